@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ToolApproval is the decision of an approver on a tool call.
+type ToolApproval struct {
+	Approved bool
+	// Feedback is given to the model with a rejection, e.g. to explain what
+	// to do instead.
+	Feedback string
+}
+
+// ToolApprover decides whether the tool call of the action is executed. The
+// executor waits for its decision, so it may ask a human. An error stops the
+// executor.
+type ToolApprover func(ctx context.Context, action schema.AgentAction) (ToolApproval, error)
+
+// ApprovalRequest is a tool call waiting for the decision of an approver of
+// NewChannelApprover.
+type ApprovalRequest struct {
+	Action schema.AgentAction
+	reply  chan ToolApproval
+}
+
+// Approve approves the tool call.
+func (r ApprovalRequest) Approve() {
+	r.Respond(ToolApproval{Approved: true})
+}
+
+// Reject rejects the tool call, giving the feedback to the model.
+func (r ApprovalRequest) Reject(feedback string) {
+	r.Respond(ToolApproval{Feedback: feedback})
+}
+
+// Respond gives the decision on the tool call. Only the first decision is
+// taken into account.
+func (r ApprovalRequest) Respond(approval ToolApproval) {
+	select {
+	case r.reply <- approval:
+	default:
+	}
+}
+
+// NewChannelApprover returns a ToolApprover sending the tool calls to approve
+// on the returned channel, and waiting for the decision given with the
+// request, e.g. by a chat UI.
+func NewChannelApprover() (ToolApprover, <-chan ApprovalRequest) {
+	requests := make(chan ApprovalRequest)
+	approver := func(ctx context.Context, action schema.AgentAction) (ToolApproval, error) {
+		request := ApprovalRequest{Action: action, reply: make(chan ToolApproval, 1)}
+		select {
+		case requests <- request:
+		case <-ctx.Done():
+			return ToolApproval{}, ctx.Err()
+		}
+		select {
+		case approval := <-request.reply:
+			return approval, nil
+		case <-ctx.Done():
+			return ToolApproval{}, ctx.Err()
+		}
+	}
+	return approver, requests
+}
+
+// requiresApproval returns whether the tool call of the action must be
+// approved.
+func (e *Executor) requiresApproval(action schema.AgentAction) bool {
+	if e.Approver == nil {
+		return false
+	}
+	if len(e.ApprovalTools) == 0 {
+		return true
+	}
+	for _, name := range e.ApprovalTools {
+		if strings.EqualFold(name, action.Tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectionObservation returns the observation given to the model for a
+// rejected tool call.
+func rejectionObservation(action schema.AgentAction, approval ToolApproval) string {
+	observation := fmt.Sprintf("The call of %s was rejected by the user.", action.Tool)
+	if approval.Feedback != "" {
+		observation += " Feedback: " + approval.Feedback
+	}
+	return observation
+}
@@ -0,0 +1,87 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type deleteTool struct {
+	deleted *[]string
+}
+
+func (deleteTool) Name() string        { return "delete_file" }
+func (deleteTool) Description() string { return "Deletes a file." }
+func (t deleteTool) Call(_ context.Context, input string) (string, error) {
+	*t.deleted = append(*t.deleted, input)
+	return "deleted " + input, nil
+}
+
+func TestExecutorToolApproval(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		fake.Response{ToolCalls: []llms.ToolCall{
+			{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "delete_file", Arguments: `{"input": "/etc/hosts"}`}}, //nolint:lll
+			{ID: "call_2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"input": "1+1"}`}},
+		}},
+		fake.Response{Content: "I didn't delete the file, and 1+1 is 2."},
+	)
+	var deleted []string
+	agentTools := []tools.Tool{deleteTool{deleted: &deleted}, tools.Calculator{}}
+	approver, requests := agents.NewChannelApprover()
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithToolApprover(approver, "delete_file"))
+
+	var approvals []schema.AgentAction
+	go func() {
+		for request := range requests {
+			approvals = append(approvals, request.Action)
+			request.Reject("don't touch system files")
+		}
+	}()
+
+	result, err := chains.Run(context.Background(), executor, "Delete /etc/hosts and compute 1+1.")
+	require.NoError(t, err)
+	require.Equal(t, "I didn't delete the file, and 1+1 is 2.", result)
+	require.Empty(t, deleted)
+
+	// Only the dangerous tool is approved, and its rejection is given to the
+	// model.
+	require.Len(t, approvals, 1)
+	require.Equal(t, "/etc/hosts", approvals[0].ToolInput)
+	messages := llm.LastCall().Messages
+	require.Equal(t, llms.ToolCallResponse{
+		ToolCallID: "call_1",
+		Name:       "delete_file",
+		Content:    "The call of delete_file was rejected by the user. Feedback: don't touch system files",
+	}, messages[len(messages)-2].Parts[0])
+	require.Equal(t, "2", messages[len(messages)-1].Parts[0].(llms.ToolCallResponse).Content)
+}
+
+func TestExecutorToolApprovalApproved(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		toolCallResponse("call_1", "delete_file", `{"input": "/tmp/scratch"}`),
+		fake.Response{Content: "Deleted."},
+	)
+	var deleted []string
+	agentTools := []tools.Tool{deleteTool{deleted: &deleted}}
+	approver := func(_ context.Context, action schema.AgentAction) (agents.ToolApproval, error) {
+		return agents.ToolApproval{Approved: action.ToolInput == "/tmp/scratch"}, nil
+	}
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithToolApprover(approver))
+
+	_, err := chains.Run(context.Background(), executor, "Delete /tmp/scratch.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/tmp/scratch"}, deleted)
+}
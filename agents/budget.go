@@ -0,0 +1,111 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrBudgetExceeded is returned when a run of an executor exceeds its budget.
+var ErrBudgetExceeded = errors.New("agent budget exceeded")
+
+// Budget limits the resources used by a run of an executor. Zero values mean
+// no limit. The tokens are those of the model calls recorded with
+// llms.RecordUsage, as done by the agents of this package.
+type Budget struct {
+	// MaxTotalTokens is the maximum number of tokens of the model calls.
+	MaxTotalTokens int
+	// MaxCost is the maximum cost of the model calls in dollars, estimated
+	// with Pricing.
+	MaxCost float64
+	// Pricing is the price of the tokens of the model.
+	Pricing llms.Pricing
+	// MaxToolCalls is the maximum number of tool calls.
+	MaxToolCalls int
+}
+
+// BudgetLimit is a limit of a Budget.
+type BudgetLimit string
+
+const (
+	BudgetTokens    BudgetLimit = "tokens"
+	BudgetCost      BudgetLimit = "cost"
+	BudgetToolCalls BudgetLimit = "tool calls"
+)
+
+// BudgetExceededError is the error of a run stopped because it exceeded a
+// limit of its budget. The run is stopped as soon as the tokens or the cost
+// are exceeded, without calling the tools of the last answer of the model,
+// and before calling tools that would exceed the tool calls. A final answer
+// is returned even if its model call exceeded the budget.
+type BudgetExceededError struct {
+	// Limit is the limit exceeded.
+	Limit BudgetLimit
+	// Usage is the token usage of the run, and Cost its estimated cost.
+	Usage llms.Usage
+	Cost  float64
+	// ToolCalls is the number of tools called in the run.
+	ToolCalls int
+	// Steps are the steps completed before the run was stopped.
+	Steps []schema.AgentStep
+}
+
+func (e *BudgetExceededError) Error() string {
+	switch e.Limit {
+	case BudgetTokens:
+		return fmt.Sprintf("%s: %d tokens used", ErrBudgetExceeded, e.Usage.TotalTokens)
+	case BudgetCost:
+		return fmt.Sprintf("%s: $%.4f spent", ErrBudgetExceeded, e.Cost)
+	default:
+		return fmt.Sprintf("%s: %d tool calls made", ErrBudgetExceeded, e.ToolCalls)
+	}
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// budgetError returns the error of the budget limit the run exceeded, if
+// any, given the tool calls it is about to make.
+func (e *Executor) budgetError(run *runState, toolCalls int) *BudgetExceededError {
+	usage := run.usage.Usage()
+	err := &BudgetExceededError{
+		Usage:     usage,
+		Cost:      e.Budget.Pricing.Cost(usage),
+		ToolCalls: run.toolCallCount(),
+	}
+	switch {
+	case e.Budget.MaxTotalTokens > 0 && err.Usage.TotalTokens >= e.Budget.MaxTotalTokens:
+		err.Limit = BudgetTokens
+	case e.Budget.MaxCost > 0 && err.Cost >= e.Budget.MaxCost:
+		err.Limit = BudgetCost
+	case e.Budget.MaxToolCalls > 0 && err.ToolCalls+toolCalls > e.Budget.MaxToolCalls:
+		err.Limit = BudgetToolCalls
+	default:
+		return nil
+	}
+	return err
+}
+
+// budgetExceeded ends the run stopped by the budget error, deleting its
+// checkpoint and returning the intermediate steps if the executor returns
+// them.
+func (e *Executor) budgetExceeded(
+	ctx context.Context,
+	err *BudgetExceededError,
+	steps []schema.AgentStep,
+) (map[string]any, error) {
+	err.Steps = steps
+	if deleteErr := e.deleteCheckpoint(ctx); deleteErr != nil {
+		return nil, deleteErr
+	}
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
+			ReturnValues: map[string]any{"output": err.Error()},
+		})
+	}
+	return e.getReturn(&schema.AgentFinish{ReturnValues: make(map[string]any)}, steps), err
+}
@@ -0,0 +1,79 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorBudget(t *testing.T) {
+	t.Parallel()
+
+	usage := llms.Usage{PromptTokens: 800, CompletionTokens: 200, TotalTokens: 1000}
+	call := func(id string) fake.Response {
+		resp := toolCallResponse(id, "calculator", `{"input": "1+1"}`)
+		resp.Usage = usage
+		return resp
+	}
+	parallelCalls := fake.Response{ToolCalls: append(call("call_2").ToolCalls, call("call_3").ToolCalls...), Usage: usage}
+
+	tests := []struct {
+		name   string
+		budget agents.Budget
+		limit  agents.BudgetLimit
+		steps  int
+		calls  int
+	}{
+		{
+			name:   "tokens",
+			budget: agents.Budget{MaxTotalTokens: 1500},
+			limit:  agents.BudgetTokens,
+			steps:  1,
+			calls:  2,
+		},
+		{
+			name:   "cost",
+			budget: agents.Budget{MaxCost: 0.0005, Pricing: llms.Pricing{PromptPerMillion: 1, CompletionPerMillion: 1}},
+			limit:  agents.BudgetCost,
+			steps:  0,
+			calls:  1,
+		},
+		{
+			name:   "tool calls",
+			budget: agents.Budget{MaxToolCalls: 2},
+			limit:  agents.BudgetToolCalls,
+			steps:  1,
+			calls:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := fake.New(call("call_1"), parallelCalls, fake.Response{Content: "2"})
+			agentTools := []tools.Tool{tools.Calculator{}}
+			executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+				agents.WithBudget(tt.budget), agents.WithReturnIntermediateSteps())
+
+			outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "Compute 1+1."})
+			require.ErrorIs(t, err, agents.ErrBudgetExceeded)
+			var budgetErr *agents.BudgetExceededError
+			require.True(t, errors.As(err, &budgetErr))
+			require.Equal(t, tt.limit, budgetErr.Limit)
+			require.Len(t, budgetErr.Steps, tt.steps)
+			require.Len(t, llm.Calls(), tt.calls)
+
+			steps, ok := outputs["intermediateSteps"].([]schema.AgentStep)
+			require.True(t, ok)
+			require.Len(t, steps, tt.steps)
+		})
+	}
+}
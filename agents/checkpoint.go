@@ -0,0 +1,395 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrCheckpointNotFound is returned by checkpoint stores when no checkpoint
+// is stored for a run.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint is the state of an interrupted run of an executor: its inputs
+// and the steps taken, i.e. the scratchpad of the agent with the tool
+// results.
+type Checkpoint struct {
+	RunID      string
+	Inputs     map[string]string
+	Steps      []schema.AgentStep
+	Iterations int
+	// ToolErrors are the failures of the tools with the ToolErrorRetry policy
+	// by tool name, SkippedTools the tools made unavailable by the
+	// ToolErrorSkip policy, and ToolCalls and Usage the tool calls and token
+	// usage counted against the budget, so that a resumed run keeps its
+	// limits.
+	ToolErrors   map[string]int
+	SkippedTools []string
+	ToolCalls    int
+	Usage        llms.Usage
+	UpdatedAt    time.Time
+}
+
+// CheckpointStore stores the checkpoints of the runs of executors.
+type CheckpointStore interface {
+	// Save saves the checkpoint, replacing the previous checkpoint of the
+	// run.
+	Save(ctx context.Context, checkpoint Checkpoint) error
+	// Load returns the checkpoint of the run, or ErrCheckpointNotFound.
+	Load(ctx context.Context, runID string) (Checkpoint, error)
+	// Delete deletes the checkpoint of the run, if any.
+	Delete(ctx context.Context, runID string) error
+}
+
+type runIDKey struct{}
+
+// ContextWithRunID returns a context identifying the run of an executor with
+// a checkpoint store. The executor saves the checkpoint of the run after each
+// iteration, resumes the run from its checkpoint if there is one, and
+// deletes the checkpoint once the run finishes. Runs ending on an error a
+// resumed run would end on again, i.e. ErrNotFinished, ErrToolRetriesExceeded
+// or a BudgetExceededError, delete their checkpoint too.
+func ContextWithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext returns the run ID of the context, if any.
+func RunIDFromContext(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDKey{}).(string)
+	return runID
+}
+
+// Resume resumes the run with the inputs of its checkpoint, e.g. after a
+// restart of the process. It returns ErrCheckpointNotFound if the executor
+// has no checkpoint of the run.
+func (e *Executor) Resume(ctx context.Context, runID string, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	if e.Checkpoints == nil {
+		return nil, ErrCheckpointNotFound
+	}
+	checkpoint, err := e.Checkpoints.Load(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make(map[string]any, len(checkpoint.Inputs))
+	for key, value := range checkpoint.Inputs {
+		inputs[key] = value
+	}
+	return chains.Call(ContextWithRunID(ctx, runID), e, inputs, options...)
+}
+
+// loadCheckpoint returns the steps and iterations of the checkpoint of the
+// run of the context, if any, and restores the state of the run.
+func (e *Executor) loadCheckpoint(ctx context.Context, run *runState) ([]schema.AgentStep, int, error) {
+	runID := RunIDFromContext(ctx)
+	if e.Checkpoints == nil || runID == "" {
+		return nil, 0, nil
+	}
+	checkpoint, err := e.Checkpoints.Load(ctx, runID)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading checkpoint: %w", err)
+	}
+	run.restore(checkpoint)
+	return checkpoint.Steps, checkpoint.Iterations, nil
+}
+
+func (e *Executor) saveCheckpoint(
+	ctx context.Context,
+	run *runState,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	iterations int,
+) error {
+	runID := RunIDFromContext(ctx)
+	if e.Checkpoints == nil || runID == "" {
+		return nil
+	}
+	checkpoint := Checkpoint{
+		RunID:      runID,
+		Inputs:     inputs,
+		Steps:      steps,
+		Iterations: iterations,
+		UpdatedAt:  time.Now(),
+	}
+	run.snapshot(&checkpoint)
+	err := e.Checkpoints.Save(ctx, checkpoint)
+	if err != nil {
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (e *Executor) deleteCheckpoint(ctx context.Context) error {
+	runID := RunIDFromContext(ctx)
+	if e.Checkpoints == nil || runID == "" {
+		return nil
+	}
+	if err := e.Checkpoints.Delete(ctx, runID); err != nil {
+		return fmt.Errorf("deleting checkpoint: %w", err)
+	}
+	return nil
+}
+
+// checkpointJSON is the JSON encoding of a checkpoint. The messages of the
+// actions are stored once, the steps referring to them by index.
+type checkpointJSON struct {
+	RunID        string            `json:"run_id"`
+	Inputs       map[string]string `json:"inputs"`
+	Steps        []stepJSON        `json:"steps"`
+	Messages     []messageJSON     `json:"messages,omitempty"`
+	Iterations   int               `json:"iterations"`
+	ToolErrors   map[string]int    `json:"tool_errors,omitempty"`
+	SkippedTools []string          `json:"skipped_tools,omitempty"`
+	ToolCalls    int               `json:"tool_calls,omitempty"`
+	Usage        *llms.Usage       `json:"usage,omitempty"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type stepJSON struct {
+	Tool        string `json:"tool,omitempty"`
+	ToolInput   string `json:"tool_input,omitempty"`
+	Log         string `json:"log,omitempty"`
+	ToolID      string `json:"tool_id,omitempty"`
+	Message     *int   `json:"message,omitempty"`
+	Observation string `json:"observation"`
+}
+
+type messageJSON struct {
+	Role      llms.ChatMessageType `json:"role"`
+	Text      []string             `json:"text,omitempty"`
+	ToolCalls []llms.ToolCall      `json:"tool_calls,omitempty"`
+}
+
+// MarshalJSON encodes the checkpoint. The messages of the actions may only
+// have text and tool call parts.
+func (c Checkpoint) MarshalJSON() ([]byte, error) {
+	encoded := checkpointJSON{
+		RunID:        c.RunID,
+		Inputs:       c.Inputs,
+		Steps:        make([]stepJSON, 0, len(c.Steps)),
+		Iterations:   c.Iterations,
+		ToolErrors:   c.ToolErrors,
+		SkippedTools: c.SkippedTools,
+		ToolCalls:    c.ToolCalls,
+		UpdatedAt:    c.UpdatedAt,
+	}
+	if c.Usage != (llms.Usage{}) {
+		encoded.Usage = &c.Usage
+	}
+	indexes := make(map[*llms.MessageContent]int)
+	for _, step := range c.Steps {
+		s := stepJSON{
+			Tool:        step.Action.Tool,
+			ToolInput:   step.Action.ToolInput,
+			Log:         step.Action.Log,
+			ToolID:      step.Action.ToolID,
+			Observation: step.Observation,
+		}
+		if msg := step.Action.Message; msg != nil {
+			i, ok := indexes[msg]
+			if !ok {
+				m, err := encodeMessage(msg)
+				if err != nil {
+					return nil, err
+				}
+				i = len(encoded.Messages)
+				indexes[msg] = i
+				encoded.Messages = append(encoded.Messages, m)
+			}
+			s.Message = &i
+		}
+		encoded.Steps = append(encoded.Steps, s)
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a checkpoint encoded by MarshalJSON. The actions of a
+// same message share it again.
+func (c *Checkpoint) UnmarshalJSON(data []byte) error {
+	var encoded checkpointJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	messages := make([]*llms.MessageContent, len(encoded.Messages))
+	for i, m := range encoded.Messages {
+		msg := &llms.MessageContent{Role: m.Role}
+		for _, text := range m.Text {
+			msg.Parts = append(msg.Parts, llms.TextPart(text))
+		}
+		for _, call := range m.ToolCalls {
+			msg.Parts = append(msg.Parts, call)
+		}
+		messages[i] = msg
+	}
+
+	steps := make([]schema.AgentStep, 0, len(encoded.Steps))
+	for _, s := range encoded.Steps {
+		step := schema.AgentStep{
+			Action: schema.AgentAction{
+				Tool:      s.Tool,
+				ToolInput: s.ToolInput,
+				Log:       s.Log,
+				ToolID:    s.ToolID,
+			},
+			Observation: s.Observation,
+		}
+		if s.Message != nil {
+			if *s.Message < 0 || *s.Message >= len(messages) {
+				return fmt.Errorf("invalid checkpoint message index %d", *s.Message)
+			}
+			step.Action.Message = messages[*s.Message]
+		}
+		steps = append(steps, step)
+	}
+
+	*c = Checkpoint{
+		RunID:        encoded.RunID,
+		Inputs:       encoded.Inputs,
+		Steps:        steps,
+		Iterations:   encoded.Iterations,
+		ToolErrors:   encoded.ToolErrors,
+		SkippedTools: encoded.SkippedTools,
+		ToolCalls:    encoded.ToolCalls,
+		UpdatedAt:    encoded.UpdatedAt,
+	}
+	if encoded.Usage != nil {
+		c.Usage = *encoded.Usage
+	}
+	return nil
+}
+
+func encodeMessage(msg *llms.MessageContent) (messageJSON, error) {
+	m := messageJSON{Role: msg.Role}
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			m.Text = append(m.Text, p.Text)
+		case llms.ToolCall:
+			m.ToolCalls = append(m.ToolCalls, p)
+		default:
+			return messageJSON{}, llms.UnsupportedContentPartError(part)
+		}
+	}
+	return m, nil
+}
+
+// InMemoryCheckpointStore is a CheckpointStore keeping the checkpoints in
+// memory, e.g. to resume runs interrupted by errors.
+type InMemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string][]byte
+}
+
+var _ CheckpointStore = &InMemoryCheckpointStore{}
+
+// NewInMemoryCheckpointStore creates a new InMemoryCheckpointStore.
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{checkpoints: make(map[string][]byte)}
+}
+
+// Save saves a copy of the checkpoint.
+func (s *InMemoryCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[checkpoint.RunID] = data
+	return nil
+}
+
+func (s *InMemoryCheckpointStore) Load(_ context.Context, runID string) (Checkpoint, error) {
+	s.mu.Lock()
+	data, ok := s.checkpoints[runID]
+	s.mu.Unlock()
+	if !ok {
+		return Checkpoint{}, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+	}
+	var checkpoint Checkpoint
+	err := json.Unmarshal(data, &checkpoint)
+	return checkpoint, err
+}
+
+func (s *InMemoryCheckpointStore) Delete(_ context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, runID)
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore keeping each checkpoint in a JSON
+// file of a directory, so that runs survive restarts of the process.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+var _ CheckpointStore = FileCheckpointStore{}
+
+// NewFileCheckpointStore creates a new FileCheckpointStore in the directory,
+// which is created if needed.
+func NewFileCheckpointStore(dir string) (FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil { //nolint:mnd
+		return FileCheckpointStore{}, err
+	}
+	return FileCheckpointStore{Dir: dir}, nil
+}
+
+func (s FileCheckpointStore) path(runID string) string {
+	return filepath.Join(s.Dir, url.PathEscape(runID)+".json")
+}
+
+// Save writes the checkpoint to a temporary file renamed over the previous
+// checkpoint, so that a crash doesn't leave a partial checkpoint.
+func (s FileCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path(checkpoint.RunID))
+}
+
+func (s FileCheckpointStore) Load(_ context.Context, runID string) (Checkpoint, error) {
+	data, err := os.ReadFile(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+	}
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	var checkpoint Checkpoint
+	err = json.Unmarshal(data, &checkpoint)
+	return checkpoint, err
+}
+
+func (s FileCheckpointStore) Delete(_ context.Context, runID string) error {
+	err := os.Remove(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
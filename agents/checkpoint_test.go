@@ -0,0 +1,204 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorResume(t *testing.T) {
+	t.Parallel()
+
+	store := agents.NewInMemoryCheckpointStore()
+	agentTools := []tools.Tool{tools.Calculator{}}
+	ctx := agents.ContextWithRunID(context.Background(), "run-1")
+	errInterrupted := errors.New("interrupted")
+
+	// The first run is interrupted after a tool call.
+	llm := fake.New(
+		toolCallResponse("call_1", "calculator", `{"input": "1+1"}`),
+		fake.Response{Err: errInterrupted},
+	)
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithCheckpointStore(store))
+	_, err := chains.Run(ctx, executor, "Compute 1+1.")
+	require.ErrorIs(t, err, errInterrupted)
+
+	checkpoint, err := store.Load(ctx, "run-1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"input": "Compute 1+1."}, checkpoint.Inputs)
+	require.Equal(t, 1, checkpoint.Iterations)
+	require.Len(t, checkpoint.Steps, 1)
+	require.Equal(t, "2", checkpoint.Steps[0].Observation)
+
+	// The resumed run continues from the stored steps instead of calling the
+	// tool again.
+	llm = fake.New(fake.Response{Content: "1+1 is 2."})
+	executor = agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithCheckpointStore(store))
+	outputs, err := executor.Resume(context.Background(), "run-1")
+	require.NoError(t, err)
+	require.Equal(t, "1+1 is 2.", outputs["output"])
+
+	messages := llm.LastCall().Messages
+	require.Contains(t, messages, llms.MessageContent{
+		Role: llms.ChatMessageTypeTool,
+		Parts: []llms.ContentPart{llms.ToolCallResponse{
+			ToolCallID: "call_1", Name: "calculator", Content: "2",
+		}},
+	})
+
+	_, err = store.Load(ctx, "run-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	_, err = executor.Resume(context.Background(), "run-2")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestExecutorResumeToolErrors(t *testing.T) {
+	t.Parallel()
+
+	store := agents.NewInMemoryCheckpointStore()
+	lookup := &countingFailingTool{}
+	agentTools := []tools.Tool{lookup}
+	ctx := agents.ContextWithRunID(context.Background(), "run-1")
+	errInterrupted := errors.New("interrupted")
+	newExecutor := func(llm *fake.LLM) *agents.Executor {
+		return agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+			agents.WithCheckpointStore(store),
+			agents.WithToolErrorPolicy(agents.ToolErrorRetry),
+			agents.WithMaxToolRetries(1))
+	}
+
+	// The first run is interrupted after a failed tool call.
+	llm := fake.New(
+		toolCallResponse("call_1", "lookup", `{"input": "population of Paris"}`),
+		fake.Response{Err: errInterrupted},
+	)
+	_, err := chains.Run(ctx, newExecutor(llm), "What is the population of Paris?")
+	require.ErrorIs(t, err, errInterrupted)
+	checkpoint, err := store.Load(ctx, "run-1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"LOOKUP": 1}, checkpoint.ToolErrors)
+	require.Equal(t, 1, checkpoint.ToolCalls)
+
+	// The resumed run keeps the retries used, and its checkpoint is deleted
+	// once they are exceeded.
+	llm = fake.New(toolCallResponse("call_2", "lookup", `{"input": "Paris population"}`))
+	_, err = newExecutor(llm).Resume(context.Background(), "run-1")
+	require.ErrorIs(t, err, agents.ErrToolRetriesExceeded)
+	require.Equal(t, int32(2), lookup.calls.Load())
+	_, err = store.Load(ctx, "run-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestExecutorResumeBudget(t *testing.T) {
+	t.Parallel()
+
+	store := agents.NewInMemoryCheckpointStore()
+	agentTools := []tools.Tool{tools.Calculator{}}
+	ctx := agents.ContextWithRunID(context.Background(), "run-1")
+	errInterrupted := errors.New("interrupted")
+	usage := llms.Usage{PromptTokens: 800, CompletionTokens: 200, TotalTokens: 1000}
+	newExecutor := func(llm *fake.LLM) *agents.Executor {
+		return agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+			agents.WithCheckpointStore(store),
+			agents.WithBudget(agents.Budget{MaxTotalTokens: 1500}))
+	}
+
+	// The first run is interrupted after a tool call.
+	call := toolCallResponse("call_1", "calculator", `{"input": "1+1"}`)
+	call.Usage = usage
+	_, err := chains.Run(ctx, newExecutor(fake.New(call, fake.Response{Err: errInterrupted})), "Compute 1+1.")
+	require.ErrorIs(t, err, errInterrupted)
+	checkpoint, err := store.Load(ctx, "run-1")
+	require.NoError(t, err)
+	require.Equal(t, usage, checkpoint.Usage)
+
+	// The resumed run counts the tokens used before it was interrupted, and
+	// is stopped before calling the tools of its first answer.
+	call = toolCallResponse("call_2", "calculator", `{"input": "2+2"}`)
+	call.Usage = usage
+	_, err = newExecutor(fake.New(call)).Resume(context.Background(), "run-1")
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetTokens, budgetErr.Limit)
+	require.Equal(t, 2000, budgetErr.Usage.TotalTokens)
+}
+
+func TestExecutorMaxIterationsDeletesCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	store := agents.NewInMemoryCheckpointStore()
+	agentTools := []tools.Tool{tools.Calculator{}}
+	ctx := agents.ContextWithRunID(context.Background(), "run-1")
+
+	llm := fake.New(toolCallResponse("call_1", "calculator", `{"input": "1+1"}`))
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithCheckpointStore(store), agents.WithMaxIterations(1))
+	_, err := chains.Run(ctx, executor, "Compute 1+1.")
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+
+	// Resuming would stop on the maximum of iterations again.
+	_, err = store.Load(ctx, "run-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	message := &llms.MessageContent{
+		Role: llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{
+			llms.TextContent{Text: "Let me compute."},
+			llms.ToolCall{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: "1+1"}},
+			llms.ToolCall{ID: "call_2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: "2+2"}},
+		},
+	}
+	checkpoint := agents.Checkpoint{
+		RunID:        "user/run 1",
+		Inputs:       map[string]string{"input": "Compute."},
+		Iterations:   1,
+		ToolErrors:   map[string]int{"LOOKUP": 1},
+		SkippedTools: []string{"SEARCH"},
+		ToolCalls:    3,
+		Usage:        llms.Usage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100},
+	}
+	checkpoint.Steps = append(checkpoint.Steps, agentStep("call_1", "1+1", "2", message), agentStep("call_2", "2+2", "4", message))
+	require.NoError(t, store.Save(ctx, checkpoint))
+
+	loaded, err := store.Load(ctx, "user/run 1")
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.Inputs, loaded.Inputs)
+	require.Equal(t, checkpoint.Iterations, loaded.Iterations)
+	require.Equal(t, checkpoint.Steps, loaded.Steps)
+	require.Equal(t, checkpoint.ToolErrors, loaded.ToolErrors)
+	require.Equal(t, checkpoint.SkippedTools, loaded.SkippedTools)
+	require.Equal(t, checkpoint.ToolCalls, loaded.ToolCalls)
+	require.Equal(t, checkpoint.Usage, loaded.Usage)
+	// The steps of a model answer keep sharing its message.
+	require.True(t, loaded.Steps[0].Action.Message == loaded.Steps[1].Action.Message)
+
+	require.NoError(t, store.Delete(ctx, "user/run 1"))
+	_, err = store.Load(ctx, "user/run 1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func agentStep(id, input, observation string, message *llms.MessageContent) schema.AgentStep {
+	return schema.AgentStep{
+		Action:      schema.AgentAction{Tool: "calculator", ToolInput: input, ToolID: id, Message: message},
+		Observation: observation,
+	}
+}
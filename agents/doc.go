@@ -18,5 +18,12 @@
 // responsible for calling the agent, getting back and action and action input,
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
-// into the Agent to get the next action it should take.
+// into the Agent to get the next action it should take. With a
+// CheckpointStore, the executor saves the steps of a run after each
+// iteration, so that an interrupted run can be resumed later with Resume.
+//
+// For longer tasks, PlanAndExecute first plans the steps with a single call
+// to the model, and then executes each step with an executor, revising the
+// steps left when one fails, and Supervisor routes the tasks of a request
+// among named worker agents, each with its own model and tools.
 package agents
@@ -0,0 +1,190 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EventType is the type of an agent event.
+type EventType string
+
+const (
+	// EventThought is the reasoning of the agent before it calls tools, in
+	// Text.
+	EventThought EventType = "thought"
+	// EventToolStart is sent before a tool is called with the ToolInput.
+	EventToolStart EventType = "tool_start"
+	// EventToolEnd is sent with the Output, or the Err, of a tool.
+	EventToolEnd EventType = "tool_end"
+	// EventFinalAnswerDelta is a chunk of the final answer, in Text.
+	EventFinalAnswerDelta EventType = "final_answer_delta"
+	// EventFinish is sent with the ReturnValues of the agent once it is done.
+	EventFinish EventType = "finish"
+)
+
+// Event is a typed event of an agent run, to show its progress live.
+type Event struct {
+	Type EventType
+	// Text is the text of thoughts and final answer deltas.
+	Text string
+	// Tool, ToolInput and ToolID identify the tool call of tool events.
+	Tool      string
+	ToolInput string
+	ToolID    string
+	// Output and Err are the result of the tool of tool end events.
+	Output string
+	Err    error
+	// ReturnValues are the return values of the agent of finish events.
+	ReturnValues map[string]any
+}
+
+// _defaultFinalAnswerKeywords are the prefixes of the final answers of the
+// text agents.
+//
+//nolint:gochecknoglobals
+var _defaultFinalAnswerKeywords = []string{_finalAnswerAction, _conversationalFinalAnswerAction}
+
+// EventHandler is a callbacks handler turning the callbacks of an agent and
+// of its executor into Events, e.g. for chat UIs. It must be the callbacks
+// handler of both the agent, to stream the final answer, and the executor:
+//
+//	handler := agents.NewEventHandler(func(ctx context.Context, event agents.Event) { ... })
+//	agent := agents.NewOneShotAgent(llm, tools, agents.WithCallbacksHandler(handler))
+//	executor := agents.NewExecutor(agent, tools, agents.WithCallbacksHandler(handler))
+//
+// The text streamed by the model after one of the Keywords is sent as final
+// answer deltas. When the final answer was not streamed, e.g. for agents
+// using tool calling, it is sent as a single delta when the agent finishes.
+type EventHandler struct {
+	callbacks.SimpleHandler
+
+	// Keywords are the prefixes of the final answer in the text streamed by
+	// the model. Defaults to "Final Answer:" and "AI:".
+	Keywords []string
+	// OutputKey is the key of the final answer in the return values of the
+	// agent, by default "output".
+	OutputKey string
+
+	handle func(ctx context.Context, event Event)
+
+	mu            sync.Mutex
+	text          string
+	streaming     bool
+	lastThought   string
+	closeChannels func()
+}
+
+var (
+	_ callbacks.Handler          = &EventHandler{}
+	_ callbacks.AgentToolHandler = &EventHandler{}
+)
+
+// NewEventHandler creates a new EventHandler calling handle with each event.
+func NewEventHandler(handle func(ctx context.Context, event Event)) *EventHandler {
+	return &EventHandler{
+		Keywords:  _defaultFinalAnswerKeywords,
+		OutputKey: _defaultOutputKey,
+		handle:    handle,
+	}
+}
+
+// NewEventChannel creates a new EventHandler sending the events on the
+// returned channel, with the buffer size. The channel is closed by Close,
+// which must be called once the run of the executor returned.
+func NewEventChannel(size int) (*EventHandler, <-chan Event) {
+	events := make(chan Event, size)
+	h := NewEventHandler(func(ctx context.Context, event Event) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	})
+	var once sync.Once
+	h.closeChannels = func() { once.Do(func() { close(events) }) }
+	return h, events
+}
+
+// Close closes the channel of the events of handlers created with
+// NewEventChannel.
+func (h *EventHandler) Close() {
+	if h.closeChannels != nil {
+		h.closeChannels()
+	}
+}
+
+// HandleStreamingFunc sends the chunks of the final answer as final answer
+// deltas.
+func (h *EventHandler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	h.mu.Lock()
+	var delta string
+	if h.streaming {
+		delta = string(chunk)
+	} else {
+		h.text += string(chunk)
+		for _, keyword := range h.Keywords {
+			if i := strings.Index(h.text, keyword); i >= 0 {
+				h.streaming = true
+				delta = strings.TrimLeft(h.text[i+len(keyword):], " ")
+				break
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	if delta != "" {
+		h.handle(ctx, Event{Type: EventFinalAnswerDelta, Text: delta})
+	}
+}
+
+// HandleAgentAction sends the thought of the action, once for the actions of
+// a same model answer.
+func (h *EventHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	h.mu.Lock()
+	h.text, h.streaming = "", false
+	thought := strings.TrimSpace(action.Log)
+	isNew := thought != h.lastThought
+	h.lastThought = thought
+	h.mu.Unlock()
+
+	if isNew && thought != "" {
+		h.handle(ctx, Event{Type: EventThought, Text: thought})
+	}
+}
+
+func (h *EventHandler) HandleAgentToolStart(ctx context.Context, action schema.AgentAction) {
+	h.handle(ctx, Event{
+		Type:      EventToolStart,
+		Tool:      action.Tool,
+		ToolInput: action.ToolInput,
+		ToolID:    action.ToolID,
+	})
+}
+
+func (h *EventHandler) HandleAgentToolEnd(ctx context.Context, action schema.AgentAction, output string, err error) {
+	h.handle(ctx, Event{
+		Type:      EventToolEnd,
+		Tool:      action.Tool,
+		ToolInput: action.ToolInput,
+		ToolID:    action.ToolID,
+		Output:    output,
+		Err:       err,
+	})
+}
+
+// HandleAgentFinish sends the final answer if it wasn't streamed, and the
+// finish event.
+func (h *EventHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	h.mu.Lock()
+	streamed := h.streaming
+	h.text, h.streaming, h.lastThought = "", false, ""
+	h.mu.Unlock()
+
+	if output, ok := finish.ReturnValues[h.OutputKey].(string); ok && !streamed && output != "" {
+		h.handle(ctx, Event{Type: EventFinalAnswerDelta, Text: strings.TrimSpace(output)})
+	}
+	h.handle(ctx, Event{Type: EventFinish, ReturnValues: finish.ReturnValues})
+}
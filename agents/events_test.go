@@ -0,0 +1,88 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestEventChannel(t *testing.T) {
+	t.Parallel()
+
+	action := "I need to compute it.\nAction: calculator\nAction Input: 2*21"
+	llm := fake.New(
+		fake.Response{Content: action, Chunks: []string{"I need to ", "compute it.\nAction: calculator\nAction Input: 2*21"}},
+		fake.Response{
+			Content: "I now know the final answer\nFinal Answer: The answer is 42.",
+			Chunks:  []string{"I now know the final answer\nFinal An", "swer: The answer", " is 42."},
+		},
+	)
+	handler, events := agents.NewEventChannel(0)
+	agentTools := []tools.Tool{tools.Calculator{}}
+	agent := agents.NewOneShotAgent(llm, agentTools, agents.WithCallbacksHandler(handler))
+	executor := agents.NewExecutor(agent, agentTools, agents.WithCallbacksHandler(handler))
+
+	errs := make(chan error, 1)
+	go func() {
+		defer handler.Close()
+		_, err := chains.Run(context.Background(), executor, "What is 2*21?")
+		errs <- err
+	}()
+
+	var got []agents.Event
+	for event := range events {
+		got = append(got, event)
+	}
+	require.NoError(t, <-errs)
+	require.Equal(t, []agents.Event{
+		{Type: agents.EventThought, Text: action},
+		{Type: agents.EventToolStart, Tool: "calculator", ToolInput: "2*21"},
+		{Type: agents.EventToolEnd, Tool: "calculator", ToolInput: "2*21", Output: "42"},
+		{Type: agents.EventFinalAnswerDelta, Text: "The answer"},
+		{Type: agents.EventFinalAnswerDelta, Text: " is 42."},
+		{Type: agents.EventFinish, ReturnValues: map[string]any{"output": " The answer is 42."}},
+	}, got)
+}
+
+func TestEventHandlerToolCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		toolCallResponse("call_1", "calculator", `{"input": "2*21"}`),
+		fake.Response{Content: "The answer is 42."},
+	)
+	var got []agents.Event
+	handler := agents.NewEventHandler(func(_ context.Context, event agents.Event) {
+		got = append(got, event)
+	})
+	agentTools := []tools.Tool{tools.Calculator{}}
+	agent := agents.NewToolCallingAgent(llm, agentTools, agents.WithCallbacksHandler(handler))
+	executor := agents.NewExecutor(agent, agentTools, agents.WithCallbacksHandler(handler))
+
+	_, err := chains.Run(context.Background(), executor, "What is 2*21?")
+	require.NoError(t, err)
+
+	// Without keyword, the final answer is sent once the agent finishes.
+	require.Equal(t, []agents.EventType{
+		agents.EventThought,
+		agents.EventToolStart,
+		agents.EventToolEnd,
+		agents.EventFinalAnswerDelta,
+		agents.EventFinish,
+	}, eventTypes(got))
+	require.Equal(t, "call_1", got[1].ToolID)
+	require.Equal(t, "The answer is 42.", got[3].Text)
+}
+
+func eventTypes(events []agents.Event) []agents.EventType {
+	types := make([]agents.EventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
 )
@@ -24,6 +27,41 @@ type Executor struct {
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// MaxParallelToolCalls is the maximum number of tools called concurrently
+	// when the agent returns several actions at once, e.g. the tool calls of
+	// a model answer. 1 calls them sequentially, and 0 doesn't limit them.
+	MaxParallelToolCalls int
+
+	// Approver, if set, decides whether the tools in ApprovalTools, or all
+	// the tools if it is empty, are called. Rejected calls are not executed,
+	// the rejection being given to the agent as the observation.
+	Approver      ToolApprover
+	ApprovalTools []string
+
+	// Timeout is the maximum duration of a run, LLMCallTimeout of each call
+	// of the agent to its model, and ToolTimeout of each tool call. Zero
+	// means no timeout. When a timeout expires, the run fails with a
+	// TimeoutError.
+	Timeout        time.Duration
+	LLMCallTimeout time.Duration
+	ToolTimeout    time.Duration
+
+	// Checkpoints, if set, stores the steps of the runs identified by
+	// ContextWithRunID after each iteration, so that they can be resumed, e.g.
+	// with Resume after a restart.
+	Checkpoints CheckpointStore
+
+	// ToolErrorPolicies are the policies of the errors of the tools by name,
+	// the others using DefaultToolErrorPolicy, ToolErrorAbort if empty.
+	// MaxToolRetries is the number of times a tool with the ToolErrorRetry
+	// policy can fail in a run before the run is aborted.
+	ToolErrorPolicies      map[string]ToolErrorPolicy
+	DefaultToolErrorPolicy ToolErrorPolicy
+	MaxToolRetries         int
+
+	// Budget limits the tokens, the estimated cost and the tool calls of a
+	// run. A run exceeding it ends with a BudgetExceededError.
+	Budget Budget
 }
 
 var (
@@ -46,6 +84,17 @@ func NewExecutor(agent Agent, tools []tools.Tool, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		MaxParallelToolCalls:    options.maxParallelToolCalls,
+		Approver:                options.approver,
+		ApprovalTools:           options.approvalTools,
+		Timeout:                 options.timeout,
+		LLMCallTimeout:          options.llmCallTimeout,
+		ToolTimeout:             options.toolTimeout,
+		Checkpoints:             options.checkpoints,
+		ToolErrorPolicies:       options.toolErrorPolicies,
+		DefaultToolErrorPolicy:  options.defaultToolErrorPolicy,
+		MaxToolRetries:          options.maxToolRetries,
+		Budget:                  options.budget,
 	}
 }
 
@@ -55,14 +104,52 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 		return nil, err
 	}
 	nameToTool := getNameToTool(e.Tools)
+	run := newRunState()
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	runCtx, cancel := withTimeout(ctx, e.Timeout)
+	defer cancel()
+	if e.Budget != (Budget{}) {
+		runCtx = llms.ContextWithUsageTracker(runCtx, run.usage)
+	}
+
+	steps, start, err := e.loadCheckpoint(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if steps == nil {
+		steps = make([]schema.AgentStep, 0)
+	}
+	for i := start; i < e.MaxIterations; i++ {
+		if budgetErr := e.budgetError(run, 0); budgetErr != nil {
+			return e.budgetExceeded(ctx, budgetErr, steps)
+		}
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
-		if finish != nil || err != nil {
-			return finish, err
+		steps, finish, err = e.doIteration(runCtx, run, steps, nameToTool, inputs)
+		var budgetErr *BudgetExceededError
+		if errors.As(err, &budgetErr) {
+			return e.budgetExceeded(ctx, budgetErr, steps)
+		}
+		if errors.Is(err, ErrToolRetriesExceeded) {
+			if deleteErr := e.deleteCheckpoint(ctx); deleteErr != nil {
+				return nil, deleteErr
+			}
 		}
+		if err != nil {
+			return nil, e.timeoutError(ctx, runCtx, err, steps)
+		}
+		if finish != nil {
+			if err := e.deleteCheckpoint(ctx); err != nil {
+				return nil, err
+			}
+			return finish, nil
+		}
+		if err := e.saveCheckpoint(ctx, run, inputs, steps, i+1); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.deleteCheckpoint(ctx); err != nil {
+		return nil, err
 	}
 
 	if e.CallbacksHandler != nil {
@@ -78,11 +165,17 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 
 func (e *Executor) doIteration( // nolint
 	ctx context.Context,
+	run *runState,
 	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	inputs map[string]string,
 ) ([]schema.AgentStep, map[string]any, error) {
-	actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
+	planCtx, cancel := withTimeout(ctx, e.LLMCallTimeout)
+	actions, finish, err := e.Agent.Plan(planCtx, steps, inputs)
+	if err != nil && timedOut(ctx, planCtx, e.LLMCallTimeout) {
+		err = &TimeoutError{Stage: TimeoutLLMCall, Timeout: e.LLMCallTimeout, Err: err}
+	}
+	cancel()
 	if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
 		formattedObservation := err.Error()
 		if e.ErrorHandler.Formatter != nil {
@@ -108,43 +201,161 @@ func (e *Executor) doIteration( // nolint
 		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
-		if err != nil {
-			return steps, nil, err
-		}
+	if budgetErr := e.budgetError(run, len(actions)); budgetErr != nil {
+		return steps, nil, budgetErr
+	}
+	run.addToolCalls(len(actions))
+
+	actionSteps, err := e.doActions(ctx, run, nameToTool, actions)
+	if err != nil {
+		return steps, nil, err
 	}
 
-	return steps, nil, nil
+	return append(steps, actionSteps...), nil, nil
 }
 
-func (e *Executor) doAction(
+// doActions calls the tools of the actions, concurrently up to
+// MaxParallelToolCalls, and returns their steps in the order of the actions.
+// If a tool fails, the context of the others is canceled and the error of the
+// first failing action is returned.
+func (e *Executor) doActions(
 	ctx context.Context,
-	steps []schema.AgentStep,
+	run *runState,
 	nameToTool map[string]tools.Tool,
-	action schema.AgentAction,
+	actions []schema.AgentAction,
 ) ([]schema.AgentStep, error) {
 	if e.CallbacksHandler != nil {
-		e.CallbacksHandler.HandleAgentAction(ctx, action)
+		for _, action := range actions {
+			e.CallbacksHandler.HandleAgentAction(ctx, action)
+		}
+	}
+
+	steps := make([]schema.AgentStep, len(actions))
+	if len(actions) == 1 || e.MaxParallelToolCalls == 1 {
+		for i, action := range actions {
+			step, err := e.doAction(ctx, run, nameToTool, action)
+			if err != nil {
+				return nil, err
+			}
+			steps[i] = step
+		}
+		return steps, nil
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := e.MaxParallelToolCalls
+	if limit <= 0 {
+		limit = len(actions)
+	}
+	sem := make(chan struct{}, limit)
+	errs := make([]error, len(actions))
+	var wg sync.WaitGroup
+	for i, action := range actions {
+		wg.Add(1)
+		go func(i int, action schema.AgentAction) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			steps[i], errs[i] = e.doAction(ctx, run, nameToTool, action)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, action)
+	}
+	wg.Wait()
+
+	// Return the error of the first failing action rather than the context
+	// errors of the actions it canceled.
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && (firstErr == nil || (errors.Is(firstErr, context.Canceled) && !errors.Is(err, context.Canceled))) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return steps, nil
+}
+
+func (e *Executor) doAction(
+	ctx context.Context,
+	run *runState,
+	nameToTool map[string]tools.Tool,
+	action schema.AgentAction,
+) (schema.AgentStep, error) {
 	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
+	if ok && run.isSkipped(action.Tool) {
+		return skippedToolStep(action), nil
+	}
+	if ok && e.requiresApproval(action) {
+		approval, err := e.Approver(ctx, action)
+		if err != nil {
+			return schema.AgentStep{}, fmt.Errorf("approval of %s: %w", action.Tool, err)
+		}
+		if !approval.Approved {
+			return schema.AgentStep{
+				Action:      action,
+				Observation: rejectionObservation(action, approval),
+			}, nil
+		}
+	}
+
+	toolHandler, _ := e.CallbacksHandler.(callbacks.AgentToolHandler)
+	if toolHandler != nil {
+		toolHandler.HandleAgentToolStart(ctx, action)
+	}
+
 	if !ok {
-		return append(steps, schema.AgentStep{
+		observation := fmt.Sprintf("%s is not a valid tool, try another one", action.Tool)
+		if toolHandler != nil {
+			toolHandler.HandleAgentToolEnd(ctx, action, observation, nil)
+		}
+		return schema.AgentStep{
 			Action:      action,
-			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-		}), nil
+			Observation: observation,
+		}, nil
 	}
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	toolCtx, cancel := withTimeout(ctx, e.ToolTimeout)
+	defer cancel()
+	observation, err := tool.Call(toolCtx, action.ToolInput)
+	if err != nil && timedOut(ctx, toolCtx, e.ToolTimeout) {
+		err = &TimeoutError{Stage: TimeoutTool, Tool: action.Tool, Timeout: e.ToolTimeout, Err: err}
+	}
+	if toolHandler != nil {
+		toolHandler.HandleAgentToolEnd(ctx, action, observation, err)
+	}
 	if err != nil {
-		return nil, err
+		return e.handleToolError(ctx, run, action, err)
 	}
 
-	return append(steps, schema.AgentStep{
+	return schema.AgentStep{
 		Action:      action,
 		Observation: observation,
-	}), nil
+	}, nil
+}
+
+// timeoutError returns the error of a run, as a TimeoutError with the steps
+// completed if a timeout expired.
+func (e *Executor) timeoutError(ctx, runCtx context.Context, err error, steps []schema.AgentStep) error {
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		if !timedOut(ctx, runCtx, e.Timeout) {
+			return err
+		}
+		timeoutErr = &TimeoutError{Stage: TimeoutRun, Timeout: e.Timeout, Err: err}
+		err = timeoutErr
+	}
+	timeoutErr.Steps = steps
+	return err
 }
 
 func (e *Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
@@ -4,11 +4,15 @@ import (
 	"context"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/agents"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -70,6 +74,66 @@ func TestExecutorWithErrorHandler(t *testing.T) {
 	}, a.recordedIntermediateSteps)
 }
 
+type concurrencyTool struct {
+	running, peak *atomic.Int32
+}
+
+func (concurrencyTool) Name() string        { return "lookup" }
+func (concurrencyTool) Description() string { return "Looks up facts." }
+func (t concurrencyTool) Call(_ context.Context, input string) (string, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		peak := t.peak.Load()
+		if n <= peak || t.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return "fact about " + input, nil
+}
+
+func TestExecutorParallelToolCalls(t *testing.T) {
+	t.Parallel()
+
+	for _, maxParallel := range []int{1, 2, 0} {
+		var toolCalls []llms.ToolCall
+		for _, id := range []string{"a", "b", "c", "d"} {
+			toolCalls = append(toolCalls, llms.ToolCall{
+				ID:           id,
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"input": "` + id + `"}`},
+			})
+		}
+		llm := fake.New(fake.Response{ToolCalls: toolCalls}, fake.Response{Content: "done"})
+		tool := concurrencyTool{running: &atomic.Int32{}, peak: &atomic.Int32{}}
+		agentTools := []tools.Tool{tool}
+		executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+			agents.WithMaxParallelToolCalls(maxParallel), agents.WithReturnIntermediateSteps())
+
+		result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Look up a, b, c and d."})
+		require.NoError(t, err)
+
+		// The steps are in the order of the tool calls.
+		steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+		require.True(t, ok)
+		require.Len(t, steps, 4)
+		for i, id := range []string{"a", "b", "c", "d"} {
+			require.Equal(t, id, steps[i].Action.ToolID)
+			require.Equal(t, "fact about "+id, steps[i].Observation)
+		}
+
+		limit := int32(maxParallel)
+		if limit == 0 {
+			limit = 4
+		}
+		require.LessOrEqual(t, tool.peak.Load(), limit)
+		if maxParallel == 1 {
+			require.Equal(t, int32(1), tool.peak.Load())
+		}
+	}
+}
+
 func TestExecutorWithMRKLAgent(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,88 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// FinalAnswerToolName is the name of the tool a ToolCallingAgent with a
+// final answer schema calls to give its final answer.
+const FinalAnswerToolName = "final_answer"
+
+const _finalAnswerToolDescription = "Gives the final answer to the user. " +
+	"Call it once you know the answer instead of answering with text."
+
+// WithFinalAnswer is an option for making a ToolCallingAgent give its final
+// answer as a JSON value of type T, by calling the final_answer tool with
+// the schema of T. Use RunStructured to get the answer as a T.
+func WithFinalAnswer[T any]() Option {
+	return WithFinalAnswerSchema(jsonschema.For[T]())
+}
+
+// WithFinalAnswerSchema is an option for making a ToolCallingAgent give its
+// final answer as a JSON value matching the definition, by calling the
+// final_answer tool.
+func WithFinalAnswerSchema(definition jsonschema.Definition) Option {
+	return func(co *Options) {
+		co.finalAnswerSchema = &definition
+	}
+}
+
+// RunStructured runs the chain, typically an executor of a ToolCallingAgent
+// created with WithFinalAnswer[T], with a single input and decodes its JSON
+// output into a T.
+func RunStructured[T any](ctx context.Context, c chains.Chain, input string, options ...chains.ChainCallOption) (T, error) { //nolint:lll
+	var answer T
+	output, err := chains.Run(ctx, c, input, options...)
+	if err != nil {
+		return answer, err
+	}
+	if err := json.Unmarshal([]byte(output), &answer); err != nil {
+		return answer, fmt.Errorf("%w: decoding final answer: %w", ErrUnableToParseOutput, err)
+	}
+	return answer, nil
+}
+
+// finalAnswerTool returns the definition of the final_answer tool.
+func (a *ToolCallingAgent) finalAnswerTool() llms.Tool {
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        FinalAnswerToolName,
+			Description: _finalAnswerToolDescription,
+			Parameters:  *a.FinalAnswerSchema,
+		},
+	}
+}
+
+// finalAnswerCall returns the final_answer call of the tool calls, if any.
+func finalAnswerCall(calls []llms.ToolCall) *llms.FunctionCall {
+	for _, call := range calls {
+		if call.FunctionCall != nil && call.FunctionCall.Name == FinalAnswerToolName {
+			return call.FunctionCall
+		}
+	}
+	return nil
+}
+
+// parseFinalAnswer returns the finish of the final_answer call, or a parsing
+// error, given back to the model by the executor if it has a parser error
+// handler, if its arguments don't match the schema.
+func (a *ToolCallingAgent) parseFinalAnswer(
+	call *llms.FunctionCall,
+	content string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if err := a.FinalAnswerSchema.Validate([]byte(call.Arguments)); err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid final answer: %w", ErrUnableToParseOutput, err)
+	}
+	return nil, &schema.AgentFinish{
+		ReturnValues: map[string]any{a.OutputKey: call.Arguments},
+		Log:          content,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type cityWeather struct {
+	City        string `json:"city"`
+	Temperature int    `json:"temperature" description:"The temperature in Celsius"`
+}
+
+func TestToolCallingAgentFinalAnswer(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		toolCallResponse("call_1", "calculator", `{"input": "50-32"}`),
+		toolCallResponse("call_2", agents.FinalAnswerToolName, `{"city": "Paris"}`),
+		fake.Response{Content: "It is 10 degrees in Paris."},
+		toolCallResponse("call_3", agents.FinalAnswerToolName, `{"city": "Paris", "temperature": 10}`),
+	)
+	agentTools := []tools.Tool{tools.Calculator{}}
+	agent := agents.NewToolCallingAgent(llm, agentTools, agents.WithFinalAnswer[cityWeather]())
+	executor := agents.NewExecutor(agent, agentTools, agents.WithParserErrorHandler(agents.NewParserErrorHandler(nil)))
+
+	answer, err := agents.RunStructured[cityWeather](context.Background(), executor, "What's the weather in Paris?")
+	require.NoError(t, err)
+	require.Equal(t, cityWeather{City: "Paris", Temperature: 10}, answer)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 4)
+	definitions := calls[0].Options.Tools
+	require.Len(t, definitions, 2)
+	require.Equal(t, agents.FinalAnswerToolName, definitions[1].Function.Name)
+
+	// The invalid answers are given back to the model.
+	messages := calls[3].Messages
+	require.Equal(t, llms.TextParts(llms.ChatMessageTypeHuman,
+		`unable to parse agent output: invalid final answer: value doesn't match the schema: $: missing required property "temperature"`), //nolint:lll
+		messages[len(messages)-2])
+	require.Equal(t, llms.TextParts(llms.ChatMessageTypeHuman,
+		"unable to parse agent output: the final answer must be given by calling final_answer"),
+		messages[len(messages)-1])
+}
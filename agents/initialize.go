@@ -5,7 +5,11 @@ import (
 	"github.com/tmc/langchaingo/tools"
 )
 
-const _defaultMaxIterations = 5
+const (
+	_defaultMaxIterations        = 5
+	_defaultMaxParallelToolCalls = 4
+	_defaultMaxToolRetries       = 2
+)
 
 // AgentType is a string type representing the type of agent to create.
 type AgentType string
@@ -17,6 +21,13 @@ const (
 	// ConversationalReactDescription is an AgentType constant that represents
 	// the "conversationalReactDescription" agent type.
 	ConversationalReactDescription AgentType = "conversationalReactDescription"
+	// ToolCalling is an AgentType constant that represents the "toolCalling"
+	// agent type, using the native tool calling of the model.
+	ToolCalling AgentType = "toolCalling"
+	// StructuredChatZeroShotReactDescription is an AgentType constant that
+	// represents the "structuredChatZeroShotReactDescription" agent type,
+	// using tools with several arguments.
+	StructuredChatZeroShotReactDescription AgentType = "structuredChatZeroShotReactDescription"
 )
 
 // Deprecated: This may be removed in the future; please use NewExecutor instead.
@@ -35,6 +46,10 @@ func Initialize(
 		agent = NewOneShotAgent(llm, tools, opts...)
 	case ConversationalReactDescription:
 		agent = NewConversationalAgent(llm, tools, opts...)
+	case ToolCalling:
+		agent = NewToolCallingAgent(llm, tools, opts...)
+	case StructuredChatZeroShotReactDescription:
+		agent = NewStructuredChatAgent(llm, tools, opts...)
 	default:
 		return &Executor{}, ErrUnknownAgentType
 	}
@@ -134,6 +134,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 	if err != nil {
 		return nil, nil, err
 	}
+	llms.RecordUsage(ctx, result)
 
 	return o.ParseOutput(result)
 }
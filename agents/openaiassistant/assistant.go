@@ -0,0 +1,302 @@
+package openaiassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_inputKey           = "input"
+	_toolInputParameter = "input"
+	_cancelTimeout      = 5 * time.Second
+)
+
+var (
+	// ErrMissingToken is returned by New when no API key is set.
+	ErrMissingToken = errors.New("missing the OpenAI API key, set it in the OPENAI_API_KEY environment variable") //nolint:lll
+	// ErrRunFailed is returned when a run of the assistant fails, is
+	// canceled, expires or is incomplete.
+	ErrRunFailed = errors.New("assistant run failed")
+	// ErrNoPendingRun is returned when the agent is given tool results while
+	// no run waits for them.
+	ErrNoPendingRun = errors.New("no run waiting for tool outputs")
+)
+
+// Agent is an agents.Agent backed by an assistant of the OpenAI Assistants
+// API, used with an agents.Executor. The first Plan of an executor call adds
+// the input to the thread of the agent and starts a run of the assistant.
+// The calls of the functions of the local tools are returned as actions, and
+// their results are submitted to the run by the next Plan, until the run
+// completes with the answer of the assistant.
+//
+// The thread is kept between calls, so that the assistant remembers the
+// conversation. An Agent runs one conversation at a time.
+type Agent struct {
+	AssistantID string
+	// Tools are the local tools given to the runs as functions.
+	Tools []tools.Tool
+	// HostedTools are the hosted tools of the runs, e.g. "code_interpreter".
+	HostedTools []string
+	// Instructions are appended to the instructions of the assistant.
+	Instructions string
+	PollInterval time.Duration
+	OutputKey    string
+
+	client *client
+
+	mu       sync.Mutex
+	threadID string
+	runID    string
+	pending  []toolCall
+}
+
+var _ agents.Agent = (*Agent)(nil)
+
+// New creates a new Agent for the assistant, with the local tools.
+func New(assistantID string, tools []tools.Tool, opts ...Option) (*Agent, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.token == "" {
+		return nil, ErrMissingToken
+	}
+
+	return &Agent{
+		AssistantID:  assistantID,
+		Tools:        tools,
+		HostedTools:  options.hostedTools,
+		Instructions: options.instructions,
+		PollInterval: options.pollInterval,
+		OutputKey:    options.outputKey,
+		client: &client{
+			token:        options.token,
+			baseURL:      options.baseURL,
+			organization: options.organization,
+			httpClient:   options.httpClient,
+		},
+		threadID: options.threadID,
+	}, nil
+}
+
+// ThreadID returns the ID of the thread of the conversation, empty before the
+// first run.
+func (a *Agent) ThreadID() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.threadID
+}
+
+// ResetThread starts a new conversation at the next run.
+func (a *Agent) ResetThread() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.threadID, a.runID, a.pending = "", "", nil
+}
+
+// Plan starts a run with the input, or submits the results of the tool calls
+// of the steps to the pending run, and waits for the next tool calls or the
+// answer of the assistant.
+func (a *Agent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var r run
+	var err error
+	if len(intermediateSteps) == 0 {
+		r, err = a.startRun(ctx, inputs[_inputKey])
+	} else {
+		r, err = a.submitToolOutputs(ctx, intermediateSteps)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err = a.wait(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.parseRun(ctx, r)
+}
+
+func (a *Agent) startRun(ctx context.Context, input string) (run, error) {
+	if a.threadID == "" {
+		t, err := a.client.createThread(ctx, input)
+		if err != nil {
+			return run{}, fmt.Errorf("creating thread: %w", err)
+		}
+		a.threadID = t.ID
+	} else if err := a.client.createMessage(ctx, a.threadID, input); err != nil {
+		return run{}, fmt.Errorf("adding message: %w", err)
+	}
+
+	r, err := a.client.createRun(ctx, a.threadID, runRequest{
+		AssistantID:            a.AssistantID,
+		AdditionalInstructions: a.Instructions,
+		Tools:                  a.toolDefinitions(),
+	})
+	if err != nil {
+		return run{}, fmt.Errorf("creating run: %w", err)
+	}
+	a.runID, a.pending = r.ID, nil
+	return r, nil
+}
+
+// submitToolOutputs submits the observations of the steps of the pending tool
+// calls to the run.
+func (a *Agent) submitToolOutputs(ctx context.Context, steps []schema.AgentStep) (run, error) {
+	if a.runID == "" || len(a.pending) == 0 {
+		return run{}, ErrNoPendingRun
+	}
+	observations := make(map[string]string, len(steps))
+	for _, step := range steps {
+		if step.Action.ToolID != "" {
+			observations[step.Action.ToolID] = step.Observation
+		}
+	}
+	outputs := make([]toolOutput, 0, len(a.pending))
+	for _, call := range a.pending {
+		outputs = append(outputs, toolOutput{ToolCallID: call.ID, Output: observations[call.ID]})
+	}
+
+	r, err := a.client.submitToolOutputs(ctx, a.threadID, a.runID, outputs)
+	if err != nil {
+		return run{}, fmt.Errorf("submitting tool outputs: %w", err)
+	}
+	a.pending = nil
+	return r, nil
+}
+
+// wait polls the run until it requires tool outputs or ends. The run is
+// canceled if the context is done.
+func (a *Agent) wait(ctx context.Context, r run) (run, error) {
+	for r.Status == "queued" || r.Status == "in_progress" || r.Status == "cancelling" {
+		select {
+		case <-ctx.Done():
+			cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _cancelTimeout)
+			defer cancel()
+			_ = a.client.cancelRun(cancelCtx, a.threadID, r.ID)
+			a.runID = ""
+			return run{}, ctx.Err()
+		case <-time.After(a.PollInterval):
+		}
+
+		var err error
+		r, err = a.client.getRun(ctx, a.threadID, r.ID)
+		if err != nil {
+			return run{}, fmt.Errorf("getting run: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func (a *Agent) parseRun(ctx context.Context, r run) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	switch r.Status {
+	case "requires_action":
+		if r.RequiredAction == nil || len(r.RequiredAction.SubmitToolOutputs.ToolCalls) == 0 {
+			return nil, nil, fmt.Errorf("%w: run requires an action without tool calls", agents.ErrUnableToParseOutput)
+		}
+		a.pending = r.RequiredAction.SubmitToolOutputs.ToolCalls
+		actions := make([]schema.AgentAction, 0, len(a.pending))
+		for _, call := range a.pending {
+			actions = append(actions, schema.AgentAction{
+				Tool:      call.Function.Name,
+				ToolInput: a.toolInput(call.Function.Name, call.Function.Arguments),
+				Log:       fmt.Sprintf("Invoking: %s with %s\n", call.Function.Name, call.Function.Arguments),
+				ToolID:    call.ID,
+			})
+		}
+		return actions, nil, nil
+	case "completed":
+		a.runID = ""
+		output, err := a.client.runOutput(ctx, a.threadID, r.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting run output: %w", err)
+		}
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: output},
+			Log:          output,
+		}, nil
+	default:
+		a.runID = ""
+		reason := r.Status
+		if r.LastError != nil {
+			reason += fmt.Sprintf(": %s: %s", r.LastError.Code, r.LastError.Message)
+		}
+		if r.IncompleteDetails != nil {
+			reason += ": " + r.IncompleteDetails.Reason
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrRunFailed, reason)
+	}
+}
+
+// toolDefinitions returns the hosted tools and the functions of the local
+// tools of the runs, or nil to use the tools of the assistant.
+func (a *Agent) toolDefinitions() []toolDefinition {
+	definitions := make([]toolDefinition, 0, len(a.HostedTools)+len(a.Tools))
+	for _, hosted := range a.HostedTools {
+		definitions = append(definitions, toolDefinition{Type: hosted})
+	}
+	for _, tool := range a.Tools {
+		var parameters any = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				_toolInputParameter: map[string]any{"type": "string", "description": "The input of the tool."},
+			},
+			"required": []string{_toolInputParameter},
+		}
+		if structured, ok := tool.(tools.StructuredTool); ok {
+			parameters = structured.Parameters()
+		}
+		definitions = append(definitions, toolDefinition{
+			Type: "function",
+			Function: &functionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  parameters,
+			},
+		})
+	}
+	if len(definitions) == 0 {
+		return nil
+	}
+	return definitions
+}
+
+// toolInput returns the input of the tool called: the JSON arguments for
+// structured tools, and the input parameter for others.
+func (a *Agent) toolInput(name, arguments string) string {
+	for _, tool := range a.Tools {
+		if _, ok := tool.(tools.StructuredTool); ok && tool.Name() == name {
+			return arguments
+		}
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(arguments), &values); err != nil {
+		return arguments
+	}
+	if input, ok := values[_toolInputParameter].(string); ok {
+		return input
+	}
+	return arguments
+}
+
+func (a *Agent) GetInputKeys() []string {
+	return []string{_inputKey}
+}
+
+func (a *Agent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
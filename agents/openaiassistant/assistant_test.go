@@ -0,0 +1,121 @@
+package openaiassistant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// fakeAssistantServer serves the responses of the routes, in order for the
+// routes with several responses.
+type fakeAssistantServer struct {
+	mu        sync.Mutex
+	responses map[string][]string
+	requests  map[string][]map[string]any
+}
+
+func (s *fakeAssistantServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	route := r.Method + " " + r.URL.Path
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.requests[route] = append(s.requests[route], body)
+
+	responses := s.responses[route]
+	if len(responses) == 0 {
+		http.Error(w, `{"error": {"message": "unexpected request", "type": "invalid_request_error"}}`, http.StatusNotFound)
+		return
+	}
+	if len(responses) > 1 {
+		s.responses[route] = responses[1:]
+	}
+	_, _ = w.Write([]byte(responses[0]))
+}
+
+func TestAgent(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeAssistantServer{
+		requests: map[string][]map[string]any{},
+		responses: map[string][]string{
+			"POST /threads":                   {`{"id": "thread_1"}`},
+			"POST /threads/thread_1/runs":     {`{"id": "run_1", "status": "queued"}`, `{"id": "run_2", "status": "completed"}`},
+			"POST /threads/thread_1/messages": {`{"id": "msg_2"}`},
+			"GET /threads/thread_1/runs/run_1": {
+				`{"id": "run_1", "status": "requires_action", "required_action": {"type": "submit_tool_outputs", "submit_tool_outputs": {"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "calculator", "arguments": "{\"input\": \"6*7\"}"}}]}}}`, //nolint:lll
+				`{"id": "run_1", "status": "completed"}`,
+			},
+			"POST /threads/thread_1/runs/run_1/submit_tool_outputs": {`{"id": "run_1", "status": "in_progress"}`},
+			"GET /threads/thread_1/messages": {
+				`{"data": [{"role": "assistant", "content": [{"type": "text", "text": {"value": "6*7 is 42."}}]}]}`,
+				`{"data": [{"role": "assistant", "content": [{"type": "text", "text": {"value": "You asked about 6*7."}}]}]}`,
+			},
+		},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	agentTools := []tools.Tool{tools.Calculator{}}
+	agent, err := New("asst_1", agentTools, WithToken("token"), WithBaseURL(ts.URL),
+		WithHostedTools("code_interpreter"), WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	executor := agents.NewExecutor(agent, agentTools)
+
+	result, err := chains.Run(context.Background(), executor, "What is 6*7?")
+	require.NoError(t, err)
+	require.Equal(t, "6*7 is 42.", result)
+	require.Equal(t, "thread_1", agent.ThreadID())
+
+	// The local tools are given with the hosted tools, and their results are
+	// submitted to the run.
+	runRequest := server.requests["POST /threads/thread_1/runs"][0]
+	require.Equal(t, "asst_1", runRequest["assistant_id"])
+	runTools, ok := runRequest["tools"].([]any)
+	require.True(t, ok)
+	require.Len(t, runTools, 2)
+	require.Equal(t, map[string]any{"type": "code_interpreter"}, runTools[0])
+	require.Equal(t, map[string]any{
+		"tool_outputs": []any{map[string]any{"tool_call_id": "call_1", "output": "42"}},
+	}, server.requests["POST /threads/thread_1/runs/run_1/submit_tool_outputs"][0])
+
+	// The next call continues the conversation in the thread.
+	result, err = chains.Run(context.Background(), executor, "What did I ask?")
+	require.NoError(t, err)
+	require.Equal(t, "You asked about 6*7.", result)
+	require.Equal(t, map[string]any{"role": "user", "content": "What did I ask?"},
+		server.requests["POST /threads/thread_1/messages"][0])
+}
+
+func TestAgentRunFailed(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeAssistantServer{
+		requests: map[string][]map[string]any{},
+		responses: map[string][]string{
+			"POST /threads":                    {`{"id": "thread_1"}`},
+			"POST /threads/thread_1/runs":      {`{"id": "run_1", "status": "queued"}`},
+			"GET /threads/thread_1/runs/run_1": {`{"id": "run_1", "status": "failed", "last_error": {"code": "rate_limit_exceeded", "message": "Slow down."}}`}, //nolint:lll
+		},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	agent, err := New("asst_1", nil, WithToken("token"), WithBaseURL(ts.URL), WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = chains.Run(context.Background(), agents.NewExecutor(agent, nil), "Hello")
+	require.ErrorIs(t, err, ErrRunFailed)
+	require.ErrorContains(t, err, "failed: rate_limit_exceeded: Slow down.")
+	require.Nil(t, server.requests["POST /threads/thread_1/runs"][0]["tools"])
+}
@@ -0,0 +1,192 @@
+package openaiassistant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+type client struct {
+	token        string
+	baseURL      string
+	organization string
+	httpClient   llms.HTTPDoer
+}
+
+type toolDefinition struct {
+	Type     string              `json:"type"`
+	Function *functionDefinition `json:"function,omitempty"`
+}
+
+type functionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type thread struct {
+	ID string `json:"id"`
+}
+
+type runRequest struct {
+	AssistantID            string           `json:"assistant_id"`
+	AdditionalInstructions string           `json:"additional_instructions,omitempty"`
+	Tools                  []toolDefinition `json:"tools,omitempty"`
+}
+
+type run struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	RequiredAction *struct {
+		SubmitToolOutputs struct {
+			ToolCalls []toolCall `json:"tool_calls"`
+		} `json:"submit_tool_outputs"`
+	} `json:"required_action"`
+	LastError *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"last_error"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details"`
+}
+
+type toolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type toolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+type messageList struct {
+	Data []struct {
+		Role    string `json:"role"`
+		Content []struct {
+			Type string `json:"type"`
+			Text struct {
+				Value string `json:"value"`
+			} `json:"text"`
+		} `json:"content"`
+	} `json:"data"`
+}
+
+func (c *client) createThread(ctx context.Context, content string) (thread, error) {
+	var t thread
+	err := c.do(ctx, http.MethodPost, "/threads", map[string]any{
+		"messages": []message{{Role: "user", Content: content}},
+	}, &t)
+	return t, err
+}
+
+func (c *client) createMessage(ctx context.Context, threadID, content string) error {
+	return c.do(ctx, http.MethodPost, "/threads/"+threadID+"/messages", message{Role: "user", Content: content}, nil)
+}
+
+func (c *client) createRun(ctx context.Context, threadID string, req runRequest) (run, error) {
+	var r run
+	err := c.do(ctx, http.MethodPost, "/threads/"+threadID+"/runs", req, &r)
+	return r, err
+}
+
+func (c *client) getRun(ctx context.Context, threadID, runID string) (run, error) {
+	var r run
+	err := c.do(ctx, http.MethodGet, "/threads/"+threadID+"/runs/"+runID, nil, &r)
+	return r, err
+}
+
+func (c *client) cancelRun(ctx context.Context, threadID, runID string) error {
+	return c.do(ctx, http.MethodPost, "/threads/"+threadID+"/runs/"+runID+"/cancel", nil, nil)
+}
+
+func (c *client) submitToolOutputs(ctx context.Context, threadID, runID string, outputs []toolOutput) (run, error) {
+	var r run
+	err := c.do(ctx, http.MethodPost, "/threads/"+threadID+"/runs/"+runID+"/submit_tool_outputs",
+		map[string]any{"tool_outputs": outputs}, &r)
+	return r, err
+}
+
+// runOutput returns the text of the messages of the assistant created by the
+// run.
+func (c *client) runOutput(ctx context.Context, threadID, runID string) (string, error) {
+	var messages messageList
+	query := url.Values{"run_id": {runID}, "order": {"asc"}}
+	if err := c.do(ctx, http.MethodGet, "/threads/"+threadID+"/messages?"+query.Encode(), nil, &messages); err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, msg := range messages.Data {
+		if msg.Role != "assistant" {
+			continue
+		}
+		for _, content := range msg.Content {
+			if content.Type == "text" {
+				texts = append(texts, content.Text.Value)
+			}
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+func (c *client) do(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("OpenAI-Beta", "assistants=v2")
+	if c.organization != "" {
+		req.Header.Set("OpenAI-Organization", c.organization)
+	}
+
+	resp, err := llms.DoRequest(c.httpClient, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(data, &errResp)
+		return llms.NewLLMError(resp.StatusCode, errResp.Error.Type, errResp.Error.Message, data)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
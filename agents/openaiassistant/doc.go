@@ -0,0 +1,7 @@
+// Package openaiassistant provides an agent backed by the OpenAI Assistants
+// API. The conversation is kept in a thread, each call of the agent executor
+// being a run of the assistant. The tools of the executor are given to the
+// run as functions, whose calls are executed locally and submitted back to
+// the run, so that they can be mixed with hosted tools such as
+// code_interpreter and file_search.
+package openaiassistant
@@ -0,0 +1,124 @@
+package openaiassistant
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	tokenEnvVarName        = "OPENAI_API_KEY"      //nolint:gosec
+	baseURLEnvVarName      = "OPENAI_BASE_URL"     //nolint:gosec
+	organizationEnvVarName = "OPENAI_ORGANIZATION" //nolint:gosec
+
+	_defaultBaseURL      = "https://api.openai.com/v1"
+	_defaultPollInterval = 500 * time.Millisecond
+	_defaultOutputKey    = "output"
+)
+
+type options struct {
+	token        string
+	baseURL      string
+	organization string
+	httpClient   llms.HTTPDoer
+
+	hostedTools  []string
+	instructions string
+	threadID     string
+	pollInterval time.Duration
+	outputKey    string
+}
+
+// Option is a functional option for the assistant agent.
+type Option func(*options)
+
+func defaultOptions() options {
+	baseURL := os.Getenv(baseURLEnvVarName)
+	if baseURL == "" {
+		baseURL = _defaultBaseURL
+	}
+	return options{
+		token:        os.Getenv(tokenEnvVarName),
+		baseURL:      baseURL,
+		organization: os.Getenv(organizationEnvVarName),
+		httpClient:   http.DefaultClient,
+		pollInterval: _defaultPollInterval,
+		outputKey:    _defaultOutputKey,
+	}
+}
+
+// WithToken passes the OpenAI API token to the client. If not set, the token
+// is read from the OPENAI_API_KEY environment variable.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithBaseURL passes the OpenAI base url to the client. If not set, the base
+// url is read from the OPENAI_BASE_URL environment variable, and defaults to
+// https://api.openai.com/v1.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithOrganization passes the OpenAI organization to the client. If not set,
+// the organization is read from the OPENAI_ORGANIZATION environment variable.
+func WithOrganization(organization string) Option {
+	return func(opts *options) {
+		opts.organization = organization
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client. If not set, the default
+// value is http.DefaultClient.
+func WithHTTPClient(client llms.HTTPDoer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// WithHostedTools sets the hosted tools of the runs, e.g. "code_interpreter"
+// and "file_search". As the tools of a run replace those of the assistant,
+// the hosted tools of the assistant must be given when the agent has local
+// tools.
+func WithHostedTools(tools ...string) Option {
+	return func(opts *options) {
+		opts.hostedTools = tools
+	}
+}
+
+// WithInstructions sets instructions appended to the instructions of the
+// assistant for the runs.
+func WithInstructions(instructions string) Option {
+	return func(opts *options) {
+		opts.instructions = instructions
+	}
+}
+
+// WithThreadID continues the conversation of an existing thread. If not set,
+// a thread is created by the first run.
+func WithThreadID(threadID string) Option {
+	return func(opts *options) {
+		opts.threadID = threadID
+	}
+}
+
+// WithPollInterval sets the interval between the polls of the status of a
+// run. Defaults to 500ms.
+func WithPollInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.pollInterval = interval
+	}
+}
+
+// WithOutputKey sets the output key of the agent. Defaults to "output".
+func WithOutputKey(outputKey string) Option {
+	return func(opts *options) {
+		opts.outputKey = outputKey
+	}
+}
@@ -1,7 +1,11 @@
 package agents
 
 import (
+	"maps"
+	"time"
+
 	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -14,11 +18,28 @@ type Options struct {
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
 	maxIterations           int
+	maxReplans              int
+	maxParallelToolCalls    int
+	approver                ToolApprover
+	approvalTools           []string
+	timeout                 time.Duration
+	llmCallTimeout          time.Duration
+	toolTimeout             time.Duration
+	isolatedMemory          bool
+	checkpoints             CheckpointStore
+	finalAnswerSchema       *jsonschema.Definition
+	toolErrorPolicies       map[string]ToolErrorPolicy
+	defaultToolErrorPolicy  ToolErrorPolicy
+	maxToolRetries          int
+	budget                  Budget
 	returnIntermediateSteps bool
 	outputKey               string
 	promptPrefix            string
 	formatInstructions      string
 	promptSuffix            string
+	examples                []Example
+	toolExamples            []toolExample
+	outputLanguage          string
 
 	// openai
 	systemMessage string
@@ -31,9 +52,11 @@ type Option func(*Options)
 
 func executorDefaultOptions() Options {
 	return Options{
-		maxIterations: _defaultMaxIterations,
-		outputKey:     _defaultOutputKey,
-		memory:        memory.NewSimple(),
+		maxIterations:        _defaultMaxIterations,
+		maxParallelToolCalls: _defaultMaxParallelToolCalls,
+		maxToolRetries:       _defaultMaxToolRetries,
+		outputKey:            _defaultOutputKey,
+		memory:               memory.NewSimple(),
 	}
 }
 
@@ -62,17 +85,53 @@ func openAIFunctionsDefaultOptions() Options {
 	}
 }
 
+func toolCallingDefaultOptions() Options {
+	return Options{
+		systemMessage: "You are a helpful AI assistant.",
+		outputKey:     _defaultOutputKey,
+	}
+}
+
+func structuredChatDefaultOptions() Options {
+	return Options{
+		promptPrefix:       _defaultStructuredChatPrefix,
+		formatInstructions: _defaultStructuredChatFormatInstructions,
+		promptSuffix:       _defaultStructuredChatSuffix,
+		outputKey:          _defaultOutputKey,
+	}
+}
+
+func supervisorDefaultOptions() Options {
+	return Options{
+		systemMessage: _defaultSupervisorMessage,
+		maxIterations: _defaultSupervisorRounds,
+		outputKey:     _defaultOutputKey,
+	}
+}
+
+func planAndExecuteDefaultOptions() Options {
+	return Options{
+		maxIterations:        _defaultMaxIterations,
+		maxParallelToolCalls: _defaultMaxParallelToolCalls,
+		maxReplans:           _defaultMaxReplans,
+		outputKey:            _defaultOutputKey,
+	}
+}
+
 func (co Options) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
 	}
 
-	return createMRKLPrompt(
+	instructions, extras := co.promptExtras(formatMRKLExample)
+	prompt := createMRKLPrompt(
 		tools,
 		co.promptPrefix,
-		co.formatInstructions,
+		instructions,
 		co.promptSuffix,
 	)
+	maps.Copy(prompt.PartialVariables, extras)
+	return prompt
 }
 
 func (co Options) getConversationalPrompt(tools []tools.Tool) prompts.PromptTemplate {
@@ -80,12 +139,31 @@ func (co Options) getConversationalPrompt(tools []tools.Tool) prompts.PromptTemp
 		return co.prompt
 	}
 
-	return createConversationalPrompt(
+	instructions, extras := co.promptExtras(formatConversationalExample)
+	prompt := createConversationalPrompt(
 		tools,
 		co.promptPrefix,
-		co.formatInstructions,
+		instructions,
 		co.promptSuffix,
 	)
+	maps.Copy(prompt.PartialVariables, extras)
+	return prompt
+}
+
+func (co Options) getStructuredChatPrompt(tools []tools.Tool) prompts.PromptTemplate {
+	if co.prompt.Template != "" {
+		return co.prompt
+	}
+
+	instructions, extras := co.promptExtras(formatStructuredChatExample)
+	prompt := createStructuredChatPrompt(
+		tools,
+		co.promptPrefix,
+		instructions,
+		co.promptSuffix,
+	)
+	maps.Copy(prompt.PartialVariables, extras)
+	return prompt
 }
 
 // WithMaxIterations is an option for setting the max number of iterations the executor
@@ -96,6 +174,108 @@ func WithMaxIterations(iterations int) Option {
 	}
 }
 
+// WithMaxParallelToolCalls is an option for setting the max number of tools the
+// executor calls concurrently when the agent returns several actions at once.
+// 1 calls them sequentially, and 0 doesn't limit them.
+func WithMaxParallelToolCalls(n int) Option {
+	return func(co *Options) {
+		co.maxParallelToolCalls = n
+	}
+}
+
+// WithToolApprover is an option for making the executor ask the approver
+// before calling the tools with the names, or all the tools if none is given,
+// e.g. the tools with side effects.
+func WithToolApprover(approver ToolApprover, toolNames ...string) Option {
+	return func(co *Options) {
+		co.approver = approver
+		co.approvalTools = toolNames
+	}
+}
+
+// WithToolErrorPolicy is an option for setting what the executor does when
+// the tools with the names, or all the tools if none is given, return an
+// error.
+func WithToolErrorPolicy(policy ToolErrorPolicy, toolNames ...string) Option {
+	return func(co *Options) {
+		if len(toolNames) == 0 {
+			co.defaultToolErrorPolicy = policy
+			return
+		}
+		if co.toolErrorPolicies == nil {
+			co.toolErrorPolicies = make(map[string]ToolErrorPolicy, len(toolNames))
+		}
+		for _, name := range toolNames {
+			co.toolErrorPolicies[name] = policy
+		}
+	}
+}
+
+// WithMaxToolRetries is an option for setting the number of times a tool with
+// the ToolErrorRetry policy can fail in a run before the executor aborts it.
+func WithMaxToolRetries(n int) Option {
+	return func(co *Options) {
+		co.maxToolRetries = n
+	}
+}
+
+// WithBudget is an option for limiting the tokens, the estimated cost and the
+// tool calls of each run of the executor.
+func WithBudget(budget Budget) Option {
+	return func(co *Options) {
+		co.budget = budget
+	}
+}
+
+// WithTimeout is an option for setting the maximum duration of a run of the
+// executor.
+func WithTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.timeout = timeout
+	}
+}
+
+// WithLLMCallTimeout is an option for setting the maximum duration of each call
+// of the agent of the executor to its model.
+func WithLLMCallTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.llmCallTimeout = timeout
+	}
+}
+
+// WithToolTimeout is an option for setting the maximum duration of each tool
+// call of the executor.
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.toolTimeout = timeout
+	}
+}
+
+// WithIsolatedMemory is an option for giving the workers of a supervisor only
+// their own previous tasks and results, instead of those of all the workers.
+func WithIsolatedMemory() Option {
+	return func(co *Options) {
+		co.isolatedMemory = true
+	}
+}
+
+// WithCheckpointStore is an option for making the executor save the steps of
+// the runs identified by ContextWithRunID to the store, so that they can be
+// resumed.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(co *Options) {
+		co.checkpoints = store
+	}
+}
+
+// WithMaxReplans is an option for setting the max number of revisions of the
+// plan of a plan-and-execute agent.
+func WithMaxReplans(replans int) Option {
+	return func(co *Options) {
+		co.maxReplans = replans
+	}
+}
+
 // WithOutputKey is an option for setting the output key of the agent.
 func WithOutputKey(outputKey string) Option {
 	return func(co *Options) {
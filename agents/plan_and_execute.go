@@ -0,0 +1,269 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_planAndExecuteInputKey = "input"
+	_defaultMaxReplans      = 3
+)
+
+// _planStepRegex matches the steps of a plan written as a numbered or bulleted
+// list.
+var _planStepRegex = regexp.MustCompile(`^\s*(?:(?:[Ss]tep\s+)?\d+[.):]|[-*])\s+(.+?)\s*$`)
+
+// Planner makes the plans of a PlanAndExecute agent with a single call to the
+// model, whose answer is a numbered list of steps.
+type Planner struct {
+	LLM llms.Model
+	// Prompt asks for the plan as a numbered list, one step per line.
+	Prompt prompts.FormatPrompter
+}
+
+// NewPlanner creates a new Planner with the default prompt, formatted with the
+// objective of the plan in "input".
+func NewPlanner(llm llms.Model) Planner {
+	return Planner{
+		LLM:    llm,
+		Prompt: prompts.NewPromptTemplate(_defaultPlannerTemplate, []string{"input"}),
+	}
+}
+
+// NewReplanner creates a new Planner revising the steps left of a plan when
+// one of its steps failed. Its prompt is formatted with the "input"
+// objective, the "plan", the "completed" steps and their results, and the
+// failed "step" with its "error".
+func NewReplanner(llm llms.Model) Planner {
+	return Planner{
+		LLM: llm,
+		Prompt: prompts.NewPromptTemplate(
+			_defaultReplannerTemplate,
+			[]string{"input", "plan", "completed", "step", "error"},
+		),
+	}
+}
+
+// Plan returns the steps of the plan made by the model from the values of the
+// prompt.
+func (p Planner) Plan(ctx context.Context, values map[string]any) ([]string, error) {
+	prompt, err := p.Prompt.FormatPrompt(values)
+	if err != nil {
+		return nil, err
+	}
+	text, err := llms.GenerateFromSinglePrompt(ctx, p.LLM, prompt.String())
+	if err != nil {
+		return nil, err
+	}
+
+	steps := parsePlanSteps(text)
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: no plan steps in %q", ErrUnableToParseOutput, text)
+	}
+	return steps, nil
+}
+
+func parsePlanSteps(text string) []string {
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		if match := _planStepRegex.FindStringSubmatch(line); match != nil {
+			steps = append(steps, match[1])
+		}
+	}
+	return steps
+}
+
+// PlanStepResult is a step of a plan executed by a PlanAndExecute agent.
+type PlanStepResult struct {
+	Step   string
+	Result string
+}
+
+// PlanAndExecute is an agent that first plans the steps to reach the objective
+// given as input with a single call to the model, and then executes each step
+// with a worker agent using tools. When a step fails, the replanner revises
+// the steps left. The result of the final step is the output.
+//
+// Plans and steps are notified to the callbacks handler if it implements
+// callbacks.PlanHandler, e.g. to display the plan and its progress.
+type PlanAndExecute struct {
+	Planner Planner
+	// Replanner revises the steps left of the plan when a step fails. If nil,
+	// the agent fails with the step.
+	Replanner *Planner
+	// Worker executes the steps. Its single input is the StepPrompt,
+	// formatted with the "input" objective, the "completed" steps and their
+	// results, and the "step" to execute.
+	Worker     chains.Chain
+	StepPrompt prompts.FormatPrompter
+	// MaxReplans is the maximum number of revisions of the plan.
+	MaxReplans int
+
+	OutputKey               string
+	ReturnIntermediateSteps bool
+	CallbacksHandler        callbacks.Handler
+}
+
+var (
+	_ chains.Chain           = &PlanAndExecute{}
+	_ callbacks.HandlerHaver = &PlanAndExecute{}
+)
+
+// NewPlanAndExecute creates a new PlanAndExecute agent planning with the model,
+// and executing the steps with an executor of a ToolCallingAgent using the
+// tools. The max iterations, max parallel tool calls, callbacks handler and
+// parser error handler options are also given to the executor of the steps.
+func NewPlanAndExecute(llm llms.Model, tools []tools.Tool, opts ...Option) *PlanAndExecute {
+	options := planAndExecuteDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	replanner := NewReplanner(llm)
+	return &PlanAndExecute{
+		Planner:   NewPlanner(llm),
+		Replanner: &replanner,
+		Worker: NewExecutor(
+			NewToolCallingAgent(llm, tools, WithCallbacksHandler(options.callbacksHandler)),
+			tools,
+			WithMaxIterations(options.maxIterations),
+			WithMaxParallelToolCalls(options.maxParallelToolCalls),
+			WithCallbacksHandler(options.callbacksHandler),
+			WithParserErrorHandler(options.errorHandler),
+		),
+		StepPrompt:              prompts.NewPromptTemplate(_defaultPlanStepTemplate, []string{"input", "completed", "step"}),
+		MaxReplans:              options.maxReplans,
+		OutputKey:               options.outputKey,
+		ReturnIntermediateSteps: options.returnIntermediateSteps,
+		CallbacksHandler:        options.callbacksHandler,
+	}
+}
+
+// Call plans the steps to reach the objective of the input, and executes them
+// in order, revising the steps left when one fails.
+func (p *PlanAndExecute) Call(ctx context.Context, values map[string]any, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values[_planAndExecuteInputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrExecutorInputNotString, _planAndExecuteInputKey)
+	}
+	planHandler, _ := p.CallbacksHandler.(callbacks.PlanHandler)
+
+	steps, err := p.Planner.Plan(ctx, map[string]any{"input": input})
+	if err != nil {
+		return nil, fmt.Errorf("planning: %w", err)
+	}
+	if planHandler != nil {
+		planHandler.HandlePlan(ctx, steps)
+	}
+
+	results := make([]PlanStepResult, 0, len(steps))
+	replans := 0
+	for len(results) < len(steps) {
+		i := len(results)
+		if planHandler != nil {
+			planHandler.HandlePlanStepStart(ctx, i, steps[i])
+		}
+		result, err := p.executeStep(ctx, input, results, steps[i], options)
+		if err != nil {
+			if planHandler != nil {
+				planHandler.HandlePlanStepError(ctx, i, err)
+			}
+			if ctx.Err() != nil || p.Replanner == nil || replans >= p.MaxReplans {
+				return nil, fmt.Errorf("step %d of plan: %w", i+1, err)
+			}
+			replans++
+
+			left, replanErr := p.Replanner.Plan(ctx, map[string]any{
+				"input":     input,
+				"plan":      formatPlan(steps),
+				"completed": formatCompletedSteps(results, "None."),
+				"step":      steps[i],
+				"error":     err.Error(),
+			})
+			if replanErr != nil {
+				return nil, fmt.Errorf("replanning: %w", replanErr)
+			}
+			steps = append(steps[:i:i], left...)
+			if planHandler != nil {
+				planHandler.HandlePlan(ctx, steps)
+			}
+			continue
+		}
+		if planHandler != nil {
+			planHandler.HandlePlanStepEnd(ctx, i, result)
+		}
+		results = append(results, PlanStepResult{Step: steps[i], Result: result})
+	}
+
+	outputs := map[string]any{p.OutputKey: results[len(results)-1].Result}
+	if p.ReturnIntermediateSteps {
+		outputs[_intermediateStepsOutputKey] = results
+	}
+	return outputs, nil
+}
+
+func (p *PlanAndExecute) executeStep(
+	ctx context.Context,
+	input string,
+	results []PlanStepResult,
+	step string,
+	options []chains.ChainCallOption,
+) (string, error) {
+	prompt, err := p.StepPrompt.FormatPrompt(map[string]any{
+		"input":     input,
+		"completed": formatCompletedSteps(results, ""),
+		"step":      step,
+	})
+	if err != nil {
+		return "", err
+	}
+	return chains.Run(ctx, p.Worker, prompt.String(), options...)
+}
+
+func formatPlan(steps []string) string {
+	var b strings.Builder
+	for i, step := range steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatCompletedSteps returns the completed steps with their results, or
+// none if there are no completed steps.
+func formatCompletedSteps(results []PlanStepResult, none string) string {
+	if len(results) == 0 {
+		return none
+	}
+	var b strings.Builder
+	for i, result := range results {
+		fmt.Fprintf(&b, "%d. %s\nResult: %s\n", i+1, result.Step, result.Result)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (p *PlanAndExecute) GetInputKeys() []string {
+	return []string{_planAndExecuteInputKey}
+}
+
+func (p *PlanAndExecute) GetOutputKeys() []string {
+	return []string{p.OutputKey}
+}
+
+func (p *PlanAndExecute) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (p *PlanAndExecute) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return p.CallbacksHandler
+}
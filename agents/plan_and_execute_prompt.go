@@ -0,0 +1,32 @@
+package agents
+
+//nolint:lll
+const _defaultPlannerTemplate = `For the given objective, come up with a simple step by step plan. This plan should involve individual tasks, that if executed correctly will yield the correct answer. Do not add any superfluous steps. The result of the final step should be the final answer. Make sure that each step has all the information needed - do not skip steps.
+
+Write the plan as a numbered list, one step per line, and nothing else.
+
+Objective: {{.input}}`
+
+//nolint:lll
+const _defaultReplannerTemplate = `For the given objective, you made a step by step plan, and one of its steps failed. Revise the steps of the plan left to execute, working around the failure, so that the result of the final step is the final answer. Do not repeat the completed steps.
+
+Write the steps left as a numbered list, one step per line, and nothing else.
+
+Objective: {{.input}}
+
+Plan:
+{{.plan}}
+
+Completed steps:
+{{.completed}}
+
+Failed step: {{.step}}
+Error: {{.error}}`
+
+//nolint:lll
+const _defaultPlanStepTemplate = `You are executing a step by step plan for the objective: {{.input}}
+{{if .completed}}
+Completed steps:
+{{.completed}}
+{{end}}
+Execute the following step, and answer with its result: {{.step}}`
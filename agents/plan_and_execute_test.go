@@ -0,0 +1,111 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type failingTool struct{}
+
+func (failingTool) Name() string        { return "lookup" }
+func (failingTool) Description() string { return "Looks up facts." }
+func (failingTool) Call(context.Context, string) (string, error) {
+	return "", errors.New("lookup unavailable")
+}
+
+type planRecordingHandler struct {
+	callbacks.SimpleHandler
+	events []string
+}
+
+func (h *planRecordingHandler) HandlePlan(_ context.Context, steps []string) {
+	h.events = append(h.events, fmt.Sprintf("plan %q", steps))
+}
+
+func (h *planRecordingHandler) HandlePlanStepStart(_ context.Context, index int, _ string) {
+	h.events = append(h.events, fmt.Sprintf("start %d", index))
+}
+
+func (h *planRecordingHandler) HandlePlanStepEnd(_ context.Context, index int, result string) {
+	h.events = append(h.events, fmt.Sprintf("end %d %s", index, result))
+}
+
+func (h *planRecordingHandler) HandlePlanStepError(_ context.Context, index int, _ error) {
+	h.events = append(h.events, fmt.Sprintf("error %d", index))
+}
+
+func toolCallResponse(id, name, arguments string) fake.Response {
+	return fake.Response{ToolCalls: []llms.ToolCall{
+		{ID: id, Type: "function", FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments}},
+	}}
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		// Plan.
+		fake.Response{Content: "Here is the plan:\n1. Look up the population of Paris.\n2) Double it."},
+		// Step 1, failing.
+		toolCallResponse("call_1", "lookup", `{"input": "population of Paris"}`),
+		// Revised plan.
+		fake.Response{Content: "1. Estimate the population of Paris.\n2. Double it."},
+		// Step 1.
+		fake.Response{Content: "2000000"},
+		// Step 2.
+		toolCallResponse("call_2", "calculator", `{"input": "2*2000000"}`),
+		fake.Response{Content: "4000000"},
+	)
+	handler := &planRecordingHandler{}
+	agent := agents.NewPlanAndExecute(llm, []tools.Tool{failingTool{}, tools.Calculator{}},
+		agents.WithCallbacksHandler(handler), agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), agent, map[string]any{"input": "What is twice the population of Paris?"}) //nolint:lll
+	require.NoError(t, err)
+	require.Equal(t, "4000000", result["output"])
+	require.Equal(t, []agents.PlanStepResult{
+		{Step: "Estimate the population of Paris.", Result: "2000000"},
+		{Step: "Double it.", Result: "4000000"},
+	}, result["intermediateSteps"])
+	require.Equal(t, []string{
+		`plan ["Look up the population of Paris." "Double it."]`,
+		"start 0",
+		"error 0",
+		`plan ["Estimate the population of Paris." "Double it."]`,
+		"start 0",
+		"end 0 2000000",
+		"start 1",
+		"end 1 4000000",
+	}, handler.events)
+
+	// The replanner is given the failure, and the steps the completed steps.
+	calls := llm.Calls()
+	replan := calls[2].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, replan, "Failed step: Look up the population of Paris.\nError: lookup unavailable")
+	step := calls[4].Messages[1].Parts[0].(llms.TextContent).Text
+	require.Contains(t, step, "1. Estimate the population of Paris.\nResult: 2000000")
+	require.Contains(t, step, "Execute the following step, and answer with its result: Double it.")
+}
+
+func TestPlanAndExecuteMaxReplans(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		fake.Response{Content: "1. Look up the population of Paris."},
+		toolCallResponse("call_1", "lookup", `{"input": "population of Paris"}`),
+	)
+	agent := agents.NewPlanAndExecute(llm, []tools.Tool{failingTool{}}, agents.WithMaxReplans(0))
+
+	_, err := chains.Call(context.Background(), agent, map[string]any{"input": "What is the population of Paris?"})
+	require.ErrorContains(t, err, "step 1 of plan: lookup unavailable")
+}
@@ -0,0 +1,140 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Example is a worked example of the use of the tools, shown to the model in
+// the prompts of the MRKL, conversational and structured chat agents in
+// their own format.
+type Example struct {
+	// Input is the question of the example.
+	Input string
+	// Steps are the tool calls made to answer it.
+	Steps []ExampleStep
+	// Answer is the final answer.
+	Answer string
+}
+
+// ExampleStep is a tool call of an Example.
+type ExampleStep struct {
+	Thought     string
+	Tool        string
+	ToolInput   string
+	Observation string
+}
+
+// toolExample is an example input of a tool.
+type toolExample struct {
+	Tool  string
+	Input string
+}
+
+// WithExamples is an option for adding worked examples of the use of the
+// tools to the prompt of the ReAct agents, after the format instructions.
+func WithExamples(examples ...Example) Option {
+	return func(co *Options) {
+		co.examples = append(co.examples, examples...)
+	}
+}
+
+// WithToolExamples is an option for adding example inputs of the tool to the
+// prompt of the ReAct agents.
+func WithToolExamples(toolName string, inputs ...string) Option {
+	return func(co *Options) {
+		for _, input := range inputs {
+			co.toolExamples = append(co.toolExamples, toolExample{Tool: toolName, Input: input})
+		}
+	}
+}
+
+// WithOutputLanguage is an option for making the ReAct agents give their
+// final answer in the language, e.g. "French".
+func WithOutputLanguage(language string) Option {
+	return func(co *Options) {
+		co.outputLanguage = language
+	}
+}
+
+// promptExtras returns the format instructions followed by the sections of
+// the examples and the output language, if any, and the partial variables of
+// these sections. The sections are given as variables so that their text
+// isn't parsed as a template.
+func (co Options) promptExtras(formatExample func(Example) string) (string, map[string]any) {
+	sections := []string{co.formatInstructions}
+	values := make(map[string]any)
+
+	if len(co.toolExamples) > 0 {
+		var b strings.Builder
+		b.WriteString("Examples of tool inputs:\n")
+		for _, example := range co.toolExamples {
+			fmt.Fprintf(&b, "- %s: %s\n", example.Tool, example.Input)
+		}
+		sections = append(sections, "{{.tool_examples}}")
+		values["tool_examples"] = strings.TrimSuffix(b.String(), "\n")
+	}
+	if len(co.examples) > 0 {
+		formatted := make([]string, 0, len(co.examples))
+		for _, example := range co.examples {
+			formatted = append(formatted, formatExample(example))
+		}
+		sections = append(sections, "{{.examples}}")
+		values["examples"] = "Here are some examples:\n\n" + strings.Join(formatted, "\n\n")
+	}
+	if co.outputLanguage != "" {
+		sections = append(sections, "Always give the final answer in {{.output_language}}.")
+		values["output_language"] = co.outputLanguage
+	}
+
+	return strings.Join(sections, "\n\n"), values
+}
+
+func formatMRKLExample(example Example) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question: %s\n", example.Input)
+	for _, step := range example.Steps {
+		fmt.Fprintf(&b, "Thought: %s\nAction: %s\nAction Input: %s\nObservation: %s\n",
+			step.Thought, step.Tool, step.ToolInput, step.Observation)
+	}
+	fmt.Fprintf(&b, "Thought: I now know the final answer\nFinal Answer: %s", example.Answer)
+	return b.String()
+}
+
+func formatConversationalExample(example Example) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "New input: %s\n", example.Input)
+	for _, step := range example.Steps {
+		fmt.Fprintf(&b, "Thought: Do I need to use a tool? Yes\nAction: %s\nAction Input: %s\nObservation: %s\n",
+			step.Tool, step.ToolInput, step.Observation)
+	}
+	fmt.Fprintf(&b, "Thought: Do I need to use a tool? No\nAI: %s", example.Answer)
+	return b.String()
+}
+
+func formatStructuredChatExample(example Example) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question: %s\n", example.Input)
+	for _, step := range example.Steps {
+		fmt.Fprintf(&b, "Thought: %s\nAction:\n```\n%s\n```\nObservation: %s\n",
+			step.Thought, structuredChatBlob(step.Tool, json.RawMessage(step.ToolInput)), step.Observation)
+	}
+	answer, _ := json.Marshal(example.Answer)
+	fmt.Fprintf(&b, "Thought: I know what to respond\nAction:\n```\n%s\n```",
+		structuredChatBlob(_structuredChatFinalAnswerAction, json.RawMessage(answer)))
+	return b.String()
+}
+
+// structuredChatBlob returns the JSON blob of an action of the structured
+// chat agent. Inputs which aren't valid JSON are given as strings.
+func structuredChatBlob(action string, input json.RawMessage) string {
+	if !json.Valid(input) {
+		input, _ = json.Marshal(string(input))
+	}
+	blob, err := json.MarshalIndent(map[string]any{"action": action, "action_input": input}, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(blob)
+}
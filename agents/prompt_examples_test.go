@@ -0,0 +1,81 @@
+package agents_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestPromptExamples(t *testing.T) {
+	t.Parallel()
+
+	example := agents.Example{
+		Input: "What is 3 to the power of 4?",
+		Steps: []agents.ExampleStep{{
+			Thought:     "I should compute it.",
+			Tool:        "calculator",
+			ToolInput:   "3^4",
+			Observation: "81",
+		}},
+		Answer: "81",
+	}
+	opts := []agents.Option{
+		agents.WithExamples(example),
+		agents.WithToolExamples("calculator", "2+2", "sqrt({{16}})"),
+		agents.WithOutputLanguage("French"),
+	}
+	agentTools := []tools.Tool{tools.Calculator{}}
+
+	tests := []struct {
+		name     string
+		newAgent func(llms.Model) agents.Agent
+		answer   string
+		example  string
+	}{
+		{
+			name:     "mrkl",
+			newAgent: func(llm llms.Model) agents.Agent { return agents.NewOneShotAgent(llm, agentTools, opts...) },
+			answer:   "Final Answer: 42",
+			example: "Question: What is 3 to the power of 4?\nThought: I should compute it.\nAction: calculator\n" +
+				"Action Input: 3^4\nObservation: 81\nThought: I now know the final answer\nFinal Answer: 81",
+		},
+		{
+			name:     "conversational",
+			newAgent: func(llm llms.Model) agents.Agent { return agents.NewConversationalAgent(llm, agentTools, opts...) },
+			answer:   "Do I need to use a tool? No\nAI: 42",
+			example: "New input: What is 3 to the power of 4?\nThought: Do I need to use a tool? Yes\nAction: calculator\n" +
+				"Action Input: 3^4\nObservation: 81\nThought: Do I need to use a tool? No\nAI: 81",
+		},
+		{
+			name:     "structured chat",
+			newAgent: func(llm llms.Model) agents.Agent { return agents.NewStructuredChatAgent(llm, agentTools, opts...) },
+			answer:   `Action: {"action": "Final Answer", "action_input": "42"}`,
+			example: "Question: What is 3 to the power of 4?\nThought: I should compute it.\nAction:\n```\n" +
+				"{\n  \"action\": \"calculator\",\n  \"action_input\": \"3^4\"\n}\n```\nObservation: 81\n" +
+				"Thought: I know what to respond\nAction:\n```\n{\n  \"action\": \"Final Answer\",\n  \"action_input\": \"81\"\n}\n```",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := fake.New(fake.Response{Content: tt.answer})
+			executor := agents.NewExecutor(tt.newAgent(llm), agentTools)
+			result, err := chains.Run(context.Background(), executor, "What is 6 times 7?")
+			require.NoError(t, err)
+			require.Equal(t, "42", strings.TrimSpace(result))
+
+			prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+			require.Contains(t, prompt, "Examples of tool inputs:\n- calculator: 2+2\n- calculator: sqrt({{16}})\n\n")
+			require.Contains(t, prompt, "Here are some examples:\n\n"+tt.example+"\n\n")
+			require.Contains(t, prompt, "Always give the final answer in French.\n\n")
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const _structuredChatFinalAnswerAction = "Final Answer"
+
+// _jsonBlobRegex matches the JSON blob of an action, fenced or not.
+var _jsonBlobRegex = regexp.MustCompile("(?s)```(?:json)?\\s*(\\{.*?\\})\\s*```|(\\{.*\\})")
+
+// StructuredChatAgent is an agent using tools with several arguments. The
+// arguments of the tools implementing tools.StructuredTool are described by
+// their JSON schema in the prompt, and the model answers with a JSON blob
+// giving the tool and its arguments, which are given as JSON to the tool.
+// Other tools have a single string "input" argument.
+//
+// Unlike ToolCallingAgent, it doesn't need the model to support tool calling.
+type StructuredChatAgent struct {
+	// Chain is the chain used to call with the values. The chain should have an
+	// input called "agent_scratchpad" for the agent to put its thoughts in.
+	Chain chains.Chain
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var _ Agent = (*StructuredChatAgent)(nil)
+
+// NewStructuredChatAgent creates a new StructuredChatAgent with the given LLM
+// model, tools, and options.
+func NewStructuredChatAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *StructuredChatAgent {
+	options := structuredChatDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &StructuredChatAgent{
+		Chain: chains.NewLLMChain(
+			llm,
+			options.getStructuredChatPrompt(tools),
+			chains.WithCallback(options.callbacksHandler),
+		),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what action to take or returns the final result of the input.
+func (a *StructuredChatAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+
+	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+
+	var stream func(ctx context.Context, chunk []byte) error
+
+	if a.CallbacksHandler != nil {
+		stream = func(ctx context.Context, chunk []byte) error {
+			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
+			return nil
+		}
+	}
+
+	output, err := chains.Predict(
+		ctx,
+		a.Chain,
+		fullInputs,
+		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
+		chains.WithStreamingFunc(stream),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.parseOutput(output)
+}
+
+func (a *StructuredChatAgent) GetInputKeys() []string {
+	chainInputs := a.Chain.GetInputKeys()
+
+	// Remove inputs given in plan.
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == "agent_scratchpad" {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+func (a *StructuredChatAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func (a *StructuredChatAgent) parseOutput(output string) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	matches := _jsonBlobRegex.FindStringSubmatch(output)
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnableToParseOutput, output)
+	}
+	blob := matches[1]
+	if blob == "" {
+		blob = matches[2]
+	}
+
+	var action struct {
+		Action      string          `json:"action"`
+		ActionInput json.RawMessage `json:"action_input"`
+	}
+	if err := json.Unmarshal([]byte(blob), &action); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w: %s", ErrUnableToParseOutput, err, output)
+	}
+	if action.Action == "" {
+		return nil, nil, fmt.Errorf("%w: no action: %s", ErrUnableToParseOutput, output)
+	}
+
+	if action.Action == _structuredChatFinalAnswerAction {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: jsonString(action.ActionInput)},
+			Log:          output,
+		}, nil
+	}
+
+	return []schema.AgentAction{
+		{Tool: action.Action, ToolInput: a.toolInput(action.Action, action.ActionInput), Log: output},
+	}, nil, nil
+}
+
+// toolInput returns the input of the tool called: the JSON arguments for
+// structured tools, and the input argument for others.
+func (a *StructuredChatAgent) toolInput(name string, input json.RawMessage) string {
+	for _, tool := range a.Tools {
+		if _, ok := tool.(tools.StructuredTool); ok && strings.EqualFold(tool.Name(), name) {
+			// Arguments given as a JSON string are decoded.
+			return jsonString(input)
+		}
+	}
+
+	var arguments map[string]any
+	if err := json.Unmarshal(input, &arguments); err == nil {
+		if value, ok := arguments[_toolInputParameter].(string); ok {
+			return value
+		}
+	}
+	return jsonString(input)
+}
+
+// jsonString returns the value of a JSON string, or the compacted JSON of
+// other values.
+func jsonString(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, value); err != nil {
+		return string(value)
+	}
+	return b.String()
+}
+
+//go:embed prompts/structured_chat_prefix.txt
+var _defaultStructuredChatPrefix string //nolint:gochecknoglobals
+
+//go:embed prompts/structured_chat_format_instructions.txt
+var _defaultStructuredChatFormatInstructions string //nolint:gochecknoglobals
+
+//go:embed prompts/structured_chat_suffix.txt
+var _defaultStructuredChatSuffix string //nolint:gochecknoglobals
+
+func createStructuredChatPrompt(tools []tools.Tool, prefix, instructions, suffix string) prompts.PromptTemplate {
+	template := strings.Join([]string{prefix, instructions, suffix}, "\n\n")
+
+	return prompts.PromptTemplate{
+		Template:       template,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "agent_scratchpad"},
+		PartialVariables: map[string]any{
+			"tool_names":        structuredToolNames(tools),
+			"tool_descriptions": structuredToolDescriptions(tools),
+		},
+	}
+}
+
+func structuredToolNames(tools []tools.Tool) string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, fmt.Sprintf("%q", tool.Name()))
+	}
+	return strings.Join(names, ", ")
+}
+
+// structuredToolDescriptions describes the tools with the JSON schema of
+// their arguments.
+func structuredToolDescriptions(tools []tools.Tool) string {
+	var ts strings.Builder
+	for _, tool := range tools {
+		ts.WriteString(fmt.Sprintf("- %s: %s, args: %s\n", tool.Name(), tool.Description(), toolArguments(tool)))
+	}
+
+	return ts.String()
+}
+
+// toolArguments returns the JSON schema of the properties of the parameters
+// of the tool.
+func toolArguments(tool tools.Tool) string {
+	var parameters any = map[string]any{
+		"type":       "object",
+		"properties": map[string]any{_toolInputParameter: map[string]any{"type": "string"}},
+	}
+	if structured, ok := tool.(tools.StructuredTool); ok {
+		parameters = structured.Parameters()
+	}
+
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return "{}"
+	}
+	var definition struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &definition); err != nil || len(definition.Properties) == 0 {
+		return string(data)
+	}
+	return string(definition.Properties)
+}
@@ -0,0 +1,70 @@
+package agents_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type forecastArgs struct {
+	City string `json:"city" description:"The name of the city."`
+	Days int    `json:"days"`
+}
+
+func TestStructuredChatAgent(t *testing.T) {
+	t.Parallel()
+
+	var got forecastArgs
+	forecast := tools.NewFunc("forecast", "Returns the weather forecast of a city.",
+		func(_ context.Context, args forecastArgs) (string, error) {
+			got = args
+			return fmt.Sprintf("sunny for %d days", args.Days), nil
+		})
+
+	llm := fake.New(
+		fake.Response{Content: "I should get the forecast.\nAction:\n```json\n" +
+			`{"action": "forecast", "action_input": {"city": "Paris", "days": 3}}` + "\n```"},
+		fake.Response{Content: "I know what to respond\nAction:\n```\n" +
+			`{"action": "Final Answer", "action_input": "It will be sunny in Paris."}` + "\n```"},
+	)
+	agentTools := []tools.Tool{forecast, tools.Calculator{}}
+	executor := agents.NewExecutor(agents.NewStructuredChatAgent(llm, agentTools), agentTools)
+
+	result, err := chains.Run(context.Background(), executor, "What's the weather in Paris for the next 3 days?")
+	require.NoError(t, err)
+	require.Equal(t, "It will be sunny in Paris.", result)
+	require.Equal(t, forecastArgs{City: "Paris", Days: 3}, got)
+
+	calls := llm.Calls()
+	prompt := calls[0].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, `- forecast: Returns the weather forecast of a city., args: {"city":{"type":"string","description":"The name of the city.","properties":{}},"days":{"type":"integer","properties":{}}}`) //nolint:lll
+	require.Contains(t, prompt, `- calculator: `)
+	require.Contains(t, prompt, `Valid "action" values: "Final Answer" or "forecast", "calculator"`)
+	prompt = calls[1].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "\nObservation: sunny for 3 days\nThought:")
+}
+
+func TestStructuredChatAgentStringInput(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		fake.Response{Content: `Action: {"action": "calculator", "action_input": {"input": "2*21"}}`},
+		fake.Response{Content: `Action: {"action": "Final Answer", "action_input": {"answer": 42}}`},
+	)
+	agentTools := []tools.Tool{tools.Calculator{}}
+	executor := agents.NewExecutor(agents.NewStructuredChatAgent(llm, agentTools), agentTools,
+		agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is 2*21?"})
+	require.NoError(t, err)
+	require.Equal(t, `{"answer":42}`, result["output"])
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "\nObservation: 42\nThought:")
+}
@@ -0,0 +1,404 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_supervisorInputKey       = "input"
+	_defaultSupervisorRounds  = 10
+	_workerTaskParameter      = "task"
+	_handoffToolName          = "handoff"
+	_handoffWorkerParameter   = "worker"
+	_defaultSupervisorMessage = "You are a supervisor managing a team of workers. " +
+		"Route the request of the user to the workers best suited to each part of it, one task at a time, " +
+		"by calling them with a precise task. When the work is done, answer the user with the final answer, " +
+		"combining the results of the workers, without calling any worker."
+)
+
+//nolint:lll
+const _defaultWorkerTaskTemplate = `{{if .history}}Work done so far by the team:
+{{.history}}
+
+{{end}}Your task: {{.task}}`
+
+// Worker is a named agent of a Supervisor, e.g. an Executor with its own
+// model and tools. Its chain has a single input, the task, and a single
+// output, the result.
+type Worker struct {
+	Name string
+	// Description tells the supervisor what the worker is good at.
+	Description string
+	Chain       chains.Chain
+}
+
+// WorkerTurn is a task executed by a worker of a Supervisor.
+type WorkerTurn struct {
+	Worker string
+	Task   string
+	Result string
+	// HandoffFrom is the worker which handed the task off, if the task wasn't
+	// given by the supervisor.
+	HandoffFrom string
+}
+
+// Supervisor is a multi-agent chain where a supervisor model routes the tasks
+// of a request among named workers, by calling them as tools, and answers
+// with the final answer once the work is done. Workers may hand tasks off
+// directly to other workers with a HandoffTool.
+//
+// With SharedMemory, the workers are given the tasks and results of all the
+// workers before them, otherwise only their own.
+type Supervisor struct {
+	LLM     llms.Model
+	Workers []Worker
+	// Prompt is the prompt of the supervisor, followed by the messages of
+	// the worker calls and results.
+	Prompt prompts.FormatPrompter
+	// TaskPrompt is the input of the workers, formatted with the "task" and
+	// the "history" of the previous turns visible to the worker.
+	TaskPrompt   prompts.FormatPrompter
+	SharedMemory bool
+	// MaxRounds is the maximum number of worker turns.
+	MaxRounds int
+
+	OutputKey               string
+	ReturnIntermediateSteps bool
+	CallbacksHandler        callbacks.Handler
+}
+
+var (
+	_ chains.Chain           = &Supervisor{}
+	_ callbacks.HandlerHaver = &Supervisor{}
+)
+
+// NewSupervisor creates a new Supervisor routing tasks with the model among
+// the workers, whose memory is shared. The system message of the supervisor
+// is set with the options of NewOpenAIOption, and its max number of worker
+// turns with WithMaxIterations. It returns an error if the names of the
+// workers are empty or not unique.
+func NewSupervisor(llm llms.Model, workers []Worker, opts ...Option) (*Supervisor, error) {
+	options := supervisorDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	seen := make(map[string]bool, len(workers))
+	for _, worker := range workers {
+		if worker.Name == "" || seen[worker.Name] || worker.Name == _handoffToolName {
+			return nil, fmt.Errorf("%w: invalid or duplicate worker name %q", ErrInvalidOptions, worker.Name)
+		}
+		seen[worker.Name] = true
+	}
+
+	return &Supervisor{
+		LLM:                     llm,
+		Workers:                 workers,
+		Prompt:                  createToolCallingPrompt(options),
+		TaskPrompt:              prompts.NewPromptTemplate(_defaultWorkerTaskTemplate, []string{"task", "history"}),
+		SharedMemory:            !options.isolatedMemory,
+		MaxRounds:               options.maxIterations,
+		OutputKey:               options.outputKey,
+		ReturnIntermediateSteps: options.returnIntermediateSteps,
+		CallbacksHandler:        options.callbacksHandler,
+	}, nil
+}
+
+// Call routes the tasks of the input among the workers until the supervisor
+// answers with the final answer.
+func (s *Supervisor) Call(ctx context.Context, values map[string]any, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll,cyclop
+	input, ok := values[_supervisorInputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrExecutorInputNotString, _supervisorInputKey)
+	}
+	prompt, err := s.Prompt.FormatPrompt(map[string]any{_supervisorInputKey: input})
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]llms.MessageContent, 0, len(prompt.Messages()))
+	for _, msg := range prompt.Messages() {
+		messages = append(messages, llms.TextParts(msg.GetType(), msg.GetContent()))
+	}
+
+	var turns []WorkerTurn
+	var handoff *Handoff
+	for len(turns) < s.MaxRounds {
+		var calls []llms.ToolCall
+		if handoff != nil {
+			// The handoff is given to the supervisor as its own call.
+			arguments, err := json.Marshal(map[string]string{_workerTaskParameter: handoff.Task})
+			if err != nil {
+				return nil, err
+			}
+			calls = []llms.ToolCall{{
+				ID:           fmt.Sprintf("handoff_%d", len(turns)),
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: handoff.Worker, Arguments: string(arguments)},
+			}}
+		} else {
+			resp, err := s.LLM.GenerateContent(ctx, messages, llms.WithTools(s.workerTools()))
+			if err != nil {
+				return nil, err
+			}
+			llms.RecordUsage(ctx, resp)
+			if len(resp.Choices) == 0 {
+				return nil, fmt.Errorf("%w: no choices", ErrUnableToParseOutput)
+			}
+			choice := resp.Choices[0]
+			for _, call := range choice.ToolCalls {
+				if call.FunctionCall != nil {
+					calls = append(calls, call)
+				}
+			}
+			if len(calls) == 0 {
+				return s.finish(ctx, choice.Content, turns), nil
+			}
+		}
+
+		message := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+		for _, call := range calls {
+			message.Parts = append(message.Parts, call)
+		}
+		messages = append(messages, message)
+
+		from := ""
+		if handoff != nil {
+			from = handoff.From
+		}
+		handoff = nil
+		for _, call := range calls {
+			turn := WorkerTurn{Worker: call.FunctionCall.Name, HandoffFrom: from}
+			turn.Result, handoff, err = s.callWorker(ctx, call.FunctionCall, turns, &turn, options)
+			if err != nil {
+				return nil, err
+			}
+			turns = append(turns, turn)
+			messages = append(messages, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: call.ID,
+					Name:       call.FunctionCall.Name,
+					Content:    turn.Result,
+				}},
+			})
+			if handoff != nil {
+				// The other calls of the supervisor are given up for the
+				// handoff.
+				break
+			}
+		}
+		messages = answerSkippedCalls(messages, calls)
+	}
+
+	return nil, ErrNotFinished
+}
+
+// callWorker calls the worker of the function call, and returns its result
+// and its handoff. Calls of unknown workers are answered with an error
+// message for the supervisor.
+func (s *Supervisor) callWorker(
+	ctx context.Context,
+	call *llms.FunctionCall,
+	turns []WorkerTurn,
+	turn *WorkerTurn,
+	options []chains.ChainCallOption,
+) (string, *Handoff, error) {
+	var arguments map[string]any
+	_ = json.Unmarshal([]byte(call.Arguments), &arguments)
+	turn.Task, _ = arguments[_workerTaskParameter].(string)
+
+	i := slices.IndexFunc(s.Workers, func(w Worker) bool { return w.Name == call.Name })
+	if i < 0 {
+		return fmt.Sprintf("%s is not a valid worker, try another one", call.Name), nil, nil
+	}
+	worker := s.Workers[i]
+
+	if s.CallbacksHandler != nil {
+		s.CallbacksHandler.HandleAgentAction(ctx, schema.AgentAction{
+			Tool:      worker.Name,
+			ToolInput: turn.Task,
+			Log:       fmt.Sprintf("Routing to %s: %s", worker.Name, turn.Task),
+		})
+	}
+
+	history := make([]string, 0, len(turns))
+	for _, previous := range turns {
+		if s.SharedMemory || previous.Worker == worker.Name {
+			history = append(history, fmt.Sprintf("%s: %s\nResult: %s", previous.Worker, previous.Task, previous.Result))
+		}
+	}
+	input, err := s.TaskPrompt.FormatPrompt(map[string]any{
+		"task":    turn.Task,
+		"history": strings.Join(history, "\n\n"),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	recorder := &handoffRecorder{from: worker.Name}
+	result, err := chains.Run(context.WithValue(ctx, handoffKey{}, recorder), worker.Chain, input.String(), options...)
+	if err != nil {
+		return "", nil, fmt.Errorf("worker %s: %w", worker.Name, err)
+	}
+	return result, recorder.handoff, nil
+}
+
+// answerSkippedCalls answers the calls of the last supervisor message left
+// unanswered because of a handoff, as models require all the calls to be
+// answered.
+func answerSkippedCalls(messages []llms.MessageContent, calls []llms.ToolCall) []llms.MessageContent {
+	answered := make(map[string]bool, len(calls))
+	for _, msg := range messages[len(messages)-len(calls):] {
+		for _, part := range msg.Parts {
+			if response, ok := part.(llms.ToolCallResponse); ok {
+				answered[response.ToolCallID] = true
+			}
+		}
+	}
+	for _, call := range calls {
+		if !answered[call.ID] {
+			messages = append(messages, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: call.ID,
+					Name:       call.FunctionCall.Name,
+					Content:    "Skipped because of a handoff.",
+				}},
+			})
+		}
+	}
+	return messages
+}
+
+func (s *Supervisor) finish(ctx context.Context, answer string, turns []WorkerTurn) map[string]any {
+	outputs := map[string]any{s.OutputKey: answer}
+	if s.CallbacksHandler != nil {
+		s.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{ReturnValues: outputs, Log: answer})
+	}
+	if s.ReturnIntermediateSteps {
+		outputs[_intermediateStepsOutputKey] = turns
+	}
+	return outputs
+}
+
+// workerTools returns the definitions of the workers as tools of the
+// supervisor.
+func (s *Supervisor) workerTools() []llms.Tool {
+	definitions := make([]llms.Tool, 0, len(s.Workers))
+	for _, worker := range s.Workers {
+		definitions = append(definitions, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        worker.Name,
+				Description: worker.Description,
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						_workerTaskParameter: map[string]any{
+							"type":        "string",
+							"description": "The task given to the worker, with all the information it needs.",
+						},
+					},
+					"required": []string{_workerTaskParameter},
+				},
+			},
+		})
+	}
+	return definitions
+}
+
+func (s *Supervisor) GetInputKeys() []string {
+	return []string{_supervisorInputKey}
+}
+
+func (s *Supervisor) GetOutputKeys() []string {
+	return []string{s.OutputKey}
+}
+
+func (s *Supervisor) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (s *Supervisor) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return s.CallbacksHandler
+}
+
+// Handoff is a task handed off by a worker of a Supervisor to another worker.
+type Handoff struct {
+	From   string
+	Worker string
+	Task   string
+}
+
+type handoffKey struct{}
+
+type handoffRecorder struct {
+	from    string
+	handoff *Handoff
+}
+
+// HandoffTool is a tool letting the workers of a Supervisor hand a task off
+// directly to another worker, which is called next instead of asking the
+// supervisor.
+type HandoffTool struct {
+	// Workers are the names of the workers the task can be handed off to.
+	Workers []string
+}
+
+var _ tools.StructuredTool = HandoffTool{}
+
+func (t HandoffTool) Name() string {
+	return _handoffToolName
+}
+
+func (t HandoffTool) Description() string {
+	return "Hands a task off to another worker of the team: " + strings.Join(t.Workers, ", ") + "."
+}
+
+func (t HandoffTool) Parameters() any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			_handoffWorkerParameter: map[string]any{"type": "string", "enum": t.Workers},
+			_workerTaskParameter: map[string]any{
+				"type":        "string",
+				"description": "The task given to the worker, with all the information it needs.",
+			},
+		},
+		"required": []string{_handoffWorkerParameter, _workerTaskParameter},
+	}
+}
+
+// Call records the handoff for the supervisor. Errors are given in the result
+// to give the agent the ability to retry.
+func (t HandoffTool) Call(ctx context.Context, input string) (string, error) {
+	recorder, ok := ctx.Value(handoffKey{}).(*handoffRecorder)
+	if !ok {
+		return "Handoffs are only available to the workers of a supervisor.", nil
+	}
+	var arguments struct {
+		Worker string `json:"worker"`
+		Task   string `json:"task"`
+	}
+	if err := json.Unmarshal([]byte(input), &arguments); err != nil {
+		return fmt.Sprintf("invalid arguments for %s: %s", _handoffToolName, err.Error()), nil //nolint:nilerr
+	}
+	if !slices.Contains(t.Workers, arguments.Worker) {
+		return fmt.Sprintf("%s is not a valid worker, try another one", arguments.Worker), nil
+	}
+
+	recorder.handoff = &Handoff{From: recorder.from, Worker: arguments.Worker, Task: arguments.Task}
+	return fmt.Sprintf("The task was handed off to %s. Finish now with a summary of your work.", arguments.Worker), nil
+}
@@ -0,0 +1,91 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+
+	for _, shared := range []bool{true, false} {
+		supervisorLLM := fake.New(
+			toolCallResponse("call_1", "researcher", `{"task": "Find the population of Paris."}`),
+			fake.Response{Content: "Paris has 2 million inhabitants."},
+		)
+		researcherLLM := fake.New(
+			toolCallResponse("call_r", "handoff", `{"worker": "writer", "task": "Write a sentence about the population."}`),
+			fake.Response{Content: "Paris has about 2 million people, handed off to the writer."},
+		)
+		writerLLM := fake.New(fake.Response{Content: "Paris is home to 2 million inhabitants."})
+
+		researcherTools := []tools.Tool{agents.HandoffTool{Workers: []string{"writer"}}}
+		workers := []agents.Worker{
+			{
+				Name:        "researcher",
+				Description: "Finds facts.",
+				Chain:       agents.NewExecutor(agents.NewToolCallingAgent(researcherLLM, researcherTools), researcherTools),
+			},
+			{
+				Name:        "writer",
+				Description: "Writes texts.",
+				Chain:       agents.NewExecutor(agents.NewToolCallingAgent(writerLLM, nil), nil),
+			},
+		}
+		opts := []agents.Option{agents.WithReturnIntermediateSteps()}
+		if !shared {
+			opts = append(opts, agents.WithIsolatedMemory())
+		}
+		supervisor, err := agents.NewSupervisor(supervisorLLM, workers, opts...)
+		require.NoError(t, err)
+
+		result, err := chains.Call(context.Background(), supervisor, map[string]any{"input": "How many people live in Paris?"})
+		require.NoError(t, err)
+		require.Equal(t, "Paris has 2 million inhabitants.", result["output"])
+		require.Equal(t, []agents.WorkerTurn{
+			{
+				Worker: "researcher",
+				Task:   "Find the population of Paris.",
+				Result: "Paris has about 2 million people, handed off to the writer.",
+			},
+			{
+				Worker:      "writer",
+				Task:        "Write a sentence about the population.",
+				Result:      "Paris is home to 2 million inhabitants.",
+				HandoffFrom: "researcher",
+			},
+		}, result["intermediateSteps"])
+
+		// The writer is called directly by the handoff, and sees the work of
+		// the researcher only with shared memory.
+		require.Len(t, supervisorLLM.Calls(), 2)
+		writerInput := writerLLM.LastCall().Messages[1].Parts[0].(llms.TextContent).Text
+		require.Equal(t, shared, writerInput != "Your task: Write a sentence about the population.")
+
+		// The handoff is given to the supervisor as its call of the writer.
+		messages := supervisorLLM.LastCall().Messages
+		require.Len(t, messages, 6)
+		call, ok := messages[4].Parts[0].(llms.ToolCall)
+		require.True(t, ok)
+		require.Equal(t, "writer", call.FunctionCall.Name)
+		require.Equal(t, llms.ToolCallResponse{
+			ToolCallID: call.ID,
+			Name:       "writer",
+			Content:    "Paris is home to 2 million inhabitants.",
+		}, messages[5].Parts[0])
+	}
+}
+
+func TestNewSupervisorDuplicateWorkers(t *testing.T) {
+	t.Parallel()
+
+	_, err := agents.NewSupervisor(fake.New(), []agents.Worker{{Name: "a"}, {Name: "a"}})
+	require.ErrorIs(t, err, agents.ErrInvalidOptions)
+}
@@ -0,0 +1,73 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrAgentTimeout is returned, wrapped in a TimeoutError, when a timeout of
+// the executor expires.
+var ErrAgentTimeout = errors.New("agent timed out")
+
+// TimeoutStage is the part of the run of an executor whose timeout expired.
+type TimeoutStage string
+
+const (
+	// TimeoutRun is the whole run of the executor.
+	TimeoutRun TimeoutStage = "run"
+	// TimeoutLLMCall is a call of the agent to its model.
+	TimeoutLLMCall TimeoutStage = "llm call"
+	// TimeoutTool is a tool call.
+	TimeoutTool TimeoutStage = "tool"
+)
+
+// TimeoutError is returned by an executor when one of its timeouts expires.
+// It wraps ErrAgentTimeout and the error of the interrupted call.
+type TimeoutError struct {
+	Stage TimeoutStage
+	// Tool is the name of the tool of tool timeouts.
+	Tool    string
+	Timeout time.Duration
+	// Steps are the steps completed before the timeout, i.e. the partial
+	// scratchpad of the agent.
+	Steps []schema.AgentStep
+	// Err is the error of the interrupted call.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	stage := string(e.Stage)
+	if e.Tool != "" {
+		stage += " " + e.Tool
+	}
+	msg := fmt.Sprintf("%s: %s exceeded %s after %d steps", ErrAgentTimeout, stage, e.Timeout, len(e.Steps))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *TimeoutError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrAgentTimeout}
+	}
+	return []error{ErrAgentTimeout, e.Err}
+}
+
+// withTimeout returns the context with the timeout, if positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut returns whether the deadline of the context derived from parent
+// with the timeout expired, while the parent is still live.
+func timedOut(parent, ctx context.Context, timeout time.Duration) bool {
+	return timeout > 0 && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
@@ -0,0 +1,101 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type blockingTool struct{}
+
+func (blockingTool) Name() string        { return "wait" }
+func (blockingTool) Description() string { return "Waits forever." }
+func (blockingTool) Call(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestExecutorTimeouts(t *testing.T) {
+	t.Parallel()
+
+	calculatorCall := toolCallResponse("call_1", "calculator", `{"input": "1+1"}`)
+	slowCall := toolCallResponse("call_2", "calculator", `{"input": "2+2"}`)
+	slowCall.Delay = time.Second
+
+	tests := []struct {
+		name      string
+		responses []fake.Response
+		opts      []agents.Option
+		stage     agents.TimeoutStage
+		tool      string
+		steps     int
+	}{
+		{
+			name:      "llm call",
+			responses: []fake.Response{calculatorCall, slowCall},
+			opts:      []agents.Option{agents.WithLLMCallTimeout(20 * time.Millisecond)},
+			stage:     agents.TimeoutLLMCall,
+			steps:     1,
+		},
+		{
+			name:      "tool",
+			responses: []fake.Response{calculatorCall, toolCallResponse("call_2", "wait", `{"input": ""}`)},
+			opts:      []agents.Option{agents.WithToolTimeout(20 * time.Millisecond)},
+			stage:     agents.TimeoutTool,
+			tool:      "wait",
+			steps:     1,
+		},
+		{
+			name:      "run",
+			responses: []fake.Response{calculatorCall, slowCall},
+			opts:      []agents.Option{agents.WithTimeout(50 * time.Millisecond), agents.WithMaxIterations(10)},
+			stage:     agents.TimeoutRun,
+			steps:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := fake.New(tt.responses...)
+			agentTools := []tools.Tool{tools.Calculator{}, blockingTool{}}
+			executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools, tt.opts...)
+
+			_, err := chains.Run(context.Background(), executor, "Compute.")
+			require.ErrorIs(t, err, agents.ErrAgentTimeout)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			var timeoutErr *agents.TimeoutError
+			require.True(t, errors.As(err, &timeoutErr))
+			require.Equal(t, tt.stage, timeoutErr.Stage)
+			require.Equal(t, tt.tool, timeoutErr.Tool)
+			require.Len(t, timeoutErr.Steps, tt.steps)
+			require.Equal(t, "2", timeoutErr.Steps[0].Observation)
+		})
+	}
+}
+
+func TestExecutorTimeoutCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	slowCall := toolCallResponse("call_1", "calculator", `{"input": "1+1"}`)
+	slowCall.Delay = time.Second
+	llm := fake.New(slowCall)
+	agentTools := []tools.Tool{tools.Calculator{}}
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithLLMCallTimeout(time.Second), agents.WithTimeout(time.Second))
+
+	// The cancellation of the caller isn't a timeout of the executor.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := chains.Run(ctx, executor, "Compute.")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, agents.ErrAgentTimeout)
+}
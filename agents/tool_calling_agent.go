@@ -0,0 +1,256 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// _toolInputParameter is the parameter of the tools without JSON schema
+// parameters.
+const _toolInputParameter = "input"
+
+// ToolCallingAgent is an Agent using the native tool calling of the model,
+// supported by the OpenAI, Anthropic, Gemini and Mistral models among
+// others, instead of parsing the text of the model. Several tools can be
+// called at once, and the conversation of tool calls and tool responses is
+// given back to the model as structured messages.
+//
+// Tools implementing tools.StructuredTool are called with the JSON arguments
+// of the model. Other tools have a single string "input" parameter. With
+// WithFinalAnswer, the final answer is a JSON value validated against a
+// schema instead of free text.
+type ToolCallingAgent struct {
+	// LLM is the model, which must support tool calling.
+	LLM llms.Model
+	// Prompt is the prompt of the conversation, followed by the messages of
+	// the tool calls and responses.
+	Prompt prompts.FormatPrompter
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+	// FinalAnswerSchema, if set, is the schema of the final answer, given by
+	// the model as the JSON arguments of the final_answer tool instead of
+	// text.
+	FinalAnswerSchema *jsonschema.Definition
+}
+
+var _ Agent = (*ToolCallingAgent)(nil)
+
+// NewToolCallingAgent creates a new ToolCallingAgent. The system message and
+// the extra messages of the prompt are set with the options of
+// NewOpenAIOption.
+func NewToolCallingAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ToolCallingAgent {
+	options := toolCallingDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolCallingAgent{
+		LLM:               llm,
+		Prompt:            createToolCallingPrompt(options),
+		Tools:             tools,
+		OutputKey:         options.outputKey,
+		CallbacksHandler:  options.callbacksHandler,
+		FinalAnswerSchema: options.finalAnswerSchema,
+	}
+}
+
+// Plan asks the model for the tool calls to make, or the final answer when it
+// calls no tool.
+func (a *ToolCallingAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	promptValues := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		promptValues[key] = value
+	}
+	prompt, err := a.Prompt.FormatPrompt(promptValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]llms.MessageContent, 0, len(prompt.Messages())+len(intermediateSteps))
+	for _, msg := range prompt.Messages() {
+		messages = append(messages, llms.TextParts(msg.GetType(), msg.GetContent()))
+	}
+	messages = append(messages, a.constructScratchPad(intermediateSteps)...)
+
+	options := []llms.CallOption{llms.WithTools(a.tools())}
+	if a.CallbacksHandler != nil {
+		options = append(options, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
+			return nil
+		}))
+	}
+	resp, err := a.LLM.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	llms.RecordUsage(ctx, resp)
+
+	return a.ParseOutput(resp)
+}
+
+// ParseOutput returns an action for each tool call of the response, or the
+// finish if it has none. With a final answer schema, the finish is given by
+// the final_answer call instead.
+func (a *ToolCallingAgent) ParseOutput(resp *llms.ContentResponse) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if len(resp.Choices) == 0 {
+		return nil, nil, fmt.Errorf("%w: no choices", ErrUnableToParseOutput)
+	}
+	choice := resp.Choices[0]
+
+	if a.FinalAnswerSchema != nil {
+		if call := finalAnswerCall(choice.ToolCalls); call != nil {
+			return a.parseFinalAnswer(call, choice.Content)
+		}
+		if len(choice.ToolCalls) == 0 {
+			return nil, nil, fmt.Errorf("%w: the final answer must be given by calling %s",
+				ErrUnableToParseOutput, FinalAnswerToolName)
+		}
+	}
+
+	if len(choice.ToolCalls) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: choice.Content},
+			Log:          choice.Content,
+		}, nil
+	}
+
+	// The message is shared by the actions, to be given back to the model
+	// once with all its tool calls.
+	message := &llms.MessageContent{Role: llms.ChatMessageTypeAI}
+	if choice.Content != "" {
+		message.Parts = append(message.Parts, llms.TextPart(choice.Content))
+	}
+	actions := make([]schema.AgentAction, 0, len(choice.ToolCalls))
+	for _, call := range choice.ToolCalls {
+		if call.FunctionCall == nil {
+			continue
+		}
+		message.Parts = append(message.Parts, call)
+		actions = append(actions, schema.AgentAction{
+			Tool:      call.FunctionCall.Name,
+			ToolInput: a.toolInput(call.FunctionCall),
+			Log:       fmt.Sprintf("Invoking: %s with %s\n%s", call.FunctionCall.Name, call.FunctionCall.Arguments, choice.Content),
+			ToolID:    call.ID,
+			Message:   message,
+		})
+	}
+	if len(actions) == 0 {
+		return nil, nil, fmt.Errorf("%w: no function call", ErrUnableToParseOutput)
+	}
+	return actions, nil, nil
+}
+
+// toolInput returns the input of the tool called: the JSON arguments for
+// structured tools, and the input parameter for others.
+func (a *ToolCallingAgent) toolInput(call *llms.FunctionCall) string {
+	for _, tool := range a.Tools {
+		if _, ok := tool.(tools.StructuredTool); ok && tool.Name() == call.Name {
+			return call.Arguments
+		}
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(call.Arguments), &arguments); err != nil {
+		return call.Arguments
+	}
+	if input, ok := arguments[_toolInputParameter].(string); ok {
+		return input
+	}
+	return call.Arguments
+}
+
+// tools returns the definitions of the tools.
+func (a *ToolCallingAgent) tools() []llms.Tool {
+	definitions := make([]llms.Tool, 0, len(a.Tools))
+	for _, tool := range a.Tools {
+		var parameters any = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				_toolInputParameter: map[string]any{"type": "string", "description": "The input of the tool."},
+			},
+			"required": []string{_toolInputParameter},
+		}
+		if structured, ok := tool.(tools.StructuredTool); ok {
+			parameters = structured.Parameters()
+		}
+		definitions = append(definitions, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  parameters,
+			},
+		})
+	}
+	if a.FinalAnswerSchema != nil {
+		definitions = append(definitions, a.finalAnswerTool())
+	}
+	return definitions
+}
+
+// constructScratchPad returns the messages of the tool calls of the steps,
+// each followed by the tool responses.
+func (a *ToolCallingAgent) constructScratchPad(steps []schema.AgentStep) []llms.MessageContent {
+	messages := make([]llms.MessageContent, 0, 2*len(steps)) //nolint:mnd
+	for i, step := range steps {
+		if step.Action.Message != nil && (i == 0 || steps[i-1].Action.Message != step.Action.Message) {
+			messages = append(messages, *step.Action.Message)
+		}
+		if step.Action.ToolID == "" {
+			// Steps without tool call, e.g. of parsing errors, are given as
+			// human messages.
+			messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, step.Observation))
+			continue
+		}
+		messages = append(messages, llms.MessageContent{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: step.Action.ToolID,
+				Name:       step.Action.Tool,
+				Content:    step.Observation,
+			}},
+		})
+	}
+	return messages
+}
+
+func (a *ToolCallingAgent) GetInputKeys() []string {
+	chainInputs := a.Prompt.GetInputVariables()
+
+	// Remove inputs given in plan.
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == agentScratchpad {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+func (a *ToolCallingAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func createToolCallingPrompt(opts Options) prompts.ChatPromptTemplate {
+	messageFormatters := []prompts.MessageFormatter{prompts.NewSystemMessagePromptTemplate(opts.systemMessage, nil)}
+	messageFormatters = append(messageFormatters, opts.extraMessages...)
+	messageFormatters = append(messageFormatters, prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}))
+	return prompts.NewChatPromptTemplate(messageFormatters)
+}
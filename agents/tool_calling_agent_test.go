@@ -0,0 +1,76 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type weatherTool struct{}
+
+var _ tools.StructuredTool = weatherTool{}
+
+func (weatherTool) Name() string        { return "weather" }
+func (weatherTool) Description() string { return "Returns the weather of a city." }
+func (weatherTool) Parameters() any {
+	return map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		"required":   []string{"city"},
+	}
+}
+
+func (weatherTool) Call(_ context.Context, input string) (string, error) {
+	return "sunny, input " + input, nil
+}
+
+func TestToolCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		fake.Response{
+			Content: "Let me check.",
+			ToolCalls: []llms.ToolCall{
+				{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"input": "2+2"}`}},
+				{ID: "call_2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city": "Paris"}`}},
+			},
+		},
+		fake.Response{Content: "2+2 is 4 and Paris is sunny."},
+	)
+	agentTools := []tools.Tool{tools.Calculator{}, weatherTool{}}
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, agentTools), agentTools,
+		agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Add 2 and 2, and what's the weather in Paris?"}) //nolint:lll
+	require.NoError(t, err)
+	require.Equal(t, "2+2 is 4 and Paris is sunny.", result["output"])
+
+	calls := llm.Calls()
+	require.Len(t, calls, 2)
+	tool := calls[0].Options.Tools[1].Function
+	require.Equal(t, "weather", tool.Name)
+	require.Equal(t, weatherTool{}.Parameters(), tool.Parameters)
+
+	// The tool calls are given back in a single message, followed by the
+	// responses.
+	messages := calls[1].Messages
+	require.Len(t, messages, 5)
+	require.Equal(t, llms.ChatMessageTypeAI, messages[2].Role)
+	require.Len(t, messages[2].Parts, 3)
+	require.Equal(t, llms.MessageContent{
+		Role:  llms.ChatMessageTypeTool,
+		Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_1", Name: "calculator", Content: "4"}},
+	}, messages[3])
+	require.Equal(t, llms.MessageContent{
+		Role: llms.ChatMessageTypeTool,
+		Parts: []llms.ContentPart{llms.ToolCallResponse{
+			ToolCallID: "call_2", Name: "weather", Content: `sunny, input {"city": "Paris"}`,
+		}},
+	}, messages[4])
+}
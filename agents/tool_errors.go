@@ -0,0 +1,196 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrToolRetriesExceeded is returned when a tool with the ToolErrorRetry
+// policy fails more times in a run than the executor allows.
+var ErrToolRetriesExceeded = errors.New("tool retries exceeded")
+
+// ToolErrorPolicy is what the executor does when a tool returns an error.
+type ToolErrorPolicy string
+
+const (
+	// ToolErrorAbort aborts the run with the error of the tool. It is the
+	// default policy.
+	ToolErrorAbort ToolErrorPolicy = "abort"
+	// ToolErrorRetry gives the error back to the model as the observation,
+	// for it to fix the input of the tool and call it again, until the tool
+	// fails more than MaxToolRetries times in the run.
+	ToolErrorRetry ToolErrorPolicy = "retry"
+	// ToolErrorSkip gives the error back to the model as the observation and
+	// makes the tool unavailable for the rest of the run, its later calls
+	// not being executed.
+	ToolErrorSkip ToolErrorPolicy = "skip"
+)
+
+// toolErrorObservation is the observation of a failed tool call, encoded as
+// JSON for the model to tell it apart from a result.
+type toolErrorObservation struct {
+	Tool        string `json:"tool"`
+	Error       string `json:"error"`
+	RetriesLeft *int   `json:"retries_left,omitempty"`
+	Hint        string `json:"hint"`
+}
+
+func (o toolErrorObservation) String() string {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return o.Error
+	}
+	return string(data)
+}
+
+// runState is the state of a run of an executor, shared by its iterations
+// and tool calls.
+type runState struct {
+	mu         sync.Mutex
+	toolErrors map[string]int
+	skipped    map[string]bool
+	toolCalls  int
+	usage      *llms.UsageTracker
+}
+
+func newRunState() *runState {
+	return &runState{
+		toolErrors: map[string]int{},
+		skipped:    map[string]bool{},
+		usage:      llms.NewUsageTracker(),
+	}
+}
+
+func (s *runState) addToolCalls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolCalls += n
+}
+
+func (s *runState) toolCallCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.toolCalls
+}
+
+// addToolError records a failure of the tool and returns its failures in the
+// run.
+func (s *runState) addToolError(tool string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolErrors[strings.ToUpper(tool)]++
+	return s.toolErrors[strings.ToUpper(tool)]
+}
+
+func (s *runState) skipTool(tool string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped[strings.ToUpper(tool)] = true
+}
+
+func (s *runState) isSkipped(tool string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skipped[strings.ToUpper(tool)]
+}
+
+// snapshot stores the tool error counters, tool calls and token usage of the
+// run in the checkpoint.
+func (s *runState) snapshot(checkpoint *Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.toolErrors) > 0 {
+		checkpoint.ToolErrors = maps.Clone(s.toolErrors)
+	}
+	for tool := range s.skipped {
+		checkpoint.SkippedTools = append(checkpoint.SkippedTools, tool)
+	}
+	slices.Sort(checkpoint.SkippedTools)
+	checkpoint.ToolCalls = s.toolCalls
+	checkpoint.Usage = s.usage.Usage()
+}
+
+// restore restores the tool error counters, tool calls and token usage of the
+// run from the checkpoint. The usage of the interrupted run is added to the
+// tracker as a single call.
+func (s *runState) restore(checkpoint Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for tool, failures := range checkpoint.ToolErrors {
+		s.toolErrors[strings.ToUpper(tool)] = failures
+	}
+	for _, tool := range checkpoint.SkippedTools {
+		s.skipped[strings.ToUpper(tool)] = true
+	}
+	s.toolCalls = checkpoint.ToolCalls
+	if checkpoint.Usage != (llms.Usage{}) {
+		s.usage.Add(checkpoint.Usage)
+	}
+}
+
+// toolErrorPolicy returns the policy of the errors of the tool.
+func (e *Executor) toolErrorPolicy(tool string) ToolErrorPolicy {
+	for name, policy := range e.ToolErrorPolicies {
+		if strings.EqualFold(name, tool) {
+			return policy
+		}
+	}
+	if e.DefaultToolErrorPolicy == "" {
+		return ToolErrorAbort
+	}
+	return e.DefaultToolErrorPolicy
+}
+
+// handleToolError applies the policy of the tool to its error, returning the
+// step giving the error to the model, or the error aborting the run. The
+// errors of a canceled run always abort it.
+func (e *Executor) handleToolError(
+	ctx context.Context,
+	run *runState,
+	action schema.AgentAction,
+	err error,
+) (schema.AgentStep, error) {
+	if ctx.Err() != nil {
+		return schema.AgentStep{}, err
+	}
+
+	observation := toolErrorObservation{Tool: action.Tool, Error: err.Error()}
+	switch e.toolErrorPolicy(action.Tool) {
+	case ToolErrorRetry:
+		failures := run.addToolError(action.Tool)
+		if failures > e.MaxToolRetries {
+			return schema.AgentStep{}, fmt.Errorf("%w: %s failed %d times: %w", ErrToolRetriesExceeded, action.Tool, failures, err)
+		}
+		retriesLeft := e.MaxToolRetries - failures
+		observation.RetriesLeft = &retriesLeft
+		observation.Hint = "Fix the input and call the tool again, or use another tool."
+	case ToolErrorSkip:
+		run.skipTool(action.Tool)
+		observation.Hint = "The tool is unavailable for the rest of the task, use another tool."
+	default:
+		return schema.AgentStep{}, err
+	}
+	return schema.AgentStep{Action: action, Observation: observation.String()}, nil
+}
+
+// skippedToolStep returns the step of a call of a tool made unavailable by
+// the ToolErrorSkip policy.
+func skippedToolStep(action schema.AgentAction) schema.AgentStep {
+	return schema.AgentStep{
+		Action: action,
+		Observation: toolErrorObservation{
+			Tool:  action.Tool,
+			Error: "the tool is unavailable after a previous error",
+			Hint:  "Use another tool.",
+		}.String(),
+	}
+}
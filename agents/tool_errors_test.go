@@ -0,0 +1,103 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type countingFailingTool struct {
+	calls atomic.Int32
+}
+
+func (*countingFailingTool) Name() string        { return "lookup" }
+func (*countingFailingTool) Description() string { return "Looks up facts." }
+func (t *countingFailingTool) Call(context.Context, string) (string, error) {
+	t.calls.Add(1)
+	return "", errors.New("lookup unavailable")
+}
+
+func TestExecutorToolErrorPolicies(t *testing.T) {
+	t.Parallel()
+
+	lookupCall := func(id string) fake.Response {
+		return toolCallResponse(id, "lookup", `{"input": "population of Paris"}`)
+	}
+
+	tests := []struct {
+		name         string
+		responses    []fake.Response
+		opts         []agents.Option
+		err          error
+		calls        int32
+		observations []string
+	}{
+		{
+			name:      "abort",
+			responses: []fake.Response{lookupCall("call_1")},
+			err:       errors.New("lookup unavailable"),
+			calls:     1,
+		},
+		{
+			name:      "retry",
+			responses: []fake.Response{lookupCall("call_1"), lookupCall("call_2"), {Content: "I couldn't find it."}},
+			opts:      []agents.Option{agents.WithToolErrorPolicy(agents.ToolErrorRetry, "lookup")},
+			calls:     2,
+			observations: []string{
+				`{"tool":"lookup","error":"lookup unavailable","retries_left":1,"hint":"Fix the input and call the tool again, or use another tool."}`, //nolint:lll
+				`{"tool":"lookup","error":"lookup unavailable","retries_left":0,"hint":"Fix the input and call the tool again, or use another tool."}`, //nolint:lll
+			},
+		},
+		{
+			name:      "retries exceeded",
+			responses: []fake.Response{lookupCall("call_1"), lookupCall("call_2")},
+			opts:      []agents.Option{agents.WithToolErrorPolicy(agents.ToolErrorRetry), agents.WithMaxToolRetries(1)},
+			err:       agents.ErrToolRetriesExceeded,
+			calls:     2,
+		},
+		{
+			name:      "skip",
+			responses: []fake.Response{lookupCall("call_1"), lookupCall("call_2"), {Content: "I couldn't find it."}},
+			opts:      []agents.Option{agents.WithToolErrorPolicy(agents.ToolErrorSkip, "LOOKUP")},
+			calls:     1,
+			observations: []string{
+				`{"tool":"lookup","error":"lookup unavailable","hint":"The tool is unavailable for the rest of the task, use another tool."}`, //nolint:lll
+				`{"tool":"lookup","error":"the tool is unavailable after a previous error","hint":"Use another tool."}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := &countingFailingTool{}
+			agentTools := []tools.Tool{lookup, tools.Calculator{}}
+			opts := append([]agents.Option{agents.WithReturnIntermediateSteps()}, tt.opts...)
+			executor := agents.NewExecutor(agents.NewToolCallingAgent(fake.New(tt.responses...), agentTools), agentTools, opts...)
+
+			outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is the population of Paris?"}) //nolint:lll
+			require.Equal(t, tt.calls, lookup.calls.Load())
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			steps, ok := outputs["intermediateSteps"].([]schema.AgentStep)
+			require.True(t, ok)
+			observations := make([]string, 0, len(steps))
+			for _, step := range steps {
+				observations = append(observations, step.Observation)
+			}
+			require.Equal(t, tt.observations, observations)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// AgentToolHandler is implemented by the handlers notified of the tools called
+// by the agent executors, with their inputs and outputs. Unlike
+// HandleToolStart and HandleToolEnd, which are called by the tools having the
+// handler, it is called by the executor for all the tools. It is separate from
+// Handler so that existing handlers don't have to implement it.
+type AgentToolHandler interface {
+	HandleAgentToolStart(ctx context.Context, action schema.AgentAction)
+	HandleAgentToolEnd(ctx context.Context, action schema.AgentAction, output string, err error)
+}
@@ -0,0 +1,21 @@
+package callbacks
+
+import "context"
+
+// ChainAttempt identifies an attempt of a chain retried or falling back to
+// other chains.
+type ChainAttempt struct {
+	// Chain is the index of the chain attempted: 0 for the primary chain, and
+	// i for its i-th fallback.
+	Chain int
+	// Attempt is the number of the attempt of the chain, starting at 1.
+	Attempt int
+}
+
+// AttemptHandler is implemented by the handlers notified of each attempt of
+// the chains with retries and fallbacks, e.g. chains.Retry. It is separate
+// from Handler so that existing handlers don't have to implement it.
+type AttemptHandler interface {
+	HandleChainAttemptStart(ctx context.Context, attempt ChainAttempt)
+	HandleChainAttemptError(ctx context.Context, attempt ChainAttempt, err error)
+}
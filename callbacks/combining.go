@@ -109,3 +109,73 @@ func (l CombiningHandler) HandleToolError(ctx context.Context, err error) {
 		handle.HandleToolError(ctx, err)
 	}
 }
+
+var _ AttemptHandler = CombiningHandler{}
+
+func (l CombiningHandler) HandleChainAttemptStart(ctx context.Context, attempt ChainAttempt) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(AttemptHandler); ok {
+			handle.HandleChainAttemptStart(ctx, attempt)
+		}
+	}
+}
+
+func (l CombiningHandler) HandleChainAttemptError(ctx context.Context, attempt ChainAttempt, err error) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(AttemptHandler); ok {
+			handle.HandleChainAttemptError(ctx, attempt, err)
+		}
+	}
+}
+
+var _ PlanHandler = CombiningHandler{}
+
+func (l CombiningHandler) HandlePlan(ctx context.Context, steps []string) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(PlanHandler); ok {
+			handle.HandlePlan(ctx, steps)
+		}
+	}
+}
+
+func (l CombiningHandler) HandlePlanStepStart(ctx context.Context, index int, step string) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(PlanHandler); ok {
+			handle.HandlePlanStepStart(ctx, index, step)
+		}
+	}
+}
+
+func (l CombiningHandler) HandlePlanStepEnd(ctx context.Context, index int, result string) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(PlanHandler); ok {
+			handle.HandlePlanStepEnd(ctx, index, result)
+		}
+	}
+}
+
+func (l CombiningHandler) HandlePlanStepError(ctx context.Context, index int, err error) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(PlanHandler); ok {
+			handle.HandlePlanStepError(ctx, index, err)
+		}
+	}
+}
+
+var _ AgentToolHandler = CombiningHandler{}
+
+func (l CombiningHandler) HandleAgentToolStart(ctx context.Context, action schema.AgentAction) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(AgentToolHandler); ok {
+			handle.HandleAgentToolStart(ctx, action)
+		}
+	}
+}
+
+func (l CombiningHandler) HandleAgentToolEnd(ctx context.Context, action schema.AgentAction, output string, err error) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(AgentToolHandler); ok {
+			handle.HandleAgentToolEnd(ctx, action, output, err)
+		}
+	}
+}
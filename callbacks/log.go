@@ -123,3 +123,48 @@ func formatAgentAction(action schema.AgentAction) string {
 func removeNewLines(s any) string {
 	return strings.ReplaceAll(fmt.Sprint(s), "\n", " ")
 }
+
+var _ AttemptHandler = LogHandler{}
+
+func (l LogHandler) HandleChainAttemptStart(_ context.Context, attempt ChainAttempt) {
+	fmt.Printf("Entering attempt %d of chain %d\n", attempt.Attempt, attempt.Chain)
+}
+
+func (l LogHandler) HandleChainAttemptError(_ context.Context, attempt ChainAttempt, err error) {
+	fmt.Printf("Exiting attempt %d of chain %d with error: %s\n", attempt.Attempt, attempt.Chain, err)
+}
+
+var _ PlanHandler = LogHandler{}
+
+func (l LogHandler) HandlePlan(_ context.Context, steps []string) {
+	fmt.Println("Plan:")
+	for i, step := range steps {
+		fmt.Printf("%d. %s\n", i+1, removeNewLines(step))
+	}
+}
+
+func (l LogHandler) HandlePlanStepStart(_ context.Context, index int, step string) {
+	fmt.Printf("Entering step %d of plan: %s\n", index+1, removeNewLines(step))
+}
+
+func (l LogHandler) HandlePlanStepEnd(_ context.Context, index int, result string) {
+	fmt.Printf("Exiting step %d of plan with result: %s\n", index+1, removeNewLines(result))
+}
+
+func (l LogHandler) HandlePlanStepError(_ context.Context, index int, err error) {
+	fmt.Printf("Exiting step %d of plan with error: %s\n", index+1, err)
+}
+
+var _ AgentToolHandler = LogHandler{}
+
+func (l LogHandler) HandleAgentToolStart(_ context.Context, action schema.AgentAction) {
+	fmt.Println("Calling tool", formatAgentAction(action))
+}
+
+func (l LogHandler) HandleAgentToolEnd(_ context.Context, action schema.AgentAction, output string, err error) {
+	if err != nil {
+		fmt.Printf("Tool %q failed with error: %s\n", removeNewLines(action.Tool), err)
+		return
+	}
+	fmt.Printf("Tool %q returned: %s\n", removeNewLines(action.Tool), removeNewLines(output))
+}
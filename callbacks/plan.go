@@ -0,0 +1,22 @@
+package callbacks
+
+import "context"
+
+// PlanHandler is implemented by the handlers notified of the plans of the
+// plan-and-execute agents and of the execution of their steps, e.g. to
+// display the plan and its progress. It is separate from Handler so that
+// existing handlers don't have to implement it.
+type PlanHandler interface {
+	// HandlePlan is called with the steps of the plan, when it is made and
+	// each time it is revised. The steps already executed are kept first.
+	HandlePlan(ctx context.Context, steps []string)
+	// HandlePlanStepStart is called before the execution of the step at the
+	// index of the plan.
+	HandlePlanStepStart(ctx context.Context, index int, step string)
+	// HandlePlanStepEnd is called with the result of the step at the index of
+	// the plan.
+	HandlePlanStepEnd(ctx context.Context, index int, result string)
+	// HandlePlanStepError is called when the step at the index of the plan
+	// fails.
+	HandlePlanStepError(ctx context.Context, index int, err error)
+}
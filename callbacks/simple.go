@@ -28,3 +28,20 @@ func (SimpleHandler) HandleAgentFinish(context.Context, schema.AgentFinish)
 func (SimpleHandler) HandleRetrieverStart(context.Context, string)                         {}
 func (SimpleHandler) HandleRetrieverEnd(context.Context, string, []schema.Document)        {}
 func (SimpleHandler) HandleStreamingFunc(context.Context, []byte)                          {}
+
+var _ AttemptHandler = SimpleHandler{}
+
+func (SimpleHandler) HandleChainAttemptStart(context.Context, ChainAttempt)        {}
+func (SimpleHandler) HandleChainAttemptError(context.Context, ChainAttempt, error) {}
+
+var _ PlanHandler = SimpleHandler{}
+
+func (SimpleHandler) HandlePlan(context.Context, []string)             {}
+func (SimpleHandler) HandlePlanStepStart(context.Context, int, string) {}
+func (SimpleHandler) HandlePlanStepEnd(context.Context, int, string)   {}
+func (SimpleHandler) HandlePlanStepError(context.Context, int, error)  {}
+
+var _ AgentToolHandler = SimpleHandler{}
+
+func (SimpleHandler) HandleAgentToolStart(context.Context, schema.AgentAction)              {}
+func (SimpleHandler) HandleAgentToolEnd(context.Context, schema.AgentAction, string, error) {}
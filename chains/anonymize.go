@@ -0,0 +1,378 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _piiEntitiesFunctionName = "report_entities"
+
+//nolint:lll
+const _defaultPIIEntitiesPrompt = `Find all the personal information of the following types in the text below, and call the report_entities function with them, copying their text exactly as it appears: %s.
+
+Text:
+%s`
+
+// PIIEntity is personal information found in a text by a PIIDetector.
+type PIIEntity struct {
+	// Type is the type of the entity, e.g. "EMAIL" or "PERSON".
+	Type string
+	// Start and End are the byte offsets of the entity in the text.
+	Start, End int
+}
+
+// PIIDetector finds personal information in texts.
+type PIIDetector interface {
+	Detect(ctx context.Context, text string) ([]PIIEntity, error)
+}
+
+// PIIDetectorFunc is a function implementing PIIDetector.
+type PIIDetectorFunc func(ctx context.Context, text string) ([]PIIEntity, error)
+
+// Detect calls the function.
+func (f PIIDetectorFunc) Detect(ctx context.Context, text string) ([]PIIEntity, error) {
+	return f(ctx, text)
+}
+
+// RegexDetector is a PIIDetector finding the matches of a regular
+// expression, e.g. email addresses.
+type RegexDetector struct {
+	Type    string
+	Pattern *regexp.Regexp
+	// Validate, if not nil, filters the matches, e.g. checking the checksum
+	// of card numbers.
+	Validate func(match string) bool
+}
+
+var _ PIIDetector = RegexDetector{}
+
+// Detect returns the matches of the regular expression.
+func (d RegexDetector) Detect(_ context.Context, text string) ([]PIIEntity, error) {
+	var entities []PIIEntity
+	for _, loc := range d.Pattern.FindAllStringIndex(text, -1) {
+		if d.Validate != nil && !d.Validate(text[loc[0]:loc[1]]) {
+			continue
+		}
+		entities = append(entities, PIIEntity{Type: d.Type, Start: loc[0], End: loc[1]})
+	}
+	return entities, nil
+}
+
+// DefaultPIIDetectors returns regex detectors of email addresses, card
+// numbers, US social security numbers, IBANs, IP addresses and phone
+// numbers. Names and addresses need a named entity detector, such as an
+// LLMEntityDetector.
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{
+		RegexDetector{Type: "EMAIL", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+		RegexDetector{
+			Type:     "CREDIT_CARD",
+			Pattern:  regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+			Validate: luhnValid,
+		},
+		RegexDetector{Type: "US_SSN", Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+		RegexDetector{Type: "IBAN", Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`)},
+		RegexDetector{Type: "IP_ADDRESS", Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`)}, //nolint:lll
+		// Phone numbers are international ones or US ones, as other groups of
+		// digits are too often not phone numbers.
+		RegexDetector{Type: "PHONE", Pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?(?:\(\d{1,4}\)[ .\-]?)?\d{2,4}(?:[ .\-]?\d{2,4}){1,3}|\(\d{3}\) ?\d{3}[ .\-]\d{4}|\b\d{3}[.\-]\d{3}[.\-]\d{4})\b`)}, //nolint:lll
+	}
+}
+
+// luhnValid returns whether the digits of the number pass the Luhn checksum.
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 { //nolint:mnd
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0 //nolint:mnd
+}
+
+// LLMEntityDetector is a named entity detector asking an LLM for the
+// entities of the types in a text, e.g. "PERSON" or "ADDRESS". As the text
+// is sent to the LLM, it should be a model deployed in the compliance
+// perimeter, e.g. a local one.
+type LLMEntityDetector struct {
+	LLM   llms.Model
+	Types []string
+}
+
+var _ PIIDetector = LLMEntityDetector{}
+
+// Detect asks the LLM for the entities of the text, and returns all their
+// occurrences in the text.
+func (d LLMEntityDetector) Detect(ctx context.Context, text string) ([]PIIEntity, error) {
+	prompt := fmt.Sprintf(_defaultPIIEntitiesPrompt, strings.Join(d.Types, ", "), text)
+	resp, err := d.LLM.GenerateContent(ctx,
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)},
+		llms.WithTools([]llms.Tool{d.tool()}),
+		llms.WithToolChoice(llms.ToolChoice{
+			Type:     "function",
+			Function: &llms.FunctionReference{Name: _piiEntitiesFunctionName},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, nil
+	}
+
+	var entities []PIIEntity
+	for _, argument := range functionArguments(resp.Choices[0], _piiEntitiesFunctionName) {
+		var call struct {
+			Entities []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"entities"`
+		}
+		if err := json.Unmarshal([]byte(argument), &call); err != nil {
+			return nil, fmt.Errorf("invalid %s arguments: %w", _piiEntitiesFunctionName, err)
+		}
+		for _, e := range call.Entities {
+			if e.Text == "" {
+				continue
+			}
+			for start := 0; ; {
+				i := strings.Index(text[start:], e.Text)
+				if i < 0 {
+					break
+				}
+				start += i
+				end := start + len(e.Text)
+				entities = append(entities, PIIEntity{Type: strings.ToUpper(e.Type), Start: start, End: end})
+				start = end
+			}
+		}
+	}
+	return entities, nil
+}
+
+func (d LLMEntityDetector) tool() llms.Tool {
+	entity := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"type": {Type: jsonschema.String, Enum: d.Types},
+			"text": {Type: jsonschema.String, Description: "The text of the entity, as it appears in the text."},
+		},
+		Required: []string{"type", "text"},
+	}
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        _piiEntitiesFunctionName,
+			Description: "Reports the personal information found in a text.",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"entities": {Type: jsonschema.Array, Items: &entity},
+				},
+				Required: []string{"entities"},
+			},
+		},
+	}
+}
+
+// PIIMapping maps the placeholders of anonymized texts to the personal
+// information they replace.
+type PIIMapping struct {
+	placeholders map[string]string
+	values       map[string]string
+	counts       map[string]int
+}
+
+// NewPIIMapping creates an empty mapping.
+func NewPIIMapping() *PIIMapping {
+	return &PIIMapping{
+		placeholders: make(map[string]string),
+		values:       make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// placeholder returns the placeholder of the value, the same for all the
+// occurrences of the value, e.g. "<EMAIL_1>".
+func (m *PIIMapping) placeholder(typ, value string) string {
+	key := typ + "\x00" + value
+	if p, ok := m.placeholders[key]; ok {
+		return p
+	}
+	m.counts[typ]++
+	p := fmt.Sprintf("<%s_%d>", typ, m.counts[typ])
+	m.placeholders[key] = p
+	m.values[p] = value
+	return p
+}
+
+// Values returns the personal information of the placeholders.
+func (m *PIIMapping) Values() map[string]string {
+	values := make(map[string]string, len(m.values))
+	for p, v := range m.values {
+		values[p] = v
+	}
+	return values
+}
+
+// Deanonymize replaces the placeholders of the text with the personal
+// information they replace.
+func (m *PIIMapping) Deanonymize(text string) string {
+	if len(m.values) == 0 {
+		return text
+	}
+	oldnew := make([]string, 0, 2*len(m.values)) //nolint:mnd
+	for p, v := range m.values {
+		oldnew = append(oldnew, p, v)
+	}
+	return strings.NewReplacer(oldnew...).Replace(text)
+}
+
+// Anonymizer replaces the personal information found by its detectors in
+// texts with placeholders, reversibly.
+type Anonymizer struct {
+	Detectors []PIIDetector
+}
+
+// NewAnonymizer creates a new anonymizer with the detectors, or the
+// DefaultPIIDetectors if none is given.
+func NewAnonymizer(detectors ...PIIDetector) Anonymizer {
+	if len(detectors) == 0 {
+		detectors = DefaultPIIDetectors()
+	}
+	return Anonymizer{Detectors: detectors}
+}
+
+// Anonymize replaces the personal information of the text with placeholders
+// recorded in the mapping. Overlapping entities are resolved in favor of the
+// one starting first, then the longest.
+func (a Anonymizer) Anonymize(ctx context.Context, text string, mapping *PIIMapping) (string, error) {
+	var entities []PIIEntity
+	for _, detector := range a.Detectors {
+		found, err := detector.Detect(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		for _, e := range found {
+			if e.Start >= 0 && e.Start < e.End && e.End <= len(text) {
+				entities = append(entities, e)
+			}
+		}
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Start != entities[j].Start {
+			return entities[i].Start < entities[j].Start
+		}
+		return entities[i].End > entities[j].End
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, e := range entities {
+		if e.Start < last {
+			continue
+		}
+		b.WriteString(text[last:e.Start])
+		b.WriteString(mapping.placeholder(e.Type, text[e.Start:e.End]))
+		last = e.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// AnonymizedChain is a chain calling a chain with the personal information of
+// its string inputs replaced with placeholders, and restoring it in the
+// string outputs, so that the personal information isn't sent to the LLM.
+// Streamed chunks have the placeholders.
+type AnonymizedChain struct {
+	Chain      Chain
+	Anonymizer Anonymizer
+	// InputKeys are the keys of the inputs anonymized. If empty, all the
+	// string inputs are.
+	InputKeys []string
+	// ReturnMapping is whether the placeholders and the personal
+	// information, a map[string]string, are returned under the
+	// "pii_mapping" output key.
+	ReturnMapping bool
+}
+
+var _ Chain = AnonymizedChain{}
+
+// NewAnonymizedChain creates a new chain anonymizing the inputs of the chain
+// with the detectors, or the DefaultPIIDetectors if none is given.
+func NewAnonymizedChain(chain Chain, detectors ...PIIDetector) AnonymizedChain {
+	return AnonymizedChain{
+		Chain:      chain,
+		Anonymizer: NewAnonymizer(detectors...),
+	}
+}
+
+// Call anonymizes the inputs, calls the chain and de-anonymizes its outputs.
+func (c AnonymizedChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	mapping := NewPIIMapping()
+	inputs := make(map[string]any, len(values))
+	for key, value := range values {
+		inputs[key] = value
+		text, ok := value.(string)
+		if !ok || (len(c.InputKeys) > 0 && !slices.Contains(c.InputKeys, key)) {
+			continue
+		}
+		anonymized, err := c.Anonymizer.Anonymize(ctx, text, mapping)
+		if err != nil {
+			return nil, err
+		}
+		inputs[key] = anonymized
+	}
+
+	outputs, err := Call(ctx, c.Chain, inputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range outputs {
+		if text, ok := value.(string); ok {
+			outputs[key] = mapping.Deanonymize(text)
+		}
+	}
+	if c.ReturnMapping {
+		outputs["pii_mapping"] = mapping.Values()
+	}
+	return outputs, nil
+}
+
+// GetMemory returns a simple memory, the memory of the chain being used by
+// its calls with the anonymized inputs and outputs, so that it holds no
+// personal information.
+func (c AnonymizedChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c AnonymizedChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+func (c AnonymizedChain) GetOutputKeys() []string {
+	if c.ReturnMapping {
+		return append(append([]string{}, c.Chain.GetOutputKeys()...), "pii_mapping")
+	}
+	return c.Chain.GetOutputKeys()
+}
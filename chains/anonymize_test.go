@@ -0,0 +1,51 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestAnonymizer(t *testing.T) {
+	t.Parallel()
+
+	mapping := NewPIIMapping()
+	text := "Mail jane@example.com or john@example.com, then jane@example.com again. " +
+		"Card 4111 1111 1111 1111, not 4111 1111 1111 1112. SSN 123-45-6789, IP 10.0.0.1, call +1 415-555-0100."
+	anonymized, err := NewAnonymizer().Anonymize(context.Background(), text, mapping)
+	require.NoError(t, err)
+	require.Equal(t, "Mail <EMAIL_1> or <EMAIL_2>, then <EMAIL_1> again. "+
+		"Card <CREDIT_CARD_1>, not 4111 1111 1111 1112. SSN <US_SSN_1>, IP <IP_ADDRESS_1>, call <PHONE_1>.", anonymized)
+	require.Equal(t, text, mapping.Deanonymize(anonymized))
+	require.Equal(t, "john@example.com", mapping.Values()["<EMAIL_2>"])
+}
+
+func TestAnonymizedChain(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		fake.Response{ToolCalls: []llms.ToolCall{{
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name:      _piiEntitiesFunctionName,
+				Arguments: `{"entities": [{"type": "person", "text": "Jane Doe"}]}`,
+			},
+		}}},
+		fake.Response{Content: "Dear <PERSON_1>, we will write to <EMAIL_1>."},
+	)
+	detectors := append(DefaultPIIDetectors(), LLMEntityDetector{LLM: llm, Types: []string{"PERSON"}})
+	c := NewAnonymizedChain(NewLLMChain(llm, prompts.NewPromptTemplate("Reply to {{.input}}", []string{"input"})),
+		detectors...)
+	c.ReturnMapping = true
+
+	result, err := Call(context.Background(), c, map[string]any{"input": "Jane Doe (jane@example.com) asked for a refund."})
+	require.NoError(t, err)
+	require.Equal(t, "Dear Jane Doe, we will write to jane@example.com.", result["text"])
+	require.Equal(t, map[string]string{"<PERSON_1>": "Jane Doe", "<EMAIL_1>": "jane@example.com"}, result["pii_mapping"])
+	require.Equal(t, "Reply to <PERSON_1> (<EMAIL_1>) asked for a refund.",
+		llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text)
+}
@@ -0,0 +1,138 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _citationQADefaultCitationsKey = "citations"
+
+// _citationRE matches the citations of answers, e.g. "[2]" or "[1, 3]".
+var _citationRE = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// Citation is a source cited by an answer of a CitationQA chain.
+type Citation struct {
+	// Number is the number of the source in the prompt, starting at 1.
+	Number int
+	// Document is the cited document.
+	Document schema.Document
+}
+
+// CitationQA is a chain answering a question from documents with inline
+// citations: the documents are numbered in the prompt, the LLM is instructed
+// to cite them as [n], and the citations of the answer are parsed back into
+// the cited documents.
+//
+// The answer is returned under the output key of the LLM chain, and the
+// citations, as a []Citation in the order they are first cited, under the
+// CitationsKey. Citations of numbers without a source are ignored.
+type CitationQA struct {
+	// LLMChain is the LLMChain called with the numbered documents.
+	LLMChain *LLMChain
+
+	// InputKey is the input key the chain expects the documents to be in.
+	InputKey string
+
+	// DocumentVariableName is the variable name used in the llm chain to put
+	// the numbered documents in.
+	DocumentVariableName string
+
+	// Separator is the string used to join the numbered documents.
+	Separator string
+
+	// CitationsKey is the output key of the citations.
+	CitationsKey string
+}
+
+var _ Chain = CitationQA{}
+
+// NewCitationQA creates a new chain answering with citations, with an LLM
+// chain instructed to cite the numbered documents.
+func NewCitationQA(llmChain *LLMChain) CitationQA {
+	return CitationQA{
+		LLMChain:             llmChain,
+		InputKey:             _combineDocumentsDefaultInputKey,
+		DocumentVariableName: _combineDocumentsDefaultDocumentVariableName,
+		Separator:            _stuffDocumentsDefaultSeparator,
+		CitationsKey:         _citationQADefaultCitationsKey,
+	}
+}
+
+// Call numbers the documents, calls the LLM chain and parses the citations
+// of its answer.
+func (c CitationQA) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	docs, ok := values[c.InputKey].([]schema.Document)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	inputValues := make(map[string]any, len(values)+1)
+	for key, value := range values {
+		inputValues[key] = value
+	}
+	inputValues[c.DocumentVariableName] = c.numberDocuments(docs)
+
+	outputs, err := Call(ctx, c.LLMChain, inputValues, options...)
+	if err != nil {
+		return nil, err
+	}
+	answer, ok := outputs[c.LLMChain.OutputKey].(string)
+	if !ok {
+		return nil, ErrInvalidOutputValues
+	}
+	outputs[c.CitationsKey] = ParseCitations(answer, docs)
+	return outputs, nil
+}
+
+// numberDocuments joins the documents prefixed with their number.
+func (c CitationQA) numberDocuments(docs []schema.Document) string {
+	parts := make([]string, len(docs))
+	for i, doc := range docs {
+		parts[i] = fmt.Sprintf("[%d] %s", i+1, doc.PageContent)
+	}
+	return strings.Join(parts, c.Separator)
+}
+
+// ParseCitations returns the documents cited by the answer as [n], n being
+// the number of the document starting at 1, in the order they are first
+// cited. Citations of numbers without a document are ignored.
+func ParseCitations(answer string, docs []schema.Document) []Citation {
+	citations := make([]Citation, 0)
+	seen := make(map[int]struct{})
+	for _, match := range _citationRE.FindAllStringSubmatch(answer, -1) {
+		for _, s := range strings.Split(match[1], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n < 1 || n > len(docs) {
+				continue
+			}
+			if _, ok := seen[n]; ok {
+				continue
+			}
+			seen[n] = struct{}{}
+			citations = append(citations, Citation{Number: n, Document: docs[n-1]})
+		}
+	}
+	return citations
+}
+
+// GetMemory returns a simple memory.
+func (c CitationQA) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the expected input keys, by default "input_documents".
+func (c CitationQA) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the output keys of the LLM chain and the citations
+// key.
+func (c CitationQA) GetOutputKeys() []string {
+	return append(append([]string{}, c.LLMChain.GetOutputKeys()...), c.CitationsKey)
+}
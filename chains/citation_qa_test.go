@@ -0,0 +1,46 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCitationQA(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(fake.TextResponses("Foxes are red [2] and fast [1, 2][7]. Owls hunt at night [3].")...)
+	docs := []schema.Document{
+		{PageContent: "Foxes run fast.", Metadata: map[string]any{"source": "a"}},
+		{PageContent: "Foxes are red.", Metadata: map[string]any{"source": "b"}},
+		{PageContent: "Owls hunt at night.", Metadata: map[string]any{"source": "c"}},
+	}
+	result, err := Call(context.Background(), LoadCitationQA(llm), map[string]any{
+		"input_documents": docs,
+		"question":        "What are foxes like?",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Foxes are red [2] and fast [1, 2][7]. Owls hunt at night [3].", result["text"])
+	require.Equal(t, []Citation{
+		{Number: 2, Document: docs[1]},
+		{Number: 1, Document: docs[0]},
+		{Number: 3, Document: docs[2]},
+	}, result["citations"])
+
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "[1] Foxes run fast.\n\n[2] Foxes are red.\n\n[3] Owls hunt at night.")
+	require.Contains(t, prompt, "Question: What are foxes like?")
+}
+
+func TestParseCitations(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "foo"}}
+	require.Empty(t, ParseCitations("No sources.", docs))
+	require.Empty(t, ParseCitations("See [0] and [2].", docs))
+	require.Equal(t, []Citation{{Number: 1, Document: docs[0]}}, ParseCitations("Foo [1]. Again [1].", docs))
+}
@@ -0,0 +1,470 @@
+package chains
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownConfigType is returned when a configuration refers to a
+	// chain, LLM or retriever type without registered constructor.
+	ErrUnknownConfigType = errors.New("unknown configuration type")
+	// ErrInvalidConfig is returned when a configuration is missing settings
+	// needed by its chain or has unknown ones.
+	ErrInvalidConfig = errors.New("invalid chain configuration")
+)
+
+// ChainConfig is the serializable configuration of a chain, saved and loaded
+// as YAML or JSON. Chains are built from configurations by the constructor
+// registered for their type in a ConfigRegistry, so that they can be
+// versioned in configuration repositories and reloaded without recompiling.
+type ChainConfig struct {
+	// Type is the registered type of the chain, e.g. "llm".
+	Type string `json:"type" yaml:"type"`
+	// Prompt is the prompt template of the chain, if any.
+	Prompt *PromptConfig `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	// LLM is the model of the chain. Chains without one use the model of the
+	// chain they are part of.
+	LLM *LLMConfig `json:"llm,omitempty" yaml:"llm,omitempty"`
+	// Retriever is the retriever of the chain, if any.
+	Retriever *RetrieverConfig `json:"retriever,omitempty" yaml:"retriever,omitempty"`
+	// Chains are the chains the chain is made of, e.g. the steps of a
+	// sequential chain.
+	Chains []ChainConfig `json:"chains,omitempty" yaml:"chains,omitempty"`
+	// Params are the other settings of the chain, e.g. its "output_key".
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// PromptConfig is the serializable configuration of a prompt template.
+type PromptConfig struct {
+	Template       string   `json:"template" yaml:"template"`
+	InputVariables []string `json:"input_variables" yaml:"input_variables"`
+	// TemplateFormat is the format of the template, by default
+	// "go-template".
+	TemplateFormat   prompts.TemplateFormat `json:"template_format,omitempty" yaml:"template_format,omitempty"`
+	PartialVariables map[string]string      `json:"partial_variables,omitempty" yaml:"partial_variables,omitempty"`
+}
+
+// PromptTemplate returns the prompt template of the configuration.
+func (c PromptConfig) PromptTemplate() prompts.PromptTemplate {
+	p := prompts.NewPromptTemplate(c.Template, c.InputVariables)
+	if c.TemplateFormat != "" {
+		p.TemplateFormat = c.TemplateFormat
+	}
+	if len(c.PartialVariables) > 0 {
+		p.PartialVariables = make(map[string]any, len(c.PartialVariables))
+		for key, value := range c.PartialVariables {
+			p.PartialVariables[key] = value
+		}
+	}
+	return p
+}
+
+// LLMConfig is the serializable configuration of a model, given to the
+// constructor registered for its type.
+type LLMConfig struct {
+	// Type is the registered type of the model, e.g. "openai".
+	Type string `json:"type" yaml:"type"`
+	// Model is the name of the model, e.g. "gpt-4o".
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+	// Params are the other settings of the model, e.g. its "temperature".
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// RetrieverConfig is the serializable configuration of a retriever, given to
+// the constructor registered for its type.
+type RetrieverConfig struct {
+	// Type is the registered type of the retriever, e.g. "vectorstore".
+	Type string `json:"type" yaml:"type"`
+	// Params are the settings of the retriever, e.g. its number of documents.
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// DecodeParams decodes params of a configuration into v, a pointer to a
+// struct whose json tags name the params. Unknown params are errors, so
+// that misspelled settings aren't silently ignored.
+func DecodeParams(params map[string]any, v any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: params: %w", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// ParseChainConfig parses a chain configuration in YAML or JSON.
+func ParseChainConfig(data []byte) (ChainConfig, error) {
+	var cfg ChainConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ChainConfig{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+// LoadChainConfig reads a chain configuration from a YAML or JSON file.
+func LoadChainConfig(path string) (ChainConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	return ParseChainConfig(data)
+}
+
+// SaveChainConfig writes a chain configuration to a file, as JSON if its
+// extension is ".json" and as YAML otherwise.
+func SaveChainConfig(path string, cfg ChainConfig) error {
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(cfg)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ChainConstructor builds a chain from its configuration. The model of the
+// configuration, or of the chain it is part of, is given built.
+type ChainConstructor func(ctx context.Context, r *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error)
+
+// LLMConstructor builds a model from its configuration.
+type LLMConstructor func(ctx context.Context, cfg LLMConfig) (llms.Model, error)
+
+// RetrieverConstructor builds a retriever from its configuration.
+type RetrieverConstructor func(ctx context.Context, cfg RetrieverConfig) (schema.Retriever, error)
+
+// ConfigRegistry maps the types of configurations to their constructors. It
+// is safe for concurrent use.
+type ConfigRegistry struct {
+	mu         sync.RWMutex
+	chains     map[string]ChainConstructor
+	llms       map[string]LLMConstructor
+	retrievers map[string]RetrieverConstructor
+}
+
+// NewConfigRegistry creates a registry with the constructors of the "llm",
+// "stuff_documents", "retrieval_qa", "sequential" and "simple_sequential"
+// chains. Models and retrievers, which depend on the providers used, must be
+// registered.
+func NewConfigRegistry() *ConfigRegistry {
+	r := &ConfigRegistry{
+		chains:     make(map[string]ChainConstructor),
+		llms:       make(map[string]LLMConstructor),
+		retrievers: make(map[string]RetrieverConstructor),
+	}
+	r.RegisterChain("llm", buildLLMChain)
+	r.RegisterChain("stuff_documents", buildStuffDocuments)
+	r.RegisterChain("retrieval_qa", buildRetrievalQA)
+	r.RegisterChain("sequential", buildSequentialChain)
+	r.RegisterChain("simple_sequential", buildSimpleSequentialChain)
+	return r
+}
+
+// RegisterChain registers the constructor of a chain type, replacing any
+// constructor of the same type.
+func (r *ConfigRegistry) RegisterChain(typ string, constructor ChainConstructor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chains[typ] = constructor
+}
+
+// RegisterLLM registers the constructor of a model type, replacing any
+// constructor of the same type.
+func (r *ConfigRegistry) RegisterLLM(typ string, constructor LLMConstructor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.llms[typ] = constructor
+}
+
+// RegisterRetriever registers the constructor of a retriever type, replacing
+// any constructor of the same type.
+func (r *ConfigRegistry) RegisterRetriever(typ string, constructor RetrieverConstructor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retrievers[typ] = constructor
+}
+
+// Build builds the chain of the configuration.
+func (r *ConfigRegistry) Build(ctx context.Context, cfg ChainConfig) (Chain, error) { //nolint:ireturn
+	return r.BuildWithLLM(ctx, cfg, nil)
+}
+
+// BuildWithLLM builds the chain of the configuration, using the model if the
+// configuration has none. It is used by constructors to build the chains
+// their chain is made of.
+func (r *ConfigRegistry) BuildWithLLM(ctx context.Context, cfg ChainConfig, llm llms.Model) (Chain, error) { //nolint:ireturn,lll
+	r.mu.RLock()
+	constructor, ok := r.chains[cfg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: chain %q", ErrUnknownConfigType, cfg.Type)
+	}
+	if cfg.LLM != nil {
+		var err error
+		llm, err = r.BuildLLM(ctx, *cfg.LLM)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain, err := constructor(ctx, r, cfg, llm)
+	if err != nil {
+		return nil, fmt.Errorf("%s chain: %w", cfg.Type, err)
+	}
+	return chain, nil
+}
+
+// BuildLLM builds the model of the configuration.
+func (r *ConfigRegistry) BuildLLM(ctx context.Context, cfg LLMConfig) (llms.Model, error) { //nolint:ireturn
+	r.mu.RLock()
+	constructor, ok := r.llms[cfg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: llm %q", ErrUnknownConfigType, cfg.Type)
+	}
+	return constructor(ctx, cfg)
+}
+
+// BuildRetriever builds the retriever of the configuration.
+func (r *ConfigRegistry) BuildRetriever(ctx context.Context, cfg RetrieverConfig) (schema.Retriever, error) { //nolint:ireturn,lll
+	r.mu.RLock()
+	constructor, ok := r.retrievers[cfg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: retriever %q", ErrUnknownConfigType, cfg.Type)
+	}
+	return constructor(ctx, cfg)
+}
+
+func buildLLMChain(_ context.Context, _ *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error) {
+	if llm == nil || cfg.Prompt == nil {
+		return nil, fmt.Errorf("%w: llm and prompt are required", ErrInvalidConfig)
+	}
+	var params struct {
+		OutputKey string `json:"output_key"`
+	}
+	if err := DecodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+	chain := NewLLMChain(llm, cfg.Prompt.PromptTemplate())
+	if params.OutputKey != "" {
+		chain.OutputKey = params.OutputKey
+	}
+	return chain, nil
+}
+
+func buildStuffDocuments(_ context.Context, _ *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error) {
+	if llm == nil {
+		return nil, fmt.Errorf("%w: llm is required", ErrInvalidConfig)
+	}
+	var params struct {
+		InputKey             string  `json:"input_key"`
+		DocumentVariableName string  `json:"document_variable_name"`
+		Separator            *string `json:"separator"`
+		MaxTokens            int     `json:"max_tokens"`
+	}
+	if err := DecodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+	chain := LoadStuffQA(llm)
+	if cfg.Prompt != nil {
+		chain = NewStuffDocuments(NewLLMChain(llm, cfg.Prompt.PromptTemplate()))
+	}
+	if params.InputKey != "" {
+		chain.InputKey = params.InputKey
+	}
+	if params.DocumentVariableName != "" {
+		chain.DocumentVariableName = params.DocumentVariableName
+	}
+	if params.Separator != nil {
+		chain.Separator = *params.Separator
+	}
+	chain.MaxTokens = params.MaxTokens
+	return chain, nil
+}
+
+func buildRetrievalQA(ctx context.Context, r *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error) {
+	if cfg.Retriever == nil || len(cfg.Chains) > 1 || (len(cfg.Chains) == 0 && llm == nil) {
+		return nil, fmt.Errorf("%w: a retriever and a combine documents chain or an llm are required", ErrInvalidConfig)
+	}
+	var params struct {
+		InputKey              string `json:"input_key"`
+		ReturnSourceDocuments bool   `json:"return_source_documents"`
+	}
+	if err := DecodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+	retriever, err := r.BuildRetriever(ctx, *cfg.Retriever)
+	if err != nil {
+		return nil, err
+	}
+	var combineChain Chain = LoadStuffQA(llm)
+	if len(cfg.Chains) == 1 {
+		combineChain, err = r.BuildWithLLM(ctx, cfg.Chains[0], llm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain := NewRetrievalQA(combineChain, retriever)
+	if params.InputKey != "" {
+		chain.InputKey = params.InputKey
+	}
+	chain.ReturnSourceDocuments = params.ReturnSourceDocuments
+	return chain, nil
+}
+
+func buildSequentialChain(ctx context.Context, r *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error) {
+	var params struct {
+		InputKeys  []string `json:"input_keys"`
+		OutputKeys []string `json:"output_keys"`
+	}
+	if err := DecodeParams(cfg.Params, &params); err != nil {
+		return nil, err
+	}
+	chains, err := buildChains(ctx, r, cfg.Chains, llm)
+	if err != nil {
+		return nil, err
+	}
+	return NewSequentialChain(chains, params.InputKeys, params.OutputKeys)
+}
+
+func buildSimpleSequentialChain(ctx context.Context, r *ConfigRegistry, cfg ChainConfig, llm llms.Model) (Chain, error) { //nolint:lll
+	if err := DecodeParams(cfg.Params, &struct{}{}); err != nil {
+		return nil, err
+	}
+	chains, err := buildChains(ctx, r, cfg.Chains, llm)
+	if err != nil {
+		return nil, err
+	}
+	return NewSimpleSequentialChain(chains)
+}
+
+func buildChains(ctx context.Context, r *ConfigRegistry, configs []ChainConfig, llm llms.Model) ([]Chain, error) {
+	chains := make([]Chain, len(configs))
+	for i, cfg := range configs {
+		chain, err := r.BuildWithLLM(ctx, cfg, llm)
+		if err != nil {
+			return nil, fmt.Errorf("chain at index %d: %w", i, err)
+		}
+		chains[i] = chain
+	}
+	return chains, nil
+}
+
+// ReloadableChain is a chain built from a configuration file, which can be
+// reloaded while the program runs. Calls use the chain built from the
+// configuration last loaded successfully.
+type ReloadableChain struct {
+	path     string
+	registry *ConfigRegistry
+	current  atomic.Pointer[Chain]
+	modTime  atomic.Int64
+}
+
+var _ Chain = &ReloadableChain{}
+
+// NewReloadableChain builds the chain of the configuration file with the
+// registry.
+func NewReloadableChain(ctx context.Context, registry *ConfigRegistry, path string) (*ReloadableChain, error) {
+	c := &ReloadableChain{path: path, registry: registry}
+	if err := c.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload loads the configuration file and rebuilds the chain. If the
+// configuration is invalid, the previous chain is kept and the error
+// returned.
+func (c *ReloadableChain) Reload(ctx context.Context) error {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadChainConfig(c.path)
+	if err != nil {
+		return err
+	}
+	chain, err := c.registry.Build(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	c.current.Store(&chain)
+	c.modTime.Store(info.ModTime().UnixNano())
+	return nil
+}
+
+// Watch reloads the chain whenever the modification time of the
+// configuration file changes, checking it every interval, until the context
+// is done. Errors of reloads are given to onError, if not nil.
+func (c *ReloadableChain) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// seen is the modification time of the file last loaded, successfully or
+	// not, so that invalid configurations are only reported once.
+	seen := c.modTime.Load()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(c.path)
+		if err == nil {
+			if info.ModTime().UnixNano() == seen {
+				continue
+			}
+			seen = info.ModTime().UnixNano()
+			err = c.Reload(ctx)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Chain returns the chain currently used.
+func (c *ReloadableChain) Chain() Chain { //nolint:ireturn
+	return *c.current.Load()
+}
+
+// Call calls the chain currently used.
+func (c *ReloadableChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	return c.Chain().Call(ctx, inputs, options...)
+}
+
+// GetMemory returns the memory of the chain currently used.
+func (c *ReloadableChain) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Chain().GetMemory()
+}
+
+// GetInputKeys returns the input keys of the chain currently used.
+func (c *ReloadableChain) GetInputKeys() []string {
+	return c.Chain().GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the chain currently used.
+func (c *ReloadableChain) GetOutputKeys() []string {
+	return c.Chain().GetOutputKeys()
+}
@@ -0,0 +1,148 @@
+package chains
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _testChainConfig = `type: sequential
+llm:
+  type: fake
+  model: small
+params:
+  input_keys: [topic]
+  output_keys: [review]
+chains:
+  - type: llm
+    prompt:
+      template: "Write a poem about {{.topic}}"
+      input_variables: [topic]
+    params:
+      output_key: poem
+  - type: llm
+    llm:
+      type: fake
+      model: large
+    prompt:
+      template: "Review {{.poem}}"
+      input_variables: [poem]
+    params:
+      output_key: review
+`
+
+func testConfigRegistry(responses *[]string) *ConfigRegistry {
+	r := NewConfigRegistry()
+	r.RegisterLLM("fake", func(_ context.Context, cfg LLMConfig) (llms.Model, error) {
+		*responses = append(*responses, cfg.Model)
+		return fake.NewRepeating(fake.TextResponses(cfg.Model + " answer")...), nil
+	})
+	r.RegisterRetriever("static", func(_ context.Context, cfg RetrieverConfig) (schema.Retriever, error) {
+		var params struct {
+			Documents []string `json:"documents"`
+		}
+		if err := DecodeParams(cfg.Params, &params); err != nil {
+			return nil, err
+		}
+		docs := make(testDocumentsRetriever, len(params.Documents))
+		for i, d := range params.Documents {
+			docs[i] = schema.Document{PageContent: d}
+		}
+		return docs, nil
+	})
+	return r
+}
+
+func TestChainConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := ParseChainConfig([]byte(_testChainConfig))
+	require.NoError(t, err)
+
+	var models []string
+	chain, err := testConfigRegistry(&models).Build(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, []string{"small", "large"}, models)
+
+	result, err := Call(context.Background(), chain, map[string]any{"topic": "the sea"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"review": "large answer"}, result)
+
+	// Configurations saved as JSON or YAML load back the same.
+	dir := t.TempDir()
+	for _, name := range []string{"chain.json", "chain.yaml"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, SaveChainConfig(path, cfg))
+		loaded, err := LoadChainConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, cfg, loaded)
+	}
+}
+
+func TestChainConfigRetrievalQA(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := ParseChainConfig([]byte(`{
+  "type": "retrieval_qa",
+  "llm": {"type": "fake", "model": "qa"},
+  "retriever": {"type": "static", "params": {"documents": ["foo", "bar"]}},
+  "params": {"return_source_documents": true}
+}`))
+	require.NoError(t, err)
+
+	var models []string
+	r := testConfigRegistry(&models)
+	chain, err := r.Build(context.Background(), cfg)
+	require.NoError(t, err)
+	result, err := Call(context.Background(), chain, map[string]any{"query": "foo?"})
+	require.NoError(t, err)
+	require.Equal(t, "qa answer", result["text"])
+	require.Len(t, result["source_documents"], 2)
+
+	cfg.Params["return_sources"] = true
+	_, err = r.Build(context.Background(), cfg)
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	delete(cfg.Params, "return_sources")
+	cfg.Retriever.Type = "unknown"
+	_, err = r.Build(context.Background(), cfg)
+	require.ErrorIs(t, err, ErrUnknownConfigType)
+}
+
+func TestReloadableChain(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "chain.yaml")
+	require.NoError(t, SaveChainConfig(path, ChainConfig{
+		Type:   "llm",
+		LLM:    &LLMConfig{Type: "fake", Model: "v1"},
+		Prompt: &PromptConfig{Template: "{{.input}}", InputVariables: []string{"input"}},
+	}))
+
+	var models []string
+	chain, err := NewReloadableChain(context.Background(), testConfigRegistry(&models), path)
+	require.NoError(t, err)
+	answer, err := Run(context.Background(), chain, "hi")
+	require.NoError(t, err)
+	require.Equal(t, "v1 answer", answer)
+
+	// Invalid configurations keep the previous chain.
+	require.NoError(t, os.WriteFile(path, []byte("type: llm\n"), 0o600))
+	require.ErrorIs(t, chain.Reload(context.Background()), ErrInvalidConfig)
+
+	require.NoError(t, SaveChainConfig(path, ChainConfig{
+		Type:   "llm",
+		LLM:    &LLMConfig{Type: "fake", Model: "v2"},
+		Prompt: &PromptConfig{Template: "{{.input}}", InputVariables: []string{"input"}},
+	}))
+	require.NoError(t, chain.Reload(context.Background()))
+	answer, err = Run(context.Background(), chain, "hi")
+	require.NoError(t, err)
+	require.Equal(t, "v2 answer", answer)
+}
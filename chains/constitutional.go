@@ -3,6 +3,8 @@ package chains
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/tmc/langchaingo/llms"
@@ -357,6 +359,41 @@ type ConstitutionalPrinciple struct {
 	name            string
 }
 
+// Name returns the name of the principle.
+func (p ConstitutionalPrinciple) Name() string {
+	return p.name
+}
+
+// ExamplePrinciples returns the built-in principles with the names, e.g.
+// "harmful1" or "criminal", or all of them, sorted by name, if no name is
+// given.
+func ExamplePrinciples(names ...string) ([]ConstitutionalPrinciple, error) {
+	if len(names) == 0 {
+		for name := range examplePrinciples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	principles := make([]ConstitutionalPrinciple, 0, len(names))
+	for _, name := range names {
+		principle, ok := examplePrinciples[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: principle %q", ErrNotFound, name)
+		}
+		principles = append(principles, principle)
+	}
+	return principles, nil
+}
+
+// CritiqueRevision is a step of the critique trail of a Constitutional chain:
+// the critique of the response against a principle, and the revised response,
+// empty if the critique found no revision needed.
+type CritiqueRevision struct {
+	Principle string
+	Critique  string
+	Revision  string
+}
+
 type Pair struct {
 	first, second interface{}
 }
@@ -380,6 +417,19 @@ type Constitutional struct {
 	memory                   schema.Memory
 }
 
+// ConstitutionalOption is a function that configures a Constitutional chain.
+type ConstitutionalOption func(*Constitutional)
+
+// WithConstitutionalReturnIntermediateSteps is an option for returning the
+// initial response under the "initial_output" output key and the critique
+// trail, a []CritiqueRevision, under the "critique_trail" output key, for
+// auditing the revisions.
+func WithConstitutionalReturnIntermediateSteps(returnIntermediateSteps bool) ConstitutionalOption {
+	return func(c *Constitutional) {
+		c.returnIntermediateSteps = returnIntermediateSteps
+	}
+}
+
 // getConstitutionalExample returns an array of ConstitutionalExample to be used for the default critiquePrompt and
 // revisionPrompt.
 func getConstitutionalExample() []ConstitutionalExample {
@@ -556,9 +606,11 @@ func NewConstitutionalPrinciple(critique, revision string, names ...string) Cons
 	}
 }
 
-// NewConstitutional creates a new Constitutional chain.
+// NewConstitutional creates a new Constitutional chain, generating a response
+// with the chain, then critiquing and revising it against each principle in
+// turn.
 func NewConstitutional(llm llms.Model, chain LLMChain, constitutionalPrinciples []ConstitutionalPrinciple,
-	options map[string]*prompts.FewShotPrompt,
+	options map[string]*prompts.FewShotPrompt, opts ...ConstitutionalOption,
 ) *Constitutional {
 	CritiquePrompt, RevisionPrompt := initCritiqueRevision()
 	var critiquePrompt, revisionPrompt *prompts.FewShotPrompt
@@ -580,7 +632,7 @@ func NewConstitutional(llm llms.Model, chain LLMChain, constitutionalPrinciples
 	critiqueChain := *NewLLMChain(llm, critiquePrompt)
 	revisionChain := *NewLLMChain(llm, revisionPrompt)
 
-	return &Constitutional{
+	c := &Constitutional{
 		chain:                    chain,
 		critiqueChain:            critiqueChain,
 		revisionChain:            revisionChain,
@@ -589,6 +641,10 @@ func NewConstitutional(llm llms.Model, chain LLMChain, constitutionalPrinciples
 		returnIntermediateSteps:  false,
 		memory:                   memory.NewSimple(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Call handles the inner logic of the Constitutional chain.
@@ -609,25 +665,33 @@ func (c *Constitutional) Call(ctx context.Context, inputs map[string]any, option
 	if err != nil {
 		return nil, err
 	}
-	critiquesAndRevisions, err := c.processCritiquesAndRevisions(ctx, response, inputPrompt, options)
+	critiquesAndRevisions, trail, err := c.processCritiquesAndRevisions(ctx, response, inputPrompt, options)
 	if err != nil {
 		return nil, err
 	}
+	// The response is the last revision, if any.
+	for _, step := range trail {
+		if step.Revision != "" {
+			response = step.Revision
+		}
+	}
 	finalOutput := map[string]any{"output": response}
 	if c.returnIntermediateSteps {
 		finalOutput["initial_output"] = initialResponse
 		finalOutput["critiques_and_revisions"] = critiquesAndRevisions
+		finalOutput["critique_trail"] = trail
 	}
 	return finalOutput, nil
 }
 
 // processCritiquesAndRevisions processes critiques and revisions based on the input response and prompt.
 // It iterates through constitutional principles, retrieves critiques, and performs revisions where necessary.
-// The resulting pairs of critiques and revisions are returned.
+// The resulting pairs of critiques and revisions are returned, with the critique trail.
 func (c *Constitutional) processCritiquesAndRevisions(ctx context.Context, response any, inputPrompt llms.PromptValue,
 	options []ChainCallOption,
-) ([]Pair, error) {
+) ([]Pair, []CritiqueRevision, error) {
 	critiquesAndRevisions := make([]Pair, 0, len(c.constitutionalPrinciples))
+	trail := make([]CritiqueRevision, 0, len(c.constitutionalPrinciples))
 	for _, constitutionalPrincipal := range c.constitutionalPrinciples {
 		rawCritique, err := c.critiqueChain.Call(ctx, map[string]any{
 			"inputPrompt":     inputPrompt,
@@ -635,19 +699,19 @@ func (c *Constitutional) processCritiquesAndRevisions(ctx context.Context, respo
 			"critiqueRequest": constitutionalPrincipal.critiqueRequest,
 		}, options...)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		output, ok := rawCritique["text"]
 		if !ok {
-			return nil, ErrNotFound
+			return nil, nil, ErrNotFound
 		}
 		output, ok = output.(string)
 		if !ok {
-			return nil, ErrConvert
+			return nil, nil, ErrConvert
 		}
 		stringOutput, ok := output.(string)
 		if !ok {
-			return nil, ErrConvert
+			return nil, nil, ErrConvert
 		}
 		critique := parseCritique(stringOutput)
 
@@ -661,6 +725,7 @@ func (c *Constitutional) processCritiquesAndRevisions(ctx context.Context, respo
 				first:  critique,
 				second: "",
 			})
+			trail = append(trail, CritiqueRevision{Principle: constitutionalPrincipal.name, Critique: critique})
 			continue
 		}
 
@@ -670,13 +735,13 @@ func (c *Constitutional) processCritiquesAndRevisions(ctx context.Context, respo
 			"critiqueRequest": constitutionalPrincipal.critiqueRequest,
 			"critique":        critique,
 			"revisionRequest": constitutionalPrincipal.revisionRequest,
-		})
+		}, options...)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		revision, ok := result["text"].(string)
 		if !ok {
-			return nil, ErrNotFound
+			return nil, nil, ErrNotFound
 		}
 		revision = strings.Trim(revision, " ")
 		response = revision
@@ -684,8 +749,13 @@ func (c *Constitutional) processCritiquesAndRevisions(ctx context.Context, respo
 			first:  critique,
 			second: revision,
 		})
+		trail = append(trail, CritiqueRevision{
+			Principle: constitutionalPrincipal.name,
+			Critique:  critique,
+			Revision:  revision,
+		})
 	}
-	return critiquesAndRevisions, nil
+	return critiquesAndRevisions, trail, nil
 }
 
 func parseCritique(rawCritique string) string {
@@ -709,7 +779,7 @@ func (c *Constitutional) GetInputKeys() []string {
 
 func (c *Constitutional) GetOutputKeys() []string {
 	if c.returnIntermediateSteps {
-		return []string{"output", "critiques_and_revisions", "initial_output"}
+		return []string{"output", "critiques_and_revisions", "initial_output", "critique_trail"}
 	}
 	return []string{"output"}
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 )
@@ -63,3 +64,31 @@ func Test(t *testing.T) {
 	_, err = c.Call(context.Background(), map[string]any{"question": "What is the meaning of life?"})
 	require.NoError(t, err)
 }
+
+func TestConstitutionalCritiqueTrail(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(fake.TextResponses(
+		"Just take the money.",
+		"The response encourages theft.\n\nRevision request: Remove it.",
+		"Ask your bank about a loan.",
+		"No critique needed.",
+	)...)
+	principles, err := ExamplePrinciples("illegal", "harmful1")
+	require.NoError(t, err)
+	require.Equal(t, "illegal", principles[0].Name())
+
+	c := NewConstitutional(llm, *NewLLMChain(llm, prompts.NewPromptTemplate("{{.question}}", []string{"question"})),
+		principles, nil, WithConstitutionalReturnIntermediateSteps(true))
+	result, err := Call(context.Background(), c, map[string]any{"question": "How do I get money fast?"})
+	require.NoError(t, err)
+	require.Equal(t, "Ask your bank about a loan.", result["output"])
+	require.Equal(t, "Just take the money.", result["initial_output"])
+	require.Equal(t, []CritiqueRevision{
+		{Principle: "illegal", Critique: "The response encourages theft.", Revision: "Ask your bank about a loan."},
+		{Principle: "harmful1", Critique: "No critique needed."},
+	}, result["critique_trail"])
+
+	_, err = ExamplePrinciples("unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+}
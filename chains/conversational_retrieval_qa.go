@@ -41,13 +41,15 @@ type ConversationalRetrievalQA struct {
 	// original question along to the CombineDocumentsChain.
 	RephraseQuestion bool
 
-	// ReturnGeneratedQuestion Return the generated question as part of the final result.
+	// ReturnGeneratedQuestion Return the generated standalone question, used for retrieval,
+	// as part of the final result in the "generated_question" key.
 	ReturnGeneratedQuestion bool
 
 	// InputKey The input key to get the query from, by default "query".
 	InputKey string
 
-	// ReturnSourceDocuments Return the retrieved source documents as part of the final result.
+	// ReturnSourceDocuments Return the retrieved source documents as part of the final result
+	// in the "source_documents" key.
 	ReturnSourceDocuments bool
 }
 
@@ -139,7 +141,12 @@ func (c ConversationalRetrievalQA) Call(ctx context.Context, values map[string]a
 	return output, nil
 }
 
+// GetMemory returns the memory. When the source documents or the generated question are
+// returned, only the answer is saved to the memory.
 func (c ConversationalRetrievalQA) GetMemory() schema.Memory {
+	if c.ReturnSourceDocuments || c.ReturnGeneratedQuestion {
+		return answerMemory{Memory: c.Memory}
+	}
 	return c.Memory
 }
 
@@ -152,6 +159,9 @@ func (c ConversationalRetrievalQA) GetOutputKeys() []string {
 	if c.ReturnSourceDocuments {
 		outputKeys = append(outputKeys, _conversationalRetrievalQADefaultSourceDocumentKey)
 	}
+	if c.ReturnGeneratedQuestion {
+		outputKeys = append(outputKeys, _conversationalRetrievalQADefaultGeneratedQuestionKey)
+	}
 
 	return outputKeys
 }
@@ -192,3 +202,15 @@ func (c ConversationalRetrievalQA) rephraseQuestion(question string, newQuestion
 
 	return question
 }
+
+// answerMemory is a memory saving only the answer of a conversational retrieval chain, and not
+// the source documents and generated question it returns, to the chat history.
+type answerMemory struct {
+	schema.Memory
+}
+
+func (m answerMemory) SaveContext(ctx context.Context, inputs map[string]any, outputs map[string]any) error {
+	return m.Memory.SaveContext(ctx, inputs, map[string]any{
+		_llmChainDefaultOutputKey: outputs[_llmChainDefaultOutputKey],
+	})
+}
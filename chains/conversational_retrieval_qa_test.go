@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/schema"
@@ -120,6 +121,56 @@ func TestConversationalRetrievalQAWithReturnMessages(t *testing.T) {
 	require.True(t, strings.Contains(result, "Justice Stephen Breyer"), "expected  Justice Stephen Breyer in result")
 }
 
+func TestConversationalRetrievalQAStreaming(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(fake.TextResponses("She was nominated.", "Who did Ketanji Brown Jackson succeed?", "Breyer.")...)
+	chain := NewConversationalRetrievalQAFromLLM(llm, testConversationalRetriever{}, memory.NewConversationBuffer())
+
+	var chunks []string
+	streamingFunc := WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+	_, err := Run(ctx, chain, "What did the president say about Ketanji Brown Jackson", streamingFunc)
+	require.NoError(t, err)
+	result, err := Run(ctx, chain, "Did he mention who she succeeded", streamingFunc)
+	require.NoError(t, err)
+	require.Equal(t, "Breyer.", result)
+	// the standalone question isn't streamed.
+	require.Equal(t, []string{"She was nominated.", "Breyer."}, chunks)
+}
+
+func TestConversationalRetrievalQAReturnSources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(fake.TextResponses("She was nominated.", "Who did Ketanji Brown Jackson succeed?", "Breyer.")...)
+	mem := memory.NewConversationBuffer()
+	chain := NewConversationalRetrievalQAFromLLM(llm, testConversationalRetriever{}, mem)
+	chain.ReturnSourceDocuments = true
+	chain.ReturnGeneratedQuestion = true
+	require.Equal(t, []string{"text", "source_documents", "generated_question"}, chain.GetOutputKeys())
+
+	result, err := Call(ctx, chain, map[string]any{"question": "What did the president say about Ketanji Brown Jackson"})
+	require.NoError(t, err)
+	require.Equal(t, "What did the president say about Ketanji Brown Jackson", result["generated_question"])
+	require.Len(t, result["source_documents"], 4)
+
+	result, err = Call(ctx, chain, map[string]any{"question": "Did he mention who she succeeded"})
+	require.NoError(t, err)
+	require.Equal(t, "Breyer.", result["text"])
+	require.Equal(t, "Who did Ketanji Brown Jackson succeed?", result["generated_question"])
+	require.Len(t, result["source_documents"], 4)
+
+	// only the answers are saved to the memory.
+	history, err := mem.LoadMemoryVariables(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Human: What did the president say about Ketanji Brown Jackson\nAI: She was nominated.\n"+
+		"Human: Did he mention who she succeeded\nAI: Breyer.", history["history"])
+}
+
 func TestConversationalRetrievalQAFromLLM(t *testing.T) {
 	t.Skip("Test currently fails; see #415")
 	t.Parallel()
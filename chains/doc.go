@@ -1,3 +1,18 @@
 // Package chains contains a standard interface for chains, a number of built-in chains and
 // functions for calling and running chains.
+//
+// # Streaming
+//
+// The WithStreamingFunc and WithStreamingEventFunc options, and the streaming of a callback
+// handler given with WithCallback, apply to the LLM call producing the final answer of a
+// chain, so that only the answer is streamed:
+//
+//   - LLMChain streams its LLM call.
+//   - StuffDocuments, and RetrievalQA with it, stream the call answering with the documents.
+//   - MapReduceDocuments streams the reduce chain, not the map calls.
+//   - RefineDocuments streams the call refining the answer with the last document.
+//   - MapRerankDocuments doesn't stream, as its answers are all generated before the best one
+//     is chosen.
+//   - ConversationalRetrievalQA streams the combine documents chain, not the generation of the
+//     standalone question.
 package chains
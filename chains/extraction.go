@@ -0,0 +1,312 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultExtractionTemplate = `Extract the information described by the parameters of the extract function from the following text, and call the function with it. Only extract information stated in the text, leaving out the optional properties whose values the text doesn't give.
+
+Text:
+{{.input}}`
+
+//nolint:lll
+const _defaultManyExtractionTemplate = `Extract all the instances of the information described by the items parameter of the extract function from the following text, and call the function with them. Only extract information stated in the text, leaving out the optional properties whose values the text doesn't give. If the text has no instance, call the function with no items.
+
+Text:
+{{.input}}`
+
+const (
+	_extractionFunctionName      = "extract"
+	_extractionDefaultInputKey   = "input"
+	_extractionDefaultOutputKey  = "extracted"
+	_extractionErrorKey          = "extraction_error"
+	_extractionDefaultMaxRetries = 2
+)
+
+var (
+	// ErrInvalidExtraction is returned by an Extraction when the LLM doesn't
+	// extract a valid instance.
+	ErrInvalidExtraction = errors.New("invalid extraction")
+	// ErrPartialExtraction is returned by an Extraction of many instances with
+	// the valid instances when some of the instances extracted are invalid.
+	ErrPartialExtraction = errors.New("partial extraction")
+)
+
+// Extraction is a chain extracting instances of the Go type T, usually a
+// struct, from unstructured text. The LLM is asked to call a function whose
+// parameters are the JSON schema of T, see jsonschema.For, and the arguments
+// are decoded into T. Instances missing required properties, or whose
+// Validate method, if T or *T has one, returns an error, are invalid: the
+// errors are given back to the LLM to correct them, up to MaxRetries times.
+//
+// The instance extracted is returned under the "extracted" output key, or,
+// if Many is set, the instances, a []T, and the ErrPartialExtraction error
+// of the invalid ones, if any, under the "extraction_error" output key.
+type Extraction[T any] struct {
+	LLM    llms.Model
+	Memory schema.Memory
+	// Prompt is the prompt asking the LLM to call the extract function, with
+	// the input variable.
+	Prompt prompts.PromptTemplate
+	// Many is whether all the instances in the text are extracted, rather
+	// than a single one.
+	Many bool
+	// MaxRetries is the number of times the LLM is asked to correct invalid
+	// instances.
+	MaxRetries int
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = Extraction[struct{}]{}
+
+// NewExtraction creates a new chain extracting instances of T. If many is
+// true, all the instances in the text are extracted, otherwise a single one.
+func NewExtraction[T any](llm llms.Model, many bool) Extraction[T] {
+	template := _defaultExtractionTemplate
+	if many {
+		template = _defaultManyExtractionTemplate
+	}
+	return Extraction[T]{
+		LLM:        llm,
+		Memory:     memory.NewSimple(),
+		Prompt:     prompts.NewPromptTemplate(template, []string{"input"}),
+		Many:       many,
+		MaxRetries: _extractionDefaultMaxRetries,
+		InputKey:   _extractionDefaultInputKey,
+		OutputKey:  _extractionDefaultOutputKey,
+	}
+}
+
+// Call extracts the instances of T in the input text. An error wrapping
+// ErrPartialExtraction is returned as the "extraction_error" output, rather
+// than as the error of the call, so that the valid instances are kept.
+func (c Extraction[T]) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	text, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	instances, err := c.Extract(ctx, text, options...)
+	if !c.Many {
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{c.OutputKey: instances[0]}, nil
+	}
+	if err != nil && !errors.Is(err, ErrPartialExtraction) {
+		return nil, err
+	}
+	return map[string]any{c.OutputKey: instances, _extractionErrorKey: err}, nil
+}
+
+// Extract returns the instances of T in the text, a single one unless Many
+// is set. If some of many instances are still invalid after the retries, the
+// valid ones are returned with an error wrapping ErrPartialExtraction.
+func (c Extraction[T]) Extract(ctx context.Context, text string, options ...ChainCallOption) ([]T, error) {
+	prompt, err := c.Prompt.Format(map[string]any{"input": text})
+	if err != nil {
+		return nil, err
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}
+	callOptions := append(getLLMCallOptions(withoutStreaming(options)...),
+		llms.WithTools([]llms.Tool{c.tool()}),
+		llms.WithToolChoice(llms.ToolChoice{
+			Type:     "function",
+			Function: &llms.FunctionReference{Name: _extractionFunctionName},
+		}),
+	)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.LLM.GenerateContent(ctx, messages, callOptions...)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("%w: empty response", ErrInvalidExtraction)
+		}
+
+		arguments := functionArguments(resp.Choices[0], _extractionFunctionName)
+		instances, err := c.parse(arguments)
+		if err == nil {
+			return instances, nil
+		}
+		if attempt >= c.MaxRetries || ctx.Err() != nil {
+			if c.Many && len(instances) > 0 {
+				return instances, fmt.Errorf("%w: %w", ErrPartialExtraction, err)
+			}
+			return nil, fmt.Errorf("%w: %w", ErrInvalidExtraction, err)
+		}
+		messages = []llms.MessageContent{messages[0], llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(
+			"The arguments of the extract function call %s are invalid: %s\nCall the function again with corrected arguments.",
+			strings.Join(arguments, "\n"), err,
+		))}
+	}
+}
+
+// tool returns the definition of the extract function.
+func (c Extraction[T]) tool() llms.Tool {
+	parameters := jsonschema.For[T]()
+	if c.Many {
+		parameters = jsonschema.Definition{
+			Type: jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{
+				"items": {Type: jsonschema.Array, Items: &parameters, Description: "The instances in the text."},
+			},
+			Required: []string{"items"},
+		}
+	}
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        _extractionFunctionName,
+			Description: "Extracts structured information from a text.",
+			Parameters:  parameters,
+		},
+	}
+}
+
+// parse decodes the arguments of the extract function calls, returning the
+// valid instances and the errors of the invalid ones.
+func (c Extraction[T]) parse(arguments []string) ([]T, error) {
+	if len(arguments) == 0 {
+		return nil, errors.New("no extract function call")
+	}
+	if !c.Many {
+		instance, err := decodeInstance[T](jsonschema.For[T](), []byte(arguments[0]))
+		if err != nil {
+			return nil, err
+		}
+		return []T{instance}, nil
+	}
+
+	definition := jsonschema.For[T]()
+	instances := []T{}
+	var errs []error
+	index := 0
+	for _, argument := range arguments {
+		var call struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(argument), &call); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, item := range call.Items {
+			instance, err := decodeInstance[T](definition, item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("item %d: %w", index, err))
+			} else {
+				instances = append(instances, instance)
+			}
+			index++
+		}
+	}
+	return instances, errors.Join(errs...)
+}
+
+// decodeInstance decodes and validates an instance of T.
+func decodeInstance[T any](definition jsonschema.Definition, data []byte) (T, error) {
+	var instance T
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return instance, err
+	}
+	if err := checkRequired(definition, value, ""); err != nil {
+		return instance, err
+	}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return instance, err
+	}
+	if v, ok := any(&instance).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return instance, err
+		}
+	}
+	return instance, nil
+}
+
+// checkRequired returns an error if the value, decoded from JSON, misses a
+// required property of the definition.
+func checkRequired(definition jsonschema.Definition, value any, path string) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range definition.Required {
+			if v[key] == nil {
+				return fmt.Errorf("missing required property %s", path+key)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := definition.Properties[key]; ok {
+				if err := checkRequired(property, v[key], path+key+"."); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if definition.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := checkRequired(*definition.Items, item, fmt.Sprintf("%s%d.", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// functionArguments returns the arguments of the calls of the function of the
+// choice, or its content for models answering with JSON text instead.
+func functionArguments(choice *llms.ContentChoice, name string) []string {
+	var arguments []string
+	for _, call := range choice.ToolCalls {
+		if call.FunctionCall != nil && call.FunctionCall.Name == name {
+			arguments = append(arguments, call.FunctionCall.Arguments)
+		}
+	}
+	if len(arguments) == 0 && choice.FuncCall != nil && choice.FuncCall.Name == name {
+		arguments = append(arguments, choice.FuncCall.Arguments)
+	}
+	if len(arguments) == 0 && strings.TrimSpace(choice.Content) != "" {
+		content := strings.TrimSpace(choice.Content)
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+		arguments = append(arguments, strings.TrimSpace(content))
+	}
+	return arguments
+}
+
+func (c Extraction[T]) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Memory
+}
+
+func (c Extraction[T]) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c Extraction[T]) GetOutputKeys() []string {
+	if c.Many {
+		return []string{c.OutputKey, _extractionErrorKey}
+	}
+	return []string{c.OutputKey}
+}
@@ -0,0 +1,77 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+type extractedPerson struct {
+	Name string `json:"name"`
+	Age  int    `json:"age,omitempty"`
+}
+
+func (p extractedPerson) Validate() error {
+	if p.Age < 0 {
+		return errors.New("negative age")
+	}
+	return nil
+}
+
+func extractCall(arguments string) fake.Response {
+	return fake.Response{ToolCalls: []llms.ToolCall{{
+		ID:           "call",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "extract", Arguments: arguments},
+	}}}
+}
+
+func TestExtraction(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		extractCall(`{"age": 30}`),
+		extractCall(`{"name": "Alice", "age": 30}`),
+	)
+	c := NewExtraction[extractedPerson](llm, false)
+	result, err := Call(context.Background(), c, map[string]any{"input": "Alice is 30."})
+	require.NoError(t, err)
+	require.Equal(t, extractedPerson{Name: "Alice", Age: 30}, result["extracted"])
+
+	calls := llm.Calls()
+	require.Len(t, calls, 2)
+	require.Equal(t, jsonschema.For[extractedPerson](), calls[0].Options.Tools[0].Function.Parameters)
+	require.Contains(t, calls[1].Messages[1].Parts[0].(llms.TextContent).Text, "missing required property name")
+
+	llm.AddResponses(fake.TextResponses(`{"name": "Bob", "age": -1}`, "Bob", "```json\n{\"age\": 3}\n```")...)
+	_, err = c.Extract(context.Background(), "Bob")
+	require.ErrorIs(t, err, ErrInvalidExtraction)
+	require.ErrorContains(t, err, "missing required property name")
+}
+
+func TestExtractionMany(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		extractCall(`{"items": [{"name": "Alice", "age": 30}, {"age": 4}]}`),
+		extractCall(`{"items": [{"name": "Alice", "age": 30}, {"name": "Bob", "age": -4}]}`),
+		extractCall(`{"items": []}`),
+	)
+	c := NewExtraction[extractedPerson](llm, true)
+	c.MaxRetries = 1
+	result, err := Call(context.Background(), c, map[string]any{"input": "Alice is 30, Bob is 4."})
+	require.NoError(t, err)
+	require.Equal(t, []extractedPerson{{Name: "Alice", Age: 30}}, result["extracted"])
+	extractionErr, _ := result["extraction_error"].(error)
+	require.ErrorIs(t, extractionErr, ErrPartialExtraction)
+	require.ErrorContains(t, extractionErr, "item 1: negative age")
+
+	instances, err := c.Extract(context.Background(), "Nobody.")
+	require.NoError(t, err)
+	require.Empty(t, instances)
+}
@@ -0,0 +1,232 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/graphs"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultCypherGenerationTemplate = `Task: Generate a Cypher statement to query a graph database.
+Instructions:
+Use only the provided relationship types and properties in the schema.
+Do not use any other relationship types or properties that are not provided.
+Only write read-only queries, never queries creating, updating or deleting data.
+Schema:
+{{.schema}}
+Note: Do not include any explanations or apologies in your responses.
+Do not respond to any questions that might ask anything else than for you to construct a Cypher statement.
+Do not include any text except the generated Cypher statement.
+
+The question is:
+{{.question}}`
+
+//nolint:lll
+const _defaultCypherQATemplate = `You are an assistant that helps to form nice and human understandable answers.
+The information part contains the provided information that you must use to construct an answer.
+The provided information is authoritative, you must never doubt it or try to use your internal knowledge to correct it.
+Make the answer sound as a response to the question. Do not mention that you based the result on the given information.
+If the provided information is empty, say that you don't know the answer.
+Information:
+{{.context}}
+
+Question: {{.question}}
+Helpful Answer:`
+
+const (
+	_graphCypherQADefaultInputKey   = "query"
+	_graphCypherQADefaultOutputKey  = "result"
+	_graphCypherQADefaultTopK       = 10
+	_graphCypherQADefaultMaxRetries = 2
+)
+
+// ErrUnsafeCypherQuery is returned when the Cypher query generated by the LLM
+// of a GraphCypherQAChain isn't read-only.
+var ErrUnsafeCypherQuery = errors.New("unsafe cypher query")
+
+// _cypherDeniedKeywords are the keywords of the clauses writing to the graph
+// or the database.
+//
+//nolint:gochecknoglobals
+var _cypherDeniedKeywords = []string{
+	"CREATE", "MERGE", "DELETE", "DETACH", "SET", "REMOVE", "DROP", "LOAD", "FOREACH",
+	"GRANT", "DENY", "REVOKE", "ALTER", "RENAME",
+}
+
+var (
+	_cypherFenceRE = regexp.MustCompile("(?s)```(?:cypher)?\\s*(.*?)```")
+	// _cypherLiteralRE matches the string literals, quoted identifiers and
+	// comments of queries.
+	_cypherLiteralRE = regexp.MustCompile("(?s)'(?:[^'\\\\]|\\\\.)*'|\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`|//[^\n]*|/\\*.*?\\*/")
+	_cypherWordRE    = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// GraphCypherQAChain is a chain answering questions about a graph database:
+// the LLM writes a Cypher query from the schema of the graph and the
+// question, the query is run, and the LLM answers from its results. It is the
+// graph counterpart of the SQLDatabaseChain.
+//
+// Queries writing to the graph are rejected, and the graph should also run
+// them in read access mode, as the Neo4j graph does. Invalid or failing
+// queries are given back to the LLM to correct them, up to MaxRetries times.
+type GraphCypherQAChain struct {
+	CypherGenerationChain *LLMChain
+	QAChain               *LLMChain
+	Graph                 graphs.Graph
+	// TopK is the number of records of the results given to the LLM.
+	TopK int
+	// MaxRetries is the number of times the LLM is asked to correct an
+	// invalid or failing query.
+	MaxRetries int
+	// ReturnIntermediateSteps is whether the query and its records are
+	// returned under the "cypher" and "context" output keys.
+	ReturnIntermediateSteps bool
+	// DeniedKeywords are the keywords, outside of literals and quoted
+	// identifiers, rejected in queries.
+	DeniedKeywords []string
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = GraphCypherQAChain{}
+
+// NewGraphCypherQAChain creates a new chain answering questions about the
+// graph.
+func NewGraphCypherQAChain(llm llms.Model, graph graphs.Graph) GraphCypherQAChain {
+	return GraphCypherQAChain{
+		CypherGenerationChain: NewLLMChain(llm, prompts.NewPromptTemplate(_defaultCypherGenerationTemplate,
+			[]string{"schema", "question"})),
+		QAChain: NewLLMChain(llm, prompts.NewPromptTemplate(_defaultCypherQATemplate,
+			[]string{"context", "question"})),
+		Graph:          graph,
+		TopK:           _graphCypherQADefaultTopK,
+		MaxRetries:     _graphCypherQADefaultMaxRetries,
+		DeniedKeywords: _cypherDeniedKeywords,
+		InputKey:       _graphCypherQADefaultInputKey,
+		OutputKey:      _graphCypherQADefaultOutputKey,
+	}
+}
+
+// Call generates and runs the Cypher query of the question, and answers it
+// from the results. Only the LLM call generating the answer streams.
+func (c GraphCypherQAChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	question, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+	graphSchema, err := c.Graph.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate and run the query, feeding errors back to the llm.
+	llmInputs := map[string]any{"schema": graphSchema, "question": question}
+	var query string
+	var records []map[string]any
+	for attempt := 0; ; attempt++ {
+		out, err := Predict(ctx, c.CypherGenerationChain, llmInputs, withoutStreaming(options)...)
+		if err != nil {
+			return nil, err
+		}
+
+		query = extractCypherQuery(out)
+		records, err = c.runQuery(ctx, query)
+		if err == nil {
+			break
+		}
+		if attempt >= c.MaxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+		llmInputs["question"] = question + "\n\nThe Cypher statement:\n" + query + "\nfailed with the error: " +
+			err.Error() + "\nGenerate a corrected Cypher statement."
+	}
+
+	if c.TopK > 0 && len(records) > c.TopK {
+		records = records[:c.TopK]
+	}
+	results, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	answer, err := Predict(ctx, c.QAChain, map[string]any{
+		"context":  string(results),
+		"question": question,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]any{c.OutputKey: strings.TrimSpace(answer)}
+	if c.ReturnIntermediateSteps {
+		outputs["cypher"] = query
+		outputs["context"] = records
+	}
+	return outputs, nil
+}
+
+// runQuery validates the query and runs it.
+func (c GraphCypherQAChain) runQuery(ctx context.Context, query string) ([]map[string]any, error) {
+	if query == "" {
+		return nil, fmt.Errorf("no cypher query generated")
+	}
+	if err := c.validateCypherQuery(query); err != nil {
+		return nil, err
+	}
+	return c.Graph.Query(ctx, query, nil)
+}
+
+// validateCypherQuery returns an error wrapping ErrUnsafeCypherQuery if the
+// query has a denied keyword outside of its literals, quoted identifiers and
+// comments. Property names, labels and relationship types, following a dot or
+// a colon, aren't keywords.
+func (c GraphCypherQAChain) validateCypherQuery(query string) error {
+	stripped := _cypherLiteralRE.ReplaceAllString(query, " ")
+	for _, loc := range _cypherWordRE.FindAllStringIndex(stripped, -1) {
+		if prefix := strings.TrimRight(stripped[:loc[0]], " \t\n"); strings.HasSuffix(prefix, ".") ||
+			strings.HasSuffix(prefix, ":") {
+			continue
+		}
+		word := stripped[loc[0]:loc[1]]
+		if slices.ContainsFunc(c.DeniedKeywords, func(keyword string) bool {
+			return strings.EqualFold(keyword, word)
+		}) {
+			return fmt.Errorf("%w: %s is not allowed", ErrUnsafeCypherQuery, strings.ToUpper(word))
+		}
+	}
+	return nil
+}
+
+// extractCypherQuery returns the query of the output of the LLM, removing
+// the code fences around it.
+func extractCypherQuery(out string) string {
+	if match := _cypherFenceRE.FindStringSubmatch(out); match != nil {
+		out = match[1]
+	}
+	return strings.TrimSpace(out)
+}
+
+func (c GraphCypherQAChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c GraphCypherQAChain) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c GraphCypherQAChain) GetOutputKeys() []string {
+	if c.ReturnIntermediateSteps {
+		return []string{c.OutputKey, "cypher", "context"}
+	}
+	return []string{c.OutputKey}
+}
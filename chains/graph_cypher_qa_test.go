@@ -0,0 +1,87 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/graphs"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+// testGraph records the queries run and fails those with unknown labels.
+type testGraph struct {
+	queries []string
+}
+
+var _ graphs.Graph = &testGraph{}
+
+func (g *testGraph) Query(_ context.Context, query string, _ map[string]any) ([]map[string]any, error) {
+	g.queries = append(g.queries, query)
+	if strings.Contains(query, ":Actor") {
+		return nil, errors.New("unknown label Actor")
+	}
+	return []map[string]any{{"m.title": "The Matrix"}, {"m.title": "John Wick"}}, nil
+}
+
+func (g *testGraph) Schema(_ context.Context) (string, error) {
+	return "Node properties:\nMovie {title: String}\nPerson {name: String}\n" +
+		"The relationships:\n(:Person)-[:ACTED_IN]->(:Movie)\n", nil
+}
+
+func TestGraphCypherQAChain(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(fake.TextResponses(
+		"MATCH (p:Actor {name: 'Keanu Reeves'})-[:ACTED_IN]->(m) RETURN m.title",
+		"```cypher\nMATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie) RETURN m.title\n```",
+		"Keanu Reeves acted in The Matrix.",
+	)...)
+	graph := &testGraph{}
+	c := NewGraphCypherQAChain(llm, graph)
+	c.TopK = 1
+	c.ReturnIntermediateSteps = true
+
+	result, err := Call(context.Background(), c, map[string]any{"query": "Which movies did Keanu Reeves act in?"})
+	require.NoError(t, err)
+	require.Equal(t, "Keanu Reeves acted in The Matrix.", result["result"])
+	require.Equal(t, "MATCH (p:Person {name: 'Keanu Reeves'})-[:ACTED_IN]->(m:Movie) RETURN m.title", result["cypher"])
+	require.Equal(t, []map[string]any{{"m.title": "The Matrix"}}, result["context"])
+	require.Len(t, graph.queries, 2)
+
+	calls := llm.Calls()
+	require.Contains(t, calls[0].Messages[0].Parts[0].(llms.TextContent).Text, "(:Person)-[:ACTED_IN]->(:Movie)")
+	require.Contains(t, calls[1].Messages[0].Parts[0].(llms.TextContent).Text, "unknown label Actor")
+	require.Contains(t, calls[2].Messages[0].Parts[0].(llms.TextContent).Text, `[{"m.title":"The Matrix"}]`)
+
+	c.MaxRetries = 0
+	llm.AddResponses(fake.TextResponses("MATCH (m:Movie) DETACH DELETE m")...)
+	_, err = Call(context.Background(), c, map[string]any{"query": "Delete all the movies."})
+	require.ErrorIs(t, err, ErrUnsafeCypherQuery)
+	require.Len(t, graph.queries, 2)
+}
+
+func TestValidateCypherQuery(t *testing.T) {
+	t.Parallel()
+
+	c := NewGraphCypherQAChain(fake.New(), &testGraph{})
+	for query, safe := range map[string]bool{
+		"MATCH (n:Person) RETURN n.name":                            true,
+		"MATCH (n {status: 'delete me'}) RETURN n.set, n.remove":    true,
+		"MATCH (n:`CREATE`) // set it\nRETURN n":                    true,
+		"MATCH (n:Person) SET n.name = 'Bob'":                       false,
+		"MERGE (n:Person {name: 'Bob'})":                            false,
+		"MATCH (n) WITH n CALL { WITH n DETACH DELETE n } RETURN 1": false,
+		"LOAD CSV FROM 'file:///x.csv' AS row RETURN row":           false,
+	} {
+		err := c.validateCypherQuery(query)
+		if safe {
+			require.NoError(t, err, query)
+		} else {
+			require.ErrorIs(t, err, ErrUnsafeCypherQuery, query)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/schema"
 	"golang.org/x/exp/maps"
@@ -38,6 +39,16 @@ type MapReduceDocuments struct {
 
 	// Whether to add the intermediate steps to the output.
 	ReturnIntermediateSteps bool
+
+	// MaxTokens is the maximum number of tokens of the prompts of the
+	// LLMChain. If set, the documents are batched, in order, into as few calls
+	// of the LLMChain as fit, documents larger than a prompt being split,
+	// instead of mapping each document on its own. Zero means no batching.
+	MaxTokens int
+
+	// TokenCounter measures the prompts when batching. Defaults to the token
+	// counting of the model of the LLMChain.
+	TokenCounter llms.TokenCounter
 }
 
 var _ Chain = MapReduceDocuments{}
@@ -63,8 +74,17 @@ func (c MapReduceDocuments) Call(ctx context.Context, values map[string]any, opt
 		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
 	}
 
-	// Execute the chain with each of the documents asynchronously.
-	mapResults, err := Apply(ctx, c.LLMChain, c.getApplyInputs(values, docs), c.MaxNumberOfConcurrent, options...)
+	if c.MaxTokens > 0 {
+		var err error
+		docs, err = c.batchDocuments(ctx, values, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Execute the chain with each of the documents asynchronously. Only the reduce chain streams.
+	mapResults, err := Apply(ctx, c.LLMChain, c.getApplyInputs(values, docs), c.MaxNumberOfConcurrent,
+		withoutStreaming(options)...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +99,19 @@ func (c MapReduceDocuments) Call(ctx context.Context, values map[string]any, opt
 	return c.maybeAddIntermediateSteps(result, mapResults), err
 }
 
+// batchDocuments groups the documents into batches fitting in the prompt of
+// the LLMChain.
+func (c MapReduceDocuments) batchDocuments(ctx context.Context, values map[string]any, docs []schema.Document) ([]schema.Document, error) { //nolint:lll
+	llmChainInputVariable := c.getInputVariable(c.LLMChainInputVariableName, c.LLMChain.GetInputKeys())
+	measure := tokenMeasure{counter: c.TokenCounter, model: c.LLMChain.LLM}
+	promptValues := c.copyInputValuesWithoutInputKey(values)
+	promptTokens, err := measure.promptTokens(ctx, c.LLMChain, promptValues, llmChainInputVariable)
+	if err != nil {
+		return nil, err
+	}
+	return measure.batchDocuments(ctx, docs, c.MaxTokens-promptTokens, _stuffDocumentsDefaultSeparator)
+}
+
 // If the LLMChain or the reduce chain only has one input variable, it will be used to place the
 // input automatically.
 func (c MapReduceDocuments) getInputVariable(givenInputName string, chainInputVariables []string) string {
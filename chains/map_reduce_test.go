@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
 )
@@ -56,3 +57,33 @@ func TestMapReduce(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "foo\n\nboo\n\nzoo\n\ndoo", result)
 }
+
+func TestMapReduceMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	c := NewMapReduceDocuments(
+		NewLLMChain(
+			&testLanguageModel{},
+			prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+		),
+		NewStuffDocuments(
+			NewLLMChain(
+				&testLanguageModel{},
+				prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+			),
+		),
+	)
+	c.MaxTokens = 8
+	c.TokenCounter = llms.ApproximateTokenCounter{CharsPerToken: 1}
+	c.ReturnIntermediateSteps = true
+
+	result, err := Call(context.Background(), c, map[string]any{"input_documents": []schema.Document{
+		{PageContent: "foo"},
+		{PageContent: "boo"},
+		{PageContent: "zoooooooooooo"},
+		{PageContent: "doo"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "foo\n\nboo\n\nzooooooo\n\nooooo\n\ndoo", result["text"])
+	require.Len(t, result[_intermediateStepsOutputKey], 4)
+}
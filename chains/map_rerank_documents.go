@@ -3,8 +3,10 @@ package chains
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/outputparser"
@@ -13,11 +15,15 @@ import (
 )
 
 const (
-	_mapRerankDocumentsDefaultDocumentTemplate = "{{.page_content}}"
-	_mapRerankDocumentsDefaultRankKey          = "score"
-	_mapRerankDocumentsDefaultAnswerKey        = "answer"
+	_mapRerankDocumentsDefaultDocumentTemplate  = "{{.page_content}}"
+	_mapRerankDocumentsDefaultRankKey           = "score"
+	_mapRerankDocumentsDefaultAnswerKey         = "answer"
+	_mapRerankDocumentsDefaultSourceDocumentKey = "source_documents"
 )
 
+// MapRerankDocuments is a chain that answers with each document separately,
+// asking the LLM to score how fully each answer answers the question, and
+// returns the best-scoring answer.
 type MapRerankDocuments struct {
 	// Chain used to rerank the documents.
 	LLMChain *LLMChain
@@ -45,13 +51,17 @@ type MapRerankDocuments struct {
 
 	// When true, the intermediate steps of the map rerank are returned.
 	ReturnIntermediateSteps bool
+
+	// When true, the document of the best-scoring answer is returned in the
+	// "source_documents" key, as a slice of one document.
+	ReturnSourceDocuments bool
 }
 
 var _ Chain = MapRerankDocuments{}
 
 // NewMapRerankDocuments creates a new map rerank documents chain.
 func NewMapRerankDocuments(mapRerankLLMChain *LLMChain) *MapRerankDocuments {
-	mapRerankRE := `\s*(?P<answer>.*?)\nScore: (?P<score>.*)`
+	mapRerankRE := `(?s)\s*(?P<answer>.*?)\n\s*Score: (?P<score>.*)`
 	mapRerankLLMChain.OutputParser = outputparser.NewRegexParser(mapRerankRE)
 
 	return &MapRerankDocuments{
@@ -79,14 +89,18 @@ func (c MapRerankDocuments) Call(ctx context.Context, values map[string]any, opt
 		return nil, fmt.Errorf("%w: documents slice has no elements", ErrInvalidInputValues)
 	}
 
+	// the answers are scored before the best one is known, so none of them streams.
 	applyInputs := c.getApplyInputs(values, docs)
-	mapResults, err := Apply(ctx, c.LLMChain, applyInputs, c.MaxConcurrentWorkers, options...)
+	mapResults, err := Apply(ctx, c.LLMChain, applyInputs, c.MaxConcurrentWorkers, withoutStreaming(options)...)
 	if err != nil {
 		return nil, err
 	}
 
-	// create a slice of outputs to return after sorting the ranks.
+	// create a slice of outputs to return after sorting the ranks, with the
+	// documents they answer with.
 	outputs := make([]map[string]any, len(mapResults))
+	sources := make([]schema.Document, len(mapResults))
+	scores := make([]float64, len(mapResults))
 
 	for i, res := range mapResults {
 		rankedAnswer, ok := res[c.LLMChain.OutputKey].(map[string]string)
@@ -95,23 +109,46 @@ func (c MapRerankDocuments) Call(ctx context.Context, values map[string]any, opt
 		}
 
 		outputs[i] = c.parseMapResults(rankedAnswer)
+		sources[i] = docs[i]
+		scores[i] = c.parseScore(rankedAnswer[c.RankKey])
 	}
 
-	sort.Slice(outputs, func(i, j int) bool {
-		curr, err := strconv.Atoi(outputs[i][c.RankKey].(string))
-		if err != nil {
-			return false
-		}
+	// sort by decreasing score, keeping the order of the documents for equal
+	// scores.
+	sort.Stable(rankedOutputs{outputs: outputs, sources: sources, scores: scores})
 
-		compare, err := strconv.Atoi(outputs[j][c.RankKey].(string))
-		if err != nil {
-			return true
-		}
+	return c.formatOutputs(outputs, sources[0]), nil
+}
+
+// parseScore parses a score such as "85" or "85.5", which may be followed
+// by text. Unparsable scores rank last.
+func (c MapRerankDocuments) parseScore(score string) float64 {
+	fields := strings.Fields(score)
+	if len(fields) == 0 {
+		return math.Inf(-1)
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimRight(fields[0], ".,;"), 64)
+	if err != nil {
+		return math.Inf(-1)
+	}
+
+	return value
+}
 
-		return curr > compare
-	})
+// rankedOutputs sorts the outputs and their documents by decreasing score.
+type rankedOutputs struct {
+	outputs []map[string]any
+	sources []schema.Document
+	scores  []float64
+}
 
-	return c.formatOutputs(outputs), nil
+func (r rankedOutputs) Len() int           { return len(r.outputs) }
+func (r rankedOutputs) Less(i, j int) bool { return r.scores[i] > r.scores[j] }
+func (r rankedOutputs) Swap(i, j int) {
+	r.outputs[i], r.outputs[j] = r.outputs[j], r.outputs[i]
+	r.sources[i], r.sources[j] = r.sources[j], r.sources[i]
+	r.scores[i], r.scores[j] = r.scores[j], r.scores[i]
 }
 
 // getInputVariable returns the input variable name to use for the LLM chain.
@@ -155,8 +192,9 @@ func (c MapRerankDocuments) parseMapResults(inputs map[string]string) map[string
 	return outputs
 }
 
-// formatOutputs returns the first output and the intermediate steps, if enabled.
-func (c MapRerankDocuments) formatOutputs(outputs []map[string]any) map[string]any {
+// formatOutputs returns the first output, its source document and the
+// intermediate steps, if enabled.
+func (c MapRerankDocuments) formatOutputs(outputs []map[string]any, source schema.Document) map[string]any {
 	if len(outputs) == 0 {
 		return nil
 	}
@@ -166,6 +204,10 @@ func (c MapRerankDocuments) formatOutputs(outputs []map[string]any) map[string]a
 
 	formattedOutputs[c.LLMChain.OutputKey] = answerOutput[c.AnswerKey]
 
+	if c.ReturnSourceDocuments {
+		formattedOutputs[_mapRerankDocumentsDefaultSourceDocumentKey] = []schema.Document{source}
+	}
+
 	if !c.ReturnIntermediateSteps {
 		return formattedOutputs
 	}
@@ -192,6 +234,10 @@ func (c MapRerankDocuments) GetInputKeys() []string {
 func (c MapRerankDocuments) GetOutputKeys() []string {
 	outputKeys := c.LLMChain.GetOutputKeys()
 
+	if c.ReturnSourceDocuments {
+		outputKeys = append(outputKeys, _mapRerankDocumentsDefaultSourceDocumentKey)
+	}
+
 	if c.ReturnIntermediateSteps {
 		outputKeys = append(outputKeys, _intermediateStepsOutputKey)
 	}
@@ -67,3 +67,28 @@ func TestMapRerankDocumentsCall(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestMapRerankDocumentsSourceDocuments(t *testing.T) {
+	t.Parallel()
+
+	mapRerankLLMChain := NewLLMChain(
+		&testLanguageModel{},
+		prompts.NewPromptTemplate("{{.context}}", []string{"context"}),
+	)
+
+	docs := []schema.Document{
+		{PageContent: "Test Low\nScore: 20.5", Metadata: map[string]any{"source": "low"}},
+		{PageContent: "Test\nHigh\nScore: 85.5 (detailed)", Metadata: map[string]any{"source": "high"}},
+		{PageContent: "Test Equal\nScore: 85.5", Metadata: map[string]any{"source": "equal"}},
+		{PageContent: "Test Unscored\nScore: none", Metadata: map[string]any{"source": "unscored"}},
+	}
+
+	mapRerankDocumentsChain := NewMapRerankDocuments(mapRerankLLMChain)
+	mapRerankDocumentsChain.ReturnSourceDocuments = true
+	require.Equal(t, []string{"text", "source_documents"}, mapRerankDocumentsChain.GetOutputKeys())
+
+	result, err := Call(context.Background(), mapRerankDocumentsChain, map[string]any{"input_documents": docs})
+	require.NoError(t, err)
+	require.Equal(t, "Test\nHigh", result["text"])
+	require.Equal(t, []schema.Document{docs[1]}, result["source_documents"])
+}
@@ -0,0 +1,229 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultMultimodalQATemplate = `Use the sources above, their text and their images, to answer the following question. If you don't know the answer, just say that you don't know, don't try to make up an answer.
+
+Question: {{.question}}
+Helpful Answer:`
+
+const (
+	_multimodalRetrievalQADefaultImagesKey = "images"
+	_multimodalRetrievalQADefaultMaxImages = 8
+)
+
+// ErrUnsupportedImage is returned when the reference of an image of a
+// document can't be loaded by the image loader.
+var ErrUnsupportedImage = errors.New("unsupported image reference")
+
+// ImageLoader returns the content part of the image referenced in the
+// metadata of a document.
+type ImageLoader func(ctx context.Context, ref string) (llms.ContentPart, error)
+
+// MultimodalRetrievalQA is a chain used for question-answering against a
+// retriever whose documents may have images, e.g. the pages of manuals or the
+// slides of decks. The references of the images of a document are stored in
+// its metadata, under the ImagesKey, as a string or a list of strings, and
+// loaded into the content parts of the prompt by the ImageLoader, so that a
+// vision-capable model sees each source with its images.
+type MultimodalRetrievalQA struct {
+	// Retriever used to retrieve the relevant documents.
+	Retriever schema.Retriever
+
+	// LLM is the vision-capable model answering the question.
+	LLM llms.Model
+
+	// Prompt is the prompt following the sources, formatted with the
+	// "question".
+	Prompt prompts.FormatPrompter
+
+	// ImagesKey is the metadata key of the image references of the documents,
+	// by default "images".
+	ImagesKey string
+
+	// ImageLoader loads the images of the documents. Defaults to
+	// LoadImageURL.
+	ImageLoader ImageLoader
+
+	// MaxImages is the maximum number of images in the prompt, the images of
+	// the most relevant documents coming first. Zero means no limit.
+	MaxImages int
+
+	// The input key to get the query from, by default "query".
+	InputKey string
+
+	// The output key of the answer, by default "text".
+	OutputKey string
+
+	// If the chain should return the retrieved documents in the
+	// "source_documents" key.
+	ReturnSourceDocuments bool
+}
+
+var _ Chain = MultimodalRetrievalQA{}
+
+// NewMultimodalRetrievalQA creates a new MultimodalRetrievalQA answering with
+// the model the questions about the documents of the retriever.
+func NewMultimodalRetrievalQA(llm llms.Model, retriever schema.Retriever) MultimodalRetrievalQA {
+	return MultimodalRetrievalQA{
+		Retriever:   retriever,
+		LLM:         llm,
+		Prompt:      prompts.NewPromptTemplate(_defaultMultimodalQATemplate, []string{"question"}),
+		ImagesKey:   _multimodalRetrievalQADefaultImagesKey,
+		ImageLoader: LoadImageURL,
+		MaxImages:   _multimodalRetrievalQADefaultMaxImages,
+		InputKey:    _retrievalQADefaultInputKey,
+		OutputKey:   _llmChainDefaultOutputKey,
+	}
+}
+
+// Call gets relevant documents from the retriever, and asks the LLM the
+// question with the text and images of the documents.
+func (c MultimodalRetrievalQA) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	query, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	docs, err := c.Retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := c.sourceParts(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := c.Prompt.FormatPrompt(map[string]any{"question": query})
+	if err != nil {
+		return nil, err
+	}
+	parts = append(parts, llms.TextPart(prompt.String()))
+
+	resp, err := c.LLM.GenerateContent(ctx, []llms.MessageContent{
+		{Role: llms.ChatMessageTypeHuman, Parts: parts},
+	}, getLLMCallOptions(options...)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, ErrInvalidOutputValues
+	}
+
+	result := map[string]any{c.OutputKey: resp.Choices[0].Content}
+	if c.ReturnSourceDocuments {
+		result[_retrievalQADefaultSourceDocumentKey] = docs
+	}
+	return result, nil
+}
+
+// sourceParts returns the content parts of the numbered documents, each
+// document being followed by its images.
+func (c MultimodalRetrievalQA) sourceParts(ctx context.Context, docs []schema.Document) ([]llms.ContentPart, error) {
+	loader := c.ImageLoader
+	if loader == nil {
+		loader = LoadImageURL
+	}
+
+	parts := make([]llms.ContentPart, 0, len(docs)+1)
+	images := 0
+	for i, doc := range docs {
+		parts = append(parts, llms.TextPart(fmt.Sprintf("Source %d:\n%s\n", i+1, doc.PageContent)))
+		for _, ref := range imageRefs(doc.Metadata[c.ImagesKey]) {
+			if c.MaxImages > 0 && images >= c.MaxImages {
+				break
+			}
+			part, err := loader(ctx, ref)
+			if err != nil {
+				return nil, fmt.Errorf("loading image %q of source %d: %w", ref, i+1, err)
+			}
+			parts = append(parts, part)
+			images++
+		}
+	}
+	return parts, nil
+}
+
+// imageRefs returns the image references of a metadata value, a string or a
+// list of strings.
+func imageRefs(value any) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		refs := make([]string, 0, len(v))
+		for _, ref := range v {
+			if s, ok := ref.(string); ok && s != "" {
+				refs = append(refs, s)
+			}
+		}
+		return refs
+	default:
+		return nil
+	}
+}
+
+// LoadImageURL is an ImageLoader for images referenced by http, https or data
+// URLs, which are given to the model as image URLs.
+func LoadImageURL(_ context.Context, ref string) (llms.ContentPart, error) { //nolint:ireturn
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "data:") {
+		return llms.ImageURLPart(ref), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, ref)
+}
+
+// NewFileImageLoader returns an ImageLoader for images referenced by URLs, as
+// LoadImageURL, or by paths relative to the directory, whose files are given
+// to the model as binary content. Paths can't refer to files outside of the
+// directory.
+func NewFileImageLoader(dir string) ImageLoader {
+	return func(ctx context.Context, ref string) (llms.ContentPart, error) {
+		if part, err := LoadImageURL(ctx, ref); err == nil {
+			return part, nil
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.Clean(string(filepath.Separator)+ref)))
+		if err != nil {
+			return nil, err
+		}
+		mimeType := http.DetectContentType(data)
+		if !strings.HasPrefix(mimeType, "image/") {
+			return nil, fmt.Errorf("%w: %s is %s", ErrUnsupportedImage, ref, mimeType)
+		}
+		return llms.BinaryPart(mimeType, data), nil
+	}
+}
+
+func (c MultimodalRetrievalQA) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c MultimodalRetrievalQA) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c MultimodalRetrievalQA) GetOutputKeys() []string {
+	outputKeys := []string{c.OutputKey}
+	if c.ReturnSourceDocuments {
+		outputKeys = append(outputKeys, _retrievalQADefaultSourceDocumentKey)
+	}
+	return outputKeys
+}
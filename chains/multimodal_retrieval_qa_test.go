@@ -0,0 +1,63 @@
+package chains
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type testDocumentsRetriever []schema.Document
+
+func (r testDocumentsRetriever) GetRelevantDocuments(_ context.Context, _ string) ([]schema.Document, error) {
+	return r, nil
+}
+
+func TestMultimodalRetrievalQA(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "slide2.png"), png, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o600))
+
+	docs := testDocumentsRetriever{
+		{PageContent: "Slide 1: the wiring.", Metadata: map[string]any{"images": "https://example.com/slide1.png"}},
+		{PageContent: "Slide 2: the fuse box.", Metadata: map[string]any{"images": []any{"slide2.png", "../slide2.png"}}},
+		{PageContent: "Slide 3: no picture."},
+	}
+	llm := fake.New(fake.TextResponses("The fuse box is on the left.")...)
+	c := NewMultimodalRetrievalQA(llm, docs)
+	c.ImageLoader = NewFileImageLoader(dir)
+	c.MaxImages = 2
+	c.ReturnSourceDocuments = true
+
+	result, err := Call(context.Background(), c, map[string]any{"query": "Where is the fuse box?"})
+	require.NoError(t, err)
+	require.Equal(t, "The fuse box is on the left.", result["text"])
+	require.Equal(t, []schema.Document(docs), result["source_documents"])
+
+	parts := llm.LastCall().Messages[0].Parts
+	require.Equal(t, []llms.ContentPart{
+		llms.TextPart("Source 1:\nSlide 1: the wiring.\n"),
+		llms.ImageURLPart("https://example.com/slide1.png"),
+		llms.TextPart("Source 2:\nSlide 2: the fuse box.\n"),
+		llms.BinaryPart("image/png", png),
+		llms.TextPart("Source 3:\nSlide 3: no picture.\n"),
+	}, parts[:5])
+	require.Contains(t, parts[5].(llms.TextContent).Text, "Question: Where is the fuse box?")
+
+	docs[2].Metadata = map[string]any{"images": "notes.txt"}
+	c.MaxImages = 0
+	_, err = Call(context.Background(), c, map[string]any{"query": "Where is the fuse box?"})
+	require.ErrorIs(t, err, ErrUnsupportedImage)
+
+	c.ImageLoader = LoadImageURL
+	_, err = Call(context.Background(), c, map[string]any{"query": "Where is the fuse box?"})
+	require.ErrorIs(t, err, ErrUnsupportedImage)
+}
@@ -0,0 +1,554 @@
+package chains
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+//nolint:lll
+const _defaultOpenAPIOperationTemplate = `You are given the operations of an API as functions. Call the function of the operation whose response answers the following input, with the parameters the input gives. Only fill in the parameters you know the values of.
+
+Input: {{.input}}`
+
+//nolint:lll
+const _defaultOpenAPIAnswerTemplate = `The following API operation was called to answer the input.
+
+Input: {{.input}}
+Operation: {{.operation}}
+Status: {{.status}}
+Response:
+{{.api_response}}
+
+Answer the input from the response of the API. Your answer should highlight the key information of the response that answers or relates to the input, and be concise, yet informative. If the call failed, explain why.
+
+Answer:`
+
+const (
+	_openAPIChainDefaultInputKey          = "input"
+	_openAPIChainDefaultOutputKey         = "answer"
+	_openAPIChainDefaultMaxResponseLength = 8000
+	_openAPIMaxRefDepth                   = 16
+)
+
+var (
+	// ErrNoOpenAPIOperation is returned by an OpenAPIChain when the LLM
+	// doesn't select an allowed operation for the input.
+	ErrNoOpenAPIOperation = errors.New("no api operation selected")
+	// ErrInvalidOpenAPIArguments is returned by an OpenAPIChain when the LLM
+	// doesn't fill the required parameters of the operation.
+	ErrInvalidOpenAPIArguments = errors.New("invalid api operation arguments")
+)
+
+// _openAPIToolNameRE matches the characters not allowed in function names by
+// LLM providers.
+var _openAPIToolNameRE = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// _openAPIMethods are the HTTP methods of the operations of path items.
+//
+//nolint:gochecknoglobals
+var _openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OpenAPISpec is an OpenAPI 3 specification, with the parts of it describing
+// the operations of the API.
+type OpenAPISpec struct {
+	Info struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	// Operations are the operations of the paths of the API, sorted by path
+	// and method.
+	Operations []OpenAPIOperation `yaml:"-"`
+}
+
+// OpenAPIOperation is an operation of an OpenAPI specification.
+type OpenAPIOperation struct {
+	// ID is the operationId of the operation, or, if it has none, its method
+	// and path.
+	ID          string              `yaml:"operationId"`
+	Method      string              `yaml:"-"`
+	Path        string              `yaml:"-"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Parameters  []OpenAPIParameter  `yaml:"parameters"`
+	RequestBody *OpenAPIRequestBody `yaml:"requestBody"`
+}
+
+// OpenAPIParameter is a parameter of an OpenAPI operation.
+type OpenAPIParameter struct {
+	Name string `yaml:"name"`
+	// In is the location of the parameter: path, query, header or cookie.
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      map[string]any `yaml:"schema"`
+}
+
+// OpenAPIRequestBody is the request body of an OpenAPI operation.
+type OpenAPIRequestBody struct {
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	// Content maps media types to the schemas of the body.
+	Content map[string]struct {
+		Schema map[string]any `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+// LoadOpenAPISpec loads an OpenAPI 3 specification in JSON or YAML. The local
+// references of the specification, e.g. to "#/components/schemas/Pet", are
+// resolved.
+func LoadOpenAPISpec(data []byte) (*OpenAPISpec, error) {
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	resolved, ok := resolveOpenAPIRefs(document, document, 0).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid openapi spec")
+	}
+	// decode the resolved document into the spec.
+	data, err := yaml.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	spec := &OpenAPISpec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+
+	var paths map[string]map[string]yaml.Node
+	if raw, ok := resolved["paths"]; ok {
+		data, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &paths); err != nil {
+			return nil, err
+		}
+	}
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	for _, path := range pathNames {
+		item := paths[path]
+		var common OpenAPIOperation
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&common.Parameters); err != nil {
+				return nil, err
+			}
+		}
+		for _, method := range _openAPIMethods {
+			node, ok := item[method]
+			if !ok {
+				continue
+			}
+			var operation OpenAPIOperation
+			if err := node.Decode(&operation); err != nil {
+				return nil, err
+			}
+			operation.Method, operation.Path = strings.ToUpper(method), path
+			if operation.ID == "" {
+				operation.ID = method + " " + path
+			}
+			// path item parameters apply unless overridden by the operation.
+			for _, p := range common.Parameters {
+				if !slices.ContainsFunc(operation.Parameters, func(o OpenAPIParameter) bool {
+					return o.Name == p.Name && o.In == p.In
+				}) {
+					operation.Parameters = append(operation.Parameters, p)
+				}
+			}
+			spec.Operations = append(spec.Operations, operation)
+		}
+	}
+	return spec, nil
+}
+
+// resolveOpenAPIRefs replaces the local references of the node with the
+// nodes of the document they reference. References nested too deep, e.g.
+// those of recursive schemas, are replaced with empty objects.
+func resolveOpenAPIRefs(node any, document map[string]any, depth int) any {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			if depth >= _openAPIMaxRefDepth {
+				return map[string]any{}
+			}
+			var target any = document
+			for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+				token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+				m, _ := target.(map[string]any)
+				target = m[token]
+			}
+			return resolveOpenAPIRefs(target, document, depth+1)
+		}
+		resolved := make(map[string]any, len(n))
+		for key, value := range n {
+			resolved[key] = resolveOpenAPIRefs(value, document, depth)
+		}
+		return resolved
+	case []any:
+		resolved := make([]any, len(n))
+		for i, value := range n {
+			resolved[i] = resolveOpenAPIRefs(value, document, depth)
+		}
+		return resolved
+	default:
+		return node
+	}
+}
+
+// OpenAPIChain is a chain answering inputs by calling an operation of an API
+// described by an OpenAPI specification. The LLM selects the operation and
+// fills its parameters by calling the function of the operation, the chain
+// calls the API, and the LLM answers the input from the response.
+//
+// Only the operations allowed can be called, and requests are only sent to
+// the server URL, with the credentials added by Authorize.
+type OpenAPIChain struct {
+	OperationChain *LLMChain
+	AnswerChain    *LLMChain
+	Spec           *OpenAPISpec
+	Request        HTTPRequest
+	// ServerURL is the base URL of the requests, by default the URL of the
+	// first server of the specification.
+	ServerURL string
+	// AllowedOperations are the IDs of the operations the LLM can call. If
+	// empty, all the operations can be called.
+	AllowedOperations []string
+	// Authorize, if set, adds the credentials of the API to the requests,
+	// e.g. an Authorization header, so that the LLM never sees them.
+	Authorize func(req *http.Request) error
+	// MaxResponseLength is the number of bytes of the responses given to the
+	// LLM, the rest being truncated. If zero, responses aren't truncated.
+	MaxResponseLength int
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = OpenAPIChain{}
+
+// NewOpenAPIChain creates a new chain calling the operations of the API of
+// the specification with the HTTP client.
+func NewOpenAPIChain(llm llms.Model, spec *OpenAPISpec, request HTTPRequest) OpenAPIChain {
+	serverURL := ""
+	if len(spec.Servers) > 0 {
+		serverURL = spec.Servers[0].URL
+	}
+	return OpenAPIChain{
+		OperationChain: NewLLMChain(llm, prompts.NewPromptTemplate(_defaultOpenAPIOperationTemplate,
+			[]string{"input"})),
+		AnswerChain: NewLLMChain(llm, prompts.NewPromptTemplate(_defaultOpenAPIAnswerTemplate,
+			[]string{"input", "operation", "status", "api_response"})),
+		Spec:              spec,
+		Request:           request,
+		ServerURL:         serverURL,
+		MaxResponseLength: _openAPIChainDefaultMaxResponseLength,
+		InputKey:          _openAPIChainDefaultInputKey,
+		OutputKey:         _openAPIChainDefaultOutputKey,
+	}
+}
+
+// Call selects the operation answering the input, calls it, and answers the
+// input from its response. Only the LLM call generating the answer streams.
+func (c OpenAPIChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	operation, arguments, err := c.selectOperation(ctx, input, options...)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, operation, arguments)
+	if err != nil {
+		return nil, err
+	}
+	status, response, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := Predict(ctx, c.AnswerChain, map[string]any{
+		"input":        input,
+		"operation":    operation.Method + " " + operation.Path,
+		"status":       status,
+		"api_response": response,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{c.OutputKey: strings.TrimSpace(answer)}, nil
+}
+
+// selectOperation asks the LLM to call the function of the operation
+// answering the input, returning the operation and its arguments.
+func (c OpenAPIChain) selectOperation(
+	ctx context.Context,
+	input string,
+	options ...ChainCallOption,
+) (OpenAPIOperation, map[string]any, error) {
+	operations := map[string]OpenAPIOperation{}
+	tools := []llms.Tool{}
+	for _, operation := range c.Spec.Operations {
+		if len(c.AllowedOperations) > 0 && !slices.Contains(c.AllowedOperations, operation.ID) {
+			continue
+		}
+		tool := openAPITool(operation)
+		operations[tool.Function.Name] = operation
+		tools = append(tools, tool)
+	}
+	if len(tools) == 0 {
+		return OpenAPIOperation{}, nil, fmt.Errorf("%w: no allowed operations", ErrNoOpenAPIOperation)
+	}
+
+	prompt, err := c.OperationChain.Prompt.FormatPrompt(map[string]any{"input": input})
+	if err != nil {
+		return OpenAPIOperation{}, nil, err
+	}
+	callOptions := append(getLLMCallOptions(withoutStreaming(options)...), llms.WithTools(tools))
+	resp, err := c.OperationChain.LLM.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt.String()),
+	}, callOptions...)
+	if err != nil {
+		return OpenAPIOperation{}, nil, err
+	}
+	if len(resp.Choices) == 0 || len(resp.Choices[0].ToolCalls) == 0 ||
+		resp.Choices[0].ToolCalls[0].FunctionCall == nil {
+		return OpenAPIOperation{}, nil, ErrNoOpenAPIOperation
+	}
+
+	call := resp.Choices[0].ToolCalls[0].FunctionCall
+	operation, ok := operations[call.Name]
+	if !ok {
+		return OpenAPIOperation{}, nil, fmt.Errorf("%w: unknown operation %q", ErrNoOpenAPIOperation, call.Name)
+	}
+	arguments := map[string]any{}
+	if strings.TrimSpace(call.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &arguments); err != nil {
+			return OpenAPIOperation{}, nil, fmt.Errorf("%w: %w", ErrInvalidOpenAPIArguments, err)
+		}
+	}
+	return operation, arguments, nil
+}
+
+// openAPITool returns the function of the operation, whose parameters are
+// the parameters of the operation and, if it has a JSON request body, the
+// body parameter.
+func openAPITool(operation OpenAPIOperation) llms.Tool {
+	parameters := map[string]any{}
+	required := []string{}
+	for _, p := range operation.Parameters {
+		if p.In == "cookie" {
+			continue
+		}
+		property := map[string]any{"type": "string"}
+		if p.Schema != nil {
+			property = maps.Clone(p.Schema)
+		}
+		if p.Description != "" {
+			property["description"] = p.Description
+		}
+		parameters[p.Name] = property
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+	}
+	if body := operation.RequestBody; body != nil {
+		if content, ok := body.Content["application/json"]; ok {
+			property := map[string]any{"type": "object"}
+			if content.Schema != nil {
+				property = maps.Clone(content.Schema)
+			}
+			if body.Description != "" {
+				property["description"] = body.Description
+			}
+			parameters["body"] = property
+			if body.Required {
+				required = append(required, "body")
+			}
+		}
+	}
+
+	description := operation.Summary
+	if operation.Description != "" {
+		description = strings.TrimSpace(description + "\n" + operation.Description)
+	}
+	name := strings.Trim(_openAPIToolNameRE.ReplaceAllString(operation.ID, "_"), "_")
+	if len(name) > 64 { //nolint:mnd
+		name = name[:64]
+	}
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters: map[string]any{
+				"type":       jsonschema.Object,
+				"properties": parameters,
+				"required":   required,
+			},
+		},
+	}
+}
+
+// newRequest returns the request of the operation with the arguments of the
+// LLM, sent to the server URL.
+func (c OpenAPIChain) newRequest(
+	ctx context.Context,
+	operation OpenAPIOperation,
+	arguments map[string]any,
+) (*http.Request, error) {
+	path := operation.Path
+	query := url.Values{}
+	headers := http.Header{}
+	for _, p := range operation.Parameters {
+		value, ok := arguments[p.Name]
+		if !ok || value == nil {
+			if p.Required || p.In == "path" {
+				return nil, fmt.Errorf("%w: missing required parameter %s", ErrInvalidOpenAPIArguments, p.Name)
+			}
+			continue
+		}
+		switch p.In {
+		case "path":
+			v := openAPIParameterValue(value)
+			// url.PathEscape keeps dots, so "." and ".." would change the path.
+			if strings.Trim(v, ".") == "" {
+				return nil, fmt.Errorf("%w: invalid path parameter %s: %q", ErrInvalidOpenAPIArguments, p.Name, v)
+			}
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(v))
+		case "query":
+			if values, ok := value.([]any); ok {
+				for _, v := range values {
+					query.Add(p.Name, openAPIParameterValue(v))
+				}
+			} else {
+				query.Set(p.Name, openAPIParameterValue(value))
+			}
+		case "header":
+			headers.Set(p.Name, openAPIParameterValue(value))
+		}
+	}
+
+	serverURL, err := url.Parse(c.ServerURL)
+	if err != nil {
+		return nil, err
+	}
+	if serverURL.Scheme != "http" && serverURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server url %q", c.ServerURL)
+	}
+	// the path parameters are escaped already.
+	serverURL.RawPath = strings.TrimSuffix(serverURL.EscapedPath(), "/") + path
+	if serverURL.Path, err = url.PathUnescape(serverURL.RawPath); err != nil {
+		return nil, err
+	}
+	serverURL.RawQuery = query.Encode()
+
+	var body io.Reader
+	if value, ok := arguments["body"]; ok && operation.RequestBody != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		headers.Set("Content-Type", "application/json")
+	} else if operation.RequestBody != nil && operation.RequestBody.Required {
+		return nil, fmt.Errorf("%w: missing required body", ErrInvalidOpenAPIArguments)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, operation.Method, serverURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Authorize != nil {
+		if err := c.Authorize(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func openAPIParameterValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		values := make([]string, len(v))
+		for i, value := range v {
+			values[i] = openAPIParameterValue(value)
+		}
+		return strings.Join(values, ",")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// do sends the request, returning the status and the truncated body of the
+// response.
+func (c OpenAPIChain) do(req *http.Request) (string, string, error) {
+	resp, err := c.Request.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if c.MaxResponseLength > 0 {
+		reader = io.LimitReader(resp.Body, int64(c.MaxResponseLength)+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", "", err
+	}
+	response := string(body)
+	if c.MaxResponseLength > 0 && len(body) > c.MaxResponseLength {
+		response = string(body[:c.MaxResponseLength]) + "\n[truncated]"
+	}
+	return resp.Status, response, nil
+}
+
+func (c OpenAPIChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c OpenAPIChain) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c OpenAPIChain) GetOutputKeys() []string {
+	return []string{c.OutputKey}
+}
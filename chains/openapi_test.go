@@ -0,0 +1,166 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+const testOpenAPISpec = `openapi: 3.0.0
+info:
+  title: Pet store
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List the pets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+  /pets/{petId}:
+    parameters:
+      - $ref: "#/components/parameters/PetID"
+    get:
+      operationId: showPetById
+      summary: Info for a specific pet
+      responses:
+        200:
+          description: The pet
+components:
+  parameters:
+    PetID:
+      name: petId
+      in: path
+      required: true
+      description: The id of the pet
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        parent:
+          $ref: "#/components/schemas/Pet"
+`
+
+func TestLoadOpenAPISpec(t *testing.T) {
+	t.Parallel()
+
+	spec, err := LoadOpenAPISpec([]byte(testOpenAPISpec))
+	require.NoError(t, err)
+	require.Equal(t, "https://petstore.example.com/v1", spec.Servers[0].URL)
+	require.Len(t, spec.Operations, 3)
+
+	operation := spec.Operations[2]
+	require.Equal(t, "showPetById", operation.ID)
+	require.Equal(t, "GET", operation.Method)
+	require.Equal(t, "/pets/{petId}", operation.Path)
+	require.Len(t, operation.Parameters, 1)
+	require.Equal(t, "The id of the pet", operation.Parameters[0].Description)
+
+	body := spec.Operations[1].RequestBody.Content["application/json"].Schema
+	require.Equal(t, "object", body["type"])
+	require.Contains(t, body["properties"], "parent")
+}
+
+func TestOpenAPIChain(t *testing.T) {
+	t.Parallel()
+
+	var request *http.Request
+	var requestBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ := io.ReadAll(r.Body)
+		requestBody = string(body)
+		_, _ = w.Write([]byte(`{"id": "42", "name": "Rex"}`))
+	}))
+	defer server.Close()
+
+	spec, err := LoadOpenAPISpec([]byte(testOpenAPISpec))
+	require.NoError(t, err)
+	llm := fake.New(
+		fake.Response{ToolCalls: []llms.ToolCall{{
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "showPetById", Arguments: `{"petId": "4 2"}`},
+		}}},
+		fake.Response{Content: "The pet 42 is Rex."},
+	)
+	c := NewOpenAPIChain(llm, spec, server.Client())
+	c.ServerURL = server.URL + "/v1"
+	c.AllowedOperations = []string{"listPets", "showPetById"}
+	c.Authorize = func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer secret")
+		return nil
+	}
+
+	answer, err := Run(context.Background(), c, "What is the name of the pet 4 2?")
+	require.NoError(t, err)
+	require.Equal(t, "The pet 42 is Rex.", answer)
+	require.Equal(t, "/v1/pets/4%202", request.URL.EscapedPath())
+	require.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+	require.Empty(t, requestBody)
+
+	calls := llm.Calls()
+	tools := calls[0].Options.Tools
+	require.Len(t, tools, 2)
+	require.Equal(t, "listPets", tools[0].Function.Name)
+	require.Contains(t, calls[1].Messages[0].Parts[0].(llms.TextContent).Text, `{"id": "42", "name": "Rex"}`)
+
+	llm.AddResponses(fake.Response{ToolCalls: []llms.ToolCall{{
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "createPet", Arguments: `{"body": {"name": "Rex"}}`},
+	}}})
+	_, err = Run(context.Background(), c, "Create a pet named Rex.")
+	require.ErrorIs(t, err, ErrNoOpenAPIOperation)
+
+	c.AllowedOperations = nil
+	llm.AddResponses(fake.Response{ToolCalls: []llms.ToolCall{{
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "createPet", Arguments: `{"body": {"name": "Rex"}}`},
+	}}}, fake.Response{Content: "Rex was created."})
+	answer, err = Run(context.Background(), c, "Create a pet named Rex.")
+	require.NoError(t, err)
+	require.Equal(t, "Rex was created.", answer)
+	require.Equal(t, http.MethodPost, request.Method)
+	require.Equal(t, "/v1/pets", request.URL.Path)
+	require.JSONEq(t, `{"name": "Rex"}`, requestBody)
+
+	llm.AddResponses(fake.Response{ToolCalls: []llms.ToolCall{{
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "showPetById", Arguments: `{}`},
+	}}})
+	_, err = Run(context.Background(), c, "Show a pet.")
+	require.ErrorIs(t, err, ErrInvalidOpenAPIArguments)
+
+	for _, id := range []string{"", ".", ".."} {
+		llm.AddResponses(fake.Response{ToolCalls: []llms.ToolCall{{
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "showPetById", Arguments: fmt.Sprintf(`{"petId": %q}`, id)},
+		}}})
+		_, err = Run(context.Background(), c, "Show a pet.")
+		require.ErrorIs(t, err, ErrInvalidOpenAPIArguments)
+	}
+}
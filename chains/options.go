@@ -39,6 +39,13 @@ type chainCallOption struct {
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error
 
+	// StreamingEventFunc is a function to be called for each typed event of a streaming
+	// response. Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event llms.StreamingEvent) error
+
+	// streamingDisabled is set for the intermediate LLM calls of chains, which don't stream.
+	streamingDisabled bool
+
 	// TopK is the number of tokens to consider for top-k sampling in an LLM call.
 	TopK    int
 	topkSet bool
@@ -91,13 +98,31 @@ func WithTemperature(temperature float64) ChainCallOption {
 	}
 }
 
-// WithStreamingFunc is an option for LLM.Call that allows streaming responses.
+// WithStreamingFunc is an option for LLM.Call that allows streaming responses. Only the
+// LLM call producing the final answer of a chain streams, see the package documentation.
 func WithStreamingFunc(streamingFunc func(ctx context.Context, chunk []byte) error) ChainCallOption {
 	return func(o *chainCallOption) {
 		o.StreamingFunc = streamingFunc
 	}
 }
 
+// WithStreamingEventFunc is an option for LLM.Call that allows streaming typed events, such
+// as text and reasoning deltas. Like WithStreamingFunc, only the LLM call producing the final
+// answer of a chain streams.
+func WithStreamingEventFunc(streamingEventFunc func(ctx context.Context, event llms.StreamingEvent) error) ChainCallOption { //nolint:lll
+	return func(o *chainCallOption) {
+		o.StreamingEventFunc = streamingEventFunc
+	}
+}
+
+// withoutStreaming returns the options with streaming disabled, for the intermediate calls of
+// a chain.
+func withoutStreaming(options []ChainCallOption) []ChainCallOption {
+	return append(options[:len(options):len(options)], func(o *chainCallOption) {
+		o.streamingDisabled = true
+	})
+}
+
 // WithTopK will add an option to use top-k sampling for LLM.Call.
 func WithTopK(topK int) ChainCallOption {
 	return func(o *chainCallOption) {
@@ -166,7 +191,9 @@ func getLLMCallOptions(options ...ChainCallOption) []llms.CallOption { //nolint:
 	for _, option := range options {
 		option(opts)
 	}
-	if opts.StreamingFunc == nil && opts.CallbackHandler != nil {
+	if opts.streamingDisabled {
+		opts.StreamingFunc, opts.StreamingEventFunc = nil, nil
+	} else if opts.StreamingFunc == nil && opts.CallbackHandler != nil {
 		opts.StreamingFunc = func(ctx context.Context, chunk []byte) error {
 			opts.CallbackHandler.HandleStreamingFunc(ctx, chunk)
 			return nil
@@ -206,6 +233,9 @@ func getLLMCallOptions(options ...ChainCallOption) []llms.CallOption { //nolint:
 		chainCallOption = append(chainCallOption, llms.WithRepetitionPenalty(opts.RepetitionPenalty))
 	}
 	chainCallOption = append(chainCallOption, llms.WithStreamingFunc(opts.StreamingFunc))
+	if opts.StreamingEventFunc != nil {
+		chainCallOption = append(chainCallOption, llms.WithStreamingEventFunc(opts.StreamingEventFunc))
+	}
 
 	return chainCallOption
 }
@@ -0,0 +1,198 @@
+package chains
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"go.starlark.net/lib/math"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+//go:embed prompts/pal.txt
+var _palPrompt string //nolint:gochecknoglobals
+
+const (
+	_palDefaultMaxExecutionSteps = 1_000_000
+	_palDefaultMaxValueSize      = 1 << 20
+	_palDefaultTimeout           = 5 * time.Second
+	_palDefaultMaxRetries        = 1
+	_palSolutionFunction         = "solution"
+)
+
+// ErrInvalidProgram is returned by a PALChain when the program written by
+// the LLM fails to run or doesn't define the solution function.
+var ErrInvalidProgram = errors.New("invalid program")
+
+var _palProgramRE = regexp.MustCompile("(?s)```(?:starlark|python)?\\s*\n(.*?)```")
+
+// PALChain is a Program-Aided Language chain: the LLM writes a Starlark
+// program solving the math or logic problem of the question, and the chain
+// runs it to compute the answer, which is far more reliable than arithmetic
+// done by the LLM. Programs run in a sandbox: they can only use the math
+// module, can't load modules, can't build values larger than MaxValueSize,
+// and are stopped after MaxExecutionSteps steps or Timeout.
+type PALChain struct {
+	LLMChain *LLMChain
+	// MaxExecutionSteps is the number of steps after which programs are
+	// stopped.
+	MaxExecutionSteps uint64
+	// MaxValueSize is the maximum size of the values built by programs: of
+	// the strings and bytes, in bytes, of the lists, tuples and dicts, in
+	// elements, and of the integers, in bytes. Repeating, concatenating,
+	// joining, replacing, formatting or extending values exhausts memory in
+	// a few steps, so the size of the values built by the +, * and %
+	// operators, the join, replace, format, append, insert, extend and update
+	// methods, and the str, repr, print, list, tuple, sorted, reversed,
+	// enumerate and zip builtins is checked before they are built.
+	MaxValueSize int
+	// Timeout is the duration after which programs are stopped.
+	Timeout time.Duration
+	// MaxRetries is the number of times the LLM is asked to correct a
+	// program failing to run.
+	MaxRetries int
+	// ReturnProgram is whether the program is returned under the "program"
+	// output key.
+	ReturnProgram bool
+}
+
+var _ Chain = PALChain{}
+
+// NewPALChain creates a new PALChain.
+func NewPALChain(llm llms.Model) PALChain {
+	return PALChain{
+		LLMChain:          NewLLMChain(llm, prompts.NewPromptTemplate(_palPrompt, []string{"question"})),
+		MaxExecutionSteps: _palDefaultMaxExecutionSteps,
+		MaxValueSize:      _palDefaultMaxValueSize,
+		Timeout:           _palDefaultTimeout,
+		MaxRetries:        _palDefaultMaxRetries,
+	}
+}
+
+// Call asks the LLM for the program solving the question, runs it and
+// returns the value returned by its solution function as the answer. The
+// chain doesn't stream, as programs aren't answers.
+func (c PALChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) {
+	question, ok := values["question"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	input := question
+	for attempt := 0; ; attempt++ {
+		out, err := Predict(ctx, c.LLMChain, map[string]any{"question": input}, withoutStreaming(options)...)
+		if err != nil {
+			return nil, err
+		}
+
+		program := extractProgram(out)
+		answer, err := c.run(ctx, program)
+		if err == nil {
+			outputs := map[string]any{"answer": answer}
+			if c.ReturnProgram {
+				outputs["program"] = program
+			}
+			return outputs, nil
+		}
+		if attempt >= c.MaxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+		input = question + "\n```starlark\n" + program + "\n```\nThe program failed with the error: " + err.Error() +
+			"\nWrite a corrected program."
+	}
+}
+
+// run runs the program in a sandbox and returns the value returned by its
+// solution function.
+func (c PALChain) run(ctx context.Context, program string) (string, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	// Load is nil, so that programs can't load modules, and prints are
+	// discarded.
+	thread := &starlark.Thread{Name: "pal", Print: func(*starlark.Thread, string) {}}
+	if c.MaxExecutionSteps > 0 {
+		thread.SetMaxExecutionSteps(c.MaxExecutionSteps)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		thread.Cancel(ctx.Err().Error())
+	})
+	defer stop()
+
+	globals, err := c.exec(thread, program)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidProgram, err)
+	}
+	solution, ok := globals[_palSolutionFunction].(starlark.Callable)
+	if !ok {
+		return "", fmt.Errorf("%w: no %s function", ErrInvalidProgram, _palSolutionFunction)
+	}
+	v, err := starlark.Call(thread, solution, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidProgram, err)
+	}
+	if s, ok := starlark.AsString(v); ok {
+		return s, nil
+	}
+	return v.String(), nil
+}
+
+// exec runs the program on the thread and returns its globals. If
+// MaxValueSize is set, the operators, methods and builtins of the program
+// building values are rewritten or wrapped to check the size of their result.
+func (c PALChain) exec(thread *starlark.Thread, program string) (starlark.StringDict, error) {
+	predeclared := starlark.StringDict{"math": math.Module}
+	if c.MaxValueSize <= 0 {
+		return starlark.ExecFile(thread, "solution.star", program, predeclared)
+	}
+
+	f, err := syntax.Parse("solution.star", program, 0)
+	if err != nil {
+		return nil, err
+	}
+	palRewrite(f)
+	for name, builtin := range palBuiltins(c.MaxValueSize) {
+		predeclared[name] = builtin
+	}
+	prog, err := starlark.FileProgram(f, predeclared.Has)
+	if err != nil {
+		return nil, err
+	}
+	return prog.Init(thread, predeclared)
+}
+
+// extractProgram returns the program of the output of the LLM, removing the
+// code fences around it.
+func extractProgram(out string) string {
+	if match := _palProgramRE.FindStringSubmatch(out); match != nil {
+		out = match[1]
+	}
+	return strings.TrimSpace(out)
+}
+
+func (c PALChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c PALChain) GetInputKeys() []string {
+	return []string{"question"}
+}
+
+func (c PALChain) GetOutputKeys() []string {
+	if c.ReturnProgram {
+		return []string{"answer", "program"}
+	}
+	return []string{"answer"}
+}
@@ -0,0 +1,520 @@
+package chains
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// _palBinaryBuiltin is the builtin that the +, * and % operators of
+	// programs are rewritten to call, to check the size of their result
+	// before computing it.
+	_palBinaryBuiltin = "__pal_binary__"
+	// _palAttrBuiltin is the builtin that the selections of the methods of
+	// palMethodSizes are rewritten to call, to check the size of the result
+	// of the method before calling it.
+	_palAttrBuiltin = "__pal_attr__"
+)
+
+// palTooLarge returns the error of an operation whose result is larger than
+// maxSize.
+func palTooLarge(op string, size int64, maxSize int) error {
+	return fmt.Errorf("value too large: %s has size %d, the limit is %d", op, size, maxSize)
+}
+
+// palBinary returns the builtin evaluating the binary operations of programs,
+// which fails when their result is larger than maxSize, in elements for
+// strings, bytes, lists and tuples and in bytes for integers.
+func palBinary(maxSize int) *starlark.Builtin {
+	return starlark.NewBuiltin(_palBinaryBuiltin, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, _ []starlark.Tuple) (starlark.Value, error) { //nolint:lll
+		if len(args) != 3 {
+			return nil, fmt.Errorf("%s: got %d arguments, want 3", _palBinaryBuiltin, len(args))
+		}
+		op, x, y := syntax.Token(args[0].(starlark.Int).BigInt().Int64()), args[1], args[2] //nolint:forcetypeassert
+		augmented, ok := palAugmentedOps[op]
+		if ok {
+			op = augmented
+		}
+		if size := palResultSize(op, x, y, maxSize); size > int64(maxSize) {
+			return nil, palTooLarge(fmt.Sprintf("%s %s %s", x.Type(), op, y.Type()), size, maxSize)
+		}
+		if ok {
+			return y, nil
+		}
+		return starlark.Binary(op, x, y)
+	})
+}
+
+// palResultSize returns the size of the result of the binary operation, or 0
+// if it doesn't build a sequence or an integer.
+func palResultSize(op syntax.Token, x, y starlark.Value, maxSize int) int64 {
+	switch op { //nolint:exhaustive
+	case syntax.PERCENT:
+		// Each conversion of the format formats at most all of the operands.
+		if format, ok := x.(starlark.String); ok {
+			return int64(len(format)) + int64(strings.Count(string(format), "%"))*palStringSize(y, maxSize)
+		}
+		return palSize(x) + palSize(y)
+	case syntax.PLUS:
+		return palSize(x) + palSize(y)
+	case syntax.STAR:
+		if n, ok := palRepeat(y); ok && palIsSequence(x) {
+			return palSize(x) * n
+		}
+		if n, ok := palRepeat(x); ok && palIsSequence(y) {
+			return palSize(y) * n
+		}
+		return palSize(x) + palSize(y)
+	}
+	return 0
+}
+
+// palSize returns the number of elements of sequences and the number of bytes
+// of integers.
+func palSize(v starlark.Value) int64 {
+	switch v := v.(type) {
+	case starlark.Int:
+		return int64(v.BigInt().BitLen()+7) / 8 //nolint:mnd
+	case starlark.Indexable:
+		return int64(v.Len())
+	}
+	return 0
+}
+
+func palIsSequence(v starlark.Value) bool {
+	_, ok := v.(starlark.Indexable)
+	return ok
+}
+
+// palRepeat returns the repetition count of an integer operand of *, with
+// counts too large to be represented being capped instead.
+func palRepeat(v starlark.Value) (int64, bool) {
+	i, ok := v.(starlark.Int)
+	if !ok {
+		return 0, false
+	}
+	n, err := starlark.AsInt32(i)
+	if err != nil {
+		if i.Sign() < 0 {
+			return 0, true
+		}
+		return 1 << 31, true
+	}
+	return max(int64(n), 0), true
+}
+
+// palRewrite rewrites the +, * and % operators of the program to calls to the
+// _palBinaryBuiltin, and the selections of the methods growing values to
+// calls to the _palAttrBuiltin. The operands of augmented assignments are passed through
+// the builtin, which returns the right operand once the size of the result
+// is checked, so that lists are still extended in place.
+func palRewrite(f *syntax.File) {
+	palRewriteStmts(f.Stmts)
+}
+
+func palRewriteStmts(stmts []syntax.Stmt) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *syntax.AssignStmt:
+			palRewriteExpr(&stmt.LHS)
+			palRewriteExpr(&stmt.RHS)
+			if _, ok := palAugmentedOps[stmt.Op]; ok {
+				// x += y is rewritten to x += __pal_binary__(+=, x, y), which
+				// checks the size of x + y and returns y.
+				stmt.RHS = palCall(stmt.Op, stmt.OpPos, palCopyIdent(stmt.LHS), stmt.RHS)
+			}
+		case *syntax.DefStmt:
+			palRewriteExprs(stmt.Params)
+			palRewriteStmts(stmt.Body)
+		case *syntax.ExprStmt:
+			palRewriteExpr(&stmt.X)
+		case *syntax.ForStmt:
+			palRewriteExpr(&stmt.X)
+			palRewriteStmts(stmt.Body)
+		case *syntax.WhileStmt:
+			palRewriteExpr(&stmt.Cond)
+			palRewriteStmts(stmt.Body)
+		case *syntax.IfStmt:
+			palRewriteExpr(&stmt.Cond)
+			palRewriteStmts(stmt.True)
+			palRewriteStmts(stmt.False)
+		case *syntax.ReturnStmt:
+			if stmt.Result != nil {
+				palRewriteExpr(&stmt.Result)
+			}
+		}
+	}
+}
+
+//nolint:gochecknoglobals
+var palAugmentedOps = map[syntax.Token]syntax.Token{
+	syntax.PLUS_EQ:    syntax.PLUS,
+	syntax.STAR_EQ:    syntax.STAR,
+	syntax.PERCENT_EQ: syntax.PERCENT,
+}
+
+func palRewriteExprs(exprs []syntax.Expr) {
+	for i := range exprs {
+		palRewriteExpr(&exprs[i])
+	}
+}
+
+//nolint:cyclop
+func palRewriteExpr(p *syntax.Expr) {
+	switch e := (*p).(type) {
+	case *syntax.BinaryExpr:
+		palRewriteExpr(&e.X)
+		palRewriteExpr(&e.Y)
+		if e.Op == syntax.PLUS || e.Op == syntax.STAR || e.Op == syntax.PERCENT {
+			*p = palCall(e.Op, e.OpPos, e.X, e.Y)
+		}
+	case *syntax.UnaryExpr:
+		if e.X != nil {
+			palRewriteExpr(&e.X)
+		}
+	case *syntax.ParenExpr:
+		palRewriteExpr(&e.X)
+	case *syntax.CallExpr:
+		palRewriteExpr(&e.Fn)
+		palRewriteExprs(e.Args)
+	case *syntax.DotExpr:
+		palRewriteExpr(&e.X)
+		if _, ok := palMethodSizes[e.Name.Name]; ok {
+			*p = &syntax.CallExpr{
+				Fn:     &syntax.Ident{NamePos: e.Dot, Name: _palAttrBuiltin},
+				Lparen: e.Dot,
+				Args: []syntax.Expr{e.X, &syntax.Literal{
+					Token: syntax.STRING, TokenPos: e.NamePos, Raw: strconv.Quote(e.Name.Name), Value: e.Name.Name,
+				}},
+				Rparen: e.Dot,
+			}
+		}
+	case *syntax.IndexExpr:
+		palRewriteExpr(&e.X)
+		palRewriteExpr(&e.Y)
+	case *syntax.SliceExpr:
+		palRewriteExpr(&e.X)
+		for _, q := range []*syntax.Expr{&e.Lo, &e.Hi, &e.Step} {
+			if *q != nil {
+				palRewriteExpr(q)
+			}
+		}
+	case *syntax.CondExpr:
+		palRewriteExpr(&e.Cond)
+		palRewriteExpr(&e.True)
+		palRewriteExpr(&e.False)
+	case *syntax.ListExpr:
+		palRewriteExprs(e.List)
+	case *syntax.TupleExpr:
+		palRewriteExprs(e.List)
+	case *syntax.DictExpr:
+		palRewriteExprs(e.List)
+	case *syntax.DictEntry:
+		palRewriteExpr(&e.Key)
+		palRewriteExpr(&e.Value)
+	case *syntax.LambdaExpr:
+		palRewriteExprs(e.Params)
+		palRewriteExpr(&e.Body)
+	case *syntax.Comprehension:
+		palRewriteExpr(&e.Body)
+		for _, clause := range e.Clauses {
+			switch clause := clause.(type) {
+			case *syntax.ForClause:
+				palRewriteExpr(&clause.X)
+			case *syntax.IfClause:
+				palRewriteExpr(&clause.Cond)
+			}
+		}
+	}
+}
+
+// palCopyIdent returns a copy of the expression if it's an identifier, so
+// that the identifiers bound by augmented assignments aren't shared with
+// their uses.
+func palCopyIdent(e syntax.Expr) syntax.Expr { //nolint:ireturn
+	if id, ok := e.(*syntax.Ident); ok {
+		return &syntax.Ident{NamePos: id.NamePos, Name: id.Name}
+	}
+	return e
+}
+
+// palCall returns the call of the _palBinaryBuiltin computing x op y.
+func palCall(op syntax.Token, pos syntax.Position, x, y syntax.Expr) *syntax.CallExpr {
+	return &syntax.CallExpr{
+		Fn:     &syntax.Ident{NamePos: pos, Name: _palBinaryBuiltin},
+		Lparen: pos,
+		Args: []syntax.Expr{
+			&syntax.Literal{Token: syntax.INT, TokenPos: pos, Raw: fmt.Sprint(int(op)), Value: int64(op)},
+			x,
+			y,
+		},
+		Rparen: pos,
+	}
+}
+
+// palSizeFunc returns the size of the result of a call to a builtin or to a
+// method of recv, counting at most past maxSize.
+type palSizeFunc func(recv starlark.Value, args starlark.Tuple, kwargs []starlark.Tuple, maxSize int) int64
+
+// palMethodSizes are the sizes of the results of the methods growing values.
+//
+//nolint:gochecknoglobals
+var palMethodSizes = map[string]palSizeFunc{
+	"append": func(recv starlark.Value, _ starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+		return palLen(recv) + 1
+	},
+	"insert": func(recv starlark.Value, _ starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+		return palLen(recv) + 1
+	},
+	"extend": func(recv starlark.Value, args starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+		return palLen(recv) + palLen(args...)
+	},
+	"update": func(recv starlark.Value, args starlark.Tuple, kwargs []starlark.Tuple, _ int) int64 {
+		return palLen(recv) + palLen(args...) + int64(len(kwargs))
+	},
+	"format": func(recv starlark.Value, args starlark.Tuple, kwargs []starlark.Tuple, maxSize int) int64 {
+		// Each field of the format formats at most all of the arguments.
+		format, _ := starlark.AsString(recv)
+		size := palStringSize(args, maxSize)
+		for _, kwarg := range kwargs {
+			size += palStringSize(kwarg[1], maxSize)
+		}
+		return int64(len(format)) + int64(strings.Count(format, "{"))*size
+	},
+	"join": func(recv starlark.Value, args starlark.Tuple, _ []starlark.Tuple, maxSize int) int64 {
+		sep, _ := starlark.AsString(recv)
+		if len(args) != 1 {
+			return 0
+		}
+		iter := starlark.Iterate(args[0])
+		if iter == nil {
+			return 0
+		}
+		defer iter.Done()
+		var size int64
+		var x starlark.Value
+		for n := 0; size <= int64(maxSize) && iter.Next(&x); n++ {
+			s, ok := x.(starlark.String)
+			if !ok {
+				// join fails on the element.
+				return 0
+			}
+			if n > 0 {
+				size += int64(len(sep))
+			}
+			size += int64(len(s))
+		}
+		return size
+	},
+	"replace": func(recv starlark.Value, args starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+		s, _ := starlark.AsString(recv)
+		if len(args) < 2 { //nolint:mnd
+			return 0
+		}
+		old, ok1 := starlark.AsString(args[0])
+		replacement, ok2 := starlark.AsString(args[1])
+		if !ok1 || !ok2 || len(replacement) <= len(old) {
+			return int64(len(s))
+		}
+		n := int64(len(s) + 1)
+		if old != "" {
+			n = int64(strings.Count(s, old))
+		}
+		if len(args) > 2 { //nolint:mnd
+			if count, err := starlark.AsInt32(args[2]); err == nil && count >= 0 {
+				n = min(n, int64(count))
+			}
+		}
+		return int64(len(s)) + n*int64(len(replacement)-len(old))
+	},
+}
+
+// palBuiltinSizes are the sizes of the results of the builtins building values
+// from their arguments.
+//
+//nolint:gochecknoglobals
+var palBuiltinSizes = map[string]palSizeFunc{
+	"str":       palStringsSize,
+	"repr":      palStringsSize,
+	"print":     palStringsSize,
+	"list":      palArgsLen,
+	"tuple":     palArgsLen,
+	"sorted":    palArgsLen,
+	"reversed":  palArgsLen,
+	"enumerate": palArgsLen,
+	"zip": func(_ starlark.Value, args starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+		var size int64
+		for i, arg := range args {
+			if n := palLen(arg); i == 0 || n < size {
+				size = n
+			}
+		}
+		return size
+	},
+}
+
+func palStringsSize(_ starlark.Value, args starlark.Tuple, _ []starlark.Tuple, maxSize int) int64 {
+	var size int64
+	for _, arg := range args {
+		size += palStringSize(arg, maxSize)
+	}
+	return size
+}
+
+func palArgsLen(_ starlark.Value, args starlark.Tuple, _ []starlark.Tuple, _ int) int64 {
+	if len(args) == 0 {
+		return 0
+	}
+	return palLen(args[0])
+}
+
+// palLen returns the total length of the values, or 0 for the values without
+// length.
+func palLen(values ...starlark.Value) int64 {
+	var size int64
+	for _, v := range values {
+		size += int64(max(starlark.Len(v), 0))
+	}
+	return size
+}
+
+// palBuiltins returns the builtins predeclared for programs run with a
+// maximum value size: the _palBinaryBuiltin and the _palAttrBuiltin, and the
+// universal builtins building values, wrapped to check the size of their
+// result. getattr is wrapped too, so that the methods it selects are checked
+// like the ones selected by dot expressions.
+func palBuiltins(maxSize int) starlark.StringDict {
+	builtins := starlark.StringDict{
+		_palBinaryBuiltin: palBinary(maxSize),
+		_palAttrBuiltin: starlark.NewBuiltin(_palAttrBuiltin, func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, _ []starlark.Tuple) (starlark.Value, error) { //nolint:lll
+			var x starlark.Value
+			var name string
+			if err := starlark.UnpackPositionalArgs(_palAttrBuiltin, args, nil, 2, &x, &name); err != nil {
+				return nil, err
+			}
+			return palAttr(x, name, nil, maxSize)
+		}),
+	}
+	getattr := starlark.Universe["getattr"]
+	builtins["getattr"] = starlark.NewBuiltin("getattr", func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) { //nolint:lll
+		var x, dflt starlark.Value
+		var name string
+		if err := starlark.UnpackPositionalArgs("getattr", args, kwargs, 2, &x, &name, &dflt); err != nil {
+			return nil, err
+		}
+		if _, ok := palMethodSizes[name]; !ok {
+			return starlark.Call(thread, getattr, args, kwargs)
+		}
+		return palAttr(x, name, dflt, maxSize)
+	})
+	for name, size := range palBuiltinSizes {
+		builtins[name] = palChecked(name, starlark.Universe[name], nil, size, maxSize)
+	}
+	return builtins
+}
+
+// palAttr returns the method of x, wrapped to check the size of its result,
+// or dflt if x has no such method and dflt isn't nil.
+func palAttr(x starlark.Value, name string, dflt starlark.Value, maxSize int) (starlark.Value, error) {
+	var attr starlark.Value
+	if x, ok := x.(starlark.HasAttrs); ok {
+		var err error
+		if attr, err = x.Attr(name); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case attr != nil:
+		return palChecked(name, attr, x, palMethodSizes[name], maxSize), nil
+	case dflt != nil:
+		return dflt, nil
+	}
+	return nil, fmt.Errorf("%s has no .%s field or method", x.Type(), name)
+}
+
+// palChecked returns fn wrapped to fail when the size of its result is
+// larger than maxSize.
+func palChecked(name string, fn, recv starlark.Value, size palSizeFunc, maxSize int) *starlark.Builtin {
+	op := name
+	if recv != nil {
+		op = recv.Type() + "." + name
+	}
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) { //nolint:lll
+		if size := size(recv, args, kwargs, maxSize); size > int64(maxSize) {
+			return nil, palTooLarge(op, size, maxSize)
+		}
+		return starlark.Call(thread, fn, args, kwargs)
+	})
+}
+
+// palStringSize returns the approximate size of the string representation of
+// v, counting at most past maxSize. Values contained in themselves are
+// represented as [...] or {...}.
+func palStringSize(v starlark.Value, maxSize int) int64 {
+	s := palStringSizer{maxSize: int64(maxSize), path: make(map[starlark.Value]bool)}
+	s.add(v)
+	return s.size
+}
+
+type palStringSizer struct {
+	maxSize int64
+	size    int64
+	path    map[starlark.Value]bool
+}
+
+func (s *palStringSizer) add(v starlark.Value) {
+	if s.size > s.maxSize {
+		return
+	}
+	switch v := v.(type) {
+	case starlark.String:
+		s.size += int64(len(v))
+	case starlark.Bytes:
+		s.size += int64(len(v))
+	case starlark.Int:
+		// A decimal digit holds more than 3 bits.
+		s.size += int64(v.BigInt().BitLen())/3 + 2 //nolint:mnd
+	case starlark.Tuple:
+		s.addElems(v)
+	case *starlark.List:
+		if s.enter(v) {
+			s.addElems(v)
+			delete(s.path, v)
+		}
+	case *starlark.Dict:
+		if s.enter(v) {
+			for _, item := range v.Items() {
+				s.add(item[0])
+				s.add(item[1])
+				s.size += 4 //nolint:mnd
+			}
+			s.size += 2 //nolint:mnd
+			delete(s.path, v)
+		}
+	default:
+		s.size += int64(len(v.String()))
+	}
+}
+
+// enter adds v to the path of the values being represented, or returns false
+// if v is already in it.
+func (s *palStringSizer) enter(v starlark.Value) bool {
+	if s.path[v] {
+		s.size += 5 //nolint:mnd
+		return false
+	}
+	s.path[v] = true
+	return true
+}
+
+func (s *palStringSizer) addElems(v starlark.Indexable) {
+	s.size += 2 //nolint:mnd
+	for i := 0; i < v.Len() && s.size <= s.maxSize; i++ {
+		s.add(v.Index(i))
+		s.size += 2 //nolint:mnd
+	}
+}
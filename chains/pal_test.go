@@ -0,0 +1,85 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+func TestPALChain(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(fake.TextResponses(
+		"```starlark\ndef solution():\n    return undefined_total\n```",
+		"```python\ndef solution():\n    total = 0\n    for n in range(1, 20, 2):\n        total += n * n\n"+
+			"    return math.sqrt(total)\n```",
+	)...)
+	c := NewPALChain(llm)
+	c.ReturnProgram = true
+
+	result, err := Call(context.Background(), c, map[string]any{"question": "What is the norm of the odd numbers below 20?"})
+	require.NoError(t, err)
+	require.Equal(t, "36.46916505762094", result["answer"])
+	require.Contains(t, result["program"], "for n in range(1, 20, 2):")
+	require.Contains(t, llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text, "undefined: undefined_total")
+
+	llm.AddResponses(fake.TextResponses(
+		"def solution():\n    return 'four'",
+		"def solution():\n    n = 0\n    for i in range(100000000):\n        n += i\n    return n",
+		`load("os.star", "os")`,
+	)...)
+	answer, err := Run(context.Background(), NewPALChain(llm), "Spell four.")
+	require.NoError(t, err)
+	require.Equal(t, "four", answer)
+
+	c.MaxExecutionSteps = 1000
+	_, err = Call(context.Background(), c, map[string]any{"question": "Sum the numbers below 100000000."})
+	require.ErrorIs(t, err, ErrInvalidProgram)
+	require.ErrorContains(t, err, "load not implemented")
+	require.Contains(t, llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text, "too many steps")
+}
+
+func TestPALChainMaxValueSize(t *testing.T) {
+	t.Parallel()
+	c := NewPALChain(fake.New())
+	c.MaxValueSize = 1 << 16
+
+	for _, program := range []string{
+		"def solution():\n    return 'ab' * 100000000",
+		"def solution():\n    return len([0] * (1 << 40))",
+		"def solution():\n    s = 'ab'\n    for _ in range(40):\n        s = s + s\n    return len(s)",
+		"def solution():\n    l = [0]\n    for _ in range(40):\n        l += l\n    return len(l)",
+		"def solution():\n    n = 3\n    for _ in range(40):\n        n *= n\n    return n",
+		"def solution():\n    s = 'a' * 60000\n    return len(''.join([s] * 1000))",
+		"def solution():\n    s = 'a' * 60000\n    return len(s.replace('a', s))",
+		"def solution():\n    s = 'a' * 60000\n    return len('%s%s' % (s, s))",
+		"def solution():\n    s = 'a' * 60000\n    return len('%(k)s%(k)s' % {'k': s})",
+		"def solution():\n    s = '%s%s'\n    s %= ('a' * 60000, 'b')\n    return len(s)",
+		"def solution():\n    s = 'a' * 60000\n    return len('{0}{0}'.format(s))",
+		"def solution():\n    s = 'a' * 60000\n    return len(str([s] * 1000))",
+		"def solution():\n    s = 'a' * 60000\n    f = getattr('', 'join')\n    return len(f([s, s]))",
+		"def solution():\n    l = []\n    big = [0] * 60000\n    for _ in range(1000):\n        l.extend(big)\n    return len(l)",
+		"def solution():\n    l = []\n    for _ in range(100000):\n        l.append(0)\n    return len(l)",
+		"def solution():\n    return len(list(range(1 << 40)))",
+	} {
+		_, err := c.run(context.Background(), program)
+		require.ErrorIs(t, err, ErrInvalidProgram, program)
+		require.ErrorContains(t, err, "value too large", program)
+	}
+
+	answer, err := c.run(context.Background(),
+		"def solution():\n    a = [1]\n    b = a\n    a += [2] * 3\n    s = 'x' * 4 + 'y'\n"+
+			"    return '%d %d %s %d' % (len(a), len(b), s, 6 * 7)")
+	require.NoError(t, err)
+	require.Equal(t, "4 4 xxxxy 42", answer)
+
+	answer, err = c.run(context.Background(),
+		"def solution():\n    l = [1]\n    l.append(l)\n    l.extend(range(2))\n    d = {}\n    d.update(a=1)\n"+
+			"    join = getattr(', ', 'join')\n    n = 7\n    n %= 4\n"+
+			"    return join([str(l), 'a-b'.replace('-', '+'), '{} {}'.format(n, d), repr(list(zip(['a', 'b'], (1, 2))))])")
+	require.NoError(t, err)
+	require.Equal(t, `[1, [...], 0, 1], a+b, 3 {"a": 1}, [("a", 1), ("b", 2)]`, answer)
+}
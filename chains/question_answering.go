@@ -13,6 +13,15 @@ const _defaultStuffQATemplate = `Use the following pieces of context to answer t
 Question: {{.question}}
 Helpful Answer:`
 
+//nolint:lll
+const _defaultCitationQATemplate = `Use the following numbered sources to answer the question at the end. If you don't know the answer, just say that you don't know, don't try to make up an answer.
+Cite the sources supporting each statement with their number in square brackets, e.g. [1] or [1][3]. Only cite the given sources.
+
+{{.context}}
+
+Question: {{.question}}
+Helpful Answer:`
+
 const _defaultRefineQATemplate = `The original question is as follows: {{.question}}
 We have provided an existing answer: {{.existing_answer}}
 We have the opportunity to refine the existing answer
@@ -168,3 +177,13 @@ func LoadMapRerankQA(llm llms.Model) MapRerankDocuments {
 
 	return *mapRerank
 }
+
+// LoadCitationQA loads a CitationQA chain with the default prompt. Inputs are
+// "question" and "input_documents".
+func LoadCitationQA(llm llms.Model) CitationQA {
+	prompt := prompts.NewPromptTemplate(
+		_defaultCitationQATemplate,
+		[]string{"context", "question"},
+	)
+	return NewCitationQA(NewLLMChain(llm, prompt))
+}
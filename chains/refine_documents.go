@@ -81,7 +81,8 @@ func (c RefineDocuments) Call(ctx context.Context, values map[string]any, option
 	if err != nil {
 		return nil, err
 	}
-	response, err := Predict(ctx, c.LLMChain, initialInputs, options...)
+	// only the last call, giving the final text, streams.
+	response, err := Predict(ctx, c.LLMChain, initialInputs, c.stepOptions(0, len(docs), options)...)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +93,7 @@ func (c RefineDocuments) Call(ctx context.Context, values map[string]any, option
 		if err != nil {
 			return nil, err
 		}
-		response, err = Predict(ctx, c.RefineLLMChain, refineInputs, options...)
+		response, err = Predict(ctx, c.RefineLLMChain, refineInputs, c.stepOptions(i, len(docs), options)...)
 		if err != nil {
 			return nil, err
 		}
@@ -103,6 +104,15 @@ func (c RefineDocuments) Call(ctx context.Context, values map[string]any, option
 	}, nil
 }
 
+// stepOptions returns the options of the call of the step-th document, which streams only if
+// it is the last one.
+func (c RefineDocuments) stepOptions(step, numDocs int, options []ChainCallOption) []ChainCallOption {
+	if step < numDocs-1 {
+		return withoutStreaming(options)
+	}
+	return options
+}
+
 func (c RefineDocuments) constructInitialInputs(doc schema.Document, rest map[string]any) (map[string]any, error) {
 	return c.getBaseInputs(doc, rest)
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -63,3 +65,29 @@ func TestRetrievalQAFromLLM(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, strings.Contains(result, "34"), "expected 34 in result")
 }
+
+func TestRetrievalQAStreaming(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(fake.TextResponses("foo is 34", "nothing about foo", "Foo is 34.")...)
+	chain := NewRetrievalQA(LoadMapReduceQA(llm), testRetriever{})
+
+	var chunks, deltas []string
+	result, err := Run(context.Background(), chain, "what is foo?",
+		WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+		WithStreamingEventFunc(func(_ context.Context, event llms.StreamingEvent) error {
+			if event.Type == llms.StreamingEventTextDelta {
+				deltas = append(deltas, event.Text)
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Foo is 34.", result)
+	// only the reduce call streams, not the map calls.
+	require.Equal(t, []string{"Foo is 34."}, chunks)
+	require.Equal(t, []string{"Foo is 34."}, deltas)
+}
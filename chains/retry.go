@@ -0,0 +1,175 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _retryDefaultMaxAttempts = 3
+
+// ErrAllAttemptsFailed is returned by a Retry chain when all the attempts of
+// the chain and of its fallbacks failed.
+var ErrAllAttemptsFailed = errors.New("all chain attempts failed")
+
+// Retry is a chain calling a chain, retrying it with backoff when it fails,
+// and then falling back to alternate chains, e.g. using a cheaper model or a
+// smaller context, each retried the same way. The fallbacks are called with
+// the same inputs, and should return the same outputs.
+//
+// The attempts are notified to the callbacks handler if it implements
+// callbacks.AttemptHandler.
+type Retry struct {
+	Chain     Chain
+	Fallbacks []Chain
+	// MaxAttempts is the maximum number of attempts of each chain, including
+	// the first one.
+	MaxAttempts int
+	// Backoff returns the delay before each retry of a chain.
+	Backoff llms.BackoffFunc
+	// RetryIf returns whether a chain failing with the error is retried.
+	// Errors which aren't retried fall back to the next chain at once. If
+	// nil, all the errors are retried.
+	RetryIf          func(err error) bool
+	CallbacksHandler callbacks.Handler
+}
+
+var (
+	_ Chain                  = Retry{}
+	_ callbacks.HandlerHaver = Retry{}
+)
+
+// RetryOption is a function that configures a Retry.
+type RetryOption func(*Retry)
+
+// WithRetryMaxAttempts is an option for setting the maximum number of
+// attempts of each chain. Defaults to 3.
+func WithRetryMaxAttempts(maxAttempts int) RetryOption {
+	return func(r *Retry) {
+		r.MaxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff is an option for setting the delay before each retry.
+// Defaults to an exponential backoff starting at 500ms.
+func WithRetryBackoff(backoff llms.BackoffFunc) RetryOption {
+	return func(r *Retry) {
+		r.Backoff = backoff
+	}
+}
+
+// WithRetryIf is an option for setting which errors are retried, e.g. only
+// the errors of the provider wrapping llms.ErrRetriesExhausted.
+func WithRetryIf(retryIf func(err error) bool) RetryOption {
+	return func(r *Retry) {
+		r.RetryIf = retryIf
+	}
+}
+
+// WithRetryFallbacks is an option for setting the chains called, in order,
+// when the chain still fails after its retries.
+func WithRetryFallbacks(fallbacks ...Chain) RetryOption {
+	return func(r *Retry) {
+		r.Fallbacks = fallbacks
+	}
+}
+
+// WithRetryCallback is an option for setting the callbacks handler of the
+// chain.
+func WithRetryCallback(handler callbacks.Handler) RetryOption {
+	return func(r *Retry) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// NewRetry creates a new chain retrying the chain when it fails.
+func NewRetry(chain Chain, opts ...RetryOption) Retry {
+	r := Retry{
+		Chain:       chain,
+		MaxAttempts: _retryDefaultMaxAttempts,
+		Backoff:     llms.ExponentialBackoff(500*time.Millisecond, 30*time.Second), //nolint:mnd
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// Call calls the chain, retrying it and falling back to the fallbacks until
+// an attempt succeeds. If all fail, the errors of the last attempt of each
+// chain are returned with ErrAllAttemptsFailed. Errors of the context stop
+// the attempts.
+func (r Retry) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) {
+	attemptHandler, _ := r.CallbacksHandler.(callbacks.AttemptHandler)
+
+	var errs []error
+	for i, chain := range append([]Chain{r.Chain}, r.Fallbacks...) {
+		for attempt := 1; ; attempt++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			a := callbacks.ChainAttempt{Chain: i, Attempt: attempt}
+			if attemptHandler != nil {
+				attemptHandler.HandleChainAttemptStart(ctx, a)
+			}
+			outputs, err := Call(ctx, chain, values, options...)
+			if err == nil {
+				return outputs, nil
+			}
+			if attemptHandler != nil {
+				attemptHandler.HandleChainAttemptError(ctx, a, err)
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if attempt >= r.MaxAttempts || (r.RetryIf != nil && !r.RetryIf(err)) {
+				errs = append(errs, fmt.Errorf("chain %d: %w", i, err))
+				break
+			}
+			if err := r.wait(ctx, attempt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrAllAttemptsFailed, errors.Join(errs...))
+}
+
+func (r Retry) wait(ctx context.Context, retry int) error {
+	if r.Backoff == nil {
+		return nil
+	}
+	timer := time.NewTimer(r.Backoff(retry))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// GetMemory returns a simple memory, the memories of the chains being used
+// by their calls.
+func (r Retry) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (r Retry) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return r.CallbacksHandler
+}
+
+// GetInputKeys returns the input keys of the chain.
+func (r Retry) GetInputKeys() []string {
+	return r.Chain.GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (r Retry) GetOutputKeys() []string {
+	return r.Chain.GetOutputKeys()
+}
@@ -0,0 +1,72 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+var errTestUnavailable = errors.New("unavailable")
+
+// attemptRecorder records the attempts of chains.
+type attemptRecorder struct {
+	callbacks.SimpleHandler
+	started []callbacks.ChainAttempt
+	failed  []callbacks.ChainAttempt
+}
+
+func (h *attemptRecorder) HandleChainAttemptStart(_ context.Context, attempt callbacks.ChainAttempt) {
+	h.started = append(h.started, attempt)
+}
+
+func (h *attemptRecorder) HandleChainAttemptError(_ context.Context, attempt callbacks.ChainAttempt, _ error) {
+	h.failed = append(h.failed, attempt)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	prompt := prompts.NewPromptTemplate("{{.input}}", []string{"input"})
+	primary := fake.New(fake.Response{Err: errTestUnavailable}, fake.Response{Err: errTestUnavailable})
+	fallback := fake.New(fake.Response{Err: errTestUnavailable}, fake.Response{Content: "fallback"})
+	handler := &attemptRecorder{}
+	c := NewRetry(NewLLMChain(primary, prompt),
+		WithRetryMaxAttempts(2),
+		WithRetryBackoff(nil),
+		WithRetryFallbacks(NewLLMChain(fallback, prompt)),
+		WithRetryCallback(handler),
+	)
+
+	result, err := Run(context.Background(), c, "hello")
+	require.NoError(t, err)
+	require.Equal(t, "fallback", result)
+	require.Len(t, primary.Calls(), 2)
+	require.Equal(t, []callbacks.ChainAttempt{
+		{Chain: 0, Attempt: 1}, {Chain: 0, Attempt: 2}, {Chain: 1, Attempt: 1}, {Chain: 1, Attempt: 2},
+	}, handler.started)
+	require.Equal(t, handler.started[:3], handler.failed)
+}
+
+func TestRetryIf(t *testing.T) {
+	t.Parallel()
+
+	prompt := prompts.NewPromptTemplate("{{.input}}", []string{"input"})
+	llm := fake.New(fake.Response{Err: errTestUnavailable}, fake.Response{Err: errTestUnavailable})
+	c := NewRetry(NewLLMChain(llm, prompt), WithRetryIf(func(error) bool { return false }))
+
+	_, err := Run(context.Background(), c, "hello")
+	require.ErrorIs(t, err, ErrAllAttemptsFailed)
+	require.ErrorIs(t, err, errTestUnavailable)
+	require.Len(t, llm.Calls(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = NewRetry(NewLLMChain(fake.New(), prompt))
+	_, err = Run(ctx, c, "hello")
+	require.ErrorIs(t, err, context.Canceled)
+}
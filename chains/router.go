@@ -0,0 +1,205 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultRouterTemplate = `Given a raw text input to a language model, select the destination best suited for the input. You will be given the names of the available destinations and a description of what each destination is best suited for.
+
+Destinations:
+{{.destinations}}
+
+Answer with the name of the destination only, and nothing else. If none of the destinations is suited for the input, answer with DEFAULT.
+
+Input: {{.input}}
+Destination:`
+
+const (
+	_routerDefaultInputKey    = "input"
+	_routerDefaultDestination = "DEFAULT"
+)
+
+// ErrNoRoute is returned by a Router when no destination is selected for the
+// input and there is no default chain.
+var ErrNoRoute = errors.New("no destination chain for input")
+
+// Destination is a chain a Router can dispatch inputs to.
+type Destination struct {
+	// Name identifies the destination.
+	Name string
+	// Description tells what the destination is best suited for.
+	Description string
+	// Chain is the chain called with the input values given to the router.
+	Chain Chain
+}
+
+// Router is a chain that classifies its input and dispatches the input values
+// to one of several destination chains, or to the default chain when none of
+// them is suited for the input. The input is classified by an LLM with
+// NewLLMRouter, or by the similarity of its embedding with the embeddings of
+// the descriptions of the destinations with NewEmbeddingRouter.
+type Router struct {
+	Destinations []Destination
+	// DefaultChain is called when no destination is selected. If nil, such
+	// inputs fail with ErrNoRoute.
+	DefaultChain Chain
+	// InputKey is the key of the input to classify, by default "input".
+	InputKey string
+
+	route func(ctx context.Context, input string, options ...ChainCallOption) (string, error)
+}
+
+var _ Chain = Router{}
+
+// NewLLMRouter creates a new router asking the LLM to select the destination
+// of the inputs from the names and descriptions of the destinations.
+func NewLLMRouter(llm llms.Model, destinations []Destination, defaultChain Chain) Router {
+	descriptions := make([]string, len(destinations))
+	for i, d := range destinations {
+		descriptions[i] = fmt.Sprintf("%s: %s", d.Name, d.Description)
+	}
+	routerChain := NewLLMChain(llm, prompts.NewPromptTemplate(_defaultRouterTemplate, []string{"destinations", "input"}))
+
+	return Router{
+		Destinations: destinations,
+		DefaultChain: defaultChain,
+		InputKey:     _routerDefaultInputKey,
+		route: func(ctx context.Context, input string, options ...ChainCallOption) (string, error) {
+			// only the destination chain streams.
+			return Predict(ctx, routerChain, map[string]any{
+				"destinations": strings.Join(descriptions, "\n"),
+				"input":        input,
+			}, withoutStreaming(options)...)
+		},
+	}
+}
+
+// NewEmbeddingRouter creates a new router selecting the destination whose
+// description embedding is the most similar to the embedding of the inputs.
+// Inputs whose cosine similarity with all the descriptions is below the
+// threshold, e.g. 0.5, go to the default chain. The descriptions are embedded
+// once, when the router is created.
+func NewEmbeddingRouter(
+	ctx context.Context,
+	embedder embeddings.Embedder,
+	destinations []Destination,
+	defaultChain Chain,
+	threshold float32,
+) (Router, error) {
+	descriptions := make([]string, len(destinations))
+	for i, d := range destinations {
+		descriptions[i] = d.Description
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, descriptions)
+	if err != nil {
+		return Router{}, err
+	}
+
+	return Router{
+		Destinations: destinations,
+		DefaultChain: defaultChain,
+		InputKey:     _routerDefaultInputKey,
+		route: func(ctx context.Context, input string, _ ...ChainCallOption) (string, error) {
+			vector, err := embedder.EmbedQuery(ctx, input)
+			if err != nil {
+				return "", err
+			}
+			destination, best := _routerDefaultDestination, threshold
+			for i, v := range vectors {
+				if similarity := cosineSimilarity(vector, v); similarity >= best {
+					destination, best = destinations[i].Name, similarity
+				}
+			}
+			return destination, nil
+		},
+	}, nil
+}
+
+// Call selects the destination of the input and calls its chain with the
+// input values.
+func (c Router) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	name, err := c.route(ctx, input, options...)
+	if err != nil {
+		return nil, err
+	}
+	destination := c.destination(name)
+	if destination == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNoRoute, name)
+	}
+
+	return Call(ctx, destination, values, options...)
+}
+
+// destination returns the chain of the destination whose name is the answer
+// of the router, ignoring case and quotes, or the default chain.
+func (c Router) destination(answer string) Chain { //nolint:ireturn
+	name := strings.Trim(strings.TrimSpace(answer), "\"'`.")
+	for _, d := range c.Destinations {
+		if strings.EqualFold(d.Name, name) {
+			return d.Chain
+		}
+	}
+	return c.DefaultChain
+}
+
+func (c Router) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+func (c Router) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the output keys common to all the destination chains
+// and the default chain.
+func (c Router) GetOutputKeys() []string {
+	chains := make([]Chain, 0, len(c.Destinations)+1)
+	for _, d := range c.Destinations {
+		chains = append(chains, d.Chain)
+	}
+	if c.DefaultChain != nil {
+		chains = append(chains, c.DefaultChain)
+	}
+	if len(chains) == 0 {
+		return []string{}
+	}
+
+	outputKeys := append([]string{}, chains[0].GetOutputKeys()...)
+	for _, chain := range chains[1:] {
+		keys := chain.GetOutputKeys()
+		outputKeys = slices.DeleteFunc(outputKeys, func(key string) bool {
+			return !slices.Contains(keys, key)
+		})
+	}
+	return outputKeys
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(normA*normB))
+}
@@ -0,0 +1,84 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func testRouterDestinations() ([]Destination, Chain) {
+	echo := func(name string) Chain {
+		return NewLLMChain(fake.NewRepeating(fake.TextResponses(name)...),
+			prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	}
+	return []Destination{
+		{Name: "math", Description: "Good for answering math questions", Chain: echo("math")},
+		{Name: "physics", Description: "Good for answering physics questions", Chain: echo("physics")},
+	}, echo("default")
+}
+
+func TestLLMRouter(t *testing.T) {
+	t.Parallel()
+
+	destinations, defaultChain := testRouterDestinations()
+	llm := fake.New(fake.TextResponses(" Physics\n", "`math`", "DEFAULT", "poetry")...)
+	router := NewLLMRouter(llm, destinations, defaultChain)
+	require.Equal(t, []string{"text"}, router.GetOutputKeys())
+
+	for _, expected := range []string{"physics", "math", "default", "default"} {
+		result, err := Run(context.Background(), router, "What is black body radiation?")
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	}
+	require.Contains(t, llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text,
+		"math: Good for answering math questions")
+
+	router.DefaultChain = nil
+	llm.AddResponses(fake.TextResponses("poetry")...)
+	_, err := Run(context.Background(), router, "Write a haiku")
+	require.ErrorIs(t, err, ErrNoRoute)
+}
+
+// keywordEmbedder embeds texts by the occurrences of the keywords.
+type keywordEmbedder []string
+
+func (e keywordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e keywordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, len(e))
+	for i, keyword := range e {
+		vector[i] = float32(strings.Count(strings.ToLower(text), keyword))
+	}
+	return vector, nil
+}
+
+func TestEmbeddingRouter(t *testing.T) {
+	t.Parallel()
+
+	destinations, defaultChain := testRouterDestinations()
+	router, err := NewEmbeddingRouter(context.Background(), keywordEmbedder{"math", "physics", "questions"},
+		destinations, defaultChain, 0.8)
+	require.NoError(t, err)
+
+	for input, expected := range map[string]string{
+		"physics questions":   "physics",
+		"math questions":      "math",
+		"math math questions": "math",
+		"questions":           "default",
+	} {
+		result, err := Run(context.Background(), router, input)
+		require.NoError(t, err)
+		require.Equal(t, expected, result, input)
+	}
+}
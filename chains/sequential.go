@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/tmc/langchaingo/internal/util"
@@ -13,8 +14,14 @@ import (
 
 const delimiter = ","
 
-// SequentialChain is a chain that runs multiple chains in sequence,
-// where the output of one chain is the input of the next.
+// SequentialChain is a chain that runs multiple chains in sequence, where the
+// inputs of each chain are the inputs of the sequential chain and the outputs
+// of the previous chains. Keys are renamed between chains with
+// SequentialStep, and independent chains run concurrently with
+// ParallelChain.
+//
+// The keys are validated when the chain is created, so that pipelines with
+// missing or overlapping keys fail before running any chain.
 type SequentialChain struct {
 	chains     []Chain
 	inputKeys  []string
@@ -64,22 +71,21 @@ func (c *SequentialChain) validateSeqChain() error {
 		missingKeys := util.Difference(c.GetInputKeys(), knownKeys)
 		if len(missingKeys) > 0 {
 			return fmt.Errorf(
-				"%w: missing required input keys: [%v], only had: [%v]",
-				ErrChainInitialization, strings.Join(missingKeys, delimiter), strings.Join(util.ListKeys(knownKeys), delimiter),
-			)
-		}
-
-		// Check that chain does not have output keys that are already in knownKeys
-		overlappingKeys := util.Intersection(c.GetOutputKeys(), knownKeys)
-		if len(overlappingKeys) > 0 {
-			return fmt.Errorf(
-				"%w: chain at index %d has output keys that already exist: %v",
-				ErrChainInitialization, i, strings.Join(overlappingKeys, delimiter),
+				"%w: chain at index %d (%T) is missing required input keys: [%v], only had: [%v]",
+				ErrChainInitialization, i, c, strings.Join(missingKeys, delimiter),
+				strings.Join(util.ListKeys(knownKeys), delimiter),
 			)
 		}
 
-		// Add the chain's output keys to knownKeys
+		// Check that chain does not have output keys that are already in knownKeys,
+		// including the output keys of its own parallel branches.
 		for _, key := range c.GetOutputKeys() {
+			if _, ok := knownKeys[key]; ok {
+				return fmt.Errorf(
+					"%w: chain at index %d (%T) has output keys that already exist: %v",
+					ErrChainInitialization, i, c, key,
+				)
+			}
 			knownKeys[key] = struct{}{}
 		}
 	}
@@ -98,15 +104,19 @@ func (c *SequentialChain) validateSeqChain() error {
 // not be called directly. Use rather the Call, Run or Predict functions that
 // handles the memory and other aspects of the chain.
 func (c *SequentialChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
-	var outputs map[string]any
-	var err error
-	for _, chain := range c.chains {
-		outputs, err = Call(ctx, chain, inputs, options...)
+	values := maps.Clone(inputs)
+	for i, chain := range c.chains {
+		outputs, err := Call(ctx, chain, values, options...)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("chain at index %d (%T): %w", i, chain, err)
 		}
-		// Set the input for the next chain to the output of the current chain
-		inputs = outputs
+		// The outputs of the chain are inputs of the next chains.
+		maps.Copy(values, outputs)
+	}
+
+	outputs := make(map[string]any, len(c.outputKeys))
+	for _, key := range c.outputKeys {
+		outputs[key] = values[key]
 	}
 	return outputs, nil
 }
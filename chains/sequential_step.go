@@ -0,0 +1,196 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// SequentialStep is a step of a SequentialChain renaming the input and output
+// keys of its chain, so that chains written with different key names can be
+// connected.
+type SequentialStep struct {
+	Chain Chain
+	// InputMapping maps input keys of the chain to the keys of the pipeline
+	// they are read from. Unmapped keys are read with their own name.
+	InputMapping map[string]string
+	// OutputMapping maps output keys of the chain to the keys of the pipeline
+	// they are written to. Unmapped keys are written with their own name.
+	OutputMapping map[string]string
+}
+
+var _ Chain = SequentialStep{}
+
+// NewSequentialStep creates a new step renaming the input and output keys of
+// the chain.
+func NewSequentialStep(chain Chain, inputMapping, outputMapping map[string]string) SequentialStep {
+	return SequentialStep{
+		Chain:         chain,
+		InputMapping:  inputMapping,
+		OutputMapping: outputMapping,
+	}
+}
+
+// Call calls the chain with its input keys read from the mapped keys, and
+// returns its outputs under the mapped keys.
+func (s SequentialStep) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	chainInputs := maps.Clone(inputs)
+	for key, from := range s.InputMapping {
+		if v, ok := inputs[from]; ok {
+			chainInputs[key] = v
+		}
+	}
+
+	outputs, err := Call(ctx, s.Chain, chainInputs, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make(map[string]any, len(outputs))
+	for key, v := range outputs {
+		mapped[mapKey(s.OutputMapping, key)] = v
+	}
+	return mapped, nil
+}
+
+// GetMemory returns a simple memory, the memory of the chain being used by
+// its calls.
+func (s SequentialStep) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the keys of the pipeline read by the chain.
+func (s SequentialStep) GetInputKeys() []string {
+	keys := s.Chain.GetInputKeys()
+	mapped := make([]string, len(keys))
+	for i, key := range keys {
+		mapped[i] = mapKey(s.InputMapping, key)
+	}
+	return mapped
+}
+
+// GetOutputKeys returns the keys of the pipeline written by the chain.
+func (s SequentialStep) GetOutputKeys() []string {
+	keys := s.Chain.GetOutputKeys()
+	mapped := make([]string, len(keys))
+	for i, key := range keys {
+		mapped[i] = mapKey(s.OutputMapping, key)
+	}
+	return mapped
+}
+
+func mapKey(mapping map[string]string, key string) string {
+	if mapped, ok := mapping[key]; ok {
+		return mapped
+	}
+	return key
+}
+
+// ParallelChain is a chain running independent chains concurrently with the
+// same inputs, and joining their outputs. It is used as a step of a
+// SequentialChain for branches of the pipeline that don't depend on each
+// other. The output keys of the branches must not overlap.
+type ParallelChain struct {
+	Branches []Chain
+}
+
+var _ Chain = ParallelChain{}
+
+// NewParallelChain creates a new chain running the branches concurrently. It
+// returns an error if output keys of the branches overlap.
+func NewParallelChain(branches ...Chain) (ParallelChain, error) {
+	seen := make(map[string]int)
+	for i, branch := range branches {
+		for _, key := range branch.GetOutputKeys() {
+			if j, ok := seen[key]; ok {
+				return ParallelChain{}, fmt.Errorf(
+					"%w: branches at index %d and %d have the same output key: %v",
+					ErrChainInitialization, j, i, key,
+				)
+			}
+			seen[key] = i
+		}
+	}
+	return ParallelChain{Branches: branches}, nil
+}
+
+// Call calls the branches concurrently and returns their joined outputs. If
+// a branch fails, the context of the others is canceled and its error is
+// returned.
+func (c ParallelChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]map[string]any, len(c.Branches))
+	errs := make([]error, len(c.Branches))
+	var wg sync.WaitGroup
+	for i, branch := range c.Branches {
+		wg.Add(1)
+		go func(i int, branch Chain) {
+			defer wg.Done()
+			// Each branch gets its own copy, as chains may modify their inputs.
+			results[i], errs[i] = Call(ctx, branch, maps.Clone(inputs), options...)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, branch)
+	}
+	wg.Wait()
+
+	// Return the error of the first failing branch rather than the context
+	// errors of the branches it canceled.
+	var firstErr error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("branch at index %d (%T): %w", i, c.Branches[i], err)
+		if firstErr == nil || (errors.Is(firstErr, context.Canceled) && !errors.Is(err, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	outputs := make(map[string]any)
+	for _, result := range results {
+		maps.Copy(outputs, result)
+	}
+	return outputs, nil
+}
+
+// GetMemory returns a simple memory, the memories of the branches being used
+// by their calls.
+func (c ParallelChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the input keys of all the branches.
+func (c ParallelChain) GetInputKeys() []string {
+	keys := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, branch := range c.Branches {
+		for _, key := range branch.GetInputKeys() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// GetOutputKeys returns the output keys of all the branches.
+func (c ParallelChain) GetOutputKeys() []string {
+	keys := make([]string, 0)
+	for _, branch := range c.Branches {
+		keys = append(keys, branch.GetOutputKeys()...)
+	}
+	return keys
+}
@@ -3,6 +3,8 @@ package chains
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -192,6 +194,77 @@ func TestSequentialChainErrors(t *testing.T) {
 	}
 }
 
+func TestSequentialChainMappingAndParallel(t *testing.T) {
+	t.Parallel()
+
+	upper := NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		return map[string]any{"text": strings.ToUpper(inputs["text"].(string))}, nil
+	}, []string{"text"}, []string{"text"})
+	length := NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		return map[string]any{"length": len(inputs["text"].(string))}, nil
+	}, []string{"text"}, []string{"length"})
+	join := NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		return map[string]any{"summary": fmt.Sprintf("%s (%d)", inputs["upper"], inputs["length"])}, nil
+	}, []string{"upper", "length"}, []string{"summary"})
+
+	branches, err := NewParallelChain(
+		NewSequentialStep(upper, map[string]string{"text": "title"}, map[string]string{"text": "upper"}),
+		NewSequentialStep(length, map[string]string{"text": "title"}, nil),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"title"}, branches.GetInputKeys())
+	assert.Equal(t, []string{"upper", "length"}, branches.GetOutputKeys())
+
+	seqChain, err := NewSequentialChain([]Chain{branches, join}, []string{"title"}, []string{"summary", "upper"})
+	require.NoError(t, err)
+
+	res, err := Call(context.Background(), seqChain, map[string]any{"title": "chicken"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"summary": "CHICKEN (7)", "upper": "CHICKEN"}, res)
+}
+
+func TestSequentialChainValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSequentialChain([]Chain{
+		&testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}},
+		NewSequentialStep(&testLLMChain{inputKeys: []string{"text"}, outputKeys: []string{"result"}},
+			map[string]string{"text": "missing"}, nil),
+	}, []string{"input"}, []string{"result"})
+	require.ErrorIs(t, err, ErrChainInitialization)
+	assert.Contains(t, err.Error(), "chain at index 1")
+	assert.Contains(t, err.Error(), "missing")
+
+	_, err = NewSequentialChain([]Chain{
+		&testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output", "output"}},
+	}, []string{"input"}, []string{"output"})
+	require.ErrorIs(t, err, ErrChainInitialization)
+
+	_, err = NewParallelChain(
+		&testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}},
+		&testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}},
+	)
+	require.ErrorIs(t, err, ErrChainInitialization)
+}
+
+func TestParallelChainError(t *testing.T) {
+	t.Parallel()
+
+	blocking := NewTransform(func(ctx context.Context, _ map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, []string{"input"}, []string{"slow"})
+	branches, err := NewParallelChain(
+		blocking,
+		&testLLMChain{inputKeys: []string{"input"}, outputKeys: []string{"output"}, err: errDummy},
+	)
+	require.NoError(t, err)
+
+	_, err = Call(context.Background(), branches, map[string]any{"input": "foo"})
+	require.ErrorIs(t, err, errDummy)
+	assert.Contains(t, err.Error(), "branch at index 1")
+}
+
 // LLMChain for testing purposes.
 type testLLMChain struct {
 	err        error
@@ -2,7 +2,11 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/tmc/langchaingo/llms"
@@ -18,6 +22,8 @@ const _defaultSQLTemplate = `Given an input question, first create a syntactical
 Never query for all the columns from a specific table, only ask for a the few relevant columns given the question.
 
 Pay attention to use only the column names that you can see in the schema description. Be careful to not query for columns that do not exist. Also, pay attention to which column is in which table.
+{{.dialect_instructions}}
+Only write read-only SELECT queries, never queries modifying the database.
 
 Use the following format:
 
@@ -38,27 +44,95 @@ const (
 	_sqlChainDefaultInputKeyQuery      = "query"
 	_sqlChainDefaultInputKeyTableNames = "table_names_to_use"
 	_sqlChainDefaultOutputKey          = "result"
+	_sqlChainDefaultMaxRetries         = 2
+	_sqlChainDefaultMaxResultTokens    = 1000
 )
 
+// ErrUnsafeSQLQuery is returned when the SQL query generated by the LLM of a SQLDatabaseChain
+// isn't a single read-only query.
+var ErrUnsafeSQLQuery = errors.New("unsafe sql query")
+
+// _sqlDialects are the names and prompt instructions of the dialects of the sqldatabase engines.
+//
+//nolint:gochecknoglobals,lll
+var _sqlDialects = map[string]struct{ name, instructions string }{
+	"mysql": {
+		name:         "MySQL",
+		instructions: "Wrap each column name in backticks (`) to denote it as a delimited identifier. Use the CURDATE() function to get the current date, if the question involves \"today\".",
+	},
+	"pgx": {
+		name:         "PostgreSQL",
+		instructions: `Wrap each column name in double quotes (") to denote it as a delimited identifier. Use the CURRENT_DATE function to get the current date, if the question involves "today".`,
+	},
+	"sqlite3": {
+		name:         "SQLite",
+		instructions: `Wrap each column name in double quotes (") to denote it as a delimited identifier. Use the date('now') function to get the current date, if the question involves "today".`,
+	},
+}
+
+// _sqlDeniedKeywords are the keywords of statements modifying the database or its settings,
+// and the functions with side effects: writing files, changing settings or sequences, taking
+// locks or signaling other sessions.
+//
+//nolint:gochecknoglobals
+var _sqlDeniedKeywords = []string{
+	"INSERT", "UPDATE", "DELETE", "MERGE", "UPSERT", "INTO",
+	"CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME",
+	"GRANT", "REVOKE", "ATTACH", "DETACH", "PRAGMA", "COPY",
+	"CALL", "EXEC", "EXECUTE", "LOCK", "VACUUM", "SET", "COMMIT", "ROLLBACK",
+	"OUTFILE", "DUMPFILE", "LOAD_FILE", "LOAD_EXTENSION", "WRITEFILE", "SLEEP", "BENCHMARK", "GET_LOCK",
+	"SET_CONFIG", "NEXTVAL", "SETVAL", "PG_SLEEP", "PG_TERMINATE_BACKEND", "PG_CANCEL_BACKEND",
+	"PG_RELOAD_CONF", "PG_ROTATE_LOGFILE", "PG_READ_FILE", "PG_READ_BINARY_FILE", "PG_LS_DIR",
+	"PG_ADVISORY_LOCK", "PG_ADVISORY_XACT_LOCK", "LO_IMPORT", "LO_EXPORT", "LO_UNLINK", "LO_CREATE",
+	"LO_PUT", "LO_FROM_BYTEA", "DBLINK", "DBLINK_EXEC",
+}
+
+// _sqlDollarTagRE matches the opening tag of the dollar-quoted strings of PostgreSQL.
+//
+//nolint:gochecknoglobals
+var _sqlDollarTagRE = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// _sqlWordRE matches the keywords and identifiers of upper-cased sql queries.
+//
+//nolint:gochecknoglobals
+var _sqlWordRE = regexp.MustCompile(`[A-Z_][A-Z0-9_]*`)
+
 // SQLDatabaseChain is a chain used for interacting with SQL Database.
+//
+// The queries generated by the LLM must be single SELECT statements without any of the
+// DeniedKeywords, are limited to TopK rows if they have no LIMIT clause, and run in read-only
+// transactions when the engine of the database supports them (see sqldatabase.ReadOnlyEngine). When a query is
+// invalid or fails, the error is given back to the LLM to correct it, up to MaxRetries times.
 type SQLDatabaseChain struct {
 	LLMChain  *LLMChain
 	TopK      int
 	Database  *sqldatabase.SQLDatabase
 	OutputKey string
+
+	// MaxRetries is the number of times the LLM is asked to correct an invalid or failing query.
+	MaxRetries int
+	// MaxResultTokens is the number of tokens of the query results given to the LLM, the
+	// rows exceeding it being truncated. If zero, the results aren't truncated.
+	MaxResultTokens int
+	// DeniedKeywords are the keywords, outside of literals and quoted identifiers, rejected in
+	// queries.
+	DeniedKeywords []string
 }
 
 // NewSQLDatabaseChain creates a new SQLDatabaseChain.
 // The topK is the max number of results to return.
 func NewSQLDatabaseChain(llm llms.Model, topK int, database *sqldatabase.SQLDatabase) *SQLDatabaseChain {
 	p := prompts.NewPromptTemplate(_defaultSQLTemplate+_defaultSQLSuffix,
-		[]string{"dialect", "top_k", "table_info", "input"})
+		[]string{"dialect", "dialect_instructions", "top_k", "table_info", "input"})
 	c := NewLLMChain(llm, p)
 	return &SQLDatabaseChain{
-		LLMChain:  c,
-		TopK:      topK,
-		Database:  database,
-		OutputKey: _sqlChainDefaultOutputKey,
+		LLMChain:        c,
+		TopK:            topK,
+		Database:        database,
+		OutputKey:       _sqlChainDefaultOutputKey,
+		MaxRetries:      _sqlChainDefaultMaxRetries,
+		MaxResultTokens: _sqlChainDefaultMaxResultTokens,
+		DeniedKeywords:  _sqlDeniedKeywords,
 	}
 }
 
@@ -73,6 +147,8 @@ func NewSQLDatabaseChain(llm llms.Model, topK int, database *sqldatabase.SQLData
 //
 //	"result" : with the result of the query.
 //
+// Only the LLM call generating the answer streams.
+//
 //nolint:all
 func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) {
 	query, ok := inputs[_sqlChainDefaultInputKeyQuery].(string)
@@ -99,35 +175,42 @@ func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, optio
 		queryPrefixWith = "\nSQLQuery:"  //nolint:gosec
 		stopWord        = "\nSQLResult:" //nolint:gosec
 	)
-	llmInputs := map[string]any{
-		"input":      query + queryPrefixWith,
-		"top_k":      s.TopK,
-		"dialect":    s.Database.Dialect(),
-		"table_info": tableInfos,
+	dialect, instructions := s.Database.Dialect(), ""
+	if d, ok := _sqlDialects[dialect]; ok {
+		dialect, instructions = d.name, "\n"+d.instructions+"\n"
 	}
-
-	// Predict sql query
-	opt := append(options, WithStopWords([]string{stopWord})) //nolint:cyclop
-	out, err := Predict(ctx, s.LLMChain, llmInputs, opt...)
-	if err != nil {
-		return nil, err
+	llmInputs := map[string]any{
+		"input":                query + queryPrefixWith,
+		"top_k":                s.TopK,
+		"dialect":              dialect,
+		"dialect_instructions": instructions,
+		"table_info":           tableInfos,
 	}
 
-	sqlQuery := extractSQLQuery(out)
-
-	if sqlQuery == "" {
-		return nil, fmt.Errorf("no sql query generated")
-	}
+	// Predict and execute the sql query, feeding errors back to the llm.
+	opt := append(withoutStreaming(options), WithStopWords([]string{stopWord})) //nolint:cyclop
+	var sqlQuery, queryResult string
+	for attempt := 0; ; attempt++ {
+		out, err := Predict(ctx, s.LLMChain, llmInputs, opt...)
+		if err != nil {
+			return nil, err
+		}
 
-	// Execute sql query
-	queryResult, err := s.Database.Query(ctx, sqlQuery)
-	if err != nil {
-		return nil, err
+		sqlQuery = extractSQLQuery(out)
+		queryResult, err = s.runQuery(ctx, sqlQuery)
+		if err == nil {
+			break
+		}
+		if attempt >= s.MaxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+		llmInputs["input"] = query + queryPrefixWith + " " + sqlQuery + "\nSQLError: " + err.Error() +
+			"\nThe SQLQuery is invalid, write a corrected one." + queryPrefixWith
 	}
 
 	// Generate answer
-	llmInputs["input"] = query + queryPrefixWith + sqlQuery + stopWord + queryResult
-	out, err = Predict(ctx, s.LLMChain, llmInputs, options...)
+	llmInputs["input"] = query + queryPrefixWith + sqlQuery + stopWord + s.truncateResult(queryResult)
+	out, err := Predict(ctx, s.LLMChain, llmInputs, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +225,171 @@ func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, optio
 	return map[string]any{s.OutputKey: out}, nil
 }
 
+// runQuery validates the sql query, limits it to TopK rows if it has no limit, and runs it.
+func (s SQLDatabaseChain) runQuery(ctx context.Context, sqlQuery string) (string, error) {
+	if sqlQuery == "" {
+		return "", fmt.Errorf("no sql query generated")
+	}
+	dialect := s.Database.Dialect()
+	words, err := s.validateSQLQuery(dialect, sqlQuery)
+	if err != nil {
+		return "", err
+	}
+	if s.TopK > 0 && !slices.Contains(words, "LIMIT") {
+		// remove the final semicolon, which may be followed by comments, and add the limit on a
+		// new line, in case the query ends with a comment.
+		stripped := stripSQLLiterals(sqlQuery, dialect, dialect == "mysql")
+		if end := strings.LastIndexByte(stripped, ';'); end >= 0 && strings.TrimSpace(stripped[end+1:]) == "" {
+			sqlQuery = sqlQuery[:end] + sqlQuery[end+1:]
+		}
+		sqlQuery = strings.TrimSpace(sqlQuery) + fmt.Sprintf("\nLIMIT %d", s.TopK)
+	}
+
+	return s.Database.QueryReadOnly(ctx, sqlQuery)
+}
+
+// validateSQLQuery checks that the sql query of the dialect is a single SELECT statement
+// without denied keywords, and returns its upper-cased keywords and identifiers.
+//
+// Whether backslashes escape characters in string literals depends on the settings of MySQL
+// (NO_BACKSLASH_ESCAPES) and PostgreSQL (standard_conforming_strings), so their queries are
+// checked both ways.
+func (s SQLDatabaseChain) validateSQLQuery(dialect, sqlQuery string) ([]string, error) {
+	words, err := s.validateStrippedSQLQuery(stripSQLLiterals(sqlQuery, dialect, dialect == "mysql"))
+	if err != nil {
+		return nil, err
+	}
+	if dialect == "mysql" || dialect == "pgx" {
+		if _, err := s.validateStrippedSQLQuery(stripSQLLiterals(sqlQuery, dialect, dialect != "mysql")); err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+func (s SQLDatabaseChain) validateStrippedSQLQuery(stripped string) ([]string, error) {
+	stripped = strings.TrimSpace(stripped)
+	if strings.Contains(strings.TrimRight(stripped, "; \t\n"), ";") {
+		return nil, fmt.Errorf("%w: multiple statements", ErrUnsafeSQLQuery)
+	}
+
+	words := _sqlWordRE.FindAllString(strings.ToUpper(stripped), -1)
+	if len(words) == 0 || (words[0] != "SELECT" && words[0] != "WITH") {
+		return nil, fmt.Errorf("%w: not a SELECT statement", ErrUnsafeSQLQuery)
+	}
+	for _, word := range words {
+		if slices.Contains(s.DeniedKeywords, word) {
+			return nil, fmt.Errorf("%w: denied keyword %s", ErrUnsafeSQLQuery, word)
+		}
+	}
+
+	return words, nil
+}
+
+// truncateResult keeps the header and the first rows of the query result fitting in
+// MaxResultTokens tokens.
+func (s SQLDatabaseChain) truncateResult(result string) string {
+	if s.MaxResultTokens <= 0 || llms.CountTokens("", result) <= s.MaxResultTokens {
+		return result
+	}
+
+	lines := strings.SplitAfter(strings.TrimSuffix(result, "\n"), "\n")
+	// binary search the number of rows fitting in the budget.
+	rows := sort.Search(len(lines), func(n int) bool {
+		return llms.CountTokens("", strings.Join(lines[:n+1], "")) > s.MaxResultTokens
+	}) - 1
+	rows = max(rows, 0)
+
+	return strings.Join(lines[:rows+1], "") + fmt.Sprintf("(%d more rows truncated)\n", len(lines)-1-rows)
+}
+
+// stripSQLLiterals replaces the string literals, quoted identifiers and comments of the sql
+// query with spaces, keeping the offsets of the rest of the query. The literals are those of
+// the dialect, their backslashes escaping characters if backslashEscapes is true. The
+// executable comments of MySQL (/*! ... */) aren't comments and are kept.
+//
+//nolint:cyclop,funlen
+func stripSQLLiterals(sqlQuery, dialect string, backslashEscapes bool) string {
+	b := []byte(sqlQuery)
+	blank := func(start, end int) int {
+		end = min(end, len(b))
+		for i := start; i < end; i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+		return end
+	}
+	lineComment := func(i int) int {
+		end := strings.IndexByte(sqlQuery[i:], '\n')
+		if end < 0 {
+			end = len(b) - i
+		}
+		return blank(i, i+end) - 1
+	}
+
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '\'' || c == '"' || c == '`' || (c == '[' && dialect == "sqlite3"):
+			closing, escapes := c, backslashEscapes && c != '`'
+			switch {
+			case c == '[':
+				closing, escapes = ']', false
+			case c == '\'' && dialect == "pgx" && i > 0 && (b[i-1] == 'E' || b[i-1] == 'e') &&
+				(i == 1 || !isSQLWordByte(b[i-2])):
+				// the backslashes of the escape strings of PostgreSQL always escape characters.
+				escapes = true
+			}
+			// the literal ends at the closing quote, doubled quotes being escaped ones.
+			end := i + 1
+			for ; end < len(b); end++ {
+				if escapes && b[end] == '\\' {
+					end++
+					continue
+				}
+				if b[end] == closing {
+					if end+1 < len(b) && b[end+1] == closing {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			i = blank(i, end+1) - 1
+		case c == '$' && dialect == "pgx" && (i == 0 || !isSQLWordByte(b[i-1])):
+			// the dollar-quoted strings of PostgreSQL end at their opening tag.
+			tag := _sqlDollarTagRE.FindString(sqlQuery[i:])
+			if tag == "" {
+				continue
+			}
+			end := strings.Index(sqlQuery[i+len(tag):], tag)
+			if end < 0 {
+				end = len(b)
+			}
+			i = blank(i, i+len(tag)+end+len(tag)) - 1
+		case c == '-' && i+1 < len(b) && b[i+1] == '-':
+			i = lineComment(i)
+		case c == '#' && dialect == "mysql":
+			i = lineComment(i)
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			if dialect == "mysql" && i+2 < len(b) && b[i+2] == '!' {
+				i += 2
+				continue
+			}
+			end := strings.Index(sqlQuery[i+2:], "*/")
+			if end < 0 {
+				end = len(b)
+			}
+			i = blank(i, i+2+end+2) - 1
+		}
+	}
+	return string(b)
+}
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= 0x80
+}
+
 func (s SQLDatabaseChain) GetMemory() schema.Memory { //nolint:ireturn
 	return memory.NewSimple()
 }
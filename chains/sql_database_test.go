@@ -2,10 +2,14 @@ package chains
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/tools/sqldatabase"
 	"github.com/tmc/langchaingo/tools/sqldatabase/mysql"
@@ -136,3 +140,143 @@ orders.order_date;`,
 		require.Equal(t, tc.expected, filterQuerySyntax)
 	}
 }
+
+// testSQLEngine is a sqlite3 engine with a cards table, recording its queries.
+type testSQLEngine struct {
+	queries []string
+	rows    int
+}
+
+func (e *testSQLEngine) Dialect() string { return "sqlite3" }
+
+func (e *testSQLEngine) Query(_ context.Context, query string, _ ...any) ([]string, [][]string, error) {
+	e.queries = append(e.queries, query)
+	if strings.Contains(query, "missing") {
+		return nil, nil, fmt.Errorf("no such column: missing")
+	}
+	results := make([][]string, e.rows)
+	for i := range results {
+		results[i] = []string{fmt.Sprintf("card %d", i)}
+	}
+	return []string{"name"}, results, nil
+}
+
+func (e *testSQLEngine) TableNames(context.Context) ([]string, error) { return []string{"cards"}, nil }
+
+func (e *testSQLEngine) TableInfo(context.Context, string) (string, error) {
+	return "CREATE TABLE cards (name TEXT)", nil
+}
+
+func (e *testSQLEngine) Close() error { return nil }
+
+func TestSQLDatabaseChainRetries(t *testing.T) {
+	t.Parallel()
+
+	engine := &testSQLEngine{rows: 2}
+	db, err := sqldatabase.NewSQLDatabase(engine, nil)
+	require.NoError(t, err)
+	db.SampleRowsNumber = 0
+
+	llm := fake.New(fake.TextResponses(
+		"SQLQuery: DELETE FROM cards",
+		"SQLQuery: SELECT missing FROM cards",
+		"SQLQuery: SELECT \"name\" FROM cards; -- all cards",
+		"Answer: card 0 and card 1",
+	)...)
+	chain := NewSQLDatabaseChain(llm, 5, db)
+	result, err := Run(context.Background(), chain, "Which cards are there?")
+	require.NoError(t, err)
+	require.Equal(t, "card 0 and card 1", result)
+
+	require.Equal(t, []string{"SELECT missing FROM cards\nLIMIT 5", "SELECT \"name\" FROM cards -- all cards\nLIMIT 5"},
+		engine.queries)
+	calls := llm.Calls()
+	require.Len(t, calls, 4)
+	prompt := calls[0].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "syntactically correct SQLite query")
+	require.Contains(t, prompt, "date('now')")
+	prompt = calls[1].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "SQLQuery: DELETE FROM cards\nSQLError: unsafe sql query: not a SELECT statement")
+	prompt = calls[2].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "SQLError: no such column: missing")
+
+	chain.MaxRetries = 0
+	llm.AddResponses(fake.TextResponses("SQLQuery: SELECT missing FROM cards")...)
+	_, err = Run(context.Background(), chain, "Which cards are there?")
+	require.EqualError(t, err, "no such column: missing")
+}
+
+func TestSQLDatabaseChainTruncatesResults(t *testing.T) {
+	t.Parallel()
+
+	engine := &testSQLEngine{rows: 1000}
+	db, err := sqldatabase.NewSQLDatabase(engine, nil)
+	require.NoError(t, err)
+	db.SampleRowsNumber = 0
+
+	llm := fake.New(fake.TextResponses("SQLQuery: SELECT name FROM cards LIMIT 1000", "Answer: many")...)
+	chain := NewSQLDatabaseChain(llm, 5, db)
+	chain.MaxResultTokens = 100
+	_, err = Run(context.Background(), chain, "Which cards are there?")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"SELECT name FROM cards LIMIT 1000"}, engine.queries)
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "SQLResult:name\ncard 0\n")
+	require.Contains(t, prompt, "more rows truncated)")
+	require.NotContains(t, prompt, "card 999")
+}
+
+func TestValidateSQLQuery(t *testing.T) {
+	t.Parallel()
+
+	chain := SQLDatabaseChain{DeniedKeywords: _sqlDeniedKeywords}
+	for query, valid := range map[string]bool{
+		"SELECT name FROM cards":                                true,
+		"  with c AS (SELECT name FROM cards) SELECT * FROM c;": true,
+		"SELECT 'DROP TABLE cards' AS \"delete\" FROM cards":    true,
+		"SELECT name FROM cards -- DELETE\n":                    true,
+		"SELECT name FROM cards /* ; UPDATE */":                 true,
+		"DELETE FROM cards":                                     false,
+		"SELECT name FROM cards; DROP TABLE cards":              false,
+		"WITH d AS (DELETE FROM cards RETURNING *) SELECT 1":    false,
+		"SELECT name INTO copy FROM cards":                      false,
+		"PRAGMA table_info(cards)":                              false,
+		"":                                                      false,
+	} {
+		_, err := chain.validateSQLQuery("sqlite3", query)
+		if valid {
+			require.NoError(t, err, query)
+		} else {
+			require.ErrorIs(t, err, ErrUnsafeSQLQuery, query)
+		}
+	}
+
+	for _, tc := range []struct {
+		dialect, query string
+		valid          bool
+	}{
+		{"mysql", `SELECT 'it\'s', "a\"b" FROM cards`, true},
+		{"mysql", `SELECT 'a\'' INTO OUTFILE '/tmp/x' -- '`, false},
+		{"mysql", `SELECT 'a\' INTO OUTFILE '/tmp/x' -- '`, false},
+		{"mysql", "SELECT 1 # '\nINTO OUTFILE '/tmp/x' -- '", false},
+		{"mysql", "SELECT 1 /*!50000 INTO OUTFILE '/tmp/x' */", false},
+		{"mysql", "SELECT 1 /* INTO */", true},
+		{"pgx", `SELECT E'a\'' INTO copy FROM cards -- '`, false},
+		{"pgx", `SELECT 'a\' AS "b" FROM cards`, true},
+		{"pgx", "SELECT $$'$$ INTO copy FROM cards -- '", false},
+		{"pgx", "SELECT $tag$ DROP TABLE cards $tag$ AS t", true},
+		{"pgx", "SELECT set_config('default_transaction_read_only', 'off', false)", false},
+		{"pgx", "SELECT pg_terminate_backend(pid) FROM pg_stat_activity", false},
+		{"pgx", "SELECT lo_export(16384, '/tmp/x')", false},
+		{"sqlite3", "SELECT [a'] INTO copy FROM cards -- '", false},
+		{"sqlite3", "SELECT load_extension('x')", false},
+	} {
+		_, err := chain.validateSQLQuery(tc.dialect, tc.query)
+		if tc.valid {
+			require.NoError(t, err, tc.query)
+		} else {
+			require.ErrorIs(t, err, ErrUnsafeSQLQuery, tc.query)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/schema"
 )
@@ -34,6 +35,21 @@ type StuffDocuments struct {
 
 	// Separator is the string used to join the documents.
 	Separator string
+
+	// MaxTokens is the maximum number of tokens of the prompt, e.g. the
+	// ContextBudget of the model. If set, the documents are stuffed in order
+	// until the prompt is full, the last one being truncated, unless
+	// OverflowChain is set. Zero means no limit.
+	MaxTokens int
+
+	// TokenCounter measures the prompt. Defaults to the token counting of
+	// the model of the LLM chain.
+	TokenCounter llms.TokenCounter
+
+	// OverflowChain, e.g. a MapReduceDocuments chain, is called with the
+	// input values instead of the LLM chain when the documents don't fit in
+	// MaxTokens. It should return the same output keys as the LLM chain.
+	OverflowChain Chain
 }
 
 var _ Chain = StuffDocuments{}
@@ -64,10 +80,32 @@ func (c StuffDocuments) Call(
 		inputValues[key] = value
 	}
 
+	if c.MaxTokens > 0 {
+		fitted, overflow, err := c.fitDocuments(ctx, inputValues, docs)
+		if err != nil {
+			return nil, err
+		}
+		if overflow && c.OverflowChain != nil {
+			return Call(ctx, c.OverflowChain, values, options...)
+		}
+		docs = fitted
+	}
+
 	inputValues[c.DocumentVariableName] = c.joinDocuments(docs)
 	return Call(ctx, c.LLMChain, inputValues, options...)
 }
 
+// fitDocuments returns the documents fitting in the prompt and whether
+// documents were dropped or truncated.
+func (c StuffDocuments) fitDocuments(ctx context.Context, values map[string]any, docs []schema.Document) ([]schema.Document, bool, error) { //nolint:lll
+	measure := tokenMeasure{counter: c.TokenCounter, model: c.LLMChain.LLM}
+	promptTokens, err := measure.promptTokens(ctx, c.LLMChain, values, c.DocumentVariableName)
+	if err != nil {
+		return nil, false, err
+	}
+	return measure.fitDocuments(ctx, docs, c.MaxTokens-promptTokens, c.Separator)
+}
+
 // GetMemory returns a simple memory.
 func (c StuffDocuments) GetMemory() schema.Memory {
 	return memory.NewSimple()
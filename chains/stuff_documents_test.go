@@ -2,10 +2,12 @@ package chains
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -93,3 +95,39 @@ func TestStuffDocuments_joinDocs(t *testing.T) {
 		})
 	}
 }
+
+func TestStuffDocumentsMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{
+		{PageContent: "aaa"},
+		{PageContent: "bbb"},
+		{PageContent: "ccccc"},
+	}
+	chain := NewStuffDocuments(NewLLMChain(
+		&testLanguageModel{},
+		prompts.NewPromptTemplate("Q: {{.context}}", []string{"context"}),
+	))
+	chain.TokenCounter = llms.ApproximateTokenCounter{CharsPerToken: 1}
+
+	// The prompt, the first two documents and separators take 13 tokens, the
+	// third document is truncated to the 2 tokens left.
+	chain.MaxTokens = 15
+	result, err := Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "Q: aaa\n\nbbb\n\ncc", result)
+
+	chain.MaxTokens = 100
+	result, err = Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "Q: aaa\n\nbbb\n\nccccc", result)
+
+	chain.MaxTokens = 15
+	chain.OverflowChain = NewTransform(func(_ context.Context, values map[string]any, _ ...ChainCallOption) (map[string]any, error) { //nolint:lll
+		spilled := values["input_documents"].([]schema.Document)
+		return map[string]any{"text": fmt.Sprintf("%d documents spilled", len(spilled))}, nil
+	}, []string{"input_documents"}, []string{"text"})
+	result, err = Run(context.Background(), chain, docs)
+	require.NoError(t, err)
+	require.Equal(t, "3 documents spilled", result)
+}
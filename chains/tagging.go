@@ -0,0 +1,301 @@
+package chains
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultTaggingTemplate = `Classify the following text with the label of the taxonomy below that best describes it, and call the tag function with it and your confidence in it, between 0 and 1.
+
+Taxonomy:
+{{.labels}}
+
+Text:
+{{.input}}`
+
+//nolint:lll
+const _defaultMultiTaggingTemplate = `Classify the following text with all the labels of the taxonomy below that describe it, and call the tag function with them and your confidence in each of them, between 0 and 1. If no label describes the text, call the function with no tags.
+
+Taxonomy:
+{{.labels}}
+
+Text:
+{{.input}}`
+
+const (
+	_taggingFunctionName      = "tag"
+	_taggingDefaultInputKey   = "input"
+	_taggingDefaultOutputKey  = "tags"
+	_taggingDefaultMaxRetries = 2
+)
+
+// ErrInvalidTags is returned by a Tagging chain when the LLM doesn't tag the
+// text with valid labels.
+var ErrInvalidTags = errors.New("invalid tags")
+
+// Label is a label of the taxonomy of a Tagging chain.
+type Label struct {
+	// Name is the label given to texts.
+	Name string
+	// Description tells which texts the label is for.
+	Description string
+}
+
+// Tag is a label given to a text.
+type Tag struct {
+	// Label is the name of the label.
+	Label string `json:"label"`
+	// Confidence is the confidence of the LLM in the label, between 0 and 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// Tagging is a chain labeling texts with the labels of a fixed taxonomy, a
+// single one or, if MultiLabel is set, all the labels describing them, with
+// the confidence of the LLM in each label. The LLM is asked to call a
+// function whose parameters only accept the labels of the taxonomy, and
+// invalid calls are given back to the LLM to correct them, up to MaxRetries
+// times. The tags are returned under the "tags" output key, by decreasing
+// confidence.
+type Tagging struct {
+	LLM    llms.Model
+	Memory schema.Memory
+	// Prompt is the prompt asking the LLM to call the tag function, with the
+	// labels and input variables.
+	Prompt prompts.PromptTemplate
+	// Labels is the taxonomy.
+	Labels []Label
+	// MultiLabel is whether texts are tagged with all the labels describing
+	// them, rather than with a single one.
+	MultiLabel bool
+	// MinConfidence is the confidence below which tags are dropped.
+	MinConfidence float64
+	// MaxRetries is the number of times the LLM is asked to correct invalid
+	// tags.
+	MaxRetries int
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = Tagging{}
+
+// NewTagging creates a new chain tagging texts with the labels. If
+// multiLabel is true, texts are tagged with all the labels describing them,
+// otherwise with a single one.
+func NewTagging(llm llms.Model, labels []Label, multiLabel bool) Tagging {
+	template := _defaultTaggingTemplate
+	if multiLabel {
+		template = _defaultMultiTaggingTemplate
+	}
+	return Tagging{
+		LLM:        llm,
+		Memory:     memory.NewSimple(),
+		Prompt:     prompts.NewPromptTemplate(template, []string{"labels", "input"}),
+		Labels:     labels,
+		MultiLabel: multiLabel,
+		MaxRetries: _taggingDefaultMaxRetries,
+		InputKey:   _taggingDefaultInputKey,
+		OutputKey:  _taggingDefaultOutputKey,
+	}
+}
+
+// Call tags the input text. The tags are a []Tag.
+func (c Tagging) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) {
+	text, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	tags, err := c.Tag(ctx, text, options...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{c.OutputKey: tags}, nil
+}
+
+// Tag returns the tags of the text with a confidence of at least
+// MinConfidence, by decreasing confidence.
+func (c Tagging) Tag(ctx context.Context, text string, options ...ChainCallOption) ([]Tag, error) {
+	labels := make([]string, len(c.Labels))
+	for i, l := range c.Labels {
+		labels[i] = l.Name
+		if l.Description != "" {
+			labels[i] += ": " + l.Description
+		}
+	}
+	prompt, err := c.Prompt.Format(map[string]any{"labels": strings.Join(labels, "\n"), "input": text})
+	if err != nil {
+		return nil, err
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}
+	callOptions := append(getLLMCallOptions(withoutStreaming(options)...),
+		llms.WithTools([]llms.Tool{c.tool()}),
+		llms.WithToolChoice(llms.ToolChoice{
+			Type:     "function",
+			Function: &llms.FunctionReference{Name: _taggingFunctionName},
+		}),
+	)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.LLM.GenerateContent(ctx, messages, callOptions...)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("%w: empty response", ErrInvalidTags)
+		}
+
+		arguments := functionArguments(resp.Choices[0], _taggingFunctionName)
+		tags, err := c.parse(arguments)
+		if err == nil {
+			return tags, nil
+		}
+		if attempt >= c.MaxRetries || ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTags, err)
+		}
+		messages = []llms.MessageContent{messages[0], llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(
+			"The arguments of the tag function call %s are invalid: %s\nCall the function again with corrected arguments.",
+			strings.Join(arguments, "\n"), err,
+		))}
+	}
+}
+
+// TagDocuments tags the contents of the documents, calling the chain with
+// up to maxWorkers documents concurrently. The tags are returned in the
+// order of the documents.
+func (c Tagging) TagDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	maxWorkers int,
+	options ...ChainCallOption,
+) ([][]Tag, error) {
+	inputs := make([]map[string]any, len(docs))
+	for i, doc := range docs {
+		inputs[i] = map[string]any{c.InputKey: doc.PageContent}
+	}
+	results, err := Apply(ctx, c, inputs, maxWorkers, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([][]Tag, len(results))
+	for i, result := range results {
+		tags[i], _ = result[c.OutputKey].([]Tag)
+	}
+	return tags, nil
+}
+
+// tool returns the definition of the tag function.
+func (c Tagging) tool() llms.Tool {
+	names := make([]string, len(c.Labels))
+	for i, l := range c.Labels {
+		names[i] = l.Name
+	}
+	tag := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"label":      {Type: jsonschema.String, Enum: names},
+			"confidence": {Type: jsonschema.Number, Description: "The confidence in the label, between 0 and 1."},
+		},
+		Required: []string{"label", "confidence"},
+	}
+	description := "The label of the text, as a single tag."
+	if c.MultiLabel {
+		description = "The labels of the text."
+	}
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        _taggingFunctionName,
+			Description: "Tags a text with labels of a taxonomy.",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"tags": {Type: jsonschema.Array, Items: &tag, Description: description},
+				},
+				Required: []string{"tags"},
+			},
+		},
+	}
+}
+
+// parse decodes and validates the tags of the arguments of the tag function
+// calls. Labels are matched ignoring case, and duplicate labels are merged.
+func (c Tagging) parse(arguments []string) ([]Tag, error) {
+	if len(arguments) == 0 {
+		return nil, errors.New("no tag function call")
+	}
+
+	confidences := map[string]float64{}
+	for _, argument := range arguments {
+		var call struct {
+			Tags []Tag `json:"tags"`
+		}
+		if err := json.Unmarshal([]byte(argument), &call); err != nil {
+			return nil, err
+		}
+		for _, tag := range call.Tags {
+			label, ok := c.label(tag.Label)
+			if !ok {
+				return nil, fmt.Errorf("unknown label %q", tag.Label)
+			}
+			if tag.Confidence < 0 || tag.Confidence > 1 {
+				return nil, fmt.Errorf("confidence %v of label %q not between 0 and 1", tag.Confidence, label)
+			}
+			if confidence, ok := confidences[label]; !ok || tag.Confidence > confidence {
+				confidences[label] = tag.Confidence
+			}
+		}
+	}
+	if !c.MultiLabel && len(confidences) != 1 {
+		return nil, fmt.Errorf("%d labels given instead of a single one", len(confidences))
+	}
+
+	tags := make([]Tag, 0, len(confidences))
+	for label, confidence := range confidences {
+		if confidence >= c.MinConfidence {
+			tags = append(tags, Tag{Label: label, Confidence: confidence})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Confidence != tags[j].Confidence {
+			return tags[i].Confidence > tags[j].Confidence
+		}
+		return tags[i].Label < tags[j].Label
+	})
+	return tags, nil
+}
+
+// label returns the name of the label of the taxonomy matching the name.
+func (c Tagging) label(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, l := range c.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return l.Name, true
+		}
+	}
+	return "", false
+}
+
+func (c Tagging) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Memory
+}
+
+func (c Tagging) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+func (c Tagging) GetOutputKeys() []string {
+	return []string{c.OutputKey}
+}
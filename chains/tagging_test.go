@@ -0,0 +1,73 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func tagCall(arguments string) fake.Response {
+	return fake.Response{ToolCalls: []llms.ToolCall{{
+		ID:           "call",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "tag", Arguments: arguments},
+	}}}
+}
+
+func testTaggingLabels() []Label {
+	return []Label{
+		{Name: "billing", Description: "Questions about invoices and payments"},
+		{Name: "bug", Description: "Reports of defects"},
+		{Name: "feature"},
+	}
+}
+
+func TestTagging(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.New(
+		tagCall(`{"tags": [{"label": "refund", "confidence": 0.9}]}`),
+		tagCall(`{"tags": [{"label": "Billing", "confidence": 0.9}]}`),
+		tagCall(`{"tags": [{"label": "bug", "confidence": 0.6}, {"label": "feature", "confidence": 0.4}]}`),
+	)
+	c := NewTagging(llm, testTaggingLabels(), false)
+	c.MaxRetries = 1
+	result, err := Call(context.Background(), c, map[string]any{"input": "I was charged twice."})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{Label: "billing", Confidence: 0.9}}, result["tags"])
+
+	calls := llm.Calls()
+	require.Len(t, calls, 2)
+	require.Contains(t, calls[0].Messages[0].Parts[0].(llms.TextContent).Text,
+		"billing: Questions about invoices and payments\nbug: Reports of defects\nfeature\n")
+	require.Contains(t, calls[1].Messages[1].Parts[0].(llms.TextContent).Text, `unknown label "refund"`)
+
+	llm.AddResponses(tagCall(`{"tags": []}`))
+	_, err = c.Tag(context.Background(), "The app crashes, please add a save button.")
+	require.ErrorIs(t, err, ErrInvalidTags)
+	require.Contains(t, llm.LastCall().Messages[1].Parts[0].(llms.TextContent).Text, "2 labels given")
+	require.ErrorContains(t, err, "0 labels given instead of a single one")
+}
+
+func TestTaggingMultiLabel(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewRepeating(
+		tagCall(`{"tags": [{"label": "feature", "confidence": 0.3}, {"label": "bug", "confidence": 0.8},
+			{"label": "BUG", "confidence": 0.9}, {"label": "billing", "confidence": 0.1}]}`),
+	)
+	c := NewTagging(llm, testTaggingLabels(), true)
+	c.MinConfidence = 0.2
+
+	tags, err := c.TagDocuments(context.Background(), []schema.Document{
+		{PageContent: "The app crashes."},
+		{PageContent: "The app crashes again."},
+	}, 2)
+	require.NoError(t, err)
+	expected := []Tag{{Label: "bug", Confidence: 0.9}, {Label: "feature", Confidence: 0.3}}
+	require.Equal(t, [][]Tag{expected, expected}, tags)
+}
@@ -0,0 +1,172 @@
+package chains
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ContextBudget returns the number of tokens of the context window of the
+// model left for the prompt when reservedTokens are kept for the generated
+// text, e.g. to set the MaxTokens of a StuffDocuments chain.
+func ContextBudget(llm llms.Model, reservedTokens int) int {
+	return llms.MaxContextTokens(llm) - reservedTokens
+}
+
+// tokenMeasure measures texts with a token counter, or with the token
+// counting of the model if the counter is nil.
+type tokenMeasure struct {
+	counter llms.TokenCounter
+	model   llms.Model
+}
+
+func (m tokenMeasure) count(ctx context.Context, text string) (int, error) {
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, text)}
+	if m.counter != nil {
+		return m.counter.CountTokens(ctx, messages)
+	}
+	return llms.CountMessageTokens(ctx, m.model, messages)
+}
+
+// promptTokens returns the number of tokens of the prompt of the llm chain
+// formatted with the values and no documents.
+func (m tokenMeasure) promptTokens(ctx context.Context, llmChain *LLMChain, values map[string]any, documentVariableName string) (int, error) { //nolint:lll
+	promptValues := make(map[string]any, len(values)+1)
+	for key, value := range values {
+		promptValues[key] = value
+	}
+	promptValues[documentVariableName] = ""
+	prompt, err := llmChain.Prompt.FormatPrompt(promptValues)
+	if err != nil {
+		return 0, err
+	}
+	return m.count(ctx, prompt.String())
+}
+
+// truncate returns the longest prefix of the text of at most maxTokens
+// tokens.
+func (m tokenMeasure) truncate(ctx context.Context, text string, maxTokens int) (string, error) {
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2 //nolint:mnd
+		n, err := m.count(ctx, string(runes[:mid]))
+		if err != nil {
+			return "", err
+		}
+		if n <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]), nil
+}
+
+// fitDocuments returns the documents, in order, fitting in the budget when
+// joined with the separator, the first document not fitting being truncated
+// to the tokens left, and whether documents were dropped or truncated.
+func (m tokenMeasure) fitDocuments(ctx context.Context, docs []schema.Document, budget int, separator string) ([]schema.Document, bool, error) { //nolint:lll
+	separatorTokens, err := m.count(ctx, separator)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fitted := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		if i > 0 {
+			budget -= separatorTokens
+		}
+		n, err := m.count(ctx, doc.PageContent)
+		if err != nil {
+			return nil, false, err
+		}
+		if n <= budget {
+			fitted = append(fitted, doc)
+			budget -= n
+			continue
+		}
+		if budget > 0 {
+			text, err := m.truncate(ctx, doc.PageContent, budget)
+			if err != nil {
+				return nil, false, err
+			}
+			if strings.TrimSpace(text) != "" {
+				fitted = append(fitted, schema.Document{PageContent: text, Metadata: doc.Metadata, Score: doc.Score})
+			}
+		}
+		return fitted, true, nil
+	}
+	return fitted, false, nil
+}
+
+// batchDocuments groups the documents, in order, into batches fitting in the
+// budget when joined with the separator. Documents larger than the budget are
+// split into several batches. The metadata of a batch is the metadata of its
+// first document.
+func (m tokenMeasure) batchDocuments(ctx context.Context, docs []schema.Document, budget int, separator string) ([]schema.Document, error) { //nolint:lll,cyclop
+	separatorTokens, err := m.count(ctx, separator)
+	if err != nil {
+		return nil, err
+	}
+	budget = max(budget, 1)
+
+	var batches []schema.Document
+	var current []string
+	var currentMetadata map[string]any
+	left := budget
+	flush := func() {
+		if len(current) > 0 {
+			batches = append(batches, schema.Document{
+				PageContent: strings.Join(current, separator),
+				Metadata:    currentMetadata,
+			})
+		}
+		current, currentMetadata, left = nil, nil, budget
+	}
+
+	for _, doc := range docs {
+		text := doc.PageContent
+		for {
+			n, err := m.count(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			needed := n
+			if len(current) > 0 {
+				needed += separatorTokens
+			}
+			if needed <= left {
+				if len(current) == 0 {
+					currentMetadata = doc.Metadata
+				}
+				current = append(current, text)
+				left -= needed
+				break
+			}
+			if len(current) > 0 {
+				flush()
+				continue
+			}
+			// The document alone is larger than the budget: split it.
+			head, err := m.truncate(ctx, text, budget)
+			if err != nil {
+				return nil, err
+			}
+			if head == "" {
+				head = string([]rune(text)[:1])
+			}
+			currentMetadata = doc.Metadata
+			current = append(current, head)
+			flush()
+			text = strings.TrimPrefix(text, head)
+			if text == "" {
+				break
+			}
+		}
+	}
+	flush()
+	return batches, nil
+}
@@ -121,6 +121,15 @@ func (a *AssemblyAIAudioTranscriptLoader) Load(ctx context.Context) ([]schema.Do
 		return nil, err
 	}
 
+	if a.url != "" {
+		for i := range docs {
+			if docs[i].Metadata == nil {
+				docs[i].Metadata = map[string]any{}
+			}
+			docs[i].Metadata[schema.MetadataSource] = a.url
+		}
+	}
+
 	return docs, nil
 }
 
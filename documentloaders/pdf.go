@@ -92,8 +92,8 @@ func (p PDF) Load(_ context.Context) ([]schema.Document, error) {
 		docs = append(docs, schema.Document{
 			PageContent: text,
 			Metadata: map[string]any{
-				"page":        i,
-				"total_pages": numPages,
+				schema.MetadataPage: i,
+				"total_pages":       numPages,
 			},
 		})
 	}
@@ -110,10 +110,18 @@ func TestPDFTextSplit(t *testing.T) {
 		content  string
 		metadata map[string]any
 	}{
-		{content: page1_1Content, metadata: map[string]any{"page": 1, "total_pages": 2}},
-		{content: page1_2Content, metadata: map[string]any{"page": 1, "total_pages": 2}},
-		{content: page2_1Content, metadata: map[string]any{"page": 2, "total_pages": 2}},
-		{content: page2_2Content, metadata: map[string]any{"page": 2, "total_pages": 2}},
+		{content: page1_1Content, metadata: map[string]any{
+			"page": 1, "total_pages": 2, "start_index": 1, "end_index": 299,
+		}},
+		{content: page1_2Content, metadata: map[string]any{
+			"page": 1, "total_pages": 2, "start_index": 270, "end_index": 569,
+		}},
+		{content: page2_1Content, metadata: map[string]any{
+			"page": 2, "total_pages": 2, "start_index": 1, "end_index": 297,
+		}},
+		{content: page2_2Content, metadata: map[string]any{
+			"page": 2, "total_pages": 2, "start_index": 268, "end_index": 366,
+		}},
 	}
 
 	t.Run("PDFTextSplit", func(t *testing.T) {
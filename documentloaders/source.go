@@ -0,0 +1,54 @@
+package documentloaders
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// SourceLoader is a loader setting the source of the documents of another
+// loader, such as a Text loader of a file, in their metadata under the
+// schema.MetadataSource key.
+type SourceLoader struct {
+	loader Loader
+	source string
+}
+
+var _ Loader = SourceLoader{}
+
+// NewSourceLoader creates a new loader setting the source, e.g. the URI or
+// path of the data read by the loader, of its documents.
+func NewSourceLoader(loader Loader, source string) SourceLoader {
+	return SourceLoader{
+		loader: loader,
+		source: source,
+	}
+}
+
+// Load loads the documents of the loader and sets their source.
+func (l SourceLoader) Load(ctx context.Context) ([]schema.Document, error) {
+	docs, err := l.loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range docs {
+		if docs[i].Metadata == nil {
+			docs[i].Metadata = map[string]any{}
+		}
+		docs[i].Metadata[schema.MetadataSource] = l.source
+	}
+	return docs, nil
+}
+
+// LoadAndSplit loads the documents of the loader, sets their source and
+// splits them using a text splitter.
+func (l SourceLoader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
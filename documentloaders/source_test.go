@@ -0,0 +1,29 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+func TestSourceLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/test.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	loader := NewSourceLoader(NewText(file), "testdata/test.txt")
+	split := textsplitter.NewRecursiveCharacter(textsplitter.WithChunkSize(8), textsplitter.WithChunkOverlap(0))
+	docs, err := loader.LoadAndSplit(context.Background(), split)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Foo Bar", docs[0].PageContent)
+	assert.Equal(t, schema.Provenance{Source: "testdata/test.txt", StartIndex: 0, EndIndex: 7}, docs[0].Provenance())
+	assert.Equal(t, schema.Provenance{Source: "testdata/test.txt", StartIndex: 8, EndIndex: 11}, docs[1].Provenance())
+}
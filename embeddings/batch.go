@@ -0,0 +1,186 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrVectorCountMismatch is returned when the embedder client returns a
+// different number of vectors than the texts it was given.
+var ErrVectorCountMismatch = errors.New("number of vectors doesn't match the number of texts")
+
+// TokenCountFunc returns the number of tokens of a text.
+type TokenCountFunc func(text string) int
+
+// ProgressFunc is called with the number of texts embedded so far and the
+// total number of texts.
+type ProgressFunc func(done, total int)
+
+// ApproximateTokenCount approximates the number of tokens of a text as one
+// token per four characters.
+func ApproximateTokenCount(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4 //nolint:mnd
+}
+
+// BatchTextsByTokens splits texts into batches of at most batchSize texts
+// and at most maxTokens tokens according to count. A text longer than
+// maxTokens is put in a batch of its own.
+func BatchTextsByTokens(texts []string, batchSize, maxTokens int, count TokenCountFunc) [][]string {
+	var batches [][]string
+	var batch []string
+	tokens := 0
+	for _, text := range texts {
+		n := count(text)
+		if len(batch) > 0 && (len(batch) >= batchSize || (maxTokens > 0 && tokens+n > maxTokens)) {
+			batches = append(batches, batch)
+			batch, tokens = nil, 0
+		}
+		batch = append(batch, text)
+		tokens += n
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// embedBatches embeds texts in batches with a pool of workers, retrying
+// failed batches and reporting progress.
+func (ei *EmbedderImpl) embedBatches(ctx context.Context, texts []string) ([][]float32, error) {
+	counter := ei.TokenCounter
+	if counter == nil {
+		counter = ApproximateTokenCount
+	}
+	batches := BatchTextsByTokens(texts, ei.BatchSize, ei.MaxTokensPerBatch, counter)
+	offsets := make([]int, len(batches))
+	for i := 1; i < len(batches); i++ {
+		offsets[i] = offsets[i-1] + len(batches[i-1])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	emb := make([][]float32, len(texts))
+	limiter := newRateLimiter(ei.RequestsPerSecond)
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	for range max(1, min(ei.Concurrency, len(batches))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				vectors, err := ei.embedBatch(ctx, limiter, batches[i])
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				copy(emb[offsets[i]:], vectors)
+				done += len(batches[i])
+				if ei.Progress != nil {
+					ei.Progress(done, len(texts))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+loop:
+	for i := range batches {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return emb, nil
+}
+
+func (ei *EmbedderImpl) embedBatch(ctx context.Context, limiter *rateLimiter, batch []string) ([][]float32, error) {
+	backoff := ei.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		vectors, err := ei.client.CreateEmbedding(ctx, batch)
+		if err == nil && len(vectors) != len(batch) {
+			return nil, fmt.Errorf("%w: got %d vectors for %d texts", ErrVectorCountMismatch, len(vectors), len(batch))
+		}
+		if err == nil || attempt >= ei.MaxRetries || ctx.Err() != nil {
+			return vectors, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// rateLimiter spaces requests evenly to stay under a number of requests per
+// second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
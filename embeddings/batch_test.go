@@ -0,0 +1,73 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTextsByTokens(t *testing.T) {
+	t.Parallel()
+	count := func(text string) int { return len(text) }
+	assert.Equal(t,
+		[][]string{{"aa", "b"}, {"cccc"}, {"d", "e"}, {"f"}},
+		BatchTextsByTokens([]string{"aa", "b", "cccc", "d", "e", "f"}, 2, 3, count))
+	assert.Nil(t, BatchTextsByTokens(nil, 2, 3, count))
+}
+
+func TestEmbedderConcurrentRetries(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	failures := map[string]int{"c": 1}
+	client := EmbedderClientFunc(func(_ context.Context, texts []string) ([][]float32, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures[texts[0]] > 0 {
+			failures[texts[0]]--
+			return nil, errors.New("transient")
+		}
+		vectors := make([][]float32, len(texts))
+		for i, text := range texts {
+			vectors[i] = []float32{float32(text[0])}
+		}
+		return vectors, nil
+	})
+
+	var progress []int
+	e, err := NewEmbedder(client,
+		WithBatchSize(2),
+		WithConcurrency(3),
+		WithRetries(1, 0),
+		WithProgress(func(done, total int) {
+			assert.Equal(t, 5, total)
+			progress = append(progress, done)
+		}),
+	)
+	require.NoError(t, err)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"a", "b", "c", "d", "e"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{'a'}, {'b'}, {'c'}, {'d'}, {'e'}}, vectors)
+	require.Len(t, progress, 3)
+	assert.Equal(t, 5, progress[2])
+
+	failures["a"] = 2
+	_, err = e.EmbedDocuments(context.Background(), []string{"a", "b", "c"})
+	require.EqualError(t, err, "transient")
+}
+
+func TestEmbedderVectorCountMismatch(t *testing.T) {
+	t.Parallel()
+	client := EmbedderClientFunc(func(_ context.Context, texts []string) ([][]float32, error) {
+		return make([][]float32, len(texts)-1), nil
+	})
+	e, err := NewEmbedder(client, WithBatchSize(2))
+	require.NoError(t, err)
+
+	_, err = e.EmbedDocuments(context.Background(), []string{"a", "b", "c"})
+	require.ErrorIs(t, err, ErrVectorCountMismatch)
+}
@@ -0,0 +1,222 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ErrInvalidVector is returned when decoding a stored vector of invalid
+// length.
+var ErrInvalidVector = errors.New("invalid encoded vector")
+
+// ErrUnknownCacheModel is returned by NewCachedEmbedder when the model of the
+// cache keys is neither set nor known to the wrapped embedder.
+var ErrUnknownCacheModel = errors.New("unknown model of cached embeddings, set it with WithCacheModel")
+
+// CacheStore stores embedding vectors by key for a CachedEmbedder.
+type CacheStore interface {
+	// MGet returns the vectors stored for keys, with nil for missing ones.
+	MGet(ctx context.Context, keys []string) ([][]float32, error)
+	// MSet stores the vectors for keys. It returns an error wrapping
+	// ErrVectorCountMismatch if there isn't one vector per key.
+	MSet(ctx context.Context, keys []string, vectors [][]float32) error
+}
+
+// CachedEmbedder is an Embedder wrapper storing the embeddings it creates
+// in a CacheStore, keyed by model and content hash, so that texts are only
+// embedded once.
+type CachedEmbedder struct {
+	embedder Embedder
+	store    CacheStore
+
+	// Model is part of every cache key, so that embeddings of different
+	// models sharing a store don't mix. It defaults to the model of the
+	// wrapped embedder.
+	Model string
+	// CacheQueries controls whether EmbedQuery results are cached too.
+	CacheQueries bool
+}
+
+var _ Embedder = &CachedEmbedder{}
+
+// CacheOption is a function that configures a CachedEmbedder.
+type CacheOption func(*CachedEmbedder)
+
+// WithCacheModel sets the model name used in the cache keys. It must be set
+// if the wrapped embedder doesn't implement ModelDescriber.
+func WithCacheModel(model string) CacheOption {
+	return func(c *CachedEmbedder) {
+		c.Model = model
+	}
+}
+
+// WithCacheQueries sets whether EmbedQuery results are cached. Defaults to
+// true.
+func WithCacheQueries(cacheQueries bool) CacheOption {
+	return func(c *CachedEmbedder) {
+		c.CacheQueries = cacheQueries
+	}
+}
+
+// NewCachedEmbedder returns an Embedder caching the embeddings of embedder
+// in store. It returns ErrUnknownCacheModel if no model was set with
+// WithCacheModel and the embedder doesn't report one.
+func NewCachedEmbedder(embedder Embedder, store CacheStore, opts ...CacheOption) (*CachedEmbedder, error) {
+	c := &CachedEmbedder{
+		embedder:     embedder,
+		store:        store,
+		Model:        Model(embedder),
+		CacheQueries: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Model == "" {
+		return nil, ErrUnknownCacheModel
+	}
+	return c, nil
+}
+
+// EmbedDocuments returns the cached vectors of the texts, embedding and
+// caching the missing ones.
+func (c *CachedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = c.key("document", text)
+	}
+	vectors, err := c.store.MGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// embed each missing text once, even if it appears several times.
+	missing := make(map[string][]int)
+	var missingKeys []string
+	var missingTexts []string
+	for i, vector := range vectors {
+		if vector != nil {
+			continue
+		}
+		if _, ok := missing[keys[i]]; !ok {
+			missingKeys = append(missingKeys, keys[i])
+			missingTexts = append(missingTexts, texts[i])
+		}
+		missing[keys[i]] = append(missing[keys[i]], i)
+	}
+	if len(missingTexts) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := c.embedder.EmbedDocuments(ctx, missingTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missingTexts) {
+		return nil, fmt.Errorf("%w: got %d vectors for %d texts",
+			ErrVectorCountMismatch, len(embedded), len(missingTexts))
+	}
+	if err := c.store.MSet(ctx, missingKeys, embedded); err != nil {
+		return nil, err
+	}
+	for i, key := range missingKeys {
+		for _, j := range missing[key] {
+			vectors[j] = embedded[i]
+		}
+	}
+	return vectors, nil
+}
+
+// EmbedQuery returns the cached vector of the query text, embedding and
+// caching it if missing.
+func (c *CachedEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if !c.CacheQueries {
+		return c.embedder.EmbedQuery(ctx, text)
+	}
+
+	key := c.key("query", text)
+	vectors, err := c.store.MGet(ctx, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	if vectors[0] != nil {
+		return vectors[0], nil
+	}
+
+	vector, err := c.embedder.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return vector, c.store.MSet(ctx, []string{key}, [][]float32{vector})
+}
+
+// key returns the cache key of a text. Documents and queries have different
+// keys because some models embed them differently.
+func (c *CachedEmbedder) key(kind, text string) string {
+	hash := sha256.Sum256([]byte(kind + "\x00" + text))
+	return c.Model + ":" + hex.EncodeToString(hash[:])
+}
+
+// InMemoryCacheStore is a CacheStore keeping the vectors in memory.
+type InMemoryCacheStore struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+var _ CacheStore = &InMemoryCacheStore{}
+
+// NewInMemoryCacheStore returns an empty in-memory CacheStore.
+func NewInMemoryCacheStore() *InMemoryCacheStore {
+	return &InMemoryCacheStore{vectors: make(map[string][]float32)}
+}
+
+// MGet returns the vectors stored for keys, with nil for missing ones.
+func (s *InMemoryCacheStore) MGet(_ context.Context, keys []string) ([][]float32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vectors := make([][]float32, len(keys))
+	for i, key := range keys {
+		vectors[i] = s.vectors[key]
+	}
+	return vectors, nil
+}
+
+// MSet stores the vectors for keys.
+func (s *InMemoryCacheStore) MSet(_ context.Context, keys []string, vectors [][]float32) error {
+	if len(vectors) != len(keys) {
+		return fmt.Errorf("%w: got %d vectors for %d keys", ErrVectorCountMismatch, len(vectors), len(keys))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, key := range keys {
+		s.vectors[key] = vectors[i]
+	}
+	return nil
+}
+
+// EncodeVector encodes a vector as little-endian float32 values, the format
+// used by the persistent cache stores.
+func EncodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// DecodeVector decodes a vector encoded with EncodeVector.
+func DecodeVector(buf []byte) ([]float32, error) {
+	if len(buf)%4 != 0 {
+		return nil, ErrInvalidVector
+	}
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector, nil
+}
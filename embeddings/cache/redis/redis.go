@@ -0,0 +1,102 @@
+// Package redis provides a persistent embeddings.CacheStore backed by Redis.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// DefaultPrefix is the default prefix of the keys storing the vectors.
+const DefaultPrefix = "langchaingo:embedding:"
+
+// Store is an embeddings.CacheStore storing vectors in Redis strings.
+type Store struct {
+	client rueidis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+var _ embeddings.CacheStore = &Store{}
+
+// Option is a function that configures a Store.
+type Option func(*Store)
+
+// WithPrefix sets the prefix of the keys storing the vectors.
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// WithTTL sets the time-to-live of the stored vectors. By default they
+// don't expire.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// New returns a Store using client.
+func New(client rueidis.Client, opts ...Option) *Store {
+	s := &Store{client: client, prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// MGet returns the vectors stored for keys, with nil for missing ones.
+func (s *Store) MGet(ctx context.Context, keys []string) ([][]float32, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	values, err := s.client.Do(ctx, s.client.B().Mget().Key(prefixed...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(keys))
+	for i, value := range values {
+		buf, err := value.AsBytes()
+		if rueidis.IsRedisNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if vectors[i], err = embeddings.DecodeVector(buf); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// MSet stores the vectors for keys.
+func (s *Store) MSet(ctx context.Context, keys []string, vectors [][]float32) error {
+	if len(vectors) != len(keys) {
+		return fmt.Errorf("%w: got %d vectors for %d keys", embeddings.ErrVectorCountMismatch, len(vectors), len(keys))
+	}
+	cmds := make([]rueidis.Completed, len(keys))
+	for i, key := range keys {
+		value := s.client.B().Set().Key(s.prefix + key).Value(rueidis.BinaryString(embeddings.EncodeVector(vectors[i])))
+		if s.ttl > 0 {
+			cmds[i] = value.Ex(s.ttl).Build()
+		} else {
+			cmds[i] = value.Build()
+		}
+	}
+
+	var errs []error
+	for _, res := range s.client.DoMulti(ctx, cmds...) {
+		if err := res.Error(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
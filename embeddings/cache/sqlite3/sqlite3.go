@@ -0,0 +1,128 @@
+// Package sqlite3 provides a persistent embeddings.CacheStore backed by a
+// sqlite3 database.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// DefaultTableName is the default name of the table storing the vectors.
+const DefaultTableName = "langchaingo_embedding_cache"
+
+// _maxQueryKeys is the maximum number of keys looked up in one query, below
+// the default sqlite3 limit of host parameters.
+const _maxQueryKeys = 500
+
+// Store is an embeddings.CacheStore storing vectors in a sqlite3 table.
+type Store struct {
+	db        *sql.DB
+	tableName string
+}
+
+var _ embeddings.CacheStore = &Store{}
+
+// Option is a function that configures a Store.
+type Option func(*Store)
+
+// WithTableName sets the name of the table storing the vectors.
+func WithTableName(tableName string) Option {
+	return func(s *Store) {
+		s.tableName = tableName
+	}
+}
+
+// New returns a Store using db, creating its table if needed.
+func New(ctx context.Context, db *sql.DB, opts ...Option) (*Store, error) {
+	s := &Store{db: db, tableName: DefaultTableName}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		key TEXT PRIMARY KEY,
+		vector BLOB NOT NULL
+	)`, s.tableName)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+	return s, nil
+}
+
+// MGet returns the vectors stored for keys, with nil for missing ones.
+func (s *Store) MGet(ctx context.Context, keys []string) ([][]float32, error) {
+	index := make(map[string][]int, len(keys))
+	for i, key := range keys {
+		index[key] = append(index[key], i)
+	}
+
+	vectors := make([][]float32, len(keys))
+	for start := 0; start < len(keys); start += _maxQueryKeys {
+		chunk := keys[start:min(start+_maxQueryKeys, len(keys))]
+		args := make([]any, len(chunk))
+		for i, key := range chunk {
+			args[i] = key
+		}
+		query := fmt.Sprintf("SELECT key, vector FROM %s WHERE key IN (?%s)",
+			s.tableName, strings.Repeat(", ?", len(chunk)-1))
+		if err := s.scan(ctx, query, args, index, vectors); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+func (s *Store) scan(ctx context.Context, query string, args []any, index map[string][]int, vectors [][]float32) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var buf []byte
+		if err := rows.Scan(&key, &buf); err != nil {
+			return err
+		}
+		vector, err := embeddings.DecodeVector(buf)
+		if err != nil {
+			return err
+		}
+		for _, i := range index[key] {
+			vectors[i] = vector
+		}
+	}
+	return rows.Err()
+}
+
+// MSet stores the vectors for keys.
+func (s *Store) MSet(ctx context.Context, keys []string, vectors [][]float32) error {
+	if len(vectors) != len(keys) {
+		return fmt.Errorf("%w: got %d vectors for %d keys", embeddings.ErrVectorCountMismatch, len(vectors), len(keys))
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stmt, err := tx.PrepareContext(ctx,
+		fmt.Sprintf("INSERT OR REPLACE INTO %s (key, vector) VALUES (?, ?)", s.tableName))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, key := range keys {
+		if _, err := stmt.ExecContext(ctx, key, embeddings.EncodeVector(vectors[i])); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,29 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	s, err := New(ctx, db)
+	require.NoError(t, err)
+
+	require.NoError(t, s.MSet(ctx, []string{"a", "b"}, [][]float32{{1, 2}, {3}}))
+	vectors, err := s.MGet(ctx, []string{"b", "missing", "a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3}, nil, {1, 2}, {3}}, vectors)
+
+	require.ErrorIs(t, s.MSet(ctx, []string{"c", "d"}, [][]float32{{4}}), embeddings.ErrVectorCountMismatch)
+}
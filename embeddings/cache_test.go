@@ -0,0 +1,119 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingEmbedder struct {
+	documents []string
+	queries   int
+}
+
+func (e *countingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.documents = append(e.documents, texts...)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	e.queries++
+	return []float32{-float32(len(text))}, nil
+}
+
+func TestCachedEmbedder(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := &countingEmbedder{}
+	store := NewInMemoryCacheStore()
+	e, err := NewCachedEmbedder(base, store, WithCacheModel("model"))
+	require.NoError(t, err)
+
+	vectors, err := e.EmbedDocuments(ctx, []string{"a", "bb", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}, {2}, {1}}, vectors)
+	assert.Equal(t, []string{"a", "bb"}, base.documents)
+
+	vectors, err = e.EmbedDocuments(ctx, []string{"bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{2}, {3}}, vectors)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, base.documents)
+
+	for range 2 {
+		vector, err := e.EmbedQuery(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, []float32{-1}, vector)
+	}
+	assert.Equal(t, 1, base.queries)
+
+	// another model doesn't share the cached vectors.
+	other, err := NewCachedEmbedder(base, store, WithCacheModel("other"))
+	require.NoError(t, err)
+	_, err = other.EmbedDocuments(ctx, []string{"a"})
+	require.NoError(t, err)
+	assert.Len(t, base.documents, 4)
+}
+
+type shortEmbedder struct {
+	countingEmbedder
+}
+
+func (e *shortEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := e.countingEmbedder.EmbedDocuments(ctx, texts)
+	return vectors[1:], err
+}
+
+func TestCachedEmbedderVectorCountMismatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := NewInMemoryCacheStore()
+	e, err := NewCachedEmbedder(&shortEmbedder{}, store, WithCacheModel("model"))
+	require.NoError(t, err)
+
+	_, err = e.EmbedDocuments(ctx, []string{"a", "bb"})
+	require.ErrorIs(t, err, ErrVectorCountMismatch)
+	assert.Empty(t, store.vectors)
+
+	require.ErrorIs(t, store.MSet(ctx, []string{"a", "b"}, [][]float32{{1}}), ErrVectorCountMismatch)
+}
+
+type describedEmbedder struct {
+	countingEmbedder
+}
+
+func (e *describedEmbedder) EmbeddingModel() string { return "described" }
+
+func (e *describedEmbedder) EmbeddingDimensions() int { return 1 }
+
+func TestCachedEmbedderModel(t *testing.T) {
+	t.Parallel()
+	store := NewInMemoryCacheStore()
+
+	_, err := NewCachedEmbedder(&countingEmbedder{}, store)
+	require.ErrorIs(t, err, ErrUnknownCacheModel)
+
+	e, err := NewCachedEmbedder(&describedEmbedder{}, store)
+	require.NoError(t, err)
+	assert.Equal(t, "described", e.Model)
+
+	e, err = NewCachedEmbedder(&describedEmbedder{}, store, WithCacheModel("override"))
+	require.NoError(t, err)
+	assert.Equal(t, "override", e.Model)
+}
+
+func TestEncodeVector(t *testing.T) {
+	t.Parallel()
+	vector := []float32{1.5, -2, 0}
+	decoded, err := DecodeVector(EncodeVector(vector))
+	require.NoError(t, err)
+	assert.Equal(t, vector, decoded)
+
+	_, err = DecodeVector([]byte{1, 2, 3})
+	require.ErrorIs(t, err, ErrInvalidVector)
+}
@@ -0,0 +1,264 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// EmbeddingType is the type of the values of an embedding.
+type EmbeddingType string
+
+const (
+	EmbeddingTypeFloat   EmbeddingType = "float"
+	EmbeddingTypeInt8    EmbeddingType = "int8"
+	EmbeddingTypeUint8   EmbeddingType = "uint8"
+	EmbeddingTypeBinary  EmbeddingType = "binary"
+	EmbeddingTypeUbinary EmbeddingType = "ubinary"
+)
+
+// Input types of the v3 embedding models.
+const (
+	InputTypeSearchDocument = "search_document"
+	InputTypeSearchQuery    = "search_query"
+	InputTypeClassification = "classification"
+	InputTypeClustering     = "clustering"
+	InputTypeImage          = "image"
+)
+
+var (
+	_ embeddings.Embedder       = &Cohere{}
+	_ embeddings.ImageEmbedder  = &Cohere{}
+	_ embeddings.ModelDescriber = &Cohere{}
+)
+
+// _modelDimensions are the embedding dimensions of the known models.
+var _modelDimensions = map[string]int{ // nolint:gochecknoglobals
+	"embed-english-v3.0":            1024,
+	"embed-multilingual-v3.0":       1024,
+	"embed-english-light-v3.0":      384,
+	"embed-multilingual-light-v3.0": 384,
+	"embed-v4.0":                    1536,
+}
+
+// Cohere is the embedder using the Cohere v2 embed API, for v3 and later
+// embedding models.
+type Cohere struct {
+	baseURL       string
+	token         string
+	client        *http.Client
+	Model         string
+	StripNewLines bool
+	BatchSize     int
+	// InputType overrides the input type of both documents and queries.
+	InputType string
+	// EmbeddingType is the type of the embeddings returned by EmbedDocuments
+	// and EmbedQuery.
+	EmbeddingType EmbeddingType
+	Truncate      string
+}
+
+// NewCohere returns a new embedder that uses the Cohere api.
+// The default model is "embed-english-v3.0". Use `WithModel` to change the model.
+func NewCohere(opts ...Option) (*Cohere, error) {
+	return applyOptions(opts...)
+}
+
+// Embeddings are the embeddings of a request, one slice per requested type.
+// Binary embeddings are bit-packed, eight dimensions per value.
+type Embeddings struct {
+	Float   [][]float32 `json:"float,omitempty"`
+	Int8    [][]int8    `json:"int8,omitempty"`
+	Uint8   [][]uint8   `json:"uint8,omitempty"`
+	Binary  [][]int8    `json:"binary,omitempty"`
+	Ubinary [][]uint8   `json:"ubinary,omitempty"`
+}
+
+// Float32 returns the embeddings of the given type as float32 vectors.
+func (e *Embeddings) Float32(embeddingType EmbeddingType) [][]float32 {
+	switch embeddingType {
+	case EmbeddingTypeInt8:
+		return toFloat32(e.Int8)
+	case EmbeddingTypeUint8:
+		return toFloat32(e.Uint8)
+	case EmbeddingTypeBinary:
+		return toFloat32(e.Binary)
+	case EmbeddingTypeUbinary:
+		return toFloat32(e.Ubinary)
+	case EmbeddingTypeFloat:
+	}
+	return e.Float
+}
+
+func toFloat32[T int8 | uint8](vectors [][]T) [][]float32 {
+	result := make([][]float32, len(vectors))
+	for i, vector := range vectors {
+		result[i] = make([]float32, len(vector))
+		for j, v := range vector {
+			result[i][j] = float32(v)
+		}
+	}
+	return result
+}
+
+func (e *Embeddings) append(other Embeddings) {
+	e.Float = append(e.Float, other.Float...)
+	e.Int8 = append(e.Int8, other.Int8...)
+	e.Uint8 = append(e.Uint8, other.Uint8...)
+	e.Binary = append(e.Binary, other.Binary...)
+	e.Ubinary = append(e.Ubinary, other.Ubinary...)
+}
+
+type embedRequest struct {
+	Model          string          `json:"model"`
+	Texts          []string        `json:"texts,omitempty"`
+	Images         []string        `json:"images,omitempty"`
+	InputType      string          `json:"input_type"`
+	EmbeddingTypes []EmbeddingType `json:"embedding_types"`
+	Truncate       string          `json:"truncate,omitempty"`
+}
+
+type embedResponse struct {
+	Embeddings Embeddings `json:"embeddings"`
+}
+
+// EmbedDocuments implements the `embeddings.Embedder` and creates an embedding for each of the texts.
+func (c *Cohere) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	texts = embeddings.MaybeRemoveNewLines(texts, c.StripNewLines)
+	emb, err := c.Embed(ctx, texts, c.inputType(InputTypeSearchDocument), c.EmbeddingType)
+	if err != nil {
+		return nil, err
+	}
+	return emb.Float32(c.EmbeddingType), nil
+}
+
+// EmbedQuery implements the `embeddings.Embedder` and creates an embedding for the query text.
+func (c *Cohere) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if c.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+	emb, err := c.Embed(ctx, []string{text}, c.inputType(InputTypeSearchQuery), c.EmbeddingType)
+	if err != nil {
+		return nil, err
+	}
+	vectors := emb.Float32(c.EmbeddingType)
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("embed query: no %s embedding returned", c.EmbeddingType)
+	}
+	return vectors[0], nil
+}
+
+// EmbeddingModel implements `embeddings.ModelDescriber`.
+func (c *Cohere) EmbeddingModel() string {
+	return c.Model
+}
+
+// EmbeddingDimensions implements `embeddings.ModelDescriber` for known
+// models. Binary embeddings pack eight dimensions per value.
+func (c *Cohere) EmbeddingDimensions() int {
+	dims := _modelDimensions[c.Model]
+	if c.EmbeddingType == EmbeddingTypeBinary || c.EmbeddingType == EmbeddingTypeUbinary {
+		return dims / 8 //nolint:mnd
+	}
+	return dims
+}
+
+// Embed creates embeddings of the given types for texts, batching them as
+// needed. It gives access to the int8 and binary embeddings without
+// converting them to float32.
+func (c *Cohere) Embed(ctx context.Context, texts []string, inputType string, types ...EmbeddingType) (*Embeddings, error) { //nolint:lll
+	if len(types) == 0 {
+		types = []EmbeddingType{EmbeddingTypeFloat}
+	}
+	var result Embeddings
+	for _, batch := range embeddings.BatchTexts(texts, c.BatchSize) {
+		emb, err := c.embed(ctx, embedRequest{
+			Model:          c.Model,
+			Texts:          batch,
+			InputType:      inputType,
+			EmbeddingTypes: types,
+			Truncate:       c.Truncate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.append(emb)
+	}
+	return &result, nil
+}
+
+// EmbedImages implements the `embeddings.ImageEmbedder` with a multimodal
+// model such as "embed-v4.0" or the v3 models. The API accepts one image per
+// request, so the images are embedded one at a time.
+func (c *Cohere) EmbedImages(ctx context.Context, images []embeddings.Image) ([][]float32, error) {
+	result := make([][]float32, 0, len(images))
+	for _, image := range images {
+		emb, err := c.embed(ctx, embedRequest{
+			Model:          c.Model,
+			Images:         []string{image.DataURL()},
+			InputType:      InputTypeImage,
+			EmbeddingTypes: []EmbeddingType{c.EmbeddingType},
+		})
+		if err != nil {
+			return nil, err
+		}
+		vectors := emb.Float32(c.EmbeddingType)
+		if len(vectors) == 0 {
+			return nil, fmt.Errorf("embed image: no %s embedding returned", c.EmbeddingType)
+		}
+		result = append(result, vectors[0])
+	}
+	return result, nil
+}
+
+func (c *Cohere) inputType(defaultInputType string) string {
+	if c.InputType != "" {
+		return c.InputType
+	}
+	return defaultInputType
+}
+
+func (c *Cohere) embed(ctx context.Context, payload embedRequest) (Embeddings, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Embeddings{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embed", bytes.NewReader(body))
+	if err != nil {
+		return Embeddings{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := llms.DoRequest(c.client, req)
+	if err != nil {
+		return Embeddings{}, fmt.Errorf("embed request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Embeddings{}, decodeError(resp)
+	}
+
+	var embedResp embedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return Embeddings{}, err
+	}
+	return embedResp.Embeddings, nil
+}
+
+func decodeError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var errResp struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &errResp)
+	return llms.NewLLMError(resp.StatusCode, "", errResp.Message, body)
+}
@@ -0,0 +1,118 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCohereEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	if cohereKey := os.Getenv("COHERE_API_KEY"); cohereKey == "" {
+		t.Skip("COHERE_API_KEY not set")
+	}
+	e, err := NewCohere()
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "Hello world!")
+	require.NoError(t, err)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"Hello world", "The world is ending", "good bye"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 3)
+}
+
+func TestCohereInt8Batches(t *testing.T) {
+	t.Parallel()
+	var requests []embedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embed", r.URL.Path)
+		var req embedRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		int8s := make([][]int8, len(req.Texts))
+		for i := range req.Texts {
+			int8s[i] = []int8{int8(i), -1}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"embeddings": map[string]any{"int8": int8s},
+		}))
+	}))
+	defer server.Close()
+
+	e, err := NewCohere(WithToken("token"), WithBaseURL(server.URL), WithBatchSize(2),
+		WithEmbeddingType(EmbeddingTypeInt8))
+	require.NoError(t, err)
+
+	embeddings, err := e.EmbedDocuments(context.Background(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0, -1}, {1, -1}, {0, -1}}, embeddings)
+	require.Len(t, requests, 2)
+	assert.Equal(t, InputTypeSearchDocument, requests[0].InputType)
+	assert.Equal(t, []EmbeddingType{EmbeddingTypeInt8}, requests[0].EmbeddingTypes)
+
+	_, err = e.EmbedQuery(context.Background(), "q")
+	require.NoError(t, err)
+	assert.Equal(t, InputTypeSearchQuery, requests[2].InputType)
+}
+
+func TestCohereEmbedImages(t *testing.T) {
+	t.Parallel()
+	var requests []embedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embedRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"embeddings": map[string]any{"float": [][]float32{{float32(len(requests)), 0.5}}},
+		}))
+	}))
+	defer server.Close()
+
+	e, err := NewCohere(WithToken("token"), WithBaseURL(server.URL), WithModel("embed-v4.0"))
+	require.NoError(t, err)
+
+	images := []embeddings.Image{
+		{MIMEType: "image/png", Data: []byte("png")},
+		{MIMEType: "image/jpeg", Data: []byte("jpeg")},
+	}
+	vectors, err := e.EmbedImages(context.Background(), images)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0.5}, {2, 0.5}}, vectors)
+	require.Len(t, requests, 2)
+	assert.Equal(t, InputTypeImage, requests[0].InputType)
+	assert.Empty(t, requests[0].Texts)
+	assert.Equal(t, []string{"data:image/png;base64,cG5n"}, requests[0].Images)
+	assert.Equal(t, "embed-v4.0", requests[1].Model)
+}
+
+func TestCohereReranker(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rerank", r.URL.Path)
+		var req rerankRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "q", req.Query)
+		assert.Equal(t, DefaultRerankModel, req.Model)
+		assert.Equal(t, 2, req.TopN)
+		_, _ = w.Write([]byte(`{"results":[{"index":2,"relevance_score":0.9},{"index":0,"relevance_score":0.5}]}`))
+	}))
+	defer server.Close()
+
+	r, err := NewReranker(WithRerankBaseURL(server.URL), WithRerankToken("token"), WithTopN(2))
+	require.NoError(t, err)
+	docs, err := r.Rerank(context.Background(), "q", []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "c", Score: 0.9}, {PageContent: "a", Score: 0.5}}, docs)
+}
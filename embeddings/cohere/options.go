@@ -0,0 +1,111 @@
+package cohere
+
+import (
+	"errors"
+	"net/http"
+	"os"
+)
+
+const (
+	_defaultBaseURL       = "https://api.cohere.com/v2"
+	_defaultBatchSize     = 96
+	_defaultStripNewLines = true
+	_defaultModel         = "embed-english-v3.0"
+)
+
+// ErrMissingToken is returned when no Cohere API key is configured.
+var ErrMissingToken = errors.New("missing the Cohere API key, set it as COHERE_API_KEY environment variable")
+
+// Option is a function type that can be used to modify the client.
+type Option func(c *Cohere)
+
+// WithModel is an option for providing the model name to use.
+func WithModel(model string) Option {
+	return func(c *Cohere) {
+		c.Model = model
+	}
+}
+
+// WithClient is an option for providing a custom http client.
+func WithClient(client *http.Client) Option {
+	return func(c *Cohere) {
+		c.client = client
+	}
+}
+
+// WithToken is an option for providing the Cohere API key.
+func WithToken(token string) Option {
+	return func(c *Cohere) {
+		c.token = token
+	}
+}
+
+// WithBaseURL is an option for providing the base URL of the Cohere API.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Cohere) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(c *Cohere) {
+		c.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size. The API accepts
+// at most 96 texts per request.
+func WithBatchSize(batchSize int) Option {
+	return func(c *Cohere) {
+		c.BatchSize = batchSize
+	}
+}
+
+// WithInputType is an option for overriding the input type used for both
+// documents and queries, e.g. InputTypeClassification.
+func WithInputType(inputType string) Option {
+	return func(c *Cohere) {
+		c.InputType = inputType
+	}
+}
+
+// WithEmbeddingType is an option for specifying the type of the embeddings
+// returned by EmbedDocuments and EmbedQuery. Non float embeddings are
+// converted to float32 values.
+func WithEmbeddingType(embeddingType EmbeddingType) Option {
+	return func(c *Cohere) {
+		c.EmbeddingType = embeddingType
+	}
+}
+
+// WithTruncate is an option for specifying how inputs longer than the
+// maximum token length are handled: "NONE", "START" or "END".
+func WithTruncate(truncate string) Option {
+	return func(c *Cohere) {
+		c.Truncate = truncate
+	}
+}
+
+func applyOptions(opts ...Option) (*Cohere, error) {
+	o := &Cohere{
+		baseURL:       _defaultBaseURL,
+		Model:         _defaultModel,
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		EmbeddingType: EmbeddingTypeFloat,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.client == nil {
+		o.client = http.DefaultClient
+	}
+	if o.token == "" {
+		o.token = os.Getenv("COHERE_API_KEY")
+	}
+	if o.token == "" {
+		return nil, ErrMissingToken
+	}
+	return o, nil
+}
@@ -0,0 +1,160 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// DefaultRerankModel is the default model of the Reranker.
+const DefaultRerankModel = "rerank-v3.5"
+
+var _ schema.Reranker = &Reranker{}
+
+// Reranker reorders documents by their relevance to a query with the Cohere
+// v2 rerank API.
+type Reranker struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	Model   string
+	// TopN is the number of documents returned. If zero, all documents are
+	// returned.
+	TopN int
+}
+
+// RerankerOption is a function type that can be used to modify the reranker.
+type RerankerOption func(r *Reranker)
+
+// WithRerankModel is an option for providing the reranker model name to use.
+func WithRerankModel(model string) RerankerOption {
+	return func(r *Reranker) {
+		r.Model = model
+	}
+}
+
+// WithRerankBaseURL is an option for providing the base URL of the Cohere API.
+func WithRerankBaseURL(baseURL string) RerankerOption {
+	return func(r *Reranker) {
+		r.baseURL = baseURL
+	}
+}
+
+// WithRerankToken is an option for providing the Cohere API key.
+func WithRerankToken(token string) RerankerOption {
+	return func(r *Reranker) {
+		r.token = token
+	}
+}
+
+// WithRerankClient is an option for providing a custom http client.
+func WithRerankClient(client *http.Client) RerankerOption {
+	return func(r *Reranker) {
+		r.client = client
+	}
+}
+
+// WithTopN is an option for specifying the number of documents returned.
+func WithTopN(topN int) RerankerOption {
+	return func(r *Reranker) {
+		r.TopN = topN
+	}
+}
+
+// NewReranker returns a new Cohere reranker.
+// The default model is "rerank-v3.5". Use `WithRerankModel` to change the model.
+func NewReranker(opts ...RerankerOption) (*Reranker, error) {
+	r := &Reranker{
+		baseURL: _defaultBaseURL,
+		Model:   DefaultRerankModel,
+		client:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.token == "" {
+		r.token = os.Getenv("COHERE_API_KEY")
+	}
+	if r.token == "" {
+		return nil, ErrMissingToken
+	}
+	return r, nil
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank returns the documents ordered by decreasing relevance to the query,
+// with their Score set to the relevance score.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	body, err := json.Marshal(rerankRequest{
+		Model:     r.Model,
+		Query:     query,
+		Documents: texts,
+		TopN:      r.TopN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := llms.DoRequest(r.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	var rerankResp rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rerankResp); err != nil {
+		return nil, err
+	}
+
+	result := make([]schema.Document, 0, len(rerankResp.Results))
+	for _, res := range rerankResp.Results {
+		if res.Index < 0 || res.Index >= len(docs) {
+			continue
+		}
+		doc := docs[res.Index]
+		doc.Score = float32(res.RelevanceScore)
+		result = append(result, doc)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
@@ -3,6 +3,7 @@ package embeddings
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/tmc/langchaingo/internal/util"
 )
@@ -14,6 +15,9 @@ func NewEmbedder(client EmbedderClient, opts ...Option) (*EmbedderImpl, error) {
 		client:        client,
 		StripNewLines: defaultStripNewLines,
 		BatchSize:     defaultBatchSize,
+		TokenCounter:  ApproximateTokenCount,
+		Concurrency:   1,
+		RetryBackoff:  defaultRetryBackoff,
 	}
 
 	for _, opt := range opts {
@@ -35,6 +39,13 @@ type EmbedderClient interface {
 	CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// QueryEmbedderClient is implemented by clients embedding queries differently
+// from documents, e.g. with a retrieval query task type. EmbedderImpl uses it
+// in EmbedQuery when the client implements it.
+type QueryEmbedderClient interface {
+	CreateQueryEmbedding(ctx context.Context, texts []string) ([][]float32, error)
+}
+
 // EmbedderClientFunc is an adapter to allow the use of ordinary functions as Embedder Clients. If
 // `f` is a function with the appropriate signature, `EmbedderClientFunc(f)` is an `EmbedderClient`
 // that calls `f`.
@@ -49,6 +60,21 @@ type EmbedderImpl struct {
 
 	StripNewLines bool
 	BatchSize     int
+	// MaxTokensPerBatch limits the number of tokens of a batch, counted with
+	// TokenCounter. Zero means no limit.
+	MaxTokensPerBatch int
+	TokenCounter      TokenCountFunc
+	// Concurrency is the number of batches embedded concurrently.
+	Concurrency int
+	// MaxRetries is the number of times a failed batch is retried, waiting
+	// RetryBackoff before the first retry and doubling it afterwards.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// RequestsPerSecond limits the rate of requests to the client. Zero
+	// means no limit.
+	RequestsPerSecond float64
+	// Progress is called after each embedded batch.
+	Progress ProgressFunc
 }
 
 // EmbedQuery embeds a single text.
@@ -57,7 +83,11 @@ func (ei *EmbedderImpl) EmbedQuery(ctx context.Context, text string) ([]float32,
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := ei.client.CreateEmbedding(ctx, []string{text})
+	createEmbedding := ei.client.CreateEmbedding
+	if client, ok := ei.client.(QueryEmbedderClient); ok {
+		createEmbedding = client.CreateQueryEmbedding
+	}
+	emb, err := createEmbedding(ctx, []string{text})
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +98,7 @@ func (ei *EmbedderImpl) EmbedQuery(ctx context.Context, text string) ([]float32,
 // EmbedDocuments creates one vector embedding for each of the texts.
 func (ei *EmbedderImpl) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
 	texts = MaybeRemoveNewLines(texts, ei.StripNewLines)
-	return BatchedEmbed(ctx, ei.client, texts, ei.BatchSize)
+	return ei.embedBatches(ctx, texts)
 }
 
 func MaybeRemoveNewLines(texts []string, removeNewLines bool) []string {
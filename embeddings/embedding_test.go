@@ -1,6 +1,7 @@
 package embeddings
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,3 +61,27 @@ func TestBatchTexts(t *testing.T) {
 		assert.Equal(t, tc.expected, BatchTexts(tc.texts, tc.batchSize))
 	}
 }
+
+type queryClient struct {
+	EmbedderClientFunc
+}
+
+func (queryClient) CreateQueryEmbedding(_ context.Context, texts []string) ([][]float32, error) {
+	return [][]float32{{2}}, nil
+}
+
+func TestEmbedQueryUsesQueryEmbedderClient(t *testing.T) {
+	t.Parallel()
+	client := queryClient{func(_ context.Context, texts []string) ([][]float32, error) {
+		return [][]float32{{1}}, nil
+	}}
+	e, err := NewEmbedder(client)
+	assert.NoError(t, err)
+
+	query, err := e.EmbedQuery(context.Background(), "q")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{2}, query)
+	docs, err := e.EmbedDocuments(context.Background(), []string{"d"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}}, docs)
+}
@@ -0,0 +1,33 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// Image is an image to embed.
+type Image struct {
+	// MIMEType is the media type of the image, e.g. "image/png".
+	MIMEType string
+	// Data is the encoded image.
+	Data []byte
+}
+
+// Base64 returns the image data encoded in standard base64.
+func (i Image) Base64() string {
+	return base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// DataURL returns the image as a base64 data URL.
+func (i Image) DataURL() string {
+	return "data:" + i.MIMEType + ";base64," + i.Base64()
+}
+
+// ImageEmbedder is the interface for creating vector embeddings of images.
+// Multimodal models embed images in the same space as texts, so the vectors
+// can be stored in the existing vector stores and searched with text
+// queries or other images.
+type ImageEmbedder interface {
+	// EmbedImages returns a vector for each image.
+	EmbedImages(ctx context.Context, images []Image) ([][]float32, error)
+}
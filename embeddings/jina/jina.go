@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
 )
 
 type Jina struct {
@@ -19,11 +20,23 @@ type Jina struct {
 	BatchSize     int
 	APIBaseURL    string
 	APIKey        string
+	// Task is the task adapter used by v3 models. If empty, documents are
+	// embedded with TaskRetrievalPassage and queries with TaskRetrievalQuery.
+	Task string
+	// LateChunking embeds the texts of a request as chunks of one document,
+	// so that each embedding carries the context of the whole document.
+	LateChunking bool
+	// Dimensions truncates the embeddings of v3 models to the given size.
+	Dimensions int
+	client     *http.Client
 }
 
 type EmbeddingRequest struct {
-	Input []string `json:"input"`
-	Model string   `json:"model"`
+	Input        []string `json:"input"`
+	Model        string   `json:"model"`
+	Task         string   `json:"task,omitempty"`
+	LateChunking bool     `json:"late_chunking,omitempty"`
+	Dimensions   int      `json:"dimensions,omitempty"`
 }
 
 type EmbeddingResponse struct {
@@ -40,7 +53,11 @@ type EmbeddingResponse struct {
 	} `json:"data"`
 }
 
-var _ embeddings.Embedder = &Jina{}
+var (
+	_ embeddings.Embedder       = &Jina{}
+	_ embeddings.ImageEmbedder  = &Jina{}
+	_ embeddings.ModelDescriber = &Jina{}
+)
 
 func NewJina(opts ...Option) (*Jina, error) {
 	v := applyOptions(opts...)
@@ -56,7 +73,7 @@ func (j *Jina) EmbedDocuments(ctx context.Context, texts []string) ([][]float32,
 
 	emb := make([][]float32, 0, len(texts))
 	for _, batch := range batchedTexts {
-		curBatchEmbeddings, err := j.CreateEmbedding(ctx, batch)
+		curBatchEmbeddings, err := j.createEmbedding(ctx, batch, j.task(TaskRetrievalPassage))
 		if err != nil {
 			return nil, err
 		}
@@ -71,7 +88,7 @@ func (j *Jina) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := j.CreateEmbedding(ctx, []string{text})
+	emb, err := j.createEmbedding(ctx, []string{text}, j.task(TaskRetrievalQuery))
 	if err != nil {
 		return nil, err
 	}
@@ -81,10 +98,85 @@ func (j *Jina) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
 
 // CreateEmbedding sends texts to the Jina API and retrieves their embeddings.
 func (j *Jina) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
-	requestBody := EmbeddingRequest{
-		Input: texts,
-		Model: j.Model,
+	return j.createEmbedding(ctx, texts, j.Task)
+}
+
+// task returns the task adapter to use, defaulting to the given one for v3
+// models. Older models don't support task adapters.
+func (j *Jina) task(defaultTask string) string {
+	if j.Task != "" || !strings.HasPrefix(j.Model, "jina-embeddings-v3") {
+		return j.Task
+	}
+	return defaultTask
+}
+
+// EmbeddingModel implements `embeddings.ModelDescriber`.
+func (j *Jina) EmbeddingModel() string {
+	return j.Model
+}
+
+// EmbeddingDimensions implements `embeddings.ModelDescriber`, returning the
+// requested dimensions or the default ones of known models.
+func (j *Jina) EmbeddingDimensions() int {
+	if j.Dimensions > 0 {
+		return j.Dimensions
 	}
+	return _models[j.Model]
+}
+
+// EmbedImages implements the `embeddings.ImageEmbedder` with a CLIP model,
+// e.g. ClipV2Model, embedding images in the same space as texts.
+func (j *Jina) EmbedImages(ctx context.Context, images []embeddings.Image) ([][]float32, error) {
+	emb := make([][]float32, 0, len(images))
+	for start := 0; start < len(images); start += j.BatchSize {
+		batch := images[start:min(start+j.BatchSize, len(images))]
+		input := make([]imageInput, len(batch))
+		for i, image := range batch {
+			input[i] = imageInput{Image: image.Base64()}
+		}
+		vectors, err := j.doEmbedding(ctx, imageEmbeddingRequest{
+			Input:      input,
+			Model:      j.Model,
+			Dimensions: j.Dimensions,
+		})
+		if err != nil {
+			return nil, err
+		}
+		emb = append(emb, vectors...)
+	}
+	return emb, nil
+}
+
+type imageInput struct {
+	Image string `json:"image"`
+}
+
+type imageEmbeddingRequest struct {
+	Input      []imageInput `json:"input"`
+	Model      string       `json:"model"`
+	Dimensions int          `json:"dimensions,omitempty"`
+}
+
+func (j *Jina) createEmbedding(ctx context.Context, texts []string, task string) ([][]float32, error) {
+	return j.doEmbedding(ctx, EmbeddingRequest{
+		Input:        texts,
+		Model:        j.Model,
+		Task:         task,
+		LateChunking: j.LateChunking,
+		Dimensions:   j.Dimensions,
+	})
+}
+
+// httpClient returns the client set by WithHTTPClient, or http.DefaultClient
+// for a Jina built without NewJina.
+func (j *Jina) httpClient() *http.Client {
+	if j.client == nil {
+		return http.DefaultClient
+	}
+	return j.client
+}
+
+func (j *Jina) doEmbedding(ctx context.Context, requestBody any) ([][]float32, error) {
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, err
@@ -98,8 +190,7 @@ func (j *Jina) CreateEmbedding(ctx context.Context, texts []string) ([][]float32
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+j.APIKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := llms.DoRequest(j.httpClient(), req)
 	if err != nil {
 		return nil, err
 	}
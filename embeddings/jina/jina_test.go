@@ -2,11 +2,16 @@ package jina
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
 )
 
 func TestJinaEmbeddings(t *testing.T) {
@@ -25,3 +30,102 @@ func TestJinaEmbeddings(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, embeddings, 3)
 }
+
+func TestJinaV3Request(t *testing.T) {
+	t.Parallel()
+	var requests []EmbeddingRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EmbeddingRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float32{float32(i)}}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	defer server.Close()
+
+	j, err := NewJina(WithModel(V3Model), WithAPIBaseURL(server.URL), WithLateChunking(true), WithDimensions(256))
+	require.NoError(t, err)
+
+	embeddings, err := j.EmbedDocuments(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 2)
+	_, err = j.EmbedQuery(context.Background(), "q")
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, TaskRetrievalPassage, requests[0].Task)
+	assert.True(t, requests[0].LateChunking)
+	assert.Equal(t, 256, requests[0].Dimensions)
+	assert.Equal(t, TaskRetrievalQuery, requests[1].Task)
+}
+
+func TestJinaEmbedImages(t *testing.T) {
+	t.Parallel()
+	var request imageEmbeddingRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		data := make([]map[string]any, len(request.Input))
+		for i := range request.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float32{float32(i)}}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	defer server.Close()
+
+	j, err := NewJina(WithModel(ClipV2Model), WithAPIBaseURL(server.URL))
+	require.NoError(t, err)
+
+	vectors, err := j.EmbedImages(context.Background(), []embeddings.Image{
+		{MIMEType: "image/png", Data: []byte("a")},
+		{MIMEType: "image/png", Data: []byte("b")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0}, {1}}, vectors)
+	assert.Equal(t, ClipV2Model, request.Model)
+	assert.Equal(t, []imageInput{{Image: "YQ=="}, {Image: "Yg=="}}, request.Input)
+}
+
+func TestJinaReranker(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req RerankRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "q", req.Query)
+		assert.Equal(t, 2, req.TopN)
+		_, _ = w.Write([]byte(`{"results":[{"index":2,"relevance_score":0.9},{"index":0,"relevance_score":0.5}]}`))
+	}))
+	defer server.Close()
+
+	r, err := NewReranker(WithRerankAPIBaseURL(server.URL), WithTopN(2))
+	require.NoError(t, err)
+	docs, err := r.Rerank(context.Background(), "q", []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "c", Score: 0.9}, {PageContent: "a", Score: 0.5}}, docs)
+}
+
+func TestJinaWithoutConstructor(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rerank" {
+			_, _ = w.Write([]byte(`{"results":[{"index":0,"relevance_score":0.5}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[1]}]}`))
+	}))
+	defer server.Close()
+
+	j := &Jina{Model: V3Model, APIBaseURL: server.URL, BatchSize: 1}
+	vectors, err := j.EmbedDocuments(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}}, vectors)
+
+	r := &Reranker{APIBaseURL: server.URL + "/rerank"}
+	docs, err := r.Rerank(context.Background(), "q", []schema.Document{{PageContent: "a"}})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "a", Score: 0.5}}, docs)
+}
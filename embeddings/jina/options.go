@@ -1,6 +1,7 @@
 package jina
 
 import (
+	"net/http"
 	"os"
 )
 
@@ -11,9 +12,20 @@ const (
 	SmallModel            = "jina-embeddings-v2-small-en"
 	BaseModel             = "jina-embeddings-v2-base-en"
 	LargeModel            = "jina-embeddings-v2-large-en"
+	V3Model               = "jina-embeddings-v3"
+	ClipV2Model           = "jina-clip-v2"
 	APIBaseURL            = "https://api.jina.ai/v1/embeddings"
 )
 
+// Task adapters supported by v3 models.
+const (
+	TaskRetrievalQuery   = "retrieval.query"
+	TaskRetrievalPassage = "retrieval.passage"
+	TaskSeparation       = "separation"
+	TaskClassification   = "classification"
+	TaskTextMatching     = "text-matching"
+)
+
 // Option is a function type that can be used to modify the client.
 type Option func(p *Jina)
 
@@ -52,19 +64,53 @@ func WithAPIKey(apiKey string) Option {
 	}
 }
 
-func applyOptions(opts ...Option) *Jina {
-	_models := map[string]int{
-		"jina-embeddings-v2-small-en": 512,
-		"jina-embeddings-v2-base-en":  768,
-		"jina-embeddings-v2-large-en": 1024,
+// WithTask is an option for specifying the task adapter of v3 models, used
+// for both documents and queries.
+func WithTask(task string) Option {
+	return func(p *Jina) {
+		p.Task = task
 	}
+}
 
+// WithLateChunking is an option for enabling late chunking, embedding the
+// texts of each batch as chunks of a single document.
+func WithLateChunking(lateChunking bool) Option {
+	return func(p *Jina) {
+		p.LateChunking = lateChunking
+	}
+}
+
+// WithDimensions is an option for truncating the embeddings of v3 models.
+func WithDimensions(dimensions int) Option {
+	return func(p *Jina) {
+		p.Dimensions = dimensions
+	}
+}
+
+// WithHTTPClient is an option for providing a custom http client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Jina) {
+		p.client = client
+	}
+}
+
+// _models are the embedding dimensions of the known models.
+var _models = map[string]int{ // nolint:gochecknoglobals
+	"jina-embeddings-v2-small-en": 512,
+	"jina-embeddings-v2-base-en":  768,
+	"jina-embeddings-v2-large-en": 1024,
+	"jina-embeddings-v3":          1024,
+	"jina-clip-v2":                1024,
+}
+
+func applyOptions(opts ...Option) *Jina {
 	o := &Jina{
 		StripNewLines: _defaultStripNewLines,
 		BatchSize:     _models[_defaultModel],
 		Model:         _defaultModel,
 		APIBaseURL:    APIBaseURL,
 		APIKey:        os.Getenv("JINA_API_KEY"),
+		client:        http.DefaultClient,
 	}
 
 	for _, opt := range opts {
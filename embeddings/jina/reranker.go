@@ -0,0 +1,168 @@
+package jina
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	DefaultRerankModel = "jina-reranker-v2-base-multilingual"
+	RerankAPIBaseURL   = "https://api.jina.ai/v1/rerank"
+)
+
+var _ schema.Reranker = &Reranker{}
+
+// Reranker reorders documents by their relevance to a query with the Jina
+// rerank API.
+type Reranker struct {
+	Model      string
+	APIBaseURL string
+	APIKey     string
+	// TopN is the number of documents returned. If zero, all documents are
+	// returned.
+	TopN   int
+	client *http.Client
+}
+
+// RerankerOption is a function type that can be used to modify the reranker.
+type RerankerOption func(r *Reranker)
+
+// WithRerankModel is an option for providing the reranker model name to use.
+func WithRerankModel(model string) RerankerOption {
+	return func(r *Reranker) {
+		r.Model = model
+	}
+}
+
+// WithRerankAPIBaseURL is an option for specifying the rerank API base URL.
+func WithRerankAPIBaseURL(apiBaseURL string) RerankerOption {
+	return func(r *Reranker) {
+		r.APIBaseURL = apiBaseURL
+	}
+}
+
+// WithRerankAPIKey is an option for specifying the API key.
+func WithRerankAPIKey(apiKey string) RerankerOption {
+	return func(r *Reranker) {
+		r.APIKey = apiKey
+	}
+}
+
+// WithTopN is an option for specifying the number of documents returned.
+func WithTopN(topN int) RerankerOption {
+	return func(r *Reranker) {
+		r.TopN = topN
+	}
+}
+
+// WithRerankHTTPClient is an option for providing a custom http client.
+func WithRerankHTTPClient(client *http.Client) RerankerOption {
+	return func(r *Reranker) {
+		r.client = client
+	}
+}
+
+// NewReranker returns a new Jina reranker.
+func NewReranker(opts ...RerankerOption) (*Reranker, error) {
+	r := &Reranker{
+		Model:      DefaultRerankModel,
+		APIBaseURL: RerankAPIBaseURL,
+		APIKey:     os.Getenv("JINA_API_KEY"),
+		client:     http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+type RerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+type RerankResponse struct {
+	Model   string `json:"model"`
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// httpClient returns the client set by WithRerankHTTPClient, or
+// http.DefaultClient for a Reranker built without NewReranker.
+func (r *Reranker) httpClient() *http.Client {
+	if r.client == nil {
+		return http.DefaultClient
+	}
+	return r.client
+}
+
+// Rerank returns the documents ordered by decreasing relevance to the query,
+// with their Score set to the relevance score.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	jsonData, err := json.Marshal(RerankRequest{
+		Model:     r.Model,
+		Query:     query,
+		Documents: texts,
+		TopN:      r.TopN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.APIBaseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.APIKey)
+
+	resp, err := llms.DoRequest(r.httpClient(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("API request failed with status: " + resp.Status)
+	}
+
+	var rerankResponse RerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rerankResponse); err != nil {
+		return nil, err
+	}
+
+	result := make([]schema.Document, 0, len(rerankResponse.Results))
+	for _, res := range rerankResponse.Results {
+		if res.Index < 0 || res.Index >= len(docs) {
+			continue
+		}
+		doc := docs[res.Index]
+		doc.Score = float32(res.RelevanceScore)
+		result = append(result, doc)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package embeddings
+
+// ModelDescriber is implemented by embedders, and embedder clients, knowing
+// the model they embed texts with and the size of its embeddings. Vector
+// stores use it to check the embedder matches their index.
+type ModelDescriber interface {
+	// EmbeddingModel returns the name of the embedding model, or "" if it is
+	// unknown.
+	EmbeddingModel() string
+	// EmbeddingDimensions returns the length of the vectors returned by the
+	// embedder, or 0 if it is unknown.
+	EmbeddingDimensions() int
+}
+
+// Model returns the name of the model of the embedder, or "" if the
+// embedder doesn't implement ModelDescriber.
+func Model(e Embedder) string {
+	if d, ok := e.(ModelDescriber); ok {
+		return d.EmbeddingModel()
+	}
+	return ""
+}
+
+// Dimensions returns the length of the vectors of the embedder, or 0 if the
+// embedder doesn't implement ModelDescriber.
+func Dimensions(e Embedder) int {
+	if d, ok := e.(ModelDescriber); ok {
+		return d.EmbeddingDimensions()
+	}
+	return 0
+}
+
+var (
+	_ ModelDescriber = &EmbedderImpl{}
+	_ ModelDescriber = &CachedEmbedder{}
+	_ ModelDescriber = &ResizedEmbedder{}
+)
+
+// EmbeddingModel returns the model of the client, if it implements
+// ModelDescriber.
+func (ei *EmbedderImpl) EmbeddingModel() string {
+	if d, ok := ei.client.(ModelDescriber); ok {
+		return d.EmbeddingModel()
+	}
+	return ""
+}
+
+// EmbeddingDimensions returns the dimensions of the client, if it implements
+// ModelDescriber.
+func (ei *EmbedderImpl) EmbeddingDimensions() int {
+	if d, ok := ei.client.(ModelDescriber); ok {
+		return d.EmbeddingDimensions()
+	}
+	return 0
+}
+
+// EmbeddingModel returns the model of the wrapped embedder, falling back to
+// the model the cache keys are built with.
+func (c *CachedEmbedder) EmbeddingModel() string {
+	if model := Model(c.embedder); model != "" {
+		return model
+	}
+	return c.Model
+}
+
+// EmbeddingDimensions returns the dimensions of the wrapped embedder.
+func (c *CachedEmbedder) EmbeddingDimensions() int {
+	return Dimensions(c.embedder)
+}
+
+// EmbeddingModel returns the model of the wrapped embedder.
+func (r *ResizedEmbedder) EmbeddingModel() string {
+	return Model(r.embedder)
+}
+
+// EmbeddingDimensions returns the dimensions the vectors are truncated to,
+// or those of the wrapped embedder.
+func (r *ResizedEmbedder) EmbeddingDimensions() int {
+	if r.Dimensions > 0 {
+		return r.Dimensions
+	}
+	return Dimensions(r.embedder)
+}
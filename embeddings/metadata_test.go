@@ -0,0 +1,33 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describedClient struct {
+	EmbedderClientFunc
+}
+
+func (describedClient) EmbeddingModel() string   { return "model" }
+func (describedClient) EmbeddingDimensions() int { return 8 }
+
+func TestModelDescriber(t *testing.T) {
+	t.Parallel()
+	client := describedClient{func(context.Context, []string) ([][]float32, error) { return nil, nil }}
+	e, err := NewEmbedder(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "model", Model(e))
+	assert.Equal(t, 8, Dimensions(e))
+
+	resized := NewResizedEmbedder(e, WithTruncatedDimensions(4))
+	assert.Equal(t, "model", Model(resized))
+	assert.Equal(t, 4, Dimensions(resized))
+
+	plain, err := NewEmbedder(EmbedderClientFunc(client.EmbedderClientFunc))
+	assert.NoError(t, err)
+	assert.Equal(t, "", Model(plain))
+	assert.Equal(t, 0, Dimensions(plain))
+}
@@ -0,0 +1,8 @@
+// Package onnx provides an embedder running sentence-transformer models
+// exported to ONNX locally with onnxruntime, so that texts can be embedded
+// without any API.
+//
+// The package uses onnxruntime through cgo and is only built with the onnx
+// build tag, e.g. go build -tags onnx. The onnxruntime shared library must be
+// installed, see https://onnxruntime.ai/docs/install/.
+package onnx
@@ -0,0 +1,188 @@
+//go:build onnx
+
+package onnx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// ErrUnexpectedOutput is returned when the model output isn't a tensor of
+// token embeddings.
+var ErrUnexpectedOutput = errors.New("unexpected model output")
+
+// ONNX is the embedder client running a sentence-transformer model with
+// onnxruntime. Wrap it with embeddings.NewEmbedder to use it as an
+// embeddings.Embedder.
+type ONNX struct {
+	ModelPath         string
+	TokenizerPath     string
+	SharedLibraryPath string
+	PoolingStrategy   PoolingStrategy
+	Normalize         bool
+	MaxSequenceLength int
+	BatchSize         int
+
+	tokenizer  *Tokenizer
+	session    *ort.DynamicAdvancedSession
+	inputNames []string
+	outputName string
+	hiddenSize int64
+	// mu serializes the runs of the session.
+	mu sync.Mutex
+}
+
+var _ embeddings.EmbedderClient = (*ONNX)(nil)
+
+// nolint:gochecknoglobals
+var (
+	initOnce sync.Once
+	errInit  error
+)
+
+// New returns a new embedding client running the ONNX model at modelPath.
+// The onnxruntime environment is initialized on first use, and must not be
+// initialized elsewhere with a different shared library.
+func New(modelPath string, opts ...Option) (*ONNX, error) {
+	o := &ONNX{
+		ModelPath:     modelPath,
+		TokenizerPath: filepath.Join(filepath.Dir(modelPath), "tokenizer.json"),
+		Normalize:     true,
+		BatchSize:     _defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var err error
+	if o.tokenizer, err = LoadTokenizer(o.TokenizerPath); err != nil {
+		return nil, err
+	}
+	if o.MaxSequenceLength > 0 {
+		o.tokenizer.maxSequenceLen = o.MaxSequenceLength
+	}
+
+	initOnce.Do(func() {
+		if o.SharedLibraryPath != "" {
+			ort.SetSharedLibraryPath(o.SharedLibraryPath)
+		}
+		errInit = ort.InitializeEnvironment()
+	})
+	if errInit != nil {
+		return nil, fmt.Errorf("initialize onnxruntime: %w", errInit)
+	}
+
+	inputs, outputs, err := ort.GetInputOutputInfo(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, input := range inputs {
+		o.inputNames = append(o.inputNames, input.Name)
+	}
+	for _, name := range o.inputNames {
+		if !slices.Contains([]string{"input_ids", "attention_mask", "token_type_ids"}, name) {
+			return nil, fmt.Errorf("%w: unsupported model input %q", ErrUnexpectedOutput, name)
+		}
+	}
+	if len(outputs) == 0 || len(outputs[0].Dimensions) != 3 { //nolint:mnd
+		return nil, fmt.Errorf("%w: expected token embeddings as first output", ErrUnexpectedOutput)
+	}
+	o.outputName = outputs[0].Name
+	o.hiddenSize = outputs[0].Dimensions[2]
+
+	o.session, err = ort.NewDynamicAdvancedSession(modelPath, o.inputNames, []string{o.outputName}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Close releases the onnxruntime session.
+func (o *ONNX) Close() error {
+	return o.session.Destroy()
+}
+
+// CreateEmbedding implements the `embeddings.EmbedderClient` and creates an embedding
+// vector for each of the supplied texts.
+func (o *ONNX) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	result := make([][]float32, 0, len(texts))
+	for _, batch := range embeddings.BatchTexts(texts, o.BatchSize) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vectors, err := o.embedBatch(batch)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vectors...)
+	}
+	return result, nil
+}
+
+func (o *ONNX) embedBatch(texts []string) ([][]float32, error) {
+	encoded := make([][]int64, len(texts))
+	seqLen := 0
+	for i, text := range texts {
+		encoded[i] = o.tokenizer.Encode(text)
+		seqLen = max(seqLen, len(encoded[i]))
+	}
+
+	batchSize := int64(len(texts))
+	ids := make([]int64, 0, len(texts)*seqLen)
+	mask := make([]int64, 0, len(texts)*seqLen)
+	for _, tokens := range encoded {
+		for j := 0; j < seqLen; j++ {
+			if j < len(tokens) {
+				ids = append(ids, tokens[j])
+				mask = append(mask, 1)
+			} else {
+				ids = append(ids, o.tokenizer.padID)
+				mask = append(mask, 0)
+			}
+		}
+	}
+
+	shape := ort.NewShape(batchSize, int64(seqLen))
+	inputs := make([]ort.ArbitraryTensor, 0, len(o.inputNames))
+	defer func() {
+		for _, input := range inputs {
+			input.Destroy()
+		}
+	}()
+	for _, name := range o.inputNames {
+		data := mask
+		switch name {
+		case "input_ids":
+			data = ids
+		case "token_type_ids":
+			data = make([]int64, len(ids))
+		}
+		tensor, err := ort.NewTensor(shape, data)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, tensor)
+	}
+
+	output, err := ort.NewEmptyTensor[float32](ort.NewShape(batchSize, int64(seqLen), o.hiddenSize))
+	if err != nil {
+		return nil, err
+	}
+	defer output.Destroy()
+
+	o.mu.Lock()
+	err = o.session.Run(inputs, []ort.ArbitraryTensor{output})
+	o.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return pool(output.GetData(), mask, len(texts), seqLen, int(o.hiddenSize), o.PoolingStrategy, o.Normalize), nil
+}
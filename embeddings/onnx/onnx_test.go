@@ -0,0 +1,53 @@
+//go:build onnx
+
+package onnx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTokenizer(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokenizer.json")
+	err := os.WriteFile(path, []byte(`{
+		"normalizer": {"type": "BertNormalizer", "lowercase": true},
+		"model": {
+			"type": "WordPiece",
+			"unk_token": "[UNK]",
+			"continuing_subword_prefix": "##",
+			"vocab": {"[PAD]": 0, "[UNK]": 1, "[CLS]": 2, "[SEP]": 3, "hello": 4, "world": 5, "!": 6, "play": 7, "##ing": 8, "cafe": 9}
+		}
+	}`), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func TestTokenizer(t *testing.T) {
+	t.Parallel()
+	tokenizer, err := LoadTokenizer(writeTokenizer(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{2, 4, 5, 6, 3}, tokenizer.Encode("Hello  WORLD!"))
+	assert.Equal(t, []int64{2, 7, 8, 1, 9, 3}, tokenizer.Encode("playing xyz café"))
+
+	tokenizer.maxSequenceLen = 4
+	assert.Equal(t, []int64{2, 4, 5, 3}, tokenizer.Encode("hello world hello"))
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+	data := []float32{
+		1, 2, 3, 4, 100, 100, // first text, with a padding token.
+		0, 2, 0, 4, 0, 6,
+	}
+	mask := []int64{1, 1, 0, 1, 1, 1}
+
+	assert.Equal(t, [][]float32{{2, 3}, {0, 4}}, pool(data, mask, 2, 3, 2, MeanPooling, false))
+	assert.Equal(t, [][]float32{{1, 2}, {0, 2}}, pool(data, mask, 2, 3, 2, CLSPooling, false))
+	assert.Equal(t, [][]float32{{0.6, 0.8}}, pool([]float32{3, 4}, []int64{1}, 1, 1, 2, MeanPooling, true))
+}
@@ -0,0 +1,70 @@
+//go:build onnx
+
+package onnx
+
+const (
+	_defaultMaxSequenceLength = 512
+	_defaultBatchSize         = 32
+)
+
+// PoolingStrategy is the way token embeddings are pooled into a sentence
+// embedding.
+type PoolingStrategy int
+
+const (
+	// MeanPooling averages the token embeddings, ignoring padding. It's the
+	// pooling used by most sentence-transformer models.
+	MeanPooling PoolingStrategy = iota
+	// CLSPooling uses the embedding of the [CLS] token, e.g. for bge models.
+	CLSPooling
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(o *ONNX)
+
+// WithTokenizerPath is an option for setting the path of the tokenizer.json
+// file. Defaults to tokenizer.json next to the model.
+func WithTokenizerPath(path string) Option {
+	return func(o *ONNX) {
+		o.TokenizerPath = path
+	}
+}
+
+// WithSharedLibraryPath is an option for setting the path of the
+// onnxruntime shared library. Defaults to the platform's default library
+// name looked up by the dynamic loader.
+func WithSharedLibraryPath(path string) Option {
+	return func(o *ONNX) {
+		o.SharedLibraryPath = path
+	}
+}
+
+// WithPoolingStrategy sets the pooling strategy. Default is mean pooling.
+func WithPoolingStrategy(strategy PoolingStrategy) Option {
+	return func(o *ONNX) {
+		o.PoolingStrategy = strategy
+	}
+}
+
+// WithNormalize sets whether embeddings are scaled to a unit L2 norm.
+// Default is true.
+func WithNormalize(normalize bool) Option {
+	return func(o *ONNX) {
+		o.Normalize = normalize
+	}
+}
+
+// WithMaxSequenceLength sets the maximum number of tokens of a text, longer
+// texts being truncated. Defaults to the truncation of the tokenizer, or 512.
+func WithMaxSequenceLength(length int) Option {
+	return func(o *ONNX) {
+		o.MaxSequenceLength = length
+	}
+}
+
+// WithBatchSize sets the number of texts run through the model at once.
+func WithBatchSize(batchSize int) Option {
+	return func(o *ONNX) {
+		o.BatchSize = batchSize
+	}
+}
@@ -0,0 +1,37 @@
+//go:build onnx
+
+package onnx
+
+import "github.com/tmc/langchaingo/embeddings"
+
+// pool reduces the token embeddings of each text, of shape
+// [batch, seqLen, hidden], to a sentence embedding.
+func pool(data []float32, mask []int64, batch, seqLen, hidden int, strategy PoolingStrategy, normalize bool) [][]float32 { //nolint:lll
+	vectors := make([][]float32, batch)
+	for b := 0; b < batch; b++ {
+		vector := make([]float32, hidden)
+		if strategy == CLSPooling {
+			copy(vector, data[b*seqLen*hidden:])
+		} else {
+			var count float32
+			for s := 0; s < seqLen; s++ {
+				if mask[b*seqLen+s] == 0 {
+					continue
+				}
+				count++
+				token := data[(b*seqLen+s)*hidden:]
+				for h := range vector {
+					vector[h] += token[h]
+				}
+			}
+			for h := range vector {
+				vector[h] /= max(count, 1)
+			}
+		}
+		if normalize {
+			vector = embeddings.NormalizeVector(vector)
+		}
+		vectors[b] = vector
+	}
+	return vectors
+}
@@ -0,0 +1,192 @@
+//go:build onnx
+
+package onnx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrUnsupportedTokenizer is returned when a tokenizer.json file uses a
+// tokenization model other than WordPiece.
+var ErrUnsupportedTokenizer = errors.New("unsupported tokenizer")
+
+// Tokenizer is a WordPiece tokenizer loaded from a Hugging Face
+// tokenizer.json file, as used by BERT-based sentence-transformer models.
+type Tokenizer struct {
+	vocab          map[string]int64
+	unkToken       string
+	subwordPrefix  string
+	maxWordChars   int
+	lowercase      bool
+	stripAccents   bool
+	clsID          int64
+	sepID          int64
+	padID          int64
+	maxSequenceLen int
+}
+
+type tokenizerFile struct {
+	Normalizer *struct {
+		Type         string `json:"type"`
+		Lowercase    *bool  `json:"lowercase"`
+		StripAccents *bool  `json:"strip_accents"`
+	} `json:"normalizer"`
+	Truncation *struct {
+		MaxLength int `json:"max_length"`
+	} `json:"truncation"`
+	Model struct {
+		Type                    string           `json:"type"`
+		UnkToken                string           `json:"unk_token"`
+		ContinuingSubwordPrefix string           `json:"continuing_subword_prefix"`
+		MaxInputCharsPerWord    int              `json:"max_input_chars_per_word"`
+		Vocab                   map[string]int64 `json:"vocab"`
+	} `json:"model"`
+}
+
+// LoadTokenizer loads a WordPiece tokenizer from a tokenizer.json file.
+func LoadTokenizer(path string) (*Tokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file tokenizerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse tokenizer: %w", err)
+	}
+	if file.Model.Type != "WordPiece" {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedTokenizer, file.Model.Type)
+	}
+
+	t := &Tokenizer{
+		vocab:          file.Model.Vocab,
+		unkToken:       file.Model.UnkToken,
+		subwordPrefix:  file.Model.ContinuingSubwordPrefix,
+		maxWordChars:   file.Model.MaxInputCharsPerWord,
+		maxSequenceLen: _defaultMaxSequenceLength,
+	}
+	if t.subwordPrefix == "" {
+		t.subwordPrefix = "##"
+	}
+	if t.maxWordChars == 0 {
+		t.maxWordChars = 100
+	}
+	if file.Normalizer != nil && file.Normalizer.Lowercase != nil {
+		t.lowercase = *file.Normalizer.Lowercase
+		t.stripAccents = t.lowercase
+		if file.Normalizer.StripAccents != nil {
+			t.stripAccents = *file.Normalizer.StripAccents
+		}
+	}
+	if file.Truncation != nil && file.Truncation.MaxLength > 0 {
+		t.maxSequenceLen = file.Truncation.MaxLength
+	}
+
+	var ok bool
+	for token, id := range map[string]*int64{"[CLS]": &t.clsID, "[SEP]": &t.sepID, "[PAD]": &t.padID} {
+		if *id, ok = t.vocab[token]; !ok {
+			return nil, fmt.Errorf("%w: missing %s token", ErrUnsupportedTokenizer, token)
+		}
+	}
+	return t, nil
+}
+
+// Encode returns the token ids of a text, including the [CLS] and [SEP]
+// tokens, truncated to the maximum sequence length.
+func (t *Tokenizer) Encode(text string) []int64 {
+	ids := []int64{t.clsID}
+	for _, word := range t.preTokenize(t.normalize(text)) {
+		ids = append(ids, t.wordPiece(word)...)
+		if len(ids) >= t.maxSequenceLen-1 {
+			ids = ids[:t.maxSequenceLen-1]
+			break
+		}
+	}
+	return append(ids, t.sepID)
+}
+
+func (t *Tokenizer) normalize(text string) string {
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == 0 || r == unicode.ReplacementChar || (unicode.IsControl(r) && !unicode.IsSpace(r)):
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, text)
+	if t.stripAccents {
+		text = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, norm.NFD.String(text))
+	}
+	if t.lowercase {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// preTokenize splits text on whitespace and around punctuation and CJK
+// characters.
+func (t *Tokenizer) preTokenize(text string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.Is(unicode.Han, r):
+			flush()
+			words = append(words, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return words
+}
+
+// wordPiece splits a word into the longest matching vocabulary entries.
+func (t *Tokenizer) wordPiece(word string) []int64 {
+	runes := []rune(word)
+	if len(runes) > t.maxWordChars {
+		return []int64{t.vocab[t.unkToken]}
+	}
+
+	var ids []int64
+	for start := 0; start < len(runes); {
+		end := len(runes)
+		var id int64
+		found := false
+		for ; end > start; end-- {
+			piece := string(runes[start:end])
+			if start > 0 {
+				piece = t.subwordPrefix + piece
+			}
+			if id, found = t.vocab[piece]; found {
+				break
+			}
+		}
+		if !found {
+			return []int64{t.vocab[t.unkToken]}
+		}
+		ids = append(ids, id)
+		start = end
+	}
+	return ids
+}
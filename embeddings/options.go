@@ -1,8 +1,11 @@
 package embeddings
 
+import "time"
+
 const (
 	defaultBatchSize     = 512
 	defaultStripNewLines = true
+	defaultRetryBackoff  = time.Second
 )
 
 type Option func(p *EmbedderImpl)
@@ -20,3 +23,55 @@ func WithBatchSize(batchSize int) Option {
 		p.BatchSize = batchSize
 	}
 }
+
+// WithMaxTokensPerBatch is an option for limiting the number of tokens of a
+// batch, in addition to its number of texts. Tokens are counted with the
+// function set by WithTokenCounter, approximated from the text length by
+// default.
+func WithMaxTokensPerBatch(maxTokens int) Option {
+	return func(p *EmbedderImpl) {
+		p.MaxTokensPerBatch = maxTokens
+	}
+}
+
+// WithTokenCounter is an option for specifying how the tokens of a text are
+// counted when batching by tokens.
+func WithTokenCounter(counter TokenCountFunc) Option {
+	return func(p *EmbedderImpl) {
+		p.TokenCounter = counter
+	}
+}
+
+// WithConcurrency is an option for specifying the number of batches
+// embedded concurrently.
+func WithConcurrency(concurrency int) Option {
+	return func(p *EmbedderImpl) {
+		p.Concurrency = concurrency
+	}
+}
+
+// WithRetries is an option for retrying failed batches up to maxRetries
+// times, waiting backoff before the first retry and doubling it afterwards.
+// Batches embedded successfully are never retried.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(p *EmbedderImpl) {
+		p.MaxRetries = maxRetries
+		p.RetryBackoff = backoff
+	}
+}
+
+// WithRateLimit is an option for limiting the number of requests per
+// second sent to the client.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(p *EmbedderImpl) {
+		p.RequestsPerSecond = requestsPerSecond
+	}
+}
+
+// WithProgress is an option for specifying a function called after each
+// embedded batch.
+func WithProgress(progress ProgressFunc) Option {
+	return func(p *EmbedderImpl) {
+		p.Progress = progress
+	}
+}
@@ -0,0 +1,84 @@
+package embeddings
+
+import "context"
+
+// ResizedEmbedder is an Embedder wrapper truncating and L2-normalizing the
+// vectors of another embedder client-side, so that the same index dimension
+// can be enforced whatever the provider supports.
+type ResizedEmbedder struct {
+	embedder Embedder
+
+	// Dimensions is the number of dimensions the vectors are truncated to.
+	// Zero keeps all dimensions.
+	Dimensions int
+	// Normalize scales the vectors, after truncation, to a unit L2 norm.
+	Normalize bool
+}
+
+var _ Embedder = &ResizedEmbedder{}
+
+// ResizeOption is a function that configures a ResizedEmbedder.
+type ResizeOption func(*ResizedEmbedder)
+
+// WithTruncatedDimensions truncates the vectors to their first dimensions
+// values, which is only meaningful for models trained with Matryoshka
+// Representation Learning.
+func WithTruncatedDimensions(dimensions int) ResizeOption {
+	return func(r *ResizedEmbedder) {
+		r.Dimensions = dimensions
+	}
+}
+
+// WithL2Normalization sets whether vectors are scaled to a unit L2 norm.
+// Truncated vectors usually need to be normalized again for cosine and dot
+// product similarity to agree.
+func WithL2Normalization(normalize bool) ResizeOption {
+	return func(r *ResizedEmbedder) {
+		r.Normalize = normalize
+	}
+}
+
+// NewResizedEmbedder returns an Embedder resizing the vectors of embedder.
+func NewResizedEmbedder(embedder Embedder, opts ...ResizeOption) *ResizedEmbedder {
+	r := &ResizedEmbedder{embedder: embedder}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// EmbedDocuments creates one resized vector embedding for each of the texts.
+func (r *ResizedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := r.embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	for i, vector := range vectors {
+		if vectors[i], err = r.resize(vector); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// EmbedQuery embeds a single text and resizes its vector.
+func (r *ResizedEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vector, err := r.embedder.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return r.resize(vector)
+}
+
+func (r *ResizedEmbedder) resize(vector []float32) ([]float32, error) {
+	if r.Dimensions > 0 {
+		var err error
+		if vector, err = TruncateVector(vector, r.Dimensions); err != nil {
+			return nil, err
+		}
+	}
+	if r.Normalize {
+		vector = NormalizeVector(vector)
+	}
+	return vector, nil
+}
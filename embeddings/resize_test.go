@@ -0,0 +1,35 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResizedEmbedder(t *testing.T) {
+	t.Parallel()
+	base, err := NewEmbedder(EmbedderClientFunc(func(_ context.Context, texts []string) ([][]float32, error) {
+		vectors := make([][]float32, len(texts))
+		for i := range texts {
+			vectors[i] = []float32{3, 4, 12}
+		}
+		return vectors, nil
+	}))
+	require.NoError(t, err)
+
+	e := NewResizedEmbedder(base, WithTruncatedDimensions(2), WithL2Normalization(true))
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.6, 0.8}}, vectors)
+
+	vector, err := NewResizedEmbedder(base, WithL2Normalization(true)).EmbedQuery(context.Background(), "a")
+	require.NoError(t, err)
+	for i, want := range []float32{3.0 / 13, 4.0 / 13, 12.0 / 13} {
+		assert.InDelta(t, want, vector[i], 1e-6)
+	}
+
+	_, err = NewResizedEmbedder(base, WithTruncatedDimensions(4)).EmbedQuery(context.Background(), "a")
+	require.ErrorIs(t, err, ErrVectorTooShort)
+}
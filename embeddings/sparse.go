@@ -0,0 +1,192 @@
+package embeddings
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SparseVector is a sparse embedding mapping term indices to weights.
+type SparseVector map[uint32]float32
+
+// Indices returns the term indices of the vector in increasing order.
+func (v SparseVector) Indices() []uint32 {
+	indices := make([]uint32, 0, len(v))
+	for i := range v {
+		indices = append(indices, i)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices
+}
+
+// Values returns the weights of the vector, in the order of Indices.
+func (v SparseVector) Values() []float32 {
+	indices := v.Indices()
+	values := make([]float32, len(indices))
+	for i, index := range indices {
+		values[i] = v[index]
+	}
+	return values
+}
+
+// SparseEmbedder is the interface for creating sparse embeddings, e.g. BM25
+// or SPLADE term weights, used alongside dense embeddings for hybrid search.
+type SparseEmbedder interface {
+	// EmbedDocumentsSparse returns a sparse vector for each text.
+	EmbedDocumentsSparse(ctx context.Context, texts []string) ([]SparseVector, error)
+	// EmbedQuerySparse embeds a single query text.
+	EmbedQuerySparse(ctx context.Context, text string) (SparseVector, error)
+}
+
+const (
+	_defaultBM25K1        = 1.2
+	_defaultBM25B         = 0.75
+	_defaultBM25AvgDocLen = 256
+)
+
+// BM25 is a SparseEmbedder weighting the terms of documents with the BM25
+// term frequency saturation. Terms are mapped to indices by hashing, so no
+// vocabulary is needed.
+//
+// Query terms are weighted by their inverse document frequency once the
+// embedder has been fitted to a corpus with Fit, and have a weight of 1
+// otherwise, leaving the IDF to the vector store (e.g. the Qdrant "idf"
+// modifier).
+type BM25 struct {
+	// K1 controls the term frequency saturation.
+	K1 float64
+	// B controls the document length normalization.
+	B float64
+	// AvgDocLen is the average number of terms of a document, updated by Fit.
+	AvgDocLen float64
+	// Tokenize splits a text into terms.
+	Tokenize func(text string) []string
+
+	mu       sync.RWMutex
+	docFreqs map[uint32]int
+	numDocs  int
+}
+
+var _ SparseEmbedder = &BM25{}
+
+// BM25Option is a function that configures a BM25 embedder.
+type BM25Option func(*BM25)
+
+// WithBM25Parameters sets the k1 and b parameters of BM25.
+func WithBM25Parameters(k1, b float64) BM25Option {
+	return func(e *BM25) {
+		e.K1 = k1
+		e.B = b
+	}
+}
+
+// WithBM25AvgDocLen sets the average number of terms of a document.
+func WithBM25AvgDocLen(avgDocLen float64) BM25Option {
+	return func(e *BM25) {
+		e.AvgDocLen = avgDocLen
+	}
+}
+
+// WithBM25Tokenizer sets the function splitting texts into terms.
+func WithBM25Tokenizer(tokenize func(text string) []string) BM25Option {
+	return func(e *BM25) {
+		e.Tokenize = tokenize
+	}
+}
+
+// NewBM25 returns a BM25 sparse embedder.
+func NewBM25(opts ...BM25Option) *BM25 {
+	e := &BM25{
+		K1:        _defaultBM25K1,
+		B:         _defaultBM25B,
+		AvgDocLen: _defaultBM25AvgDocLen,
+		Tokenize:  tokenizeTerms,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Fit records the document frequencies and average length of a corpus,
+// used to weight query terms by their inverse document frequency.
+func (e *BM25) Fit(texts []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.docFreqs == nil {
+		e.docFreqs = make(map[uint32]int)
+	}
+
+	totalLen := 0
+	for _, text := range texts {
+		terms := e.Tokenize(text)
+		totalLen += len(terms)
+		seen := make(map[uint32]bool, len(terms))
+		for _, term := range terms {
+			index := TermIndex(term)
+			if !seen[index] {
+				seen[index] = true
+				e.docFreqs[index]++
+			}
+		}
+	}
+	if len(texts) > 0 {
+		e.AvgDocLen = (e.AvgDocLen*float64(e.numDocs) + float64(totalLen)) / float64(e.numDocs+len(texts))
+	}
+	e.numDocs += len(texts)
+}
+
+// EmbedDocumentsSparse returns the BM25 term weights of each text.
+func (e *BM25) EmbedDocumentsSparse(_ context.Context, texts []string) ([]SparseVector, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	vectors := make([]SparseVector, len(texts))
+	for i, text := range texts {
+		terms := e.Tokenize(text)
+		freqs := make(map[uint32]float64, len(terms))
+		for _, term := range terms {
+			freqs[TermIndex(term)]++
+		}
+		norm := e.K1 * (1 - e.B + e.B*float64(len(terms))/e.AvgDocLen)
+		vector := make(SparseVector, len(freqs))
+		for index, tf := range freqs {
+			vector[index] = float32(tf * (e.K1 + 1) / (tf + norm))
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// EmbedQuerySparse returns the weights of the query terms.
+func (e *BM25) EmbedQuerySparse(_ context.Context, text string) (SparseVector, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	vector := make(SparseVector)
+	for _, term := range e.Tokenize(text) {
+		index := TermIndex(term)
+		weight := float32(1)
+		if e.numDocs > 0 {
+			df := float64(e.docFreqs[index])
+			weight = float32(math.Log(1 + (float64(e.numDocs)-df+0.5)/(df+0.5))) //nolint:mnd
+		}
+		vector[index] = weight
+	}
+	return vector, nil
+}
+
+// TermIndex returns the sparse vector index of a term.
+func TermIndex(term string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(term))
+	return h.Sum32()
+}
+
+func tokenizeTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
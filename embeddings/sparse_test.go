@@ -0,0 +1,36 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBM25(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	e := NewBM25(WithBM25AvgDocLen(2))
+
+	vectors, err := e.EmbedDocumentsSparse(ctx, []string{"Cats, cats!", "dogs"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 2)
+	assert.Len(t, vectors[0], 1)
+	// a repeated term weighs more, but less than twice as much.
+	assert.Greater(t, vectors[0][TermIndex("cats")], vectors[1][TermIndex("dogs")])
+	assert.Less(t, vectors[0][TermIndex("cats")], 2*vectors[1][TermIndex("dogs")])
+
+	query, err := e.EmbedQuerySparse(ctx, "cats and dogs")
+	require.NoError(t, err)
+	assert.Equal(t, float32(1), query[TermIndex("and")])
+
+	e.Fit([]string{"cats", "cats and dogs", "birds"})
+	query, err = e.EmbedQuerySparse(ctx, "cats birds")
+	require.NoError(t, err)
+	assert.Greater(t, query[TermIndex("birds")], query[TermIndex("cats")])
+
+	v := SparseVector{3: 0.3, 1: 0.1}
+	assert.Equal(t, []uint32{1, 3}, v.Indices())
+	assert.Equal(t, []float32{0.1, 0.3}, v.Values())
+}
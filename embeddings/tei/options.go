@@ -0,0 +1,90 @@
+package tei
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	_defaultBaseURL       = "http://localhost:8080"
+	_defaultBatchSize     = 32
+	_defaultStripNewLines = true
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(t *TEI)
+
+// WithBaseURL is an option for providing the URL of the
+// text-embeddings-inference server. Defaults to the TEI_BASE_URL environment
+// variable, or "http://localhost:8080".
+func WithBaseURL(baseURL string) Option {
+	return func(t *TEI) {
+		t.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithToken is an option for providing a bearer token, e.g. to call a
+// Hugging Face Inference Endpoint.
+func WithToken(token string) Option {
+	return func(t *TEI) {
+		t.token = token
+	}
+}
+
+// WithClient is an option for providing a custom http client.
+func WithClient(client *http.Client) Option {
+	return func(t *TEI) {
+		t.client = client
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(t *TEI) {
+		t.StripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the batch size. It must not
+// exceed the --max-client-batch-size of the server, 32 by default.
+func WithBatchSize(batchSize int) Option {
+	return func(t *TEI) {
+		t.BatchSize = batchSize
+	}
+}
+
+// WithTruncate is an option for truncating inputs longer than the maximum
+// input length of the model instead of failing.
+func WithTruncate(truncate bool) Option {
+	return func(t *TEI) {
+		t.Truncate = truncate
+	}
+}
+
+// WithNormalize is an option for specifying whether the server normalizes
+// the embeddings. Defaults to true.
+func WithNormalize(normalize bool) Option {
+	return func(t *TEI) {
+		t.Normalize = normalize
+	}
+}
+
+func applyOptions(opts ...Option) *TEI {
+	o := &TEI{
+		baseURL:       strings.TrimSuffix(os.Getenv("TEI_BASE_URL"), "/"),
+		StripNewLines: _defaultStripNewLines,
+		BatchSize:     _defaultBatchSize,
+		Normalize:     true,
+	}
+	if o.baseURL == "" {
+		o.baseURL = _defaultBaseURL
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.client == nil {
+		o.client = http.DefaultClient
+	}
+	return o
+}
@@ -0,0 +1,168 @@
+// Package tei provides an embedder and a reranker for Hugging Face
+// text-embeddings-inference servers, commonly used to self-host models such
+// as bge or gte.
+package tei
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// TEI is the embedder using a text-embeddings-inference server. The server
+// serves a single model, so there is no model to choose.
+type TEI struct {
+	baseURL       string
+	token         string
+	client        *http.Client
+	StripNewLines bool
+	BatchSize     int
+	// Truncate truncates inputs exceeding the maximum input length of the
+	// model instead of failing.
+	Truncate bool
+	// Normalize makes the server return normalized embeddings.
+	Normalize bool
+}
+
+var (
+	_ embeddings.Embedder = &TEI{}
+	_ schema.Reranker     = &TEI{}
+)
+
+// New returns a new embedder that uses a text-embeddings-inference server.
+func New(opts ...Option) (*TEI, error) {
+	return applyOptions(opts...), nil
+}
+
+type embedRequest struct {
+	Inputs    []string `json:"inputs"`
+	Truncate  bool     `json:"truncate"`
+	Normalize bool     `json:"normalize"`
+}
+
+// EmbedDocuments implements the `embeddings.Embedder` and creates an embedding for each of the texts.
+func (t *TEI) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	texts = embeddings.MaybeRemoveNewLines(texts, t.StripNewLines)
+	emb := make([][]float32, 0, len(texts))
+	for _, batch := range embeddings.BatchTexts(texts, t.BatchSize) {
+		var vectors [][]float32
+		err := t.post(ctx, "/embed", embedRequest{
+			Inputs:    batch,
+			Truncate:  t.Truncate,
+			Normalize: t.Normalize,
+		}, &vectors)
+		if err != nil {
+			return nil, err
+		}
+		if len(vectors) != len(batch) {
+			return nil, fmt.Errorf("returned %d embeddings for %d texts", len(vectors), len(batch))
+		}
+		emb = append(emb, vectors...)
+	}
+	return emb, nil
+}
+
+// EmbedQuery implements the `embeddings.Embedder` and creates an embedding for the query text.
+func (t *TEI) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if t.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+	emb, err := t.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return emb[0], nil
+}
+
+type rerankRequest struct {
+	Query    string   `json:"query"`
+	Texts    []string `json:"texts"`
+	Truncate bool     `json:"truncate"`
+}
+
+type rerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
+
+// Rerank returns the documents ordered by decreasing relevance to the query,
+// with their Score set to the relevance score. The server must serve a
+// reranker (sequence classification) model, such as bge-reranker.
+func (t *TEI) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	var results []rerankResult
+	err := t.post(ctx, "/rerank", rerankRequest{
+		Query:    query,
+		Texts:    texts,
+		Truncate: t.Truncate,
+	}, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]schema.Document, 0, len(results))
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(docs) {
+			continue
+		}
+		doc := docs[res.Index]
+		doc.Score = float32(res.Score)
+		reranked = append(reranked, doc)
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked, nil
+}
+
+func (t *TEI) post(ctx context.Context, path string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := llms.DoRequest(t.client, req)
+	if err != nil {
+		return fmt.Errorf("tei request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func decodeError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var errResp struct {
+		Error     string `json:"error"`
+		ErrorType string `json:"error_type"`
+	}
+	_ = json.Unmarshal(body, &errResp)
+	return llms.NewLLMError(resp.StatusCode, errResp.ErrorType, errResp.Error, body)
+}
@@ -0,0 +1,87 @@
+package tei
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestTEIEmbeddings(t *testing.T) {
+	t.Parallel()
+	var requests []embedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embed", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req embedRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		vectors := make([][]float32, len(req.Inputs))
+		for i := range req.Inputs {
+			vectors[i] = []float32{float32(len(requests)), float32(i)}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(vectors))
+	}))
+	defer server.Close()
+
+	e, err := New(WithBaseURL(server.URL+"/"), WithToken("token"), WithBatchSize(2), WithTruncate(true))
+	require.NoError(t, err)
+
+	vectors, err := e.EmbedDocuments(context.Background(), []string{"a\nb", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {1, 1}, {2, 0}}, vectors)
+	require.Len(t, requests, 2)
+	assert.Equal(t, []string{"a b", "c"}, requests[0].Inputs)
+	assert.True(t, requests[0].Truncate)
+	assert.True(t, requests[0].Normalize)
+
+	query, err := e.EmbedQuery(context.Background(), "q")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{3, 0}, query)
+}
+
+func TestTEIRerank(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rerank", r.URL.Path)
+		var req rerankRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "q", req.Query)
+		assert.Equal(t, []string{"a", "b"}, req.Texts)
+		assert.NoError(t, json.NewEncoder(w).Encode([]rerankResult{
+			{Index: 0, Score: 0.1},
+			{Index: 1, Score: 0.9},
+		}))
+	}))
+	defer server.Close()
+
+	e, err := New(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	docs, err := e.Rerank(context.Background(), "q", []schema.Document{{PageContent: "a"}, {PageContent: "b"}})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "b", docs[0].PageContent)
+	assert.InDelta(t, 0.9, docs[0].Score, 1e-6)
+}
+
+func TestTEIError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_, _ = w.Write([]byte(`{"error":"batch size 64 > maximum allowed batch size 32","error_type":"Validation"}`))
+	}))
+	defer server.Close()
+
+	e, err := New(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = e.EmbedQuery(context.Background(), "q")
+	require.ErrorIs(t, err, llms.ErrInvalidRequest)
+}
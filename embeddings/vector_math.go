@@ -2,6 +2,7 @@ package embeddings
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
@@ -12,6 +13,9 @@ var (
 	// ErrAllTextsLenZero is returned if all texts to be embedded has the combined
 	// length of zero.
 	ErrAllTextsLenZero = errors.New("all texts have length 0")
+	// ErrVectorTooShort is returned when truncating a vector to more
+	// dimensions than it has.
+	ErrVectorTooShort = errors.New("vector shorter than the requested dimensions")
 )
 
 func CombineVectors(vectors [][]float32, weights []int) ([]float32, error) {
@@ -81,3 +85,26 @@ func getNorm(v []float32) float32 {
 
 	return float32(math.Sqrt(float64(sum)))
 }
+
+// TruncateVector returns the first dimensions values of v, e.g. to shorten
+// the embeddings of models trained with Matryoshka Representation Learning.
+func TruncateVector(v []float32, dimensions int) ([]float32, error) {
+	if len(v) < dimensions {
+		return nil, fmt.Errorf("%w: %d < %d", ErrVectorTooShort, len(v), dimensions)
+	}
+	return v[:dimensions:dimensions], nil
+}
+
+// NormalizeVector returns v scaled to a unit L2 norm. A zero vector is
+// returned unchanged.
+func NormalizeVector(v []float32) []float32 {
+	norm := getNorm(v)
+	if norm == 0 {
+		return v
+	}
+	normalized := make([]float32, len(v))
+	for i := range v {
+		normalized[i] = v[i] / norm
+	}
+	return normalized
+}
@@ -0,0 +1,148 @@
+package voyageai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// DefaultRerankModel is the default model of the Reranker.
+const DefaultRerankModel = "rerank-2"
+
+var _ schema.Reranker = &Reranker{}
+
+// Reranker reorders documents by their relevance to a query with the
+// VoyageAI rerank API.
+type Reranker struct {
+	api   *VoyageAI
+	Model string
+	// TopK is the number of documents returned. If zero, all documents are
+	// returned.
+	TopK int
+}
+
+// RerankerOption is a function type that can be used to modify the reranker.
+type RerankerOption func(r *Reranker)
+
+// WithRerankModel is an option for providing the reranker model name to use.
+func WithRerankModel(model string) RerankerOption {
+	return func(r *Reranker) {
+		r.Model = model
+	}
+}
+
+// WithRerankBaseURL is an option for providing the base URL of the VoyageAI API.
+func WithRerankBaseURL(baseURL string) RerankerOption {
+	return func(r *Reranker) {
+		r.api.baseURL = baseURL
+	}
+}
+
+// WithRerankToken is an option for providing the VoyageAI token.
+func WithRerankToken(token string) RerankerOption {
+	return func(r *Reranker) {
+		r.api.token = token
+	}
+}
+
+// WithRerankClient is an option for providing a custom http client.
+func WithRerankClient(client *http.Client) RerankerOption {
+	return func(r *Reranker) {
+		r.api.client = client
+	}
+}
+
+// WithTopK is an option for specifying the number of documents returned.
+func WithTopK(topK int) RerankerOption {
+	return func(r *Reranker) {
+		r.TopK = topK
+	}
+}
+
+// NewReranker returns a new VoyageAI reranker.
+// The default model is "rerank-2". Use `WithRerankModel` to change the model.
+func NewReranker(opts ...RerankerOption) (*Reranker, error) {
+	r := &Reranker{
+		api: &VoyageAI{
+			baseURL: _defaultBaseURL,
+			client:  http.DefaultClient,
+		},
+		Model: DefaultRerankModel,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.api.token == "" {
+		r.api.token = os.Getenv("VOYAGEAI_API_KEY")
+	}
+	if r.api.token == "" {
+		return nil, errors.New("missing the VoyageAI API key, set it as VOYAGEAI_API_KEY environment variable")
+	}
+	return r, nil
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopK      int      `json:"top_k,omitempty"`
+}
+
+type rerankResponse struct {
+	Data []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"data"`
+}
+
+// Rerank returns the documents ordered by decreasing relevance to the query,
+// with their Score set to the relevance score.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	resp, err := r.api.request(ctx, "/rerank", rerankRequest{
+		Model:     r.Model,
+		Query:     query,
+		Documents: texts,
+		TopK:      r.TopK,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rerank request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, r.api.decodeError(resp)
+	}
+
+	var rerankResp rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rerankResp); err != nil {
+		return nil, err
+	}
+
+	result := make([]schema.Document, 0, len(rerankResp.Data))
+	for _, res := range rerankResp.Data {
+		if res.Index < 0 || res.Index >= len(docs) {
+			continue
+		}
+		doc := docs[res.Index]
+		doc.Score = float32(res.RelevanceScore)
+		result = append(result, doc)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
@@ -2,11 +2,15 @@ package voyageai
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
 )
 
 func TestVoyageAIEmbeddings(t *testing.T) {
@@ -25,3 +29,25 @@ func TestVoyageAIEmbeddings(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, embeddings, 3)
 }
+
+func TestVoyageAIReranker(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rerank", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req rerankRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"a", "b", "c"}, req.Documents)
+		assert.Equal(t, 2, req.TopK)
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"relevance_score":0.8},{"index":2,"relevance_score":0.3}]}`))
+	}))
+	defer server.Close()
+
+	r, err := NewReranker(WithRerankBaseURL(server.URL), WithRerankToken("token"), WithTopK(2))
+	require.NoError(t, err)
+	docs, err := r.Rerank(context.Background(), "q", []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "b", Score: 0.8}, {PageContent: "c", Score: 0.3}}, docs)
+}
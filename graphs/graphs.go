@@ -0,0 +1,17 @@
+// Package graphs contains the interface of the graph databases queried by
+// chains, e.g. the graph Cypher QA chain, and its implementations in the
+// subpackages.
+package graphs
+
+import "context"
+
+// Graph is a graph database queried with Cypher.
+type Graph interface {
+	// Query runs the read-only query with the parameters and returns the
+	// records, mapping the fields returned to their values.
+	Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+	// Schema returns the description of the schema of the graph, i.e. the
+	// properties of the nodes and relationships and the relationships
+	// between node labels, given to LLMs to write queries.
+	Schema(ctx context.Context) (string, error)
+}
@@ -0,0 +1,4 @@
+// Package neo4j contains an implementation of the Graph interface for Neo4j,
+// using the Query API of the HTTP server, available since Neo4j 5.19, so that
+// no driver is needed.
+package neo4j
@@ -0,0 +1,231 @@
+package neo4j
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/graphs"
+)
+
+// ErrAPI is returned when the Neo4j server returns an error.
+var ErrAPI = errors.New("neo4j api error")
+
+// Graph is a Neo4j graph database, queried with the Query API of the HTTP
+// server. Queries run in read access mode, so that they can't modify the
+// graph. The schema is loaded on the first Schema call and cached.
+type Graph struct {
+	url              string
+	database         string
+	username         string
+	password         string
+	client           *http.Client
+	schemaSampleSize int
+
+	mu     sync.Mutex
+	schema string
+}
+
+var _ graphs.Graph = &Graph{}
+
+// New creates a new Neo4j graph with options.
+func New(opts ...Option) *Graph {
+	return applyClientOptions(opts...)
+}
+
+// Query runs the query with the parameters in read access mode.
+func (g *Graph) Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	body, err := json.Marshal(map[string]any{
+		"statement":  query,
+		"parameters": params,
+		"accessMode": "Read",
+	})
+	if err != nil {
+		return nil, err
+	}
+	u := g.url + "/db/" + url.PathEscape(g.database) + "/query/v2"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if g.username != "" || g.password != "" {
+		req.SetBasicAuth(g.username, g.password)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data struct {
+			Fields []string `json:"fields"`
+			Values [][]any  `json:"values"`
+		} `json:"data"`
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%w: status code %d: %s", ErrAPI, resp.StatusCode, data)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("%w: %s: %s", ErrAPI, result.Errors[0].Code, result.Errors[0].Message)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%w: status code %d: %s", ErrAPI, resp.StatusCode, data)
+	}
+
+	records := make([]map[string]any, len(result.Data.Values))
+	for i, values := range result.Data.Values {
+		records[i] = make(map[string]any, len(result.Data.Fields))
+		for j, field := range result.Data.Fields {
+			if j < len(values) {
+				records[i][field] = values[j]
+			}
+		}
+	}
+	return records, nil
+}
+
+// Schema returns the schema of the graph, loading it if it isn't cached.
+func (g *Graph) Schema(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.schema != "" {
+		return g.schema, nil
+	}
+	schema, err := g.loadSchema(ctx)
+	if err != nil {
+		return "", err
+	}
+	g.schema = schema
+	return schema, nil
+}
+
+// RefreshSchema reloads the schema of the graph, e.g. after new labels or
+// properties were added.
+func (g *Graph) RefreshSchema(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	schema, err := g.loadSchema(ctx)
+	if err != nil {
+		return err
+	}
+	g.schema = schema
+	return nil
+}
+
+// loadSchema describes the properties of the nodes and relationships, from
+// the schema procedures of the database, and the relationships between node
+// labels, from a sample of the relationships.
+func (g *Graph) loadSchema(ctx context.Context) (string, error) {
+	nodes, err := g.Query(ctx, `CALL db.schema.nodeTypeProperties()
+YIELD nodeLabels, propertyName, propertyTypes
+RETURN nodeLabels, propertyName, propertyTypes`, nil)
+	if err != nil {
+		return "", err
+	}
+	relationships, err := g.Query(ctx, `CALL db.schema.relTypeProperties()
+YIELD relType, propertyName, propertyTypes
+RETURN relType, propertyName, propertyTypes`, nil)
+	if err != nil {
+		return "", err
+	}
+	patterns, err := g.Query(ctx, `MATCH (a)-[r]->(b)
+WITH labels(a) AS source, type(r) AS type, labels(b) AS target LIMIT $limit
+RETURN DISTINCT source, type, target`, map[string]any{"limit": g.schemaSampleSize})
+	if err != nil {
+		return "", err
+	}
+
+	nodeProperties := properties(nodes, func(r map[string]any) string {
+		return strings.Join(toStrings(r["nodeLabels"]), ":")
+	})
+	relationshipProperties := properties(relationships, func(r map[string]any) string {
+		relType, _ := r["relType"].(string)
+		return strings.Trim(strings.TrimPrefix(relType, ":"), "`")
+	})
+
+	var b strings.Builder
+	b.WriteString("Node properties:\n")
+	b.WriteString(nodeProperties)
+	b.WriteString("Relationship properties:\n")
+	b.WriteString(relationshipProperties)
+	b.WriteString("The relationships:\n")
+	lines := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		relType, _ := p["type"].(string)
+		for _, source := range toStrings(p["source"]) {
+			for _, target := range toStrings(p["target"]) {
+				lines = append(lines, fmt.Sprintf("(:%s)-[:%s]->(:%s)", source, relType, target))
+			}
+		}
+	}
+	writeSortedLines(&b, lines)
+	return b.String(), nil
+}
+
+// properties describes the properties of the records of the schema
+// procedures, by label or type, e.g. "Person {name: String, born: Long}".
+func properties(records []map[string]any, name func(map[string]any) string) string {
+	byName := map[string][]string{}
+	for _, r := range records {
+		n := name(r)
+		if n == "" {
+			continue
+		}
+		if _, ok := byName[n]; !ok {
+			byName[n] = []string{}
+		}
+		if property, ok := r["propertyName"].(string); ok && property != "" {
+			byName[n] = append(byName[n], property+": "+strings.Join(toStrings(r["propertyTypes"]), "|"))
+		}
+	}
+	lines := make([]string, 0, len(byName))
+	for n, props := range byName {
+		sort.Strings(props)
+		lines = append(lines, n+" {"+strings.Join(props, ", ")+"}")
+	}
+	var b strings.Builder
+	writeSortedLines(&b, lines)
+	return b.String()
+}
+
+func writeSortedLines(b *strings.Builder, lines []string) {
+	sort.Strings(lines)
+	for i, line := range lines {
+		if i > 0 && line == lines[i-1] {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
+
+func toStrings(value any) []string {
+	values, _ := value.([]any)
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,67 @@
+package neo4j_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/graphs/neo4j"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/movies/query/v2", r.URL.Path)
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "neo4j:secret", username+":"+password)
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+
+		w.WriteHeader(http.StatusAccepted)
+		statement, _ := body["statement"].(string)
+		switch {
+		case strings.Contains(statement, "nodeTypeProperties"):
+			_, _ = w.Write([]byte(`{"data": {"fields": ["nodeLabels", "propertyName", "propertyTypes"], "values": [
+				[["Person"], "name", ["String"]], [["Person"], "born", ["Long"]], [["Movie"], "title", ["String"]]]}}`))
+		case strings.Contains(statement, "relTypeProperties"):
+			_, _ = w.Write([]byte(`{"data": {"fields": ["relType", "propertyName", "propertyTypes"], "values": [
+				[":` + "`ACTED_IN`" + `", "roles", ["StringArray"]]]}}`))
+		case strings.Contains(statement, "MATCH (a)-[r]->(b)"):
+			_, _ = w.Write([]byte(`{"data": {"fields": ["source", "type", "target"], "values": [
+				[["Person"], "ACTED_IN", ["Movie"]]]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"errors": [{"code": "Neo.ClientError.Statement.SyntaxError", "message": "Invalid input"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	g := neo4j.New(neo4j.WithURL(server.URL+"/"), neo4j.WithDatabase("movies"), neo4j.WithAuth("neo4j", "secret"))
+	schema, err := g.Schema(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, `Node properties:
+Movie {title: String}
+Person {born: Long, name: String}
+Relationship properties:
+ACTED_IN {roles: StringArray}
+The relationships:
+(:Person)-[:ACTED_IN]->(:Movie)
+`, schema)
+	assert.Equal(t, "Read", requests[0]["accessMode"])
+
+	// the schema is cached.
+	_, err = g.Schema(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, requests, 3)
+
+	_, err = g.Query(context.Background(), "MATC (n) RETURN n", nil)
+	require.ErrorIs(t, err, neo4j.ErrAPI)
+	require.ErrorContains(t, err, "Invalid input")
+}
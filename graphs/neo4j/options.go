@@ -0,0 +1,84 @@
+package neo4j
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultURL is the default URL of the HTTP API of the Neo4j server.
+	DefaultURL = "http://localhost:7474"
+	// DefaultDatabase is the default name of the database.
+	DefaultDatabase = "neo4j"
+	// UsernameEnvVarName is the environment variable read for the username.
+	UsernameEnvVarName = "NEO4J_USERNAME"
+	// PasswordEnvVarName is the environment variable read for the password.
+	PasswordEnvVarName = "NEO4J_PASSWORD"
+
+	_defaultSchemaSampleSize = 10000
+)
+
+// Option is a function type that can be used to modify the graph.
+type Option func(g *Graph)
+
+// WithURL is an option for specifying the URL of the HTTP API of the Neo4j
+// server.
+func WithURL(url string) Option {
+	return func(g *Graph) {
+		g.url = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithDatabase is an option for specifying the name of the database.
+func WithDatabase(database string) Option {
+	return func(g *Graph) {
+		g.database = database
+	}
+}
+
+// WithAuth is an option for specifying the credentials of the basic
+// authentication. Defaults to the NEO4J_USERNAME and NEO4J_PASSWORD
+// environment variables.
+func WithAuth(username, password string) Option {
+	return func(g *Graph) {
+		g.username = username
+		g.password = password
+	}
+}
+
+// WithHTTPClient is an option for providing a custom http client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(g *Graph) {
+		g.client = client
+	}
+}
+
+// WithSchemaSampleSize is an option for specifying the number of
+// relationships sampled to find the relationships between node labels of the
+// schema. Defaults to 10000.
+func WithSchemaSampleSize(size int) Option {
+	return func(g *Graph) {
+		g.schemaSampleSize = size
+	}
+}
+
+func applyClientOptions(opts ...Option) *Graph {
+	g := &Graph{
+		url:              DefaultURL,
+		database:         DefaultDatabase,
+		schemaSampleSize: _defaultSchemaSampleSize,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.username == "" && g.password == "" {
+		g.username = os.Getenv(UsernameEnvVarName)
+		g.password = os.Getenv(PasswordEnvVarName)
+	}
+	if g.client == nil {
+		g.client = http.DefaultClient
+	}
+	return g
+}
@@ -0,0 +1,13 @@
+// Package httprecord records HTTP interactions with LLM providers to golden
+// files and replays them in tests. Secrets such as API keys are scrubbed
+// before interactions are saved, so the golden files can be committed.
+//
+// A typical provider test records once against the real API:
+//
+//	HTTPRECORD_MODE=record go test ./llms/openai/...
+//
+// and replays the golden file without network access afterwards:
+//
+//	rec := httprecord.NewTest(t, "testdata/chat.json", httprecord.WithMode(httprecord.ModeFromEnv()))
+//	llm, err := openai.New(openai.WithHTTPClient(rec.Client()), openai.WithToken("fake"))
+package httprecord
@@ -0,0 +1,338 @@
+package httprecord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ModeEnvVarName is the environment variable selecting the mode of recorders
+// created with ModeFromEnv.
+const ModeEnvVarName = "HTTPRECORD_MODE"
+
+const _redacted = "REDACTED"
+
+// ErrNoInteraction is returned when replaying a request that was not recorded.
+var ErrNoInteraction = errors.New("httprecord: no recorded interaction matches the request")
+
+// Mode is the mode of a Recorder.
+type Mode string
+
+const (
+	// ModeReplay replays recorded interactions without network access.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests to the network and records the interactions,
+	// overwriting the golden file.
+	ModeRecord Mode = "record"
+	// ModeRecordMissing replays the golden file if it exists and records it
+	// otherwise.
+	ModeRecordMissing Mode = "record-missing"
+)
+
+// ModeFromEnv returns the mode set in the HTTPRECORD_MODE environment
+// variable, defaulting to ModeReplay.
+func ModeFromEnv() Mode {
+	if mode := Mode(os.Getenv(ModeEnvVarName)); mode != "" {
+		return mode
+	}
+	return ModeReplay
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Scrubber modifies an interaction before it is saved, e.g. to remove
+// secrets.
+type Scrubber func(*Interaction)
+
+// Matcher reports whether a recorded request matches an outgoing request.
+type Matcher func(recorded Request, req Request) bool
+
+// Recorder is an http.RoundTripper that records interactions to a golden file
+// or replays them from it.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubbers []Scrubber
+	matcher   Matcher
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// New creates a Recorder for the golden file at path. In replay mode the file
+// is loaded immediately.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      ModeReplay,
+		transport: http.DefaultTransport,
+		scrubbers: []Scrubber{RedactHeaders(defaultSecretHeaders...), RedactQueryParams(defaultSecretParams...)},
+		matcher:   DefaultMatcher,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeRecordMissing {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+	if r.mode == ModeReplay {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// NewTest creates a Recorder for a test. It fails the test if the golden file
+// can't be loaded and saves the recording when the test finishes.
+func NewTest(t testing.TB, path string, opts ...Option) *Recorder {
+	t.Helper()
+	r, err := New(path, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := r.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return r
+}
+
+// Mode returns the effective mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client using the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recordedReq := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recordedReq)
+	}
+	return r.record(req, recordedReq)
+}
+
+// Close saves the recorded interactions in record mode.
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+func (r *Recorder) replay(req *http.Request, recordedReq Request) (*http.Response, error) {
+	// requests are scrubbed the same way as recorded ones before matching.
+	scrubbed := Interaction{Request: recordedReq}
+	for _, scrub := range r.scrubbers {
+		scrub(&scrubbed)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.used[i] || !r.matcher(interaction.Request, scrubbed.Request) {
+			continue
+		}
+		r.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recordedReq Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: recordedReq,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(respBody),
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("httprecord: load %s: %w", r.path, err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return fmt.Errorf("httprecord: parse %s: %w", r.path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return nil
+}
+
+func (resp Response) toHTTP(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// DefaultMatcher matches requests by method, URL and body.
+func DefaultMatcher(recorded Request, req Request) bool {
+	return recorded.Method == req.Method && recorded.URL == req.URL && recorded.Body == req.Body
+}
+
+// nolint:gochecknoglobals
+var (
+	defaultSecretHeaders = []string{
+		"Authorization", "X-Api-Key", "Api-Key", "X-Goog-Api-Key", "Openai-Organization", "Cookie", "Set-Cookie",
+	}
+	defaultSecretParams = []string{"key", "api_key", "apikey", "token"}
+)
+
+// RedactHeaders returns a Scrubber replacing the values of the given request
+// and response headers.
+func RedactHeaders(names ...string) Scrubber {
+	return func(i *Interaction) {
+		for _, name := range names {
+			redactHeader(i.Request.Header, name)
+			redactHeader(i.Response.Header, name)
+		}
+	}
+}
+
+func redactHeader(header http.Header, name string) {
+	if header.Get(name) != "" {
+		header.Set(name, _redacted)
+	}
+}
+
+// RedactQueryParams returns a Scrubber replacing the values of the given URL
+// query parameters.
+func RedactQueryParams(names ...string) Scrubber {
+	return func(i *Interaction) {
+		u, err := url.Parse(i.Request.URL)
+		if err != nil {
+			return
+		}
+		query := u.Query()
+		changed := false
+		for _, name := range names {
+			if query.Has(name) {
+				query.Set(name, _redacted)
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+			i.Request.URL = u.String()
+		}
+	}
+}
+
+// ReplaceString returns a Scrubber replacing all occurrences of a secret in
+// the URLs and bodies of an interaction.
+func ReplaceString(secret, replacement string) Scrubber {
+	return func(i *Interaction) {
+		if secret == "" {
+			return
+		}
+		i.Request.URL = strings.ReplaceAll(i.Request.URL, secret, replacement)
+		i.Request.Body = strings.ReplaceAll(i.Request.Body, secret, replacement)
+		i.Response.Body = strings.ReplaceAll(i.Response.Body, secret, replacement)
+	}
+}
@@ -0,0 +1,55 @@
+package httprecord
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("echo " + string(body)))
+	}))
+	path := filepath.Join(t.TempDir(), "golden.json")
+
+	rec, err := New(path, WithMode(ModeRecord), WithScrubbers(ReplaceString("s3cret", "SECRET")))
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/chat?key=abc", strings.NewReader("hi s3cret"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer abc")
+	resp, err := rec.Client().Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "echo hi s3cret", string(body))
+	require.NoError(t, rec.Close())
+	server.Close()
+
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(golden), "s3cret")
+	require.NotContains(t, string(golden), "Bearer abc")
+	require.NotContains(t, string(golden), "key=abc")
+
+	rec, err = New(path, WithMode(ModeRecordMissing), WithScrubbers(ReplaceString("s3cret", "SECRET")))
+	require.NoError(t, err)
+	require.Equal(t, ModeReplay, rec.Mode())
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/chat?key=other", strings.NewReader("hi s3cret"))
+	require.NoError(t, err)
+	resp, err = rec.Client().Do(req)
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "echo hi SECRET", string(body))
+
+	_, err = rec.Client().Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, ErrNoInteraction)
+}
@@ -0,0 +1,37 @@
+package httprecord
+
+import "net/http"
+
+// Option is a function that configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the mode of the recorder. Defaults to ModeReplay.
+func WithMode(mode Mode) Option {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithTransport sets the transport used to send requests when recording.
+// Defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubbers adds scrubbers applied to interactions before they are saved.
+// Secret headers and query parameters are always redacted.
+func WithScrubbers(scrubbers ...Scrubber) Option {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, scrubbers...)
+	}
+}
+
+// WithMatcher sets the function matching outgoing requests against recorded
+// ones. Defaults to DefaultMatcher.
+func WithMatcher(matcher Matcher) Option {
+	return func(r *Recorder) {
+		r.matcher = matcher
+	}
+}
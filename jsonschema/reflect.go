@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// For returns the definition of the JSON encoding of values of type T. See
+// Reflect.
+func For[T any]() Definition {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect returns the definition of the JSON encoding of values of the type.
+// Struct fields are named after their json tag and are required unless the
+// tag has the omitempty option or the field is a pointer. The description tag
+// of a field sets its description, and its enum tag, a comma separated list,
+// its allowed values. Recursive types are defined as objects without
+// properties past their first occurrence.
+func Reflect(t reflect.Type) Definition {
+	return reflectType(t, map[reflect.Type]bool{})
+}
+
+//nolint:gochecknoglobals
+var (
+	_timeType          = reflect.TypeOf(time.Time{})
+	_textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func reflectType(t reflect.Type, seen map[reflect.Type]bool) Definition {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == _timeType || t.Implements(_textMarshalerType) {
+		return Definition{Type: String}
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return Definition{Type: Boolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}
+	case reflect.String:
+		return Definition{Type: String}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as base64 strings.
+			return Definition{Type: String}
+		}
+		items := reflectType(t.Elem(), seen)
+		return Definition{Type: Array, Items: &items}
+	case reflect.Map:
+		return Definition{Type: Object}
+	case reflect.Struct:
+		if seen[t] {
+			return Definition{Type: Object}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		d := Definition{Type: Object, Properties: map[string]Definition{}}
+		reflectFields(t, seen, &d)
+		return d
+	default:
+		// interfaces accept any value.
+		return Definition{}
+	}
+}
+
+func reflectFields(t reflect.Type, seen map[reflect.Type]bool, d *Definition) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				reflectFields(embedded, seen, d)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := reflectType(field.Type, seen)
+		property.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			property.Enum = strings.Split(enum, ",")
+		}
+		d.Properties[name] = property
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			d.Required = append(d.Required, name)
+		}
+	}
+}
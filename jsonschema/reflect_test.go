@@ -0,0 +1,48 @@
+package jsonschema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+type address struct {
+	City    string `json:"city" description:"The city"`
+	Country string `json:"country,omitempty"`
+}
+
+type person struct {
+	address
+	Name     string            `json:"name" description:"The full name"`
+	Age      *int              `json:"age"`
+	Role     string            `json:"role" enum:"admin,user"`
+	Tags     []string          `json:"tags,omitempty"`
+	Born     time.Time         `json:"born,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Manager  *person           `json:"manager,omitempty"`
+	Internal string            `json:"-"`
+	private  string
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, jsonschema.Definition{Type: jsonschema.Integer}, jsonschema.For[int]())
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city":    {Type: jsonschema.String, Description: "The city"},
+			"country": {Type: jsonschema.String},
+			"name":    {Type: jsonschema.String, Description: "The full name"},
+			"age":     {Type: jsonschema.Integer},
+			"role":    {Type: jsonschema.String, Enum: []string{"admin", "user"}},
+			"tags":    {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}},
+			"born":    {Type: jsonschema.String},
+			"labels":  {Type: jsonschema.Object},
+			"manager": {Type: jsonschema.Object},
+		},
+		Required: []string{"city", "name", "role"},
+	}, jsonschema.For[person]())
+}
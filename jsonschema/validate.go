@@ -0,0 +1,116 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalid is returned by Validate when a value doesn't match a definition.
+var ErrInvalid = errors.New("value doesn't match the schema")
+
+// Validate checks that the JSON data matches the definition: the types, the
+// enums, the required properties of objects and the items of arrays. Other
+// keywords aren't supported. Optional properties may be null.
+func (d Definition) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("%w: unexpected data after the value", ErrInvalid)
+	}
+	return d.validate(value, "$")
+}
+
+func (d Definition) validate(value any, path string) error { //nolint:cyclop
+	switch d.Type {
+	case Object:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return typeError(path, d.Type, value)
+		}
+		for _, name := range d.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%w: %s: missing required property %q", ErrInvalid, path, name)
+			}
+		}
+		for name, property := range d.Properties {
+			propertyValue, ok := object[name]
+			if !ok || propertyValue == nil && !slices.Contains(d.Required, name) {
+				continue
+			}
+			if err := property.validate(propertyValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case Array:
+		array, ok := value.([]any)
+		if !ok {
+			return typeError(path, d.Type, value)
+		}
+		if d.Items == nil {
+			return nil
+		}
+		for i, item := range array {
+			if err := d.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case String:
+		s, ok := value.(string)
+		if !ok {
+			return typeError(path, d.Type, value)
+		}
+		if len(d.Enum) > 0 && !slices.Contains(d.Enum, s) {
+			return fmt.Errorf("%w: %s: %q is not one of %s", ErrInvalid, path, s, strings.Join(d.Enum, ", "))
+		}
+	case Number:
+		if _, ok := value.(json.Number); !ok {
+			return typeError(path, d.Type, value)
+		}
+	case Integer:
+		n, ok := value.(json.Number)
+		if !ok {
+			return typeError(path, d.Type, value)
+		}
+		if _, err := n.Int64(); err != nil {
+			return typeError(path, d.Type, value)
+		}
+	case Boolean:
+		if _, ok := value.(bool); !ok {
+			return typeError(path, d.Type, value)
+		}
+	case Null:
+		if value != nil {
+			return typeError(path, d.Type, value)
+		}
+	}
+	return nil
+}
+
+func typeError(path string, expected DataType, value any) error {
+	return fmt.Errorf("%w: %s: expected %s, got %s", ErrInvalid, path, expected, jsonType(value))
+}
+
+func jsonType(value any) DataType {
+	switch value.(type) {
+	case map[string]any:
+		return Object
+	case []any:
+		return Array
+	case string:
+		return String
+	case json.Number:
+		return Number
+	case bool:
+		return Boolean
+	default:
+		return Null
+	}
+}
@@ -0,0 +1,42 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+func TestDefinitionValidate(t *testing.T) {
+	t.Parallel()
+
+	definition := jsonschema.For[person]()
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "valid", data: `{"city": "Paris", "name": "Ann", "age": 30, "role": "admin", "tags": ["a"]}`},
+		{name: "null optional", data: `{"city": "Paris", "name": "Ann", "age": null, "role": "user", "manager": null}`},
+		{name: "not an object", data: `["Ann"]`, err: "$: expected object, got array"},
+		{name: "missing", data: `{"city": "Paris", "age": 30, "role": "admin"}`, err: `$: missing required property "name"`},
+		{name: "wrong type", data: `{"city": "Paris", "name": 1, "age": 30, "role": "admin"}`, err: "$.name: expected string, got number"},
+		{name: "not integer", data: `{"city": "Paris", "name": "Ann", "age": 30.5, "role": "admin"}`, err: "$.age: expected integer, got number"}, //nolint:lll
+		{name: "enum", data: `{"city": "Paris", "name": "Ann", "age": 30, "role": "root"}`, err: `$.role: "root" is not one of admin, user`},
+		{name: "items", data: `{"city": "Paris", "name": "Ann", "age": 30, "role": "admin", "tags": [1]}`, err: "$.tags[0]: expected string, got number"}, //nolint:lll
+		{name: "invalid json", data: `{"city": `, err: "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := definition.Validate([]byte(tt.data))
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, jsonschema.ErrInvalid)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -34,7 +34,15 @@ type LLM struct {
     client           *anthropicclient.Client
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+    _ llms.Model        = (*LLM)(nil)
+    _ llms.TokenCounter = (*LLM)(nil)
+    _ llms.ContextSizer = (*LLM)(nil)
+)
+
+// _contextSize is the context window of Claude models missing from the model
+// registry.
+const _contextSize = 200000
 
 // New returns a new Anthropic LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -61,6 +69,7 @@ func newClient(opts ...Option) (*anthropicclient.Client, error) {
 
     return anthropicclient.New(options.token, options.model, options.baseURL,
         anthropicclient.WithAnthropicVersion(options.anthropicVersion),
+        anthropicclient.WithAnthropicBeta(options.betas...),
         anthropicclient.WithVertexProjectID(options.vertexProjectID),
         anthropicclient.WithVertexLocation(options.vertexLocation),
         anthropicclient.WithHTTPClient(options.httpClient),
@@ -83,6 +92,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
     for _, opt := range options {
         opt(opts)
     }
+    ctx = llms.ContextWithRequestOptions(ctx, *opts)
 
     if o.client.UseLegacyTextCompletionsAPI {
         return generateCompletionsContent(ctx, o, messages, opts)
@@ -105,7 +115,7 @@ func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.Mes
         StopWords:     opts.StopWords,
         Temperature:   opts.Temperature,
         TopP:          opts.TopP,
-        StreamingFunc: opts.StreamingFunc,
+        StreamingFunc: llms.TextStreamingFunc(*opts),
     })
     if err != nil {
         if o.CallbacksHandler != nil {
@@ -131,17 +141,20 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
     }
 
     result, err := o.client.CreateMessage(ctx, &anthropicclient.MessageRequest{
-        Model:         opts.Model,
-        Messages:      chatMessages,
-        System:        systemPrompt,
-        MaxTokens:     opts.MaxTokens,
-        StopWords:     opts.StopWords,
-        Temperature:   opts.Temperature,
-        TopP:          opts.TopP,
-        StreamingFunc: opts.StreamingFunc,
-        TopK:          opts.TopK,
-        Tools:         opts.Tools,
-        ToolChoice:    opts.ToolChoice,
+        Model:              opts.Model,
+        Messages:           chatMessages,
+        System:             systemPrompt,
+        MaxTokens:          opts.MaxTokens,
+        StopWords:          opts.StopWords,
+        Temperature:        opts.Temperature,
+        TopP:               opts.TopP,
+        StreamingFunc:      opts.StreamingFunc,
+        StreamingEventFunc: opts.StreamingEventFunc,
+        TopK:               opts.TopK,
+        Tools:              opts.Tools,
+        ToolChoice:         opts.ToolChoice,
+        ThinkingBudget:     opts.ThinkingBudget,
+        RawParams:          opts.RawParams,
     })
     if err != nil {
         if o.CallbacksHandler != nil {
@@ -153,16 +166,30 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
         return nil, ErrEmptyResponse
     }
 
-    choices := make([]*llms.ContentChoice, len(result.Content))
-    for i, content := range result.Content {
-        choices[i] = &llms.ContentChoice{
-            Content:    content.Text,
-            StopReason: result.StopReason,
+    choices := make([]*llms.ContentChoice, 0, len(result.Content))
+    // thinking blocks are attached to the choice of the following text block.
+    var reasoning string
+    for _, content := range result.Content {
+        if content.Type == "thinking" {
+            reasoning += content.Thinking
+            continue
+        }
+        choices = append(choices, &llms.ContentChoice{
+            Content:          content.Text,
+            StopReason:       result.StopReason,
+            ReasoningContent: reasoning,
             GenerationInfo: map[string]any{
                 "InputTokens":  result.Usage.InputTokens,
                 "OutputTokens": result.Usage.OutputTokens,
             },
-        }
+        })
+        reasoning = ""
+    }
+    if reasoning != "" {
+        choices = append(choices, &llms.ContentChoice{
+            StopReason:       result.StopReason,
+            ReasoningContent: reasoning,
+        })
     }
 
     resp := &llms.ContentResponse{
@@ -178,6 +205,28 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
     return resp, nil
 }
 
+// CountTokens implements the [llms.TokenCounter] interface using the
+// count_tokens API.
+func (o *LLM) CountTokens(ctx context.Context, messages []llms.MessageContent) (int, error) {
+    chatMessages, systemPrompt, err := processMessages(messages)
+    if err != nil {
+        return 0, err
+    }
+    return o.client.CountTokens(ctx, &anthropicclient.CountTokensRequest{
+        Messages: chatMessages,
+        System:   systemPrompt,
+    })
+}
+
+// MaxContextTokens implements the [llms.ContextSizer] interface using the
+// model registry.
+func (o *LLM) MaxContextTokens() int {
+    if info, ok := llms.LookupModel(o.client.Model); ok {
+        return info.ContextWindow
+    }
+    return _contextSize
+}
+
 func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMessage, string, error) {
     chatMessages := make([]anthropicclient.ChatMessage, 0, len(messages))
     systemPrompt := ""
@@ -252,6 +301,22 @@ func handleContentPart(parts []llms.ContentPart) (aparts []anthropicclient.Conte
                 Type:        "image",
                 ImageSource: imageSource,
             })
+        case llms.AudioContent:
+            if part.Transcript == "" {
+                return nil, llms.UnsupportedContentPartError(part)
+            }
+            aparts = append(aparts, anthropicclient.ContentPart{
+                Type: "text",
+                Text: part.Transcript,
+            })
+        case llms.VideoContent:
+            if part.Transcript == "" {
+                return nil, llms.UnsupportedContentPartError(part)
+            }
+            aparts = append(aparts, anthropicclient.ContentPart{
+                Type: "text",
+                Text: part.Transcript,
+            })
         }
     }
     return aparts, nil
@@ -17,6 +17,7 @@ type options struct {
     vertexProjectID  string
     vertexLocation   string
     anthropicVersion string
+    betas            []string
 
     useLegacyTextCompletionsAPI bool
 }
@@ -67,6 +68,15 @@ func WithAnthropicVersion(version string) Option {
     }
 }
 
+// WithAnthropicBeta enables beta features of the Anthropic API, e.g.
+// "prompt-caching-2024-07-31". The beta feature of the token counting API is
+// always enabled for its requests.
+func WithAnthropicBeta(features ...string) Option {
+    return func(c *options) {
+        c.betas = append(c.betas, features...)
+    }
+}
+
 // WithHTTPClient allows setting a custom HTTP client. If not set, the default value
 // is http.DefaultClient.
 func WithHTTPClient(client anthropicclient.Doer) Option {
@@ -0,0 +1,152 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages/count_tokens", r.URL.Path)
+		assert.Equal(t, "prompt-caching-2024-07-31,token-counting-2024-11-01", r.Header.Get("anthropic-beta"))
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "claude-3-5-sonnet-20241022", payload["model"])
+		assert.Equal(t, "Be brief.", payload["system"])
+		_, _ = w.Write([]byte(`{"input_tokens": 14}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(
+		WithToken("test"),
+		WithModel("claude-3-5-sonnet-20241022"),
+		WithBaseURL(server.URL),
+		WithAnthropicBeta("prompt-caching-2024-07-31"),
+	)
+	require.NoError(t, err)
+
+	tokens, err := llm.CountTokens(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "Be brief."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "How many feet are in a nautical mile?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 14, tokens)
+}
+
+func TestMaxContextTokens(t *testing.T) {
+	t.Parallel()
+	for model, want := range map[string]int{
+		"claude-3-5-sonnet-20241022": 200000,
+		"claude-2.1":                 200000,
+		"claude-2.0":                 100000,
+		"claude-instant-1.2":         100000,
+	} {
+		llm, err := New(WithToken("test"), WithModel(model))
+		require.NoError(t, err)
+		assert.Equal(t, want, llm.MaxContextTokens(), model)
+	}
+}
+
+func TestStreamingEvents(t *testing.T) {
+	t.Parallel()
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":10,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Look it up."}}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Checking."}}`,
+		`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`,
+		`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}`,
+		`{"type":"message_stop"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, true, payload["stream"])
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			_, _ = w.Write([]byte("data: " + event + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test"), WithModel("claude-3-5-sonnet-20241022"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var got []llms.StreamingEvent
+	var text strings.Builder
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris?")},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			text.Write(chunk)
+			return nil
+		}),
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamingEvent) error {
+			got = append(got, event)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Checking.", text.String())
+	assert.Equal(t, "Look it up.", resp.Choices[0].ReasoningContent)
+	assert.Equal(t, []llms.StreamingEvent{
+		{Type: llms.StreamingEventReasoningDelta, Text: "Look it up."},
+		{Type: llms.StreamingEventTextDelta, Text: "Checking."},
+		{Type: llms.StreamingEventToolCallDelta, ToolCall: &llms.ToolCall{
+			ID: "toolu_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "get_weather"},
+		}},
+		{Type: llms.StreamingEventToolCallDelta, ToolCall: &llms.ToolCall{
+			FunctionCall: &llms.FunctionCall{Arguments: `{"city":`},
+		}},
+		{Type: llms.StreamingEventToolCallDelta, ToolCall: &llms.ToolCall{
+			FunctionCall: &llms.FunctionCall{Arguments: `"Paris"}`},
+		}},
+		{Type: llms.StreamingEventUsageUpdate, Usage: &llms.Usage{
+			PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30,
+		}},
+		{Type: llms.StreamingEventDone, StopReason: "tool_use"},
+	}, got)
+}
+
+func TestThinkingBudget(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, map[string]any{"type": "enabled", "budget_tokens": float64(2048)}, payload["thinking"])
+		assert.Equal(t, float64(1), payload["temperature"])
+		assert.Equal(t, float64(4096+2048), payload["max_tokens"])
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1", "type": "message", "role": "assistant", "stop_reason": "end_turn",
+			"content": [
+				{"type": "thinking", "thinking": "A nautical mile is 1852 m."},
+				{"type": "text", "text": "About 6076 feet."}
+			],
+			"usage": {"input_tokens": 10, "output_tokens": 30}
+		}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "How many feet are in a nautical mile?")},
+		llms.WithThinkingBudget(2048),
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "About 6076 feet.", resp.Choices[0].Content)
+	assert.Equal(t, "A nautical mile is 1852 m.", resp.Choices[0].ReasoningContent)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/tmc/langchaingo/llms"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 )
 
@@ -31,6 +32,7 @@ type Client struct {
 	vertexLocation   string
 	httpClient       Doer
 	anthropicVersion string
+	betas            []string
 
 	// UseLegacyTextCompletionsAPI is a flag to use the legacy text completions API.
 	UseLegacyTextCompletionsAPI bool
@@ -68,6 +70,15 @@ func WithAnthropicVersion(version string) Option {
 	}
 }
 
+// WithAnthropicBeta enables beta features of the API, sent in the
+// anthropic-beta header of every request.
+func WithAnthropicBeta(features ...string) Option {
+	return func(c *Client) error {
+		c.betas = append(c.betas, features...)
+		return nil
+	}
+}
+
 // WithHTTPClient allows setting a custom HTTP client.
 func WithHTTPClient(client Doer) Option {
 	return func(c *Client) error {
@@ -158,6 +169,16 @@ type MessageRequest struct {
 	Stream     bool     `json:"stream,omitempty"`
 
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingEventFunc is called with every typed event of a streaming
+	// response.
+	StreamingEventFunc func(ctx context.Context, event llms.StreamingEvent) error `json:"-"`
+
+	// ThinkingBudget enables extended thinking with the given budget of
+	// tokens, if non-zero.
+	ThinkingBudget int `json:"-"`
+
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 func handleToolChoice(toolChoice any) (*ToolChoice, error) {
@@ -204,19 +225,28 @@ func (c *Client) CreateMessage(ctx context.Context, r *MessageRequest) (*Message
 	if err != nil {
 		return nil, err
 	}
+	var thinking *Thinking
+	if r.ThinkingBudget > 0 {
+		thinking = &Thinking{Type: "enabled", BudgetTokens: r.ThinkingBudget}
+		// extended thinking requires a temperature of 1.
+		r.Temperature = 1
+	}
 	resp, err := c.createMessage(ctx, &messagePayload{
-		Model:         r.Model,
-		Messages:      r.Messages,
-		System:        r.System,
-		Temperature:   r.Temperature,
-		MaxTokens:     r.MaxTokens,
-		StopWords:     r.StopWords,
-		TopP:          r.TopP,
-		Stream:        r.Stream,
-		StreamingFunc: r.StreamingFunc,
-		TopK:          r.TopK,
-		Tools:         tools,
-		ToolChoice:    toolChoice,
+		Model:              r.Model,
+		Messages:           r.Messages,
+		System:             r.System,
+		Temperature:        r.Temperature,
+		MaxTokens:          r.MaxTokens,
+		StopWords:          r.StopWords,
+		TopP:               r.TopP,
+		Stream:             r.Stream,
+		StreamingFunc:      r.StreamingFunc,
+		StreamingEventFunc: r.StreamingEventFunc,
+		TopK:               r.TopK,
+		Tools:              tools,
+		ToolChoice:         toolChoice,
+		Thinking:           thinking,
+		RawParams:          r.RawParams,
 	})
 	if err != nil {
 		return nil, err
@@ -224,7 +254,9 @@ func (c *Client) CreateMessage(ctx context.Context, r *MessageRequest) (*Message
 	return resp, nil
 }
 
-func (c *Client) setHeaders(req *http.Request) {
+// setHeaders sets the headers of a request, enabling the beta features of the
+// client and the given ones.
+func (c *Client) setHeaders(req *http.Request, betas ...string) {
 	req.Header.Set("Content-Type", "application/json")
 
 	if c.vertexProjectID != "" {
@@ -244,9 +276,13 @@ func (c *Client) setHeaders(req *http.Request) {
 		}
 	}
 
+	if betas = append(slices.Clone(c.betas), betas...); len(betas) > 0 {
+		req.Header.Set("anthropic-beta", strings.Join(betas, ","))
+	}
+
 }
 
-func (c *Client) do(ctx context.Context, path string, payloadBytes []byte) (*http.Response, error) {
+func (c *Client) do(ctx context.Context, path string, payloadBytes []byte, betas ...string) (*http.Response, error) {
 	var url string
 
 	if c.vertexProjectID == "" {
@@ -266,9 +302,9 @@ func (c *Client) do(ctx context.Context, path string, payloadBytes []byte) (*htt
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	c.setHeaders(req)
+	c.setHeaders(req, betas...)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -292,7 +328,12 @@ func (c *Client) decodeError(resp *http.Response) error {
 
 	var errResp errorMessage
 	if err := json.Unmarshal(respBody, &errResp); err != nil {
-		return errors.New(msg) // nolint:goerr113
+		return &llms.LLMError{
+			Message:     msg,
+			StatusCode:  resp.StatusCode,
+			RawResponse: respBody,
+			Code:        llms.ClassifyError(resp.StatusCode, "", ""),
+		}
 	}
 
 	// nolint:goerr113
@@ -302,5 +343,6 @@ func (c *Client) decodeError(resp *http.Response) error {
 		ErrorType:    errResp.Error.Type,
 		ErrorMessage: errResp.Error.Message,
 		RawResponse:  respBody,
+		Code:         llms.ClassifyError(resp.StatusCode, errResp.Error.Type, errResp.Error.Message),
 	}
 }
@@ -10,6 +10,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 // https://docs.anthropic.com/en/api/messages
@@ -55,15 +57,27 @@ type messagePayload struct {
 	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
 	Tools      []Tool      `json:"tools,omitempty"`
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	Thinking *Thinking `json:"thinking,omitempty"`
+
+	StreamingFunc      func(ctx context.Context, chunk []byte) error              `json:"-"`
+	StreamingEventFunc func(ctx context.Context, event llms.StreamingEvent) error `json:"-"`
 
 	AnthropicVersion string `json:"anthropic_version,omitempty"`
+
+	RawParams map[string]any `json:"-"`
+}
+
+// Thinking configures the extended thinking of a message request.
+type Thinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 type MessageResponsePayload struct {
 	Content []struct {
-		Text string `json:"text"`
-		Type string `json:"type"`
+		Text     string `json:"text"`
+		Type     string `json:"type"`
+		Thinking string `json:"thinking,omitempty"`
 	} `json:"content"`
 	ID           string `json:"id"`
 	Model        string `json:"model,omitempty"`
@@ -81,6 +95,10 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 	// Set defaults
 	if payload.MaxTokens == 0 {
 		payload.MaxTokens = 4096
+		// the thinking budget counts towards the max tokens.
+		if payload.Thinking != nil {
+			payload.MaxTokens += payload.Thinking.BudgetTokens
+		}
 	}
 
 	if len(payload.StopWords) == 0 {
@@ -106,11 +124,25 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 		payload.AnthropicVersion = c.anthropicVersion
 
 	}
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		payload.Stream = true
 	}
 }
 
+func (p *messagePayload) isStreaming() bool {
+	return p.StreamingFunc != nil || p.StreamingEventFunc != nil
+}
+
+func (p *messagePayload) sendEvent(ctx context.Context, event llms.StreamingEvent) error {
+	if p.StreamingEventFunc == nil {
+		return nil
+	}
+	if err := p.StreamingEventFunc(ctx, event); err != nil {
+		return fmt.Errorf("streaming event func returned an error: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*MessageResponsePayload, error) {
 	c.setMessageDefaults(payload)
 
@@ -118,6 +150,10 @@ func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*M
 	if err != nil {
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
+	payloadBytes, err = llms.MergeRawParams(payloadBytes, payload.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.do(ctx, "/messages", payloadBytes)
 	if err != nil {
@@ -129,7 +165,7 @@ func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*M
 		return nil, c.decodeError(resp)
 	}
 
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		return parseStreamingMessageResponse(ctx, resp, payload)
 	}
 
@@ -200,14 +236,20 @@ func processStreamEvent(ctx context.Context, event map[string]interface{}, paylo
 	case "message_start":
 		return handleMessageStartEvent(event, response)
 	case "content_block_start":
-		return handleContentBlockStartEvent(event, response)
+		return handleContentBlockStartEvent(ctx, event, response, payload)
 	case "content_block_delta":
 		return handleContentBlockDeltaEvent(ctx, event, response, payload)
 	case "content_block_stop":
 		// Nothing to do here
 	case "message_delta":
-		return handleMessageDeltaEvent(event, response)
+		return handleMessageDeltaEvent(ctx, event, response, payload)
 	case "message_stop":
+		if err := payload.sendEvent(ctx, llms.StreamingEvent{
+			Type:       llms.StreamingEventDone,
+			StopReason: response.StopReason,
+		}); err != nil {
+			return response, err
+		}
 		eventChan <- MessageEvent{Response: &response, Err: nil}
 	case "ping":
 		// Nothing to do here
@@ -242,7 +284,7 @@ func handleMessageStartEvent(event map[string]interface{}, response MessageRespo
 	return response, nil
 }
 
-func handleContentBlockStartEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleContentBlockStartEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	indexValue, ok := event["index"].(float64)
 	if !ok {
 		return response, errors.New("invalid index field type")
@@ -251,11 +293,43 @@ func handleContentBlockStartEvent(event map[string]interface{}, response Message
 
 	if len(response.Content) <= index {
 		response.Content = append(response.Content, struct {
-			Text string `json:"text"`
-			Type string `json:"type"`
+			Text     string `json:"text"`
+			Type     string `json:"type"`
+			Thinking string `json:"thinking,omitempty"`
 		}{})
 	}
-	return response, nil
+	block, ok := event["content_block"].(map[string]interface{})
+	if !ok {
+		return response, nil
+	}
+	response.Content[len(response.Content)-1].Type = getString(block, "type")
+	if getString(block, "type") != "tool_use" {
+		return response, nil
+	}
+	err := payload.sendEvent(ctx, llms.StreamingEvent{
+		Type:          llms.StreamingEventToolCallDelta,
+		ToolCallIndex: toolCallIndex(response, len(response.Content)-1),
+		ToolCall: &llms.ToolCall{
+			ID:   getString(block, "id"),
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name: getString(block, "name"),
+			},
+		},
+	})
+	return response, err
+}
+
+// toolCallIndex returns the index among the tool calls of the tool_use content
+// block at index.
+func toolCallIndex(response MessageResponsePayload, index int) int {
+	n := 0
+	for _, content := range response.Content[:index] {
+		if content.Type == "tool_use" {
+			n++
+		}
+	}
+	return n
 }
 
 func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
@@ -274,32 +348,63 @@ func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interfac
 		return response, errors.New("invalid delta type field type")
 	}
 
-	if deltaType == "text_delta" {
-		text, ok := delta["text"].(string)
+	if deltaType == "thinking_delta" {
+		thinking, ok := delta["thinking"].(string)
 		if !ok {
-			return response, errors.New("invalid delta text field type")
+			return response, errors.New("invalid delta thinking field type")
 		}
-		if len(response.Content) > index {
-			response.Content[index].Text += text
-		} else {
+		if len(response.Content) <= index {
 			return response, errors.New("content index out of range")
 		}
+		response.Content[index].Thinking += thinking
+		return response, payload.sendEvent(ctx, llms.StreamingEvent{
+			Type: llms.StreamingEventReasoningDelta,
+			Text: thinking,
+		})
 	}
 
-	if payload.StreamingFunc != nil {
-		text, ok := delta["text"].(string)
+	if deltaType == "input_json_delta" {
+		partialJSON, ok := delta["partial_json"].(string)
 		if !ok {
-			return response, errors.New("invalid delta text field type")
+			return response, errors.New("invalid delta partial_json field type")
 		}
+		if len(response.Content) <= index {
+			return response, errors.New("content index out of range")
+		}
+		return response, payload.sendEvent(ctx, llms.StreamingEvent{
+			Type:          llms.StreamingEventToolCallDelta,
+			ToolCallIndex: toolCallIndex(response, index),
+			ToolCall: &llms.ToolCall{
+				FunctionCall: &llms.FunctionCall{Arguments: partialJSON},
+			},
+		})
+	}
+
+	if deltaType != "text_delta" {
+		return response, nil
+	}
+	text, ok := delta["text"].(string)
+	if !ok {
+		return response, errors.New("invalid delta text field type")
+	}
+	if len(response.Content) <= index {
+		return response, errors.New("content index out of range")
+	}
+	response.Content[index].Text += text
+
+	if payload.StreamingFunc != nil {
 		err := payload.StreamingFunc(ctx, []byte(text))
 		if err != nil {
 			return response, fmt.Errorf("streaming func returned an error: %w", err)
 		}
 	}
-	return response, nil
+	return response, payload.sendEvent(ctx, llms.StreamingEvent{
+		Type: llms.StreamingEventTextDelta,
+		Text: text,
+	})
 }
 
-func handleMessageDeltaEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleMessageDeltaEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	delta, ok := event["delta"].(map[string]interface{})
 	if !ok {
 		return response, errors.New("invalid delta field type")
@@ -315,7 +420,14 @@ func handleMessageDeltaEvent(event map[string]interface{}, response MessageRespo
 	if outputTokens, ok := usage["output_tokens"].(float64); ok {
 		response.Usage.OutputTokens = int(outputTokens)
 	}
-	return response, nil
+	return response, payload.sendEvent(ctx, llms.StreamingEvent{
+		Type: llms.StreamingEventUsageUpdate,
+		Usage: &llms.Usage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	})
 }
 
 func getString(m map[string]interface{}, key string) string {
@@ -333,3 +445,49 @@ func getFloat64(m map[string]interface{}, key string) (float64, error) {
 	}
 	return value, nil
 }
+
+// tokenCountingBeta is the beta feature of the count_tokens API.
+const tokenCountingBeta = "token-counting-2024-11-01"
+
+// CountTokensRequest is a request to count the input tokens of messages.
+type CountTokensRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	System   string        `json:"system,omitempty"`
+}
+
+type countTokensResponse struct {
+	InputTokens int `json:"input_tokens"`
+}
+
+// CountTokens counts the input tokens of messages with the count_tokens API,
+// enabling its beta feature.
+func (c *Client) CountTokens(ctx context.Context, r *CountTokensRequest) (int, error) {
+	if r.Model == "" {
+		r.Model = c.Model
+	}
+	if r.Model == "" {
+		r.Model = defaultModel
+	}
+
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	resp, err := c.do(ctx, "/messages/count_tokens", payloadBytes, tokenCountingBeta)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, c.decodeError(resp)
+	}
+
+	var response countTokensResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("parse response: %w", err)
+	}
+	return response.InputTokens, nil
+}
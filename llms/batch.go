@@ -0,0 +1,129 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const _defaultBatchConcurrency = 4
+
+// RateLimiter limits the rate of requests. It is satisfied by
+// golang.org/x/time/rate.Limiter.
+type RateLimiter interface {
+	// Wait blocks until a request is allowed or the context is done.
+	Wait(ctx context.Context) error
+}
+
+// BatchOption is a function that configures a BatchOptions.
+type BatchOption func(*BatchOptions)
+
+// BatchOptions is a set of options for GenerateBatch.
+type BatchOptions struct {
+	// Concurrency is the maximum number of concurrent requests.
+	Concurrency int
+	// RateLimiter is waited on before every request if set.
+	RateLimiter RateLimiter
+	// CallOptions are passed to the model for every item.
+	CallOptions []CallOption
+}
+
+// WithBatchConcurrency sets the maximum number of concurrent requests.
+// Defaults to 4.
+func WithBatchConcurrency(concurrency int) BatchOption {
+	return func(o *BatchOptions) {
+		o.Concurrency = concurrency
+	}
+}
+
+// WithBatchRateLimiter sets a rate limiter waited on before every request.
+func WithBatchRateLimiter(limiter RateLimiter) BatchOption {
+	return func(o *BatchOptions) {
+		o.RateLimiter = limiter
+	}
+}
+
+// WithBatchCallOptions sets the call options used for every item.
+func WithBatchCallOptions(options ...CallOption) BatchOption {
+	return func(o *BatchOptions) {
+		o.CallOptions = append(o.CallOptions, options...)
+	}
+}
+
+// BatchItemError is the error of a single item of a batch.
+type BatchItemError struct {
+	// Index is the index of the failed item.
+	Index int
+	// Err is the error returned for the item.
+	Err error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateBatch calls GenerateContent for every list of messages with bounded
+// concurrency. The responses are returned in the order of the inputs. Failed
+// items have a nil response and their errors are joined into the returned
+// error as *BatchItemError values.
+func GenerateBatch(
+	ctx context.Context,
+	model Model,
+	batch [][]MessageContent,
+	options ...BatchOption,
+) ([]*ContentResponse, error) {
+	opts := BatchOptions{Concurrency: _defaultBatchConcurrency}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	responses := make([]*ContentResponse, len(batch))
+	errs := make([]error, len(batch))
+	sem := make(chan struct{}, opts.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, messages := range batch {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, messages []MessageContent) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resp, err := generateBatchItem(ctx, model, messages, opts)
+			if err != nil {
+				errs[i] = &BatchItemError{Index: i, Err: err}
+				return
+			}
+			responses[i] = resp
+		}(i, messages)
+	}
+	wg.Wait()
+
+	return responses, errors.Join(errs...)
+}
+
+func generateBatchItem(
+	ctx context.Context,
+	model Model,
+	messages []MessageContent,
+	opts BatchOptions,
+) (*ContentResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.RateLimiter != nil {
+		if err := opts.RateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return model.GenerateContent(ctx, messages, opts.CallOptions...)
+}
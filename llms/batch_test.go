@@ -0,0 +1,80 @@
+package llms_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// echoModel answers with the text of the first part and fails on "fail".
+type echoModel struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (m *echoModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *echoModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	n := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		prev := m.maxInFlight.Load()
+		if n <= prev || m.maxInFlight.CompareAndSwap(prev, n) {
+			break
+		}
+	}
+
+	text := messages[0].Parts[0].(llms.TextContent).Text //nolint:forcetypeassert
+	if text == "fail" {
+		return nil, errors.New("failed")
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: text}}}, nil
+}
+
+type countingLimiter struct {
+	calls atomic.Int32
+}
+
+func (l *countingLimiter) Wait(_ context.Context) error {
+	l.calls.Add(1)
+	return nil
+}
+
+func TestGenerateBatch(t *testing.T) {
+	t.Parallel()
+	model := &echoModel{}
+	limiter := &countingLimiter{}
+
+	inputs := []string{"a", "b", "fail", "c", "d"}
+	batch := make([][]llms.MessageContent, len(inputs))
+	for i, input := range inputs {
+		batch[i] = []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, input)}
+	}
+
+	responses, err := llms.GenerateBatch(context.Background(), model, batch,
+		llms.WithBatchConcurrency(2),
+		llms.WithBatchRateLimiter(limiter),
+	)
+	require.Error(t, err)
+
+	var itemErr *llms.BatchItemError
+	require.ErrorAs(t, err, &itemErr)
+	require.Equal(t, 2, itemErr.Index)
+
+	require.Len(t, responses, len(inputs))
+	require.Nil(t, responses[2])
+	for i, input := range inputs {
+		if input == "fail" {
+			continue
+		}
+		require.Equal(t, input, responses[i].Choices[0].Content)
+	}
+	require.LessOrEqual(t, model.maxInFlight.Load(), int32(2))
+	require.Equal(t, int32(len(inputs)), limiter.calls.Load())
+}
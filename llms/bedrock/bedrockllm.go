@@ -76,7 +76,13 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		return nil, err
 	}
 
-	res, err := l.client.CreateCompletion(ctx, opts.Model, m, opts)
+	var res *llms.ContentResponse
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
+	err = llms.RetryCall(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = l.client.CreateCompletion(ctx, opts.Model, m, opts)
+		return err
+	})
 	if err != nil {
 		if l.CallbacksHandler != nil {
 			l.CallbacksHandler.HandleLLMError(ctx, err)
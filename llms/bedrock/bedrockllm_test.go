@@ -2,11 +2,15 @@ package bedrock_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/bedrock"
 )
@@ -84,3 +88,63 @@ func TestAmazonOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPOverrides(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "tenant-1", r.Header.Get("X-Tenant-Id"))
+		require.Contains(t, r.Header.Get("Authorization"), "x-tenant-id")
+		require.Equal(t, "high", r.URL.Query().Get("priority"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"inputTextTokenCount":3,"results":[{"tokenCount":2,"outputText":"Hi!","completionReason":"FINISH"}]}`))
+	}))
+	defer server.Close()
+
+	client := bedrockruntime.New(bedrockruntime.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+	})
+	llm, err := bedrock.New(bedrock.WithClient(client), bedrock.WithModel(bedrock.ModelAmazonTitanTextLiteV1))
+	require.NoError(t, err)
+
+	resp, err := llm.Call(context.Background(), "Hello",
+		llms.WithHTTPHeader("X-Tenant-Id", "tenant-1"),
+		llms.WithQueryParam("priority", "high"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Hi!", resp)
+}
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-Errortype", "ThrottlingException")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"Too many requests, please wait before trying again."}`))
+	}))
+	defer server.Close()
+
+	client := bedrockruntime.New(bedrockruntime.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+		RetryMaxAttempts: 1,
+	})
+	llm, err := bedrock.New(bedrock.WithClient(client), bedrock.WithModel(bedrock.ModelAnthropicClaudeV3Sonnet))
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "Hello")
+	require.ErrorIs(t, err, llms.ErrRateLimited)
+	llmErr, ok := llms.AsLLMError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusTooManyRequests, llmErr.StatusCode)
+	require.Equal(t, "ThrottlingException", llmErr.ErrorType)
+}
@@ -46,21 +46,24 @@ func (c *Client) CreateCompletion(ctx context.Context,
 	messages []Message,
 	options llms.CallOptions,
 ) (*llms.ContentResponse, error) {
+	var resp *llms.ContentResponse
+	var err error
 	provider := getProvider(modelID)
 	switch provider {
 	case "ai21":
-		return createAi21Completion(ctx, c.client, modelID, messages, options)
+		resp, err = createAi21Completion(ctx, c.client, modelID, messages, options)
 	case "amazon":
-		return createAmazonCompletion(ctx, c.client, modelID, messages, options)
+		resp, err = createAmazonCompletion(ctx, c.client, modelID, messages, options)
 	case "anthropic":
-		return createAnthropicCompletion(ctx, c.client, modelID, messages, options)
+		resp, err = createAnthropicCompletion(ctx, c.client, modelID, messages, options)
 	case "cohere":
-		return createCohereCompletion(ctx, c.client, modelID, messages, options)
+		resp, err = createCohereCompletion(ctx, c.client, modelID, messages, options)
 	case "meta":
-		return createMetaCompletion(ctx, c.client, modelID, messages, options)
+		resp, err = createMetaCompletion(ctx, c.client, modelID, messages, options)
 	default:
 		return nil, errors.New("unsupported provider")
 	}
+	return resp, wrapError(err)
 }
 
 // Helper function to process input text chat
@@ -0,0 +1,43 @@
+package bedrockclient
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// nolint:gochecknoglobals
+var _exceptionCodes = map[string]llms.ErrorCode{
+	"ThrottlingException":           llms.ErrorCodeRateLimited,
+	"ServiceQuotaExceededException": llms.ErrorCodeRateLimited,
+	"AccessDeniedException":         llms.ErrorCodeAuthFailed,
+	"UnrecognizedClientException":   llms.ErrorCodeAuthFailed,
+	"ExpiredTokenException":         llms.ErrorCodeAuthFailed,
+	"ServiceUnavailableException":   llms.ErrorCodeOverloaded,
+	"ModelNotReadyException":        llms.ErrorCodeOverloaded,
+	"ModelTimeoutException":         llms.ErrorCodeOverloaded,
+}
+
+// wrapError classifies an error of the Bedrock API as an llms.LLMError. Other
+// errors are returned unchanged.
+func wrapError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	var statusCode int
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		statusCode = respErr.HTTPStatusCode()
+	}
+
+	llmErr := llms.NewLLMError(statusCode, apiErr.ErrorCode(), apiErr.ErrorMessage(), nil)
+	llmErr.Message = err.Error()
+	llmErr.Err = err
+	if code, ok := _exceptionCodes[apiErr.ErrorCode()]; ok {
+		llmErr.Code = code
+	}
+	return llmErr
+}
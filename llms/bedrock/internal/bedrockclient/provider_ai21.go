@@ -99,6 +99,10 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 	if err != nil {
 		return nil, err
 	}
+	body, err = llms.MergeRawParams(body, options.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	modelInput := bedrockruntime.InvokeModelInput{
 		ModelId:     aws.String(modelID),
@@ -107,7 +111,7 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 		ContentType: aws.String("application/json"),
 	}
 
-	resp, err := client.InvokeModel(ctx, &modelInput)
+	resp, err := client.InvokeModel(ctx, &modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +78,10 @@ func createAmazonCompletion(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	body, err = llms.MergeRawParams(body, options.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	modelInput := &bedrockruntime.InvokeModelInput{
 		ModelId:     aws.String(modelID),
@@ -85,7 +89,7 @@ func createAmazonCompletion(ctx context.Context,
 		ContentType: aws.String("application/json"),
 		Body:        body,
 	}
-	resp, err := client.InvokeModel(ctx, modelInput)
+	resp, err := client.InvokeModel(ctx, modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +147,10 @@ func createAnthropicCompletion(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	body, err = llms.MergeRawParams(body, options.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	if options.StreamingFunc != nil {
 		modelInput := &bedrockruntime.InvokeModelWithResponseStreamInput{
@@ -164,7 +168,7 @@ func createAnthropicCompletion(ctx context.Context,
 		ContentType: aws.String("application/json"),
 		Body:        body,
 	}
-	resp, err := client.InvokeModel(ctx, modelInput)
+	resp, err := client.InvokeModel(ctx, modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +234,7 @@ type streamingCompletionResponseChunk struct {
 }
 
 func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntime.Client, modelInput *bedrockruntime.InvokeModelWithResponseStreamInput, options llms.CallOptions) (*llms.ContentResponse, error) {
-	output, err := client.InvokeModelWithResponseStream(ctx, modelInput)
+	output, err := client.InvokeModelWithResponseStream(ctx, modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +83,10 @@ func createCohereCompletion(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	body, err = llms.MergeRawParams(body, options.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	modelInput := &bedrockruntime.InvokeModelInput{
 		ModelId:     aws.String(modelID),
@@ -90,7 +94,7 @@ func createCohereCompletion(ctx context.Context,
 		ContentType: aws.String("application/json"),
 		Body:        body,
 	}
-	resp, err := client.InvokeModel(ctx, modelInput)
+	resp, err := client.InvokeModel(ctx, modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +63,10 @@ func createMetaCompletion(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	body, err = llms.MergeRawParams(body, options.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	modelInput := &bedrockruntime.InvokeModelInput{
 		ModelId:     aws.String(modelID),
@@ -71,7 +75,7 @@ func createMetaCompletion(ctx context.Context,
 		Body:        body,
 	}
 
-	resp, err := client.InvokeModel(ctx, modelInput)
+	resp, err := client.InvokeModel(ctx, modelInput, requestOptions(options))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,40 @@
+package bedrockclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// requestOptions returns an option of the Bedrock runtime client that sets the
+// extra HTTP headers and query parameters of the call options on the request.
+// They are set before the request is signed.
+func requestOptions(options llms.CallOptions) func(*bedrockruntime.Options) {
+	return func(o *bedrockruntime.Options) {
+		if len(options.HTTPHeaders) == 0 && len(options.QueryParams) == 0 {
+			return
+		}
+		o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Build.Add(middleware.BuildMiddlewareFunc("HTTPOverrides", func(
+				ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+			) (middleware.BuildOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					for key, values := range options.HTTPHeaders {
+						req.Header[key] = values
+					}
+					if len(options.QueryParams) > 0 {
+						query := req.URL.Query()
+						for key, values := range options.QueryParams {
+							query[key] = values
+						}
+						req.URL.RawQuery = query.Encode()
+					}
+				}
+				return next.HandleBuild(ctx, in)
+			}), middleware.After)
+		})
+	}
+}
@@ -61,9 +61,10 @@ func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageCon
 	}
 
 	if response := c.cache.Get(ctx, key); response != nil {
-		if opts.StreamingFunc != nil && len(response.Choices) > 0 {
+		streamingFunc := llms.TextStreamingFunc(opts)
+		if streamingFunc != nil && len(response.Choices) > 0 {
 			// only stream the first choice.
-			if err := opts.StreamingFunc(ctx, []byte(response.Choices[0].Content)); err != nil {
+			if err := streamingFunc(ctx, []byte(response.Choices[0].Content)); err != nil {
 				return nil, err
 			}
 		}
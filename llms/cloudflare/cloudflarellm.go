@@ -61,6 +61,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(&opts)
 	}
+	opts.StreamingFunc = llms.TextStreamingFunc(opts)
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
 
 	// Our input is a sequence of Message, each of which potentially has
 	// a sequence of Part that is text.
@@ -113,6 +115,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Messages:      chatMsgs,
 		Stream:        *stream,
 		StreamingFunc: opts.StreamingFunc,
+		RawParams:     opts.RawParams,
 	})
 	if err != nil {
 		return nil, err
@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 // CreateEmbedding creates an embedding from the given texts.
@@ -26,7 +28,7 @@ func (c *Client) CreateEmbedding(ctx context.Context, texts *CreateEmbeddingRequ
 	req.Header.Add("Authorization", c.bearerToken)
 	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +40,7 @@ func (c *Client) CreateEmbedding(ctx context.Context, texts *CreateEmbeddingRequ
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("error: %s", body)
+		return nil, statusError(resp.StatusCode, body)
 	}
 
 	var createEmbeddingResponse CreateEmbeddingResponse
@@ -58,6 +60,10 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 	if err != nil {
 		return nil, err
 	}
+	requestBody, err = llms.MergeRawParams(requestBody, request.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpointURL, bytes.NewBuffer(requestBody))
 	if err != nil {
@@ -67,7 +73,7 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 	req.Header.Add("Authorization", c.bearerToken)
 	req.Header.Add("Content-Type", "application/json")
 
-	response, err := c.httpClient.Do(req)
+	response, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +88,7 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 		}
 
 		if response.StatusCode > 299 {
-			return nil, fmt.Errorf("error: %s", body)
+			return nil, statusError(response.StatusCode, body)
 		}
 
 		var generateResponse GenerateContentResponse
@@ -126,9 +132,7 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 			if err != nil {
 				return nil, err
 			}
-			return &GenerateContentResponse{
-				Errors: []APIError{{Message: string(body)}},
-			}, nil
+			return nil, statusError(response.StatusCode, body)
 		}
 
 		if err = request.StreamingFunc(ctx, bts); err != nil {
@@ -154,7 +158,7 @@ func (c *Client) Summarize(ctx context.Context, inputText string, maxLength int)
 	req.Header.Add("Authorization", c.bearerToken)
 	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +170,7 @@ func (c *Client) Summarize(ctx context.Context, inputText string, maxLength int)
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("error: %s", body)
+		return nil, statusError(resp.StatusCode, body)
 	}
 
 	var summarizeResponse SummarizeResponse
@@ -177,3 +181,14 @@ func (c *Client) Summarize(ctx context.Context, inputText string, maxLength int)
 
 	return &summarizeResponse, nil
 }
+
+// statusError returns an llms.LLMError for a response with an unexpected
+// status code.
+func statusError(statusCode int, body []byte) error {
+	return &llms.LLMError{
+		Message:     fmt.Sprintf("error: %s", body),
+		StatusCode:  statusCode,
+		RawResponse: body,
+		Code:        llms.ClassifyError(statusCode, "", string(body)),
+	}
+}
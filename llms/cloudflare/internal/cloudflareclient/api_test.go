@@ -143,3 +143,33 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 		})
 	}
 }
+
+type recordingHTTPClient struct {
+	body string
+}
+
+func (m *recordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	m.body = string(body)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"result": {"response": "response"}}`)),
+	}, err
+}
+
+func TestClient_GenerateContentRawParams(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingHTTPClient{}
+	c := Client{httpClient: doer, endpointURL: "http://localhost"}
+	_, err := c.GenerateContent(context.Background(), &GenerateContentRequest{
+		Messages:  []Message{{Role: "user", Content: "userPrompt"}},
+		RawParams: map[string]any{"max_tokens": 10, "stream": nil},
+	})
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if want := `{"max_tokens":10,"messages":[{"content":"userPrompt","role":"user"}]}`; doer.body != want {
+		t.Errorf("GenerateContent() body = %s, want %s", doer.body, want)
+	}
+}
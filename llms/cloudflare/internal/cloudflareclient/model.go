@@ -9,6 +9,9 @@ type GenerateContentRequest struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 type Message struct {
@@ -39,12 +39,14 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx = llms.ContextWithRequestOptions(ctx, *opts)
 
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
 	result, err := o.client.CreateGeneration(ctx, &cohereclient.GenerationRequest{
-		Prompt: part.(llms.TextContent).Text,
+		Prompt:    part.(llms.TextContent).Text,
+		RawParams: opts.RawParams,
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/cohere-ai/tokenizer"
+	"github.com/tmc/langchaingo/llms"
 )
 
 var (
@@ -66,6 +68,8 @@ func New(token string, baseURL string, model string, opts ...Option) (*Client, e
 
 type GenerationRequest struct {
 	Prompt string `json:"prompt"`
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 type Generation struct {
@@ -100,6 +104,10 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 	if err != nil {
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
+	payloadBytes, err = llms.MergeRawParams(payloadBytes, r.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -114,12 +122,16 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 	req.Header.Set("content-type", "application/json")
 	req.Header.Set("authorization", "bearer "+c.token)
 
-	res, err := c.httpClient.Do(req)
+	res, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, decodeError(res)
+	}
+
 	var response generateResponsePayload
 	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("parse response: %w", err)
@@ -137,3 +149,17 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 
 	return &generation, nil
 }
+
+func decodeError(res *http.Response) error {
+	body, _ := io.ReadAll(res.Body)
+	var errResp struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &errResp)
+
+	llmErr := llms.NewLLMError(res.StatusCode, "", errResp.Message, body)
+	if strings.HasPrefix(errResp.Message, "model not found") {
+		llmErr.Err = ErrModelNotFound
+	}
+	return llmErr
+}
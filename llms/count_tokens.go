@@ -11,7 +11,6 @@ const (
 )
 
 const (
-	_gpt35TurboContextSize   = 4096
 	_gpt432KContextSize      = 32768
 	_gpt4ContextSize         = 8192
 	_textDavinci3ContextSize = 4097
@@ -25,27 +24,15 @@ const (
 	_defaultContextSize      = 2048
 )
 
-// nolint:gochecknoglobals
-var modelToContextSize = map[string]int{
-	"gpt-3.5-turbo":    _gpt35TurboContextSize,
-	"gpt-4-32k":        _gpt432KContextSize,
-	"gpt-4":            _gpt4ContextSize,
-	"text-davinci-003": _textDavinci3ContextSize,
-	"text-curie-001":   _textCurie1ContextSize,
-	"text-babbage-001": _textBabbage1ContextSize,
-	"text-ada-001":     _textBabbage1ContextSize,
-	"code-davinci-002": _codeDavinci2ContextSize,
-	"code-cushman-001": _codeCushman1ContextSize,
-}
-
-// GetModelContextSize gets the max number of tokens for a language model. If the model
-// name isn't recognized the default value 2048 is returned.
+// GetModelContextSize gets the max number of tokens for a language model from
+// the model registry. If the model name isn't recognized the default value
+// 2048 is returned.
 func GetModelContextSize(model string) int {
-	contextSize, ok := modelToContextSize[model]
-	if !ok {
+	info, ok := LookupModel(model)
+	if !ok || info.ContextWindow == 0 {
 		return _defaultContextSize
 	}
-	return contextSize
+	return info.ContextWindow
 }
 
 // CountTokens gets the number of tokens the text contains.
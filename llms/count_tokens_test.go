@@ -1,6 +1,7 @@
 package llms
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,3 +13,14 @@ func TestCountTokens(t *testing.T) {
 	expectedNumTokens := 4
 	assert.Equal(t, expectedNumTokens, numTokens)
 }
+
+func TestApproximateTokenCounter(t *testing.T) {
+	t.Parallel()
+	messages := []MessageContent{
+		TextParts(ChatMessageTypeHuman, "12345678"),
+		TextParts(ChatMessageTypeAI, "123"),
+	}
+	numTokens, err := ApproximateTokenCounter{}.CountTokens(context.Background(), messages)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, numTokens)
+}
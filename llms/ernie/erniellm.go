@@ -74,6 +74,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(opts)
 	}
+	opts.StreamingFunc = llms.TextStreamingFunc(*opts)
+	ctx = llms.ContextWithRequestOptions(ctx, *opts)
 
 	// Assume we get a single text message
 	msg0 := messages[0]
@@ -85,6 +87,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		PenaltyScore:  opts.RepetitionPenalty,
 		StreamingFunc: opts.StreamingFunc,
 		Stream:        opts.StreamingFunc != nil,
+		RawParams:     opts.RawParams,
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -46,6 +45,9 @@ type ChatRequest struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 // ChatMessage is a message in a chat request.
@@ -153,6 +155,10 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	if err != nil {
 		return nil, err
 	}
+	payloadBytes, err = llms.MergeRawParams(payloadBytes, payload.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build request
 	body := bytes.NewReader(payloadBytes)
@@ -164,7 +170,7 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	c.setHeaders(req)
 
 	// Send request
-	r, err := c.httpClient.Do(req)
+	r, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -177,10 +183,14 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, &llms.LLMError{
+				Message:    msg,
+				StatusCode: r.StatusCode,
+				Code:       llms.ClassifyError(r.StatusCode, "", ""),
+			}
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewLLMError(r.StatusCode, errResp.Error.Type, errResp.Error.Message, nil)
 	}
 	if payload.StreamingFunc != nil {
 		return parseStreamingChatResponse(ctx, r, payload)
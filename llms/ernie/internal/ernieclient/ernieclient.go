@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 var (
@@ -63,6 +65,8 @@ type CompletionRequest struct {
 	Stream        bool                                          `json:"stream,omitempty"`
 	UserID        string                                        `json:"user_id,omitempty"`
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 // Completion is a completion.
@@ -194,19 +198,22 @@ func (c *Client) CreateCompletion(ctx context.Context, modelPath ModelPath, r *C
 	if e != nil {
 		return nil, e
 	}
+	if body, e = llms.MergeRawParams(body, r.RawParams); e != nil {
+		return nil, e
+	}
 	req, e := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if e != nil {
 		return nil, e
 	}
 
-	resp, e := c.httpClient.Do(req)
+	resp, e := llms.DoRequest(c.httpClient, req)
 	if e != nil {
 		return nil, e
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrCompletionCode, resp.StatusCode)
+		return nil, statusError(ErrCompletionCode, resp.StatusCode)
 	}
 
 	if r.Stream {
@@ -235,14 +242,14 @@ func (c *Client) CreateEmbedding(ctx context.Context, texts []string) (*Embeddin
 		return nil, e
 	}
 
-	resp, e := c.httpClient.Do(req)
+	resp, e := llms.DoRequest(c.httpClient, req)
 	if e != nil {
 		return nil, e
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrEmbeddingCode, resp.StatusCode)
+		return nil, statusError(ErrEmbeddingCode, resp.StatusCode)
 	}
 
 	var response EmbeddingResponse
@@ -260,14 +267,14 @@ func (c *Client) getAccessToken(ctx context.Context) (*authResponse, error) {
 		return nil, e
 	}
 
-	resp, e := c.httpClient.Do(req)
+	resp, e := llms.DoRequest(c.httpClient, req)
 	if e != nil {
 		return nil, e
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrAccessTokenCode, resp.StatusCode)
+		return nil, statusError(ErrAccessTokenCode, resp.StatusCode)
 	}
 
 	var response authResponse
@@ -354,3 +361,14 @@ func (c *Client) buildURL(modelpath ModelPath) string {
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 }
+
+// statusError returns an llms.LLMError wrapping sentinel for a response with
+// an unexpected status code.
+func statusError(sentinel error, statusCode int) error {
+	return &llms.LLMError{
+		Message:    fmt.Sprintf("%s: %d", sentinel, statusCode),
+		StatusCode: statusCode,
+		Code:       llms.ClassifyError(statusCode, "", ""),
+		Err:        sentinel,
+	}
+}
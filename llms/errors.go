@@ -1,13 +1,186 @@
 package llms
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrUnsupportedContentPart is returned when a model doesn't support a
+// content part type, e.g. video input.
+var ErrUnsupportedContentPart = errors.New("unsupported content part")
+
+// UnsupportedContentPartError returns an error wrapping
+// ErrUnsupportedContentPart for the given part.
+func UnsupportedContentPartError(part ContentPart) error {
+	return fmt.Errorf("%w: %T", ErrUnsupportedContentPart, part)
+}
+
+// ErrorCode is a provider independent classification of an LLMError.
+type ErrorCode string
+
+const (
+	// ErrorCodeUnknown is used for errors that don't fit any other code.
+	ErrorCodeUnknown ErrorCode = ""
+	// ErrorCodeRateLimited is used when the request was rejected because of
+	// rate limits or exhausted quotas.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeContextLengthExceeded is used when the prompt doesn't fit the
+	// context window of the model.
+	ErrorCodeContextLengthExceeded ErrorCode = "context_length_exceeded"
+	// ErrorCodeContentFiltered is used when the prompt or the completion was
+	// blocked by a content filter.
+	ErrorCodeContentFiltered ErrorCode = "content_filtered"
+	// ErrorCodeAuthFailed is used when the credentials are missing, invalid or
+	// lack the required permissions.
+	ErrorCodeAuthFailed ErrorCode = "auth_failed"
+	// ErrorCodeOverloaded is used when the provider is temporarily unavailable.
+	ErrorCodeOverloaded ErrorCode = "overloaded"
+	// ErrorCodeInvalidRequest is used when the provider rejected the request,
+	// e.g. because of an unknown model or an invalid parameter.
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+)
+
+// Sentinel errors matching an LLMError of the corresponding code with
+// errors.Is.
+var (
+	ErrRateLimited           = errors.New("rate limited")
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	ErrContentFiltered       = errors.New("content filtered")
+	ErrAuthFailed            = errors.New("authentication failed")
+	ErrOverloaded            = errors.New("provider overloaded")
+	ErrInvalidRequest        = errors.New("invalid request")
+)
+
+// nolint:gochecknoglobals
+var errorCodeSentinels = map[ErrorCode]error{
+	ErrorCodeRateLimited:           ErrRateLimited,
+	ErrorCodeContextLengthExceeded: ErrContextLengthExceeded,
+	ErrorCodeContentFiltered:       ErrContentFiltered,
+	ErrorCodeAuthFailed:            ErrAuthFailed,
+	ErrorCodeOverloaded:            ErrOverloaded,
+	ErrorCodeInvalidRequest:        ErrInvalidRequest,
+}
+
+// LLMError is an error returned by a provider API.
 type LLMError struct {
-	Message      string `json:"message,omitempty"`
-	ErrorMessage string `json:"error_message,omitempty"`
-	StatusCode   int    `json:"status_code,omitempty"`
-	ErrorType    string `json:"error_code,omitempty"`
-	RawResponse  []byte `json:"raw_response,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ErrorType    string    `json:"error_code,omitempty"`
+	RawResponse  []byte    `json:"raw_response,omitempty"`
+	Code         ErrorCode `json:"code,omitempty"`
+	// Err is an underlying error, e.g. a provider specific sentinel error.
+	Err error `json:"-"`
+}
+
+// NewLLMError returns an LLMError for a provider response with an
+// unexpected status code, classifying it with ClassifyError.
+func NewLLMError(statusCode int, errorType, errorMessage string, rawResponse []byte) *LLMError {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", statusCode)
+	if errorMessage != "" {
+		msg += ": " + errorMessage
+	}
+	return &LLMError{
+		Message:      msg,
+		ErrorMessage: errorMessage,
+		StatusCode:   statusCode,
+		ErrorType:    errorType,
+		RawResponse:  rawResponse,
+		Code:         ClassifyError(statusCode, errorType, errorMessage),
+	}
 }
 
 func (e *LLMError) Error() string {
 	return e.Message
 }
+
+// Unwrap returns the underlying error, if any.
+func (e *LLMError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the code of e, e.g.
+// ErrRateLimited.
+func (e *LLMError) Is(target error) bool {
+	sentinel, ok := errorCodeSentinels[e.Code]
+	return ok && sentinel == target
+}
+
+// AsLLMError returns the first LLMError in the chain of err, if any.
+func AsLLMError(err error) (*LLMError, bool) {
+	var llmErr *LLMError
+	if errors.As(err, &llmErr) {
+		return llmErr, true
+	}
+	return nil, false
+}
+
+// ErrorCodeOf returns the code of the first LLMError in the chain of err, or
+// ErrorCodeUnknown if there is none.
+func ErrorCodeOf(err error) ErrorCode {
+	if llmErr, ok := AsLLMError(err); ok {
+		return llmErr.Code
+	}
+	return ErrorCodeUnknown
+}
+
+// nolint:gochecknoglobals
+var (
+	_contextLengthHints = []string{
+		"context length", "context_length", "context window", "maximum context",
+		"prompt is too long", "too many tokens", "too many input tokens", "input is too long",
+		"exceeds the maximum number of tokens", "token limit", "tokens limit",
+	}
+	_contentFilterHints = []string{
+		"content_filter", "content filter", "content_policy", "content policy", "content management",
+		"safety", "moderation", "responsible ai",
+	}
+	_rateLimitHints = []string{"rate_limit", "rate limit", "quota", "too many requests"}
+	_authHints      = []string{
+		"authentication", "unauthorized", "permission", "invalid_api_key", "invalid api key",
+		"api key", "access token", "forbidden",
+	}
+	_overloadedHints = []string{"overloaded", "unavailable", "capacity"}
+)
+
+// ClassifyError returns the code of a provider error from its HTTP status
+// code and the type and message decoded from the response body. Any of them
+// may be empty.
+func ClassifyError(statusCode int, errorType, message string) ErrorCode {
+	hint := strings.ToLower(errorType + " " + message)
+	containsAny := func(substrs []string) bool {
+		for _, s := range substrs {
+			if strings.Contains(hint, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorCodeAuthFailed
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case statusCode == http.StatusBadGateway, statusCode == http.StatusServiceUnavailable,
+		statusCode == http.StatusGatewayTimeout, statusCode == 529: //nolint:mnd
+		return ErrorCodeOverloaded
+	case containsAny(_contextLengthHints):
+		return ErrorCodeContextLengthExceeded
+	case containsAny(_contentFilterHints):
+		return ErrorCodeContentFiltered
+	case containsAny(_rateLimitHints):
+		return ErrorCodeRateLimited
+	case containsAny(_overloadedHints):
+		return ErrorCodeOverloaded
+	case containsAny(_authHints):
+		return ErrorCodeAuthFailed
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusNotFound,
+		statusCode == http.StatusRequestEntityTooLarge, statusCode == http.StatusUnprocessableEntity:
+		return ErrorCodeInvalidRequest
+	default:
+		return ErrorCodeUnknown
+	}
+}
@@ -0,0 +1,55 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		statusCode int
+		errorType  string
+		message    string
+		want       ErrorCode
+	}{
+		{http.StatusUnauthorized, "authentication_error", "invalid x-api-key", ErrorCodeAuthFailed},
+		{http.StatusTooManyRequests, "", "", ErrorCodeRateLimited},
+		{http.StatusForbidden, "insufficient_quota", "", ErrorCodeAuthFailed},
+		{http.StatusBadRequest, "insufficient_quota", "You exceeded your current quota", ErrorCodeRateLimited},
+		{529, "overloaded_error", "Overloaded", ErrorCodeOverloaded},
+		{http.StatusBadRequest, "invalid_request_error", "prompt is too long: 210000 tokens > 200000 maximum", ErrorCodeContextLengthExceeded},    //nolint:lll
+		{http.StatusBadRequest, "invalid_request_error", "This model's maximum context length is 8192 tokens", ErrorCodeContextLengthExceeded},    //nolint:lll
+		{http.StatusBadRequest, "", "The response was filtered due to the prompt triggering content management policy", ErrorCodeContentFiltered}, //nolint:lll
+		{http.StatusNotFound, "not_found_error", "model: foo", ErrorCodeInvalidRequest},
+		{http.StatusInternalServerError, "", "", ErrorCodeUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ClassifyError(tt.statusCode, tt.errorType, tt.message), tt.message)
+	}
+}
+
+func TestLLMErrorIs(t *testing.T) {
+	t.Parallel()
+	sentinel := errors.New("provider error")
+	llmErr := NewLLMError(http.StatusTooManyRequests, "rate_limit_error", "slow down", nil)
+	llmErr.Err = sentinel
+	err := fmt.Errorf("generate: %w", llmErr)
+
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.ErrorIs(t, err, sentinel)
+	require.NotErrorIs(t, err, ErrAuthFailed)
+	assert.Equal(t, ErrorCodeRateLimited, ErrorCodeOf(err))
+	assert.Equal(t, "API returned unexpected status code: 429: slow down", err.Error()[len("generate: "):])
+
+	got, ok := AsLLMError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, got.StatusCode)
+
+	assert.Equal(t, ErrorCodeUnknown, ErrorCodeOf(sentinel))
+}
@@ -0,0 +1,5 @@
+// Package fake provides a scriptable llms.Model for tests. Responses,
+// including tool calls and streamed chunks, are scripted per call and the
+// messages and options of every call are recorded, so chains and agents can be
+// tested without HTTP mocks.
+package fake
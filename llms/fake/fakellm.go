@@ -0,0 +1,217 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrNoMoreResponses is returned when the LLM is called more often than
+// responses were scripted.
+var ErrNoMoreResponses = errors.New("fake: no more scripted responses")
+
+// Response is the scripted response of a single call.
+type Response struct {
+	// Content is the text of the response.
+	Content string
+	// ToolCalls are the tool calls the model asks to invoke.
+	ToolCalls []llms.ToolCall
+	// StopReason is the reason the model stopped generating output.
+	StopReason string
+	// Chunks are streamed when a streaming function is set. If empty, Content
+	// is streamed as a single chunk.
+	Chunks []string
+	// Usage is the token usage reported with the response.
+	Usage llms.Usage
+	// Err is returned instead of a response if set.
+	Err error
+	// Delay is waited before responding. The call fails if the context is
+	// done earlier.
+	Delay time.Duration
+}
+
+// Call is a recorded call to the LLM.
+type Call struct {
+	// Messages are the messages the LLM was called with.
+	Messages []llms.MessageContent
+	// Options are the resolved call options.
+	Options llms.CallOptions
+}
+
+// LLM is a fake llms.Model returning scripted responses, in order, one per
+// call. It records the calls it receives and is safe for concurrent use.
+type LLM struct {
+	mu        sync.Mutex
+	responses []Response
+	// index is the position of the next response in the script.
+	index  int
+	calls  []Call
+	repeat bool
+}
+
+var _ llms.Model = (*LLM)(nil)
+
+// New creates a fake LLM returning the given responses in order.
+func New(responses ...Response) *LLM {
+	return &LLM{responses: responses}
+}
+
+// NewRepeating creates a fake LLM that returns the given responses in order
+// and starts over once all of them were returned.
+func NewRepeating(responses ...Response) *LLM {
+	return &LLM{responses: responses, repeat: true}
+}
+
+// TextResponses creates text responses for the given contents.
+func TextResponses(contents ...string) []Response {
+	responses := make([]Response, len(contents))
+	for i, content := range contents {
+		responses[i] = Response{Content: content}
+	}
+	return responses
+}
+
+// AddResponses appends responses to the script.
+func (f *LLM) AddResponses(responses ...Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, responses...)
+}
+
+// Calls returns the recorded calls.
+func (f *LLM) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call{}, f.calls...)
+}
+
+// LastCall returns the last recorded call. It returns a zero Call if the LLM
+// wasn't called yet.
+func (f *LLM) LastCall() Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) == 0 {
+		return Call{}
+	}
+	return f.calls[len(f.calls)-1]
+}
+
+// Reset removes the recorded calls and restarts the script from its first
+// response.
+func (f *LLM) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.index = 0
+}
+
+// Call implements the [llms.Model] interface.
+func (f *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// GenerateContent implements the [llms.Model] interface.
+func (f *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	resp, err := f.next(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	if err := stream(ctx, resp, opts); err != nil {
+		return nil, err
+	}
+
+	choice := &llms.ContentChoice{
+		Content:    resp.Content,
+		StopReason: resp.StopReason,
+		ToolCalls:  resp.ToolCalls,
+	}
+	if len(resp.ToolCalls) > 0 {
+		choice.FuncCall = resp.ToolCalls[0].FunctionCall
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{choice},
+		Usage:   resp.Usage,
+	}, nil
+}
+
+func (f *LLM) next(messages []llms.MessageContent, opts llms.CallOptions) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{
+		Messages: append([]llms.MessageContent{}, messages...),
+		Options:  opts,
+	})
+	if f.repeat && f.index >= len(f.responses) {
+		f.index = 0
+	}
+	if f.index >= len(f.responses) {
+		return Response{}, ErrNoMoreResponses
+	}
+	resp := f.responses[f.index]
+	f.index++
+	return resp, nil
+}
+
+func stream(ctx context.Context, resp Response, opts llms.CallOptions) error {
+	streamingFunc := llms.TextStreamingFunc(opts)
+	if streamingFunc == nil {
+		return nil
+	}
+
+	chunks := resp.Chunks
+	if len(chunks) == 0 && resp.Content != "" {
+		chunks = []string{resp.Content}
+	}
+	for _, chunk := range chunks {
+		if err := streamingFunc(ctx, []byte(chunk)); err != nil {
+			return err
+		}
+	}
+
+	if opts.StreamingEventFunc == nil {
+		return nil
+	}
+	for i := range resp.ToolCalls {
+		toolCall := resp.ToolCalls[i]
+		if err := opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+			Type:          llms.StreamingEventToolCallDelta,
+			ToolCall:      &toolCall,
+			ToolCallIndex: i,
+		}); err != nil {
+			return err
+		}
+	}
+	if resp.Usage != (llms.Usage{}) {
+		usage := resp.Usage
+		if err := opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+			Type:  llms.StreamingEventUsageUpdate,
+			Usage: &usage,
+		}); err != nil {
+			return err
+		}
+	}
+	return opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+		Type:       llms.StreamingEventDone,
+		StopReason: resp.StopReason,
+	})
+}
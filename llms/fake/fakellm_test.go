@@ -0,0 +1,96 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestLLMScriptedResponses(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := New(
+		Response{Content: "hello", Chunks: []string{"hel", "lo"}},
+		Response{ToolCalls: []llms.ToolCall{{
+			ID:           "1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "search", Arguments: "{}"},
+		}}},
+	)
+
+	var chunks []string
+	resp, err := llm.GenerateContent(ctx,
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")},
+		llms.WithTemperature(0.5),
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "hello", resp.Choices[0].Content)
+	require.Equal(t, []string{"hel", "lo"}, chunks)
+
+	resp, err = llm.GenerateContent(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "search", resp.Choices[0].ToolCalls[0].FunctionCall.Name)
+
+	_, err = llm.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, ErrNoMoreResponses)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 3)
+	require.Equal(t, "hi", llms.MessageText(calls[0].Messages[0]))
+	require.InDelta(t, 0.5, calls[0].Options.Temperature, 0.001)
+}
+
+func TestLLMErrorsAndDelay(t *testing.T) {
+	t.Parallel()
+	errBoom := errors.New("boom")
+	llm := NewRepeating(
+		Response{Err: errBoom},
+		Response{Content: "slow", Delay: time.Second},
+	)
+
+	_, err := llm.Call(context.Background(), "a")
+	require.ErrorIs(t, err, errBoom)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = llm.Call(ctx, "b")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = llm.Call(context.Background(), "c")
+	require.ErrorIs(t, err, errBoom)
+	require.Len(t, llm.Calls(), 3)
+}
+
+func TestLLMAddResponsesAndReset(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	llm := New(TextResponses("one")...)
+
+	_, err := llm.Call(ctx, "a")
+	require.NoError(t, err)
+	_, err = llm.Call(ctx, "b")
+	require.ErrorIs(t, err, ErrNoMoreResponses)
+
+	// Responses added after the script ran out are returned next, even though
+	// failed calls were recorded.
+	llm.AddResponses(TextResponses("two")...)
+	out, err := llm.Call(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, "two", out)
+	require.Len(t, llm.Calls(), 3)
+
+	llm.Reset()
+	require.Empty(t, llm.Calls())
+	out, err = llm.Call(ctx, "d")
+	require.NoError(t, err)
+	require.Equal(t, "one", out)
+}
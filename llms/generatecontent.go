@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // MessageContent is the content of a message sent to a LLM. It has a role and a
@@ -38,6 +39,40 @@ func ImageURLPart(url string) ImageURLContent {
 	}
 }
 
+// AudioPart creates a new AudioContent from the given MIME type (e.g.
+// "audio/wav") and binary data.
+func AudioPart(mime string, data []byte) AudioContent {
+	return AudioContent{
+		MIMEType: mime,
+		Data:     data,
+	}
+}
+
+// AudioURLPart creates a new AudioContent from the given MIME type and URL.
+func AudioURLPart(mime, url string) AudioContent {
+	return AudioContent{
+		MIMEType: mime,
+		URL:      url,
+	}
+}
+
+// VideoPart creates a new VideoContent from the given MIME type (e.g.
+// "video/mp4") and binary data.
+func VideoPart(mime string, data []byte) VideoContent {
+	return VideoContent{
+		MIMEType: mime,
+		Data:     data,
+	}
+}
+
+// VideoURLPart creates a new VideoContent from the given MIME type and URL.
+func VideoURLPart(mime, url string) VideoContent {
+	return VideoContent{
+		MIMEType: mime,
+		URL:      url,
+	}
+}
+
 // ContentPart is an interface all parts of content have to implement.
 type ContentPart interface {
 	isPart()
@@ -107,6 +142,101 @@ func (bc BinaryContent) MarshalJSON() ([]byte, error) {
 
 func (BinaryContent) isPart() {}
 
+// AudioContent is audio content, given either as binary data or as an URL.
+type AudioContent struct {
+	// MIMEType is the MIME type of the audio, e.g. "audio/wav".
+	MIMEType string
+	// Data is the binary audio data. Either Data or URL is set.
+	Data []byte
+	// URL points to the audio. Either Data or URL is set.
+	URL string
+	// Transcript is an optional transcript of the audio. It is sent as text
+	// to models that don't support audio input directly.
+	Transcript string
+}
+
+func (ac AudioContent) String() string {
+	return mediaString(ac.MIMEType, ac.Data, ac.URL)
+}
+
+func (ac AudioContent) MarshalJSON() ([]byte, error) {
+	if ac.URL != "" {
+		return json.Marshal(map[string]any{
+			"type": "audio_url",
+			"audio_url": map[string]string{
+				"url":       ac.URL,
+				"mime_type": ac.MIMEType,
+			},
+		})
+	}
+	return json.Marshal(map[string]any{
+		"type": "input_audio",
+		"input_audio": map[string]string{
+			"data":   base64.StdEncoding.EncodeToString(ac.Data),
+			"format": AudioFormat(ac.MIMEType),
+		},
+	})
+}
+
+func (AudioContent) isPart() {}
+
+// AudioFormat returns the audio format of a MIME type, e.g. "wav" for
+// "audio/wav" and "mp3" for "audio/mpeg".
+func AudioFormat(mime string) string {
+	format := strings.TrimPrefix(mime, "audio/")
+	switch format {
+	case "mpeg":
+		return "mp3"
+	case "x-wav", "wave":
+		return "wav"
+	}
+	return format
+}
+
+// VideoContent is video content, given either as binary data or as an URL.
+type VideoContent struct {
+	// MIMEType is the MIME type of the video, e.g. "video/mp4".
+	MIMEType string
+	// Data is the binary video data. Either Data or URL is set.
+	Data []byte
+	// URL points to the video. Either Data or URL is set.
+	URL string
+	// Transcript is an optional transcript of the audio track of the video.
+	Transcript string
+}
+
+func (vc VideoContent) String() string {
+	return mediaString(vc.MIMEType, vc.Data, vc.URL)
+}
+
+func (vc VideoContent) MarshalJSON() ([]byte, error) {
+	if vc.URL != "" {
+		return json.Marshal(map[string]any{
+			"type": "video_url",
+			"video_url": map[string]string{
+				"url":       vc.URL,
+				"mime_type": vc.MIMEType,
+			},
+		})
+	}
+	return json.Marshal(map[string]any{
+		"type": "video",
+		"video": map[string]string{
+			"mime_type": vc.MIMEType,
+			"data":      base64.StdEncoding.EncodeToString(vc.Data),
+		},
+	})
+}
+
+func (VideoContent) isPart() {}
+
+func mediaString(mime string, data []byte, url string) string {
+	if url != "" {
+		return url
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
 // FunctionCall is the name and arguments of a function call.
 type FunctionCall struct {
 	Name      string `json:"name"`
@@ -169,6 +299,20 @@ type ContentChoice struct {
 
 	// ToolCalls is a list of tool calls the model asks to invoke.
 	ToolCalls []ToolCall
+
+	// ReasoningContent is the reasoning (chain of thought) the model produced
+	// before its final answer, for models that expose it, e.g. DeepSeek-R1
+	// served by Ollama with ollama.WithSplitReasoning, and Claude with
+	// extended thinking. It is not part of Content. Gemini models don't fill
+	// it: the genai SDKs used by googleai and vertex don't expose thought
+	// parts.
+	ReasoningContent string
+
+	// ReasoningTokens is the number of tokens spent on reasoning, if reported
+	// by the provider, e.g. by OpenAI o-series models. The genai SDKs don't
+	// expose the thoughts token count of Gemini models, so googleai and vertex
+	// leave it zero.
+	ReasoningTokens int
 }
 
 // TextParts is a helper function to create a MessageContent with a role and a
@@ -198,6 +342,10 @@ func ShowMessageContents(w io.Writer, msgs []MessageContent) {
 				fmt.Fprintf(w, "ImageURLPart %q\n", pp.URL)
 			case BinaryContent:
 				fmt.Fprintf(w, "BinaryContent MIME=%q, size=%d\n", pp.MIMEType, len(pp.Data))
+			case AudioContent:
+				fmt.Fprintf(w, "AudioContent MIME=%q, size=%d, URL=%q\n", pp.MIMEType, len(pp.Data), pp.URL)
+			case VideoContent:
+				fmt.Fprintf(w, "VideoContent MIME=%q, size=%d, URL=%q\n", pp.MIMEType, len(pp.Data), pp.URL)
 			case ToolCall:
 				fmt.Fprintf(w, "ToolCall ID=%v, Type=%v, Func=%v(%v)\n", pp.ID, pp.Type, pp.FunctionCall.Name, pp.FunctionCall.Arguments)
 			case ToolCallResponse:
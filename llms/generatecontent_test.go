@@ -1,6 +1,7 @@
 package llms
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -34,3 +35,40 @@ func TestTextParts(t *testing.T) {
 		})
 	}
 }
+
+func TestMediaContentMarshalJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		part ContentPart
+		want string
+	}{
+		{
+			"audio data",
+			AudioPart("audio/mpeg", []byte("abc")),
+			`{"input_audio":{"data":"YWJj","format":"mp3"},"type":"input_audio"}`,
+		},
+		{
+			"audio url",
+			AudioURLPart("audio/wav", "https://example.com/a.wav"),
+			`{"audio_url":{"mime_type":"audio/wav","url":"https://example.com/a.wav"},"type":"audio_url"}`,
+		},
+		{
+			"video data",
+			VideoPart("video/mp4", []byte("abc")),
+			`{"type":"video","video":{"data":"YWJj","mime_type":"video/mp4"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := json.Marshal(tt.part)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
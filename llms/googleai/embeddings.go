@@ -4,19 +4,49 @@ import (
 	"context"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
 )
 
+var _ embeddings.QueryEmbedderClient = &GoogleAI{}
+
 // CreateEmbedding creates embeddings from texts.
 func (g *GoogleAI) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	return g.createEmbedding(ctx, texts, g.opts.EmbeddingTaskType)
+}
+
+// CreateQueryEmbedding creates embeddings from query texts, with the query
+// task type set by WithEmbeddingTaskType.
+func (g *GoogleAI) CreateQueryEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	return g.createEmbedding(ctx, texts, g.opts.EmbeddingQueryTaskType)
+}
+
+func (g *GoogleAI) createEmbedding(ctx context.Context, texts []string, taskType EmbeddingTaskType) ([][]float32, error) {
 	em := g.client.EmbeddingModel(g.opts.DefaultEmbeddingModel)
+	em.TaskType = genai.TaskType(taskType)
 
 	results := make([][]float32, 0, len(texts))
 	for _, t := range texts {
-		res, err := em.EmbedContent(ctx, genai.Text(t))
+		var res *genai.EmbedContentResponse
+		err := llms.RetryCall(ctx, func(ctx context.Context) error {
+			var err error
+			res, err = em.EmbedContent(ctx, genai.Text(t))
+			return wrapError(err)
+		})
 		if err != nil {
 			return results, err
 		}
-		results = append(results, res.Embedding.Values)
+		values := res.Embedding.Values
+		// the SDK doesn't expose the output dimensionality of the API, so the
+		// embeddings are truncated and normalized again, as the API does.
+		if dims := g.opts.EmbeddingOutputDimensionality; dims > 0 && dims < len(values) {
+			values, err = embeddings.TruncateVector(values, dims)
+			if err != nil {
+				return results, err
+			}
+			values = embeddings.NormalizeVector(values)
+		}
+		results = append(results, values)
 	}
 
 	return results, nil
@@ -0,0 +1,116 @@
+package googleai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTTPOverrides(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant-1", r.Header.Get("X-Tenant-Id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hi!"}]},"finishReason":1}],"usageMetadata":{"promptTokenCount":1,"candidatesTokenCount":2,"totalTokenCount":3}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey("test"), option.WithEndpoint(server.URL))
+	require.NoError(t, err)
+	g := &GoogleAI{client: client, opts: DefaultOptions()}
+
+	resp, err := g.Call(ctx, "Hello", llms.WithHTTPHeader("X-Tenant-Id", "tenant-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hi!", resp)
+
+	_, err = g.Call(ctx, "Hello", llms.WithQueryParam("priority", "high"))
+	require.ErrorIs(t, err, llms.ErrHTTPOverrideUnsupported)
+
+	_, err = g.Call(ctx, "Hello", llms.WithRawParams(map[string]any{"cachedContent": "cache-1"}))
+	require.ErrorIs(t, err, llms.ErrRawParamsUnsupported)
+}
+
+func TestCreateEmbeddingOutputDimensionality(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"embedding":{"values":[3,4,12]}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey("test"), option.WithEndpoint(server.URL))
+	require.NoError(t, err)
+	opts := DefaultOptions()
+	WithEmbeddingOutputDimensionality(2)(&opts)
+	g := &GoogleAI{client: client, opts: opts}
+
+	vectors, err := g.CreateEmbedding(ctx, []string{"hello"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 1)
+	assert.InDeltaSlice(t, []float32{0.6, 0.8}, vectors[0], 1e-6)
+}
+
+func TestWrapError(t *testing.T) {
+	t.Parallel()
+	errOther := errors.New("other")
+	tests := []struct {
+		name string
+		err  error
+		want llms.ErrorCode
+	}{
+		{"grpc quota", status.Error(codes.ResourceExhausted, "Quota exceeded"), llms.ErrorCodeRateLimited},
+		{"grpc api key", fmt.Errorf("stream: %w", status.Error(codes.PermissionDenied, "API key not valid")), llms.ErrorCodeAuthFailed},
+		{"grpc unavailable", status.Error(codes.Unavailable, "The model is overloaded"), llms.ErrorCodeOverloaded},
+		{"grpc invalid", status.Error(codes.InvalidArgument, "Request contains an invalid argument"), llms.ErrorCodeInvalidRequest},
+		{"rest quota", &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Resource has been exhausted"}, llms.ErrorCodeRateLimited},
+		{"rest context length", &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: "The input token count exceeds the maximum number of tokens allowed",
+		}, llms.ErrorCodeContextLengthExceeded},
+		{"blocked", &genai.BlockedError{}, llms.ErrorCodeContentFiltered},
+	}
+	for _, tt := range tests {
+		err := wrapError(tt.err)
+		assert.Equal(t, tt.want, llms.ErrorCodeOf(err), tt.name)
+		assert.ErrorIs(t, err, tt.err, tt.name)
+	}
+
+	assert.Same(t, errOther, wrapError(errOther))
+	assert.NoError(t, wrapError(nil))
+}
+
+func TestMediaPart(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	part, err := mediaPart(ctx, llms.AudioContent{}, "audio/mpeg", nil, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, genai.Blob{MIMEType: "audio/mpeg", Data: []byte("audio")}, part)
+
+	for _, uri := range []string{"gs://bucket/video.mp4", "https://generativelanguage.googleapis.com/v1beta/files/abc"} {
+		part, err = mediaPart(ctx, llms.VideoContent{}, "video/mp4", nil, uri)
+		require.NoError(t, err)
+		assert.Equal(t, genai.FileData{MIMEType: "video/mp4", URI: uri}, part)
+	}
+
+	_, err = mediaPart(ctx, llms.VideoContent{}, "video/mp4", nil, "file:///etc/passwd")
+	require.ErrorIs(t, err, llms.ErrUnsupportedContentPart)
+}
@@ -36,6 +36,7 @@ func main() {
 			}
 			addCastToTopK(x)
 			removeTokenCount(x)
+			renameFileDataURI(x)
 		}
 
 		return true
@@ -105,6 +106,29 @@ func removeTokenCount(fun *ast.FuncDecl) {
 	})
 }
 
+// renameFileDataURI renames the URI field of genai.FileData literals, which is
+// called FileURI in the vertex package.
+func renameFileDataURI(fun *ast.FuncDecl) {
+	ast.Inspect(fun, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || getIdentName(sel.X) != "genai" || getIdentName(sel.Sel) != "FileData" {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "URI" {
+					key.Name = "FileURI"
+				}
+			}
+		}
+		return true
+	})
+}
+
 // getIdentName returns the identifier name from ast.Ident expressions; for
 // other expressions, returns an empty string.
 func getIdentName(x ast.Expr) string {
@@ -2,6 +2,7 @@ package palmclient
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"runtime"
@@ -41,6 +42,10 @@ const (
 	defaultMaxConns = 4
 )
 
+// MultimodalEmbeddingModelName is the model embedding images in the same
+// space as texts.
+const MultimodalEmbeddingModelName = "multimodalembedding@001"
+
 // PaLMClient represents a Vertex AI based PaLM API client.
 type PaLMClient struct {
 	client    *aiplatform.PredictionClient
@@ -118,12 +123,26 @@ func (c *PaLMClient) CreateCompletion(ctx context.Context, r *CompletionRequest)
 // EmbeddingRequest is a request to create an embedding.
 type EmbeddingRequest struct {
 	Input []string `json:"input"`
+	// TaskType is the task type of the embeddings, e.g. "RETRIEVAL_QUERY".
+	TaskType string `json:"task_type,omitempty"`
+	// OutputDimensionality is the size of the embeddings, if non-zero.
+	OutputDimensionality int `json:"output_dimensionality,omitempty"`
 }
 
 // CreateEmbedding creates embeddings.
 func (c *PaLMClient) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]float32, error) {
 	params := map[string]interface{}{}
-	responses, err := c.batchPredict(ctx, embeddingModelName, r.Input, params)
+	if r.OutputDimensionality > 0 {
+		params["outputDimensionality"] = r.OutputDimensionality
+	}
+	instances := make([]map[string]interface{}, len(r.Input))
+	for i, text := range r.Input {
+		instances[i] = map[string]interface{}{"content": text}
+		if r.TaskType != "" {
+			instances[i]["task_type"] = r.TaskType
+		}
+	}
+	responses, err := c.predict(ctx, embeddingModelName, instances, params)
 	if err != nil {
 		return nil, err
 	}
@@ -139,23 +158,118 @@ func (c *PaLMClient) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) (
 		if !ok {
 			return nil, fmt.Errorf("%w: %v", ErrMissingValue, "values")
 		}
-		floatValues := []float32{}
-		for _, v := range values {
-			val, ok := v.(float32)
-			if !ok {
-				valF64, ok := v.(float64)
-				if !ok {
-					return nil, fmt.Errorf("%w: %v is not a float64 or float32, it is a %T", ErrInvalidValue, "value", v)
-				}
-				val = float32(valF64)
-			}
-			floatValues = append(floatValues, val)
+		floatValues, err := toFloat32s(values)
+		if err != nil {
+			return nil, err
 		}
 		embeddings = append(embeddings, floatValues)
 	}
 	return embeddings, nil
 }
 
+// ImageEmbeddingRequest is a request to create embeddings of images.
+type ImageEmbeddingRequest struct {
+	// Images are the encoded images.
+	Images [][]byte
+	// Dimension is the size of the embeddings, 128, 256, 512 or 1408. If
+	// zero, the model default of 1408 is used.
+	Dimension int
+}
+
+// CreateImageEmbedding creates embeddings of images with the multimodal
+// embedding model.
+func (c *PaLMClient) CreateImageEmbedding(ctx context.Context, r *ImageEmbeddingRequest) ([][]float32, error) {
+	instances := make([]map[string]interface{}, len(r.Images))
+	for i, image := range r.Images {
+		instances[i] = map[string]interface{}{
+			"image": map[string]interface{}{
+				"bytesBase64Encoded": base64.StdEncoding.EncodeToString(image),
+			},
+		}
+	}
+	return c.multimodalEmbedding(ctx, instances, "imageEmbedding", r.Dimension)
+}
+
+// MultimodalTextEmbeddingRequest is a request to create embeddings of texts in
+// the vector space of the images.
+type MultimodalTextEmbeddingRequest struct {
+	// Texts are the texts to embed.
+	Texts []string
+	// Dimension is the size of the embeddings, see ImageEmbeddingRequest.
+	Dimension int
+}
+
+// CreateMultimodalTextEmbedding creates embeddings of texts with the
+// multimodal embedding model, so that they can be compared with the
+// embeddings of CreateImageEmbedding.
+func (c *PaLMClient) CreateMultimodalTextEmbedding(ctx context.Context, r *MultimodalTextEmbeddingRequest) ([][]float32, error) { //nolint:lll
+	instances := make([]map[string]interface{}, len(r.Texts))
+	for i, text := range r.Texts {
+		instances[i] = map[string]interface{}{"text": text}
+	}
+	return c.multimodalEmbedding(ctx, instances, "textEmbedding", r.Dimension)
+}
+
+// multimodalEmbedding embeds the instances with the multimodal embedding
+// model, which accepts a single instance per request, and returns the
+// embeddings found under key in the predictions.
+func (c *PaLMClient) multimodalEmbedding(ctx context.Context, instances []map[string]interface{}, key string, dimension int) ([][]float32, error) { //nolint:lll
+	params := map[string]interface{}{}
+	if dimension > 0 {
+		params["dimension"] = dimension
+	}
+	parameters, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, 0, len(instances))
+	for _, fields := range instances {
+		instance, err := structpb.NewStruct(fields)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.client.Predict(ctx, &aiplatformpb.PredictRequest{
+			Endpoint:   c.projectLocationPublisherModelPath(c.projectID, defaultLocation, defaultPublisher, MultimodalEmbeddingModelName), //nolint:lll
+			Instances:  []*structpb.Value{structpb.NewStructValue(instance)},
+			Parameters: structpb.NewStructValue(parameters),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.GetPredictions()) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		value := resp.GetPredictions()[0].GetStructValue().AsMap()
+		values, ok := value[key].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrMissingValue, key)
+		}
+		floatValues, err := toFloat32s(values)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, floatValues)
+	}
+	return embeddings, nil
+}
+
+func toFloat32s(values []interface{}) ([]float32, error) {
+	floatValues := make([]float32, 0, len(values))
+	for _, v := range values {
+		val, ok := v.(float32)
+		if !ok {
+			valF64, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%w: %v is not a float64 or float32, it is a %T", ErrInvalidValue, "value", v)
+			}
+			val = float32(valF64)
+		}
+		floatValues = append(floatValues, val)
+	}
+	return floatValues, nil
+}
+
 // ChatRequest is a request to create an embedding.
 type ChatRequest struct {
 	Context        string         `json:"context"`
@@ -278,12 +392,20 @@ func convertArray(value []string) interface{} {
 }
 
 func (c *PaLMClient) batchPredict(ctx context.Context, model string, prompts []string, params map[string]interface{}) ([]*structpb.Value, error) { //nolint:lll
+	instances := make([]map[string]interface{}, len(prompts))
+	for i, prompt := range prompts {
+		instances[i] = map[string]interface{}{
+			"content": prompt,
+		}
+	}
+	return c.predict(ctx, model, instances, params)
+}
+
+func (c *PaLMClient) predict(ctx context.Context, model string, instanceFields []map[string]interface{}, params map[string]interface{}) ([]*structpb.Value, error) { //nolint:lll
 	mergedParams := mergeParams(defaultParameters, params)
 	instances := []*structpb.Value{}
-	for _, prompt := range prompts {
-		content, _ := structpb.NewStruct(map[string]interface{}{
-			"content": prompt,
-		})
+	for _, fields := range instanceFields {
+		content, _ := structpb.NewStruct(fields)
 		instances = append(instances, structpb.NewStructValue(content))
 	}
 	resp, err := c.client.Predict(ctx, &aiplatformpb.PredictRequest{
@@ -18,7 +18,11 @@ type GoogleAI struct {
 	opts             Options
 }
 
-var _ llms.Model = &GoogleAI{}
+var (
+	_ llms.Model        = &GoogleAI{}
+	_ llms.TokenCounter = &GoogleAI{}
+	_ llms.ContextSizer = &GoogleAI{}
+)
 
 // New creates a new GoogleAI client.
 func New(ctx context.Context, opts ...Option) (*GoogleAI, error) {
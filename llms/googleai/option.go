@@ -13,6 +13,14 @@ type Options struct {
 	DefaultTopK           int
 	DefaultTopP           float64
 	HarmThreshold         HarmBlockThreshold
+
+	// EmbeddingTaskType is the task type of document embeddings and
+	// EmbeddingQueryTaskType the one of query embeddings.
+	EmbeddingTaskType      EmbeddingTaskType
+	EmbeddingQueryTaskType EmbeddingTaskType
+	// EmbeddingOutputDimensionality reduces the size of the embeddings, if
+	// non-zero. Only newer models such as text-embedding-004 support it.
+	EmbeddingOutputDimensionality int
 }
 
 func DefaultOptions() Options {
@@ -116,6 +124,26 @@ func WithHarmThreshold(ht HarmBlockThreshold) Option {
 	}
 }
 
+// WithEmbeddingTaskType sets the task type of the embeddings of documents
+// and of queries, e.g. EmbeddingTaskTypeRetrievalDocument and
+// EmbeddingTaskTypeRetrievalQuery for retrieval. Queries are only embedded
+// with their own task type by embedders created with embeddings.NewEmbedder.
+func WithEmbeddingTaskType(documents, queries EmbeddingTaskType) Option {
+	return func(opts *Options) {
+		opts.EmbeddingTaskType = documents
+		opts.EmbeddingQueryTaskType = queries
+	}
+}
+
+// WithEmbeddingOutputDimensionality sets the size of the embeddings. The
+// googleai embeddings are truncated and normalized to that size, Vertex AI
+// passes it to the API.
+func WithEmbeddingOutputDimensionality(dimensionality int) Option {
+	return func(opts *Options) {
+		opts.EmbeddingOutputDimensionality = dimensionality
+	}
+}
+
 type HarmBlockThreshold int32
 
 const (
@@ -130,3 +158,41 @@ const (
 	// HarmBlockNone means all content will be allowed.
 	HarmBlockNone HarmBlockThreshold = 4
 )
+
+// EmbeddingTaskType is the intended application of embeddings, which the
+// model optimizes them for.
+type EmbeddingTaskType int32
+
+const (
+	// EmbeddingTaskTypeUnspecified uses the default of the model.
+	EmbeddingTaskTypeUnspecified EmbeddingTaskType = 0
+	// EmbeddingTaskTypeRetrievalQuery is for search queries.
+	EmbeddingTaskTypeRetrievalQuery EmbeddingTaskType = 1
+	// EmbeddingTaskTypeRetrievalDocument is for the searched documents.
+	EmbeddingTaskTypeRetrievalDocument EmbeddingTaskType = 2
+	// EmbeddingTaskTypeSemanticSimilarity is for semantic text similarity.
+	EmbeddingTaskTypeSemanticSimilarity EmbeddingTaskType = 3
+	// EmbeddingTaskTypeClassification is for classification.
+	EmbeddingTaskTypeClassification EmbeddingTaskType = 4
+	// EmbeddingTaskTypeClustering is for clustering.
+	EmbeddingTaskTypeClustering EmbeddingTaskType = 5
+)
+
+// String returns the name of the task type used by the APIs, or an empty
+// string if it is unspecified.
+func (t EmbeddingTaskType) String() string {
+	switch t {
+	case EmbeddingTaskTypeRetrievalQuery:
+		return "RETRIEVAL_QUERY"
+	case EmbeddingTaskTypeRetrievalDocument:
+		return "RETRIEVAL_DOCUMENT"
+	case EmbeddingTaskTypeSemanticSimilarity:
+		return "SEMANTIC_SIMILARITY"
+	case EmbeddingTaskTypeClassification:
+		return "CLASSIFICATION"
+	case EmbeddingTaskTypeClustering:
+		return "CLUSTERING"
+	case EmbeddingTaskTypeUnspecified:
+	}
+	return ""
+}
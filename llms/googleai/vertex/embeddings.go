@@ -5,13 +5,35 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/googleai"
 	"github.com/tmc/langchaingo/llms/googleai/internal/palmclient"
 )
 
+var _ embeddings.QueryEmbedderClient = &Vertex{}
+
 // CreateEmbedding creates embeddings from texts.
 func (g *Vertex) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
-	embeddings, err := g.palmClient.CreateEmbedding(ctx, &palmclient.EmbeddingRequest{
-		Input: texts,
+	return g.createEmbedding(ctx, texts, g.opts.EmbeddingTaskType)
+}
+
+// CreateQueryEmbedding creates embeddings from query texts, with the query
+// task type set by googleai.WithEmbeddingTaskType.
+func (g *Vertex) CreateQueryEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	return g.createEmbedding(ctx, texts, g.opts.EmbeddingQueryTaskType)
+}
+
+func (g *Vertex) createEmbedding(ctx context.Context, texts []string, taskType googleai.EmbeddingTaskType) ([][]float32, error) { //nolint:lll
+	var embeddings [][]float32
+	err := llms.RetryCall(ctx, func(ctx context.Context) error {
+		var err error
+		embeddings, err = g.palmClient.CreateEmbedding(ctx, &palmclient.EmbeddingRequest{
+			Input:                texts,
+			TaskType:             taskType.String(),
+			OutputDimensionality: g.opts.EmbeddingOutputDimensionality,
+		})
+		return wrapError(err)
 	})
 	if err != nil {
 		return [][]float32{}, err
@@ -0,0 +1,78 @@
+package vertex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/googleai"
+	"github.com/tmc/langchaingo/llms/googleai/internal/palmclient"
+)
+
+// MultimodalEmbedder creates embeddings of texts and images in the same
+// vector space with the Vertex AI multimodal embedding model, so that images
+// can be searched with text queries. The vectors are not comparable with
+// those of Vertex.CreateEmbedding.
+type MultimodalEmbedder struct {
+	palmClient *palmclient.PaLMClient
+	dimension  int
+}
+
+var (
+	_ embeddings.EmbedderClient = &MultimodalEmbedder{}
+	_ embeddings.ImageEmbedder  = &MultimodalEmbedder{}
+)
+
+// NewMultimodalEmbedder creates a new multimodal embedder. The size of the
+// embeddings is set with googleai.WithEmbeddingOutputDimensionality to 128,
+// 256, 512 or 1408, the default.
+func NewMultimodalEmbedder(_ context.Context, opts ...googleai.Option) (*MultimodalEmbedder, error) {
+	clientOptions := googleai.DefaultOptions()
+	for _, opt := range opts {
+		opt(&clientOptions)
+	}
+
+	palmClient, err := palmclient.New(clientOptions.CloudProject) //nolint:contextcheck
+	if err != nil {
+		return nil, err
+	}
+	return &MultimodalEmbedder{
+		palmClient: palmClient,
+		dimension:  clientOptions.EmbeddingOutputDimensionality,
+	}, nil
+}
+
+// CreateEmbedding creates embeddings of texts in the vector space of the
+// images.
+func (m *MultimodalEmbedder) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := m.palmClient.CreateMultimodalTextEmbedding(ctx, &palmclient.MultimodalTextEmbeddingRequest{
+		Texts:     texts,
+		Dimension: m.dimension,
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(texts) != len(vectors) {
+		return vectors, fmt.Errorf("returned %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// EmbedImages creates embeddings of images in the vector space of the texts.
+func (m *MultimodalEmbedder) EmbedImages(ctx context.Context, images []embeddings.Image) ([][]float32, error) {
+	data := make([][]byte, len(images))
+	for i, image := range images {
+		data[i] = image.Data
+	}
+	vectors, err := m.palmClient.CreateImageEmbedding(ctx, &palmclient.ImageEmbeddingRequest{
+		Images:    data,
+		Dimension: m.dimension,
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(images) != len(vectors) {
+		return vectors, fmt.Errorf("returned %d embeddings for %d images", len(vectors), len(images))
+	}
+	return vectors, nil
+}
@@ -0,0 +1,66 @@
+package vertex
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/googleai/internal/palmclient"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakePredictionServer answers multimodal embedding requests with a
+// one-dimensional embedding of the length of the text or image.
+type fakePredictionServer struct {
+	aiplatformpb.UnimplementedPredictionServiceServer
+}
+
+func (*fakePredictionServer) Predict(_ context.Context, req *aiplatformpb.PredictRequest) (*aiplatformpb.PredictResponse, error) { //nolint:lll
+	instance := req.GetInstances()[0].GetStructValue().AsMap()
+	prediction := map[string]any{}
+	if text, ok := instance["text"].(string); ok {
+		prediction["textEmbedding"] = []any{float64(len(text))}
+	}
+	if image, ok := instance["image"].(map[string]any); ok {
+		prediction["imageEmbedding"] = []any{float64(len(image["bytesBase64Encoded"].(string)))}
+	}
+	value, err := structpb.NewStruct(prediction)
+	if err != nil {
+		return nil, err
+	}
+	return &aiplatformpb.PredictResponse{Predictions: []*structpb.Value{structpb.NewStructValue(value)}}, nil
+}
+
+func TestMultimodalEmbedder(t *testing.T) {
+	t.Parallel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	aiplatformpb.RegisterPredictionServiceServer(server, &fakePredictionServer{})
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	palmClient, err := palmclient.New("project",
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	require.NoError(t, err)
+	m := &MultimodalEmbedder{palmClient: palmClient}
+
+	ctx := context.Background()
+	vectors, err := m.CreateEmbedding(ctx, []string{"a cat", "a dog on a sofa"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{5}, {15}}, vectors)
+
+	vectors, err = m.EmbedImages(ctx, []embeddings.Image{{MIMEType: "image/png", Data: []byte("png")}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{4}}, vectors)
+}
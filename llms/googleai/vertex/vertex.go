@@ -10,12 +10,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"cloud.google.com/go/vertexai/genai"
+	"github.com/googleapis/gax-go/v2/callctx"
 	"github.com/tmc/langchaingo/internal/util"
 	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -55,6 +61,17 @@ func (g *Vertex) GenerateContent(ctx context.Context, messages []llms.MessageCon
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if opts.ThinkingBudget > 0 {
+		return nil, fmt.Errorf("%w: the genai SDK doesn't expose the thinking config", llms.ErrThinkingUnsupported)
+	}
+	if len(opts.RawParams) > 0 {
+		return nil, fmt.Errorf("%w: the genai SDK doesn't expose the request payload", llms.ErrRawParamsUnsupported)
+	}
+	ctx, err := contextWithHTTPOverrides(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx = llms.ContextWithRetryPolicy(ctx, opts.RetryPolicy)
 
 	model := g.client.GenerativeModel(opts.Model)
 	model.SetCandidateCount(int32(opts.CandidateCount))
@@ -81,7 +98,6 @@ func (g *Vertex) GenerateContent(ctx context.Context, messages []llms.MessageCon
 			Threshold: genai.HarmBlockThreshold(g.opts.HarmThreshold),
 		},
 	}
-	var err error
 	if model.Tools, err = convertTools(opts.Tools); err != nil {
 		return nil, err
 	}
@@ -98,7 +114,7 @@ func (g *Vertex) GenerateContent(ctx context.Context, messages []llms.MessageCon
 		response, err = generateFromMessages(ctx, model, messages, &opts)
 	}
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
 	if g.CallbacksHandler != nil {
@@ -108,7 +124,35 @@ func (g *Vertex) GenerateContent(ctx context.Context, messages []llms.MessageCon
 	return response, nil
 }
 
+// CountTokens implements the [llms.TokenCounter] interface using the
+// countTokens API of the default model.
+func (g *Vertex) CountTokens(ctx context.Context, messages []llms.MessageContent) (int, error) {
+	var parts []genai.Part
+	for _, mc := range messages {
+		converted, err := convertParts(ctx, mc.Parts)
+		if err != nil {
+			return 0, err
+		}
+		parts = append(parts, converted...)
+	}
+
+	model := g.client.GenerativeModel(g.opts.DefaultModel)
+	resp, err := model.CountTokens(ctx, parts...)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// MaxContextTokens implements the [llms.ContextSizer] interface using the
+// model registry.
+func (g *Vertex) MaxContextTokens() int {
+	return llms.GetModelContextSize(g.opts.DefaultModel)
+}
+
 // convertCandidates converts a sequence of genai.Candidate to a response.
+// The SDK doesn't expose the thought parts nor the thoughts token count of
+// thinking models, so ReasoningContent and ReasoningTokens are left empty.
 func convertCandidates(candidates []*genai.Candidate) (choices []*llms.ContentChoice, err error) {
 	var contentResponse llms.ContentResponse
 	var toolCalls []llms.ToolCall
@@ -157,7 +201,7 @@ func convertCandidates(candidates []*genai.Candidate) (choices []*llms.ContentCh
 }
 
 // convertParts converts between a sequence of langchain parts and genai parts.
-func convertParts(parts []llms.ContentPart) ([]genai.Part, error) {
+func convertParts(ctx context.Context, parts []llms.ContentPart) ([]genai.Part, error) {
 	convertedParts := make([]genai.Part, 0, len(parts))
 	for _, part := range parts {
 		var out genai.Part
@@ -173,6 +217,18 @@ func convertParts(parts []llms.ContentPart) ([]genai.Part, error) {
 				return nil, err
 			}
 			out = genai.ImageData(typ, data)
+		case llms.AudioContent:
+			media, err := mediaPart(ctx, p, p.MIMEType, p.Data, p.URL)
+			if err != nil {
+				return nil, err
+			}
+			out = media
+		case llms.VideoContent:
+			media, err := mediaPart(ctx, p, p.MIMEType, p.Data, p.URL)
+			if err != nil {
+				return nil, err
+			}
+			out = media
 		case llms.ToolCall:
 			fc := p.FunctionCall
 			var argsMap map[string]any
@@ -197,9 +253,49 @@ func convertParts(parts []llms.ContentPart) ([]genai.Part, error) {
 	return convertedParts, nil
 }
 
+// mediaPart converts audio or video given as data or URL to a genai part.
+// Cloud Storage URIs are sent as references. The API can't fetch other
+// URLs, so http(s) URLs are downloaded, within the size limit of
+// llms.URLPart, and other URLs are rejected.
+func mediaPart(ctx context.Context, part llms.ContentPart, mimeType string, data []byte, uri string) (genai.Part, error) {
+	if uri == "" {
+		return genai.Blob{MIMEType: mimeType, Data: data}, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "gs":
+		return genai.FileData{MIMEType: mimeType, FileURI: uri}, nil
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("%w: %s URL", llms.UnsupportedContentPartError(part), u.Scheme)
+	}
+
+	var opts []llms.MediaOption
+	if mimeType != "" {
+		opts = append(opts, llms.WithMediaMIMEType(mimeType))
+	}
+	downloaded, err := llms.URLPart(ctx, uri, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch d := downloaded.(type) {
+	case llms.AudioContent:
+		return genai.Blob{MIMEType: d.MIMEType, Data: d.Data}, nil
+	case llms.VideoContent:
+		return genai.Blob{MIMEType: d.MIMEType, Data: d.Data}, nil
+	case llms.BinaryContent:
+		return genai.Blob{MIMEType: d.MIMEType, Data: d.Data}, nil
+	default:
+		return nil, llms.UnsupportedContentPartError(downloaded)
+	}
+}
+
 // convertContent converts between a langchain MessageContent and genai content.
-func convertContent(content llms.MessageContent) (*genai.Content, error) {
-	parts, err := convertParts(content.Parts)
+func convertContent(ctx context.Context, content llms.MessageContent) (*genai.Content, error) {
+	parts, err := convertParts(ctx, content.Parts)
 	if err != nil {
 		return nil, err
 	}
@@ -233,15 +329,20 @@ func convertContent(content llms.MessageContent) (*genai.Content, error) {
 // generateFromSingleMessage generates content from the parts of a single
 // message.
 func generateFromSingleMessage(ctx context.Context, model *genai.GenerativeModel, parts []llms.ContentPart, opts *llms.CallOptions) (*llms.ContentResponse, error) {
-	convertedParts, err := convertParts(parts)
+	convertedParts, err := convertParts(ctx, parts)
 	if err != nil {
 		return nil, err
 	}
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
 		// When no streaming is requested, just call GenerateContent and return
 		// the complete response with a list of candidates.
-		resp, err := model.GenerateContent(ctx, convertedParts...)
+		var resp *genai.GenerateContentResponse
+		err := llms.RetryCall(ctx, func(ctx context.Context) error {
+			var err error
+			resp, err = model.GenerateContent(ctx, convertedParts...)
+			return wrapError(err)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -261,14 +362,19 @@ func generateFromSingleMessage(ctx context.Context, model *genai.GenerativeModel
 		}, err
 
 	}
-	iter := model.GenerateContentStream(ctx, convertedParts...)
-	return convertAndStreamFromIterator(ctx, iter, opts)
+	next, err := startStream(ctx, func(ctx context.Context) *genai.GenerateContentResponseIterator {
+		return model.GenerateContentStream(ctx, convertedParts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertAndStreamFromIterator(ctx, next, opts)
 }
 
 func generateFromMessages(ctx context.Context, model *genai.GenerativeModel, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
 	history := make([]*genai.Content, 0, len(messages))
 	for _, mc := range messages {
-		content, err := convertContent(mc)
+		content, err := convertContent(ctx, mc)
 		if err != nil {
 			return nil, err
 		}
@@ -294,10 +400,17 @@ func generateFromMessages(ctx context.Context, model *genai.GenerativeModel, mes
 	history = history[:n-1]
 
 	session := model.StartChat()
-	session.History = history
 
-	if opts.StreamingFunc == nil {
-		resp, err := session.SendMessage(ctx, reqContent.Parts...)
+	// the session adds the request to its history before sending it, so the
+	// history is reset before each attempt.
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
+		var resp *genai.GenerateContentResponse
+		err := llms.RetryCall(ctx, func(ctx context.Context) error {
+			session.History = history
+			var err error
+			resp, err = session.SendMessage(ctx, reqContent.Parts...)
+			return wrapError(err)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -315,23 +428,60 @@ func generateFromMessages(ctx context.Context, model *genai.GenerativeModel, mes
 			},
 		}, err
 	}
-	iter := session.SendMessageStream(ctx, reqContent.Parts...)
-	return convertAndStreamFromIterator(ctx, iter, opts)
+	next, err := startStream(ctx, func(ctx context.Context) *genai.GenerateContentResponseIterator {
+		session.History = history
+		return session.SendMessageStream(ctx, reqContent.Parts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertAndStreamFromIterator(ctx, next, opts)
 }
 
-// convertAndStreamFromIterator takes an iterator of GenerateContentResponse
+// startStream starts a stream with start, retrying it according to the retry
+// policy of the context until its first response is received, and returns the
+// function returning the responses of the stream.
+func startStream(ctx context.Context, start func(ctx context.Context) *genai.GenerateContentResponseIterator) (func() (*genai.GenerateContentResponse, error), error) { //nolint:lll
+	var iter *genai.GenerateContentResponseIterator
+	var first *genai.GenerateContentResponse
+	var firstErr error
+	err := llms.RetryCall(ctx, func(ctx context.Context) error {
+		iter = start(ctx)
+		first, firstErr = iter.Next()
+		if errors.Is(firstErr, iterator.Done) {
+			return nil
+		}
+		return wrapError(firstErr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	started := false
+	return func() (*genai.GenerateContentResponse, error) {
+		if !started {
+			started = true
+			return first, firstErr
+		}
+		return iter.Next()
+	}, nil
+}
+
+// convertAndStreamFromIterator takes the next function of a stream of GenerateContentResponse
 // and produces a llms.ContentResponse reply from it, while streaming the
-// resulting text into the opts-provided streaming function.
+// resulting text into the opts-provided streaming function and typed events
+// into the streaming event function.
 // Note that this is tricky in the face of multiple
 // candidates, so this code assumes only a single candidate for now.
-func convertAndStreamFromIterator(ctx context.Context, iter *genai.GenerateContentResponseIterator, opts *llms.CallOptions) (*llms.ContentResponse, error) {
+func convertAndStreamFromIterator(ctx context.Context, next func() (*genai.GenerateContentResponse, error), opts *llms.CallOptions) (*llms.ContentResponse, error) { //nolint:lll
 	candidate := &genai.Candidate{
 		Content: &genai.Content{},
 	}
 	usage := llms.Usage{}
+	var toolCount int
 DoStream:
 	for {
-		resp, err := iter.Next()
+		resp, err := next()
 		if errors.Is(err, iterator.Done) {
 			break DoStream
 		}
@@ -358,15 +508,53 @@ DoStream:
 				CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
 				TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
 			}
+			usage := usage
+			if err := sendEvent(ctx, opts, llms.StreamingEvent{
+				Type:  llms.StreamingEventUsageUpdate,
+				Usage: &usage,
+			}); err != nil {
+				return nil, err
+			}
 		}
 		for _, part := range respCandidate.Content.Parts {
-			if text, ok := part.(genai.Text); ok {
-				if opts.StreamingFunc(ctx, []byte(text)) != nil {
+			switch v := part.(type) {
+			case genai.Text:
+				if opts.StreamingFunc != nil && opts.StreamingFunc(ctx, []byte(v)) != nil {
 					break DoStream
 				}
+				if err := sendEvent(ctx, opts, llms.StreamingEvent{
+					Type: llms.StreamingEventTextDelta,
+					Text: string(v),
+				}); err != nil {
+					return nil, err
+				}
+			case genai.FunctionCall:
+				args, err := json.Marshal(v.Args)
+				if err != nil {
+					return nil, err
+				}
+				if err := sendEvent(ctx, opts, llms.StreamingEvent{
+					Type:          llms.StreamingEventToolCallDelta,
+					ToolCallIndex: toolCount,
+					ToolCall: &llms.ToolCall{
+						FunctionCall: &llms.FunctionCall{
+							Name:      v.Name,
+							Arguments: string(args),
+						},
+					},
+				}); err != nil {
+					return nil, err
+				}
+				toolCount++
 			}
 		}
 	}
+	if err := sendEvent(ctx, opts, llms.StreamingEvent{
+		Type:       llms.StreamingEventDone,
+		StopReason: candidate.FinishReason.String(),
+	}); err != nil {
+		return nil, err
+	}
 
 	choices, err := convertCandidates([]*genai.Candidate{candidate})
 	return &llms.ContentResponse{
@@ -375,6 +563,66 @@ DoStream:
 	}, err
 }
 
+// sendEvent sends a typed streaming event to the streaming event function of
+// opts, if any.
+func sendEvent(ctx context.Context, opts *llms.CallOptions, event llms.StreamingEvent) error {
+	if opts.StreamingEventFunc == nil {
+		return nil
+	}
+	if err := opts.StreamingEventFunc(ctx, event); err != nil {
+		return fmt.Errorf("streaming event func returned an error: %w", err)
+	}
+	return nil
+}
+
+// nolint:gochecknoglobals
+var _grpcStatusCodes = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// wrapError classifies an error of the API as an llms.LLMError. The REST
+// transport returns googleapi.Error, the gRPC transport errors carrying a gRPC
+// status. Blocked prompts and responses are classified as filtered content.
+// Other errors are returned unchanged.
+func wrapError(err error) error {
+	var llmErr *llms.LLMError
+	var apiErr *googleapi.Error
+	var blockedErr *genai.BlockedError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &llmErr):
+		// the error is already classified, e.g. by a retried call.
+		return err
+	case errors.As(err, &blockedErr):
+		llmErr = &llms.LLMError{Code: llms.ErrorCodeContentFiltered}
+	case errors.As(err, &apiErr):
+		var reason string
+		if len(apiErr.Errors) > 0 {
+			reason = apiErr.Errors[0].Reason
+		}
+		llmErr = llms.NewLLMError(apiErr.Code, reason, apiErr.Message, []byte(apiErr.Body))
+	default:
+		st, ok := status.FromError(err)
+		if !ok {
+			return err
+		}
+		llmErr = llms.NewLLMError(_grpcStatusCodes[st.Code()], st.Code().String(), st.Message(), nil)
+	}
+	llmErr.Message = err.Error()
+	llmErr.Err = err
+	return llmErr
+}
+
 // convertTools converts from a list of langchaingo tools to a list of genai
 // tools.
 func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
@@ -491,3 +739,18 @@ func showContent(w io.Writer, cs []*genai.Content) {
 		}
 	}
 }
+
+// contextWithHTTPOverrides returns a context setting the extra HTTP headers of
+// the call options on the requests of the SDK. The SDK doesn't support extra
+// query parameters.
+func contextWithHTTPOverrides(ctx context.Context, opts llms.CallOptions) (context.Context, error) {
+	if len(opts.QueryParams) > 0 {
+		return nil, fmt.Errorf("%w: the genai SDK doesn't support extra query parameters", llms.ErrHTTPOverrideUnsupported)
+	}
+	for key, values := range opts.HTTPHeaders {
+		for _, value := range values {
+			ctx = callctx.SetHeaders(ctx, key, value)
+		}
+	}
+	return ctx, nil
+}
@@ -0,0 +1,67 @@
+package vertex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/vertexai/genai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapError(t *testing.T) {
+	t.Parallel()
+	errOther := errors.New("other")
+	tests := []struct {
+		name string
+		err  error
+		want llms.ErrorCode
+	}{
+		{"grpc quota", status.Error(codes.ResourceExhausted, "Quota exceeded"), llms.ErrorCodeRateLimited},
+		{"grpc api key", fmt.Errorf("stream: %w", status.Error(codes.PermissionDenied, "API key not valid")), llms.ErrorCodeAuthFailed},
+		{"grpc unavailable", status.Error(codes.Unavailable, "The model is overloaded"), llms.ErrorCodeOverloaded},
+		{"grpc invalid", status.Error(codes.InvalidArgument, "Request contains an invalid argument"), llms.ErrorCodeInvalidRequest},
+		{"rest quota", &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Resource has been exhausted"}, llms.ErrorCodeRateLimited},
+		{"rest context length", &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: "The input token count exceeds the maximum number of tokens allowed",
+		}, llms.ErrorCodeContextLengthExceeded},
+		{"blocked", &genai.BlockedError{}, llms.ErrorCodeContentFiltered},
+	}
+	for _, tt := range tests {
+		err := wrapError(tt.err)
+		assert.Equal(t, tt.want, llms.ErrorCodeOf(err), tt.name)
+		assert.ErrorIs(t, err, tt.err, tt.name)
+	}
+
+	assert.Same(t, errOther, wrapError(errOther))
+	assert.NoError(t, wrapError(nil))
+}
+
+func TestMediaPart(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	part, err := mediaPart(ctx, llms.AudioContent{}, "audio/mpeg", nil, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, genai.Blob{MIMEType: "audio/mpeg", Data: []byte("audio")}, part)
+
+	part, err = mediaPart(ctx, llms.VideoContent{}, "video/mp4", nil, "gs://bucket/video.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, genai.FileData{MIMEType: "video/mp4", FileURI: "gs://bucket/video.mp4"}, part)
+
+	_, err = mediaPart(ctx, llms.VideoContent{}, "video/mp4", nil, "file:///etc/passwd")
+	require.ErrorIs(t, err, llms.ErrUnsupportedContentPart)
+}
@@ -0,0 +1,155 @@
+package guardrails
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// nolint:gochecknoglobals
+var promptInjectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget)\s+(all\s+|any\s+)?(the\s+)?(previous|prior|above|earlier|preceding)\s+(instructions|prompts|rules|messages)`),
+	regexp.MustCompile(`(?i)\b(reveal|print|show|repeat|output)\s+(me\s+)?(your|the)\s+(system\s+prompt|hidden\s+instructions|initial\s+instructions)`),
+	regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(in\s+)?(developer|dan|jailbreak|unrestricted)\b`),
+	regexp.MustCompile(`(?i)\bact\s+as\s+if\s+you\s+have\s+no\s+(restrictions|rules|guidelines)`),
+	regexp.MustCompile(`(?i)<\s*/?\s*(system|im_start|im_end)\s*>`),
+}
+
+// PromptInjection returns a checker detecting common prompt injection
+// phrases, such as asking the model to ignore previous instructions. It is a
+// heuristic and can't redact its violations.
+func PromptInjection() Checker {
+	return CheckerFunc(func(_ context.Context, text string) (CheckResult, error) {
+		var result CheckResult
+		for _, pattern := range promptInjectionPatterns {
+			if match := pattern.FindString(text); match != "" {
+				result.Violations = append(result.Violations, Violation{
+					Checker: "prompt_injection",
+					Reason:  "possible prompt injection",
+					Match:   match,
+				})
+			}
+		}
+		return result, nil
+	})
+}
+
+// PIIKind is a kind of personally identifiable information.
+type PIIKind string
+
+const (
+	PIIEmail      PIIKind = "EMAIL"
+	PIIPhone      PIIKind = "PHONE"
+	PIICreditCard PIIKind = "CREDIT_CARD"
+	PIISSN        PIIKind = "SSN"
+)
+
+// nolint:gochecknoglobals
+var piiPatterns = []struct {
+	kind    PIIKind
+	pattern *regexp.Regexp
+}{
+	{PIIEmail, regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{PIICreditCard, regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)},
+	{PIISSN, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{PIIPhone, regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?\(?\b\d{3}\)?[ .\-]?\d{3}[ .\-]?\d{4}\b`)},
+}
+
+// PII returns a checker detecting email addresses, phone numbers, credit
+// card numbers and US social security numbers. Matches are redacted as
+// [KIND], e.g. [EMAIL]. If no kinds are given, all kinds are detected.
+func PII(kinds ...PIIKind) Checker {
+	enabled := make(map[PIIKind]bool, len(kinds))
+	for _, kind := range kinds {
+		enabled[kind] = true
+	}
+
+	return CheckerFunc(func(_ context.Context, text string) (CheckResult, error) {
+		var result CheckResult
+		redacted := text
+		for _, p := range piiPatterns {
+			if len(enabled) > 0 && !enabled[p.kind] {
+				continue
+			}
+			redacted = p.pattern.ReplaceAllStringFunc(redacted, func(match string) string {
+				if p.kind == PIICreditCard && !luhnValid(match) {
+					return match
+				}
+				result.Violations = append(result.Violations, Violation{
+					Checker: "pii",
+					Reason:  "contains " + strings.ToLower(strings.ReplaceAll(string(p.kind), "_", " ")),
+					Match:   match,
+				})
+				return "[" + string(p.kind) + "]"
+			})
+		}
+		if len(result.Violations) > 0 {
+			result.Redacted = redacted
+		}
+		return result, nil
+	})
+}
+
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// Denylist returns a checker detecting the given words or phrases, ignoring
+// case. Matches are redacted as asterisks.
+func Denylist(terms ...string) Checker {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return CheckerFunc(func(context.Context, string) (CheckResult, error) {
+			return CheckResult{}, nil
+		})
+	}
+	return regexChecker("denylist", "contains denied term",
+		regexp.MustCompile(`(?i)\b(`+strings.Join(quoted, "|")+`)\b`),
+		func(match string) string { return strings.Repeat("*", len(match)) })
+}
+
+// Regex returns a checker reporting matches of the pattern as violations of
+// the named policy. Matches are redacted as [REDACTED].
+func Regex(name string, pattern *regexp.Regexp) Checker {
+	return regexChecker(name, "matches policy "+name, pattern,
+		func(string) string { return "[REDACTED]" })
+}
+
+func regexChecker(name, reason string, pattern *regexp.Regexp, replace func(string) string) Checker {
+	return CheckerFunc(func(_ context.Context, text string) (CheckResult, error) {
+		var result CheckResult
+		redacted := pattern.ReplaceAllStringFunc(text, func(match string) string {
+			result.Violations = append(result.Violations, Violation{
+				Checker: name,
+				Reason:  reason,
+				Match:   match,
+			})
+			return replace(match)
+		})
+		if len(result.Violations) > 0 {
+			result.Redacted = redacted
+		}
+		return result, nil
+	})
+}
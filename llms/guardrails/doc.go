@@ -0,0 +1,5 @@
+// Package guardrails provides a wrapper around a `llms.Model` that checks the input messages
+// before generation and the generated content after it. Checkers report violations, which
+// either block the call with a *ViolationError or, with redaction enabled, are redacted from
+// the text.
+package guardrails
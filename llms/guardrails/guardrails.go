@@ -0,0 +1,182 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrViolation is returned when a checker reports a violation that is not
+// redacted.
+var ErrViolation = errors.New("guardrail violation")
+
+// Stage is the stage of a call at which a check runs.
+type Stage string
+
+const (
+	// StageInput checks the text of the messages sent to the model.
+	StageInput Stage = "input"
+	// StageOutput checks the content generated by the model.
+	StageOutput Stage = "output"
+)
+
+// Violation is a policy violation found by a checker.
+type Violation struct {
+	// Checker is the name of the checker reporting the violation.
+	Checker string
+	// Reason describes the violation.
+	Reason string
+	// Match is the offending text, if any.
+	Match string
+}
+
+// CheckResult is the result of a check.
+type CheckResult struct {
+	// Violations are the violations found in the text.
+	Violations []Violation
+	// Redacted is the text with the violations redacted. It is empty if the
+	// checker can't redact its violations.
+	Redacted string
+}
+
+// Checker checks a text against a policy.
+type Checker interface {
+	Check(ctx context.Context, text string) (CheckResult, error)
+}
+
+// CheckerFunc is an adapter to use a function as a Checker, e.g. to call a
+// moderation API.
+type CheckerFunc func(ctx context.Context, text string) (CheckResult, error)
+
+// Check implements the Checker interface.
+func (f CheckerFunc) Check(ctx context.Context, text string) (CheckResult, error) {
+	return f(ctx, text)
+}
+
+// ViolationError is returned when a call is blocked by violations.
+type ViolationError struct {
+	Stage      Stage
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", v.Checker, v.Reason))
+	}
+	return fmt.Sprintf("%s in %s: %s", ErrViolation, e.Stage, strings.Join(reasons, "; "))
+}
+
+func (e *ViolationError) Unwrap() error {
+	return ErrViolation
+}
+
+// Guarded is an LLM wrapper that runs checkers on the input and output of
+// the wrapped model.
+type Guarded struct {
+	llm  llms.Model
+	opts Options
+}
+
+// assert that `Guarded` implements the `llms.Model` interface.
+var _ llms.Model = (*Guarded)(nil)
+
+// New wraps a Model with the given checkers.
+func New(llm llms.Model, opts ...Option) *Guarded {
+	g := &Guarded{llm: llm}
+	for _, opt := range opts {
+		opt(&g.opts)
+	}
+	return g
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (g *Guarded) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, g, prompt, options...)
+}
+
+// GenerateContent checks the text parts of the messages, generates content
+// with the wrapped model and checks the generated choices. Streamed chunks
+// are sent before the output is checked.
+func (g *Guarded) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	checked, err := g.checkMessages(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.llm.GenerateContent(ctx, checked, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, choice := range response.Choices {
+		content, err := g.check(ctx, StageOutput, g.opts.OutputCheckers, choice.Content)
+		if err != nil {
+			return nil, err
+		}
+		choice.Content = content
+	}
+	return response, nil
+}
+
+func (g *Guarded) checkMessages(ctx context.Context, messages []llms.MessageContent) ([]llms.MessageContent, error) {
+	if len(g.opts.InputCheckers) == 0 {
+		return messages, nil
+	}
+
+	checked := make([]llms.MessageContent, len(messages))
+	for i, msg := range messages {
+		parts := make([]llms.ContentPart, len(msg.Parts))
+		for j, part := range msg.Parts {
+			text, ok := part.(llms.TextContent)
+			if !ok {
+				parts[j] = part
+				continue
+			}
+			content, err := g.check(ctx, StageInput, g.opts.InputCheckers, text.Text)
+			if err != nil {
+				return nil, err
+			}
+			parts[j] = llms.TextPart(content)
+		}
+		checked[i] = llms.MessageContent{Role: msg.Role, Parts: parts}
+	}
+	return checked, nil
+}
+
+// check runs the checkers on text and returns the text with redactions.
+func (g *Guarded) check(ctx context.Context, stage Stage, checkers []Checker, text string) (string, error) {
+	var blocking []Violation
+	for _, checker := range checkers {
+		result, err := checker.Check(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		if len(result.Violations) == 0 {
+			continue
+		}
+		if g.opts.Redact && result.Redacted != "" {
+			text = result.Redacted
+			continue
+		}
+		blocking = append(blocking, result.Violations...)
+	}
+
+	if len(blocking) > 0 {
+		err := &ViolationError{Stage: stage, Violations: blocking}
+		if g.opts.OnViolation != nil {
+			g.opts.OnViolation(ctx, err)
+		}
+		return "", err
+	}
+	return text, nil
+}
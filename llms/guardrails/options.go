@@ -0,0 +1,49 @@
+package guardrails
+
+import "context"
+
+// Option is a function that configures a Guarded model.
+type Option func(*Options)
+
+// Options are the options of a Guarded model.
+type Options struct {
+	// InputCheckers check the text parts of the input messages.
+	InputCheckers []Checker
+	// OutputCheckers check the content of the generated choices.
+	OutputCheckers []Checker
+	// Redact replaces violations with the redacted text of the checkers
+	// instead of blocking the call, when the checkers support it.
+	Redact bool
+	// OnViolation is called when a call is blocked.
+	OnViolation func(ctx context.Context, err *ViolationError)
+}
+
+// WithInputCheckers adds checkers run on the input messages.
+func WithInputCheckers(checkers ...Checker) Option {
+	return func(o *Options) {
+		o.InputCheckers = append(o.InputCheckers, checkers...)
+	}
+}
+
+// WithOutputCheckers adds checkers run on the generated content.
+func WithOutputCheckers(checkers ...Checker) Option {
+	return func(o *Options) {
+		o.OutputCheckers = append(o.OutputCheckers, checkers...)
+	}
+}
+
+// WithRedaction redacts violations instead of blocking the call when the
+// checker supports redaction.
+func WithRedaction() Option {
+	return func(o *Options) {
+		o.Redact = true
+	}
+}
+
+// WithViolationHandler sets a function called when a call is blocked, e.g.
+// to log violations.
+func WithViolationHandler(fn func(ctx context.Context, err *ViolationError)) Option {
+	return func(o *Options) {
+		o.OnViolation = fn
+	}
+}
@@ -0,0 +1,85 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ErrHTTPOverrideUnsupported is returned by providers that can't set the extra
+// HTTP headers or query parameters of a call on their requests.
+var ErrHTTPOverrideUnsupported = errors.New("HTTP override not supported")
+
+// HTTPDoer sends HTTP requests, e.g. an *http.Client.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WithHTTPHeader will add an option to set an extra HTTP header on the
+// requests sent to the provider, e.g. a tenant ID for an API gateway. It
+// replaces any value set by the provider client.
+func WithHTTPHeader(key, value string) CallOption {
+	return func(o *CallOptions) {
+		if o.HTTPHeaders == nil {
+			o.HTTPHeaders = make(http.Header)
+		}
+		o.HTTPHeaders.Set(key, value)
+	}
+}
+
+// WithQueryParam will add an option to set an extra URL query parameter on
+// the requests sent to the provider.
+func WithQueryParam(key, value string) CallOption {
+	return func(o *CallOptions) {
+		if o.QueryParams == nil {
+			o.QueryParams = make(url.Values)
+		}
+		o.QueryParams.Set(key, value)
+	}
+}
+
+type httpOverrides struct {
+	headers http.Header
+	query   url.Values
+}
+
+type httpOverridesKey struct{}
+
+// ContextWithHTTPOverrides returns a context carrying extra headers and query
+// parameters set on the requests sent with DoRequest.
+func ContextWithHTTPOverrides(ctx context.Context, headers http.Header, query url.Values) context.Context {
+	if len(headers) == 0 && len(query) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, httpOverridesKey{}, httpOverrides{headers: headers, query: query})
+}
+
+// ContextWithRequestOptions returns a context carrying the options of a call
+// that apply to the HTTP requests of the provider clients: the retry policy
+// and the extra headers and query parameters. Providers call it once the call
+// options are parsed and send their requests with DoRequest.
+func ContextWithRequestOptions(ctx context.Context, opts CallOptions) context.Context {
+	ctx = ContextWithRetryPolicy(ctx, opts.RetryPolicy)
+	return ContextWithHTTPOverrides(ctx, opts.HTTPHeaders, opts.QueryParams)
+}
+
+// DoRequest sends a request with the doer, applying the request options of
+// the request context: extra headers and query parameters are set and failed
+// requests are retried according to the retry policy. Without request
+// options the request is sent once, unchanged.
+func DoRequest(doer HTTPDoer, req *http.Request) (*http.Response, error) {
+	if overrides, ok := req.Context().Value(httpOverridesKey{}).(httpOverrides); ok {
+		for key, values := range overrides.headers {
+			req.Header[key] = values
+		}
+		if len(overrides.query) > 0 {
+			query := req.URL.Query()
+			for key, values := range overrides.query {
+				query[key] = values
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+	}
+	return doWithRetry(doer, req, RetryPolicyFromContext(req.Context()))
+}
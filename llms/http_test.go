@@ -0,0 +1,34 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRequestOverrides(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Tenant-Id") + " " + r.URL.RawQuery))
+	}))
+	defer server.Close()
+
+	opts := CallOptions{}
+	WithHTTPHeader("x-tenant-id", "acme")(&opts)
+	WithQueryParam("priority", "high")(&opts)
+	ctx := ContextWithRequestOptions(context.Background(), opts)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?api-version=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Tenant-Id", "default")
+	resp, err := DoRequest(http.DefaultClient, req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	require.Equal(t, "acme api-version=1&priority=high", string(body[:n]))
+}
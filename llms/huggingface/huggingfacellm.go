@@ -39,6 +39,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx = llms.ContextWithRequestOptions(ctx, *opts)
 
 	// Assume we get a single text message
 	msg0 := messages[0]
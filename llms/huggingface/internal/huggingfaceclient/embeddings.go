@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type embeddingPayload struct {
@@ -33,16 +35,14 @@ func (c *Client) createEmbedding(ctx context.Context, model string, task string,
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	r, err := http.DefaultClient.Do(req)
+	r, err := llms.DoRequest(http.DefaultClient, req)
 	if err != nil {
 		return nil, err
 	}
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
-		return nil, fmt.Errorf("%s: %s", msg, "unable to create embeddings") // nolint:goerr113
+		return nil, llms.NewLLMError(r.StatusCode, "", "unable to create embeddings", nil)
 	}
 
 	var response [][]float32
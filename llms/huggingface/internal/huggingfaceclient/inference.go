@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 var ErrUnexpectedStatusCode = errors.New("unexpected status code")
@@ -64,7 +66,7 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 	// }
 	// fmt.Fprintf(os.Stderr, "%s", reqDump)
 
-	r, err := http.DefaultClient.Do(req)
+	r, err := llms.DoRequest(http.DefaultClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -76,12 +78,17 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
 
+		msg := fmt.Sprintf("%s: %d", ErrUnexpectedStatusCode, r.StatusCode)
 		if len(b) > 0 {
-			err = fmt.Errorf("%w: %d, body: %s", ErrUnexpectedStatusCode, r.StatusCode, string(b))
-		} else {
-			err = fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, r.StatusCode)
+			msg += ", body: " + string(b)
+		}
+		return nil, &llms.LLMError{
+			Message:     msg,
+			StatusCode:  r.StatusCode,
+			RawResponse: b,
+			Code:        llms.ClassifyError(r.StatusCode, "", string(b)),
+			Err:         ErrUnexpectedStatusCode,
 		}
-		return nil, err
 	}
 
 	// debug print the http response with httputil:
@@ -14,6 +14,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const maxBufferSize = 512 * 1000
@@ -114,7 +116,7 @@ func (c *Client) do(ctx context.Context, method, path string, reqData, respData
 	request.Header.Set("User-Agent",
 		fmt.Sprintf("langchaingo/ (%s %s) Go/%s", runtime.GOARCH, runtime.GOOS, runtime.Version()))
 
-	respObj, err := c.httpClient.Do(request)
+	respObj, err := llms.DoRequest(c.httpClient, request)
 	if err != nil {
 		return err
 	}
@@ -174,7 +176,7 @@ func (c *Client) sendHTTPRequest(ctx context.Context, method, path string, buf *
 	}
 	setRequestHeaders(request)
 
-	return c.httpClient.Do(request)
+	return llms.DoRequest(c.httpClient, request)
 }
 
 // setRequestHeaders sets the necessary headers for the HTTP request.
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -27,6 +29,14 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Unwrap returns the error as a classified llms.LLMError, so that errors.Is
+// matches sentinels like llms.ErrRateLimited.
+func (e StatusError) Unwrap() error {
+	llmErr := llms.NewLLMError(e.StatusCode, e.Status, e.ErrorMessage, nil)
+	llmErr.Message = e.Error()
+	return llmErr
+}
+
 type GenerateRequest struct {
 	Prompt   string `json:"prompt"`
 	System   string `json:"system"`
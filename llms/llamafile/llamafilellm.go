@@ -54,6 +54,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(&opts)
 	}
+	opts.StreamingFunc = llms.TextStreamingFunc(opts)
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
 
 	// Our input is a sequence of MessageContent, each of which potentially has
 	// a sequence of Part that could be text, images etc.
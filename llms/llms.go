@@ -42,6 +42,7 @@ func GenerateFromSinglePrompt(ctx context.Context, llm Model, prompt string, opt
 	if err != nil {
 		return "", err
 	}
+	RecordUsage(ctx, resp)
 
 	choices := resp.Choices
 	if len(choices) < 1 {
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const defaultURL = "https://chat.maritaca.ai/api"
@@ -55,7 +57,7 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 	request.Header.Set("Accept", "application/json")
 	request.Header.Set("Authorization", token)
 
-	response, err := c.httpClient.Do(request)
+	response, err := llms.DoRequest(c.httpClient, request)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,8 @@ package maritacaclient
 
 import (
 	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -24,6 +26,14 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Unwrap returns the error as a classified llms.LLMError, so that errors.Is
+// matches sentinels like llms.ErrRateLimited.
+func (e StatusError) Unwrap() error {
+	llmErr := llms.NewLLMError(e.StatusCode, e.Status, e.ErrorMessage, nil)
+	llmErr.Message = e.Error()
+	return llmErr
+}
+
 type Message struct {
 	Role    string `json:"role"` // one of ["system", "user", "assistant"]
 	Content string `json:"content"`
@@ -59,6 +59,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(&opts)
 	}
+	opts.StreamingFunc = llms.TextStreamingFunc(opts)
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
 
 	// Override LLM model if set as llms.CallOption
 	model := o.options.model
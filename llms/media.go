@@ -0,0 +1,307 @@
+package llms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register the GIF decoder.
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	_defaultMaxMediaBytes      = 20 << 20
+	_defaultMaxImageDimension  = 2048
+	_defaultMaxImagePixels     = 50_000_000
+	_maxURLMediaBytes          = 100 << 20
+	_downscaledJPEGQuality     = 85
+	_maxDownscaleIterations    = 8
+	_downscaleIterationPercent = 75
+)
+
+// ErrMediaTooLarge is returned when media is larger than the size limit and
+// can't be downscaled under it.
+var ErrMediaTooLarge = errors.New("media too large")
+
+// MediaOption is a function that configures MediaOptions.
+type MediaOption func(*MediaOptions)
+
+// MediaOptions configures how content parts are built from media.
+type MediaOptions struct {
+	// MIMEType overrides the detected MIME type.
+	MIMEType string
+	// MaxBytes is the maximum size of the media. Larger images are
+	// downscaled, other media are rejected with ErrMediaTooLarge.
+	MaxBytes int
+	// MaxImageDimension is the maximum width and height of images. Larger
+	// images are downscaled. Zero disables downscaling by dimension.
+	MaxImageDimension int
+	// MaxImagePixels is the maximum number of pixels of images that are
+	// decoded for downscaling. Larger images are rejected with
+	// ErrMediaTooLarge before they are decoded.
+	MaxImagePixels int
+	// HTTPClient is the client used to fetch media from URLs.
+	HTTPClient HTTPDoer
+}
+
+// WithMediaMIMEType sets the MIME type of the media instead of detecting it.
+func WithMediaMIMEType(mimeType string) MediaOption {
+	return func(o *MediaOptions) {
+		o.MIMEType = mimeType
+	}
+}
+
+// WithMediaMaxBytes sets the maximum size of the media. Defaults to 20MiB.
+func WithMediaMaxBytes(maxBytes int) MediaOption {
+	return func(o *MediaOptions) {
+		o.MaxBytes = maxBytes
+	}
+}
+
+// WithMaxImageDimension sets the maximum width and height of images. Defaults
+// to 2048 pixels.
+func WithMaxImageDimension(pixels int) MediaOption {
+	return func(o *MediaOptions) {
+		o.MaxImageDimension = pixels
+	}
+}
+
+// WithMaxImagePixels sets the maximum number of pixels of images that are
+// decoded for downscaling. Defaults to 50 megapixels.
+func WithMaxImagePixels(pixels int) MediaOption {
+	return func(o *MediaOptions) {
+		o.MaxImagePixels = pixels
+	}
+}
+
+// WithMediaHTTPClient sets the client used by URLPart to fetch media.
+// Defaults to http.DefaultClient.
+func WithMediaHTTPClient(client HTTPDoer) MediaOption {
+	return func(o *MediaOptions) {
+		o.HTTPClient = client
+	}
+}
+
+func newMediaOptions(opts []MediaOption) MediaOptions {
+	o := MediaOptions{
+		MaxBytes:          _defaultMaxMediaBytes,
+		MaxImageDimension: _defaultMaxImageDimension,
+		MaxImagePixels:    _defaultMaxImagePixels,
+		HTTPClient:        http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FilePart builds a content part from the file at path. The MIME type is
+// guessed from the file extension and content: images become BinaryContent,
+// audio AudioContent, video VideoContent and text files TextContent.
+func FilePart(path string, opts ...MediaOption) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	o := newMediaOptions(opts)
+	if o.MIMEType == "" {
+		o.MIMEType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	return readerPart(f, o, readLimit(o))
+}
+
+// ReaderPart builds a content part from the data of r, see FilePart.
+func ReaderPart(r io.Reader, opts ...MediaOption) (ContentPart, error) {
+	o := newMediaOptions(opts)
+	return readerPart(r, o, readLimit(o))
+}
+
+// URLPart builds a content part from the media at url, which is downloaded
+// so that it can be sent to any provider. See FilePart. Downloads are capped
+// even if MaxBytes is disabled.
+func URLPart(ctx context.Context, url string, opts ...MediaOption) (ContentPart, error) {
+	o := newMediaOptions(opts)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+	limit := readLimit(o)
+	if limit <= 0 || limit > _maxURLMediaBytes {
+		limit = _maxURLMediaBytes
+	}
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrMediaTooLarge, resp.ContentLength, limit)
+	}
+	if o.MIMEType == "" {
+		o.MIMEType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	}
+	return readerPart(resp.Body, o, limit)
+}
+
+// readLimit returns the number of bytes read from media, or -1 if unlimited.
+// Images over the size limit may still be downscaled, so a bounded amount
+// more than the limit is read before rejecting them.
+func readLimit(o MediaOptions) int64 {
+	if o.MaxBytes <= 0 {
+		return -1
+	}
+	return int64(o.MaxBytes) * 4
+}
+
+func readerPart(r io.Reader, o MediaOptions, limit int64) (ContentPart, error) {
+	var data []byte
+	var err error
+	if limit > 0 {
+		data, err = io.ReadAll(io.LimitReader(r, limit+1))
+		if err == nil && int64(len(data)) > limit {
+			return nil, fmt.Errorf("%w: more than %d bytes", ErrMediaTooLarge, limit)
+		}
+	} else {
+		data, err = io.ReadAll(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := o.MIMEType
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	return mediaPart(mimeType, data, o)
+}
+
+func mediaPart(mimeType string, data []byte, o MediaOptions) (ContentPart, error) {
+	if strings.HasPrefix(mimeType, "image/") {
+		var err error
+		mimeType, data, err = fitImage(mimeType, data, o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.MaxBytes > 0 && len(data) > o.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrMediaTooLarge, len(data), o.MaxBytes)
+	}
+
+	switch {
+	case strings.HasPrefix(mimeType, "audio/"):
+		return AudioPart(mimeType, data), nil
+	case strings.HasPrefix(mimeType, "video/"):
+		return VideoPart(mimeType, data), nil
+	case strings.HasPrefix(mimeType, "text/"):
+		return TextPart(string(data)), nil
+	default:
+		return BinaryPart(mimeType, data), nil
+	}
+}
+
+// fitImage downscales an image until it fits the dimension and size limits.
+// Images in formats that can't be decoded are returned unchanged, images with
+// more than MaxImagePixels pixels are rejected without being decoded.
+func fitImage(mimeType string, data []byte, o MediaOptions) (string, []byte, error) {
+	tooBig := o.MaxBytes > 0 && len(data) > o.MaxBytes
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return mimeType, data, nil //nolint:nilerr
+	}
+	tooWide := o.MaxImageDimension > 0 && max(cfg.Width, cfg.Height) > o.MaxImageDimension
+	if !tooBig && !tooWide {
+		return mimeType, data, nil
+	}
+	if o.MaxImagePixels > 0 && int64(cfg.Width)*int64(cfg.Height) > int64(o.MaxImagePixels) {
+		return "", nil, fmt.Errorf("%w: image is %dx%d pixels, limit is %d pixels",
+			ErrMediaTooLarge, cfg.Width, cfg.Height, o.MaxImagePixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("decode image: %w", err)
+	}
+	width, height := cfg.Width, cfg.Height
+	if tooWide {
+		width, height = fitDimensions(width, height, o.MaxImageDimension)
+	}
+
+	// PNG is kept for images with transparency, other images become JPEG.
+	encodeAsPNG := mimeType == "image/png" && !isOpaque(img)
+	for i := 0; i < _maxDownscaleIterations; i++ {
+		var buf bytes.Buffer
+		scaled := downscale(img, width, height)
+		if encodeAsPNG {
+			err = png.Encode(&buf, scaled)
+		} else {
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: _downscaledJPEGQuality})
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("encode image: %w", err)
+		}
+		if o.MaxBytes <= 0 || buf.Len() <= o.MaxBytes {
+			if encodeAsPNG {
+				return "image/png", buf.Bytes(), nil
+			}
+			return "image/jpeg", buf.Bytes(), nil
+		}
+		width = max(1, width*_downscaleIterationPercent/100)
+		height = max(1, height*_downscaleIterationPercent/100)
+	}
+	return "", nil, fmt.Errorf("%w: image can't be downscaled under %d bytes", ErrMediaTooLarge, o.MaxBytes)
+}
+
+func fitDimensions(width, height, maxDimension int) (int, int) {
+	if width >= height {
+		return maxDimension, max(1, height*maxDimension/width)
+	}
+	return max(1, width*maxDimension/height), maxDimension
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// downscale resizes img to width x height by averaging the source pixels
+// covered by each destination pixel.
+func downscale(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(y0+1, src.Min.Y+(y+1)*src.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(x0+1, src.Min.X+(x+1)*src.Dx()/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,76 @@
+package llms
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePart(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for x := 0; x < 400; x++ {
+		for y := 0; y < 100; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	imagePath := filepath.Join(dir, "image.png")
+	require.NoError(t, os.WriteFile(imagePath, buf.Bytes(), 0o600))
+
+	part, err := FilePart(imagePath, WithMaxImageDimension(200))
+	require.NoError(t, err)
+	binary, ok := part.(BinaryContent)
+	require.True(t, ok)
+	require.Equal(t, "image/jpeg", binary.MIMEType)
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(binary.Data))
+	require.NoError(t, err)
+	require.Equal(t, 200, cfg.Width)
+	require.Equal(t, 50, cfg.Height)
+
+	part, err = FilePart(imagePath)
+	require.NoError(t, err)
+	require.Equal(t, BinaryPart("image/png", buf.Bytes()), part)
+
+	textPath := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(textPath, []byte("hello"), 0o600))
+	part, err = FilePart(textPath)
+	require.NoError(t, err)
+	require.Equal(t, TextPart("hello"), part)
+
+	audioPath := filepath.Join(dir, "speech.wav")
+	require.NoError(t, os.WriteFile(audioPath, make([]byte, 64), 0o600))
+	_, err = FilePart(audioPath, WithMediaMaxBytes(32))
+	require.ErrorIs(t, err, ErrMediaTooLarge)
+
+	_, err = FilePart(imagePath, WithMaxImageDimension(200), WithMaxImagePixels(10000))
+	require.ErrorIs(t, err, ErrMediaTooLarge)
+}
+
+func TestURLPart(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("mp3 data"))
+	}))
+	defer server.Close()
+
+	part, err := URLPart(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, AudioPart("audio/mpeg", []byte("mp3 data")), part)
+
+	_, err = URLPart(context.Background(), server.URL, WithMediaMaxBytes(1))
+	require.ErrorIs(t, err, ErrMediaTooLarge)
+}
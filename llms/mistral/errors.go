@@ -0,0 +1,43 @@
+package mistral
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// _httpErrorPattern matches the errors the Mistral SDK returns for responses
+// with an error status code.
+var _httpErrorPattern = regexp.MustCompile(`(?s)^\(HTTP Error (\d+)\) (.*)$`)
+
+// wrapError classifies an error response of the Mistral API as an
+// llms.LLMError. Other errors are returned unchanged.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	match := _httpErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	statusCode, _ := strconv.Atoi(match[1])
+	body := match[2]
+
+	message := body
+	var errResp struct {
+		Message any    `json:"message"`
+		Type    string `json:"type"`
+	}
+	if json.Unmarshal([]byte(body), &errResp) == nil {
+		if msg, ok := errResp.Message.(string); ok {
+			message = msg
+		}
+	}
+
+	llmErr := llms.NewLLMError(statusCode, errResp.Type, message, []byte(body))
+	llmErr.Message = err.Error()
+	llmErr.Err = err
+	return llmErr
+}
@@ -3,6 +3,7 @@ package mistral
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	sdk "github.com/gage-technologies/mistral-go"
@@ -45,6 +46,9 @@ func New(opts ...Option) (*Model, error) {
 func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
 	callOptions := resolveDefaultOptions(sdk.DefaultChatRequestParams, m.clientOptions)
 	setCallOptions(options, callOptions)
+	if err := checkHTTPOverrides(callOptions); err != nil {
+		return "", err
+	}
 	mistralChatParams := mistralChatParamsFromCallOptions(callOptions)
 
 	messages := make([]sdk.ChatMessage, 0)
@@ -52,7 +56,12 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 		Role:    "user",
 		Content: prompt,
 	})
-	res, err := m.client.Chat("", messages, &mistralChatParams)
+	var res *sdk.ChatCompletionResponse
+	err := llms.RetryCall(llms.ContextWithRetryPolicy(ctx, callOptions.RetryPolicy), func(context.Context) error {
+		var err error
+		res, err = m.client.Chat("", messages, &mistralChatParams)
+		return wrapError(err)
+	})
 	if err != nil {
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return "", err
@@ -69,6 +78,9 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
 	callOptions := resolveDefaultOptions(sdk.DefaultChatRequestParams, m.clientOptions)
 	setCallOptions(options, callOptions)
+	if err := checkHTTPOverrides(callOptions); err != nil {
+		return nil, err
+	}
 	m.CallbacksHandler.HandleLLMGenerateContentStart(ctx, langchainMessages)
 
 	chatOpts := mistralChatParamsFromCallOptions(callOptions)
@@ -88,6 +100,21 @@ func setCallOptions(options []llms.CallOption, callOpts *llms.CallOptions) {
 	for _, opt := range options {
 		opt(callOpts)
 	}
+	callOpts.StreamingFunc = llms.TextStreamingFunc(*callOpts)
+}
+
+// checkHTTPOverrides returns an error if extra HTTP headers or query
+// parameters or raw parameters are set, the mistral SDK doesn't expose its
+// HTTP client nor the request payload.
+func checkHTTPOverrides(callOpts *llms.CallOptions) error {
+	if len(callOpts.HTTPHeaders) > 0 || len(callOpts.QueryParams) > 0 {
+		return fmt.Errorf("%w: the mistral SDK doesn't support extra HTTP headers or query parameters",
+			llms.ErrHTTPOverrideUnsupported)
+	}
+	if len(callOpts.RawParams) > 0 {
+		return fmt.Errorf("%w: the mistral SDK doesn't expose the request payload", llms.ErrRawParamsUnsupported)
+	}
+	return nil
 }
 
 func resolveDefaultOptions(sdkDefaults sdk.ChatRequestParams, c *clientOptions) *llms.CallOptions {
@@ -131,7 +158,12 @@ func mistralChatParamsFromCallOptions(callOpts *llms.CallOptions) sdk.ChatReques
 }
 
 func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
-	res, err := m.client.Chat(callOptions.Model, messages, &chatOpts)
+	var res *sdk.ChatCompletionResponse
+	err := llms.RetryCall(llms.ContextWithRetryPolicy(ctx, callOptions.RetryPolicy), func(context.Context) error {
+		var err error
+		res, err = m.client.Chat(callOptions.Model, messages, &chatOpts)
+		return wrapError(err)
+	})
 	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, nil)
 	if err != nil {
 		m.CallbacksHandler.HandleLLMError(ctx, err)
@@ -167,7 +199,14 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 }
 
 func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
-	chatResChan, err := m.client.ChatStream(callOptions.Model, messages, &chatOpts)
+	// the stream is retried until it starts, the SDK returning the errors of its
+	// response status before streaming.
+	var chatResChan <-chan sdk.ChatCompletionStreamResponse
+	err := llms.RetryCall(llms.ContextWithRetryPolicy(ctx, callOptions.RetryPolicy), func(context.Context) error {
+		var err error
+		chatResChan, err = m.client.ChatStream(callOptions.Model, messages, &chatOpts)
+		return wrapError(err)
+	})
 	if err != nil {
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
@@ -199,7 +238,7 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 				return langchainContentResponse, err
 			}
 		} else {
-			return langchainContentResponse, chatResChunk.Error
+			return langchainContentResponse, wrapError(chatResChunk.Error)
 		}
 	}
 
@@ -0,0 +1,47 @@
+package mistral
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestHTTPOverridesUnsupported(t *testing.T) {
+	t.Parallel()
+	model, err := New(WithAPIKey("test"))
+	require.NoError(t, err)
+
+	_, err = model.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Hello")},
+		llms.WithHTTPHeader("X-Tenant-Id", "tenant-1"))
+	require.ErrorIs(t, err, llms.ErrHTTPOverrideUnsupported)
+
+	_, err = model.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Hello")},
+		llms.WithRawParams(map[string]any{"safe_prompt": true}))
+	require.ErrorIs(t, err, llms.ErrRawParamsUnsupported)
+}
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Unauthorized","request_id":"abc"}`))
+	}))
+	defer server.Close()
+
+	model, err := New(WithAPIKey("test"), WithEndpoint(server.URL), WithMaxRetries(1))
+	require.NoError(t, err)
+
+	_, err = model.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Hello")})
+	require.ErrorIs(t, err, llms.ErrAuthFailed)
+	llmErr, ok := llms.AsLLMError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusUnauthorized, llmErr.StatusCode)
+	require.Equal(t, "Unauthorized", llmErr.ErrorMessage)
+}
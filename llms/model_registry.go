@@ -0,0 +1,202 @@
+package llms
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Modality is a kind of model input.
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+	ModalityVideo Modality = "video"
+)
+
+// ModelInfo describes the capabilities of a model.
+type ModelInfo struct {
+	// ID is the model ID, e.g. "gpt-4o". Versioned IDs such as
+	// "gpt-4o-2024-08-06" resolve to the longest registered prefix.
+	ID string
+	// ContextWindow is the maximum number of input and output tokens.
+	ContextWindow int
+	// MaxOutputTokens is the maximum number of generated tokens.
+	MaxOutputTokens int
+	// InputModalities are the kinds of input the model accepts.
+	InputModalities []Modality
+	// SupportsTools is true if the model supports tool calling.
+	SupportsTools bool
+	// SupportsReasoning is true if the model produces reasoning content.
+	SupportsReasoning bool
+}
+
+// SupportsModality reports whether the model accepts the given input
+// modality.
+func (m ModelInfo) SupportsModality(modality Modality) bool {
+	return slices.Contains(m.InputModalities, modality)
+}
+
+// ModelRegistry maps model IDs to their capabilities. It is safe for
+// concurrent use.
+type ModelRegistry struct {
+	mu     sync.RWMutex
+	models map[string]ModelInfo
+}
+
+// NewModelRegistry creates a registry with the given models.
+func NewModelRegistry(models ...ModelInfo) *ModelRegistry {
+	r := &ModelRegistry{models: make(map[string]ModelInfo, len(models))}
+	for _, info := range models {
+		r.Register(info)
+	}
+	return r
+}
+
+// Register adds a model to the registry, replacing any model with the same
+// ID.
+func (r *ModelRegistry) Register(info ModelInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models[info.ID] = info
+}
+
+// Lookup returns the model with the given ID. Provider prefixes such as
+// "openai/" or "models/" are ignored, and IDs with a version or tag suffix
+// (e.g. "claude-3-5-sonnet-20241022" or "llama3.1:70b") match the longest
+// registered ID they extend.
+func (r *ModelRegistry) Lookup(id string) (ModelInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if info, ok := r.models[id]; ok {
+		return info, true
+	}
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+		if info, ok := r.models[id]; ok {
+			return info, true
+		}
+	}
+
+	var best ModelInfo
+	for registered, info := range r.models {
+		if len(registered) <= len(best.ID) || len(id) <= len(registered) || !strings.HasPrefix(id, registered) {
+			continue
+		}
+		if strings.ContainsRune("-:@", rune(id[len(registered)])) {
+			best = info
+		}
+	}
+	return best, best.ID != ""
+}
+
+// Models returns the registered models, sorted by ID.
+func (r *ModelRegistry) Models() []ModelInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	models := make([]ModelInfo, 0, len(r.models))
+	for _, info := range r.models {
+		models = append(models, info)
+	}
+	slices.SortFunc(models, func(a, b ModelInfo) int { return strings.Compare(a.ID, b.ID) })
+	return models
+}
+
+// RegisterModel adds or overrides a model in the default registry, e.g. for
+// fine-tuned or self-hosted models.
+func RegisterModel(info ModelInfo) {
+	defaultModelRegistry.Register(info)
+}
+
+// LookupModel returns the model with the given ID from the default registry.
+func LookupModel(id string) (ModelInfo, bool) {
+	return defaultModelRegistry.Lookup(id)
+}
+
+// DefaultModelRegistry returns the registry of the built-in models used by
+// LookupModel and RegisterModel.
+func DefaultModelRegistry() *ModelRegistry {
+	return defaultModelRegistry
+}
+
+// nolint:gochecknoglobals
+var (
+	_textOnly   = []Modality{ModalityText}
+	_textImage  = []Modality{ModalityText, ModalityImage}
+	_multimodal = []Modality{ModalityText, ModalityImage, ModalityAudio, ModalityVideo}
+)
+
+// nolint:gochecknoglobals,mnd
+var defaultModelRegistry = NewModelRegistry(
+	// OpenAI
+	ModelInfo{ID: "gpt-3.5-turbo", ContextWindow: 16385, MaxOutputTokens: 4096, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "gpt-4", ContextWindow: _gpt4ContextSize, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "gpt-4-32k", ContextWindow: _gpt432KContextSize, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "gpt-4-turbo", ContextWindow: 128000, MaxOutputTokens: 4096, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "gpt-4o", ContextWindow: 128000, MaxOutputTokens: 16384, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "gpt-4o-mini", ContextWindow: 128000, MaxOutputTokens: 16384, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "gpt-4o-audio-preview", ContextWindow: 128000, MaxOutputTokens: 16384, InputModalities: []Modality{ModalityText, ModalityAudio}, SupportsTools: true},
+	ModelInfo{ID: "gpt-4.1", ContextWindow: 1047576, MaxOutputTokens: 32768, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "gpt-4.1-mini", ContextWindow: 1047576, MaxOutputTokens: 32768, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "o1", ContextWindow: 200000, MaxOutputTokens: 100000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "o1-mini", ContextWindow: 128000, MaxOutputTokens: 65536, InputModalities: _textOnly, SupportsReasoning: true},
+	ModelInfo{ID: "o3-mini", ContextWindow: 200000, MaxOutputTokens: 100000, InputModalities: _textOnly, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "o3", ContextWindow: 200000, MaxOutputTokens: 100000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "o4-mini", ContextWindow: 200000, MaxOutputTokens: 100000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "text-davinci-003", ContextWindow: _textDavinci3ContextSize, MaxOutputTokens: _textDavinci3ContextSize, InputModalities: _textOnly},
+	ModelInfo{ID: "text-curie-001", ContextWindow: _textCurie1ContextSize, MaxOutputTokens: _textCurie1ContextSize, InputModalities: _textOnly},
+	ModelInfo{ID: "text-babbage-001", ContextWindow: _textBabbage1ContextSize, MaxOutputTokens: _textBabbage1ContextSize, InputModalities: _textOnly},
+	ModelInfo{ID: "text-ada-001", ContextWindow: _textAda1ContextSize, MaxOutputTokens: _textAda1ContextSize, InputModalities: _textOnly},
+	ModelInfo{ID: "code-davinci-002", ContextWindow: _codeDavinci2ContextSize, MaxOutputTokens: _codeDavinci2ContextSize, InputModalities: _textOnly},
+	ModelInfo{ID: "code-cushman-001", ContextWindow: _codeCushman1ContextSize, MaxOutputTokens: _codeCushman1ContextSize, InputModalities: _textOnly},
+
+	// Anthropic
+	ModelInfo{ID: "claude-2", ContextWindow: 100000, MaxOutputTokens: 4096, InputModalities: _textOnly},
+	ModelInfo{ID: "claude-2.0", ContextWindow: 100000, MaxOutputTokens: 4096, InputModalities: _textOnly},
+	ModelInfo{ID: "claude-2.1", ContextWindow: 200000, MaxOutputTokens: 4096, InputModalities: _textOnly},
+	ModelInfo{ID: "claude-instant", ContextWindow: 100000, MaxOutputTokens: 4096, InputModalities: _textOnly},
+	ModelInfo{ID: "claude-3-haiku", ContextWindow: 200000, MaxOutputTokens: 4096, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "claude-3-sonnet", ContextWindow: 200000, MaxOutputTokens: 4096, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "claude-3-opus", ContextWindow: 200000, MaxOutputTokens: 4096, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "claude-3-5-haiku", ContextWindow: 200000, MaxOutputTokens: 8192, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "claude-3-5-sonnet", ContextWindow: 200000, MaxOutputTokens: 8192, InputModalities: _textImage, SupportsTools: true},
+	ModelInfo{ID: "claude-3-7-sonnet", ContextWindow: 200000, MaxOutputTokens: 64000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "claude-sonnet-4", ContextWindow: 200000, MaxOutputTokens: 64000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "claude-opus-4", ContextWindow: 200000, MaxOutputTokens: 32000, InputModalities: _textImage, SupportsTools: true, SupportsReasoning: true},
+
+	// Google
+	ModelInfo{ID: "text-bison", ContextWindow: _textBisonContextSize, MaxOutputTokens: 1024, InputModalities: _textOnly},
+	ModelInfo{ID: "chat-bison", ContextWindow: _chatBisonContextSize, MaxOutputTokens: 1024, InputModalities: _textOnly},
+	ModelInfo{ID: "gemini-pro", ContextWindow: 32760, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "gemini-1.5-pro", ContextWindow: 2097152, MaxOutputTokens: 8192, InputModalities: _multimodal, SupportsTools: true},
+	ModelInfo{ID: "gemini-1.5-flash", ContextWindow: 1048576, MaxOutputTokens: 8192, InputModalities: _multimodal, SupportsTools: true},
+	ModelInfo{ID: "gemini-2.0-flash", ContextWindow: 1048576, MaxOutputTokens: 8192, InputModalities: _multimodal, SupportsTools: true},
+	ModelInfo{ID: "gemini-2.5-pro", ContextWindow: 1048576, MaxOutputTokens: 65536, InputModalities: _multimodal, SupportsTools: true, SupportsReasoning: true},
+	ModelInfo{ID: "gemini-2.5-flash", ContextWindow: 1048576, MaxOutputTokens: 65536, InputModalities: _multimodal, SupportsTools: true, SupportsReasoning: true},
+
+	// Mistral
+	ModelInfo{ID: "mistral-small", ContextWindow: 32000, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "mistral-large", ContextWindow: 128000, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "open-mistral-nemo", ContextWindow: 128000, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+
+	// Cohere
+	ModelInfo{ID: "command-r", ContextWindow: 128000, MaxOutputTokens: 4096, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "command-r-plus", ContextWindow: 128000, MaxOutputTokens: 4096, InputModalities: _textOnly, SupportsTools: true},
+
+	// DeepSeek
+	ModelInfo{ID: "deepseek-chat", ContextWindow: 64000, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "deepseek-reasoner", ContextWindow: 64000, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsReasoning: true},
+	ModelInfo{ID: "deepseek-r1", ContextWindow: 128000, MaxOutputTokens: 32768, InputModalities: _textOnly, SupportsReasoning: true},
+
+	// Open models, as named by Ollama
+	ModelInfo{ID: "llama2", ContextWindow: 4096, MaxOutputTokens: 4096, InputModalities: _textOnly},
+	ModelInfo{ID: "llama3", ContextWindow: 8192, MaxOutputTokens: 8192, InputModalities: _textOnly},
+	ModelInfo{ID: "llama3.1", ContextWindow: 131072, MaxOutputTokens: 131072, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "llama3.2", ContextWindow: 131072, MaxOutputTokens: 131072, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "mistral", ContextWindow: 32768, MaxOutputTokens: 32768, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "qwen2.5", ContextWindow: 32768, MaxOutputTokens: 8192, InputModalities: _textOnly, SupportsTools: true},
+	ModelInfo{ID: "gemma2", ContextWindow: 8192, MaxOutputTokens: 8192, InputModalities: _textOnly},
+)
@@ -0,0 +1,51 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelRegistryLookup(t *testing.T) {
+	t.Parallel()
+	r := NewModelRegistry(
+		ModelInfo{ID: "gpt-4o", ContextWindow: 128000},
+		ModelInfo{ID: "gpt-4o-mini", ContextWindow: 64000},
+		ModelInfo{ID: "llama3.1", ContextWindow: 131072},
+	)
+
+	cases := map[string]int{
+		"gpt-4o":                 128000,
+		"gpt-4o-2024-08-06":      128000,
+		"gpt-4o-mini-2024-07-18": 64000,
+		"openai/gpt-4o-mini":     64000,
+		"llama3.1:70b":           131072,
+	}
+	for id, contextWindow := range cases {
+		info, ok := r.Lookup(id)
+		assert.True(t, ok, id)
+		assert.Equal(t, contextWindow, info.ContextWindow, id)
+	}
+
+	_, ok := r.Lookup("gpt-4")
+	assert.False(t, ok)
+	_, ok = r.Lookup("gpt-4oo")
+	assert.False(t, ok)
+
+	r.Register(ModelInfo{ID: "gpt-4o", ContextWindow: 1})
+	info, _ := r.Lookup("gpt-4o-2024-08-06")
+	assert.Equal(t, 1, info.ContextWindow)
+}
+
+func TestDefaultModelRegistry(t *testing.T) {
+	t.Parallel()
+	info, ok := LookupModel("claude-3-5-sonnet-20241022")
+	assert.True(t, ok)
+	assert.Equal(t, 200000, info.ContextWindow)
+	assert.True(t, info.SupportsTools)
+	assert.True(t, info.SupportsModality(ModalityImage))
+	assert.False(t, info.SupportsModality(ModalityAudio))
+
+	assert.Equal(t, 8192, GetModelContextSize("gpt-4"))
+	assert.Equal(t, _defaultContextSize, GetModelContextSize("unknown-model"))
+}
@@ -13,6 +13,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type Client struct {
@@ -96,7 +98,7 @@ func (c *Client) do(ctx context.Context, method, path string, reqData, respData
 	request.Header.Set("User-Agent",
 		fmt.Sprintf("langchaingo/ (%s %s) Go/%s", runtime.GOARCH, runtime.GOOS, runtime.Version()))
 
-	respObj, err := c.httpClient.Do(request)
+	respObj, err := llms.DoRequest(c.httpClient, request)
 	if err != nil {
 		return err
 	}
@@ -143,7 +145,7 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 	request.Header.Set("User-Agent",
 		fmt.Sprintf("langchaingo (%s %s) Go/%s", runtime.GOARCH, runtime.GOOS, runtime.Version()))
 
-	response, err := c.httpClient.Do(request)
+	response, err := llms.DoRequest(c.httpClient, request)
 	if err != nil {
 		return err
 	}
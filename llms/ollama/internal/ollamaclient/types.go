@@ -1,9 +1,12 @@
 package ollamaclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -26,6 +29,14 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Unwrap returns the error as a classified llms.LLMError, so that errors.Is
+// matches sentinels like llms.ErrRateLimited.
+func (e StatusError) Unwrap() error {
+	llmErr := llms.NewLLMError(e.StatusCode, e.Status, e.ErrorMessage, nil)
+	llmErr.Message = e.Error()
+	return llmErr
+}
+
 type GenerateRequest struct {
 	Model     string `json:"model"`
 	Prompt    string `json:"prompt"`
@@ -54,6 +65,19 @@ type ChatRequest struct {
 	KeepAlive string     `json:"keep_alive,omitempty"`
 
 	Options Options `json:"options"`
+
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
+}
+
+// MarshalJSON marshals the request and merges its raw parameters.
+func (r ChatRequest) MarshalJSON() ([]byte, error) {
+	type request ChatRequest
+	data, err := json.Marshal(request(r))
+	if err != nil {
+		return nil, err
+	}
+	return llms.MergeRawParams(data, r.RawParams)
 }
 
 type Metrics struct {
@@ -95,7 +119,8 @@ type ChatResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	Message   *Message  `json:"message,omitempty"`
 
-	Done bool `json:"done"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
 
 	Metrics
 }
@@ -3,6 +3,8 @@ package ollama
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -129,3 +131,40 @@ func TestWithKeepAlive(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, vector)
 }
+
+func TestStreamingEvents(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		for _, chunk := range []string{
+			`{"model":"llama3","message":{"role":"assistant","content":"Hello"},"done":false}`,
+			`{"model":"llama3","message":{"role":"assistant","content":" there"},"done":false}`,
+			`{"model":"llama3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":5,"eval_count":2}`,
+		} {
+			_, _ = w.Write([]byte(chunk + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	llm, err := New(WithModel("llama3"), WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	var events []llms.StreamingEvent
+	rsp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Hi")},
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamingEvent) error {
+			events = append(events, event)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there", rsp.Choices[0].Content)
+	assert.Equal(t, []llms.StreamingEvent{
+		{Type: llms.StreamingEventTextDelta, Text: "Hello"},
+		{Type: llms.StreamingEventTextDelta, Text: " there"},
+		{Type: llms.StreamingEventUsageUpdate, Usage: &llms.Usage{
+			PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7,
+		}},
+		{Type: llms.StreamingEventDone, StopReason: "stop"},
+	}, events)
+}
@@ -54,6 +54,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(&opts)
 	}
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
 
 	// Override LLM model if set as llms.CallOption
 	model := o.options.model
@@ -104,11 +105,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	// Get our ollamaOptions from llms.CallOptions
 	ollamaOptions := makeOllamaOptionsFromOptions(o.options.ollamaOptions, opts)
 	req := &ollamaclient.ChatRequest{
-		Model:    model,
-		Format:   format,
-		Messages: chatMsgs,
-		Options:  ollamaOptions,
-		Stream:   func(b bool) *bool { return &b }(opts.StreamingFunc != nil),
+		Model:     model,
+		Format:    format,
+		Messages:  chatMsgs,
+		Options:   ollamaOptions,
+		Stream:    func(b bool) *bool { return &b }(opts.StreamingFunc != nil || opts.StreamingEventFunc != nil),
+		RawParams: opts.RawParams,
 	}
 
 	keepAlive := o.options.keepAlive
@@ -121,12 +123,10 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	var resp ollamaclient.ChatResponse
 
 	fn = func(response ollamaclient.ChatResponse) error {
-		if opts.StreamingFunc != nil && response.Message != nil {
-			if err := opts.StreamingFunc(ctx, []byte(response.Message.Content)); err != nil {
+		if response.Message != nil {
+			if err := streamText(ctx, opts, response.Message.Content); err != nil {
 				return err
 			}
-		}
-		if response.Message != nil {
 			streamedResponse += response.Message.Content
 		}
 		if response.Done {
@@ -135,6 +135,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Role:    "assistant",
 				Content: streamedResponse,
 			}
+			return streamDone(ctx, opts, response)
 		}
 		return nil
 	}
@@ -147,9 +148,14 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		return nil, err
 	}
 
+	content, reasoning := resp.Message.Content, ""
+	if o.options.splitReasoning {
+		reasoning, content = llms.SplitReasoning(content)
+	}
 	choices := []*llms.ContentChoice{
 		{
-			Content: resp.Message.Content,
+			Content:          content,
+			ReasoningContent: reasoning,
 			GenerationInfo: map[string]any{
 				"CompletionTokens": resp.EvalCount,
 				"PromptTokens":     resp.PromptEvalCount,
@@ -167,6 +173,44 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return response, nil
 }
 
+// streamText sends a streamed chunk of text to the streaming functions of opts.
+func streamText(ctx context.Context, opts llms.CallOptions, text string) error {
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte(text)); err != nil {
+			return err
+		}
+	}
+	if opts.StreamingEventFunc == nil || text == "" {
+		return nil
+	}
+	return opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+		Type: llms.StreamingEventTextDelta,
+		Text: text,
+	})
+}
+
+// streamDone sends the usage and done events of the final chunk of a
+// streamed response.
+func streamDone(ctx context.Context, opts llms.CallOptions, response ollamaclient.ChatResponse) error {
+	if opts.StreamingEventFunc == nil {
+		return nil
+	}
+	if err := opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+		Type: llms.StreamingEventUsageUpdate,
+		Usage: &llms.Usage{
+			PromptTokens:     response.PromptEvalCount,
+			CompletionTokens: response.EvalCount,
+			TotalTokens:      response.PromptEvalCount + response.EvalCount,
+		},
+	}); err != nil {
+		return err
+	}
+	return opts.StreamingEventFunc(ctx, llms.StreamingEvent{
+		Type:       llms.StreamingEventDone,
+		StopReason: response.DoneReason,
+	})
+}
+
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings := [][]float32{}
 
@@ -17,6 +17,7 @@ type options struct {
 	system              string
 	format              string
 	keepAlive           string
+	splitReasoning      bool
 }
 
 type Option func(*options)
@@ -76,6 +77,16 @@ func WithServerURL(rawURL string) Option {
 	}
 }
 
+// WithSplitReasoning moves the leading <think></think> block that reasoning
+// models, such as DeepSeek-R1, inline in their completion out of
+// ContentChoice.Content into ContentChoice.ReasoningContent. By default the
+// completion is returned unchanged in Content.
+func WithSplitReasoning() Option {
+	return func(opts *options) {
+		opts.splitReasoning = true
+	}
+}
+
 // WithHTTPClient Set custom http client.
 func WithHTTPClient(client *http.Client) Option {
 	return func(opts *options) {
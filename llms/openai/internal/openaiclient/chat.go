@@ -59,6 +59,10 @@ type ChatRequest struct {
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
 
+	// StreamingEventFunc is a function to be called for each typed event of a
+	// streaming response. Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event llms.StreamingEvent) error `json:"-"`
+
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
 	// Deprecated: use ToolChoice instead.
@@ -66,6 +70,9 @@ type ChatRequest struct {
 
 	// Metadata allows you to specify additional information that will be passed to the model.
 	Metadata map[string]any `json:"metadata,omitempty"`
+
+	// RawParams are merged into the request payload, overriding the fields above.
+	RawParams map[string]any `json:"-"`
 }
 
 // ToolType is the type of a tool.
@@ -137,6 +144,10 @@ type ChatMessage struct { //nolint:musttag
 	// ToolCallID is the ID of the tool call this message is for.
 	// Only present in tool messages.
 	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// ReasoningContent is the reasoning of the model, returned by reasoning
+	// models such as DeepSeek-R1. It is never sent in requests.
+	ReasoningContent string
 }
 
 func (m ChatMessage) MarshalJSON() ([]byte, error) {
@@ -161,6 +172,8 @@ func (m ChatMessage) MarshalJSON() ([]byte, error) {
 			// ToolCallID is the ID of the tool call this message is for.
 			// Only present in tool messages.
 			ToolCallID string `json:"tool_call_id,omitempty"`
+
+			ReasoningContent string `json:"-"`
 		}(m)
 		return json.Marshal(msg)
 	}
@@ -176,6 +189,8 @@ func (m ChatMessage) MarshalJSON() ([]byte, error) {
 		// ToolCallID is the ID of the tool call this message is for.
 		// Only present in tool messages.
 		ToolCallID string `json:"tool_call_id,omitempty"`
+
+		ReasoningContent string `json:"-"`
 	}(m)
 	return json.Marshal(msg)
 }
@@ -201,6 +216,8 @@ func (m *ChatMessage) UnmarshalJSON(data []byte) error {
 		// ToolCallID is the ID of the tool call this message is for.
 		// Only present in tool messages.
 		ToolCallID string `json:"tool_call_id,omitempty"`
+
+		ReasoningContent string `json:"reasoning_content,omitempty"`
 	}{}
 	err := json.Unmarshal(data, &msg)
 	if err != nil {
@@ -263,6 +280,13 @@ type ChatUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	CompletionTokensDetails CompletionTokensDetails `json:"completion_tokens_details"`
+}
+
+// CompletionTokensDetails is the breakdown of the completion tokens.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
 }
 
 // ChatCompletionResponse is a response to a chat request.
@@ -288,6 +312,8 @@ type StreamedChatResponsePayload struct {
 			Role         string        `json:"role,omitempty"`
 			Content      string        `json:"content,omitempty"`
 			FunctionCall *FunctionCall `json:"function_call,omitempty"`
+			// ReasoningContent is a chunk of the reasoning of the model.
+			ReasoningContent string `json:"reasoning_content,omitempty"`
 			// ToolCalls is a list of tools that were called in the message.
 			ToolCalls []*ToolCall `json:"tool_calls,omitempty"`
 		} `json:"delta,omitempty"`
@@ -327,7 +353,7 @@ type FunctionCall struct {
 }
 
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCompletionResponse, error) {
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		payload.Stream = true
 		payload.StreamOptions = &StreamOptions{
 			IncludeUsage: true,
@@ -339,6 +365,10 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	if err != nil {
 		return nil, err
 	}
+	payloadBytes, err = llms.MergeRawParams(payloadBytes, payload.RawParams)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build request
 	body := bytes.NewReader(payloadBytes)
@@ -353,7 +383,7 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	c.setHeaders(req)
 
 	// Send request
-	r, err := c.httpClient.Do(req)
+	r, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -370,7 +400,12 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 		// status code.
 		var errResp errorMessage
 		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, &llms.LLMError{
+				Message:     msg,
+				StatusCode:  r.StatusCode,
+				RawResponse: respBody,
+				Code:        llms.ClassifyError(r.StatusCode, "", ""),
+			}
 		}
 
 		return nil, &llms.LLMError{
@@ -379,9 +414,10 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 			StatusCode:   r.StatusCode,
 			ErrorType:    errResp.Error.Type,
 			RawResponse:  respBody,
+			Code:         llms.ClassifyError(r.StatusCode, errResp.Error.Type, errResp.Error.Message),
 		} // nolint:goerr113
 	}
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		return parseStreamingChatResponse(ctx, r, payload)
 	}
 	// Parse response
@@ -437,12 +473,35 @@ func combineStreamingChatResponse(ctx context.Context, payload *ChatRequest, res
 	for streamResponse := range responseChan {
 		if streamResponse.Usage != nil {
 			response.Usage = *streamResponse.Usage
+			if err := payload.sendEvent(ctx, llms.StreamingEvent{
+				Type: llms.StreamingEventUsageUpdate,
+				Usage: &llms.Usage{
+					PromptTokens:     streamResponse.Usage.PromptTokens,
+					CompletionTokens: streamResponse.Usage.CompletionTokens,
+					TotalTokens:      streamResponse.Usage.TotalTokens,
+				},
+			}); err != nil {
+				return nil, err
+			}
 		}
 		if len(streamResponse.Choices) == 0 {
 			continue
 		}
 
 		choice := streamResponse.Choices[0]
+		if choice.Delta.ReasoningContent != "" {
+			response.Choices[0].Message.ReasoningContent += choice.Delta.ReasoningContent
+			if err := payload.sendEvent(ctx, llms.StreamingEvent{
+				Type: llms.StreamingEventReasoningDelta,
+				Text: choice.Delta.ReasoningContent,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := sendDeltaEvents(ctx, payload, choice.Delta.Content, choice.Delta.ToolCalls,
+			len(response.Choices[0].Message.ToolCalls)); err != nil {
+			return nil, err
+		}
 		chunk := []byte(choice.Delta.Content)
 		response.Choices[0].Message.Content += choice.Delta.Content
 		response.Choices[0].FinishReason = choice.FinishReason
@@ -462,9 +521,70 @@ func combineStreamingChatResponse(ctx context.Context, payload *ChatRequest, res
 			}
 		}
 	}
+	if err := payload.sendEvent(ctx, llms.StreamingEvent{
+		Type:       llms.StreamingEventDone,
+		StopReason: string(response.Choices[0].FinishReason),
+	}); err != nil {
+		return nil, err
+	}
 	return &response, nil
 }
 
+func (r *ChatRequest) isStreaming() bool {
+	return r.StreamingFunc != nil || r.StreamingEventFunc != nil
+}
+
+func (r *ChatRequest) sendEvent(ctx context.Context, event llms.StreamingEvent) error {
+	if r.StreamingEventFunc == nil {
+		return nil
+	}
+	if err := r.StreamingEventFunc(ctx, event); err != nil {
+		return fmt.Errorf("streaming event func returned an error: %w", err)
+	}
+	return nil
+}
+
+// sendDeltaEvents sends the text and tool call events of a streamed delta.
+// toolCount is the number of tool calls received before this delta.
+func sendDeltaEvents(ctx context.Context, payload *ChatRequest, content string, toolCalls []*ToolCall, toolCount int) error {
+	if content != "" {
+		if err := payload.sendEvent(ctx, llms.StreamingEvent{
+			Type: llms.StreamingEventTextDelta,
+			Text: content,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, t := range toolCalls {
+		event := llms.StreamingEvent{Type: llms.StreamingEventToolCallDelta}
+		// mirror updateToolCalls: argument-only deltas belong to the last tool call.
+		if t.Type == `` && t.Function.Arguments != `` {
+			if toolCount == 0 {
+				continue
+			}
+			event.ToolCallIndex = toolCount - 1
+			event.ToolCall = &llms.ToolCall{
+				FunctionCall: &llms.FunctionCall{Arguments: t.Function.Arguments},
+			}
+		} else {
+			event.ToolCallIndex = toolCount
+			event.ToolCall = &llms.ToolCall{
+				ID:   t.ID,
+				Type: string(t.Type),
+				FunctionCall: &llms.FunctionCall{
+					Name:      t.Function.Name,
+					Arguments: t.Function.Arguments,
+				},
+			}
+			toolCount++
+		}
+		if err := payload.sendEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func updateFunctionCall(message ChatMessage, functionCall *FunctionCall) []byte {
 	if message.FunctionCall == nil {
 		message.FunctionCall = functionCall
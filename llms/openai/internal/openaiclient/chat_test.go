@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 )
 
 func TestParseStreamingChatResponse_FinishReason(t *testing.T) {
@@ -52,3 +53,76 @@ func TestChatMessage_MarshalUnmarshal(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, msg, msg2)
 }
+
+func TestParseStreamingChatResponse_Events(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","content":"hi"}}]}
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":""}}]}}]}
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":"{}"}}]}}]}
+data: {"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}
+data: {"choices":[],"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3}}
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var events []llms.StreamingEvent
+	req := &ChatRequest{
+		StreamingEventFunc: func(_ context.Context, event llms.StreamingEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+	require.Len(t, events, 5)
+
+	assert.Equal(t, llms.StreamingEventTextDelta, events[0].Type)
+	assert.Equal(t, "hi", events[0].Text)
+	assert.Equal(t, llms.StreamingEventToolCallDelta, events[1].Type)
+	assert.Equal(t, "lookup", events[1].ToolCall.FunctionCall.Name)
+	assert.Equal(t, llms.StreamingEventToolCallDelta, events[2].Type)
+	assert.Equal(t, "{}", events[2].ToolCall.FunctionCall.Arguments)
+	assert.Equal(t, 0, events[2].ToolCallIndex)
+	assert.Equal(t, llms.StreamingEventUsageUpdate, events[3].Type)
+	assert.Equal(t, 3, events[3].Usage.TotalTokens)
+	assert.Equal(t, llms.StreamingEventDone, events[4].Type)
+	assert.Equal(t, "tool_calls", events[4].StopReason)
+}
+
+func TestParseStreamingChatResponse_Reasoning(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"think"}}]}
+data: {"choices":[{"index":0,"delta":{"content":"answer"},"finish_reason":"stop"}]}
+data: {"choices":[],"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3,"completion_tokens_details":{"reasoning_tokens":1}}}
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var events []llms.StreamingEvent
+	req := &ChatRequest{
+		StreamingEventFunc: func(_ context.Context, event llms.StreamingEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	assert.Equal(t, "think", resp.Choices[0].Message.ReasoningContent)
+	assert.Equal(t, "answer", resp.Choices[0].Message.Content)
+	assert.Equal(t, 1, resp.Usage.CompletionTokensDetails.ReasoningTokens)
+	require.NotEmpty(t, events)
+	assert.Equal(t, llms.StreamingEventReasoningDelta, events[0].Type)
+	assert.Equal(t, "think", events[0].Text)
+
+	// reasoning content is never sent back to the API.
+	data, err := json.Marshal(resp.Choices[0].Message)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "think")
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -54,7 +55,7 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 
 	c.setHeaders(req)
 
-	r, err := c.httpClient.Do(req)
+	r, err := llms.DoRequest(c.httpClient, req)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -67,10 +68,14 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return nil, &llms.LLMError{
+				Message:    msg,
+				StatusCode: r.StatusCode,
+				Code:       llms.ClassifyError(r.StatusCode, "", ""),
+			}
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewLLMError(r.StatusCode, errResp.Error.Type, errResp.Error.Message, nil)
 	}
 
 	var response embeddingResponsePayload
@@ -146,11 +146,6 @@ func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletio
 	if len(resp.Choices) == 0 {
 		return nil, ErrEmptyResponse
 	}
-	resp.Usage = ChatUsage(llms.Usage{
-		TotalTokens:      resp.Usage.TotalTokens,
-		PromptTokens:     resp.Usage.PromptTokens,
-		CompletionTokens: resp.Usage.CompletionTokens,
-	})
 	return resp, nil
 }
 
@@ -24,7 +24,11 @@ const (
 	RoleTool      = "tool"
 )
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model        = (*LLM)(nil)
+	_ llms.TokenCounter = (*LLM)(nil)
+	_ llms.ContextSizer = (*LLM)(nil)
+)
 
 // New returns a new OpenAI LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -53,10 +57,15 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	for _, opt := range options {
 		opt(&opts)
 	}
+	ctx = llms.ContextWithRequestOptions(ctx, opts)
 
 	chatMsgs := make([]*ChatMessage, 0, len(messages))
 	for _, mc := range messages {
-		msg := &ChatMessage{MultiContent: mc.Parts}
+		parts, err := convertMediaParts(mc.Parts)
+		if err != nil {
+			return nil, err
+		}
+		msg := &ChatMessage{MultiContent: parts}
 		switch mc.Role {
 		case llms.ChatMessageTypeSystem:
 			msg.Role = RoleSystem
@@ -96,19 +105,21 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 	req := &openaiclient.ChatRequest{
-		Model:            opts.Model,
-		StopWords:        opts.StopWords,
-		Messages:         chatMsgs,
-		StreamingFunc:    opts.StreamingFunc,
-		Temperature:      opts.Temperature,
-		MaxTokens:        opts.MaxTokens,
-		N:                opts.N,
-		FrequencyPenalty: opts.FrequencyPenalty,
-		PresencePenalty:  opts.PresencePenalty,
+		Model:              opts.Model,
+		StopWords:          opts.StopWords,
+		Messages:           chatMsgs,
+		StreamingFunc:      opts.StreamingFunc,
+		StreamingEventFunc: opts.StreamingEventFunc,
+		Temperature:        opts.Temperature,
+		MaxTokens:          opts.MaxTokens,
+		N:                  opts.N,
+		FrequencyPenalty:   opts.FrequencyPenalty,
+		PresencePenalty:    opts.PresencePenalty,
 
 		FunctionCallBehavior: openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior),
 		Seed:                 opts.Seed,
 		Metadata:             opts.Metadata,
+		RawParams:            opts.RawParams,
 	}
 	if opts.JSONMode {
 		req.ResponseFormat = ResponseFormatJSON
@@ -146,12 +157,15 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	choices := make([]*llms.ContentChoice, len(result.Choices))
 	for i, c := range result.Choices {
 		choices[i] = &llms.ContentChoice{
-			Content:    c.Message.Content,
-			StopReason: fmt.Sprint(c.FinishReason),
+			Content:          c.Message.Content,
+			StopReason:       fmt.Sprint(c.FinishReason),
+			ReasoningContent: c.Message.ReasoningContent,
+			ReasoningTokens:  result.Usage.CompletionTokensDetails.ReasoningTokens,
 			GenerationInfo: map[string]any{
 				"CompletionTokens": result.Usage.CompletionTokens,
 				"PromptTokens":     result.Usage.PromptTokens,
 				"TotalTokens":      result.Usage.TotalTokens,
+				"ReasoningTokens":  result.Usage.CompletionTokensDetails.ReasoningTokens,
 			},
 		}
 
@@ -192,6 +206,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return response, nil
 }
 
+// CountTokens implements the [llms.TokenCounter] interface using tiktoken.
+func (o *LLM) CountTokens(ctx context.Context, messages []llms.MessageContent) (int, error) {
+	return llms.TiktokenCounter{Model: o.client.Model}.CountTokens(ctx, messages)
+}
+
+// MaxContextTokens implements the [llms.ContextSizer] interface.
+func (o *LLM) MaxContextTokens() int {
+	return llms.GetModelContextSize(o.client.Model)
+}
+
 // CreateEmbedding creates embeddings for the given input texts.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings, err := o.client.CreateEmbedding(ctx, &openaiclient.EmbeddingRequest{
@@ -222,6 +246,8 @@ func ExtractToolParts(msg *ChatMessage) ([]llms.ContentPart, []llms.ToolCall) {
 			content = append(content, p)
 		case llms.BinaryContent:
 			content = append(content, p)
+		case llms.AudioContent:
+			content = append(content, p)
 		case llms.ToolCall:
 			toolCalls = append(toolCalls, p)
 		}
@@ -229,6 +255,33 @@ func ExtractToolParts(msg *ChatMessage) ([]llms.ContentPart, []llms.ToolCall) {
 	return content, toolCalls
 }
 
+// convertMediaParts replaces audio and video parts that can't be sent to the
+// API with their transcripts. Audio given as data is supported natively.
+func convertMediaParts(parts []llms.ContentPart) ([]llms.ContentPart, error) {
+	converted := make([]llms.ContentPart, 0, len(parts))
+	for _, part := range parts {
+		transcript := ""
+		switch p := part.(type) {
+		case llms.AudioContent:
+			if p.URL == "" {
+				converted = append(converted, p)
+				continue
+			}
+			transcript = p.Transcript
+		case llms.VideoContent:
+			transcript = p.Transcript
+		default:
+			converted = append(converted, part)
+			continue
+		}
+		if transcript == "" {
+			return nil, llms.UnsupportedContentPartError(part)
+		}
+		converted = append(converted, llms.TextPart(transcript))
+	}
+	return converted, nil
+}
+
 // toolFromTool converts an llms.Tool to a Tool.
 func toolFromTool(t llms.Tool) (openaiclient.Tool, error) {
 	tool := openaiclient.Tool{
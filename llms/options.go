@@ -1,6 +1,10 @@
 package llms
 
-import "context"
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
 
 // CallOption is a function that configures a CallOptions.
 type CallOption func(*CallOptions)
@@ -21,6 +25,9 @@ type CallOptions struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingEventFunc is a function to be called for each typed event of a
+	// streaming response. Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event StreamingEvent) error `json:"-"`
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
@@ -61,6 +68,24 @@ type CallOptions struct {
 	// Metadata is a map of metadata to include in the request.
 	// The meaning of this field is specific to the backend in use.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// RawParams are provider-specific parameters merged into the outgoing
+	// request payload. See WithRawParams.
+	RawParams map[string]any `json:"-"`
+
+	// RetryPolicy configures how failed requests are retried. See
+	// WithRetryPolicy.
+	RetryPolicy *RetryPolicy `json:"-"`
+
+	// HTTPHeaders are extra headers set on the HTTP requests to the provider.
+	HTTPHeaders http.Header `json:"-"`
+	// QueryParams are extra query parameters set on the HTTP requests to the
+	// provider.
+	QueryParams url.Values `json:"-"`
+
+	// ThinkingBudget is the number of tokens reasoning models may spend on
+	// thinking. See WithThinkingBudget.
+	ThinkingBudget int `json:"thinking_budget,omitempty"`
 }
 
 // Tool is a tool that can be used by the model.
@@ -265,3 +290,19 @@ func WithMetadata(metadata map[string]interface{}) CallOption {
 		o.Metadata = metadata
 	}
 }
+
+// WithRawParams will add an option to merge raw parameters into the JSON
+// payload sent to the provider, overriding the typed options. This allows
+// using new provider parameters before typed options exist for them. Calling
+// it several times merges the parameters. Providers whose SDK doesn't expose
+// the payload return ErrRawParamsUnsupported.
+func WithRawParams(params map[string]any) CallOption {
+	return func(o *CallOptions) {
+		if o.RawParams == nil {
+			o.RawParams = make(map[string]any, len(params))
+		}
+		for k, v := range params {
+			o.RawParams[k] = v
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package llms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrRawParamsUnsupported is returned by providers whose SDK doesn't expose
+// the request payload, so that raw parameters can't be merged into it.
+var ErrRawParamsUnsupported = errors.New("raw params not supported")
+
+// MergeRawParams merges raw parameters into a JSON object payload. Nested
+// objects are merged recursively, other values replace the payload values and
+// nil values remove the key from the payload. It is used by providers to
+// implement WithRawParams.
+func MergeRawParams(payload []byte, params map[string]any) ([]byte, error) {
+	if len(params) == 0 {
+		return payload, nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal(payload, &object); err != nil {
+		return nil, fmt.Errorf("merge raw params: %w", err)
+	}
+	if object == nil {
+		object = make(map[string]any, len(params))
+	}
+	mergeObjects(object, params)
+	return json.Marshal(object)
+}
+
+func mergeObjects(dst, src map[string]any) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		srcObject, srcOK := v.(map[string]any)
+		dstObject, dstOK := dst[k].(map[string]any)
+		if srcOK && dstOK {
+			mergeObjects(dstObject, srcObject)
+			continue
+		}
+		dst[k] = v
+	}
+}
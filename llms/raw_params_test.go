@@ -0,0 +1,29 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRawParams(t *testing.T) {
+	t.Parallel()
+	payload := []byte(`{"model":"m","temperature":0.5,"options":{"num_ctx":2048,"top_k":40}}`)
+
+	merged, err := MergeRawParams(payload, map[string]any{
+		"temperature":      nil,
+		"reasoning_effort": "high",
+		"options":          map[string]any{"num_ctx": 8192},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"m","reasoning_effort":"high","options":{"num_ctx":8192,"top_k":40}}`, string(merged))
+
+	unchanged, err := MergeRawParams(payload, nil)
+	require.NoError(t, err)
+	require.Equal(t, payload, unchanged)
+
+	opts := CallOptions{}
+	WithRawParams(map[string]any{"a": 1})(&opts)
+	WithRawParams(map[string]any{"b": 2})(&opts)
+	require.Equal(t, map[string]any{"a": 1, "b": 2}, opts.RawParams)
+}
@@ -0,0 +1,44 @@
+package llms
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrThinkingUnsupported is returned by models that can't be configured with a
+// thinking budget.
+var ErrThinkingUnsupported = errors.New("thinking budget not supported")
+
+const (
+	_thinkOpenTag  = "<think>"
+	_thinkCloseTag = "</think>"
+)
+
+// SplitReasoning splits a completion of a model that inlines its reasoning in
+// <think></think> tags, such as DeepSeek-R1 served by Ollama, into the
+// reasoning and the final answer. Content without a leading think block is
+// returned unchanged as the answer.
+func SplitReasoning(content string) (reasoning, answer string) {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, _thinkOpenTag) {
+		return "", content
+	}
+	end := strings.Index(trimmed, _thinkCloseTag)
+	if end < 0 {
+		return "", content
+	}
+	reasoning = strings.TrimSpace(trimmed[len(_thinkOpenTag):end])
+	answer = strings.TrimLeft(trimmed[end+len(_thinkCloseTag):], " \t\r\n")
+	return reasoning, answer
+}
+
+// WithThinkingBudget enables the extended thinking of reasoning models, such
+// as Claude, letting them spend up to budget tokens on reasoning before they
+// answer. The reasoning is returned in ContentChoice.ReasoningContent. Models
+// that can't be configured with a budget return an error wrapping
+// ErrThinkingUnsupported.
+func WithThinkingBudget(budget int) CallOption {
+	return func(o *CallOptions) {
+		o.ThinkingBudget = budget
+	}
+}
@@ -0,0 +1,26 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitReasoning(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		content   string
+		reasoning string
+		answer    string
+	}{
+		{"<think>\nadd 1 and 1\n</think>\n\n2", "add 1 and 1", "2"},
+		{"2", "", "2"},
+		{"<think>unterminated", "", "<think>unterminated"},
+		{"answer <think>late</think>", "", "answer <think>late</think>"},
+	}
+	for _, tc := range cases {
+		reasoning, answer := SplitReasoning(tc.content)
+		assert.Equal(t, tc.reasoning, reasoning)
+		assert.Equal(t, tc.answer, answer)
+	}
+}
@@ -0,0 +1,263 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrRetriesExhausted is returned when a request still fails after all the
+// attempts allowed by a RetryPolicy.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried when a
+// RetryPolicy doesn't list any.
+var DefaultRetryableStatusCodes = []int{ //nolint:gochecknoglobals
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// BackoffFunc returns the delay before the given retry, starting at 1.
+type BackoffFunc func(retry int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc doubling the delay after each
+// retry, starting at base and capped at maxDelay.
+func ExponentialBackoff(base, maxDelay time.Duration) BackoffFunc {
+	return func(retry int) time.Duration {
+		delay := base
+		for i := 1; i < retry && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		return min(delay, maxDelay)
+	}
+}
+
+// RetryPolicy configures how failed requests to a provider are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff returns the delay before each retry. A Retry-After header sent
+	// by the provider takes precedence when it is longer.
+	Backoff BackoffFunc
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	// Network errors are always retried.
+	RetryableStatusCodes []int
+}
+
+// WithRetryPolicy will add an option to retry failed requests up to
+// maxAttempts times in total, waiting according to backoff between attempts.
+// If no status codes are given, DefaultRetryableStatusCodes are retried. A
+// nil backoff uses an exponential backoff starting at 500ms.
+func WithRetryPolicy(maxAttempts int, backoff BackoffFunc, retryableStatusCodes ...int) CallOption {
+	return func(o *CallOptions) {
+		if backoff == nil {
+			backoff = ExponentialBackoff(500*time.Millisecond, 30*time.Second)
+		}
+		if len(retryableStatusCodes) == 0 {
+			retryableStatusCodes = DefaultRetryableStatusCodes
+		}
+		o.RetryPolicy = &RetryPolicy{
+			MaxAttempts:          maxAttempts,
+			Backoff:              backoff,
+			RetryableStatusCodes: retryableStatusCodes,
+		}
+	}
+}
+
+// RetryError is returned when a request failed after all retries. It wraps
+// ErrRetriesExhausted and the error of the last attempt, if any.
+type RetryError struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+	// StatusCode is the HTTP status code of the last attempt, or 0 if it
+	// failed with a network error.
+	StatusCode int
+	// Body is the start of the response body of the last attempt.
+	Body string
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	msg := fmt.Sprintf("%s after %d attempts", ErrRetriesExhausted, e.Attempts)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(": status code %d", e.StatusCode)
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *RetryError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrRetriesExhausted}
+	}
+	return []error{ErrRetriesExhausted, e.Err}
+}
+
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy returns a context carrying the retry policy used by
+// DoRequest and RetryCall. See also ContextWithRequestOptions.
+func ContextWithRetryPolicy(ctx context.Context, policy *RetryPolicy) context.Context {
+	if policy == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// RetryPolicyFromContext returns the retry policy of the context, if any.
+func RetryPolicyFromContext(ctx context.Context) *RetryPolicy {
+	policy, _ := ctx.Value(retryPolicyKey{}).(*RetryPolicy)
+	return policy
+}
+
+// _maxRetryErrorBody is the maximum number of bytes of a response body kept
+// in a RetryError.
+const _maxRetryErrorBody = 1024
+
+// doWithRetry sends a request with the doer, retrying it according to policy.
+// Requests with a body are only retried if the body can be rewound with
+// GetBody.
+func doWithRetry(doer HTTPDoer, req *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	if policy == nil || policy.MaxAttempts <= 1 || (req.Body != nil && req.GetBody == nil) {
+		return doer.Do(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := doer.Do(req)
+		if err == nil && !slices.Contains(policy.RetryableStatusCodes, resp.StatusCode) {
+			return resp, nil
+		}
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+
+		var delay time.Duration
+		if policy.Backoff != nil {
+			delay = policy.Backoff(attempt)
+		}
+		if resp != nil {
+			delay = max(delay, retryAfter(resp))
+		}
+
+		if attempt >= policy.MaxAttempts {
+			retryErr := &RetryError{Attempts: attempt, Err: err}
+			if resp != nil {
+				retryErr.StatusCode = resp.StatusCode
+				body, _ := io.ReadAll(io.LimitReader(resp.Body, _maxRetryErrorBody))
+				retryErr.Body = string(body)
+				resp.Body.Close()
+			}
+			return nil, retryErr
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// RetryCall calls fn according to the retry policy of the context, for
+// providers that don't expose their HTTP requests. An error is retried if it
+// is an *LLMError or has an HTTPStatusCode or StatusCode method with a
+// retryable status code, so providers return their errors classified by
+// their wrapError functions.
+func RetryCall(ctx context.Context, fn func(ctx context.Context) error) error {
+	policy := RetryPolicyFromContext(ctx)
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		statusCode, ok := errorStatusCode(err)
+		if !ok || !slices.Contains(policy.RetryableStatusCodes, statusCode) {
+			return err
+		}
+		if attempt >= policy.MaxAttempts {
+			return &RetryError{Attempts: attempt, StatusCode: statusCode, Err: err}
+		}
+
+		var delay time.Duration
+		if policy.Backoff != nil {
+			delay = policy.Backoff(attempt)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func errorStatusCode(err error) (int, bool) {
+	var llmErr *LLMError
+	if errors.As(err, &llmErr) && llmErr.StatusCode != 0 {
+		return llmErr.StatusCode, true
+	}
+	var httpStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpStatus) {
+		return httpStatus.HTTPStatusCode(), true
+	}
+	var status interface{ StatusCode() int }
+	if errors.As(err, &status) {
+		return status.StatusCode(), true
+	}
+	return 0, false
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
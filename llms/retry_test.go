@@ -0,0 +1,113 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRequestRetries(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	opts := CallOptions{}
+	WithRetryPolicy(3, func(int) time.Duration { return time.Millisecond })(&opts)
+	ctx := ContextWithRetryPolicy(context.Background(), opts.RetryPolicy)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := DoRequest(http.DefaultClient, req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(body))
+	require.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	opts.RetryPolicy.MaxAttempts = 2
+	_, err = DoRequest(http.DefaultClient, req) //nolint:bodyclose
+	require.ErrorIs(t, err, ErrRetriesExhausted)
+	var retryErr *RetryError
+	require.ErrorAs(t, err, &retryErr)
+	require.Equal(t, 2, retryErr.Attempts)
+	require.Equal(t, http.StatusTooManyRequests, retryErr.StatusCode)
+}
+
+type statusError int
+
+func (e statusError) Error() string       { return "status error" }
+func (e statusError) HTTPStatusCode() int { return int(e) }
+
+func TestRetryCall(t *testing.T) {
+	t.Parallel()
+	policy := &RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: DefaultRetryableStatusCodes}
+	ctx := ContextWithRetryPolicy(context.Background(), policy)
+
+	calls := 0
+	err := RetryCall(ctx, func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return statusError(http.StatusServiceUnavailable)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	err = RetryCall(ctx, func(context.Context) error {
+		calls++
+		return statusError(http.StatusBadRequest)
+	})
+	require.Equal(t, statusError(http.StatusBadRequest), err)
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	err = RetryCall(ctx, func(context.Context) error {
+		calls++
+		return statusError(http.StatusTooManyRequests)
+	})
+	require.ErrorIs(t, err, ErrRetriesExhausted)
+	require.True(t, errors.Is(err, statusError(http.StatusTooManyRequests)))
+	require.Equal(t, 3, calls)
+
+	calls = 0
+	err = RetryCall(ctx, func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return NewLLMError(http.StatusTooManyRequests, "", "slow down", nil)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+	require.Equal(t, time.Second, backoff(1))
+	require.Equal(t, 2*time.Second, backoff(2))
+	require.Equal(t, 4*time.Second, backoff(3))
+	require.Equal(t, 5*time.Second, backoff(4))
+}
@@ -0,0 +1,252 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const _defaultMaxToolRounds = 10
+
+var (
+	// ErrMaxToolRoundsExceeded is returned by RunWithTools when the model is
+	// still requesting tool calls after the maximum number of rounds.
+	ErrMaxToolRoundsExceeded = errors.New("max tool rounds exceeded")
+	// ErrToolCallRejected is the error an approval hook can return to deny a
+	// tool call. The rejection is reported back to the model as the tool result.
+	ErrToolCallRejected = errors.New("tool call rejected")
+)
+
+// ToolExecutor is a tool that can be advertised to a model and executed when
+// the model asks for it.
+type ToolExecutor interface {
+	// Definition returns the tool definition sent to the model.
+	Definition() Tool
+	// Execute runs the tool with the JSON encoded arguments chosen by the model
+	// and returns the textual result.
+	Execute(ctx context.Context, arguments string) (string, error)
+}
+
+// ToolExecutorFunc is a function that implements the execution of a tool.
+type ToolExecutorFunc func(ctx context.Context, arguments string) (string, error)
+
+type funcToolExecutor struct {
+	definition FunctionDefinition
+	fn         ToolExecutorFunc
+}
+
+// NewToolExecutor creates a ToolExecutor from a function definition and the
+// function executing it.
+func NewToolExecutor(definition FunctionDefinition, fn ToolExecutorFunc) ToolExecutor { //nolint:ireturn
+	return funcToolExecutor{definition: definition, fn: fn}
+}
+
+func (f funcToolExecutor) Definition() Tool {
+	definition := f.definition
+	return Tool{Type: "function", Function: &definition}
+}
+
+func (f funcToolExecutor) Execute(ctx context.Context, arguments string) (string, error) {
+	return f.fn(ctx, arguments)
+}
+
+// ToolRunOption is a function that configures a ToolRunOptions.
+type ToolRunOption func(*ToolRunOptions)
+
+// ToolRunOptions is a set of options for RunWithTools.
+type ToolRunOptions struct {
+	// MaxRounds is the maximum number of generate/tool call rounds.
+	MaxRounds int
+	// Parallel executes the tool calls of a single round concurrently.
+	Parallel bool
+	// CallOptions are passed to the model on every generation.
+	CallOptions []CallOption
+	// ApproveToolCall is called before a tool call is executed. Returning an
+	// error skips the call and reports the error to the model as the result.
+	ApproveToolCall func(ctx context.Context, call ToolCall) error
+	// OnToolCall is called after every tool call with its result or error,
+	// including calls of unknown tools and calls rejected by
+	// ApproveToolCall, which are not executed and have an error. With
+	// Parallel, it is called concurrently for the calls of a round.
+	OnToolCall func(ctx context.Context, call ToolCall, result string, err error)
+	// OnResponse is called with every response returned by the model.
+	OnResponse func(ctx context.Context, resp *ContentResponse)
+}
+
+// WithMaxToolRounds sets the maximum number of rounds. Defaults to 10.
+func WithMaxToolRounds(maxRounds int) ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.MaxRounds = maxRounds
+	}
+}
+
+// WithParallelToolCalls executes the tool calls requested in a single round
+// concurrently.
+func WithParallelToolCalls() ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.Parallel = true
+	}
+}
+
+// WithToolRunCallOptions sets the call options used for every generation.
+func WithToolRunCallOptions(options ...CallOption) ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.CallOptions = append(o.CallOptions, options...)
+	}
+}
+
+// WithToolApproval sets a hook deciding whether a tool call may be executed.
+func WithToolApproval(approve func(ctx context.Context, call ToolCall) error) ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.ApproveToolCall = approve
+	}
+}
+
+// WithToolCallHook sets a hook called after every tool call, e.g. for logging.
+// See ToolRunOptions.OnToolCall for the calls it is called with.
+func WithToolCallHook(hook func(ctx context.Context, call ToolCall, result string, err error)) ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.OnToolCall = hook
+	}
+}
+
+// WithToolRunResponseHook sets a hook called with every model response.
+func WithToolRunResponseHook(hook func(ctx context.Context, resp *ContentResponse)) ToolRunOption {
+	return func(o *ToolRunOptions) {
+		o.OnResponse = hook
+	}
+}
+
+// RunWithTools runs the generate, tool call, tool result loop until the model
+// returns a response without tool calls. The tool results are added to the
+// conversation and returned together with the final response. Tool errors are
+// reported back to the model as the tool result so that it can recover.
+func RunWithTools(
+	ctx context.Context,
+	model Model,
+	messages []MessageContent,
+	executors []ToolExecutor,
+	options ...ToolRunOption,
+) (*ContentResponse, []MessageContent, error) {
+	opts := ToolRunOptions{MaxRounds: _defaultMaxToolRounds}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	tools := make([]Tool, 0, len(executors))
+	nameToExecutor := make(map[string]ToolExecutor, len(executors))
+	for _, executor := range executors {
+		tool := executor.Definition()
+		tools = append(tools, tool)
+		if tool.Function != nil {
+			nameToExecutor[tool.Function.Name] = executor
+		}
+	}
+
+	callOptions := append([]CallOption{}, opts.CallOptions...)
+	callOptions = append(callOptions, WithTools(tools))
+
+	history := append([]MessageContent{}, messages...)
+	for round := 0; round < opts.MaxRounds; round++ {
+		resp, err := model.GenerateContent(ctx, history, callOptions...)
+		if err != nil {
+			return nil, history, err
+		}
+		if opts.OnResponse != nil {
+			opts.OnResponse(ctx, resp)
+		}
+		if len(resp.Choices) < 1 {
+			return nil, history, errors.New("empty response from model")
+		}
+
+		choice := resp.Choices[0]
+		if len(choice.ToolCalls) == 0 {
+			history = append(history, TextParts(ChatMessageTypeAI, choice.Content))
+			return resp, history, nil
+		}
+
+		assistant := TextParts(ChatMessageTypeAI, choice.Content)
+		for _, tc := range choice.ToolCalls {
+			assistant.Parts = append(assistant.Parts, tc)
+		}
+		history = append(history, assistant)
+
+		for _, result := range executeToolCalls(ctx, choice.ToolCalls, nameToExecutor, opts) {
+			history = append(history, MessageContent{
+				Role:  ChatMessageTypeTool,
+				Parts: []ContentPart{result},
+			})
+		}
+	}
+
+	return nil, history, ErrMaxToolRoundsExceeded
+}
+
+func executeToolCalls(
+	ctx context.Context,
+	calls []ToolCall,
+	nameToExecutor map[string]ToolExecutor,
+	opts ToolRunOptions,
+) []ToolCallResponse {
+	results := make([]ToolCallResponse, len(calls))
+	if !opts.Parallel {
+		for i, call := range calls {
+			results[i] = executeToolCall(ctx, call, nameToExecutor, opts)
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call ToolCall) {
+			defer wg.Done()
+			results[i] = executeToolCall(ctx, call, nameToExecutor, opts)
+		}(i, call)
+	}
+	wg.Wait()
+	return results
+}
+
+func executeToolCall(
+	ctx context.Context,
+	call ToolCall,
+	nameToExecutor map[string]ToolExecutor,
+	opts ToolRunOptions,
+) ToolCallResponse {
+	response := ToolCallResponse{ToolCallID: call.ID}
+	if call.FunctionCall == nil {
+		response.Content = "error: tool call without function"
+		return response
+	}
+	response.Name = call.FunctionCall.Name
+
+	result, err := runToolCall(ctx, call, nameToExecutor, opts)
+	if opts.OnToolCall != nil {
+		opts.OnToolCall(ctx, call, result, err)
+	}
+	if err != nil {
+		result = fmt.Sprintf("error: %s", err)
+	}
+	response.Content = result
+	return response
+}
+
+func runToolCall(
+	ctx context.Context,
+	call ToolCall,
+	nameToExecutor map[string]ToolExecutor,
+	opts ToolRunOptions,
+) (string, error) {
+	executor, ok := nameToExecutor[call.FunctionCall.Name]
+	if !ok {
+		return "", fmt.Errorf("%s is not a valid tool", call.FunctionCall.Name)
+	}
+	if opts.ApproveToolCall != nil {
+		if err := opts.ApproveToolCall(ctx, call); err != nil {
+			return "", err
+		}
+	}
+	return executor.Execute(ctx, call.FunctionCall.Arguments)
+}
@@ -0,0 +1,116 @@
+package llms_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// scriptedModel returns the given responses in order and records the
+// messages it was called with.
+type scriptedModel struct {
+	responses []*llms.ContentResponse
+	calls     [][]llms.MessageContent
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	m.calls = append(m.calls, messages)
+	if len(m.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	resp := m.responses[0]
+	m.responses = m.responses[1:]
+	return resp, nil
+}
+
+func toolCallResponse(calls ...llms.ToolCall) *llms.ContentResponse {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{ToolCalls: calls}}}
+}
+
+func TestRunWithTools(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	echo := llms.NewToolExecutor(llms.FunctionDefinition{Name: "echo"},
+		func(_ context.Context, arguments string) (string, error) {
+			return "echo " + arguments, nil
+		})
+	model := &scriptedModel{responses: []*llms.ContentResponse{
+		toolCallResponse(
+			llms.ToolCall{ID: "1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "echo", Arguments: "a"}},
+			llms.ToolCall{ID: "2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "missing", Arguments: "b"}},
+		),
+		{Choices: []*llms.ContentChoice{{Content: "done"}}},
+	}}
+
+	var (
+		mu     sync.Mutex
+		logged []string
+	)
+	resp, history, err := llms.RunWithTools(ctx, model,
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")},
+		[]llms.ToolExecutor{echo},
+		llms.WithParallelToolCalls(),
+		llms.WithToolCallHook(func(_ context.Context, call llms.ToolCall, _ string, _ error) {
+			mu.Lock()
+			defer mu.Unlock()
+			logged = append(logged, call.ID)
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "done", resp.Choices[0].Content)
+	require.Len(t, history, 5)
+	require.Len(t, model.calls, 2)
+	require.ElementsMatch(t, []string{"1", "2"}, logged)
+
+	first, ok := history[2].Parts[0].(llms.ToolCallResponse)
+	require.True(t, ok)
+	require.Equal(t, "echo a", first.Content)
+	second, ok := history[3].Parts[0].(llms.ToolCallResponse)
+	require.True(t, ok)
+	require.Equal(t, "error: missing is not a valid tool", second.Content)
+}
+
+func TestRunWithToolsApprovalAndMaxRounds(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	executed := false
+	tool := llms.NewToolExecutor(llms.FunctionDefinition{Name: "delete"},
+		func(_ context.Context, _ string) (string, error) {
+			executed = true
+			return "deleted", nil
+		})
+	call := llms.ToolCall{ID: "1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "delete"}}
+	model := &scriptedModel{responses: []*llms.ContentResponse{
+		toolCallResponse(call),
+		toolCallResponse(call),
+	}}
+
+	var hookErrs []error
+	_, history, err := llms.RunWithTools(ctx, model, nil, []llms.ToolExecutor{tool},
+		llms.WithMaxToolRounds(2),
+		llms.WithToolApproval(func(_ context.Context, _ llms.ToolCall) error {
+			return llms.ErrToolCallRejected
+		}),
+		llms.WithToolCallHook(func(_ context.Context, _ llms.ToolCall, _ string, err error) {
+			hookErrs = append(hookErrs, err)
+		}),
+	)
+	require.ErrorIs(t, err, llms.ErrMaxToolRoundsExceeded)
+	require.False(t, executed)
+	require.Len(t, hookErrs, 2)
+	require.ErrorIs(t, hookErrs[0], llms.ErrToolCallRejected)
+	require.Len(t, history, 4)
+	result, ok := history[1].Parts[0].(llms.ToolCallResponse)
+	require.True(t, ok)
+	require.Equal(t, "error: tool call rejected", result.Content)
+}
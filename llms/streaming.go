@@ -0,0 +1,68 @@
+package llms
+
+import "context"
+
+// StreamingEventType is the type of a streaming event.
+type StreamingEventType string
+
+const (
+	// StreamingEventTextDelta is a chunk of the generated text.
+	StreamingEventTextDelta StreamingEventType = "text_delta"
+	// StreamingEventToolCallDelta is a chunk of a tool call requested by the
+	// model.
+	StreamingEventToolCallDelta StreamingEventType = "tool_call_delta"
+	// StreamingEventReasoningDelta is a chunk of the reasoning of the model.
+	StreamingEventReasoningDelta StreamingEventType = "reasoning_delta"
+	// StreamingEventUsageUpdate reports the token usage of the generation.
+	StreamingEventUsageUpdate StreamingEventType = "usage_update"
+	// StreamingEventDone is sent once the generation is complete.
+	StreamingEventDone StreamingEventType = "done"
+)
+
+// StreamingEvent is a typed event of a streaming response.
+type StreamingEvent struct {
+	// Type is the type of the event.
+	Type StreamingEventType `json:"type"`
+	// Text is the text chunk of text and reasoning deltas.
+	Text string `json:"text,omitempty"`
+	// ToolCall is the partial tool call of tool call deltas. The ID and
+	// function name are only set on the first delta of a tool call, later
+	// deltas carry chunks of the arguments.
+	ToolCall *ToolCall `json:"tool_call,omitempty"`
+	// ToolCallIndex is the index of the tool call a tool call delta belongs to.
+	ToolCallIndex int `json:"tool_call_index,omitempty"`
+	// Usage is the token usage of usage updates.
+	Usage *Usage `json:"usage,omitempty"`
+	// StopReason is the reason the model stopped generating, set on done events.
+	StopReason string `json:"stop_reason,omitempty"`
+}
+
+// WithStreamingEventFunc specifies the function called with every typed
+// streaming event. Providers without native support for typed events report
+// their streamed text as text deltas.
+func WithStreamingEventFunc(streamingEventFunc func(ctx context.Context, event StreamingEvent) error) CallOption {
+	return func(o *CallOptions) {
+		o.StreamingEventFunc = streamingEventFunc
+	}
+}
+
+// TextStreamingFunc returns a streaming function that forwards text chunks to
+// both the StreamingFunc and the StreamingEventFunc of the options. It returns
+// nil if neither is set. Providers that only stream text use it to support
+// typed streaming events.
+func TextStreamingFunc(opts CallOptions) func(ctx context.Context, chunk []byte) error {
+	if opts.StreamingEventFunc == nil {
+		return opts.StreamingFunc
+	}
+	return func(ctx context.Context, chunk []byte) error {
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, chunk); err != nil {
+				return err
+			}
+		}
+		return opts.StreamingEventFunc(ctx, StreamingEvent{
+			Type: StreamingEventTextDelta,
+			Text: string(chunk),
+		})
+	}
+}
@@ -0,0 +1,75 @@
+//go:build go1.23
+
+package llms
+
+import (
+	"context"
+	"iter"
+)
+
+type streamResult struct {
+	resp *ContentResponse
+	err  error
+}
+
+// GenerateContentStream calls GenerateContent on the model and returns an
+// iterator over the typed streaming events of the response. Breaking out of
+// the loop cancels the generation. If the generation fails the error is
+// yielded as the last element. A done event is always yielded on success,
+// also for providers that don't report it themselves.
+func GenerateContentStream(
+	ctx context.Context,
+	model Model,
+	messages []MessageContent,
+	options ...CallOption,
+) iter.Seq2[StreamingEvent, error] {
+	return func(yield func(StreamingEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		events := make(chan StreamingEvent)
+		result := make(chan streamResult, 1)
+		streamOptions := append([]CallOption{}, options...)
+		streamOptions = append(streamOptions, WithStreamingEventFunc(
+			func(ctx context.Context, event StreamingEvent) error {
+				select {
+				case events <- event:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}))
+
+		go func() {
+			defer close(events)
+			resp, err := model.GenerateContent(ctx, messages, streamOptions...)
+			result <- streamResult{resp: resp, err: err}
+		}()
+
+		done := false
+		for event := range events {
+			if event.Type == StreamingEventDone {
+				done = true
+			}
+			if !yield(event, nil) {
+				cancel()
+				for range events { //nolint:revive
+				}
+				return
+			}
+		}
+
+		r := <-result
+		if r.err != nil {
+			yield(StreamingEvent{}, r.err)
+			return
+		}
+		if !done {
+			event := StreamingEvent{Type: StreamingEventDone}
+			if r.resp != nil && len(r.resp.Choices) > 0 {
+				event.StopReason = r.resp.Choices[0].StopReason
+			}
+			yield(event, nil)
+		}
+	}
+}
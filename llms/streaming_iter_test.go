@@ -0,0 +1,77 @@
+//go:build go1.23
+
+package llms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// chunkModel streams its chunks as text and fails with err if it is set.
+type chunkModel struct {
+	chunks []string
+	err    error
+}
+
+func (m *chunkModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *chunkModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	streamingFunc := llms.TextStreamingFunc(opts)
+	content := ""
+	for _, chunk := range m.chunks {
+		if err := streamingFunc(ctx, []byte(chunk)); err != nil {
+			return nil, err
+		}
+		content += chunk
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content, StopReason: "stop"}}}, nil
+}
+
+func TestGenerateContentStream(t *testing.T) {
+	t.Parallel()
+	model := &chunkModel{chunks: []string{"a", "b", "c"}}
+
+	var text string
+	var last llms.StreamingEvent
+	for event, err := range llms.GenerateContentStream(context.Background(), model, nil) {
+		require.NoError(t, err)
+		text += event.Text
+		last = event
+	}
+	require.Equal(t, "abc", text)
+	require.Equal(t, llms.StreamingEventDone, last.Type)
+	require.Equal(t, "stop", last.StopReason)
+}
+
+func TestGenerateContentStreamBreakAndError(t *testing.T) {
+	t.Parallel()
+	model := &chunkModel{chunks: []string{"a", "b", "c"}, err: errors.New("boom")}
+
+	count := 0
+	for range llms.GenerateContentStream(context.Background(), model, nil) {
+		count++
+		break
+	}
+	require.Equal(t, 1, count)
+
+	var gotErr error
+	for _, err := range llms.GenerateContentStream(context.Background(), model, nil) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+	require.EqualError(t, gotErr, "boom")
+}
@@ -0,0 +1,114 @@
+package llms
+
+import (
+	"context"
+	"strings"
+)
+
+// TokenCounter counts the tokens of a sequence of messages.
+type TokenCounter interface {
+	// CountTokens returns the number of tokens the messages take up in the
+	// context window of a model.
+	CountTokens(ctx context.Context, messages []MessageContent) (int, error)
+}
+
+// ContextSizer is implemented by models that know the size of their context
+// window.
+type ContextSizer interface {
+	// MaxContextTokens returns the maximum number of tokens of the context
+	// window, including the generated tokens.
+	MaxContextTokens() int
+}
+
+// TiktokenCounter is a TokenCounter backed by tiktoken. It is exact for
+// OpenAI models and a reasonable estimate for most other models.
+type TiktokenCounter struct {
+	// Model is the name of the model whose encoding is used.
+	Model string
+}
+
+var _ TokenCounter = TiktokenCounter{}
+
+// CountTokens counts the tokens of the text of the messages.
+func (c TiktokenCounter) CountTokens(_ context.Context, messages []MessageContent) (int, error) {
+	total := 0
+	for _, mc := range messages {
+		total += CountTokens(c.Model, MessageText(mc))
+	}
+	return total, nil
+}
+
+// ApproximateTokenCounter is a TokenCounter estimating the number of tokens
+// from the length of the text. It is used when no tokenizer is available.
+type ApproximateTokenCounter struct {
+	// CharsPerToken is the average number of characters per token.
+	// Defaults to 4.
+	CharsPerToken int
+}
+
+var _ TokenCounter = ApproximateTokenCounter{}
+
+// CountTokens estimates the tokens of the text of the messages.
+func (c ApproximateTokenCounter) CountTokens(_ context.Context, messages []MessageContent) (int, error) {
+	charsPerToken := c.CharsPerToken
+	if charsPerToken <= 0 {
+		charsPerToken = _tokenApproximation
+	}
+	total := 0
+	for _, mc := range messages {
+		chars := len([]rune(MessageText(mc)))
+		total += (chars + charsPerToken - 1) / charsPerToken
+	}
+	return total, nil
+}
+
+// CountMessageTokens counts the tokens of the messages for the given model. It
+// uses the model's own token counting if it implements TokenCounter and falls
+// back to tiktoken otherwise.
+func CountMessageTokens(ctx context.Context, model Model, messages []MessageContent) (int, error) {
+	if counter, ok := model.(TokenCounter); ok {
+		return counter.CountTokens(ctx, messages)
+	}
+	return TiktokenCounter{}.CountTokens(ctx, messages)
+}
+
+// MaxContextTokens returns the size of the context window of the model. If
+// the model doesn't implement ContextSizer the default context size is
+// returned.
+func MaxContextTokens(model Model) int {
+	if sizer, ok := model.(ContextSizer); ok {
+		return sizer.MaxContextTokens()
+	}
+	return _defaultContextSize
+}
+
+// MessageText returns the textual content of a message, as used for token
+// counting. Non-text parts contribute their transcripts or arguments.
+func MessageText(mc MessageContent) string {
+	var sb strings.Builder
+	for _, part := range mc.Parts {
+		var text string
+		switch p := part.(type) {
+		case TextContent:
+			text = p.Text
+		case ToolCall:
+			if p.FunctionCall != nil {
+				text = p.FunctionCall.Name + " " + p.FunctionCall.Arguments
+			}
+		case ToolCallResponse:
+			text = p.Content
+		case AudioContent:
+			text = p.Transcript
+		case VideoContent:
+			text = p.Transcript
+		}
+		if text == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
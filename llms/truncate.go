@@ -0,0 +1,248 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTruncationBudget is returned when messages can't be truncated to fit the
+// token budget, e.g. because the last message alone exceeds it.
+var ErrTruncationBudget = errors.New("messages don't fit the token budget")
+
+// TruncationStrategy decides which messages are kept when truncating a
+// conversation to a token budget.
+type TruncationStrategy interface {
+	// Truncate returns messages fitting the budget according to counter.
+	Truncate(ctx context.Context, messages []MessageContent, budget int, counter TokenCounter) ([]MessageContent, error)
+}
+
+// TruncateOption is a function that configures TruncateOptions.
+type TruncateOption func(*TruncateOptions)
+
+// TruncateOptions configures TruncateMessages.
+type TruncateOptions struct {
+	// Counter counts the tokens of the messages. Defaults to a
+	// TiktokenCounter.
+	Counter TokenCounter
+}
+
+// WithTruncationCounter sets the token counter used to measure messages,
+// e.g. a model implementing TokenCounter.
+func WithTruncationCounter(counter TokenCounter) TruncateOption {
+	return func(o *TruncateOptions) {
+		o.Counter = counter
+	}
+}
+
+// TruncateMessages truncates a conversation to a token budget with the given
+// strategy. Leading system messages and the last message are always kept,
+// and tool responses are kept or dropped together with the message calling
+// the tools. Messages already fitting the budget are returned unchanged.
+func TruncateMessages(ctx context.Context, messages []MessageContent, budget int, strategy TruncationStrategy, opts ...TruncateOption) ([]MessageContent, error) { //nolint:lll
+	o := TruncateOptions{Counter: TiktokenCounter{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	total, err := o.Counter.CountTokens(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	if total <= budget {
+		return messages, nil
+	}
+	return strategy.Truncate(ctx, messages, budget, o.Counter)
+}
+
+// messageGroup is a message followed by the tool responses to its calls,
+// which must be kept or dropped together.
+type messageGroup struct {
+	messages []MessageContent
+	tokens   int
+}
+
+// splitConversation splits messages into the leading system messages and
+// the groups of the rest of the conversation.
+func splitConversation(ctx context.Context, messages []MessageContent, counter TokenCounter) ([]MessageContent, []messageGroup, int, error) { //nolint:lll
+	i := 0
+	for i < len(messages) && messages[i].Role == ChatMessageTypeSystem {
+		i++
+	}
+	system := messages[:i]
+	systemTokens, err := counter.CountTokens(ctx, system)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var groups []messageGroup
+	for _, msg := range messages[i:] {
+		if msg.Role == ChatMessageTypeTool && len(groups) > 0 {
+			last := &groups[len(groups)-1]
+			last.messages = append(last.messages, msg)
+			continue
+		}
+		groups = append(groups, messageGroup{messages: []MessageContent{msg}})
+	}
+	for i := range groups {
+		groups[i].tokens, err = counter.CountTokens(ctx, groups[i].messages)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	return system, groups, systemTokens, nil
+}
+
+func joinConversation(system []MessageContent, groups []messageGroup) []MessageContent {
+	result := make([]MessageContent, 0, len(system)+len(groups))
+	result = append(result, system...)
+	for _, g := range groups {
+		result = append(result, g.messages...)
+	}
+	return result
+}
+
+func groupTokens(groups []messageGroup) int {
+	total := 0
+	for _, g := range groups {
+		total += g.tokens
+	}
+	return total
+}
+
+// dropOldestGroups drops groups from the start, keeping the last one, until
+// the groups fit the budget. It returns the kept and dropped groups.
+func dropOldestGroups(groups []messageGroup, budget int) ([]messageGroup, []messageGroup, error) {
+	tokens := groupTokens(groups)
+	i := 0
+	for tokens > budget && i < len(groups)-1 {
+		tokens -= groups[i].tokens
+		i++
+	}
+	if tokens > budget {
+		return nil, nil, fmt.Errorf("%w: %d tokens over a budget of %d", ErrTruncationBudget, tokens, budget)
+	}
+	return groups[i:], groups[:i], nil
+}
+
+type dropOldest struct{}
+
+// DropOldest returns a strategy dropping the oldest messages until the
+// conversation fits the budget.
+func DropOldest() TruncationStrategy {
+	return dropOldest{}
+}
+
+func (dropOldest) Truncate(ctx context.Context, messages []MessageContent, budget int, counter TokenCounter) ([]MessageContent, error) { //nolint:lll
+	system, groups, systemTokens, err := splitConversation(ctx, messages, counter)
+	if err != nil {
+		return nil, err
+	}
+	kept, _, err := dropOldestGroups(groups, budget-systemTokens)
+	if err != nil {
+		return nil, err
+	}
+	return joinConversation(system, kept), nil
+}
+
+type middleOut struct{}
+
+// MiddleOut returns a strategy dropping messages from the middle of the
+// conversation outwards, keeping its first message, which often states the
+// task, and its most recent messages.
+func MiddleOut() TruncationStrategy {
+	return middleOut{}
+}
+
+func (middleOut) Truncate(ctx context.Context, messages []MessageContent, budget int, counter TokenCounter) ([]MessageContent, error) { //nolint:lll
+	system, groups, systemTokens, err := splitConversation(ctx, messages, counter)
+	if err != nil {
+		return nil, err
+	}
+	budget -= systemTokens
+
+	tokens := groupTokens(groups)
+	for tokens > budget && len(groups) > 2 {
+		// drop the group closest to the middle, never the first or last one.
+		middle := len(groups) / 2
+		if len(groups)%2 == 0 {
+			middle--
+		}
+		middle = max(1, min(middle, len(groups)-2))
+		tokens -= groups[middle].tokens
+		groups = append(groups[:middle:middle], groups[middle+1:]...)
+	}
+	if tokens > budget {
+		// fall back to keeping the most recent messages only.
+		groups, _, err = dropOldestGroups(groups, budget)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return joinConversation(system, groups), nil
+}
+
+const (
+	_defaultSummaryPrompt = "Summarize the following conversation concisely, " +
+		"keeping facts, decisions and open questions needed to continue it:\n\n"
+	_summaryPrefix = "Summary of the earlier conversation: "
+)
+
+type summarizeOldest struct {
+	model Model
+}
+
+// SummarizeOldest returns a strategy replacing the oldest messages with a
+// summary generated by model. The summary is added as a system message after
+// the leading system messages and takes up at most a quarter of the budget.
+func SummarizeOldest(model Model) TruncationStrategy {
+	return summarizeOldest{model: model}
+}
+
+func (s summarizeOldest) Truncate(ctx context.Context, messages []MessageContent, budget int, counter TokenCounter) ([]MessageContent, error) { //nolint:lll
+	system, groups, systemTokens, err := splitConversation(ctx, messages, counter)
+	if err != nil {
+		return nil, err
+	}
+	budget -= systemTokens
+	summaryBudget := budget / 4 //nolint:mnd
+
+	kept, dropped, err := dropOldestGroups(groups, budget-summaryBudget)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropped) == 0 {
+		return joinConversation(system, kept), nil
+	}
+
+	var transcript strings.Builder
+	for _, g := range dropped {
+		for _, msg := range g.messages {
+			fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, MessageText(msg))
+		}
+	}
+	summary, err := GenerateFromSinglePrompt(ctx, s.model, _defaultSummaryPrompt+transcript.String(),
+		WithMaxTokens(summaryBudget))
+	if err != nil {
+		return nil, fmt.Errorf("summarize messages: %w", err)
+	}
+	summaryMessage := TextParts(ChatMessageTypeSystem, _summaryPrefix+summary)
+	summaryTokens, err := counter.CountTokens(ctx, []MessageContent{summaryMessage})
+	if err != nil {
+		return nil, err
+	}
+
+	// the summary may be longer than requested; make room for it.
+	kept, _, err = dropOldestGroups(kept, budget-summaryTokens)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MessageContent, 0, len(system)+1+len(kept))
+	result = append(result, system...)
+	result = append(result, summaryMessage)
+	for _, g := range kept {
+		result = append(result, g.messages...)
+	}
+	return result, nil
+}
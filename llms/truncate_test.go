@@ -0,0 +1,73 @@
+package llms_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func conversation() []llms.MessageContent {
+	return []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "sys1"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "msg1"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{llms.ToolCall{ID: "1"}}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "1", Content: "tool"}}},
+		llms.TextParts(llms.ChatMessageTypeAI, "msg2"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "msg3"),
+		llms.TextParts(llms.ChatMessageTypeAI, "msg4"),
+	}
+}
+
+func texts(messages []llms.MessageContent) []string {
+	result := make([]string, len(messages))
+	for i, msg := range messages {
+		result[i] = llms.MessageText(msg)
+	}
+	return result
+}
+
+func TestTruncateMessages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	counter := llms.WithTruncationCounter(llms.ApproximateTokenCounter{})
+
+	messages, err := llms.TruncateMessages(ctx, conversation(), 100, llms.DropOldest(), counter)
+	require.NoError(t, err)
+	assert.Equal(t, conversation(), messages)
+
+	messages, err = llms.TruncateMessages(ctx, conversation(), 3, llms.DropOldest(), counter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys1", "msg3", "msg4"}, texts(messages))
+
+	// the tool call and its response are dropped together.
+	messages, err = llms.TruncateMessages(ctx, conversation(), 4, llms.DropOldest(), counter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys1", "msg2", "msg3", "msg4"}, texts(messages))
+
+	messages, err = llms.TruncateMessages(ctx, conversation(), 4, llms.MiddleOut(), counter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys1", "msg1", "msg3", "msg4"}, texts(messages))
+
+	_, err = llms.TruncateMessages(ctx, conversation(), 1, llms.DropOldest(), counter)
+	require.ErrorIs(t, err, llms.ErrTruncationBudget)
+}
+
+func TestTruncateMessagesSummarizeOldest(t *testing.T) {
+	t.Parallel()
+	model := &scriptedModel{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{Content: "sum"}}},
+	}}
+
+	// every message, including the summary, counts as one token.
+	messages, err := llms.TruncateMessages(context.Background(), conversation(), 5, llms.SummarizeOldest(model),
+		llms.WithTruncationCounter(llms.ApproximateTokenCounter{CharsPerToken: 100}))
+	require.NoError(t, err)
+	assert.Equal(t, llms.ChatMessageTypeSystem, messages[1].Role)
+	assert.Equal(t, "Summary of the earlier conversation: sum", llms.MessageText(messages[1]))
+	assert.Equal(t, []string{"msg2", "msg3", "msg4"}, texts(messages[2:]))
+	require.Len(t, model.calls, 1)
+	assert.Contains(t, llms.MessageText(model.calls[0][0]), "human: msg1")
+}
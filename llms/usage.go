@@ -0,0 +1,92 @@
+package llms
+
+import (
+	"context"
+	"sync"
+)
+
+// Pricing is the price of the tokens of a model, in dollars per million
+// tokens, used to estimate the cost of its calls.
+type Pricing struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+// Cost returns the estimated cost of the usage, in dollars.
+func (p Pricing) Cost(usage Usage) float64 {
+	const million = 1_000_000
+	return (float64(usage.PromptTokens)*p.PromptPerMillion + float64(usage.CompletionTokens)*p.CompletionPerMillion) / million //nolint:lll
+}
+
+// UsageTracker accumulates the token usage of model calls, recorded with
+// RecordUsage by the callers of the models, e.g. the agents. It is safe for
+// concurrent use.
+type UsageTracker struct {
+	mu    sync.Mutex
+	usage Usage
+	calls int
+}
+
+// NewUsageTracker creates a new UsageTracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{}
+}
+
+// Add adds the usage of a model call. The total tokens are the sum of the
+// prompt and completion tokens if not reported.
+func (t *UsageTracker) Add(usage Usage) {
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.PromptTokens += usage.PromptTokens
+	t.usage.CompletionTokens += usage.CompletionTokens
+	t.usage.TotalTokens += usage.TotalTokens
+	t.calls++
+}
+
+// Usage returns the usage of the calls recorded.
+func (t *UsageTracker) Usage() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+// Calls returns the number of calls recorded.
+func (t *UsageTracker) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls
+}
+
+type usageTrackersKey struct{}
+
+// ContextWithUsageTracker returns a context carrying the tracker, in addition
+// to the trackers of the parent context, so that nested runs are accounted
+// to each of them.
+func ContextWithUsageTracker(ctx context.Context, tracker *UsageTracker) context.Context {
+	if tracker == nil {
+		return ctx
+	}
+	parents := usageTrackersFromContext(ctx)
+	trackers := make([]*UsageTracker, 0, len(parents)+1)
+	trackers = append(trackers, parents...)
+	return context.WithValue(ctx, usageTrackersKey{}, append(trackers, tracker))
+}
+
+func usageTrackersFromContext(ctx context.Context) []*UsageTracker {
+	trackers, _ := ctx.Value(usageTrackersKey{}).([]*UsageTracker)
+	return trackers
+}
+
+// RecordUsage adds the usage of the response to the trackers of the context,
+// if any.
+func RecordUsage(ctx context.Context, resp *ContentResponse) {
+	if resp == nil {
+		return
+	}
+	for _, tracker := range usageTrackersFromContext(ctx) {
+		tracker.Add(resp.Usage)
+	}
+}
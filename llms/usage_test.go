@@ -0,0 +1,35 @@
+package llms_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+func TestUsageTracker(t *testing.T) {
+	t.Parallel()
+
+	outer, inner := llms.NewUsageTracker(), llms.NewUsageTracker()
+	ctx := llms.ContextWithUsageTracker(context.Background(), outer)
+	innerCtx := llms.ContextWithUsageTracker(ctx, inner)
+
+	llm := fake.New(
+		fake.Response{Content: "a", Usage: llms.Usage{PromptTokens: 10, CompletionTokens: 5}},
+		fake.Response{Content: "b", Usage: llms.Usage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30}},
+	)
+	_, err := llms.GenerateFromSinglePrompt(ctx, llm, "first")
+	assert.NoError(t, err)
+	_, err = llms.GenerateFromSinglePrompt(innerCtx, llm, "second")
+	assert.NoError(t, err)
+
+	assert.Equal(t, llms.Usage{PromptTokens: 30, CompletionTokens: 15, TotalTokens: 45}, outer.Usage())
+	assert.Equal(t, 2, outer.Calls())
+	assert.Equal(t, llms.Usage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30}, inner.Usage())
+	assert.Equal(t, 1, inner.Calls())
+
+	pricing := llms.Pricing{PromptPerMillion: 2, CompletionPerMillion: 8}
+	assert.InDelta(t, 0.00018, pricing.Cost(outer.Usage()), 1e-12)
+}
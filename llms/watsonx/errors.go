@@ -0,0 +1,44 @@
+package watsonx
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// _statusErrorPattern matches the errors the watsonx SDK returns for responses
+// with an error status code.
+var _statusErrorPattern = regexp.MustCompile(`(?s)^request failed with status code (\d+)(?: and error (.*))?$`)
+
+// wrapError classifies an error response of the watsonx.ai API as an
+// llms.LLMError. Other errors are returned unchanged.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	match := _statusErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	statusCode, _ := strconv.Atoi(match[1])
+	body := match[2]
+
+	var errorType, message string
+	var errResp struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal([]byte(body), &errResp) == nil && len(errResp.Errors) > 0 {
+		errorType = errResp.Errors[0].Code
+		message = errResp.Errors[0].Message
+	}
+
+	llmErr := llms.NewLLMError(statusCode, errorType, message, []byte(body))
+	llmErr.Message = err.Error()
+	llmErr.Err = err
+	return llmErr
+}
@@ -0,0 +1,35 @@
+package watsonx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestWrapError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		want llms.ErrorCode
+	}{
+		{errors.New("request failed with status code 429"), llms.ErrorCodeRateLimited},
+		{fmt.Errorf("request failed with status code 401 and error %s",
+			`{"errors":[{"code":"authentication_token_expired","message":"Failed to authenticate the request due to an expired token"}]}`),
+			llms.ErrorCodeAuthFailed},
+		{fmt.Errorf("request failed with status code 400 and error %s",
+			`{"errors":[{"code":"invalid_input_argument","message":"the number of input tokens 5000 cannot exceed the total tokens limit 4096 for this model"}]}`),
+			llms.ErrorCodeContextLengthExceeded},
+		{errors.New("request failed with status code 404 and error not found"), llms.ErrorCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		err := wrapError(tt.err)
+		assert.Equal(t, tt.want, llms.ErrorCodeOf(err), tt.err.Error())
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	errOther := errors.New("prompt cannot be empty")
+	assert.Same(t, errOther, wrapError(errOther))
+}
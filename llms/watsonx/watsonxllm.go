@@ -3,6 +3,7 @@ package watsonx
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	wx "github.com/h0rv/go-watsonx/models"
 	"github.com/tmc/langchaingo/callbacks"
@@ -38,10 +39,23 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		return nil, err
 	}
 
-	result, err := o.client.GenerateText(
-		prompt,
-		toWatsonxOptions(&options)...,
-	)
+	opts := getDefaultCallOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	wxOptions, err := toWatsonxOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	var result wx.GenerateTextResult
+	err = llms.RetryCall(llms.ContextWithRetryPolicy(ctx, opts.RetryPolicy), func(context.Context) error {
+		var err error
+		result, err = o.client.GenerateText(
+			prompt,
+			wxOptions...,
+		)
+		return wrapError(err)
+	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
 			o.CallbacksHandler.HandleLLMError(ctx, err)
@@ -97,10 +111,14 @@ func getDefaultCallOptions() *llms.CallOptions {
 	}
 }
 
-func toWatsonxOptions(options *[]llms.CallOption) []wx.GenerateOption {
-	opts := getDefaultCallOptions()
-	for _, opt := range *options {
-		opt(opts)
+func toWatsonxOptions(opts *llms.CallOptions) ([]wx.GenerateOption, error) {
+	// the watsonx SDK doesn't expose its HTTP client.
+	if len(opts.HTTPHeaders) > 0 || len(opts.QueryParams) > 0 {
+		return nil, fmt.Errorf("%w: the watsonx SDK doesn't support extra HTTP headers or query parameters",
+			llms.ErrHTTPOverrideUnsupported)
+	}
+	if len(opts.RawParams) > 0 {
+		return nil, fmt.Errorf("%w: the watsonx SDK doesn't expose the request payload", llms.ErrRawParamsUnsupported)
 	}
 
 	o := []wx.GenerateOption{}
@@ -137,5 +155,5 @@ func toWatsonxOptions(options *[]llms.CallOption) []wx.GenerateOption {
 	   	wx.WithReturnOptions(inputText, generatedTokens, inputTokens, tokenLogProbs, tokenRanks, topNTokens)
 	*/
 
-	return o
+	return o, nil
 }
@@ -0,0 +1,27 @@
+package watsonx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestHTTPOverridesUnsupported(t *testing.T) {
+	t.Parallel()
+	callOpts := getDefaultCallOptions()
+	llms.WithQueryParam("priority", "high")(callOpts)
+	_, err := toWatsonxOptions(callOpts)
+	require.ErrorIs(t, err, llms.ErrHTTPOverrideUnsupported)
+
+	callOpts = getDefaultCallOptions()
+	llms.WithRawParams(map[string]any{"moderations": map[string]any{}})(callOpts)
+	_, err = toWatsonxOptions(callOpts)
+	require.ErrorIs(t, err, llms.ErrRawParamsUnsupported)
+
+	callOpts = getDefaultCallOptions()
+	llms.WithTemperature(0.5)(callOpts)
+	opts, err := toWatsonxOptions(callOpts)
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+}
@@ -5,5 +5,10 @@ a variety of implementations for storing and retrieving that data.
 The main components of this package are:
 - ChatMessageHistory: a struct that stores chat messages.
 - ConversationBuffer: a simple form of memory that remembers previous conversational back and forth directly.
+- ConversationSummary: a memory keeping a summary of the conversation generated by an LLM.
+- ConversationSummaryBuffer: a memory keeping the recent messages up to a token limit and a summary of the older ones.
+- ConversationEntity: a memory keeping summaries of the entities of the conversation in an EntityStore.
+- VectorStoreMemory: a memory storing the turns in a vector store and returning those most relevant to the input.
+- SessionStore: an interface for managing the sessions of multiple users, each with its own memory.
 */
 package memory
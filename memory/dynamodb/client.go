@@ -0,0 +1,106 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	_targetPrefix = "DynamoDB_20120810."
+	_contentType  = "application/x-amz-json-1.0"
+	_service      = "dynamodb"
+)
+
+// attributeValue is a DynamoDB attribute value, of the types used by the
+// history.
+type attributeValue struct {
+	S string           `json:"S,omitempty"`
+	N string           `json:"N,omitempty"`
+	L []attributeValue `json:"L,omitempty"`
+}
+
+func stringValue(s string) map[string]any {
+	return map[string]any{"S": s}
+}
+
+func numberValue(n int64) map[string]any {
+	return map[string]any{"N": fmt.Sprint(n)}
+}
+
+func listValue(values []string) map[string]any {
+	list := make([]any, 0, len(values))
+	for _, value := range values {
+		list = append(list, stringValue(value))
+	}
+	return map[string]any{"L": list}
+}
+
+// apiError is the body of the errors of the DynamoDB API.
+type apiError struct {
+	Type         string `json:"__type"`
+	Message      string `json:"message"`
+	MessageUpper string `json:"Message"`
+}
+
+// call sends a request of the operation, signed with the credentials of the
+// configuration if any, and decodes the response into output.
+func (h *ChatMessageHistory) call(ctx context.Context, operation string, input, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", _contentType)
+	req.Header.Set("X-Amz-Target", _targetPrefix+operation)
+
+	if h.cfg.Credentials != nil {
+		credentials, err := h.cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return fmt.Errorf("retrieving credentials: %w", err)
+		}
+		hash := sha256.Sum256(body)
+		err = h.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), _service, h.cfg.Region, time.Now())
+		if err != nil {
+			return fmt.Errorf("signing request: %w", err)
+		}
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		var apiErr apiError
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Type == "" {
+			return fmt.Errorf("%w: %s: status %d: %s", ErrRequestFailed, operation, resp.StatusCode, data)
+		}
+		message := apiErr.Message
+		if message == "" {
+			message = apiErr.MessageUpper
+		}
+		// The type is prefixed with the namespace of the service.
+		_, errType, _ := strings.Cut(apiErr.Type, "#")
+		if errType == "" {
+			errType = apiErr.Type
+		}
+		return fmt.Errorf("%w: %s: %s: %s", ErrRequestFailed, operation, errType, message)
+	}
+	if output == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(output)
+}
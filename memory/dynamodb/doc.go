@@ -0,0 +1,13 @@
+// Package dynamodb provides a chat message history backed by Amazon
+// DynamoDB, for serverless deployments, e.g. on AWS Lambda, where local
+// files aren't an option.
+//
+// The messages of a session are stored in a single item, whose partition key
+// is the session ID, as a list appended to atomically. The item can carry a
+// TTL attribute for DynamoDB to delete inactive sessions. The table must
+// exist, with a string partition key named "SessionId" by default.
+//
+// The requests are sent to the DynamoDB JSON API signed with the credentials
+// of the aws.Config, so that the package doesn't depend on the DynamoDB
+// client of the AWS SDK.
+package dynamodb
@@ -0,0 +1,235 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _historyAttribute = "History"
+
+var (
+	// ErrInvalidTableName is returned by New when the table name is empty.
+	ErrInvalidTableName = errors.New("invalid dynamodb table name")
+	// ErrInvalidSessionID is returned by New when the session ID is empty.
+	ErrInvalidSessionID = errors.New("invalid dynamodb session id")
+	// ErrMissingRegion is returned by New when neither the region nor the
+	// endpoint is set.
+	ErrMissingRegion = errors.New("missing aws region")
+	// ErrRequestFailed is returned when a request to DynamoDB fails.
+	ErrRequestFailed = errors.New("dynamodb request failed")
+)
+
+// ChatMessageHistory is a schema.ChatMessageHistory storing the messages of
+// a session in a DynamoDB item.
+type ChatMessageHistory struct {
+	cfg          aws.Config
+	signer       *v4.Signer
+	httpClient   llms.HTTPDoer
+	endpoint     string
+	tableName    string
+	sessionID    string
+	partitionKey string
+	ttl          time.Duration
+	ttlAttribute string
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// New returns a ChatMessageHistory storing the messages of the session in
+// the table, with the region, credentials and HTTP client of the
+// configuration, e.g. loaded with config.LoadDefaultConfig.
+func New(cfg aws.Config, tableName, sessionID string, opts ...Option) (*ChatMessageHistory, error) {
+	if tableName == "" {
+		return nil, ErrInvalidTableName
+	}
+	if sessionID == "" {
+		return nil, ErrInvalidSessionID
+	}
+	h := &ChatMessageHistory{
+		cfg:          cfg,
+		signer:       v4.NewSigner(),
+		tableName:    tableName,
+		sessionID:    sessionID,
+		partitionKey: DefaultPartitionKey,
+		ttlAttribute: DefaultTTLAttribute,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.endpoint == "" {
+		if cfg.Region == "" {
+			return nil, ErrMissingRegion
+		}
+		h.endpoint = fmt.Sprintf("https://dynamodb.%s.amazonaws.com", cfg.Region)
+	}
+	if h.httpClient == nil {
+		h.httpClient = http.DefaultClient
+		if cfg.HTTPClient != nil {
+			h.httpClient = cfg.HTTPClient
+		}
+	}
+	return h, nil
+}
+
+// key returns the key of the item of the session.
+func (h *ChatMessageHistory) key() map[string]any {
+	return map[string]any{h.partitionKey: stringValue(h.sessionID)}
+}
+
+// Messages returns all messages stored. The messages of an expired session
+// not yet deleted by DynamoDB are ignored.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	var output struct {
+		Item map[string]attributeValue `json:"Item"`
+	}
+	err := h.call(ctx, "GetItem", map[string]any{
+		"TableName":                h.tableName,
+		"Key":                      h.key(),
+		"ConsistentRead":           true,
+		"ProjectionExpression":     "#h, #t",
+		"ExpressionAttributeNames": map[string]string{"#h": _historyAttribute, "#t": h.ttlAttribute},
+	}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if expireAt := output.Item[h.ttlAttribute].N; expireAt != "" {
+		seconds, err := strconv.ParseInt(expireAt, 10, 64)
+		if err == nil && time.Unix(seconds, 0).Before(time.Now()) {
+			return []llms.ChatMessage{}, nil
+		}
+	}
+
+	values := output.Item[_historyAttribute].L
+	messages := make([]llms.ChatMessage, 0, len(values))
+	for _, value := range values {
+		var m messageModel
+		if err := json.Unmarshal([]byte(value.S), &m); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m.toChatMessage())
+	}
+	return messages, nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage appends a message to the item of the session, renewing its
+// time-to-live if set.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	value, err := encodeMessages([]llms.ChatMessage{message})
+	if err != nil {
+		return err
+	}
+
+	expression := "SET #h = list_append(if_not_exists(#h, :empty), :m)"
+	names := map[string]string{"#h": _historyAttribute}
+	values := map[string]any{":empty": listValue(nil), ":m": listValue(value)}
+	if h.ttl > 0 {
+		expression += ", #t = :t"
+		names["#t"] = h.ttlAttribute
+		values[":t"] = numberValue(time.Now().Add(h.ttl).Unix())
+	}
+	return h.call(ctx, "UpdateItem", map[string]any{
+		"TableName":                 h.tableName,
+		"Key":                       h.key(),
+		"UpdateExpression":          expression,
+		"ExpressionAttributeNames":  names,
+		"ExpressionAttributeValues": values,
+	}, nil)
+}
+
+// SetMessages replaces existing messages in the store.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	values, err := encodeMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	item := h.key()
+	item[_historyAttribute] = listValue(values)
+	if h.ttl > 0 {
+		item[h.ttlAttribute] = numberValue(time.Now().Add(h.ttl).Unix())
+	}
+	return h.call(ctx, "PutItem", map[string]any{
+		"TableName": h.tableName,
+		"Item":      item,
+	}, nil)
+}
+
+// Clear deletes the item of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	return h.call(ctx, "DeleteItem", map[string]any{
+		"TableName": h.tableName,
+		"Key":       h.key(),
+	}, nil)
+}
+
+// messageModel is the JSON encoding of a stored message.
+type messageModel struct {
+	Type       llms.ChatMessageType `json:"type"`
+	Content    string               `json:"content"`
+	Name       string               `json:"name,omitempty"`
+	ToolCalls  []llms.ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string               `json:"tool_call_id,omitempty"`
+}
+
+func encodeMessages(messages []llms.ChatMessage) ([]string, error) {
+	values := make([]string, 0, len(messages))
+	for _, message := range messages {
+		m := messageModel{Type: message.GetType(), Content: message.GetContent()}
+		switch message := message.(type) {
+		case llms.AIChatMessage:
+			m.ToolCalls = message.ToolCalls
+		case llms.ToolChatMessage:
+			m.ToolCallID = message.ID
+		case llms.FunctionChatMessage:
+			m.Name = message.Name
+		case llms.GenericChatMessage:
+			m.Name = message.Role
+		}
+		value, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, string(value))
+	}
+	return values, nil
+}
+
+func (m messageModel) toChatMessage() llms.ChatMessage {
+	switch m.Type {
+	case llms.ChatMessageTypeAI:
+		return llms.AIChatMessage{Content: m.Content, ToolCalls: m.ToolCalls}
+	case llms.ChatMessageTypeHuman:
+		return llms.HumanChatMessage{Content: m.Content}
+	case llms.ChatMessageTypeSystem:
+		return llms.SystemChatMessage{Content: m.Content}
+	case llms.ChatMessageTypeTool:
+		return llms.ToolChatMessage{ID: m.ToolCallID, Content: m.Content}
+	case llms.ChatMessageTypeFunction:
+		return llms.FunctionChatMessage{Name: m.Name, Content: m.Content}
+	default:
+		return llms.GenericChatMessage{Role: m.Name, Content: m.Content}
+	}
+}
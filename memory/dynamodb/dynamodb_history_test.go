@@ -0,0 +1,142 @@
+package dynamodb_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/dynamodb"
+)
+
+type attributeValue struct {
+	S string           `json:"S,omitempty"`
+	N string           `json:"N,omitempty"`
+	L []attributeValue `json:"L,omitempty"`
+}
+
+// fakeDynamoDB implements the operations of the DynamoDB API used by the
+// history, for items keyed by SessionId.
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string]map[string]attributeValue
+}
+
+func (f *fakeDynamoDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"com.amazon.coral.service#MissingAuthenticationTokenException","message":"missing token"}`))
+		return
+	}
+	var input struct {
+		TableName                 string
+		Key                       map[string]attributeValue
+		Item                      map[string]attributeValue
+		ExpressionAttributeValues map[string]attributeValue
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.TableName != "chats" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"Requested resource not found"}`)) //nolint:lll
+		return
+	}
+
+	switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.") {
+	case "GetItem":
+		_ = json.NewEncoder(w).Encode(map[string]any{"Item": f.items[input.Key["SessionId"].S]})
+		return
+	case "PutItem":
+		f.items[input.Item["SessionId"].S] = input.Item
+	case "UpdateItem":
+		session := input.Key["SessionId"].S
+		item, ok := f.items[session]
+		if !ok {
+			item = map[string]attributeValue{"SessionId": {S: session}}
+			f.items[session] = item
+		}
+		history := item["History"]
+		history.L = append(history.L, input.ExpressionAttributeValues[":m"].L...)
+		item["History"] = history
+		if ttl, ok := input.ExpressionAttributeValues[":t"]; ok {
+			item["ExpireAt"] = ttl
+		}
+	case "DeleteItem":
+		delete(f.items, input.Key["SessionId"].S)
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake := &fakeDynamoDB{items: map[string]map[string]attributeValue{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}, nil
+		}),
+	}
+	_, err := dynamodb.New(cfg, "chats", "")
+	require.ErrorIs(t, err, dynamodb.ErrInvalidSessionID)
+	_, err = dynamodb.New(aws.Config{}, "chats", "session-1")
+	require.ErrorIs(t, err, dynamodb.ErrMissingRegion)
+
+	h, err := dynamodb.New(cfg, "chats", "session-1", dynamodb.WithEndpoint(server.URL), dynamodb.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	toolCalls := []llms.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}
+	require.NoError(t, h.AddUserMessage(ctx, "weather in Paris?"))
+	require.NoError(t, h.AddMessage(ctx, llms.AIChatMessage{ToolCalls: toolCalls}))
+	require.NoError(t, h.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "sunny"}))
+	require.NoError(t, h.AddAIMessage(ctx, "It's sunny."))
+
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "weather in Paris?"},
+		llms.AIChatMessage{ToolCalls: toolCalls},
+		llms.ToolChatMessage{ID: "call_1", Content: "sunny"},
+		llms.AIChatMessage{Content: "It's sunny."},
+	}, messages)
+	assert.NotEmpty(t, fake.items["session-1"]["ExpireAt"].N)
+
+	require.NoError(t, h.SetMessages(ctx, []llms.ChatMessage{llms.SystemChatMessage{Content: "be brief"}}))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.SystemChatMessage{Content: "be brief"}}, messages)
+
+	// Expired sessions not yet deleted are empty.
+	fake.items["session-1"]["ExpireAt"] = attributeValue{N: "1"}
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	require.NoError(t, h.Clear(ctx))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	other, err := dynamodb.New(cfg, "missing", "session-1", dynamodb.WithEndpoint(server.URL))
+	require.NoError(t, err)
+	_, err = other.Messages(ctx)
+	require.ErrorIs(t, err, dynamodb.ErrRequestFailed)
+	require.ErrorContains(t, err, "GetItem: ResourceNotFoundException: Requested resource not found")
+}
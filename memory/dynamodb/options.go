@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// DefaultPartitionKey is the default name of the partition key of the
+	// table, holding the session ID.
+	DefaultPartitionKey = "SessionId"
+	// DefaultTTLAttribute is the default name of the TTL attribute, holding
+	// the expiration time of the session in seconds since the epoch.
+	DefaultTTLAttribute = "ExpireAt"
+)
+
+// Option is a function that configures a ChatMessageHistory.
+type Option func(*ChatMessageHistory)
+
+// WithPartitionKey sets the name of the partition key of the table.
+func WithPartitionKey(name string) Option {
+	return func(h *ChatMessageHistory) {
+		h.partitionKey = name
+	}
+}
+
+// WithTTL sets the time-to-live of the session, renewed each time messages
+// are added, stored in the TTL attribute of its item. Time to live must be
+// enabled on the attribute for DynamoDB to delete expired sessions. By
+// default sessions don't expire.
+func WithTTL(ttl time.Duration) Option {
+	return func(h *ChatMessageHistory) {
+		h.ttl = ttl
+	}
+}
+
+// WithTTLAttribute sets the name of the TTL attribute of the table.
+func WithTTLAttribute(name string) Option {
+	return func(h *ChatMessageHistory) {
+		h.ttlAttribute = name
+	}
+}
+
+// WithEndpoint sets the URL of the DynamoDB API, e.g. of DynamoDB local. By
+// default it is the endpoint of the region of the configuration.
+func WithEndpoint(endpoint string) Option {
+	return func(h *ChatMessageHistory) {
+		h.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the client sending the requests. By default it is the
+// HTTP client of the configuration, or http.DefaultClient.
+func WithHTTPClient(client llms.HTTPDoer) Option {
+	return func(h *ChatMessageHistory) {
+		h.httpClient = client
+	}
+}
@@ -0,0 +1,310 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// defaultEntityWindowSize is the default number of previous turns used as
+	// context by ConversationEntity.
+	defaultEntityWindowSize = 3
+	// defaultEntitiesKey is the default key of the entity summaries.
+	defaultEntitiesKey = "entities"
+	// noEntities is the output of the extraction prompt when the input
+	// mentions no entity.
+	noEntities = "NONE"
+)
+
+//nolint:lll
+const _defaultEntityExtractionTemplate = `You are an AI assistant reading the transcript of a conversation between an AI and a human. Extract all of the proper nouns from the last line of conversation. As a guideline, a proper noun is generally capitalized. You should definitely extract all names and places.
+
+The conversation history is provided just in case of a coreference (e.g. "What do you know about him" where "him" is defined in a previous line) -- ignore items mentioned there that are not in the last line.
+
+Return the output as a single comma-separated list, or NONE if there is nothing of note to return (e.g. the user is just issuing a greeting or having a simple conversation).
+
+EXAMPLE
+Conversation history:
+Person #1: how's it going today?
+AI: "It's going great! How about you?"
+Person #1: good! busy working on Langchain. lots to do.
+AI: "That sounds like a lot of work! What kind of things are you doing to make Langchain better?"
+Last line:
+Person #1: i'm trying to improve Langchain's interfaces, the UX, its integrations with various products the user might want ... a lot of stuff. I'm working with Person #2.
+Output: Langchain, Person #2
+END OF EXAMPLE
+
+Conversation history (for reference only):
+{{.history}}
+Last line of conversation (for extraction):
+Human: {{.input}}
+
+Output:`
+
+//nolint:lll
+const _defaultEntitySummarizationTemplate = `You are an AI assistant helping a human keep track of facts about relevant people, places, and concepts in their life. Update the summary of the provided entity in the "Entity" section based on the last line of your conversation with the human. If you are writing the summary for the first time, return a single sentence.
+The update should only include facts that are relayed in the last line of conversation about the provided entity, and should only contain facts about the provided entity.
+
+If there is no new information about the provided entity or the information is not worth noting (not an important or relevant fact to remember long-term), return the existing summary unchanged.
+
+Full conversation history (for context):
+{{.history}}
+
+Entity to summarize:
+{{.entity}}
+
+Existing summary of {{.entity}}:
+{{.summary}}
+
+Last line of conversation:
+Human: {{.input}}
+Updated summary:`
+
+// DefaultEntityExtractionPrompt is the prompt used by ConversationEntity to
+// extract the entities of the input, in the "input" variable, given the
+// recent messages in the "history" variable. The LLM answers with a
+// comma-separated list of entities, or NONE.
+func DefaultEntityExtractionPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultEntityExtractionTemplate, []string{"history", "input"})
+}
+
+// DefaultEntitySummarizationPrompt is the prompt used by ConversationEntity
+// to update the summary of an entity, with the "entity", "summary", "history"
+// and "input" variables.
+func DefaultEntitySummarizationPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(
+		_defaultEntitySummarizationTemplate,
+		[]string{"history", "entity", "summary", "input"},
+	)
+}
+
+// ConversationEntity is a memory tracking the entities of the conversation,
+// such as people and places. When loading the memory variables, the LLM
+// extracts the entities mentioned by the input, whose summaries are returned
+// in the entities key along with the recent messages. When saving the
+// context, the LLM updates the summaries of these entities with the turn.
+type ConversationEntity struct {
+	ConversationBuffer
+	// LLM is the model extracting and summarizing the entities.
+	LLM llms.Model
+	// Store stores the summaries of the entities.
+	Store EntityStore
+	// ExtractionPrompt is the prompt extracting the entities of the input.
+	ExtractionPrompt prompts.FormatPrompter
+	// SummarizationPrompt is the prompt updating the summary of an entity.
+	SummarizationPrompt prompts.FormatPrompter
+	// WindowSize is the number of previous turns returned in the memory key
+	// and used as context by the prompts.
+	WindowSize int
+	// EntitiesKey is the key of the summaries of the entities, formatted as
+	// one "entity: summary" line per entity.
+	EntitiesKey string
+	// CallOptions are the options of the calls to the LLM.
+	CallOptions []llms.CallOption
+
+	mu sync.Mutex
+	// entities are the entities extracted from the last input.
+	entities []string
+}
+
+// Statically assert that ConversationEntity implement the memory interface.
+var _ schema.Memory = &ConversationEntity{}
+
+// NewConversationEntity is a function for creating a new entity memory,
+// storing the summaries in memory and using the default prompts.
+func NewConversationEntity(llm llms.Model, options ...ConversationBufferOption) *ConversationEntity {
+	return &ConversationEntity{
+		ConversationBuffer:  *applyBufferOptions(options...),
+		LLM:                 llm,
+		Store:               NewInMemoryEntityStore(),
+		ExtractionPrompt:    DefaultEntityExtractionPrompt(),
+		SummarizationPrompt: DefaultEntitySummarizationPrompt(),
+		WindowSize:          defaultEntityWindowSize,
+		EntitiesKey:         defaultEntitiesKey,
+	}
+}
+
+// MemoryVariables returns the memory key and the entities key.
+func (e *ConversationEntity) MemoryVariables(context.Context) []string {
+	return []string{e.MemoryKey, e.EntitiesKey}
+}
+
+// LoadMemoryVariables extracts the entities of the input and returns their
+// summaries in the entities key, and the recent messages in the memory key.
+func (e *ConversationEntity) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	messages, err := e.recentMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []string
+	if len(inputs) > 0 {
+		input, err := getInputValue(inputs, e.InputKey)
+		if err != nil {
+			return nil, err
+		}
+		entities, err = e.extractEntities(ctx, messages, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.mu.Lock()
+	e.entities = entities
+	e.mu.Unlock()
+
+	var notes strings.Builder
+	for _, entity := range entities {
+		summary, err := e.Store.Get(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		if summary == "" {
+			continue
+		}
+		if notes.Len() > 0 {
+			notes.WriteString("\n")
+		}
+		fmt.Fprintf(&notes, "%s: %s", entity, summary)
+	}
+
+	if e.ReturnMessages {
+		return map[string]any{e.MemoryKey: messages, e.EntitiesKey: notes.String()}, nil
+	}
+	bufferString, err := llms.GetBufferString(messages, e.HumanPrefix, e.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{e.MemoryKey: bufferString, e.EntitiesKey: notes.String()}, nil
+}
+
+// SaveContext uses ConversationBuffer method for saving context, and updates
+// the summaries of the entities extracted when loading the memory variables,
+// or of the input if they weren't loaded.
+func (e *ConversationEntity) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	input, err := getInputValue(inputValues, e.InputKey)
+	if err != nil {
+		return err
+	}
+	if err := e.ConversationBuffer.SaveContext(ctx, inputValues, outputValues); err != nil {
+		return err
+	}
+	messages, err := e.recentMessages(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	entities := e.entities
+	e.entities = nil
+	e.mu.Unlock()
+	if entities == nil {
+		// The input is the second to last message, just saved.
+		entities, err = e.extractEntities(ctx, messages[:max(len(messages)-2, 0)], input)
+		if err != nil {
+			return err
+		}
+	}
+
+	history, err := llms.GetBufferString(messages, e.HumanPrefix, e.AIPrefix)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		summary, err := e.Store.Get(ctx, entity)
+		if err != nil {
+			return err
+		}
+		summary, err = e.generate(ctx, e.SummarizationPrompt, map[string]any{
+			"history": history,
+			"entity":  entity,
+			"summary": summary,
+			"input":   input,
+		})
+		if err != nil {
+			return err
+		}
+		if err := e.Store.Set(ctx, entity, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear clears the messages and the summaries of the entities.
+func (e *ConversationEntity) Clear(ctx context.Context) error {
+	e.mu.Lock()
+	e.entities = nil
+	e.mu.Unlock()
+	if err := e.ConversationBuffer.Clear(ctx); err != nil {
+		return err
+	}
+	return e.Store.Clear(ctx)
+}
+
+// recentMessages returns the messages of the last turns of the window.
+func (e *ConversationEntity) recentMessages(ctx context.Context) ([]llms.ChatMessage, error) {
+	messages, err := e.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n := e.WindowSize * defaultMessageSize; n >= 0 && len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+	return messages, nil
+}
+
+// extractEntities returns the entities of the input extracted by the LLM.
+func (e *ConversationEntity) extractEntities(
+	ctx context.Context, messages []llms.ChatMessage, input string,
+) ([]string, error) {
+	history, err := llms.GetBufferString(messages, e.HumanPrefix, e.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	output, err := e.generate(ctx, e.ExtractionPrompt, map[string]any{
+		"history": history,
+		"input":   input,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []string{}
+	if output == noEntities {
+		return entities, nil
+	}
+	seen := make(map[string]bool)
+	for _, entity := range strings.Split(output, ",") {
+		entity = strings.TrimSpace(entity)
+		if entity == "" || seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func (e *ConversationEntity) generate(
+	ctx context.Context, prompt prompts.FormatPrompter, values map[string]any,
+) (string, error) {
+	promptValue, err := prompt.FormatPrompt(values)
+	if err != nil {
+		return "", err
+	}
+	result, err := llms.GenerateFromSinglePrompt(ctx, e.LLM, promptValue.String(), e.CallOptions...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result), nil
+}
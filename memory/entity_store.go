@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// EntityStore stores the summaries of the entities of a conversation, keyed
+// by the name of the entity.
+type EntityStore interface {
+	// Get returns the summary of the entity, or an empty string if the entity
+	// is unknown.
+	Get(ctx context.Context, entity string) (string, error)
+	// Set sets the summary of the entity.
+	Set(ctx context.Context, entity, summary string) error
+	// Delete deletes the summary of the entity.
+	Delete(ctx context.Context, entity string) error
+	// Clear deletes the summaries of all entities.
+	Clear(ctx context.Context) error
+}
+
+// InMemoryEntityStore is an EntityStore keeping the summaries in memory.
+type InMemoryEntityStore struct {
+	mu        sync.RWMutex
+	summaries map[string]string
+}
+
+// Statically assert that InMemoryEntityStore implement the entity store interface.
+var _ EntityStore = &InMemoryEntityStore{}
+
+// NewInMemoryEntityStore creates a new in-memory entity store.
+func NewInMemoryEntityStore() *InMemoryEntityStore {
+	return &InMemoryEntityStore{summaries: make(map[string]string)}
+}
+
+// Get returns the summary of the entity.
+func (s *InMemoryEntityStore) Get(_ context.Context, entity string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.summaries[entity], nil
+}
+
+// Set sets the summary of the entity.
+func (s *InMemoryEntityStore) Set(_ context.Context, entity, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summaries[entity] = summary
+	return nil
+}
+
+// Delete deletes the summary of the entity.
+func (s *InMemoryEntityStore) Delete(_ context.Context, entity string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.summaries, entity)
+	return nil
+}
+
+// Clear deletes the summaries of all entities.
+func (s *InMemoryEntityStore) Clear(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summaries = make(map[string]string)
+	return nil
+}
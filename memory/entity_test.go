@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestConversationEntity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(fake.TextResponses(
+		"Alice, Paris, Alice",
+		"Alice lives in Paris.",
+		"Paris is where Alice lives.",
+		"Alice",
+		"Alice lives in Paris and works as a baker.",
+		"NONE",
+	)...)
+	m := NewConversationEntity(llm)
+	m.SummarizationPrompt = prompts.NewPromptTemplate(
+		"{{.entity}}|{{.summary}}|{{.input}}", []string{"entity", "summary", "input"},
+	)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "Alice lives in Paris."})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "", "entities": ""}, result)
+	err = m.SaveContext(ctx, map[string]any{"input": "Alice lives in Paris."}, map[string]any{"output": "Nice."})
+	require.NoError(t, err)
+	assert.Len(t, llm.Calls(), 3)
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Equal(t, "Paris||Alice lives in Paris.", prompt)
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "Where does Alice work?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history":  "Human: Alice lives in Paris.\nAI: Nice.",
+		"entities": "Alice: Alice lives in Paris.",
+	}, result)
+	prompt = llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, prompt, "Human: Alice lives in Paris.\nAI: Nice.\n"+
+		"Last line of conversation (for extraction):\nHuman: Where does Alice work?\n")
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "She is a baker."}, map[string]any{"output": "Ok."}))
+	prompt = llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Equal(t, "Alice|Alice lives in Paris.|She is a baker.", prompt)
+
+	// Without loading the memory variables, the entities are extracted when
+	// saving the context.
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "Thanks!"}, map[string]any{"output": "Bye."}))
+	assert.Len(t, llm.Calls(), 6)
+
+	summary, err := m.Store.Get(ctx, "Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice lives in Paris and works as a baker.", summary)
+
+	require.NoError(t, m.Clear(ctx))
+	summary, err = m.Store.Get(ctx, "Alice")
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+}
@@ -0,0 +1,9 @@
+// Package jsonl provides a chat message history stored in a JSON Lines file,
+// for CLIs and local agents wanting durable transcripts without a database.
+//
+// Messages are appended to the file, one JSON object per line with the time
+// it was added. Replacing the messages writes a new file renamed over the
+// previous one, so that the file is never left partially written. The file
+// can be rotated once it reaches a size, the previous files being kept as
+// transcripts with numbered suffixes.
+package jsonl
@@ -0,0 +1,267 @@
+package jsonl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidPath is returned by New when the path is empty.
+var ErrInvalidPath = errors.New("invalid jsonl history path")
+
+// ChatMessageHistory is a schema.ChatMessageHistory storing messages in a
+// JSON Lines file. It is safe for concurrent use in a process.
+type ChatMessageHistory struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	sync       bool
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// New returns a ChatMessageHistory storing the messages in the file at path,
+// creating its directory if needed. The file is created when the first
+// message is added.
+func New(path string, opts ...Option) (*ChatMessageHistory, error) {
+	if path == "" {
+		return nil, ErrInvalidPath
+	}
+	h := &ChatMessageHistory{path: path}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:mnd
+		return nil, err
+	}
+	return h, nil
+}
+
+// record is a line of the file.
+type record struct {
+	Time       time.Time            `json:"time"`
+	Type       llms.ChatMessageType `json:"type"`
+	Content    string               `json:"content"`
+	Name       string               `json:"name,omitempty"`
+	ToolCalls  []llms.ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string               `json:"tool_call_id,omitempty"`
+}
+
+// Messages returns the messages of the file, ignoring a last line left
+// incomplete by a crash.
+func (h *ChatMessageHistory) Messages(_ context.Context) ([]llms.ChatMessage, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []llms.ChatMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A line is complete once its newline is written, so the last element is
+	// either empty or a line whose write was interrupted.
+	lines := bytes.Split(data, []byte("\n"))
+	messages := make([]llms.ChatMessage, 0, len(lines))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r record
+		if err := json.Unmarshal(line, &r); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("%s:%d: %w", h.path, i+1, err)
+		}
+		messages = append(messages, r.toChatMessage())
+	}
+	return messages, nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage appends a message to the file, rotating it first if it reached
+// its maximum size.
+func (h *ChatMessageHistory) AddMessage(_ context.Context, message llms.ChatMessage) error {
+	line, err := encode(message)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.rotate(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:mnd
+	if err != nil {
+		return err
+	}
+	// The line is written at once, so that it isn't interleaved with the
+	// writes of other processes appending to the file.
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if h.sync {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// SetMessages replaces the messages of the file.
+func (h *ChatMessageHistory) SetMessages(_ context.Context, messages []llms.ChatMessage) error {
+	var buf bytes.Buffer
+	for _, message := range messages {
+		line, err := encode(message)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.replace(buf.Bytes())
+}
+
+// Clear removes the messages of the file. The rotated files are kept.
+func (h *ChatMessageHistory) Clear(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.replace(nil)
+}
+
+// replace atomically replaces the content of the file, by writing a
+// temporary file renamed over it.
+func (h *ChatMessageHistory) replace(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if h.sync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), h.path)
+}
+
+// rotate renames the file with the suffix ".1" if it reached its maximum
+// size, shifting the suffixes of the previous rotated files.
+func (h *ChatMessageHistory) rotate() error {
+	if h.maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(h.path)
+	if errors.Is(err, fs.ErrNotExist) || err == nil && info.Size() < h.maxSize {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	backups := h.backups()
+	if h.maxBackups > 0 {
+		for n := backups; n >= h.maxBackups; n-- {
+			if err := os.Remove(backupPath(h.path, n)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		backups = min(backups, h.maxBackups-1)
+	}
+	for n := backups; n >= 1; n-- {
+		if err := os.Rename(backupPath(h.path, n), backupPath(h.path, n+1)); err != nil {
+			return err
+		}
+	}
+	return os.Rename(h.path, backupPath(h.path, 1))
+}
+
+// backups returns the number of rotated files, numbered from 1.
+func (h *ChatMessageHistory) backups() int {
+	n := 0
+	for {
+		if _, err := os.Stat(backupPath(h.path, n+1)); err != nil {
+			return n
+		}
+		n++
+	}
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func encode(message llms.ChatMessage) ([]byte, error) {
+	r := record{Time: time.Now().UTC(), Type: message.GetType(), Content: message.GetContent()}
+	switch message := message.(type) {
+	case llms.AIChatMessage:
+		r.ToolCalls = message.ToolCalls
+	case llms.ToolChatMessage:
+		r.ToolCallID = message.ID
+	case llms.FunctionChatMessage:
+		r.Name = message.Name
+	case llms.GenericChatMessage:
+		r.Name = message.Role
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+func (r record) toChatMessage() llms.ChatMessage {
+	switch r.Type {
+	case llms.ChatMessageTypeAI:
+		return llms.AIChatMessage{Content: r.Content, ToolCalls: r.ToolCalls}
+	case llms.ChatMessageTypeHuman:
+		return llms.HumanChatMessage{Content: r.Content}
+	case llms.ChatMessageTypeSystem:
+		return llms.SystemChatMessage{Content: r.Content}
+	case llms.ChatMessageTypeTool:
+		return llms.ToolChatMessage{ID: r.ToolCallID, Content: r.Content}
+	case llms.ChatMessageTypeFunction:
+		return llms.FunctionChatMessage{Name: r.Name, Content: r.Content}
+	default:
+		return llms.GenericChatMessage{Role: r.Name, Content: r.Content}
+	}
+}
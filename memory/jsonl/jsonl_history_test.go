@@ -0,0 +1,101 @@
+package jsonl_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/jsonl"
+)
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "transcripts", "session.jsonl")
+
+	_, err := jsonl.New("")
+	require.ErrorIs(t, err, jsonl.ErrInvalidPath)
+
+	h, err := jsonl.New(path, jsonl.WithSync())
+	require.NoError(t, err)
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	toolCalls := []llms.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}
+	require.NoError(t, h.AddUserMessage(ctx, "weather in Paris?"))
+	require.NoError(t, h.AddMessage(ctx, llms.AIChatMessage{ToolCalls: toolCalls}))
+	require.NoError(t, h.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "sunny"}))
+	require.NoError(t, h.AddAIMessage(ctx, "It's sunny."))
+
+	expected := []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "weather in Paris?"},
+		llms.AIChatMessage{ToolCalls: toolCalls},
+		llms.ToolChatMessage{ID: "call_1", Content: "sunny"},
+		llms.AIChatMessage{Content: "It's sunny."},
+	}
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, messages)
+
+	// A line left incomplete by a crash is ignored.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"2024-01-01T00:00:00Z","type":"hu`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, messages)
+
+	require.NoError(t, h.SetMessages(ctx, []llms.ChatMessage{llms.GenericChatMessage{Role: "critic", Content: "be brief"}}))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.GenericChatMessage{Role: "critic", Content: "be brief"}}, messages)
+
+	require.NoError(t, h.Clear(ctx))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are removed")
+}
+
+func TestChatMessageHistoryRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	h, err := jsonl.New(path, jsonl.WithMaxSize(1), jsonl.WithMaxBackups(2))
+	require.NoError(t, err)
+
+	for _, text := range []string{"one", "two", "three", "four"} {
+		require.NoError(t, h.AddUserMessage(ctx, text))
+	}
+
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.HumanChatMessage{Content: "four"}}, messages)
+
+	for n, text := range map[string]string{".1": "three", ".2": "two"} {
+		backup, err := jsonl.New(path + n)
+		require.NoError(t, err)
+		messages, err := backup.Messages(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []llms.ChatMessage{llms.HumanChatMessage{Content: text}}, messages)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
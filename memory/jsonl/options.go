@@ -0,0 +1,30 @@
+package jsonl
+
+// Option is a function that configures a ChatMessageHistory.
+type Option func(*ChatMessageHistory)
+
+// WithMaxSize sets the size in bytes after which the file is rotated when a
+// message is added: it is renamed with the suffix ".1", the previous rotated
+// files being renamed with the next suffix, and the messages are then
+// appended to a new file. By default the file isn't rotated.
+func WithMaxSize(bytes int64) Option {
+	return func(h *ChatMessageHistory) {
+		h.maxSize = bytes
+	}
+}
+
+// WithMaxBackups sets the number of rotated files kept, the oldest ones being
+// deleted. By default all are kept.
+func WithMaxBackups(n int) Option {
+	return func(h *ChatMessageHistory) {
+		h.maxBackups = n
+	}
+}
+
+// WithSync makes the history sync the file to disk after each write, so that
+// the messages added aren't lost on a crash of the system.
+func WithSync() Option {
+	return func(h *ChatMessageHistory) {
+		h.sync = true
+	}
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidOpenAIMessages is returned when importing messages which aren't
+// in the OpenAI format.
+var ErrInvalidOpenAIMessages = errors.New("invalid openai messages")
+
+// openAIMessage is a message of the OpenAI chat completions API.
+type openAIMessage struct {
+	Role         string             `json:"role"`
+	Content      json.RawMessage    `json:"content"`
+	Name         string             `json:"name,omitempty"`
+	ToolCalls    []openAIToolCall   `json:"tool_calls,omitempty"`
+	ToolCallID   string             `json:"tool_call_id,omitempty"`
+	FunctionCall *llms.FunctionCall `json:"function_call,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Function llms.FunctionCall `json:"function"`
+}
+
+// openAIContentPart is a part of the content of a message, of which only
+// the text parts are imported.
+type openAIContentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// MarshalOpenAIMessages encodes the messages as a JSON array of messages of
+// the OpenAI chat completions API, with their tool calls. Generic messages are
+// encoded with their role.
+func MarshalOpenAIMessages(messages []llms.ChatMessage) ([]byte, error) {
+	encoded := make([]openAIMessage, 0, len(messages))
+	for _, message := range messages {
+		m := openAIMessage{}
+		switch message := message.(type) {
+		case llms.SystemChatMessage:
+			m.Role = "system"
+		case llms.HumanChatMessage:
+			m.Role = "user"
+		case llms.AIChatMessage:
+			m.Role = "assistant"
+			m.FunctionCall = message.FunctionCall
+			for _, toolCall := range message.ToolCalls {
+				call := openAIToolCall{ID: toolCall.ID, Type: toolCall.Type}
+				if call.Type == "" {
+					call.Type = "function"
+				}
+				if toolCall.FunctionCall != nil {
+					call.Function = *toolCall.FunctionCall
+				}
+				m.ToolCalls = append(m.ToolCalls, call)
+			}
+		case llms.ToolChatMessage:
+			m.Role = "tool"
+			m.ToolCallID = message.ID
+		case llms.FunctionChatMessage:
+			m.Role = "function"
+			m.Name = message.Name
+		case llms.GenericChatMessage:
+			m.Role = message.Role
+			m.Name = message.Name
+		default:
+			return nil, fmt.Errorf("%w: unsupported message type %T", ErrInvalidOpenAIMessages, message)
+		}
+
+		content := message.GetContent()
+		if content == "" && (len(m.ToolCalls) > 0 || m.FunctionCall != nil) {
+			m.Content = json.RawMessage("null")
+		} else {
+			raw, err := json.Marshal(content)
+			if err != nil {
+				return nil, err
+			}
+			m.Content = raw
+		}
+		encoded = append(encoded, m)
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalOpenAIMessages decodes messages of the OpenAI chat completions
+// API, given as a JSON array or as the "messages" field of a request. The
+// text parts of array contents are joined, and the other parts are ignored.
+// Developer messages are decoded as system messages, and messages of unknown
+// roles as generic messages.
+func UnmarshalOpenAIMessages(data []byte) ([]llms.ChatMessage, error) {
+	var encoded []openAIMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var request struct {
+			Messages []openAIMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(data, &request); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOpenAIMessages, err)
+		}
+		encoded = request.Messages
+	} else if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOpenAIMessages, err)
+	}
+
+	messages := make([]llms.ChatMessage, 0, len(encoded))
+	for i, m := range encoded {
+		content, err := decodeOpenAIContent(m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: message %d: %w", ErrInvalidOpenAIMessages, i, err)
+		}
+
+		switch m.Role {
+		case "system", "developer":
+			messages = append(messages, llms.SystemChatMessage{Content: content})
+		case "user":
+			messages = append(messages, llms.HumanChatMessage{Content: content})
+		case "assistant":
+			message := llms.AIChatMessage{Content: content, FunctionCall: m.FunctionCall}
+			for _, call := range m.ToolCalls {
+				function := call.Function
+				message.ToolCalls = append(message.ToolCalls, llms.ToolCall{
+					ID:           call.ID,
+					Type:         call.Type,
+					FunctionCall: &function,
+				})
+			}
+			messages = append(messages, message)
+		case "tool":
+			messages = append(messages, llms.ToolChatMessage{ID: m.ToolCallID, Content: content})
+		case "function":
+			messages = append(messages, llms.FunctionChatMessage{Name: m.Name, Content: content})
+		case "":
+			return nil, fmt.Errorf("%w: message %d: missing role", ErrInvalidOpenAIMessages, i)
+		default:
+			messages = append(messages, llms.GenericChatMessage{Role: m.Role, Name: m.Name, Content: content})
+		}
+	}
+	return messages, nil
+}
+
+// ExportOpenAIMessages returns the messages of the chat history encoded as
+// with MarshalOpenAIMessages.
+func ExportOpenAIMessages(ctx context.Context, history schema.ChatMessageHistory) ([]byte, error) {
+	messages, err := history.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalOpenAIMessages(messages)
+}
+
+// ImportOpenAIMessages replaces the messages of the chat history with the
+// messages decoded as with UnmarshalOpenAIMessages.
+func ImportOpenAIMessages(ctx context.Context, history schema.ChatMessageHistory, data []byte) error {
+	messages, err := UnmarshalOpenAIMessages(data)
+	if err != nil {
+		return err
+	}
+	return history.SetMessages(ctx, messages)
+}
+
+// decodeOpenAIContent returns the content of a message, given as a string,
+// null or an array of parts.
+func decodeOpenAIContent(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var content string
+	if err := json.Unmarshal(raw, &content); err == nil {
+		return content, nil
+	}
+	var parts []openAIContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", err
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), nil
+}
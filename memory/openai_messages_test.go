@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestOpenAIMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "be brief"},
+		llms.HumanChatMessage{Content: "weather in Paris?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "sunny"},
+		llms.AIChatMessage{Content: "It's sunny."},
+		llms.GenericChatMessage{Role: "critic", Content: "ok"},
+	}
+	history := NewChatMessageHistory(WithPreviousMessages(messages))
+
+	data, err := ExportOpenAIMessages(ctx, history)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"role": "system", "content": "be brief"},
+		{"role": "user", "content": "weather in Paris?"},
+		{"role": "assistant", "content": null, "tool_calls": [
+			{"id": "call_1", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Paris\"}"}}
+		]},
+		{"role": "tool", "content": "sunny", "tool_call_id": "call_1"},
+		{"role": "assistant", "content": "It's sunny."},
+		{"role": "critic", "content": "ok"}
+	]`, string(data))
+
+	imported := NewChatMessageHistory()
+	require.NoError(t, ImportOpenAIMessages(ctx, imported, data))
+	result, err := imported.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, messages, result)
+
+	// Requests and array contents are imported too.
+	result, err = UnmarshalOpenAIMessages([]byte(`{"model": "gpt-4o", "messages": [
+		{"role": "developer", "content": "be brief"},
+		{"role": "user", "content": [
+			{"type": "text", "text": "what is this?"},
+			{"type": "image_url", "image_url": {"url": "https://example.com/cat.png"}}
+		]}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "be brief"},
+		llms.HumanChatMessage{Content: "what is this?"},
+	}, result)
+
+	_, err = UnmarshalOpenAIMessages([]byte(`[{"content": "no role"}]`))
+	require.ErrorIs(t, err, ErrInvalidOpenAIMessages)
+	_, err = UnmarshalOpenAIMessages([]byte(`[{"role": "user", "content": 42}]`))
+	require.ErrorIs(t, err, ErrInvalidOpenAIMessages)
+}
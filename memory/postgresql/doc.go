@@ -0,0 +1,8 @@
+// Package postgresql provides a chat message history backed by PostgreSQL,
+// for applications already running PostgreSQL.
+//
+// The messages are stored in a table, created by New unless WithoutMigration
+// is given, with their session, an optional user, their creation time,
+// metadata, and the tool calls of the AI messages. The sessions of a user can
+// be listed with Sessions.
+package postgresql
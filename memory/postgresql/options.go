@@ -0,0 +1,31 @@
+package postgresql
+
+// DefaultTableName is the default name of the table storing the messages.
+const DefaultTableName = "langchaingo_chat_messages"
+
+// Option is a function that configures a ChatMessageHistory.
+type Option func(*ChatMessageHistory)
+
+// WithTableName sets the name of the table storing the messages, optionally
+// qualified with a schema, e.g. "chat.messages".
+func WithTableName(name string) Option {
+	return func(h *ChatMessageHistory) {
+		h.tableName = name
+	}
+}
+
+// WithUserID sets the user of the session, stored with the messages added.
+func WithUserID(userID string) Option {
+	return func(h *ChatMessageHistory) {
+		h.userID = userID
+	}
+}
+
+// WithoutMigration disables the creation of the table and its indexes by
+// New, e.g. when the schema is managed by migrations of the application. See
+// Schema.
+func WithoutMigration() Option {
+	return func(h *ChatMessageHistory) {
+		h.skipMigration = true
+	}
+}
@@ -0,0 +1,260 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidSessionID is returned by New when the session ID is empty.
+var ErrInvalidSessionID = errors.New("invalid postgresql session id")
+
+// PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
+type PGXConn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+}
+
+// ChatMessageHistory is a schema.ChatMessageHistory storing the messages of
+// a session in a PostgreSQL table.
+type ChatMessageHistory struct {
+	conn          PGXConn
+	sessionID     string
+	userID        string
+	tableName     string
+	skipMigration bool
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// Record is a message stored with its metadata.
+type Record struct {
+	ID        int64
+	SessionID string
+	UserID    string
+	Message   llms.ChatMessage
+	Metadata  map[string]any
+	CreatedAt time.Time
+}
+
+// New returns a ChatMessageHistory storing the messages of the session using
+// conn, creating the table of the messages if it doesn't exist.
+func New(ctx context.Context, conn PGXConn, sessionID string, opts ...Option) (*ChatMessageHistory, error) {
+	if sessionID == "" {
+		return nil, ErrInvalidSessionID
+	}
+	h := &ChatMessageHistory{conn: conn, sessionID: sessionID, tableName: DefaultTableName}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if !h.skipMigration {
+		if _, err := conn.Exec(ctx, Schema(h.tableName)); err != nil {
+			return nil, fmt.Errorf("creating the chat messages table: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// Schema returns the statements creating the table of the messages with the
+// name, and its indexes, if they don't exist.
+func Schema(tableName string) string {
+	table := identifier(tableName)
+	parts := strings.Split(tableName, ".")
+	index := parts[len(parts)-1]
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id BIGSERIAL PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	user_id TEXT NOT NULL DEFAULT '',
+	type TEXT NOT NULL,
+	content TEXT NOT NULL,
+	name TEXT NOT NULL DEFAULT '',
+	tool_calls JSONB,
+	tool_call_id TEXT NOT NULL DEFAULT '',
+	metadata JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (session_id, id);
+CREATE INDEX IF NOT EXISTS %[3]s ON %[1]s (user_id, created_at);`,
+		table, identifier(index+"_session_idx"), identifier(index+"_user_idx"))
+}
+
+// identifier returns the quoted identifier of a name, optionally qualified
+// with a schema.
+func identifier(name string) string {
+	return pgx.Identifier(strings.Split(name, ".")).Sanitize()
+}
+
+// Messages returns all messages stored.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	records, err := h.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]llms.ChatMessage, 0, len(records))
+	for _, record := range records {
+		messages = append(messages, record.Message)
+	}
+	return messages, nil
+}
+
+// Records returns the messages of the session with their metadata, in the
+// order they were added.
+func (h *ChatMessageHistory) Records(ctx context.Context) ([]Record, error) {
+	rows, err := h.conn.Query(ctx, fmt.Sprintf(
+		`SELECT id, session_id, user_id, type, content, name, tool_calls, tool_call_id, metadata, created_at
+		FROM %s WHERE session_id = $1 ORDER BY id`, identifier(h.tableName)), h.sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var (
+			record                             Record
+			msgType, content, name, toolCallID string
+			toolCalls                          []llms.ToolCall
+		)
+		err := rows.Scan(&record.ID, &record.SessionID, &record.UserID, &msgType, &content, &name,
+			&toolCalls, &toolCallID, &record.Metadata, &record.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		record.Message = toChatMessage(llms.ChatMessageType(msgType), content, name, toolCalls, toolCallID)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// Sessions returns the IDs of the sessions of the user of the history, the
+// most recently updated first.
+func (h *ChatMessageHistory) Sessions(ctx context.Context) ([]string, error) {
+	rows, err := h.conn.Query(ctx, fmt.Sprintf(
+		`SELECT session_id FROM %s WHERE user_id = $1 GROUP BY session_id ORDER BY max(created_at) DESC`,
+		identifier(h.tableName)), h.userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the store.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	return h.AddMessageWithMetadata(ctx, message, nil)
+}
+
+// AddMessageWithMetadata adds a message to the store with metadata, e.g. the
+// model or the latency of an answer.
+func (h *ChatMessageHistory) AddMessageWithMetadata(
+	ctx context.Context,
+	message llms.ChatMessage,
+	metadata map[string]any,
+) error {
+	return h.insert(ctx, h.conn, message, metadata)
+}
+
+// SetMessages replaces existing messages in the store.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	return pgx.BeginFunc(ctx, h.conn, func(tx pgx.Tx) error {
+		if err := h.clear(ctx, tx); err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if err := h.insert(ctx, tx, message, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Clear removes the messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	return h.clear(ctx, h.conn)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func (h *ChatMessageHistory) clear(ctx context.Context, conn execer) error {
+	_, err := conn.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, identifier(h.tableName)), h.sessionID)
+	return err
+}
+
+func (h *ChatMessageHistory) insert(
+	ctx context.Context,
+	conn execer,
+	message llms.ChatMessage,
+	metadata map[string]any,
+) error {
+	var (
+		name, toolCallID string
+		toolCalls        []byte
+	)
+	switch m := message.(type) {
+	case llms.AIChatMessage:
+		if len(m.ToolCalls) > 0 {
+			var err error
+			if toolCalls, err = json.Marshal(m.ToolCalls); err != nil {
+				return err
+			}
+		}
+	case llms.ToolChatMessage:
+		toolCallID = m.ID
+	case llms.FunctionChatMessage:
+		name = m.Name
+	case llms.GenericChatMessage:
+		name = m.Role
+	}
+
+	_, err := conn.Exec(ctx, fmt.Sprintf(
+		`INSERT INTO %s (session_id, user_id, type, content, name, tool_calls, tool_call_id, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, identifier(h.tableName)),
+		h.sessionID, h.userID, string(message.GetType()), message.GetContent(), name, toolCalls, toolCallID, metadata)
+	return err
+}
+
+// toChatMessage returns the message of the columns of a row.
+func toChatMessage(
+	msgType llms.ChatMessageType,
+	content, name string,
+	toolCalls []llms.ToolCall,
+	toolCallID string,
+) llms.ChatMessage {
+	switch msgType {
+	case llms.ChatMessageTypeAI:
+		return llms.AIChatMessage{Content: content, ToolCalls: toolCalls}
+	case llms.ChatMessageTypeHuman:
+		return llms.HumanChatMessage{Content: content}
+	case llms.ChatMessageTypeSystem:
+		return llms.SystemChatMessage{Content: content}
+	case llms.ChatMessageTypeTool:
+		return llms.ToolChatMessage{ID: toolCallID, Content: content}
+	case llms.ChatMessageTypeFunction:
+		return llms.FunctionChatMessage{Name: name, Content: content}
+	default:
+		return llms.GenericChatMessage{Role: name, Content: content}
+	}
+}
@@ -0,0 +1,109 @@
+package postgresql_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/postgresql"
+)
+
+func newConn(t *testing.T) *pgx.Conn {
+	t.Helper()
+
+	url := os.Getenv("POSTGRES_CONNECTION_STRING")
+	if url == "" {
+		container, err := tcpostgres.RunContainer(
+			context.Background(),
+			testcontainers.WithImage("docker.io/postgres:16"),
+			tcpostgres.WithDatabase("db_test"),
+			tcpostgres.WithUsername("user"),
+			tcpostgres.WithPassword("passw0rd!"),
+			testcontainers.WithWaitStrategy(
+				wait.ForLog("database system is ready to accept connections").
+					WithOccurrence(2).
+					WithStartupTimeout(30*time.Second)),
+		)
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, container.Terminate(context.Background()))
+		})
+
+		url, err = container.ConnectionString(context.Background(), "sslmode=disable")
+		require.NoError(t, err)
+	}
+
+	conn, err := pgx.Connect(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close(context.Background()))
+	})
+	return conn
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := newConn(t)
+
+	_, err := postgresql.New(ctx, conn, "")
+	require.ErrorIs(t, err, postgresql.ErrInvalidSessionID)
+
+	h, err := postgresql.New(ctx, conn, "session-1", postgresql.WithUserID("ann"))
+	require.NoError(t, err)
+	require.NoError(t, h.Clear(ctx))
+
+	toolCalls := []llms.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}
+	require.NoError(t, h.AddMessage(ctx, llms.SystemChatMessage{Content: "be brief"}))
+	require.NoError(t, h.AddUserMessage(ctx, "weather in Paris?"))
+	require.NoError(t, h.AddMessage(ctx, llms.AIChatMessage{ToolCalls: toolCalls}))
+	require.NoError(t, h.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "sunny"}))
+	require.NoError(t, h.AddMessageWithMetadata(ctx, llms.AIChatMessage{Content: "It's sunny."},
+		map[string]any{"model": "gpt-4o"}))
+
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "be brief"},
+		llms.HumanChatMessage{Content: "weather in Paris?"},
+		llms.AIChatMessage{ToolCalls: toolCalls},
+		llms.ToolChatMessage{ID: "call_1", Content: "sunny"},
+		llms.AIChatMessage{Content: "It's sunny."},
+	}, messages)
+
+	records, err := h.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, "ann", records[4].UserID)
+	assert.Equal(t, map[string]any{"model": "gpt-4o"}, records[4].Metadata)
+	assert.False(t, records[4].CreatedAt.IsZero())
+
+	other, err := postgresql.New(ctx, conn, "session-2", postgresql.WithUserID("ann"))
+	require.NoError(t, err)
+	require.NoError(t, other.SetMessages(ctx, []llms.ChatMessage{llms.HumanChatMessage{Content: "hi"}}))
+	sessions, err := h.Sessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"session-2", "session-1"}, sessions)
+
+	require.NoError(t, h.Clear(ctx))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
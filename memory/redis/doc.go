@@ -0,0 +1,7 @@
+// Package redis provides a chat message history backed by Redis, for
+// stateless backends sharing conversations across replicas.
+//
+// The messages of each session are stored in a Redis list, whose key is the
+// session ID with a prefix. The list can expire after a period of
+// inactivity, and be trimmed to its most recent messages.
+package redis
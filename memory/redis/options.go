@@ -0,0 +1,33 @@
+package redis
+
+import "time"
+
+// DefaultPrefix is the default prefix of the keys storing the messages.
+const DefaultPrefix = "langchaingo:chat_history:"
+
+// Option is a function that configures a ChatMessageHistory.
+type Option func(*ChatMessageHistory)
+
+// WithPrefix sets the prefix of the keys storing the messages of the
+// sessions.
+func WithPrefix(prefix string) Option {
+	return func(h *ChatMessageHistory) {
+		h.prefix = prefix
+	}
+}
+
+// WithTTL sets the time-to-live of the messages of the session, renewed
+// each time a message is added. By default they don't expire.
+func WithTTL(ttl time.Duration) Option {
+	return func(h *ChatMessageHistory) {
+		h.ttl = ttl
+	}
+}
+
+// WithMaxMessages sets the maximum number of messages kept, the oldest ones
+// being removed when messages are added. By default all messages are kept.
+func WithMaxMessages(n int) Option {
+	return func(h *ChatMessageHistory) {
+		h.maxMessages = n
+	}
+}
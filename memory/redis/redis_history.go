@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidSessionID is returned by New when the session ID is empty.
+var ErrInvalidSessionID = errors.New("invalid redis session id")
+
+// ChatMessageHistory is a schema.ChatMessageHistory storing the messages of
+// a session in a Redis list.
+type ChatMessageHistory struct {
+	client      rueidis.Client
+	sessionID   string
+	prefix      string
+	ttl         time.Duration
+	maxMessages int
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// New returns a ChatMessageHistory storing the messages of the session using
+// client.
+func New(client rueidis.Client, sessionID string, opts ...Option) (*ChatMessageHistory, error) {
+	if sessionID == "" {
+		return nil, ErrInvalidSessionID
+	}
+	h := &ChatMessageHistory{client: client, sessionID: sessionID, prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
+}
+
+// key returns the key of the list of the messages of the session.
+func (h *ChatMessageHistory) key() string {
+	return h.prefix + h.sessionID
+}
+
+// Messages returns all messages stored.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	values, err := h.client.Do(ctx, h.client.B().Lrange().Key(h.key()).Start(0).Stop(-1).Build()).AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]llms.ChatMessage, 0, len(values))
+	for _, value := range values {
+		var m llms.ChatMessageModel
+		if err := json.Unmarshal([]byte(value), &m); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m.ToChatMessage())
+	}
+	return messages, nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the store, trimming the oldest messages and
+// renewing the time-to-live of the session if set.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	return h.write(ctx, false, []llms.ChatMessage{message})
+}
+
+// SetMessages replaces existing messages in the store.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	return h.write(ctx, true, messages)
+}
+
+// Clear removes the messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	return h.client.Do(ctx, h.client.B().Del().Key(h.key()).Build()).Error()
+}
+
+// write appends the messages to the list of the session, replacing its
+// messages if replace is true, in a transaction.
+func (h *ChatMessageHistory) write(ctx context.Context, replace bool, messages []llms.ChatMessage) error {
+	values := make([]string, 0, len(messages))
+	for _, message := range messages {
+		value, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+		if err != nil {
+			return err
+		}
+		values = append(values, string(value))
+	}
+
+	cmds := rueidis.Commands{h.client.B().Multi().Build()}
+	if replace {
+		cmds = append(cmds, h.client.B().Del().Key(h.key()).Build())
+	}
+	if len(values) > 0 {
+		cmds = append(cmds, h.client.B().Rpush().Key(h.key()).Element(values...).Build())
+	}
+	if h.maxMessages > 0 {
+		cmds = append(cmds, h.client.B().Ltrim().Key(h.key()).Start(int64(-h.maxMessages)).Stop(-1).Build())
+	}
+	if h.ttl > 0 {
+		cmds = append(cmds, h.client.B().Pexpire().Key(h.key()).Milliseconds(h.ttl.Milliseconds()).Build())
+	}
+	cmds = append(cmds, h.client.B().Exec().Build())
+
+	var errs []error
+	for _, res := range h.client.DoMulti(ctx, cmds...) {
+		if err := res.Error(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/redis"
+)
+
+func newClient(t *testing.T) rueidis.Client {
+	t.Helper()
+
+	uri := os.Getenv("REDIS_URL")
+	if uri == "" {
+		ctx := context.Background()
+		container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        "docker.io/redis:7.2",
+				ExposedPorts: []string{"6379/tcp"},
+				WaitingFor:   wait.ForLog("* Ready to accept connections"),
+			},
+			Started: true,
+		})
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+
+		redisContainer := &tcredis.RedisContainer{Container: container}
+		t.Cleanup(func() {
+			require.NoError(t, redisContainer.Terminate(context.Background()))
+		})
+		uri, err = redisContainer.ConnectionString(ctx)
+		require.NoError(t, err)
+	}
+
+	options, err := rueidis.ParseURL(uri)
+	require.NoError(t, err)
+	client, err := rueidis.NewClient(options)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t)
+
+	_, err := redis.New(client, "")
+	require.ErrorIs(t, err, redis.ErrInvalidSessionID)
+
+	h, err := redis.New(client, "session-1", redis.WithMaxMessages(3), redis.WithTTL(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, h.Clear(ctx))
+
+	require.NoError(t, h.AddUserMessage(ctx, "hi"))
+	require.NoError(t, h.AddAIMessage(ctx, "hello"))
+	require.NoError(t, h.AddUserMessage(ctx, "how are you?"))
+	require.NoError(t, h.AddAIMessage(ctx, "fine"))
+
+	// The oldest message is trimmed.
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.AIChatMessage{Content: "hello"},
+		llms.HumanChatMessage{Content: "how are you?"},
+		llms.AIChatMessage{Content: "fine"},
+	}, messages)
+
+	ttl, err := client.Do(ctx, client.B().Pttl().Key(redis.DefaultPrefix+"session-1").Build()).AsInt64()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, int64(0))
+
+	// Another replica sees the same session, but not the other sessions.
+	other, err := redis.New(client, "session-1")
+	require.NoError(t, err)
+	require.NoError(t, other.SetMessages(ctx, []llms.ChatMessage{llms.HumanChatMessage{Content: "be brief"}}))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.HumanChatMessage{Content: "be brief"}}, messages)
+
+	session2, err := redis.New(client, "session-2")
+	require.NoError(t, err)
+	messages, err = session2.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	require.NoError(t, h.Clear(ctx))
+	messages, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
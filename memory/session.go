@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrSessionNotFound is returned when a session doesn't exist, expired or
+	// belongs to another user.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionExists is returned when creating a session whose id is taken.
+	ErrSessionExists = errors.New("session already exists")
+)
+
+// MemoryFactory creates the memory of a session, e.g. a ConversationBuffer
+// with a chat history persisted under the session id.
+type MemoryFactory func(ctx context.Context, sessionID string) (schema.Memory, error)
+
+// Session is a conversation of a user, with its own memory.
+type Session struct {
+	ID     string
+	UserID string
+	Memory schema.Memory
+	// CreatedAt is the time the session was created.
+	CreatedAt time.Time
+	// LastAccessedAt is the time the session was last created or got.
+	LastAccessedAt time.Time
+}
+
+// SessionStore manages the sessions of multiple users, each with its own
+// memory, so that a chat service can scope any memory implementation by
+// session. A session is only visible to the user that created it.
+type SessionStore interface {
+	// Create creates a session of the user. If the session id is empty a
+	// random id is generated.
+	Create(ctx context.Context, sessionID, userID string) (Session, error)
+	// Get returns the session of the user, renewing its expiry.
+	Get(ctx context.Context, sessionID, userID string) (Session, error)
+	// GetOrCreate returns the session of the user, creating it if it doesn't
+	// exist. If the session id is empty a session with a random id is created.
+	GetOrCreate(ctx context.Context, sessionID, userID string) (Session, error)
+	// List returns the sessions of the user, or all sessions if the user id
+	// is empty, ordered by creation time.
+	List(ctx context.Context, userID string) ([]Session, error)
+	// Delete clears the memory of the session of the user and deletes it.
+	Delete(ctx context.Context, sessionID, userID string) error
+	// PurgeExpired clears the memory of the expired sessions and deletes
+	// them. It returns the number of sessions deleted.
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+// InMemorySessionStore is a SessionStore keeping the sessions in memory, each
+// with a memory created by a MemoryFactory. Sessions idle for longer than the
+// TTL, if set, expire: their memory is cleared and they are deleted.
+//
+// The lock of the store is never held while the memories are created or
+// cleared, so that a slow memory backend only delays the session using it.
+type InMemorySessionStore struct {
+	newMemory MemoryFactory
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*sessionEntry
+}
+
+// Statically assert that InMemorySessionStore implement the session store interface.
+var _ SessionStore = &InMemorySessionStore{}
+
+// sessionEntry is a session of the store. Its memory is created after the
+// entry is added to the store, which reserves the session id meanwhile.
+type sessionEntry struct {
+	session Session
+	// ready is closed once the memory is created, err being set if it failed.
+	ready chan struct{}
+	err   error
+}
+
+// SessionStoreOption is a function for creating new session store with other
+// than the default values.
+type SessionStoreOption func(s *InMemorySessionStore)
+
+// WithSessionTTL is an option for specifying the time after which an idle
+// session expires. By default sessions don't expire.
+func WithSessionTTL(ttl time.Duration) SessionStoreOption {
+	return func(s *InMemorySessionStore) {
+		s.ttl = ttl
+	}
+}
+
+// NewInMemorySessionStore creates a new in-memory session store creating the
+// memory of the sessions with the factory.
+func NewInMemorySessionStore(newMemory MemoryFactory, options ...SessionStoreOption) *InMemorySessionStore {
+	s := &InMemorySessionStore{
+		newMemory: newMemory,
+		now:       time.Now,
+		sessions:  make(map[string]*sessionEntry),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Create creates a session of the user. If the session id is empty a random
+// id is generated.
+func (s *InMemorySessionStore) Create(ctx context.Context, sessionID, userID string) (Session, error) {
+	if sessionID == "" {
+		var err error
+		if sessionID, err = newSessionID(); err != nil {
+			return Session{}, err
+		}
+	}
+
+	s.mu.Lock()
+	entry, expired := s.take(sessionID)
+	if entry != nil {
+		s.mu.Unlock()
+		return Session{}, fmt.Errorf("%w: %s", ErrSessionExists, sessionID)
+	}
+	entry = s.reserve(sessionID, userID)
+	s.mu.Unlock()
+
+	return s.create(ctx, entry, expired)
+}
+
+// Get returns the session of the user, renewing its expiry.
+func (s *InMemorySessionStore) Get(ctx context.Context, sessionID, userID string) (Session, error) {
+	s.mu.Lock()
+	entry, expired := s.take(sessionID)
+	s.mu.Unlock()
+
+	if expired != nil {
+		if err := s.clear(ctx, expired); err != nil {
+			return Session{}, err
+		}
+	}
+	if entry == nil || entry.session.UserID != userID {
+		return Session{}, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	return s.touch(ctx, entry)
+}
+
+// GetOrCreate returns the session of the user, creating it if it doesn't
+// exist. If the session id is empty a session with a random id is created. It
+// returns ErrSessionExists if the session belongs to another user.
+func (s *InMemorySessionStore) GetOrCreate(ctx context.Context, sessionID, userID string) (Session, error) {
+	if sessionID == "" {
+		return s.Create(ctx, sessionID, userID)
+	}
+
+	s.mu.Lock()
+	entry, expired := s.take(sessionID)
+	if entry == nil {
+		entry = s.reserve(sessionID, userID)
+		s.mu.Unlock()
+		return s.create(ctx, entry, expired)
+	}
+	s.mu.Unlock()
+
+	if entry.session.UserID != userID {
+		return Session{}, fmt.Errorf("%w: %s", ErrSessionExists, sessionID)
+	}
+	return s.touch(ctx, entry)
+}
+
+// List returns the sessions of the user, or all sessions if the user id is
+// empty, ordered by creation time. Expired sessions are purged first.
+func (s *InMemorySessionStore) List(ctx context.Context, userID string) ([]Session, error) {
+	if _, err := s.PurgeExpired(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	sessions := make([]Session, 0, len(s.sessions))
+	for _, entry := range s.sessions {
+		if entry.created() && (userID == "" || entry.session.UserID == userID) {
+			sessions = append(sessions, entry.session)
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(sessions, func(a, b Session) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sessions, nil
+}
+
+// Delete clears the memory of the session of the user and deletes it. If
+// clearing the memory fails the session is kept.
+func (s *InMemorySessionStore) Delete(ctx context.Context, sessionID, userID string) error {
+	s.mu.Lock()
+	entry, expired := s.take(sessionID)
+	if entry != nil && entry.session.UserID == userID && entry.created() {
+		delete(s.sessions, sessionID)
+	} else {
+		entry = nil
+	}
+	s.mu.Unlock()
+
+	if expired != nil {
+		if err := s.clear(ctx, expired); err != nil {
+			return err
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	if err := s.clear(ctx, entry); err != nil {
+		s.mu.Lock()
+		if _, ok := s.sessions[sessionID]; !ok {
+			s.sessions[sessionID] = entry
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// PurgeExpired clears the memory of the expired sessions and deletes them. It
+// returns the number of sessions whose memory was cleared; expired sessions
+// are deleted even if clearing their memory fails.
+func (s *InMemorySessionStore) PurgeExpired(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	var expired []*sessionEntry
+	for id, entry := range s.sessions {
+		if s.expired(entry) {
+			expired = append(expired, entry)
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, entry := range expired {
+		if err := s.clear(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(expired) - len(errs), errors.Join(errs...)
+}
+
+// take returns the entry of the session, or nil if there is none. An expired
+// entry is removed from the store and returned separately, for its memory to
+// be cleared once the lock is released. It must be called with the lock held.
+func (s *InMemorySessionStore) take(sessionID string) (entry, expired *sessionEntry) {
+	entry, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	if s.expired(entry) {
+		delete(s.sessions, sessionID)
+		return nil, entry
+	}
+	return entry, nil
+}
+
+// reserve adds an entry for a session whose memory is yet to be created. It
+// must be called with the lock held.
+func (s *InMemorySessionStore) reserve(sessionID, userID string) *sessionEntry {
+	now := s.now()
+	entry := &sessionEntry{
+		session: Session{
+			ID:             sessionID,
+			UserID:         userID,
+			CreatedAt:      now,
+			LastAccessedAt: now,
+		},
+		ready: make(chan struct{}),
+	}
+	s.sessions[sessionID] = entry
+	return entry
+}
+
+// create clears the memory of the expired session the entry replaces, if any,
+// and creates the memory of the entry. The entry is removed from the store if
+// either fails.
+func (s *InMemorySessionStore) create(ctx context.Context, entry, expired *sessionEntry) (Session, error) {
+	defer close(entry.ready)
+
+	var memory schema.Memory
+	err := func() error {
+		if expired != nil {
+			if err := s.clear(ctx, expired); err != nil {
+				return err
+			}
+		}
+		var err error
+		if memory, err = s.newMemory(ctx, entry.session.ID); err != nil {
+			return fmt.Errorf("creating memory of session %s: %w", entry.session.ID, err)
+		}
+		return nil
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		entry.err = err
+		if s.sessions[entry.session.ID] == entry {
+			delete(s.sessions, entry.session.ID)
+		}
+		return Session{}, err
+	}
+	entry.session.Memory = memory
+	return entry.session, nil
+}
+
+// touch waits for the memory of the entry to be created and renews its
+// expiry.
+func (s *InMemorySessionStore) touch(ctx context.Context, entry *sessionEntry) (Session, error) {
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return Session{}, ctx.Err()
+	}
+	if entry.err != nil {
+		return Session{}, fmt.Errorf("%w: %s", ErrSessionNotFound, entry.session.ID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.session.LastAccessedAt = s.now()
+	return entry.session, nil
+}
+
+// clear clears the memory of the session of the entry.
+func (s *InMemorySessionStore) clear(ctx context.Context, entry *sessionEntry) error {
+	if err := entry.session.Memory.Clear(ctx); err != nil {
+		return fmt.Errorf("clearing memory of session %s: %w", entry.session.ID, err)
+	}
+	return nil
+}
+
+// expired reports whether the session of the entry expired. Sessions whose
+// memory is being created don't expire. It must be called with the lock held.
+func (s *InMemorySessionStore) expired(entry *sessionEntry) bool {
+	return entry.created() && s.ttl > 0 && s.now().Sub(entry.session.LastAccessedAt) > s.ttl
+}
+
+// created reports whether the memory of the entry was created successfully.
+func (e *sessionEntry) created() bool {
+	select {
+	case <-e.ready:
+		return e.err == nil
+	default:
+		return false
+	}
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16) //nolint:mnd
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
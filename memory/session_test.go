@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestInMemorySessionStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	histories := map[string]*ChatMessageHistory{}
+	store := NewInMemorySessionStore(func(_ context.Context, sessionID string) (schema.Memory, error) {
+		histories[sessionID] = NewChatMessageHistory()
+		return NewConversationBuffer(WithChatHistory(histories[sessionID])), nil
+	}, WithSessionTTL(time.Hour))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	alice, err := store.Create(ctx, "s1", "alice")
+	require.NoError(t, err)
+	_, err = store.Create(ctx, "s1", "bob")
+	require.ErrorIs(t, err, ErrSessionExists)
+
+	require.NoError(t, alice.Memory.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+	session, err := store.Get(ctx, "s1", "alice")
+	require.NoError(t, err)
+	result, err := session.Memory.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: hi\nAI: hello"}, result)
+
+	// Sessions are only visible to their user.
+	_, err = store.Get(ctx, "s1", "bob")
+	require.ErrorIs(t, err, ErrSessionNotFound)
+	_, err = store.GetOrCreate(ctx, "s1", "bob")
+	require.ErrorIs(t, err, ErrSessionExists)
+	require.ErrorIs(t, store.Delete(ctx, "s1", "bob"), ErrSessionNotFound)
+
+	now = now.Add(time.Minute)
+	bob, err := store.GetOrCreate(ctx, "", "bob")
+	require.NoError(t, err)
+	assert.Len(t, bob.ID, 32)
+	_, err = store.GetOrCreate(ctx, "s2", "alice")
+	require.NoError(t, err)
+
+	sessions, err := store.List(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "s1", sessions[0].ID)
+	assert.Equal(t, "s2", sessions[1].ID)
+	sessions, err = store.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 3)
+
+	// Getting a session renews its expiry.
+	now = now.Add(50 * time.Minute)
+	_, err = store.Get(ctx, "s1", "alice")
+	require.NoError(t, err)
+	now = now.Add(50 * time.Minute)
+	purged, err := store.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	_, err = store.Get(ctx, "s2", "alice")
+	require.ErrorIs(t, err, ErrSessionNotFound)
+
+	require.NoError(t, store.Delete(ctx, "s1", "alice"))
+	messages, err := histories["s1"].Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{}, messages)
+	require.ErrorIs(t, store.Delete(ctx, "s1", "alice"), ErrSessionNotFound)
+}
+
+func TestInMemorySessionStoreSlowMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	created := make(chan struct{})
+	unblock := make(chan struct{})
+	store := NewInMemorySessionStore(func(_ context.Context, sessionID string) (schema.Memory, error) {
+		if sessionID == "slow" {
+			close(created)
+			<-unblock
+		}
+		return NewConversationBuffer(), nil
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := store.Create(ctx, "slow", "alice")
+		done <- err
+	}()
+	<-created
+
+	// The memory of other sessions can be created while a backend is slow.
+	_, err := store.Create(ctx, "fast", "bob")
+	require.NoError(t, err)
+	_, err = store.Create(ctx, "slow", "bob")
+	require.ErrorIs(t, err, ErrSessionExists)
+	sessions, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = store.Get(waitCtx, "slow", "alice")
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(unblock)
+	require.NoError(t, <-done)
+	session, err := store.Get(ctx, "slow", "alice")
+	require.NoError(t, err)
+	assert.NotNil(t, session.Memory)
+}
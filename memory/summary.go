@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _defaultSummaryTemplate = `Progressively summarize the lines of conversation provided, adding onto the previous summary returning a new summary.
+
+EXAMPLE
+Current summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good.
+
+New lines of conversation:
+Human: Why do you think artificial intelligence is a force for good?
+AI: Because artificial intelligence will help humans reach their full potential.
+
+New summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good because it will help humans reach their full potential.
+END OF EXAMPLE
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// DefaultSummaryPrompt is the prompt used by ConversationSummary to update
+// the summary, with the current summary in the "summary" variable and the
+// lines of conversation to add in the "new_lines" variable.
+func DefaultSummaryPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultSummaryTemplate, []string{"summary", "new_lines"})
+}
+
+// ConversationSummary is a memory keeping a summary of the conversation,
+// updated by the LLM after each turn, instead of the messages. The summary is
+// stored in the chat history as a system message, so that it is kept by
+// persistent chat histories.
+type ConversationSummary struct {
+	ConversationBuffer
+	// LLM is the model generating the summary.
+	LLM llms.Model
+	// Prompt is the prompt asking the LLM for the new summary, with the
+	// "summary" and "new_lines" variables.
+	Prompt prompts.FormatPrompter
+	// CallOptions are the options of the calls to the LLM.
+	CallOptions []llms.CallOption
+}
+
+// Statically assert that ConversationSummary implement the memory interface.
+var _ schema.Memory = &ConversationSummary{}
+
+// NewConversationSummary is a function for creating a new summary memory,
+// using the default summary prompt.
+func NewConversationSummary(llm llms.Model, options ...ConversationBufferOption) *ConversationSummary {
+	return &ConversationSummary{
+		LLM:                llm,
+		Prompt:             DefaultSummaryPrompt(),
+		ConversationBuffer: *applyBufferOptions(options...),
+	}
+}
+
+// MemoryVariables uses ConversationBuffer method for memory variables.
+func (s *ConversationSummary) MemoryVariables(ctx context.Context) []string {
+	return s.ConversationBuffer.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables returns the summary of the conversation in the memory
+// key. If ReturnMessages is set to true, the summary is returned as a slice
+// holding a system message.
+func (s *ConversationSummary) LoadMemoryVariables(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	messages, err := s.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.ReturnMessages {
+		return map[string]any{s.MemoryKey: messages}, nil
+	}
+
+	summary, newMessages := splitSummary(messages)
+	if len(newMessages) == 0 {
+		return map[string]any{s.MemoryKey: summary}, nil
+	}
+	bufferString, err := llms.GetBufferString(messages, s.HumanPrefix, s.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{s.MemoryKey: bufferString}, nil
+}
+
+// SaveContext asks the LLM to add the turn to the summary, with the messages
+// of the chat history not summarized yet, and replaces the messages of the
+// chat history with the new summary.
+func (s *ConversationSummary) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	userInputValue, err := getInputValue(inputValues, s.InputKey)
+	if err != nil {
+		return err
+	}
+	aiOutputValue, err := getInputValue(outputValues, s.OutputKey)
+	if err != nil {
+		return err
+	}
+
+	messages, err := s.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+	summary, newMessages := splitSummary(messages)
+	newMessages = append(slices.Clone(newMessages),
+		llms.HumanChatMessage{Content: userInputValue},
+		llms.AIChatMessage{Content: aiOutputValue},
+	)
+
+	summary, err = s.summarize(ctx, summary, newMessages)
+	if err != nil {
+		return err
+	}
+	return s.ChatHistory.SetMessages(ctx, []llms.ChatMessage{llms.SystemChatMessage{Content: summary}})
+}
+
+// Clear uses ConversationBuffer method for clearing buffer memory.
+func (s *ConversationSummary) Clear(ctx context.Context) error {
+	return s.ConversationBuffer.Clear(ctx)
+}
+
+// summarize returns the summary updated with the messages.
+func (s *ConversationSummary) summarize(
+	ctx context.Context, summary string, messages []llms.ChatMessage,
+) (string, error) {
+	newLines, err := llms.GetBufferString(messages, s.HumanPrefix, s.AIPrefix)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := s.Prompt.FormatPrompt(map[string]any{
+		"summary":   summary,
+		"new_lines": newLines,
+	})
+	if err != nil {
+		return "", err
+	}
+	result, err := llms.GenerateFromSinglePrompt(ctx, s.LLM, prompt.String(), s.CallOptions...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result), nil
+}
+
+// splitSummary returns the summary stored as the first message of the chat
+// history, if any, and the messages not summarized yet.
+func splitSummary(messages []llms.ChatMessage) (string, []llms.ChatMessage) {
+	if len(messages) > 0 {
+		if summary, ok := messages[0].(llms.SystemChatMessage); ok {
+			return summary.Content, messages[1:]
+		}
+	}
+	return "", messages
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// defaultSummaryBufferTokenLimit is the default number of tokens of the
+// messages kept verbatim by ConversationSummaryBuffer.
+const defaultSummaryBufferTokenLimit = 2000
+
+// ConversationSummaryBuffer is a memory keeping the recent messages of the
+// conversation verbatim, up to a number of tokens, and a summary of the older
+// messages, updated by the LLM as messages are pruned from the buffer. The
+// summary is stored in the chat history as a system message before the recent
+// messages.
+type ConversationSummaryBuffer struct {
+	ConversationSummary
+	// MaxTokenLimit is the maximum number of tokens of the messages kept
+	// verbatim. Defaults to 2000.
+	MaxTokenLimit int
+	// TokenCounter counts the tokens of the messages. Defaults to the token
+	// counting of the LLM.
+	TokenCounter llms.TokenCounter
+}
+
+// Statically assert that ConversationSummaryBuffer implement the memory interface.
+var _ schema.Memory = &ConversationSummaryBuffer{}
+
+// NewConversationSummaryBuffer is a function for creating a new summary
+// buffer memory, using the default summary prompt.
+func NewConversationSummaryBuffer(
+	llm llms.Model,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationSummaryBuffer {
+	if maxTokenLimit <= 0 {
+		maxTokenLimit = defaultSummaryBufferTokenLimit
+	}
+	return &ConversationSummaryBuffer{
+		ConversationSummary: *NewConversationSummary(llm, options...),
+		MaxTokenLimit:       maxTokenLimit,
+	}
+}
+
+// MemoryVariables uses ConversationBuffer method for memory variables.
+func (sb *ConversationSummaryBuffer) MemoryVariables(ctx context.Context) []string {
+	return sb.ConversationBuffer.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables uses ConversationBuffer method for loading memory
+// variables: the summary, if any, is the first message, followed by the
+// recent messages.
+func (sb *ConversationSummaryBuffer) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	return sb.ConversationBuffer.LoadMemoryVariables(ctx, inputs)
+}
+
+// SaveContext uses ConversationBuffer method for saving context, then folds
+// the oldest messages into the summary while the recent messages exceed the
+// token limit.
+func (sb *ConversationSummaryBuffer) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	err := sb.ConversationBuffer.SaveContext(ctx, inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+	messages, err := sb.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary, recent := splitSummary(messages)
+	pruned := 0
+	for pruned < len(recent) {
+		numTokens, err := sb.countTokens(ctx, recent[pruned:])
+		if err != nil {
+			return err
+		}
+		if numTokens <= sb.MaxTokenLimit {
+			break
+		}
+		pruned++
+	}
+	if pruned == 0 {
+		return nil
+	}
+
+	summary, err = sb.summarize(ctx, summary, recent[:pruned])
+	if err != nil {
+		return err
+	}
+	kept := make([]llms.ChatMessage, 0, len(recent)-pruned+1)
+	kept = append(kept, llms.SystemChatMessage{Content: summary})
+	kept = append(kept, recent[pruned:]...)
+	return sb.ChatHistory.SetMessages(ctx, kept)
+}
+
+// Clear uses ConversationBuffer method for clearing buffer memory.
+func (sb *ConversationSummaryBuffer) Clear(ctx context.Context) error {
+	return sb.ConversationBuffer.Clear(ctx)
+}
+
+func (sb *ConversationSummaryBuffer) countTokens(ctx context.Context, messages []llms.ChatMessage) (int, error) {
+	return countChatMessageTokens(ctx, sb.TokenCounter, sb.LLM, messages)
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(
+		fake.Response{Content: "summary 1"},
+		fake.Response{Content: "summary 2"},
+	)
+	m := NewConversationSummaryBuffer(llm, 9)
+	m.TokenCounter = llms.ApproximateTokenCounter{CharsPerToken: 1}
+	m.Prompt = prompts.NewPromptTemplate("{{.summary}}|{{.new_lines}}", []string{"summary", "new_lines"})
+
+	// The messages fit in the token limit.
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "aa"}, map[string]any{"output": "bb"}))
+	require.Empty(t, llm.Calls())
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: aa\nAI: bb"}, result)
+
+	// The oldest messages are summarized until the others fit.
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "cccc"}, map[string]any{"output": "dddd"}))
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Equal(t, "|Human: aa\nAI: bb", prompt)
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "system: summary 1\nHuman: cccc\nAI: dddd"}, result)
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "eeee"}, map[string]any{"output": "ffff"}))
+	prompt = llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Equal(t, "summary 1|Human: cccc\nAI: dddd", prompt)
+
+	m.ReturnMessages = true
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "summary 2"},
+		llms.HumanChatMessage{Content: "eeee"},
+		llms.AIChatMessage{Content: "ffff"},
+	}}, result)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestConversationSummary(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(
+		fake.Response{Content: "The human greets the AI.\n"},
+		fake.Response{Content: "The human greets the AI and asks for its name, which is Bob."},
+	)
+	m := NewConversationSummary(llm)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "The human greets the AI."}, result)
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, prompt, "Current summary:\n\n\nNew lines of conversation:\nHuman: hi\nAI: hello\n\nNew summary:")
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "name?"}, map[string]any{"output": "Bob"}))
+	prompt = llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, prompt, "Current summary:\nThe human greets the AI.\n\n"+
+		"New lines of conversation:\nHuman: name?\nAI: Bob\n")
+
+	m.ReturnMessages = true
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "The human greets the AI and asks for its name, which is Bob."},
+	}}, result)
+
+	require.NoError(t, m.Clear(ctx))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{}}, result)
+}
+
+func TestConversationSummaryWithPreLoadedHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.New(fake.Response{Content: "Short."})
+	m := NewConversationSummary(llm, WithChatHistory(NewChatMessageHistory(
+		WithPreviousMessages([]llms.ChatMessage{
+			llms.HumanChatMessage{Content: "foo"},
+			llms.AIChatMessage{Content: "bar"},
+		}),
+	)))
+	m.Prompt = prompts.NewPromptTemplate("{{.summary}}|{{.new_lines}}", []string{"summary", "new_lines"})
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: foo\nAI: bar"}, result)
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "baz"}, map[string]any{"output": "qux"}))
+	prompt := llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Equal(t, "|Human: foo\nAI: bar\nHuman: baz\nAI: qux", prompt)
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Short."}, result)
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"errors"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
@@ -10,8 +11,14 @@ import (
 // ConversationTokenBuffer for storing conversation memory.
 type ConversationTokenBuffer struct {
 	ConversationBuffer
-	LLM           llms.Model
+	LLM llms.Model
+	// MaxTokenLimit is the maximum number of tokens kept in the buffer. If it
+	// is not positive, three quarters of the context window of the LLM are
+	// used, leaving room for the prompt and the answer.
 	MaxTokenLimit int
+	// TokenCounter counts the tokens of the messages. Defaults to the token
+	// counting of the LLM.
+	TokenCounter llms.TokenCounter
 }
 
 // Statically assert that ConversationTokenBuffer implement the memory interface.
@@ -45,6 +52,9 @@ func (tb *ConversationTokenBuffer) LoadMemoryVariables(
 }
 
 // SaveContext uses ConversationBuffer method for saving context and prunes memory buffer if needed.
+// The oldest messages are removed first, except leading system messages which
+// are kept. An AI message calling tools is removed along with the responses
+// of the tools, so that the buffer never holds a call without its response.
 func (tb *ConversationTokenBuffer) SaveContext(
 	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
 ) error {
@@ -52,37 +62,35 @@ func (tb *ConversationTokenBuffer) SaveContext(
 	if err != nil {
 		return err
 	}
-	currBufferLength, err := tb.getNumTokensFromMessages(ctx)
+	messages, err := tb.ChatHistory.Messages(ctx)
 	if err != nil {
 		return err
 	}
 
-	if currBufferLength > tb.MaxTokenLimit {
-		// while currBufferLength is greater than MaxTokenLimit we keep removing messages from the memory
-		// from the oldest
-		for currBufferLength > tb.MaxTokenLimit {
-			messages, err := tb.ChatHistory.Messages(ctx)
-			if err != nil {
-				return err
-			}
-
-			if len(messages) == 0 {
-				break
-			}
-
-			err = tb.ChatHistory.SetMessages(ctx, append(messages[:0], messages[1:]...))
-			if err != nil {
-				return err
-			}
-
-			currBufferLength, err = tb.getNumTokensFromMessages(ctx)
-			if err != nil {
-				return err
-			}
-		}
+	numSystem := 0
+	for numSystem < len(messages) && messages[numSystem].GetType() == llms.ChatMessageTypeSystem {
+		numSystem++
+	}
+	// Each message is counted once while dropping the oldest ones, which
+	// keeps the leading system messages and a suffix of the conversation.
+	truncated, err := llms.TruncateMessages(ctx, chatMessageContents(messages), tb.maxTokenLimit(), llms.DropOldest(),
+		llms.WithTruncationCounter(tokenCounter(tb.TokenCounter, tb.LLM)))
+	numKept := len(truncated)
+	if errors.Is(err, llms.ErrTruncationBudget) {
+		// Not even the last message fits.
+		numKept, err = numSystem, nil
+	}
+	if err != nil {
+		return err
+	}
+	if numKept == len(messages) {
+		return nil
 	}
 
-	return nil
+	kept := make([]llms.ChatMessage, 0, numKept)
+	kept = append(kept, messages[:numSystem]...)
+	kept = append(kept, messages[len(messages)-(numKept-numSystem):]...)
+	return tb.ChatHistory.SetMessages(ctx, kept)
 }
 
 // Clear uses ConversationBuffer method for clearing buffer memory.
@@ -90,20 +98,56 @@ func (tb *ConversationTokenBuffer) Clear(ctx context.Context) error {
 	return tb.ConversationBuffer.Clear(ctx)
 }
 
-func (tb *ConversationTokenBuffer) getNumTokensFromMessages(ctx context.Context) (int, error) {
-	messages, err := tb.ChatHistory.Messages(ctx)
-	if err != nil {
-		return 0, err
+func (tb *ConversationTokenBuffer) maxTokenLimit() int {
+	if tb.MaxTokenLimit > 0 {
+		return tb.MaxTokenLimit
 	}
+	return llms.MaxContextTokens(tb.LLM) * 3 / 4 //nolint:mnd
+}
 
-	bufferString, err := llms.GetBufferString(
-		messages,
-		tb.ConversationBuffer.HumanPrefix,
-		tb.ConversationBuffer.AIPrefix,
-	)
-	if err != nil {
-		return 0, err
+// countChatMessageTokens counts the tokens of the messages with the counter,
+// or with the token counting of the LLM if the counter is nil.
+func countChatMessageTokens(
+	ctx context.Context, counter llms.TokenCounter, llm llms.Model, messages []llms.ChatMessage,
+) (int, error) {
+	return tokenCounter(counter, llm).CountTokens(ctx, chatMessageContents(messages))
+}
+
+// tokenCounter returns the counter, or the token counting of the LLM if the
+// counter is nil.
+func tokenCounter(counter llms.TokenCounter, llm llms.Model) llms.TokenCounter {
+	if counter != nil {
+		return counter
 	}
+	if counter, ok := llm.(llms.TokenCounter); ok {
+		return counter
+	}
+	return llms.TiktokenCounter{}
+}
 
-	return llms.CountTokens("", bufferString), nil
+// chatMessageContents converts the messages to message contents, keeping the
+// tool calls and responses.
+func chatMessageContents(messages []llms.ChatMessage) []llms.MessageContent {
+	contents := make([]llms.MessageContent, 0, len(messages))
+	for _, message := range messages {
+		switch message := message.(type) {
+		case llms.AIChatMessage:
+			content := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+			if message.Content != "" {
+				content.Parts = append(content.Parts, llms.TextContent{Text: message.Content})
+			}
+			for _, toolCall := range message.ToolCalls {
+				content.Parts = append(content.Parts, toolCall)
+			}
+			contents = append(contents, content)
+		case llms.ToolChatMessage:
+			contents = append(contents, llms.MessageContent{
+				Role:  llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: message.ID, Content: message.Content}},
+			})
+		default:
+			contents = append(contents, llms.TextParts(message.GetType(), message.GetContent()))
+		}
+	}
+	return contents
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -94,3 +95,78 @@ func TestTokenBufferMemoryWithPreLoadedHistory(t *testing.T) {
 	expected := map[string]any{"history": "Human: bar\nAI: foo"}
 	assert.Equal(t, expected, result)
 }
+
+func TestTokenBufferMemoryPruning(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	toolCall := llms.ToolCall{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "f", Arguments: "{}"},
+	}
+	m := NewConversationTokenBuffer(fake.New(), 12, WithChatHistory(NewChatMessageHistory(
+		WithPreviousMessages([]llms.ChatMessage{
+			llms.SystemChatMessage{Content: "sys"},
+			llms.HumanChatMessage{Content: "aaaa"},
+			llms.AIChatMessage{ToolCalls: []llms.ToolCall{toolCall}},
+			llms.ToolChatMessage{ID: "call_1", Content: "bb"},
+			llms.AIChatMessage{Content: "cc"},
+		}),
+	)))
+	m.TokenCounter = llms.ApproximateTokenCounter{CharsPerToken: 1}
+
+	// sys (3), f {} (4), bb (2), cc (2), dd (2) and ee (2) exceed the limit,
+	// so the tool call is removed with its response.
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "dd"}, map[string]any{"output": "ee"}))
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "sys"},
+		llms.AIChatMessage{Content: "cc"},
+		llms.HumanChatMessage{Content: "dd"},
+		llms.AIChatMessage{Content: "ee"},
+	}, messages)
+
+	// System messages are kept even when over the limit.
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "ffffffff"}, map[string]any{"output": "gggggggggggg"}))
+	messages, err = m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.SystemChatMessage{Content: "sys"}}, messages)
+}
+
+// countingCounter counts the calls to the counter it wraps.
+type countingCounter struct {
+	llms.TokenCounter
+	calls int
+}
+
+func (c *countingCounter) CountTokens(ctx context.Context, messages []llms.MessageContent) (int, error) {
+	c.calls++
+	return c.TokenCounter.CountTokens(ctx, messages)
+}
+
+func TestTokenBufferMemoryPruningCountsOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var history []llms.ChatMessage
+	for i := 0; i < 20; i++ {
+		history = append(history, llms.HumanChatMessage{Content: "aaaa"}, llms.AIChatMessage{Content: "bbbb"})
+	}
+	m := NewConversationTokenBuffer(fake.New(), 8, WithChatHistory(NewChatMessageHistory(
+		WithPreviousMessages(history),
+	)))
+	counter := &countingCounter{TokenCounter: llms.ApproximateTokenCounter{CharsPerToken: 1}}
+	m.TokenCounter = counter
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "cccc"}, map[string]any{"output": "dddd"}))
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "cccc"},
+		llms.AIChatMessage{Content: "dddd"},
+	}, messages)
+	// The whole history, the system messages and each message are counted once.
+	assert.LessOrEqual(t, counter.calls, len(history)+4)
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrClearNotSupported is returned by VectorStoreMemory.Clear when the vector
+// store can't delete documents.
+var ErrClearNotSupported = errors.New("vector store does not support deleting documents")
+
+// VectorStoreMemory is a memory storing each turn of the conversation as a
+// document of a vector store, and returning the past turns most relevant to
+// the input instead of the most recent ones. It lets assistants recall what
+// was said long ago without keeping the whole conversation in the prompt.
+type VectorStoreMemory struct {
+	// VectorStore stores the turns.
+	VectorStore vectorstores.VectorStore
+	// VectorStoreOptions are the options of the calls to the vector store.
+	VectorStoreOptions []vectorstores.Option
+	// NumDocuments is the number of turns retrieved for an input.
+	NumDocuments int
+
+	ReturnDocuments bool
+	InputKey        string
+	OutputKey       string
+	HumanPrefix     string
+	AIPrefix        string
+	MemoryKey       string
+
+	mu sync.Mutex
+	// ids are the ids of the documents added, deleted by Clear.
+	ids []string
+}
+
+// Statically assert that VectorStoreMemory implement the memory interface.
+var _ schema.Memory = &VectorStoreMemory{}
+
+// NewVectorStoreMemory is a function for creating a new vector store memory.
+func NewVectorStoreMemory(store vectorstores.VectorStore, options ...VectorStoreMemoryOption) *VectorStoreMemory {
+	return applyVectorStoreMemoryOptions(store, options...)
+}
+
+// MemoryVariables gets the input key the vector store memory will load dynamically.
+func (m *VectorStoreMemory) MemoryVariables(context.Context) []string {
+	return []string{m.MemoryKey}
+}
+
+// LoadMemoryVariables returns the past turns most relevant to the input, most
+// relevant first. If ReturnDocuments is set to true the output is a slice of
+// schema.Document, otherwise the contents of the documents separated by
+// newlines.
+func (m *VectorStoreMemory) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	var docs []schema.Document
+	if len(inputs) > 0 {
+		query, err := getInputValue(inputs, m.InputKey)
+		if err != nil {
+			return nil, err
+		}
+		docs, err = m.VectorStore.SimilaritySearch(ctx, query, m.NumDocuments, m.VectorStoreOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.ReturnDocuments {
+		if docs == nil {
+			docs = []schema.Document{}
+		}
+		return map[string]any{m.MemoryKey: docs}, nil
+	}
+
+	contents := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		contents = append(contents, doc.PageContent)
+	}
+	return map[string]any{m.MemoryKey: strings.Join(contents, "\n")}, nil
+}
+
+// SaveContext adds the turn to the vector store as a document, with the input
+// and output in the metadata along with the time of the turn.
+func (m *VectorStoreMemory) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	input, err := getInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	output, err := getInputValue(outputValues, m.OutputKey)
+	if err != nil {
+		return err
+	}
+
+	doc := schema.Document{
+		PageContent: fmt.Sprintf("%s: %s\n%s: %s", m.HumanPrefix, input, m.AIPrefix, output),
+		Metadata: map[string]any{
+			"input":     input,
+			"output":    output,
+			"timestamp": time.Now().Unix(),
+		},
+	}
+	ids, err := m.VectorStore.AddDocuments(ctx, []schema.Document{doc}, m.VectorStoreOptions...)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.ids = append(m.ids, ids...)
+	m.mu.Unlock()
+	return nil
+}
+
+// Clear deletes the turns added by the memory from the vector store, if it
+// implements vectorstores.Deleter. Turns added by other instances, e.g.
+// before a restart, are kept.
+func (m *VectorStoreMemory) Clear(ctx context.Context) error {
+	deleter, ok := m.VectorStore.(vectorstores.Deleter)
+	if !ok {
+		return ErrClearNotSupported
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.ids) == 0 {
+		return nil
+	}
+	if err := deleter.DeleteByIDs(ctx, m.ids, m.VectorStoreOptions...); err != nil {
+		return err
+	}
+	m.ids = nil
+	return nil
+}
+
+func (m *VectorStoreMemory) GetMemoryKey(context.Context) string {
+	return m.MemoryKey
+}
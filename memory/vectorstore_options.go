@@ -0,0 +1,85 @@
+package memory
+
+import "github.com/tmc/langchaingo/vectorstores"
+
+// defaultVectorStoreNumDocuments is the default number of past turns
+// retrieved by VectorStoreMemory.
+const defaultVectorStoreNumDocuments = 4
+
+// VectorStoreMemoryOption is a function for creating new vector store memory
+// with other than the default values.
+type VectorStoreMemoryOption func(m *VectorStoreMemory)
+
+// WithNumDocuments is an option for specifying the number of past turns
+// retrieved for an input.
+func WithNumDocuments(numDocuments int) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.NumDocuments = numDocuments
+	}
+}
+
+// WithVectorStoreOptions is an option for specifying the options of the calls
+// to the vector store, e.g. its namespace or filters to keep the turns of
+// different conversations apart.
+func WithVectorStoreOptions(options ...vectorstores.Option) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.VectorStoreOptions = append(m.VectorStoreOptions, options...)
+	}
+}
+
+// WithReturnDocuments is an option for specifying that the retrieved turns
+// are returned as a slice of schema.Document instead of a string.
+func WithReturnDocuments(returnDocuments bool) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.ReturnDocuments = returnDocuments
+	}
+}
+
+// WithVectorStoreInputKey is an option for specifying the input key.
+func WithVectorStoreInputKey(inputKey string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.InputKey = inputKey
+	}
+}
+
+// WithVectorStoreOutputKey is an option for specifying the output key.
+func WithVectorStoreOutputKey(outputKey string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.OutputKey = outputKey
+	}
+}
+
+// WithVectorStoreMemoryKey is an option for specifying the memory key.
+func WithVectorStoreMemoryKey(memoryKey string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.MemoryKey = memoryKey
+	}
+}
+
+// WithVectorStorePrefixes is an option for specifying the human and AI
+// prefixes of the stored turns.
+func WithVectorStorePrefixes(humanPrefix, aiPrefix string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.HumanPrefix = humanPrefix
+		m.AIPrefix = aiPrefix
+	}
+}
+
+func applyVectorStoreMemoryOptions(
+	store vectorstores.VectorStore,
+	options ...VectorStoreMemoryOption,
+) *VectorStoreMemory {
+	m := &VectorStoreMemory{
+		VectorStore:  store,
+		NumDocuments: defaultVectorStoreNumDocuments,
+		HumanPrefix:  "Human",
+		AIPrefix:     "AI",
+		MemoryKey:    "history",
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
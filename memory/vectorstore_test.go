@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// keywordStore is a vector store ranking the documents by the number of words
+// of the query they contain.
+type keywordStore struct {
+	ids  []string
+	docs []schema.Document
+}
+
+func (s *keywordStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) { //nolint:lll
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, fmt.Sprint(len(s.docs)))
+		s.docs = append(s.docs, doc)
+	}
+	s.ids = append(s.ids, ids...)
+	return ids, nil
+}
+
+func (s *keywordStore) SimilaritySearch(_ context.Context, query string, numDocuments int, _ ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	type scored struct {
+		doc   schema.Document
+		score int
+	}
+	var results []scored
+	for i, doc := range s.docs {
+		if s.ids[i] == "" {
+			continue
+		}
+		score := 0
+		for _, word := range strings.Fields(strings.ToLower(query)) {
+			if strings.Contains(strings.ToLower(doc.PageContent), word) {
+				score++
+			}
+		}
+		if score > 0 {
+			results = append(results, scored{doc, score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b scored) int { return b.score - a.score })
+	docs := []schema.Document{}
+	for _, r := range results[:min(numDocuments, len(results))] {
+		docs = append(docs, r.doc)
+	}
+	return docs, nil
+}
+
+func (s *keywordStore) DeleteByIDs(_ context.Context, ids []string, _ ...vectorstores.Option) error {
+	for i := range s.ids {
+		if slices.Contains(ids, s.ids[i]) {
+			s.ids[i] = ""
+		}
+	}
+	return nil
+}
+
+func (s *keywordStore) DeleteByFilter(context.Context, any, ...vectorstores.Option) error {
+	return nil
+}
+
+func TestVectorStoreMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &keywordStore{}
+	m := NewVectorStoreMemory(store, WithNumDocuments(2))
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	turns := [][2]string{
+		{"my favorite color is blue", "noted"},
+		{"i have a dog named rex", "nice dog"},
+		{"what is the weather", "sunny"},
+	}
+	for _, turn := range turns {
+		require.NoError(t, m.SaveContext(ctx, map[string]any{"input": turn[0]}, map[string]any{"output": turn[1]}))
+	}
+	assert.Equal(t, "my favorite color is blue", store.docs[0].Metadata["input"])
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "favorite dog"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "Human: my favorite color is blue\nAI: noted\nHuman: i have a dog named rex\nAI: nice dog",
+	}, result)
+
+	m.ReturnDocuments = true
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "weather"})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{store.docs[2]}, result["history"])
+
+	require.NoError(t, m.Clear(ctx))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "weather"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []schema.Document{}}, result)
+}
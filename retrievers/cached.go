@@ -0,0 +1,233 @@
+package retrievers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _defaultMaxCachedQueries is the number of queries whose results are cached.
+const _defaultMaxCachedQueries = 1000
+
+// Cached is a retriever caching the documents of a base retriever by query,
+// and removing duplicate documents from them: documents with the same
+// content, ignoring whitespace, and, with an embedder, documents whose
+// contents are nearly identical. It cuts the latency of repeated questions
+// and the size of prompts built from overlapping chunks. It is safe for
+// concurrent use.
+type Cached struct {
+	CallbacksHandler callbacks.Handler
+	base             schema.Retriever
+	embedder         embeddings.Embedder
+	// TTL is how long the documents of a query are cached. If zero, they
+	// don't expire.
+	TTL time.Duration
+	// MaxQueries is the number of queries whose documents are cached, the
+	// oldest ones being evicted first. If zero, the number is unlimited.
+	MaxQueries int
+	// SimilarityThreshold is the cosine similarity of the embeddings of the
+	// contents from which documents are near duplicates.
+	SimilarityThreshold float32
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	keys    []string
+}
+
+var _ schema.Retriever = &Cached{}
+
+type cacheEntry struct {
+	docs    []schema.Document
+	expires time.Time
+}
+
+// CachedOption is a function that configures a Cached.
+type CachedOption func(*Cached)
+
+// WithCacheTTL is an option for setting how long the documents of a query
+// are cached.
+func WithCacheTTL(ttl time.Duration) CachedOption {
+	return func(r *Cached) {
+		r.TTL = ttl
+	}
+}
+
+// WithMaxCachedQueries is an option for setting the number of queries whose
+// documents are cached. Defaults to 1000.
+func WithMaxCachedQueries(maxQueries int) CachedOption {
+	return func(r *Cached) {
+		r.MaxQueries = maxQueries
+	}
+}
+
+// WithNearDuplicates is an option for removing the documents whose contents
+// have embeddings with a cosine similarity of at least threshold, e.g. 0.95,
+// with a more similar document to the query.
+func WithNearDuplicates(embedder embeddings.Embedder, threshold float32) CachedOption {
+	return func(r *Cached) {
+		r.embedder = embedder
+		r.SimilarityThreshold = threshold
+	}
+}
+
+// NewCached returns a retriever caching the deduplicated documents of the
+// base retriever.
+func NewCached(base schema.Retriever, options ...CachedOption) *Cached {
+	r := &Cached{
+		base:       base,
+		MaxQueries: _defaultMaxCachedQueries,
+		entries:    map[string]cacheEntry{},
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+	return r
+}
+
+// GetRelevantDocuments returns the cached documents of the query, or the
+// deduplicated documents of the base retriever, which are cached. Queries
+// differing only by case or whitespace share their documents.
+func (r *Cached) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	key := hashKey(strings.ToLower(query))
+	docs, ok := r.get(key)
+	if !ok {
+		var err error
+		docs, err = r.base.GetRelevantDocuments(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		docs, err = r.deduplicate(ctx, docs)
+		if err != nil {
+			return nil, err
+		}
+		r.put(key, docs)
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+// Clear removes all the cached documents, e.g. after documents were added
+// to the store searched.
+func (r *Cached) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = map[string]cacheEntry{}
+	r.keys = nil
+}
+
+func (r *Cached) get(key string) ([]schema.Document, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok || (!entry.expires.IsZero() && time.Now().After(entry.expires)) {
+		return nil, false
+	}
+	return slices.Clone(entry.docs), true
+}
+
+func (r *Cached) put(key string, docs []schema.Document) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = map[string]cacheEntry{}
+	}
+	entry := cacheEntry{docs: slices.Clone(docs)}
+	if r.TTL > 0 {
+		entry.expires = time.Now().Add(r.TTL)
+	}
+	if _, ok := r.entries[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.entries[key] = entry
+
+	// evict the expired entries, then the oldest ones.
+	now := time.Now()
+	r.keys = slices.DeleteFunc(r.keys, func(key string) bool {
+		expires := r.entries[key].expires
+		if !expires.IsZero() && now.After(expires) {
+			delete(r.entries, key)
+			return true
+		}
+		return false
+	})
+	for r.MaxQueries > 0 && len(r.keys) > r.MaxQueries {
+		delete(r.entries, r.keys[0])
+		r.keys = r.keys[1:]
+	}
+}
+
+// deduplicate removes the documents with the content of a previous document,
+// ignoring whitespace, and, with an embedder, the near duplicates of previous
+// documents.
+func (r *Cached) deduplicate(ctx context.Context, docs []schema.Document) ([]schema.Document, error) {
+	seen := make(map[string]bool, len(docs))
+	unique := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		key := hashKey(doc.PageContent)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, doc)
+	}
+	if r.embedder == nil || len(unique) < 2 {
+		return unique, nil
+	}
+
+	contents := make([]string, len(unique))
+	for i, doc := range unique {
+		contents[i] = doc.PageContent
+	}
+	vectors, err := r.embedder.EmbedDocuments(ctx, contents)
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]int, 0, len(unique))
+	for i := range unique {
+		if !slices.ContainsFunc(kept, func(j int) bool {
+			return cosineSimilarity(vectors[i], vectors[j]) >= r.SimilarityThreshold
+		}) {
+			kept = append(kept, i)
+		}
+	}
+	docs = make([]schema.Document, len(kept))
+	for i, j := range kept {
+		docs[i] = unique[j]
+	}
+	return docs, nil
+}
+
+// hashKey returns the hash of the text with its whitespace normalized.
+func hashKey(text string) string {
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(hash[:])
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(normA*normB))
+}
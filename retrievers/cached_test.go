@@ -0,0 +1,99 @@
+package retrievers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// countingRetriever returns its documents and counts its calls.
+type countingRetriever struct {
+	docs  []schema.Document
+	calls int
+}
+
+func (r *countingRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	r.calls++
+	return r.docs, nil
+}
+
+// letterEmbedder embeds texts by the counts of the letters a and b.
+type letterEmbedder struct{}
+
+func (letterEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(strings.Count(text, "a")), float32(strings.Count(text, "b"))}
+	}
+	return vectors, nil
+}
+
+func (e letterEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func TestCached(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := &countingRetriever{docs: []schema.Document{
+		{PageContent: "aab", Score: 0.9},
+		{PageContent: "aab ", Score: 0.8},
+		{PageContent: "aaaabb", Score: 0.7},
+		{PageContent: "bba", Score: 0.6},
+	}}
+	r := retrievers.NewCached(base, retrievers.WithCacheTTL(time.Second/2))
+
+	docs, err := r.GetRelevantDocuments(ctx, "what is a?")
+	require.NoError(t, err)
+	assert.Len(t, docs, 3)
+	docs, err = r.GetRelevantDocuments(ctx, "What  is A?")
+	require.NoError(t, err)
+	assert.Len(t, docs, 3)
+	assert.Equal(t, 1, base.calls)
+
+	_, err = r.GetRelevantDocuments(ctx, "what is b?")
+	require.NoError(t, err)
+	assert.Equal(t, 2, base.calls)
+
+	time.Sleep(time.Second)
+	_, err = r.GetRelevantDocuments(ctx, "what is a?")
+	require.NoError(t, err)
+	assert.Equal(t, 3, base.calls)
+
+	r.Clear()
+	_, err = r.GetRelevantDocuments(ctx, "what is a?")
+	require.NoError(t, err)
+	assert.Equal(t, 4, base.calls)
+}
+
+func TestCachedNearDuplicates(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := &countingRetriever{docs: []schema.Document{
+		{PageContent: "aab", Score: 0.9},
+		{PageContent: "aaaabb", Score: 0.7},
+		{PageContent: "bba", Score: 0.6},
+	}}
+	r := retrievers.NewCached(base, retrievers.WithNearDuplicates(letterEmbedder{}, 0.99),
+		retrievers.WithMaxCachedQueries(1))
+
+	docs, err := r.GetRelevantDocuments(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "aab", Score: 0.9}, {PageContent: "bba", Score: 0.6}}, docs)
+
+	_, err = r.GetRelevantDocuments(ctx, "b")
+	require.NoError(t, err)
+	_, err = r.GetRelevantDocuments(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, base.calls)
+}
@@ -0,0 +1,22 @@
+/*
+Package retrievers contains retrievers wrapping other retrievers, which
+implement the schema.Retriever interface.
+
+The main components of this package are:
+
+- Reranker: a retriever reordering the documents of a base retriever with a
+schema.Reranker, over-fetching documents from vector store retrievers.
+
+- ParentDocument: a retriever searching small child chunks in a vector store
+but returning their parent documents, or larger chunks, from a DocStore.
+
+- SelfQuery: a retriever translating questions with an LLM into a query and
+a vectorstores.Filter on the metadata attributes of the documents.
+
+- HyDE: a retriever searching with the embedding of a passage an LLM drafts
+to answer the question, instead of the question.
+
+- Cached: a retriever caching the documents of a base retriever by query,
+with a TTL, and removing duplicate and near-duplicate documents.
+*/
+package retrievers
@@ -0,0 +1,75 @@
+package retrievers
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// DocStore is a key-value store of documents, such as the parent documents
+// of a ParentDocument retriever.
+type DocStore interface {
+	// Set stores the documents with the ids, replacing the documents with
+	// the same ids.
+	Set(ctx context.Context, ids []string, docs []schema.Document) error
+	// Get returns the documents with the ids. The ids of missing documents
+	// are absent from the result.
+	Get(ctx context.Context, ids []string) (map[string]schema.Document, error)
+	// Delete deletes the documents with the ids.
+	Delete(ctx context.Context, ids []string) error
+}
+
+// InMemoryDocStore is a DocStore keeping the documents in memory. It is safe
+// for concurrent use.
+type InMemoryDocStore struct {
+	mu   sync.RWMutex
+	docs map[string]schema.Document
+}
+
+var _ DocStore = &InMemoryDocStore{}
+
+// NewInMemoryDocStore returns an empty InMemoryDocStore.
+func NewInMemoryDocStore() *InMemoryDocStore {
+	return &InMemoryDocStore{docs: map[string]schema.Document{}}
+}
+
+// Set stores the documents with the ids.
+func (s *InMemoryDocStore) Set(_ context.Context, ids []string, docs []schema.Document) error {
+	if len(ids) != len(docs) {
+		return ErrMismatchedIDs
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, id := range ids {
+		doc := docs[i]
+		doc.Metadata = maps.Clone(doc.Metadata)
+		s.docs[id] = doc
+	}
+	return nil
+}
+
+// Get returns the documents with the ids.
+func (s *InMemoryDocStore) Get(_ context.Context, ids []string) (map[string]schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	docs := make(map[string]schema.Document, len(ids))
+	for _, id := range ids {
+		if doc, ok := s.docs[id]; ok {
+			doc.Metadata = maps.Clone(doc.Metadata)
+			docs[id] = doc
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents with the ids.
+func (s *InMemoryDocStore) Delete(_ context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package retrievers
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const _hydePrompt = `Please write a passage to answer the question.
+Question: {{.question}}
+Passage:`
+
+// HyDE is a Hypothetical Document Embeddings retriever: it asks an LLM to
+// draft a passage answering the question and searches the vector store with
+// the embedding of the draft instead of the question. Drafts look like the
+// documents searched, which improves zero-shot retrieval when questions and
+// documents are phrased differently, at the cost of an LLM call per search.
+type HyDE struct {
+	CallbacksHandler callbacks.Handler
+	llm              llms.Model
+	store            vectorstores.VectorStore
+	// Prompt is the prompt of the LLM drafting the passage, with the
+	// question input variable.
+	Prompt prompts.PromptTemplate
+	// NumDocuments is the number of documents returned.
+	NumDocuments int
+	// SearchOptions are the options of the searches.
+	SearchOptions []vectorstores.Option
+}
+
+var _ schema.Retriever = HyDE{}
+
+// HyDEOption is a function that configures a HyDE.
+type HyDEOption func(*HyDE)
+
+// WithHyDEPrompt is an option for setting the prompt of the LLM drafting the
+// passage, e.g. one specific to the domain of the documents, with the
+// question input variable.
+func WithHyDEPrompt(prompt prompts.PromptTemplate) HyDEOption {
+	return func(r *HyDE) {
+		r.Prompt = prompt
+	}
+}
+
+// WithHyDESearchOptions is an option for setting the options of the
+// searches.
+func WithHyDESearchOptions(options ...vectorstores.Option) HyDEOption {
+	return func(r *HyDE) {
+		r.SearchOptions = options
+	}
+}
+
+// NewHyDE returns a retriever searching numDocuments documents of the vector
+// store with the passages the LLM drafts.
+func NewHyDE(llm llms.Model, store vectorstores.VectorStore, numDocuments int, options ...HyDEOption) HyDE {
+	r := HyDE{
+		llm:          llm,
+		store:        store,
+		Prompt:       prompts.NewPromptTemplate(_hydePrompt, []string{"question"}),
+		NumDocuments: numDocuments,
+	}
+	for _, opt := range options {
+		opt(&r)
+	}
+	return r
+}
+
+// GetRelevantDocuments returns the documents most similar to the passage
+// drafted for the question. The vector store embeds the passage as a query.
+func (r HyDE) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	prompt, err := r.Prompt.Format(map[string]any{"question": query})
+	if err != nil {
+		return nil, err
+	}
+	passage, err := llms.GenerateFromSinglePrompt(ctx, r.llm, prompt)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := r.store.SimilaritySearch(ctx, passage, r.NumDocuments, r.SearchOptions...)
+	if err != nil {
+		return nil, err
+	}
+	schema.SetRetriever(docs, "hyde")
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
@@ -0,0 +1,29 @@
+package retrievers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestHyDE(t *testing.T) {
+	t.Parallel()
+	llm := fake.New(fake.TextResponses("Tokyo is the capital of Japan.")...)
+	store := &searchStore{}
+	r := retrievers.NewHyDE(llm, store, 2, retrievers.WithHyDESearchOptions(vectorstores.WithNameSpace("cities")))
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "What is the capital of Japan?")
+	require.NoError(t, err)
+	assert.Equal(t, schema.Provenance{Retriever: "hyde"}, docs[0].Provenance())
+	assert.Equal(t, "Tokyo is the capital of Japan.", docs[0].PageContent)
+	assert.Equal(t, "cities", store.opts.NameSpace)
+	assert.Equal(t, "Please write a passage to answer the question.\nQuestion: What is the capital of Japan?\nPassage:",
+		llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text)
+}
@@ -0,0 +1,216 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// _defaultIDKey is the metadata key of the id of the parent documents of
+	// child chunks.
+	_defaultIDKey = "doc_id"
+	// _defaultNumChildren is the number of child chunks searched.
+	_defaultNumChildren = 4
+)
+
+var (
+	// ErrMismatchedIDs is returned when the numbers of ids and documents
+	// given don't match.
+	ErrMismatchedIDs = errors.New("number of ids does not match number of documents")
+	// ErrMissingParentID is returned when a child chunk found has no parent
+	// id.
+	ErrMissingParentID = errors.New("missing parent id in child chunk")
+)
+
+// ParentDocument is a retriever searching small child chunks of documents in
+// a vector store, whose embeddings match queries precisely, but returning
+// their parent documents from a DocStore, which give more context. The
+// parents are the documents added, or larger chunks of them with a parent
+// splitter.
+type ParentDocument struct {
+	CallbacksHandler callbacks.Handler
+	store            vectorstores.VectorStore
+	docStore         DocStore
+	childSplitter    textsplitter.TextSplitter
+	parentSplitter   textsplitter.TextSplitter
+	// IDKey is the metadata key of the id of the parent of child chunks.
+	IDKey string
+	// NumChildren is the number of child chunks searched.
+	NumChildren int
+	// NumDocuments is the number of parent documents returned. If zero, the
+	// parents of all the child chunks found are returned.
+	NumDocuments int
+	// SearchOptions are the options of the searches of child chunks.
+	SearchOptions []vectorstores.Option
+}
+
+var _ schema.Retriever = ParentDocument{}
+
+// ParentDocumentOption is a function that configures a ParentDocument.
+type ParentDocumentOption func(*ParentDocument)
+
+// WithParentSplitter is an option for splitting the documents added into
+// parent chunks, e.g. windows of 2000 tokens, which are split into child
+// chunks. By default, the parents are the documents added.
+func WithParentSplitter(splitter textsplitter.TextSplitter) ParentDocumentOption {
+	return func(r *ParentDocument) {
+		r.parentSplitter = splitter
+	}
+}
+
+// WithIDKey is an option for setting the metadata key of the id of the
+// parent of child chunks. Defaults to "doc_id".
+func WithIDKey(key string) ParentDocumentOption {
+	return func(r *ParentDocument) {
+		r.IDKey = key
+	}
+}
+
+// WithNumChildren is an option for setting the number of child chunks
+// searched. Defaults to 4.
+func WithNumChildren(numChildren int) ParentDocumentOption {
+	return func(r *ParentDocument) {
+		r.NumChildren = numChildren
+	}
+}
+
+// WithNumDocuments is an option for setting the number of parent documents
+// returned.
+func WithNumDocuments(numDocuments int) ParentDocumentOption {
+	return func(r *ParentDocument) {
+		r.NumDocuments = numDocuments
+	}
+}
+
+// WithSearchOptions is an option for setting the options of the searches of
+// child chunks, e.g. a score threshold.
+func WithSearchOptions(options ...vectorstores.Option) ParentDocumentOption {
+	return func(r *ParentDocument) {
+		r.SearchOptions = options
+	}
+}
+
+// NewParentDocument returns a retriever whose documents, added with
+// AddDocuments, are split into child chunks by the child splitter, stored in
+// the vector store, while the parents are stored in the doc store.
+func NewParentDocument(
+	store vectorstores.VectorStore,
+	docStore DocStore,
+	childSplitter textsplitter.TextSplitter,
+	options ...ParentDocumentOption,
+) ParentDocument {
+	r := ParentDocument{
+		store:         store,
+		docStore:      docStore,
+		childSplitter: childSplitter,
+		IDKey:         _defaultIDKey,
+		NumChildren:   _defaultNumChildren,
+	}
+	for _, opt := range options {
+		opt(&r)
+	}
+	return r
+}
+
+// AddDocuments adds the documents, or their parent chunks, to the doc store
+// and their child chunks to the vector store with the options, and returns
+// the ids of the parents.
+func (r ParentDocument) AddDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	parents := docs
+	if r.parentSplitter != nil {
+		var err error
+		parents, err = textsplitter.SplitDocuments(r.parentSplitter, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]string, len(parents))
+	var children []schema.Document
+	for i, parent := range parents {
+		ids[i] = uuid.NewString()
+		chunks, err := textsplitter.SplitDocuments(r.childSplitter, []schema.Document{parent})
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			if chunk.Metadata == nil {
+				chunk.Metadata = map[string]any{}
+			}
+			chunk.Metadata[r.IDKey] = ids[i]
+			children = append(children, chunk)
+		}
+	}
+
+	if err := r.docStore.Set(ctx, ids, parents); err != nil {
+		return nil, err
+	}
+	if _, err := r.store.AddDocuments(ctx, children, options...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetRelevantDocuments returns the parents of the child chunks most similar
+// to the query, ordered by the similarity of their most similar chunk, which
+// is their score.
+func (r ParentDocument) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	children, err := r.store.SimilaritySearch(ctx, query, r.NumChildren, r.SearchOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(children))
+	scores := make(map[string]float32, len(children))
+	for _, child := range children {
+		id, ok := child.Metadata[r.IDKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingParentID, r.IDKey)
+		}
+		if score, ok := scores[id]; ok {
+			scores[id] = max(score, child.Score)
+			continue
+		}
+		scores[id] = child.Score
+		ids = append(ids, id)
+	}
+
+	parents, err := r.docStore.Get(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]schema.Document, 0, len(ids))
+	for _, id := range ids {
+		parent, ok := parents[id]
+		if !ok {
+			// the parent was deleted from the doc store.
+			continue
+		}
+		parent.Score = scores[id]
+		docs = append(docs, parent)
+		if r.NumDocuments > 0 && len(docs) == r.NumDocuments {
+			break
+		}
+	}
+	schema.SetRetriever(docs, "parent_document")
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
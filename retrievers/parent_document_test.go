@@ -0,0 +1,113 @@
+package retrievers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// chunkStore returns the chunks equal to the query, in the order they were
+// added, with decreasing scores.
+type chunkStore struct {
+	vectorstores.VectorStore
+	chunks []schema.Document
+}
+
+func (s *chunkStore) AddDocuments(
+	_ context.Context,
+	docs []schema.Document,
+	_ ...vectorstores.Option,
+) ([]string, error) {
+	s.chunks = append(s.chunks, docs...)
+	return make([]string, len(docs)), nil
+}
+
+func (s *chunkStore) SimilaritySearch(
+	_ context.Context,
+	query string,
+	numDocuments int,
+	_ ...vectorstores.Option,
+) ([]schema.Document, error) {
+	var docs []schema.Document
+	for _, chunk := range s.chunks {
+		if chunk.PageContent == query && len(docs) < numDocuments {
+			chunk.Score = 1 - float32(len(docs))/10
+			docs = append(docs, chunk)
+		}
+	}
+	return docs, nil
+}
+
+// splitter splits texts on the separator.
+type splitter string
+
+func (s splitter) SplitText(text string) ([]string, error) {
+	return strings.Split(text, string(s)), nil
+}
+
+func TestParentDocument(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := &chunkStore{}
+	docStore := retrievers.NewInMemoryDocStore()
+	r := retrievers.NewParentDocument(store, docStore, splitter(" "))
+
+	ids, err := r.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo kyoto", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "paris lyon kyoto"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Len(t, store.chunks, 5)
+	assert.Equal(t, map[string]any{
+		"country": "japan", "doc_id": ids[0], "start_index": 6, "end_index": 11,
+	}, store.chunks[1].Metadata)
+
+	docs, err := r.GetRelevantDocuments(ctx, "kyoto")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{
+		{PageContent: "tokyo kyoto", Metadata: map[string]any{"country": "japan", "retriever": "parent_document"}, Score: 1},
+		{PageContent: "paris lyon kyoto", Metadata: map[string]any{"retriever": "parent_document"}, Score: 0.9},
+	}, docs)
+
+	r.NumDocuments = 1
+	docs, err = r.GetRelevantDocuments(ctx, "kyoto")
+	require.NoError(t, err)
+	assert.Len(t, docs, 1)
+
+	require.NoError(t, docStore.Delete(ctx, ids[:1]))
+	docs, err = r.GetRelevantDocuments(ctx, "kyoto")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{
+		{PageContent: "paris lyon kyoto", Metadata: map[string]any{"retriever": "parent_document"}, Score: 0.9},
+	}, docs)
+}
+
+func TestParentDocumentWithParentSplitter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := &chunkStore{}
+	r := retrievers.NewParentDocument(store, retrievers.NewInMemoryDocStore(), splitter(" "),
+		retrievers.WithParentSplitter(splitter("\n")), retrievers.WithIDKey("parent"))
+
+	ids, err := r.AddDocuments(ctx, []schema.Document{{PageContent: "tokyo kyoto\nparis lyon"}})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	docs, err := r.GetRelevantDocuments(ctx, "lyon")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "paris lyon", docs[0].PageContent)
+	assert.Equal(t, schema.Provenance{StartIndex: 12, EndIndex: 22, Score: 1, Retriever: "parent_document"},
+		docs[0].Provenance())
+
+	store.chunks = append(store.chunks, schema.Document{PageContent: "lyon"})
+	_, err = r.GetRelevantDocuments(ctx, "lyon")
+	require.ErrorIs(t, err, retrievers.ErrMissingParentID)
+}
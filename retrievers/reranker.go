@@ -0,0 +1,110 @@
+package retrievers
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _defaultFetchMultiplier is the factor of the number of documents fetched
+// from the base retriever for reranking.
+const _defaultFetchMultiplier = 4
+
+// Reranker is a retriever reordering the documents of a base retriever with a
+// reranker, such as the Cohere, Voyage or Jina rerankers.
+type Reranker struct {
+	CallbacksHandler callbacks.Handler
+	base             schema.Retriever
+	reranker         schema.Reranker
+	// TopK is the number of documents returned. If zero, it is the number of
+	// documents of the base retriever.
+	TopK int
+	// FetchMultiplier is the factor of TopK fetched from the base retriever
+	// for reranking, if the number of its documents can be changed.
+	FetchMultiplier int
+}
+
+var _ schema.Retriever = Reranker{}
+
+// RerankerOption is a function that configures a Reranker.
+type RerankerOption func(*Reranker)
+
+// WithTopK is an option for setting the number of documents returned.
+func WithTopK(topK int) RerankerOption {
+	return func(r *Reranker) {
+		r.TopK = topK
+	}
+}
+
+// WithFetchMultiplier is an option for setting the factor of the number of
+// documents fetched from the base retriever for reranking.
+func WithFetchMultiplier(multiplier int) RerankerOption {
+	return func(r *Reranker) {
+		r.FetchMultiplier = multiplier
+	}
+}
+
+// WithCallbacksHandler is an option for setting the callbacks handler.
+func WithCallbacksHandler(handler callbacks.Handler) RerankerOption {
+	return func(r *Reranker) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// NewReranker returns a retriever which fetches TopK*FetchMultiplier
+// documents from the base retriever, reranks them with the reranker and
+// returns the TopK most relevant ones. Over-fetching requires a base
+// retriever whose number of documents can be changed, such as the
+// retrievers of vector stores; other retrievers are reranked as they are.
+func NewReranker(base schema.Retriever, reranker schema.Reranker, options ...RerankerOption) Reranker {
+	r := Reranker{
+		base:            base,
+		reranker:        reranker,
+		FetchMultiplier: _defaultFetchMultiplier,
+	}
+	for _, opt := range options {
+		opt(&r)
+	}
+	return r
+}
+
+// resizableRetriever is a retriever whose number of documents can be changed.
+type resizableRetriever interface {
+	NumDocuments() int
+	WithNumDocuments(numDocuments int) schema.Retriever
+}
+
+// GetRelevantDocuments returns the reranked documents of the base retriever.
+func (r Reranker) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	base, topK := r.base, r.TopK
+	if resizable, ok := base.(resizableRetriever); ok {
+		if topK <= 0 {
+			topK = resizable.NumDocuments()
+		}
+		if r.FetchMultiplier > 1 {
+			base = resizable.WithNumDocuments(topK * r.FetchMultiplier)
+		}
+	}
+
+	docs, err := base.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	docs, err = r.reranker.Rerank(ctx, query, docs)
+	if err != nil {
+		return nil, err
+	}
+	if topK > 0 && len(docs) > topK {
+		docs = docs[:topK]
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
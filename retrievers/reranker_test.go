@@ -0,0 +1,69 @@
+package retrievers_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+type store struct {
+	vectorstores.VectorStore
+	numDocuments int
+}
+
+func (s *store) SimilaritySearch(
+	_ context.Context,
+	_ string,
+	numDocuments int,
+	_ ...vectorstores.Option,
+) ([]schema.Document, error) {
+	s.numDocuments = numDocuments
+	docs := make([]schema.Document, numDocuments)
+	for i := range docs {
+		docs[i].PageContent = fmt.Sprint(i)
+	}
+	return docs, nil
+}
+
+// reverseReranker ranks the documents in reverse order.
+type reverseReranker struct{}
+
+func (reverseReranker) Rerank(_ context.Context, _ string, docs []schema.Document) ([]schema.Document, error) {
+	result := make([]schema.Document, len(docs))
+	for i, doc := range docs {
+		doc.Score = float32(i)
+		result[i] = doc
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
+
+func TestReranker(t *testing.T) {
+	t.Parallel()
+	s := &store{}
+	r := retrievers.NewReranker(vectorstores.ToRetriever(s, 2), reverseReranker{}, retrievers.WithFetchMultiplier(3))
+	docs, err := r.GetRelevantDocuments(context.Background(), "q")
+	require.NoError(t, err)
+	assert.Equal(t, 6, s.numDocuments)
+	metadata := map[string]any{"retriever": "vectorstore"}
+	assert.Equal(t, []schema.Document{
+		{PageContent: "5", Metadata: metadata, Score: 5},
+		{PageContent: "4", Metadata: metadata, Score: 4},
+	}, docs)
+
+	r = retrievers.NewReranker(vectorstores.ToRetriever(s, 2), reverseReranker{}, retrievers.WithTopK(3))
+	docs, err = r.GetRelevantDocuments(context.Background(), "q")
+	require.NoError(t, err)
+	assert.Equal(t, 12, s.numDocuments)
+	assert.Len(t, docs, 3)
+	assert.Equal(t, "11", docs[0].PageContent)
+}
@@ -0,0 +1,260 @@
+package retrievers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+//nolint:lll
+const _selfQueryPrompt = `Your goal is to structure the user's question to match the request schema below, so that documents can be searched by the similarity of their content to a query and filtered on their metadata attributes.
+
+The documents are: {{.content}}
+
+Their metadata attributes are:
+{{.attributes}}
+
+Answer with a JSON object, and nothing else, with the fields:
+- "query": the text to compare to the contents of the documents, without the conditions of the filter. Use an empty string if there is nothing left to compare.
+- "filter": the filter on the metadata attributes, or null if the question has no condition on them.
+
+A filter is either a comparison {"op": "eq" | "ne" | "gt" | "gte" | "lt" | "lte", "key": attribute, "value": value}, an inclusion {"op": "in", "key": attribute, "values": [values]}, or a logical operation {"op": "and" | "or", "filters": [filters]}. Only use the attributes listed above, with values of their type.
+
+For example, "reports from 2023 about pricing" for reports with a year attribute is:
+{"query": "pricing", "filter": {"op": "eq", "key": "year", "value": 2023}}
+
+Question: {{.query}}
+`
+
+// ErrInvalidStructuredQuery is returned when the LLM of a SelfQuery
+// retriever doesn't answer with a valid structured query.
+var ErrInvalidStructuredQuery = errors.New("invalid structured query")
+
+// AttributeInfo describes a metadata attribute of the documents searched by
+// a SelfQuery retriever.
+type AttributeInfo struct {
+	// Name is the metadata key of the attribute.
+	Name string `json:"name"`
+	// Description tells the LLM what the attribute is.
+	Description string `json:"description"`
+	// Type is the type of the values of the attribute, e.g. "string",
+	// "integer", "float" or "boolean".
+	Type string `json:"type"`
+}
+
+// StructuredQuery is the query and the filter a SelfQuery retriever
+// translates a question to.
+type StructuredQuery struct {
+	Query  string
+	Filter *vectorstores.Filter
+}
+
+// SelfQuery is a retriever translating natural-language questions with an
+// LLM into a query compared to the content of the documents and a
+// vectorstores.Filter on their metadata attributes, e.g. "reports from 2023
+// about pricing" into the query "pricing" and the filter year = 2023, and
+// searching the vector store with them. The store must support
+// vectorstores.Filter filters.
+type SelfQuery struct {
+	CallbacksHandler callbacks.Handler
+	llm              llms.Model
+	store            vectorstores.VectorStore
+	documentContents string
+	attributes       []AttributeInfo
+	// Prompt is the prompt of the LLM, with the content, attributes and
+	// query input variables.
+	Prompt prompts.PromptTemplate
+	// NumDocuments is the number of documents returned.
+	NumDocuments int
+	// SearchOptions are the options of the searches, to which the filter
+	// is added.
+	SearchOptions []vectorstores.Option
+}
+
+var _ schema.Retriever = SelfQuery{}
+
+// SelfQueryOption is a function that configures a SelfQuery.
+type SelfQueryOption func(*SelfQuery)
+
+// WithSelfQueryPrompt is an option for setting the prompt of the LLM, with
+// the content, attributes and query input variables.
+func WithSelfQueryPrompt(prompt prompts.PromptTemplate) SelfQueryOption {
+	return func(r *SelfQuery) {
+		r.Prompt = prompt
+	}
+}
+
+// WithSelfQuerySearchOptions is an option for setting the options of the
+// searches, e.g. a score threshold.
+func WithSelfQuerySearchOptions(options ...vectorstores.Option) SelfQueryOption {
+	return func(r *SelfQuery) {
+		r.SearchOptions = options
+	}
+}
+
+// NewSelfQuery returns a retriever searching numDocuments documents of the
+// vector store, whose contents are described by documentContents, e.g.
+// "quarterly reports of the company", and whose metadata attributes the
+// questions can filter on are the attributes.
+func NewSelfQuery(
+	llm llms.Model,
+	store vectorstores.VectorStore,
+	numDocuments int,
+	documentContents string,
+	attributes []AttributeInfo,
+	options ...SelfQueryOption,
+) SelfQuery {
+	r := SelfQuery{
+		llm:              llm,
+		store:            store,
+		documentContents: documentContents,
+		attributes:       attributes,
+		Prompt:           prompts.NewPromptTemplate(_selfQueryPrompt, []string{"content", "attributes", "query"}),
+		NumDocuments:     numDocuments,
+	}
+	for _, opt := range options {
+		opt(&r)
+	}
+	return r
+}
+
+// GetRelevantDocuments returns the documents of the structured query of the
+// question.
+func (r SelfQuery) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	structured, err := r.StructuredQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	options := r.SearchOptions
+	if structured.Filter != nil {
+		options = append(options[:len(options):len(options)], vectorstores.WithFilter(*structured.Filter))
+	}
+	docs, err := r.store.SimilaritySearch(ctx, structured.Query, r.NumDocuments, options...)
+	if err != nil {
+		return nil, err
+	}
+	schema.SetRetriever(docs, "self_query")
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+// StructuredQuery translates the question into a structured query with the
+// LLM. The query defaults to the question if the LLM leaves it empty.
+func (r SelfQuery) StructuredQuery(ctx context.Context, question string) (StructuredQuery, error) {
+	attributes := make([]string, len(r.attributes))
+	for i, attribute := range r.attributes {
+		data, err := json.Marshal(attribute)
+		if err != nil {
+			return StructuredQuery{}, err
+		}
+		attributes[i] = string(data)
+	}
+	prompt, err := r.Prompt.Format(map[string]any{
+		"content":    r.documentContents,
+		"attributes": strings.Join(attributes, "\n"),
+		"query":      question,
+	})
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	completion, err := llms.GenerateFromSinglePrompt(ctx, r.llm, prompt)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+
+	structured, err := r.parse(completion)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	if strings.TrimSpace(structured.Query) == "" {
+		structured.Query = question
+	}
+	return structured, nil
+}
+
+// queryFilter is the JSON representation of filters in the completions of
+// the LLM.
+type queryFilter struct {
+	Op      vectorstores.FilterOp `json:"op"`
+	Key     string                `json:"key"`
+	Value   any                   `json:"value"`
+	Values  []any                 `json:"values"`
+	Filters []queryFilter         `json:"filters"`
+}
+
+// parse parses the JSON object of the completion, which may be in a
+// markdown code block, into a structured query whose filter is on the
+// attributes.
+func (r SelfQuery) parse(completion string) (StructuredQuery, error) {
+	start, end := strings.Index(completion, "{"), strings.LastIndex(completion, "}")
+	if start < 0 || end < start {
+		return StructuredQuery{}, fmt.Errorf("%w: no JSON object in %q", ErrInvalidStructuredQuery, completion)
+	}
+	var output struct {
+		Query  string       `json:"query"`
+		Filter *queryFilter `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(completion[start:end+1]), &output); err != nil {
+		return StructuredQuery{}, fmt.Errorf("%w: %w", ErrInvalidStructuredQuery, err)
+	}
+
+	structured := StructuredQuery{Query: output.Query}
+	if output.Filter == nil {
+		return structured, nil
+	}
+	filter := r.filter(*output.Filter)
+	if err := filter.Validate(); err != nil {
+		return StructuredQuery{}, fmt.Errorf("%w: %w", ErrInvalidStructuredQuery, err)
+	}
+	for _, key := range filterKeys(filter) {
+		if !r.hasAttribute(key) {
+			return StructuredQuery{}, fmt.Errorf("%w: unknown attribute %q", ErrInvalidStructuredQuery, key)
+		}
+	}
+	structured.Filter = &filter
+	return structured, nil
+}
+
+func (r SelfQuery) filter(f queryFilter) vectorstores.Filter {
+	filter := vectorstores.Filter{Op: f.Op, Key: f.Key, Value: f.Value, Values: f.Values}
+	for _, operand := range f.Filters {
+		filter.Filters = append(filter.Filters, r.filter(operand))
+	}
+	return filter
+}
+
+func (r SelfQuery) hasAttribute(name string) bool {
+	for _, attribute := range r.attributes {
+		if attribute.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// filterKeys returns the keys of the filter and its operands.
+func filterKeys(f vectorstores.Filter) []string {
+	if f.Op == vectorstores.FilterAnd || f.Op == vectorstores.FilterOr {
+		var keys []string
+		for _, operand := range f.Filters {
+			keys = append(keys, filterKeys(operand)...)
+		}
+		return keys
+	}
+	return []string{f.Key}
+}
@@ -0,0 +1,72 @@
+package retrievers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// searchStore records the query and options of its searches.
+type searchStore struct {
+	vectorstores.VectorStore
+	query string
+	opts  vectorstores.Options
+}
+
+func (s *searchStore) SimilaritySearch(
+	_ context.Context,
+	query string,
+	_ int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	s.query = query
+	s.opts = vectorstores.Options{}
+	for _, opt := range options {
+		opt(&s.opts)
+	}
+	return []schema.Document{{PageContent: query}}, nil
+}
+
+func TestSelfQuery(t *testing.T) {
+	t.Parallel()
+	llm := fake.New(fake.TextResponses(
+		"```json\n"+`{"query": "pricing", "filter": {"op": "and", "filters": [
+			{"op": "eq", "key": "year", "value": 2023},
+			{"op": "in", "key": "team", "values": ["sales", "marketing"]}]}}`+"\n```",
+		`{"query": "", "filter": null}`,
+		`{"query": "pricing", "filter": {"op": "eq", "key": "author", "value": "bob"}}`,
+		`pricing`,
+	)...)
+	store := &searchStore{}
+	r := retrievers.NewSelfQuery(llm, store, 3, "reports of the company", []retrievers.AttributeInfo{
+		{Name: "year", Description: "The year of the report", Type: "integer"},
+		{Name: "team", Description: "The team writing the report", Type: "string"},
+	}, retrievers.WithSelfQuerySearchOptions(vectorstores.WithScoreThreshold(0.5)))
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "reports from 2023 about pricing by sales or marketing")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "pricing", Metadata: map[string]any{"retriever": "self_query"}}}, docs)
+	assert.InDelta(t, 0.5, store.opts.ScoreThreshold, 1e-6)
+	assert.Equal(t, vectorstores.And(
+		vectorstores.Eq("year", float64(2023)),
+		vectorstores.In("team", "sales", "marketing"),
+	), store.opts.Filters)
+	assert.Contains(t, llm.LastCall().Messages[0].Parts[0].(llms.TextContent).Text, `"name":"year"`)
+
+	_, err = r.GetRelevantDocuments(context.Background(), "all the reports")
+	require.NoError(t, err)
+	assert.Equal(t, "all the reports", store.query)
+	assert.Nil(t, store.opts.Filters)
+
+	_, err = r.GetRelevantDocuments(context.Background(), "reports by bob about pricing")
+	require.ErrorIs(t, err, retrievers.ErrInvalidStructuredQuery)
+	_, err = r.GetRelevantDocuments(context.Background(), "pricing")
+	require.ErrorIs(t, err, retrievers.ErrInvalidStructuredQuery)
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Metadata keys of the provenance of documents, populated by document
+// loaders, text splitters and retrievers. They are metadata keys, rather
+// than fields of Document, so that they are kept by vector stores.
+const (
+	// MetadataSource is the key of the URI or path of the source of a
+	// document.
+	MetadataSource = "source"
+	// MetadataPage is the key of the page number of a document in its
+	// source, starting at 1.
+	MetadataPage = "page"
+	// MetadataStartIndex is the key of the byte offset of the start of a
+	// chunk in the content of the document it was split from.
+	MetadataStartIndex = "start_index"
+	// MetadataEndIndex is the key of the byte offset of the end of a chunk
+	// in the content of the document it was split from.
+	MetadataEndIndex = "end_index"
+	// MetadataRetriever is the key of the name of the retriever which
+	// returned a document.
+	MetadataRetriever = "retriever"
+)
+
+// Provenance is where a document comes from, for citing it.
+type Provenance struct {
+	// Source is the URI or path of the source, or empty if unknown.
+	Source string
+	// Page is the page number in the source, or zero if unknown.
+	Page int
+	// StartIndex and EndIndex are the byte offsets of the chunk in the
+	// content of the loaded document. EndIndex is zero if unknown.
+	StartIndex int
+	EndIndex   int
+	// Score is the retrieval score.
+	Score float32
+	// Retriever is the name of the retriever, or empty if unknown.
+	Retriever string
+}
+
+// Provenance returns the provenance of the document from its metadata and
+// score. Numeric metadata values may be of any number type, e.g. float64
+// after a JSON round trip through a vector store.
+func (d Document) Provenance() Provenance {
+	p := Provenance{Score: d.Score}
+	p.Source, _ = d.Metadata[MetadataSource].(string)
+	p.Page, _ = metadataInt(d.Metadata[MetadataPage])
+	p.StartIndex, _ = metadataInt(d.Metadata[MetadataStartIndex])
+	p.EndIndex, _ = metadataInt(d.Metadata[MetadataEndIndex])
+	p.Retriever, _ = d.Metadata[MetadataRetriever].(string)
+	return p
+}
+
+// SetRetriever sets the name of the retriever in the metadata of the
+// documents. Their metadata maps are copied, as they may be shared with a
+// store.
+func SetRetriever(docs []Document, name string) {
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		metadata[MetadataRetriever] = name
+		docs[i].Metadata = metadata
+	}
+}
+
+func metadataInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case float32:
+		return int(math.Round(float64(v))), true
+	case float64:
+		return int(math.Round(v)), true
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	default:
+		return 0, false
+	}
+}
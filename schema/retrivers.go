@@ -6,3 +6,12 @@ import "context"
 type Retriever interface {
 	GetRelevantDocuments(ctx context.Context, query string) ([]Document, error)
 }
+
+// Reranker is an interface for reordering documents by their relevance to a
+// query, e.g. with a cross-encoder model.
+type Reranker interface {
+	// Rerank returns the documents ordered by decreasing relevance to the
+	// query, with their Score set to the relevance score. It may return
+	// fewer documents than given.
+	Rerank(ctx context.Context, query string, docs []Document) ([]Document, error)
+}
@@ -1,11 +1,17 @@
 package schema
 
+import "github.com/tmc/langchaingo/llms"
+
 // AgentAction is the agent's action to take.
 type AgentAction struct {
 	Tool      string
 	ToolInput string
 	Log       string
 	ToolID    string
+	// Message is the message of the model requesting the action with a native
+	// tool call, shared by the actions requested by the same message. It is
+	// used by tool calling agents to rebuild the conversation.
+	Message *llms.MessageContent
 }
 
 // AgentStep is a step of the agent.
@@ -13,16 +13,48 @@ import (
 // length of the metadatas slice is zero.
 var ErrMismatchMetadatasAndText = errors.New("number of texts and metadatas does not match")
 
-// SplitDocuments splits documents using a textsplitter.
+// SplitDocuments splits documents using a textsplitter. The metadata of the
+// chunks has the byte offsets of the chunks in the documents, under the
+// schema.MetadataStartIndex and schema.MetadataEndIndex keys, if the chunks
+// are found in them. The offsets of chunks of documents which are chunks
+// themselves are relative to the document they were split from.
 func SplitDocuments(textSplitter TextSplitter, documents []schema.Document) ([]schema.Document, error) {
-	texts := make([]string, 0)
-	metadatas := make([]map[string]any, 0)
+	chunks := make([]schema.Document, 0)
 	for _, document := range documents {
-		texts = append(texts, document.PageContent)
-		metadatas = append(metadatas, document.Metadata)
+		docs, err := CreateDocuments(textSplitter, []string{document.PageContent}, []map[string]any{document.Metadata})
+		if err != nil {
+			return nil, err
+		}
+		addOffsets(document, docs)
+		chunks = append(chunks, docs...)
 	}
 
-	return CreateDocuments(textSplitter, texts, metadatas)
+	return chunks, nil
+}
+
+// addOffsets adds the offsets of the chunks found in the document to their
+// metadata. The chunks are searched in order, after the start of the
+// previous one, as they may overlap.
+func addOffsets(document schema.Document, chunks []schema.Document) {
+	base := 0
+	if _, ok := document.Metadata[schema.MetadataEndIndex]; ok {
+		base = document.Provenance().StartIndex
+	}
+	from := 0
+	for _, chunk := range chunks {
+		start := strings.Index(document.PageContent[from:], chunk.PageContent)
+		if start < 0 {
+			// the splitter changed the content of the chunk, e.g. by adding
+			// headers.
+			delete(chunk.Metadata, schema.MetadataStartIndex)
+			delete(chunk.Metadata, schema.MetadataEndIndex)
+			continue
+		}
+		start += from
+		chunk.Metadata[schema.MetadataStartIndex] = base + start
+		chunk.Metadata[schema.MetadataEndIndex] = base + start + len(chunk.PageContent)
+		from = min(start+1, len(document.PageContent))
+	}
 }
 
 // CreateDocuments creates documents from texts and metadatas with a text splitter. If
@@ -0,0 +1,33 @@
+package textsplitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestSplitDocumentsOffsets(t *testing.T) {
+	t.Parallel()
+	splitter := NewRecursiveCharacter(WithChunkSize(10), WithChunkOverlap(4), WithSeparators([]string{" "}))
+
+	docs, err := SplitDocuments(splitter, []schema.Document{
+		{PageContent: "one two three one two", Metadata: map[string]any{"page": 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{
+		{PageContent: "one two", Metadata: map[string]any{"page": 2, "start_index": 0, "end_index": 7}},
+		{PageContent: "two three", Metadata: map[string]any{"page": 2, "start_index": 4, "end_index": 13}},
+		{PageContent: "one two", Metadata: map[string]any{"page": 2, "start_index": 14, "end_index": 21}},
+	}, docs)
+
+	children, err := SplitDocuments(NewRecursiveCharacter(WithChunkSize(5), WithChunkOverlap(0),
+		WithSeparators([]string{" "})), docs[1:2])
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{
+		{PageContent: "two", Metadata: map[string]any{"page": 2, "start_index": 4, "end_index": 7}},
+		{PageContent: "three", Metadata: map[string]any{"page": 2, "start_index": 8, "end_index": 13}},
+	}, children)
+	assert.Equal(t, schema.Provenance{Page: 2, StartIndex: 8, EndIndex: 13}, children[1].Provenance())
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// Func is a StructuredTool calling a function with the JSON arguments of the
+// model decoded into a value of type T, whose JSON schema is given by
+// jsonschema.For. It lets tools take several typed arguments instead of
+// parsing a single string.
+type Func[T any] struct {
+	name        string
+	description string
+	fn          func(ctx context.Context, args T) (string, error)
+}
+
+var _ StructuredTool = Func[struct{}]{}
+
+// NewFunc creates a new Func tool with the name and description, calling fn.
+func NewFunc[T any](name, description string, fn func(ctx context.Context, args T) (string, error)) Func[T] {
+	return Func[T]{name: name, description: description, fn: fn}
+}
+
+// Name returns the name of the tool.
+func (f Func[T]) Name() string {
+	return f.name
+}
+
+// Description returns the description of the tool.
+func (f Func[T]) Description() string {
+	return f.description
+}
+
+// Parameters returns the JSON schema of T.
+func (f Func[T]) Parameters() any {
+	return jsonschema.For[T]()
+}
+
+// Call decodes the JSON arguments and calls the function. If the arguments
+// are invalid the error is given in the result to give the agent the ability
+// to retry.
+func (f Func[T]) Call(ctx context.Context, input string) (string, error) {
+	var args T
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return fmt.Sprintf("invalid arguments for %s: %s", f.name, err.Error()), nil //nolint:nilerr
+	}
+	return f.fn(ctx, args)
+}
@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ProtocolVersion is the version of the Model Context Protocol requested by
+// the client.
+const ProtocolVersion = "2024-11-05"
+
+const (
+	_jsonRPCVersion = "2.0"
+	// _methodNotFound is the JSON-RPC error code of unknown methods.
+	_methodNotFound = -32601
+)
+
+// ErrClosed is returned by the requests of a client whose connection is
+// closed.
+var ErrClosed = errors.New("mcp connection closed")
+
+// RPCError is an error returned by the server for a request.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// Implementation is the name and version of a client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// message is a JSON-RPC request, notification or response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Client is a client of an MCP server. It is safe for concurrent use.
+type Client struct {
+	transport  Transport
+	clientInfo Implementation
+
+	serverInfo   Implementation
+	instructions string
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[int64]chan message
+	closed  bool
+}
+
+// Option is a function that configures a Client.
+type Option func(*Client)
+
+// WithClientInfo sets the name and version of the client sent to the server.
+// Defaults to langchaingo.
+func WithClientInfo(name, version string) Option {
+	return func(c *Client) {
+		c.clientInfo = Implementation{Name: name, Version: version}
+	}
+}
+
+// New starts the transport and initializes the connection to the server.
+func New(ctx context.Context, transport Transport, opts ...Option) (*Client, error) {
+	c := &Client{
+		transport:  transport,
+		clientInfo: Implementation{Name: "langchaingo", Version: "1.0.0"},
+		pending:    make(map[int64]chan message),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	messages, err := transport.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	go c.receive(messages)
+
+	if err := c.initialize(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// ServerInfo returns the name and version of the server.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// Instructions returns the instructions of the server on how to use it, if
+// any, which can be added to the prompt.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+	return c.transport.Close()
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions"`
+	}
+	err := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      c.clientInfo,
+	}, &result)
+	if err != nil {
+		return fmt.Errorf("initializing mcp connection: %w", err)
+	}
+	c.serverInfo = result.ServerInfo
+	c.instructions = result.Instructions
+	return c.notify(ctx, "notifications/initialized", nil)
+}
+
+// call sends a request and decodes its result into result.
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	id := c.nextID.Add(1)
+	responses := make(chan message, 1)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.pending[id] = responses
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(ctx, json.RawMessage(strconv.FormatInt(id, 10)), method, params); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		// The server can stop processing the request.
+		_ = c.notify(context.WithoutCancel(ctx), "notifications/cancelled", map[string]any{
+			"requestId": id,
+			"reason":    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	case response, ok := <-responses:
+		if !ok {
+			return ErrClosed
+		}
+		if response.Error != nil {
+			return fmt.Errorf("%s: %w", method, response.Error)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// notify sends a notification.
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	return c.send(ctx, nil, method, params)
+}
+
+func (c *Client) send(ctx context.Context, id json.RawMessage, method string, params any) error {
+	m := message{JSONRPC: _jsonRPCVersion, ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		m.Params = data
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return c.transport.Send(ctx, data)
+}
+
+// receive dispatches the messages of the server until the connection is
+// closed, then fails the pending requests.
+func (c *Client) receive(messages <-chan []byte) {
+	for data := range messages {
+		var m message
+		if err := json.Unmarshal(data, &m); err != nil {
+			continue
+		}
+		switch {
+		case m.Method != "" && m.ID != nil:
+			go c.reply(m)
+		case m.Method == "":
+			id, err := strconv.ParseInt(string(m.ID), 10, 64)
+			if err != nil {
+				continue
+			}
+			// A request gets a single response: duplicate or late responses,
+			// e.g. once the caller gave up, are dropped.
+			c.mu.Lock()
+			responses, ok := c.pending[id]
+			delete(c.pending, id)
+			c.mu.Unlock()
+			if ok {
+				select {
+				case responses <- m:
+				default:
+				}
+			}
+		}
+		// Notifications of the server are ignored.
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for id, responses := range c.pending {
+		close(responses)
+		delete(c.pending, id)
+	}
+}
+
+// reply answers the requests of the server: pings, and errors for the
+// methods the client doesn't support.
+func (c *Client) reply(request message) {
+	response := message{JSONRPC: _jsonRPCVersion, ID: request.ID}
+	if request.Method == "ping" {
+		response.Result = json.RawMessage("{}")
+	} else {
+		response.Error = &RPCError{Code: _methodNotFound, Message: "method not found: " + request.Method}
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_ = c.transport.Send(context.Background(), data)
+}
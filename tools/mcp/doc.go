@@ -0,0 +1,19 @@
+// Package mcp contains a client of the Model Context Protocol, exposing the
+// tools of MCP servers as langchaingo tools.
+//
+// A Client connects to a server through a Transport: NewStdioTransport runs
+// the server as a subprocess talking over its standard input and output, and
+// NewSSETransport connects to a server over HTTP with server-sent events.
+// Client.Tools returns the tools of the server as tools.StructuredTool, with
+// the JSON schemas of their inputs, ready to be given to an agent:
+//
+//	client, err := mcp.New(ctx, mcp.NewStdioTransport(exec.Command("mcp-server-time")))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//	tools, err := client.Tools(ctx)
+//
+// The resources of the server can be listed and read with ListResources and
+// ReadResource.
+package mcp
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToolInfo describes a tool of a server.
+type ToolInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON schema of the arguments of the tool.
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// Resource describes a resource of a server.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents are the contents of a resource, either text or a base64
+// encoded blob.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// Content is a part of the result of a tool call: text, an image or audio
+// with base64 encoded data, or an embedded resource.
+type Content struct {
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	Data     string            `json:"data,omitempty"`
+	MimeType string            `json:"mimeType,omitempty"`
+	Resource *ResourceContents `json:"resource,omitempty"`
+}
+
+// CallToolResult is the result of a tool call.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	// IsError is set if the tool failed, the content describing the error.
+	IsError bool `json:"isError,omitempty"`
+}
+
+// Text returns the text of the content of the result. Images, audio and
+// binary resources are replaced by a placeholder with their MIME type.
+func (r *CallToolResult) Text() string {
+	texts := make([]string, 0, len(r.Content))
+	for _, content := range r.Content {
+		switch {
+		case content.Type == "text":
+			texts = append(texts, content.Text)
+		case content.Resource != nil && content.Resource.Blob == "":
+			texts = append(texts, content.Resource.Text)
+		case content.Resource != nil:
+			texts = append(texts, fmt.Sprintf("[%s resource %s]", content.Resource.MimeType, content.Resource.URI))
+		default:
+			texts = append(texts, fmt.Sprintf("[%s %s]", content.MimeType, content.Type))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// ListTools returns the tools of the server.
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	err := c.list(ctx, "tools/list", func(data json.RawMessage) (string, error) {
+		var page struct {
+			Tools      []ToolInfo `json:"tools"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return "", err
+		}
+		tools = append(tools, page.Tools...)
+		return page.NextCursor, nil
+	})
+	return tools, err
+}
+
+// CallTool calls the tool of the server with the JSON arguments.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	var result CallToolResult
+	err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources returns the resources of the server.
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	err := c.list(ctx, "resources/list", func(data json.RawMessage) (string, error) {
+		var page struct {
+			Resources  []Resource `json:"resources"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return "", err
+		}
+		resources = append(resources, page.Resources...)
+		return page.NextCursor, nil
+	})
+	return resources, err
+}
+
+// ReadResource returns the contents of the resource.
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	if err := c.call(ctx, "resources/read", map[string]any{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// list calls a paginated list method, passing each page to the function
+// which returns the cursor of the next page.
+func (c *Client) list(ctx context.Context, method string, page func(json.RawMessage) (string, error)) error {
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var result json.RawMessage
+		if err := c.call(ctx, method, params, &result); err != nil {
+			return err
+		}
+		next, err := page(result)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
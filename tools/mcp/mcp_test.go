@@ -0,0 +1,285 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools"
+	"github.com/tmc/langchaingo/tools/mcp"
+)
+
+func TestMain(m *testing.M) {
+	// The test binary is run as the server of the stdio transport.
+	if os.Getenv("MCP_TEST_SERVER") == "1" {
+		var mu sync.Mutex
+		server := &fakeServer{send: func(data []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(os.Stdout, "%s\n", data)
+		}}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			server.handle(scanner.Bytes())
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// fakeServer is an MCP server with an echo tool, a failing tool and a
+// resource. It pings the client once initialized.
+type fakeServer struct {
+	send   func([]byte)
+	mu     sync.Mutex
+	pinged bool
+}
+
+func (s *fakeServer) reply(id json.RawMessage, result any) {
+	data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "result": result})
+	s.send(data)
+}
+
+func (s *fakeServer) handle(data []byte) {
+	var m rpcMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return
+	}
+	var params struct {
+		Cursor    string          `json:"cursor"`
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+		URI       string          `json:"uri"`
+	}
+	_ = json.Unmarshal(m.Params, &params)
+
+	switch m.Method {
+	case "":
+		// The response of the client to the ping.
+		s.mu.Lock()
+		s.pinged = string(m.ID) == `"ping-1"` && string(m.Result) == "{}"
+		s.mu.Unlock()
+	case "initialize":
+		s.reply(m.ID, map[string]any{
+			"protocolVersion": mcp.ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "0.1.0"},
+			"instructions":    "Use echo to echo.",
+		})
+	case "notifications/initialized":
+		s.send([]byte(`{"jsonrpc":"2.0","id":"ping-1","method":"ping"}`))
+	case "tools/list":
+		if params.Cursor == "" {
+			s.reply(m.ID, map[string]any{"nextCursor": "2", "tools": []map[string]any{{
+				"name":        "echo",
+				"description": "Echoes the text.",
+				"inputSchema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"text": map[string]any{"type": "string"}},
+					"required":   []string{"text"},
+				},
+			}}})
+			return
+		}
+		s.reply(m.ID, map[string]any{"tools": []map[string]any{{"name": "fail"}, {"name": "pinged"}}})
+	case "tools/call":
+		switch params.Name {
+		case "echo":
+			var args struct{ Text string }
+			_ = json.Unmarshal(params.Arguments, &args)
+			s.reply(m.ID, map[string]any{"content": []map[string]any{
+				{"type": "text", "text": args.Text},
+				{"type": "image", "data": "aGVsbG8=", "mimeType": "image/png"},
+			}})
+		case "pinged":
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.reply(m.ID, map[string]any{"content": []map[string]any{
+				{"type": "text", "text": fmt.Sprint(s.pinged)},
+			}})
+		default:
+			s.reply(m.ID, map[string]any{"isError": true, "content": []map[string]any{{"type": "text", "text": "boom"}}})
+		}
+	case "resources/list":
+		s.reply(m.ID, map[string]any{"resources": []map[string]any{
+			{"uri": "file:///notes.txt", "name": "notes", "mimeType": "text/plain"},
+		}})
+	case "resources/read":
+		s.reply(m.ID, map[string]any{"contents": []map[string]any{
+			{"uri": params.URI, "mimeType": "text/plain", "text": "remember the milk"},
+		}})
+	default:
+		data, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0", "id": m.ID, "error": map[string]any{"code": -32601, "message": "method not found"},
+		})
+		s.send(data)
+	}
+}
+
+func testClient(t *testing.T, client *mcp.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	assert.Equal(t, mcp.Implementation{Name: "fake", Version: "0.1.0"}, client.ServerInfo())
+	assert.Equal(t, "Use echo to echo.", client.Instructions())
+
+	serverTools, err := client.Tools(ctx)
+	require.NoError(t, err)
+	require.Len(t, serverTools, 3)
+	echo, ok := serverTools[0].(tools.StructuredTool)
+	require.True(t, ok)
+	assert.Equal(t, "echo", echo.Name())
+	assert.Equal(t, "Echoes the text.", echo.Description())
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"text": map[string]any{"type": "string"}},
+		"required":   []any{"text"},
+	}, echo.Parameters())
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}},
+		serverTools[1].(mcp.Tool).Parameters())
+
+	result, err := echo.Call(ctx, `{"text": "hello"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n[image/png image]", result)
+	result, err = echo.Call(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "invalid arguments for echo: the input must be a JSON object", result)
+	result, err = serverTools[1].Call(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "error calling fail: boom", result)
+	// The client answers the ping of the server concurrently.
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		result, err = serverTools[2].Call(ctx, "{}")
+		require.NoError(t, err)
+		if result == "true" || time.Now().After(deadline) {
+			break
+		}
+	}
+	assert.Equal(t, "true", result, "the client answers pings")
+
+	resources, err := client.ListResources(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.Resource{{URI: "file:///notes.txt", Name: "notes", MimeType: "text/plain"}}, resources)
+	contents, err := client.ReadResource(ctx, "file:///notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.ResourceContents{
+		{URI: "file:///notes.txt", MimeType: "text/plain", Text: "remember the milk"},
+	}, contents)
+
+	require.NoError(t, client.Close())
+	_, err = client.ListTools(ctx)
+	require.ErrorIs(t, err, mcp.ErrClosed)
+}
+
+func TestStdioTransport(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), "MCP_TEST_SERVER=1")
+	client, err := mcp.New(context.Background(), mcp.NewStdioTransport(cmd))
+	require.NoError(t, err)
+	testClient(t, client)
+}
+
+func TestSSETransport(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan []byte, 16)
+	server := &fakeServer{send: func(data []byte) { events <- data }}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: endpoint\ndata: /messages?session=1\n\n")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case data := <-events:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("POST /messages", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		server.handle(data)
+	})
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	_, err := mcp.New(context.Background(), mcp.NewSSETransport(httpServer.URL+"/sse"))
+	require.ErrorIs(t, err, mcp.ErrRequestFailed)
+
+	transport := mcp.NewSSETransport(httpServer.URL+"/sse", mcp.WithHeader("Authorization", "Bearer token"))
+	client, err := mcp.New(context.Background(), transport)
+	require.NoError(t, err)
+	testClient(t, client)
+}
+
+// duplicatingTransport runs the fake server in process and delivers each of
+// its responses several times.
+type duplicatingTransport struct {
+	server   *fakeServer
+	messages chan []byte
+}
+
+func (t *duplicatingTransport) Start(context.Context) (<-chan []byte, error) {
+	t.messages = make(chan []byte, 16)
+	t.server = &fakeServer{send: func(data []byte) {
+		for i := 0; i < 3; i++ {
+			t.messages <- data
+		}
+	}}
+	return t.messages, nil
+}
+
+func (t *duplicatingTransport) Send(_ context.Context, message []byte) error {
+	t.server.handle(message)
+	return nil
+}
+
+func (t *duplicatingTransport) Close() error {
+	close(t.messages)
+	return nil
+}
+
+func TestDuplicateResponses(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := mcp.New(ctx, &duplicatingTransport{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Duplicate responses are dropped instead of blocking the client.
+	for i := 0; i < 100; i++ {
+		resources, err := client.ListResources(ctx)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+	}
+}
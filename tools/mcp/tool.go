@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool of an MCP server. It is a tools.StructuredTool whose
+// parameters are the input schema of the tool.
+type Tool struct {
+	client     *Client
+	info       ToolInfo
+	parameters map[string]any
+}
+
+var _ tools.StructuredTool = Tool{}
+
+// NewTool returns the tool of the server described by info.
+func NewTool(client *Client, info ToolInfo) (Tool, error) {
+	parameters := map[string]any{"type": "object", "properties": map[string]any{}}
+	if len(info.InputSchema) > 0 && string(info.InputSchema) != "null" {
+		if err := json.Unmarshal(info.InputSchema, &parameters); err != nil {
+			return Tool{}, fmt.Errorf("invalid input schema of tool %s: %w", info.Name, err)
+		}
+	}
+	return Tool{client: client, info: info, parameters: parameters}, nil
+}
+
+// Tools returns the tools of the server.
+func (c *Client) Tools(ctx context.Context) ([]tools.Tool, error) {
+	infos, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]tools.Tool, 0, len(infos))
+	for _, info := range infos {
+		tool, err := NewTool(c, info)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tool)
+	}
+	return result, nil
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return t.info.Name
+}
+
+// Description returns the description of the tool.
+func (t Tool) Description() string {
+	return t.info.Description
+}
+
+// Parameters returns the input schema of the tool.
+func (t Tool) Parameters() any {
+	return t.parameters
+}
+
+// Call calls the tool with the JSON arguments and returns the text of the
+// result. If the arguments are invalid or the tool reports an error, the
+// error is given in the result to give the agent the ability to retry.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		input = "{}"
+	}
+	if !json.Valid([]byte(input)) || input[0] != '{' {
+		return fmt.Sprintf("invalid arguments for %s: the input must be a JSON object", t.info.Name), nil
+	}
+
+	result, err := t.client.CallTool(ctx, t.info.Name, json.RawMessage(input))
+	if err != nil {
+		return "", err
+	}
+	if result.IsError {
+		return fmt.Sprintf("error calling %s: %s", t.info.Name, result.Text()), nil
+	}
+	return result.Text(), nil
+}
@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// _stdioCloseTimeout is the time a server is given to exit once its input is
+// closed, before it is killed.
+const _stdioCloseTimeout = 5 * time.Second
+
+var (
+	// ErrRequestFailed is returned when an HTTP request to the server fails.
+	ErrRequestFailed = errors.New("mcp request failed")
+	// ErrNoEndpoint is returned when an SSE stream ends before the server
+	// gives the endpoint the messages are sent to.
+	ErrNoEndpoint = errors.New("mcp server sent no endpoint")
+)
+
+// Transport exchanges JSON-RPC messages with a server.
+type Transport interface {
+	// Start connects to the server and returns the channel of the messages
+	// received, closed when the connection is closed.
+	Start(ctx context.Context) (<-chan []byte, error)
+	// Send sends a message to the server.
+	Send(ctx context.Context, message []byte) error
+	// Close closes the connection.
+	Close() error
+}
+
+// StdioTransport is a Transport running the server as a subprocess, which
+// receives messages on its standard input and sends messages on its standard
+// output, one per line.
+type StdioTransport struct {
+	cmd   *exec.Cmd
+	mu    sync.Mutex
+	stdin io.WriteCloser
+}
+
+var _ Transport = &StdioTransport{}
+
+// NewStdioTransport returns a transport running the command, whose
+// environment, directory and standard error can be configured.
+func NewStdioTransport(cmd *exec.Cmd) *StdioTransport {
+	return &StdioTransport{cmd: cmd}
+}
+
+// Start starts the command.
+func (t *StdioTransport) Start(context.Context) (<-chan []byte, error) {
+	stdin, err := t.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := t.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting mcp server: %w", err)
+	}
+	t.stdin = stdin
+
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		reader := bufio.NewReader(stdout)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				messages <- line
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return messages, nil
+}
+
+// Send writes the message to the standard input of the command.
+func (t *StdioTransport) Send(_ context.Context, message []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stdin == nil {
+		return ErrClosed
+	}
+	_, err := t.stdin.Write(append(message, '\n'))
+	return err
+}
+
+// Close closes the standard input of the command and waits for it to exit,
+// killing it if it doesn't exit in time.
+func (t *StdioTransport) Close() error {
+	t.mu.Lock()
+	stdin := t.stdin
+	t.stdin = nil
+	t.mu.Unlock()
+	if stdin == nil {
+		return nil
+	}
+	stdin.Close()
+
+	exited := make(chan struct{})
+	go func() {
+		_ = t.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(_stdioCloseTimeout):
+		err := t.cmd.Process.Kill()
+		<-exited
+		return err
+	}
+}
+
+// SSETransport is a Transport connecting to a server over HTTP: the messages
+// of the server are received as server-sent events and the messages of the
+// client are posted to the endpoint given by the server.
+type SSETransport struct {
+	url        string
+	httpClient *http.Client
+	header     http.Header
+
+	endpoint string
+	cancel   context.CancelFunc
+}
+
+var _ Transport = &SSETransport{}
+
+// SSEOption is a function that configures an SSETransport.
+type SSEOption func(*SSETransport)
+
+// WithHTTPClient sets the HTTP client of the transport.
+func WithHTTPClient(client *http.Client) SSEOption {
+	return func(t *SSETransport) {
+		t.httpClient = client
+	}
+}
+
+// WithHeader adds a header to the requests, e.g. for authentication.
+func WithHeader(key, value string) SSEOption {
+	return func(t *SSETransport) {
+		t.header.Add(key, value)
+	}
+}
+
+// NewSSETransport returns a transport connecting to the SSE endpoint of a
+// server at the URL.
+func NewSSETransport(url string, opts ...SSEOption) *SSETransport {
+	t := &SSETransport{url: url, httpClient: http.DefaultClient, header: http.Header{}}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Start opens the event stream and waits for the endpoint of the server.
+func (t *SSETransport) Start(ctx context.Context) (<-chan []byte, error) {
+	// The stream outlives the context of the connection.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, t.url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	t.setHeaders(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("%w: GET %s: status %d", ErrRequestFailed, t.url, resp.StatusCode)
+	}
+
+	endpoints := make(chan string, 1)
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		defer resp.Body.Close()
+		readEvents(resp.Body, func(event, data string) {
+			switch event {
+			case "endpoint":
+				select {
+				case endpoints <- data:
+				default:
+				}
+			case "message", "":
+				select {
+				case messages <- []byte(data):
+				case <-streamCtx.Done():
+				}
+			}
+		})
+	}()
+
+	select {
+	case endpoint := <-endpoints:
+		base, err := url.Parse(t.url)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		ref, err := url.Parse(endpoint)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("%w: %w", ErrNoEndpoint, err)
+		}
+		t.endpoint = base.ResolveReference(ref).String()
+		t.cancel = cancel
+		return messages, nil
+	case <-messages:
+		cancel()
+		return nil, ErrNoEndpoint
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	}
+}
+
+// Send posts the message to the endpoint of the server.
+func (t *SSETransport) Send(ctx context.Context, message []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: POST %s: status %d: %s", ErrRequestFailed, t.endpoint, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// Close closes the event stream.
+func (t *SSETransport) Close() error {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	return nil
+}
+
+func (t *SSETransport) setHeaders(req *http.Request) {
+	for key, values := range t.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
+
+// readEvents reads server-sent events, calling dispatch with the type and
+// data of each event.
+func readEvents(r io.Reader, dispatch func(event, data string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20) //nolint:mnd
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				dispatch(event, strings.Join(data, "\n"))
+			}
+			event, data = "", nil
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
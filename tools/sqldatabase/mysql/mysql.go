@@ -16,7 +16,12 @@ func init() {
 	sqldatabase.RegisterEngine(EngineName, NewMySQL)
 }
 
-var _ sqldatabase.Engine = MySQL{}
+var _ sqldatabase.ReadOnlyEngine = MySQL{}
+
+// queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
 
 // MySQL is a MySQL engine.
 type MySQL struct {
@@ -42,7 +47,22 @@ func (m MySQL) Dialect() string {
 }
 
 func (m MySQL) Query(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
-	rows, err := m.db.QueryContext(ctx, query, args...)
+	return queryRows(ctx, m.db, query, args...)
+}
+
+// QueryReadOnly executes the query in a read-only transaction, which is rolled back.
+func (m MySQL) QueryReadOnly(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+	return queryRows(ctx, tx, query, args...)
+}
+
+// queryRows executes the query with q and returns the columns and results.
+func queryRows(ctx context.Context, q queryer, query string, args ...any) ([]string, [][]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -71,7 +91,7 @@ func (m MySQL) Query(ctx context.Context, query string, args ...any) ([]string,
 		}
 		results = append(results, row)
 	}
-	return cols, results, nil
+	return cols, results, rows.Err()
 }
 
 func (m MySQL) TableNames(ctx context.Context) ([]string, error) {
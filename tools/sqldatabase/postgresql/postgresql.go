@@ -18,7 +18,12 @@ func init() {
 	sqldatabase.RegisterEngine(EngineName, NewPostgreSQL)
 }
 
-var _ sqldatabase.Engine = PostgreSQL{}
+var _ sqldatabase.ReadOnlyEngine = PostgreSQL{}
+
+// queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
 
 // PostgreSQL represents the PostgreSQL engine.
 type PostgreSQL struct {
@@ -49,7 +54,22 @@ func (p PostgreSQL) Dialect() string {
 // It takes a context.Context, a query string, and optional query arguments.
 // It returns the column names, query results as a 2D slice of strings, and an error, if any.
 func (p PostgreSQL) Query(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
-	rows, err := p.db.QueryContext(ctx, query, args...)
+	return queryRows(ctx, p.db, query, args...)
+}
+
+// QueryReadOnly executes the query in a read-only transaction, which is rolled back.
+func (p PostgreSQL) QueryReadOnly(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+	return queryRows(ctx, tx, query, args...)
+}
+
+// queryRows executes the query with q and returns the columns and results.
+func queryRows(ctx context.Context, q queryer, query string, args ...any) ([]string, [][]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -78,7 +98,7 @@ func (p PostgreSQL) Query(ctx context.Context, query string, args ...any) ([]str
 		}
 		results = append(results, row)
 	}
-	return cols, results, nil
+	return cols, results, rows.Err()
 }
 
 // TableNames returns the names of all tables in the PostgreSQL database.
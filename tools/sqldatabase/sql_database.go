@@ -36,6 +36,16 @@ type Engine interface {
 	Close() error
 }
 
+// ReadOnlyEngine is implemented by the engines able to run queries without modifying the
+// database.
+type ReadOnlyEngine interface {
+	Engine
+
+	// QueryReadOnly executes the query in a read-only transaction, or with the connection
+	// being read-only, and returns the columns and results.
+	QueryReadOnly(ctx context.Context, query string, args ...any) (cols []string, results [][]string, err error)
+}
+
 var (
 	ErrUnknownDialect = fmt.Errorf("unknown dialect")
 
@@ -129,12 +139,31 @@ func (sd *SQLDatabase) Query(ctx context.Context, query string) (string, error)
 	if err != nil {
 		return "", err
 	}
+	return formatResults(cols, results), nil
+}
 
+// QueryReadOnly executes the query without modifying the database if the engine is a
+// ReadOnlyEngine, and like Query otherwise, and returns the string that contains columns and
+// results.
+func (sd *SQLDatabase) QueryReadOnly(ctx context.Context, query string) (string, error) {
+	engine, ok := sd.Engine.(ReadOnlyEngine)
+	if !ok {
+		return sd.Query(ctx, query)
+	}
+	cols, results, err := engine.QueryReadOnly(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	return formatResults(cols, results), nil
+}
+
+// formatResults returns the tab-separated columns and results, one row per line.
+func formatResults(cols []string, results [][]string) string {
 	str := strings.Join(cols, "\t") + "\n"
 	for _, row := range results {
 		str += strings.Join(row, "\t") + "\n"
 	}
-	return str, nil
+	return str
 }
 
 // Close closes the database.
@@ -15,7 +15,12 @@ func init() {
 	sqldatabase.RegisterEngine(EngineName, NewSQLite3)
 }
 
-var _ sqldatabase.Engine = SQLite3{}
+var _ sqldatabase.ReadOnlyEngine = SQLite3{}
+
+// queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
 
 // SQLite3 is a SQLite3 engine.
 type SQLite3 struct {
@@ -41,7 +46,26 @@ func (m SQLite3) Dialect() string {
 }
 
 func (m SQLite3) Query(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
-	rows, err := m.db.QueryContext(ctx, query, args...)
+	return queryRows(ctx, m.db, query, args...)
+}
+
+// QueryReadOnly executes the query with the connection being read-only (PRAGMA query_only).
+func (m SQLite3) QueryReadOnly(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, nil, err
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only = OFF") //nolint:errcheck
+	return queryRows(ctx, conn, query, args...)
+}
+
+// queryRows executes the query with q and returns the columns and results.
+func queryRows(ctx context.Context, q queryer, query string, args ...any) ([]string, [][]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -70,7 +94,7 @@ func (m SQLite3) Query(ctx context.Context, query string, args ...any) ([]string
 		}
 		results = append(results, row)
 	}
-	return cols, results, nil
+	return cols, results, rows.Err()
 }
 
 func (m SQLite3) TableNames(ctx context.Context) ([]string, error) {
@@ -59,3 +59,27 @@ func Test(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestQueryReadOnly(t *testing.T) {
+	t.Parallel()
+
+	dsn := "file:" + t.TempDir() + "/readonly.sqlite"
+	db, err := sqldatabase.NewSQLDatabaseWithDSN("sqlite3", dsn, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err = db.Query(ctx, "CREATE TABLE cards (name TEXT)")
+	require.NoError(t, err)
+	_, err = db.QueryReadOnly(ctx, "INSERT INTO cards VALUES ('ace') RETURNING name")
+	require.ErrorContains(t, err, "readonly")
+	_, err = db.QueryReadOnly(ctx, "DROP TABLE cards")
+	require.ErrorContains(t, err, "readonly")
+
+	// the connection is writable again after the read-only query.
+	_, err = db.Query(ctx, "INSERT INTO cards VALUES ('king') RETURNING name")
+	require.NoError(t, err)
+	result, err := db.QueryReadOnly(ctx, "SELECT name FROM cards")
+	require.NoError(t, err)
+	require.Equal(t, "name\nking\n", result)
+}
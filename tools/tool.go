@@ -8,3 +8,13 @@ type Tool interface {
 	Description() string
 	Call(ctx context.Context, input string) (string, error)
 }
+
+// StructuredTool is a tool with JSON schema parameters. Agents using native
+// tool calling advertise the parameters to the model and call the tool with
+// the JSON arguments of the model, instead of a single string input.
+type StructuredTool interface {
+	Tool
+	// Parameters returns the JSON schema of the parameters of the tool, e.g.
+	// a jsonschema.Definition.
+	Parameters() any
+}
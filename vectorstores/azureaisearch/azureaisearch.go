@@ -19,6 +19,8 @@ type Store struct {
 	azureAISearchAPIKey   string
 	embedder              embeddings.Embedder
 	client                *http.Client
+	metric                vectorstores.DistanceMetric
+	semanticConfiguration string
 }
 
 var (
@@ -55,7 +57,22 @@ func New(opts ...Option) (Store, error) {
 	return s, nil
 }
 
-var _ vectorstores.VectorStore = &Store{}
+const (
+	// _defaultBatchSize is the number of documents embedded and uploaded at a
+	// time.
+	_defaultBatchSize = 100
+	// _semanticRankerResults is the number of results reranked by the
+	// semantic ranker.
+	_semanticRankerResults = 50
+	// _maxRerankerScore is the maximum score of the semantic ranker.
+	_maxRerankerScore = 4
+)
+
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
 // and returns the ids of the added documents.
@@ -64,36 +81,69 @@ func (s *Store) AddDocuments(
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
-	opts := s.getOptions(options...)
-	ids := []string{}
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.UpsertDocuments(ctx, ids, docs, options...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
 
-	texts := []string{}
+// UpsertDocuments adds the text and metadata from the documents with the ids
+// to the index named by the name space of the options, replacing the
+// documents with the same ids. Documents are embedded and uploaded in batches
+// of 100 by default.
+func (s *Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.uploadDocuments(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
 
+func (s *Store) uploadDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
 	}
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return ids, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return ids, ErrNumberOfVectorDoesNotMatch
+		return ErrNumberOfVectorDoesNotMatch
+	}
+	dimensions, err := s.indexDimensions(ctx, opts.NameSpace)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+		return err
 	}
 	for i, doc := range docs {
-		id := uuid.NewString()
-		if err = s.UploadDocument(ctx, id, opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
-			return ids, err
+		if err = s.UploadDocument(ctx, ids[i], opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
+			return err
 		}
-		ids = append(ids, id)
 	}
-
-	return ids, nil
+	return nil
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents. Hybrid searches combine
+// the vector query with a full-text query of the content, fused by Azure AI
+// Search with Reciprocal Rank Fusion, and are reranked by the semantic
+// ranker of WithSemanticRanker.
 func (s *Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -101,20 +151,109 @@ func (s *Store) SimilaritySearch(
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return s.hybridSearch(ctx, query, *opts.HybridSearch, numDocuments, opts)
+	}
 
 	queryVector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
+	docs, err := s.search(ctx, opts, SearchDocumentsRequestInput{
+		VectorQueries: []VectorQuery{s.vectorQuery(queryVector, numDocuments)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].Score = s.score(docs[i].Score)
+	}
+	return filterScores(docs, opts.ScoreThreshold), nil
+}
+
+// hybridSearch searches both the content vectors and the content matching
+// the text, which defaults to the query. The weighted fusion runs both
+// queries and fuses their results, while Reciprocal Rank Fusion is a single
+// hybrid query.
+func (s *Store) hybridSearch(
+	ctx context.Context,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	opts vectorstores.Options,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+	text := hybrid.Text
+	if text == "" {
+		text = query
+	}
+	vector := hybrid.Vector
+	if vector == nil {
+		var err error
+		vector, err = s.embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if hybrid.Fusion == vectorstores.FusionWeighted {
+		if s.semanticConfiguration != "" {
+			return nil, fmt.Errorf("%w: the semantic ranker reranks results of Reciprocal Rank Fusion only",
+				vectorstores.ErrInvalidHybridSearch)
+		}
+		dense, err := s.search(ctx, opts, SearchDocumentsRequestInput{
+			VectorQueries: []VectorQuery{s.vectorQuery(vector, numDocuments)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches, err := s.search(ctx, opts, SearchDocumentsRequestInput{Search: text, Top: numDocuments})
+		if err != nil {
+			return nil, err
+		}
+		return hybrid.Fuse(dense, matches, numDocuments, opts.ScoreThreshold, nil), nil
+	}
+
 	payload := SearchDocumentsRequestInput{
-		Vectors: []SearchDocumentsRequestInputVector{{
-			Fields: "contentVector",
-			Value:  queryVector,
-			K:      numDocuments,
-		}},
+		Search:        text,
+		Top:           numDocuments,
+		VectorQueries: []VectorQuery{s.vectorQuery(vector, numDocuments)},
+	}
+	if s.semanticConfiguration != "" {
+		payload.QueryType = QueryTypeSemantic
+		payload.SemanticConfiguration = s.semanticConfiguration
+		// the semantic ranker reranks the 50 best results.
+		payload.VectorQueries[0].K = max(numDocuments, _semanticRankerResults)
+	}
+	docs, err := s.search(ctx, opts, payload)
+	if err != nil {
+		return nil, err
+	}
+	return filterScores(docs, opts.ScoreThreshold), nil
+}
+
+// vectorQuery returns the vector query of the k nearest content vectors.
+func (s *Store) vectorQuery(vector []float32, k int) VectorQuery {
+	return VectorQuery{
+		Kind:   VectorQueryKindVector,
+		Vector: vector,
+		Fields: "contentVector",
+		K:      k,
 	}
+}
 
+// search searches the documents of the index named by the name space of the
+// options, filtered by the filters of the options. The scores of the
+// documents are the search scores, or the reranker scores scaled to [0, 1]
+// for semantic queries.
+func (s *Store) search(
+	ctx context.Context,
+	opts vectorstores.Options,
+	payload SearchDocumentsRequestInput,
+) ([]schema.Document, error) {
 	if filter, ok := opts.Filters.(string); ok {
 		payload.Filter = filter
 	}
@@ -124,21 +263,44 @@ func (s *Store) SimilaritySearch(
 		return nil, err
 	}
 
-	output := []schema.Document{}
+	docs := make([]schema.Document, 0, len(searchResults.Value))
 	for _, searchResult := range searchResults.Value {
 		doc, err := assertResultValues(searchResult)
 		if err != nil {
-			return output, err
+			return nil, err
 		}
+		if payload.QueryType == QueryTypeSemantic {
+			rerankerScore, _ := searchResult["@search.rerankerScore"].(float64)
+			doc.Score = float32(rerankerScore / _maxRerankerScore)
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, nil
+}
 
-		if opts.ScoreThreshold > 0 && opts.ScoreThreshold > doc.Score {
+// filterScores returns the documents whose score is at least the score
+// threshold.
+func filterScores(docs []schema.Document, scoreThreshold float32) []schema.Document {
+	output := []schema.Document{}
+	for _, doc := range docs {
+		if scoreThreshold > 0 && scoreThreshold > doc.Score {
 			continue
 		}
-
-		output = append(output, *doc)
+		output = append(output, doc)
 	}
+	return output
+}
 
-	return output, nil
+// score converts the search score of a vector query, computed from the
+// distance of the metric, to a relevance score.
+func (s *Store) score(score float32) float32 {
+	switch s.metric { //nolint:exhaustive
+	case vectorstores.DistanceEuclidean:
+		return vectorstores.EuclideanDistanceScore(1/float64(score) - 1)
+	case vectorstores.DistanceDotProduct:
+		return vectorstores.DotProductScore(float64(score) - 1)
+	}
+	return vectorstores.CosineDistanceScore(1/float64(score) - 1)
 }
 
 func assertResultValues(searchResult map[string]interface{}) (*schema.Document, error) {
@@ -2,6 +2,9 @@ package azureaisearch_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -130,10 +133,10 @@ func TestAzureaiSearchStoreRestWithScoreThreshold(t *testing.T) {
 	}, vectorstores.WithNameSpace(indexName))
 	require.NoError(t, err)
 	time.Sleep(time.Second)
-	// test with a score threshold of 0.84, expected 6 documents
+	// test with a score threshold of 0.81, expected 6 documents
 	docs, err := storer.SimilaritySearch(context.Background(),
 		"Which of these are cities in Japan", 10,
-		vectorstores.WithScoreThreshold(0.84),
+		vectorstores.WithScoreThreshold(0.81),
 		vectorstores.WithNameSpace(indexName))
 	require.NoError(t, err)
 	require.Len(t, docs, 6)
@@ -217,7 +220,7 @@ func TestAzureaiSearchAsRetrieverWithScoreThreshold(t *testing.T) {
 			llm,
 			vectorstores.ToRetriever(&storer, 5,
 				vectorstores.WithNameSpace(indexName),
-				vectorstores.WithScoreThreshold(0.8)),
+				vectorstores.WithScoreThreshold(0.75)),
 		),
 		"What colors is each piece of furniture next to the desk?",
 	)
@@ -226,3 +229,220 @@ func TestAzureaiSearchAsRetrieverWithScoreThreshold(t *testing.T) {
 	require.Contains(t, result, "black", "expected black in result")
 	require.Contains(t, result, "beige", "expected beige in result")
 }
+
+func TestAzureaiSearchDeleteByFilter(t *testing.T) {
+	var deleted []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if strings.HasSuffix(r.URL.Path, "/docs/search") {
+			require.Equal(t, "city eq 'tokyo'", body["filter"])
+			require.Equal(t, "id", body["select"])
+			_, _ = w.Write([]byte(`{"value":[{"id":"1"},{"id":"2"}]}`))
+			return
+		}
+		require.Equal(t, "/indexes/cities/docs/index", r.URL.Path)
+		deleted = append(deleted, body["value"].([]any)...)
+	}))
+	defer server.Close()
+	t.Setenv(azureaisearch.EnvironmentVariableEndpoint, server.URL)
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+	store, err := azureaisearch.New(azureaisearch.WithEmbedder(e))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.DeleteByFilter(ctx, "city eq 'tokyo'", vectorstores.WithNameSpace("cities")))
+	require.Equal(t, []any{
+		map[string]any{"@search.action": "delete", "id": "1"},
+		map[string]any{"@search.action": "delete", "id": "2"},
+	}, deleted)
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, map[string]any{}), azureaisearch.ErrInvalidFilter)
+}
+
+func TestAzureaiSearchCollections(t *testing.T) {
+	var (
+		requests []string
+		created  map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"value":[{"name":"a"},{"name":"b"}]}`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv(azureaisearch.EnvironmentVariableEndpoint, server.URL)
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+	store, err := azureaisearch.New(azureaisearch.WithEmbedder(e))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.ErrorIs(t, store.CreateCollection(ctx, "docs"), vectorstores.ErrMissingDimensions)
+	require.NoError(t, store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(3),
+		vectorstores.WithDistanceMetric(vectorstores.DistanceDotProduct),
+		vectorstores.WithIndexParams(map[string]any{"m": 8})))
+	require.Equal(t, "docs", created["name"])
+	require.Equal(t, float64(3), created["fields"].([]any)[2].(map[string]any)["dimensions"])
+	algorithm := created["vectorSearch"].(map[string]any)["algorithms"].([]any)[0].(map[string]any)
+	require.Equal(t, "dotProduct", algorithm["hnswParameters"].(map[string]any)["metric"])
+	require.Equal(t, float64(8), algorithm["hnswParameters"].(map[string]any)["m"])
+
+	names, err := store.ListCollections(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+	require.NoError(t, store.DropCollection(ctx, "docs"))
+	require.Equal(t, []string{"PUT /indexes/docs", "GET /indexes", "DELETE /indexes/docs"}, requests)
+}
+
+func TestAzureaiSearchHybridSearch(t *testing.T) {
+	var searches []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/indexes/docs/docs/search", r.URL.Path)
+		require.Equal(t, "2024-07-01", r.URL.Query().Get("api-version"))
+		var search map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&search))
+		searches = append(searches, search)
+		_, _ = w.Write([]byte(`{"value":[
+			{"@search.score":0.03,"@search.rerankerScore":3.2,"content":"tokyo","metadata":"{\"country\":\"japan\"}"},
+			{"@search.score":0.02,"@search.rerankerScore":1.2,"content":"potato","metadata":"{}"}]}`))
+	}))
+	defer server.Close()
+	t.Setenv(azureaisearch.EnvironmentVariableEndpoint, server.URL)
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}))
+	require.NoError(t, err)
+	store, err := azureaisearch.New(azureaisearch.WithEmbedder(e), azureaisearch.WithSemanticRanker("semantic"))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "japan", 2,
+		vectorstores.WithNameSpace("docs"),
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{}),
+		vectorstores.WithScoreThreshold(0.5),
+		azureaisearch.WithFilters("lang eq 'en'"),
+	)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].PageContent)
+	require.InDelta(t, 0.8, docs[0].Score, 1e-6)
+	require.Equal(t, map[string]any{
+		"search":                "japan",
+		"top":                   float64(2),
+		"filter":                "lang eq 'en'",
+		"queryType":             "semantic",
+		"semanticConfiguration": "semantic",
+		"vectorQueries": []any{map[string]any{
+			"kind":   "vector",
+			"vector": []any{float64(1), float64(0)},
+			"fields": "contentVector",
+			"k":      float64(50),
+		}},
+	}, searches[0])
+
+	_, err = store.SimilaritySearch(context.Background(), "japan", 2,
+		vectorstores.WithNameSpace("docs"),
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{Fusion: vectorstores.FusionWeighted}),
+	)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+}
+
+func TestAzureaiSearchUpdateIndex(t *testing.T) {
+	var updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/indexes/docs", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"@odata.etag":"1","name":"docs","vectorSearch":{"profiles":[{"name":"default"}]},
+				"semantic":{"configurations":[{"name":"semantic"},{"name":"other"}]}}`))
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+		}
+	}))
+	defer server.Close()
+	t.Setenv(azureaisearch.EnvironmentVariableEndpoint, server.URL)
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+	store, err := azureaisearch.New(azureaisearch.WithEmbedder(e))
+	require.NoError(t, err)
+
+	require.NoError(t, store.UpdateIndex(context.Background(), "docs",
+		azureaisearch.WithSemanticConfiguration("semantic", "content", "metadata"),
+		azureaisearch.WithVectorCompression(azureaisearch.CompressionScalarQuantization),
+	))
+	require.NotContains(t, updated, "@odata.etag")
+	require.Equal(t, map[string]any{
+		"defaultConfiguration": "semantic",
+		"configurations": []any{
+			map[string]any{"name": "other"},
+			map[string]any{"name": "semantic", "prioritizedFields": map[string]any{
+				"prioritizedContentFields": []any{
+					map[string]any{"fieldName": "content"},
+					map[string]any{"fieldName": "metadata"},
+				},
+			}},
+		},
+	}, updated["semantic"])
+	require.Equal(t, map[string]any{
+		"profiles": []any{map[string]any{"name": "default", "compression": "default-compression"}},
+		"compressions": []any{map[string]any{
+			"name":                      "default-compression",
+			"kind":                      "scalarQuantization",
+			"rerankWithOriginalVectors": true,
+			"defaultOversampling":       float64(10),
+		}},
+	}, updated["vectorSearch"])
+}
+
+func TestAzureaiSearchDimensionMismatch(t *testing.T) {
+	var uploads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			require.Equal(t, "/indexes/docs", r.URL.Path)
+			_, _ = w.Write([]byte(`{"fields":[{"name":"id"},{"name":"contentVector","dimensions":3}]}`))
+			return
+		}
+		uploads++
+	}))
+	defer server.Close()
+	t.Setenv(azureaisearch.EnvironmentVariableEndpoint, server.URL)
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i, text := range texts {
+				vectors[i] = make([]float32, len(text))
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	store, err := azureaisearch.New(azureaisearch.WithEmbedder(e))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "abc"}}, vectorstores.WithNameSpace("docs"))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "ab"}}, vectorstores.WithNameSpace("docs"))
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+	require.Equal(t, 1, uploads)
+}
@@ -0,0 +1,92 @@
+package azureaisearch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var _ vectorstores.CollectionManager = &Store{}
+
+// _hnswMetrics maps distance metrics to the metrics of the HNSW algorithm.
+var _hnswMetrics = map[vectorstores.DistanceMetric]string{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     "cosine",
+	vectorstores.DistanceEuclidean:  "euclidean",
+	vectorstores.DistanceDotProduct: "dotProduct",
+}
+
+// CreateCollection creates an index like CreateIndex with the vector
+// dimensions and metric of the options. The index params override the HNSW
+// parameters of the index, e.g. {"m": 8}.
+func (s *Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	metric, ok := _hnswMetrics[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	return s.CreateIndex(ctx, name, func(indexMap *map[string]interface{}) {
+		index := *indexMap
+		fields, _ := index["fields"].([]map[string]interface{})
+		for _, field := range fields {
+			if field["name"] == "contentVector" {
+				field["dimensions"] = opts.Dimensions
+			}
+		}
+		vectorSearch, _ := index["vectorSearch"].(map[string]interface{})
+		algorithms, _ := vectorSearch["algorithms"].([]map[string]interface{})
+		for _, algorithm := range algorithms {
+			params, _ := algorithm["hnswParameters"].(map[string]interface{})
+			if params == nil {
+				continue
+			}
+			params["metric"] = metric
+			for key, value := range opts.IndexParams {
+				params[key] = value
+			}
+		}
+	})
+}
+
+// indexDimensions returns the dimensions of the contentVector field of the
+// index.
+func (s *Store) indexDimensions(ctx context.Context, indexName string) (int, error) {
+	index := map[string]interface{}{}
+	if err := s.RetrieveIndex(ctx, indexName, &index); err != nil {
+		return 0, err
+	}
+	fields, _ := index["fields"].([]interface{})
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		if field["name"] == "contentVector" {
+			dimensions, _ := field["dimensions"].(float64)
+			return int(dimensions), nil
+		}
+	}
+	return 0, nil
+}
+
+// DropCollection deletes an index and its documents.
+func (s *Store) DropCollection(ctx context.Context, name string) error {
+	return s.DeleteIndex(ctx, name)
+}
+
+// ListCollections returns the names of the indexes.
+func (s *Store) ListCollections(ctx context.Context) ([]string, error) {
+	var output struct {
+		Value []struct {
+			Name string `json:"name"`
+		} `json:"value"`
+	}
+	if err := s.listIndexes(ctx, &output); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(output.Value))
+	for i, index := range output.Value {
+		names[i] = index.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,107 @@
+package azureaisearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrInvalidFilter is returned when deleting documents with a filter that
+// isn't an OData filter expression.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// _maxBatchSize is the maximum number of documents of a request to the
+// azure AI search index documents API.
+const _maxBatchSize = 1000
+
+// DeleteByIDs deletes the documents with the ids from the index named by the
+// name space of the options.
+func (s *Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), _maxBatchSize)]
+		if err := s.DeleteDocumentsAPIRequest(ctx, opts.NameSpace, batch); err != nil {
+			return err
+		}
+		ids = ids[len(batch):]
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents matched by the filter, an OData filter
+// expression, from the index named by the name space of the options.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	expression, ok := filter.(string)
+	if !ok {
+		return fmt.Errorf("%w: filter must be an OData filter expression", ErrInvalidFilter)
+	}
+	if expression == "" {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+
+	// the ids are all collected first, as deleted documents may still be
+	// found until the index is refreshed.
+	var ids []string
+	for {
+		payload := SearchDocumentsRequestInput{
+			Filter: expression,
+			Select: "id",
+			Skip:   len(ids),
+			Top:    _maxBatchSize,
+		}
+		searchResults := SearchDocumentsRequestOuput{}
+		if err := s.SearchDocuments(ctx, opts.NameSpace, payload, &searchResults); err != nil {
+			return err
+		}
+		for _, result := range searchResults.Value {
+			if id, ok := result["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(searchResults.Value) < _maxBatchSize {
+			break
+		}
+	}
+	return s.DeleteByIDs(ctx, ids, options...)
+}
+
+// DeleteDocumentsAPIRequest makes a request to azure AI search to delete the
+// documents with the ids.
+func (s *Store) DeleteDocumentsAPIRequest(ctx context.Context, indexName string, ids []string) error {
+	URL := fmt.Sprintf("%s/indexes/%s/docs/index?api-version=2020-06-30", s.azureAISearchEndpoint, indexName)
+
+	documents := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		documents = append(documents, map[string]interface{}{
+			"@search.action": "delete",
+			"id":             id,
+		})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"value": documents,
+	})
+	if err != nil {
+		return fmt.Errorf("err marshalling body for azure ai search: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("err setting request for azure ai search delete documents: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	if s.azureAISearchAPIKey != "" {
+		req.Header.Add("api-key", s.azureAISearchAPIKey)
+	}
+
+	return s.httpDefaultSend(req, "azure ai search delete documents", nil)
+}
@@ -8,6 +8,15 @@ import (
 	"net/http"
 )
 
+const (
+	// _apiVersion is the version of the REST API of searches and indexes,
+	// the first supporting vector compression.
+	_apiVersion = "2024-07-01"
+	// _legacySearchAPIVersion is the version of the REST API of searches with
+	// the deprecated vectors parameter.
+	_legacySearchAPIVersion = "2023-07-01-Preview"
+)
+
 // QueryType pseudo enum for SearchDocumentsRequestInput queryType property.
 type QueryType string
 
@@ -34,6 +43,8 @@ const (
 )
 
 // SearchDocumentsRequestInput is the input struct to format a payload in order to search for a document.
+// Vectors are the vector queries of the 2023-07-01-Preview API version, which is used when they are set,
+// superseded by VectorQueries.
 type SearchDocumentsRequestInput struct {
 	Count                 bool                                `json:"count,omitempty"`
 	Captions              QueryCaptions                       `json:"captions,omitempty"`
@@ -59,6 +70,7 @@ type SearchDocumentsRequestInput struct {
 	Skip                  int                                 `json:"skip,omitempty"`
 	Top                   int                                 `json:"top,omitempty"`
 	Vectors               []SearchDocumentsRequestInputVector `json:"vectors,omitempty"`
+	VectorQueries         []VectorQuery                       `json:"vectorQueries,omitempty"`
 	VectorFilterMode      string                              `json:"vectorFilterMode,omitempty"`
 }
 
@@ -71,6 +83,21 @@ type SearchDocumentsRequestInputVector struct {
 	Exhaustive bool      `json:"exhaustive,omitempty"`
 }
 
+// VectorQueryKindVector is the kind of vector queries with a vector.
+const VectorQueryKindVector = "vector"
+
+// VectorQuery is a vector query of the search documents payload.
+type VectorQuery struct {
+	Kind       string    `json:"kind"`
+	Vector     []float32 `json:"vector,omitempty"`
+	Fields     string    `json:"fields,omitempty"`
+	K          int       `json:"k,omitempty"`
+	Exhaustive bool      `json:"exhaustive,omitempty"`
+	// Oversampling is the oversampling factor of the compressed vectors of
+	// fields with vector compression.
+	Oversampling float64 `json:"oversampling,omitempty"`
+}
+
 // SearchDocumentsRequestOuput is the output struct for search.
 type SearchDocumentsRequestOuput struct {
 	OdataCount   int `json:"@odata.count,omitempty"`
@@ -92,7 +119,11 @@ func (s *Store) SearchDocuments(
 	payload SearchDocumentsRequestInput,
 	output *SearchDocumentsRequestOuput,
 ) error {
-	URL := fmt.Sprintf("%s/indexes/%s/docs/search?api-version=2023-07-01-Preview", s.azureAISearchEndpoint, indexName)
+	apiVersion := _apiVersion
+	if len(payload.Vectors) > 0 {
+		apiVersion = _legacySearchAPIVersion
+	}
+	URL := fmt.Sprintf("%s/indexes/%s/docs/search?api-version=%s", s.azureAISearchEndpoint, indexName, apiVersion)
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("err marshalling document for azure ai search: %w", err)
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // IndexOption is used to customise the index when creating the index
@@ -17,6 +18,8 @@ const (
 	hnswParametersM              = 4
 	hnswParametersEfConstruction = 400
 	hnswParametersEfSearch       = 500
+	compressionName              = "default-compression"
+	compressionOversampling      = 10
 )
 
 // CreateIndex defines a default index (default one is made for text-embedding-ada-002)
@@ -84,9 +87,118 @@ func (s *Store) CreateIndex(ctx context.Context, indexName string, opts ...Index
 	return nil
 }
 
+// UpdateIndex updates the definition of an existing index with the options,
+// e.g. to add a semantic configuration. Changes to existing fields, such as
+// their vector compression, require to recreate the index.
+func (s *Store) UpdateIndex(ctx context.Context, indexName string, opts ...IndexOption) error {
+	index := map[string]interface{}{}
+	if err := s.RetrieveIndex(ctx, indexName, &index); err != nil {
+		return fmt.Errorf("error retrieving index: %w", err)
+	}
+	for key := range index {
+		if strings.HasPrefix(key, "@odata.") {
+			delete(index, key)
+		}
+	}
+
+	for _, indexOption := range opts {
+		indexOption(&index)
+	}
+
+	if err := s.CreateIndexAPIRequest(ctx, indexName, index); err != nil {
+		return fmt.Errorf("error updating index: %w", err)
+	}
+
+	return nil
+}
+
+// WithSemanticConfiguration is an IndexOption adding or replacing the
+// semantic configuration with the name, used by default by the semantic
+// ranker, whose prioritized content fields default to the content of the
+// documents.
+func WithSemanticConfiguration(name string, contentFields ...string) IndexOption {
+	if len(contentFields) == 0 {
+		contentFields = []string{"content"}
+	}
+	return func(indexMap *map[string]interface{}) {
+		fields := make([]map[string]interface{}, len(contentFields))
+		for i, field := range contentFields {
+			fields[i] = map[string]interface{}{"fieldName": field}
+		}
+		semantic, _ := (*indexMap)["semantic"].(map[string]interface{})
+		if semantic == nil {
+			semantic = map[string]interface{}{}
+			(*indexMap)["semantic"] = semantic
+		}
+		configurations := []map[string]interface{}{}
+		for _, configuration := range objects(semantic["configurations"]) {
+			if configuration["name"] != name {
+				configurations = append(configurations, configuration)
+			}
+		}
+		semantic["defaultConfiguration"] = name
+		semantic["configurations"] = append(configurations, map[string]interface{}{
+			"name": name,
+			"prioritizedFields": map[string]interface{}{
+				"prioritizedContentFields": fields,
+			},
+		})
+	}
+}
+
+// CompressionKind pseudo enum for the kind of vector compressions.
+type CompressionKind = string
+
+const (
+	// CompressionScalarQuantization quantizes vector components to int8.
+	CompressionScalarQuantization CompressionKind = "scalarQuantization"
+	// CompressionBinaryQuantization quantizes vector components to bits.
+	CompressionBinaryQuantization CompressionKind = "binaryQuantization"
+)
+
+// WithVectorCompression is an IndexOption compressing the content vectors
+// of the vector search profiles with the compression kind. Searches rerank
+// the nearest compressed vectors, oversampled 10 times, with the original
+// vectors.
+func WithVectorCompression(kind CompressionKind) IndexOption {
+	return func(indexMap *map[string]interface{}) {
+		vectorSearch, _ := (*indexMap)["vectorSearch"].(map[string]interface{})
+		if vectorSearch == nil {
+			return
+		}
+		vectorSearch["compressions"] = append(objects(vectorSearch["compressions"]), map[string]interface{}{
+			"name":                      compressionName,
+			"kind":                      kind,
+			"rerankWithOriginalVectors": true,
+			"defaultOversampling":       compressionOversampling,
+		})
+		for _, profile := range objects(vectorSearch["profiles"]) {
+			profile["compression"] = compressionName
+		}
+	}
+}
+
+// objects returns the objects of an array of an index definition, created by
+// IndexOptions or retrieved.
+func objects(array interface{}) []map[string]interface{} {
+	switch array := array.(type) {
+	case []map[string]interface{}:
+		return array
+	case []interface{}:
+		objects := make([]map[string]interface{}, 0, len(array))
+		for _, item := range array {
+			if object, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+		return objects
+	}
+	return nil
+}
+
 // CreateIndexAPIRequest send a request to azure AI search Rest API for creating an index.
 func (s *Store) CreateIndexAPIRequest(ctx context.Context, indexName string, payload any) error {
-	URL := fmt.Sprintf("%s/indexes/%s?api-version=2023-11-01", s.azureAISearchEndpoint, indexName)
+	URL := fmt.Sprintf("%s/indexes/%s?api-version=%s", s.azureAISearchEndpoint, indexName, _apiVersion)
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("err marshalling json: %w", err)
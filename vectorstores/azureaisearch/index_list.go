@@ -8,6 +8,10 @@ import (
 
 // ListIndexes send a request to azure AI search Rest API for creatin an index, helper function.
 func (s *Store) ListIndexes(ctx context.Context, output *map[string]interface{}) error {
+	return s.listIndexes(ctx, output)
+}
+
+func (s *Store) listIndexes(ctx context.Context, output any) error {
 	URL := fmt.Sprintf("%s/indexes?api-version=2023-11-01", s.azureAISearchEndpoint)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
@@ -2,6 +2,7 @@ package azureaisearch
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strings"
@@ -68,6 +69,27 @@ func WithAPIKey(azureAISearchAPIKey string) Option {
 	}
 }
 
+// WithDistanceMetric is an option for setting the metric of the vector
+// search profile of the indexes, converting the search scores of the
+// documents found to relevance scores. Defaults to
+// vectorstores.DistanceCosine, the metric of CreateIndex.
+func WithDistanceMetric(metric vectorstores.DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+// WithSemanticRanker is an option for reranking the results of hybrid
+// searches with the semantic ranker and the semantic configuration of the
+// index with the name, e.g. the one of WithSemanticConfiguration. The scores
+// of the documents are then the reranker scores, scaled from [0, 4] to
+// [0, 1].
+func WithSemanticRanker(configuration string) Option {
+	return func(s *Store) {
+		s.semanticConfiguration = configuration
+	}
+}
+
 func applyClientOptions(s *Store, opts ...Option) error {
 	for _, opt := range opts {
 		opt(s)
@@ -85,6 +107,13 @@ func applyClientOptions(s *Store, opts ...Option) error {
 		return ErrMissingEmbedded
 	}
 
+	if s.metric == "" {
+		s.metric = vectorstores.DistanceCosine
+	}
+	if _, ok := _hnswMetrics[s.metric]; !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, s.metric)
+	}
+
 	if envVariableAPIKey := os.Getenv(EnvironmentVariableAPIKey); envVariableAPIKey != "" {
 		s.azureAISearchAPIKey = envVariableAPIKey
 	}
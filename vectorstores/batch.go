@@ -0,0 +1,155 @@
+package vectorstores
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// DefaultBatchSize is the number of documents of a batch of
+	// AddDocumentsInBatches when no batch size is given.
+	DefaultBatchSize = 100
+
+	defaultRetryBackoff = time.Second
+)
+
+// ProgressFunc is called with the number of documents added so far and the
+// total number of documents.
+type ProgressFunc func(done, total int)
+
+// BatchFunc processes the documents from start to end of a batch.
+type BatchFunc func(ctx context.Context, start, end int) error
+
+// RunBatches splits n documents into batches of the batch size of opts, or
+// of defaultBatchSize if it isn't set, and calls fn for each batch with a
+// pool of opts.Concurrency workers. Failed batches are retried
+// opts.MaxRetries times, waiting opts.RetryBackoff before the first retry
+// and doubling it afterwards, and opts.Progress is called after each batch.
+// Stores use it to add documents in batches suited to their API.
+func RunBatches(ctx context.Context, opts Options, n, defaultBatchSize int, fn BatchFunc) error {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if batchSize <= 0 {
+		batchSize = n
+	}
+	if n == 0 {
+		return nil
+	}
+	numBatches := (n + batchSize - 1) / batchSize
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	for range max(1, min(opts.Concurrency, numBatches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range jobs {
+				end := min(start+batchSize, n)
+				err := runBatch(ctx, opts, start, end, fn)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				done += end - start
+				if opts.Progress != nil {
+					opts.Progress(done, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+loop:
+	for start := 0; start < n; start += batchSize {
+		select {
+		case jobs <- start:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func runBatch(ctx context.Context, opts Options, start, end int, fn BatchFunc) error {
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx, start, end)
+		if err == nil || attempt >= opts.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// AddDocumentsInBatches adds documents to a store in batches with the
+// batching options, for stores which don't batch documents themselves. It
+// returns the ids of the documents in the order of the documents.
+func AddDocumentsInBatches(
+	ctx context.Context,
+	store VectorStore,
+	docs []schema.Document,
+	options ...Option,
+) ([]string, error) {
+	opts := Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	ids := make([][]string, len(docs))
+	// batches are added as a whole by the store.
+	batchOptions := make([]Option, 0, len(options)+4) //nolint:mnd
+	batchOptions = append(batchOptions, options...)
+	batchOptions = append(batchOptions, WithBatchSize(len(docs)), WithConcurrency(1), WithRetries(0, 0), WithProgress(nil))
+	err := RunBatches(ctx, opts, len(docs), DefaultBatchSize, func(ctx context.Context, start, end int) error {
+		batchIDs, err := store.AddDocuments(ctx, docs[start:end], batchOptions...)
+		if err != nil {
+			return err
+		}
+		ids[start] = batchIDs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(docs))
+	for _, batchIDs := range ids {
+		result = append(result, batchIDs...)
+	}
+	return result, nil
+}
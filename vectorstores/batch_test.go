@@ -0,0 +1,107 @@
+package vectorstores_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestRunBatches(t *testing.T) {
+	t.Parallel()
+	var (
+		mu       sync.Mutex
+		batches  [][2]int
+		progress []int
+		failed   bool
+	)
+	opts := vectorstores.Options{}
+	for _, opt := range []vectorstores.Option{
+		vectorstores.WithBatchSize(4),
+		vectorstores.WithConcurrency(3),
+		vectorstores.WithRetries(1, time.Millisecond),
+		vectorstores.WithProgress(func(done, _ int) { progress = append(progress, done) }),
+	} {
+		opt(&opts)
+	}
+	err := vectorstores.RunBatches(context.Background(), opts, 10, 100, func(_ context.Context, start, end int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if start == 4 && !failed {
+			failed = true
+			return errors.New("transient")
+		}
+		batches = append(batches, [2]int{start, end})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, [][2]int{{0, 4}, {4, 8}, {8, 10}}, batches)
+	assert.Len(t, progress, 3)
+	assert.Equal(t, 10, progress[2])
+}
+
+func TestRunBatchesError(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	errBatch := errors.New("batch failed")
+	opts := vectorstores.Options{MaxRetries: 2, RetryBackoff: time.Millisecond}
+	err := vectorstores.RunBatches(context.Background(), opts, 5, 10, func(context.Context, int, int) error {
+		calls++
+		return errBatch
+	})
+	require.ErrorIs(t, err, errBatch)
+	assert.Equal(t, 3, calls)
+}
+
+type batchStore struct {
+	vectorstores.VectorStore
+	mu      sync.Mutex
+	batches []int
+}
+
+func (s *batchStore) AddDocuments(
+	_ context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Progress != nil || opts.Concurrency != 1 {
+		return nil, errors.New("unexpected batch options")
+	}
+	s.mu.Lock()
+	s.batches = append(s.batches, len(docs))
+	s.mu.Unlock()
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = "id-" + doc.PageContent
+	}
+	return ids, nil
+}
+
+func TestAddDocumentsInBatches(t *testing.T) {
+	t.Parallel()
+	docs := make([]schema.Document, 250)
+	for i := range docs {
+		docs[i].PageContent = fmt.Sprint(i)
+	}
+	store := &batchStore{}
+	var done int
+	ids, err := vectorstores.AddDocumentsInBatches(context.Background(), store, docs,
+		vectorstores.WithConcurrency(2), vectorstores.WithProgress(func(d, _ int) { done = d }))
+	require.NoError(t, err)
+	require.Len(t, ids, 250)
+	assert.Equal(t, "id-0", ids[0])
+	assert.Equal(t, "id-249", ids[249])
+	assert.ElementsMatch(t, []int{100, 100, 50}, store.batches)
+	assert.Equal(t, 250, done)
+}
@@ -0,0 +1,389 @@
+package cassandra
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+	ErrMissingDimensions          = errors.New("vector dimensions are unknown, set them with WithVectorDimensions")
+)
+
+// Store is a wrapper around a Cassandra table with a vector column indexed
+// by a Storage-Attached Index.
+type Store struct {
+	session          *gocql.Session
+	embedder         embeddings.Embedder
+	keyspace         string
+	tableName        string
+	dimensions       int
+	similarity       SimilarityFunction
+	defaultPartition string
+	skipProvisioning bool
+}
+
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
+
+// New creates a new Store with options, creating its table and indexes
+// unless WithSkipProvisioning is given.
+func New(ctx context.Context, opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.dimensions == 0 {
+		s.dimensions = embeddings.Dimensions(s.embedder)
+	}
+	if s.skipProvisioning {
+		return s, nil
+	}
+	if s.dimensions == 0 {
+		return nil, ErrMissingDimensions
+	}
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Migrate creates the table and its SAI indexes, on the vector column and
+// on the metadata entries used for filtering, if they don't exist.
+func (s *Store) Migrate(ctx context.Context) error {
+	for _, stmt := range s.schemaStatements() {
+		if err := s.session.Query(stmt).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("provision table: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) table() string {
+	return s.keyspace + "." + s.tableName
+}
+
+func (s *Store) schemaStatements() []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	partition_id text,
+	row_id text,
+	body_blob text,
+	metadata_blob text,
+	metadata_s map<text, text>,
+	vector vector<float, %d>,
+	PRIMARY KEY (partition_id, row_id)
+)`, s.table(), s.dimensions),
+		fmt.Sprintf(`CREATE CUSTOM INDEX IF NOT EXISTS %s_vector_idx ON %s (vector)
+USING 'StorageAttachedIndex' WITH OPTIONS = {'similarity_function': '%s'}`,
+			s.tableName, s.table(), s.similarity),
+		fmt.Sprintf(`CREATE CUSTOM INDEX IF NOT EXISTS %s_metadata_idx ON %s (entries(metadata_s))
+USING 'StorageAttachedIndex'`, s.tableName, s.table()),
+	}
+}
+
+// AddDocuments adds documents to the partition of the name space, or to the
+// default partition, and returns their ids.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.addDocuments(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids to the partition of the name
+// space, or to the default partition, replacing the documents with the same
+// ids.
+func (s *Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.addDocuments(ctx, ids, docs, opts)
+}
+
+// addDocuments embeds the documents and inserts them with the ids, which
+// replaces the rows with the same ids, in batches of 100 by default.
+func (s *Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.insertDocuments(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s *Store) insertDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, s.dimensions); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf(`INSERT INTO %s (partition_id, row_id, body_blob, metadata_blob, metadata_s, vector)
+VALUES (?, ?, ?, ?, ?, ?)`, s.table())
+	partition := s.partition(opts)
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		err = s.session.Query(stmt, partition, ids[i], doc.PageContent, string(metadata),
+			stringMetadata(doc.Metadata), cqlVector(vectors[i])).WithContext(ctx).Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteByIDs deletes the documents with the ids from the partition of the
+// name space, or from the default partition.
+func (s *Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE partition_id = ? AND row_id IN ?`, s.table())
+	return s.session.Query(stmt, s.partition(opts), ids).WithContext(ctx).Exec()
+}
+
+// DeleteByFilter deletes the documents of the partition of the name space, or
+// of the default partition, with the metadata values of the filter, a map of
+// metadata keys to values compared as strings.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	filters, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if len(filters) == 0 {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+
+	// rows can only be deleted by primary key, so the matching ids are
+	// selected first.
+	where, args := filterConditions(filters)
+	stmt := fmt.Sprintf(`SELECT row_id FROM %s WHERE partition_id = ? AND %s`, s.table(), strings.Join(where, " AND "))
+	scanner := s.session.Query(stmt, append([]any{s.partition(opts)}, args...)...).WithContext(ctx).Iter().Scanner()
+	var ids []string
+	for scanner.Next() {
+		var id string
+		if err := scanner.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return s.DeleteByIDs(ctx, ids, options...)
+}
+
+// stringMetadata returns the metadata values as strings, as indexed for
+// filtering.
+func stringMetadata(metadata map[string]any) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		result[key] = fmt.Sprint(value)
+	}
+	return result
+}
+
+// SimilaritySearch returns the documents of the partition of the name space
+// nearest to the query. Filters are a map of metadata keys to values the
+// documents must have, compared as strings.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by cassandra", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := vectorstores.CheckDimensions([][]float32{vector}, s.dimensions); err != nil {
+		return nil, err
+	}
+
+	stmt, args := s.searchQuery(s.partition(opts), vector, numDocuments, filters)
+	scanner := s.session.Query(stmt, args...).WithContext(ctx).Iter().Scanner()
+	docs := make([]schema.Document, 0, numDocuments)
+	for scanner.Next() {
+		var doc schema.Document
+		var metadata string
+		var similarity float32
+		if err := scanner.Scan(&doc.PageContent, &metadata, &similarity); err != nil {
+			return nil, err
+		}
+		doc.Score = s.score(similarity)
+		if opts.ScoreThreshold != 0 && doc.Score < opts.ScoreThreshold {
+			continue
+		}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, scanner.Err()
+}
+
+// score returns the score of a similarity of the vector index, which
+// Cassandra scales to [0, 1]: similarity_cosine and similarity_dot_product
+// are (1 + similarity) / 2, and similarity_euclidean is
+// 1 / (1 + squared distance).
+func (s *Store) score(similarity float32) float32 {
+	if s.similarity == SimilarityEuclidean {
+		return vectorstores.SquaredEuclideanDistanceScore(1/float64(similarity) - 1)
+	}
+	return vectorstores.CosineSimilarityScore(2*float64(similarity) - 1) //nolint:mnd
+}
+
+func (s *Store) searchQuery(partition string, vector []float32, numDocuments int, filters map[string]any) (string, []any) { //nolint:lll
+	conditions, filterArgs := filterConditions(filters)
+	where := append([]string{"partition_id = ?"}, conditions...)
+	args := append([]any{cqlVector(vector), partition}, filterArgs...)
+	args = append(args, cqlVector(vector), numDocuments)
+
+	stmt := fmt.Sprintf(`SELECT body_blob, metadata_blob, similarity_%s(vector, ?)
+FROM %s
+WHERE %s
+ORDER BY vector ANN OF ?
+LIMIT ?`, s.similarity, s.table(), strings.Join(where, " AND "))
+	return stmt, args
+}
+
+// cqlVector is a vector marshaled as a CQL vector<float, n>, which the gocql
+// driver doesn't support: the big-endian float32 values, concatenated.
+type cqlVector []float32
+
+var _ gocql.Marshaler = cqlVector(nil)
+
+// MarshalCQL implements the gocql.Marshaler interface.
+func (v cqlVector) MarshalCQL(gocql.TypeInfo) ([]byte, error) {
+	data := make([]byte, 4*len(v)) //nolint:mnd
+	for i, f := range v {
+		binary.BigEndian.PutUint32(data[4*i:], math.Float32bits(f))
+	}
+	return data, nil
+}
+
+// filterConditions returns the conditions on the indexed metadata of the
+// filters, sorted by key, and their arguments.
+func filterConditions(filters map[string]any) ([]string, []any) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	where := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys))
+	for _, key := range keys {
+		where = append(where, fmt.Sprintf("metadata_s['%s'] = ?", strings.ReplaceAll(key, "'", "''")))
+		args = append(args, fmt.Sprint(filters[key]))
+	}
+	return where, args
+}
+
+func (s *Store) partition(opts vectorstores.Options) string {
+	if opts.NameSpace != "" {
+		return opts.NameSpace
+	}
+	return s.defaultPartition
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s *Store) getFilters(opts vectorstores.Options) (map[string]any, error) {
+	if opts.Filters == nil {
+		return nil, nil
+	}
+	filters, ok := opts.Filters.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidFilters
+	}
+	return filters, nil
+}
+
+func (s *Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
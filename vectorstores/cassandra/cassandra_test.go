@@ -0,0 +1,166 @@
+package cassandra
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeEmbedder embeds texts by the counts of the letters a, b and c.
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = embed(text)
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return embed(text), nil
+}
+
+func embed(text string) []float32 {
+	vector := make([]float32, 3)
+	for _, r := range text {
+		if r >= 'a' && r <= 'c' {
+			vector[r-'a']++
+		}
+	}
+	return vector
+}
+
+// getSession returns a session to the Cassandra cluster of CASSANDRA_HOST,
+// or to a Cassandra 5 container.
+func getSession(t *testing.T) *gocql.Session {
+	t.Helper()
+	ctx := context.Background()
+	host := os.Getenv("CASSANDRA_HOST")
+	if host == "" {
+		container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        "docker.io/cassandra:5.0",
+				ExposedPorts: []string{"9042/tcp"},
+				Env:          map[string]string{"MAX_HEAP_SIZE": "512M", "HEAP_NEWSIZE": "128M"},
+				WaitingFor: wait.ForLog("Starting listening for CQL clients").
+					WithStartupTimeout(3 * time.Minute),
+			},
+			Started: true,
+		})
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, container.Terminate(context.Background()))
+		})
+		host, err = container.PortEndpoint(ctx, "9042/tcp", "")
+		require.NoError(t, err)
+	}
+
+	cluster := gocql.NewCluster(host)
+	cluster.Timeout = 30 * time.Second
+	session, err := cluster.CreateSession()
+	require.NoError(t, err)
+	t.Cleanup(session.Close)
+	err = session.Query(`CREATE KEYSPACE IF NOT EXISTS langchaingo
+WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}`).Exec()
+	require.NoError(t, err)
+	return session
+}
+
+func TestCassandraStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := New(ctx,
+		WithSession(getSession(t)),
+		WithEmbedder(fakeEmbedder{}),
+		WithKeyspace("langchaingo"),
+		WithTableName("docs"),
+		WithVectorDimensions(3),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "aaa", Metadata: map[string]any{"kind": "a"}},
+		{PageContent: "bbb", Metadata: map[string]any{"kind": "b"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "a", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "aaa", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"kind": "a"}, docs[0].Metadata)
+	assert.InDelta(t, 1, docs[0].Score, 1e-5)
+}
+
+func TestSchemaStatements(t *testing.T) {
+	t.Parallel()
+	s := &Store{keyspace: "ks", tableName: "docs", dimensions: 3, similarity: SimilarityDotProduct}
+	stmts := s.schemaStatements()
+	require.Len(t, stmts, 3)
+	assert.Contains(t, stmts[0], "CREATE TABLE IF NOT EXISTS ks.docs")
+	assert.Contains(t, stmts[0], "vector vector<float, 3>")
+	assert.Contains(t, stmts[0], "PRIMARY KEY (partition_id, row_id)")
+	assert.Contains(t, stmts[1], "'similarity_function': 'dot_product'")
+	assert.Contains(t, stmts[2], "entries(metadata_s)")
+}
+
+func TestSearchQuery(t *testing.T) {
+	t.Parallel()
+	s := &Store{keyspace: "ks", tableName: "docs", similarity: SimilarityCosine}
+	vector := []float32{1, 0}
+	stmt, args := s.searchQuery("tenant", vector, 5, map[string]any{"year": 2024, "it's": "x"})
+	assert.Contains(t, stmt, "SELECT body_blob, metadata_blob, similarity_cosine(vector, ?)")
+	assert.Contains(t, stmt, "WHERE partition_id = ? AND metadata_s['it''s'] = ? AND metadata_s['year'] = ?")
+	assert.True(t, strings.HasSuffix(stmt, "ORDER BY vector ANN OF ?\nLIMIT ?"))
+	assert.Equal(t, []any{cqlVector(vector), "tenant", "x", "2024", cqlVector(vector), 5}, args)
+}
+
+func TestCQLVector(t *testing.T) {
+	t.Parallel()
+	data, err := cqlVector{1, -2.5}.MarshalCQL(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x3f, 0x80, 0, 0, 0xc0, 0x20, 0, 0}, data)
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+	s := &Store{defaultPartition: DefaultPartition}
+	assert.Equal(t, DefaultPartition, s.partition(vectorstores.Options{}))
+	assert.Equal(t, "tenant", s.partition(vectorstores.Options{NameSpace: "tenant"}))
+}
+
+func TestDeleteAndUpsertValidation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := &Store{defaultPartition: DefaultPartition}
+	require.ErrorIs(t, s.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, s.DeleteByFilter(ctx, map[string]any{}), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, s.DeleteByFilter(ctx, "year = 2024"), ErrInvalidFilters)
+	require.NoError(t, s.DeleteByIDs(ctx, nil))
+
+	err := s.UpsertDocuments(ctx, []string{"a"}, nil)
+	require.ErrorIs(t, err, vectorstores.ErrIDsMismatch)
+	err = s.UpsertDocuments(ctx, nil, nil, vectorstores.WithScoreThreshold(0.5))
+	require.ErrorIs(t, err, ErrUnsupportedOptions)
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+	_, err := applyClientOptions()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	assert.Equal(t, map[string]string{"a": "1", "b": "true"}, stringMetadata(map[string]any{"a": 1, "b": true}))
+}
@@ -0,0 +1,12 @@
+// Package cassandra contains an implementation of the VectorStore
+// interface using the vector search of Apache Cassandra 5 and DataStax
+// Astra DB, with Storage-Attached Indexes (SAI) and CQL ANN queries.
+//
+// The store takes a *gocql.Session. To connect to Astra DB, create the
+// session with the secure connect bundle of the database, e.g. with
+// github.com/datastax/gocql-astra.
+//
+// Documents are partitioned by name space, given with
+// vectorstores.WithNameSpace, so that searches of a tenant only read its
+// own partition.
+package cassandra
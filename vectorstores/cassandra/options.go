@@ -0,0 +1,124 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultTableName is the default name of the table storing documents.
+	DefaultTableName = "langchaingo_documents"
+	// DefaultPartition is the partition of documents added without a name
+	// space.
+	DefaultPartition = "default"
+
+	// _defaultBatchSize is the number of documents embedded and inserted at
+	// a time.
+	_defaultBatchSize = 100
+)
+
+// SimilarityFunction is the similarity function of the vector index.
+type SimilarityFunction string
+
+const (
+	SimilarityCosine     SimilarityFunction = "cosine"
+	SimilarityDotProduct SimilarityFunction = "dot_product"
+	SimilarityEuclidean  SimilarityFunction = "euclidean"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithSession is an option for specifying the Cassandra session. Must be set.
+func WithSession(session *gocql.Session) Option {
+	return func(s *Store) {
+		s.session = session
+	}
+}
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithKeyspace is an option for specifying the keyspace of the table. Must
+// be set.
+func WithKeyspace(keyspace string) Option {
+	return func(s *Store) {
+		s.keyspace = keyspace
+	}
+}
+
+// WithTableName is an option for specifying the table name.
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.tableName = name
+	}
+}
+
+// WithVectorDimensions is an option for specifying the vector size used to
+// create the table. Defaults to the dimensions of the embedder.
+func WithVectorDimensions(size int) Option {
+	return func(s *Store) {
+		s.dimensions = size
+	}
+}
+
+// WithSimilarityFunction is an option for specifying the similarity function
+// of the vector index. Defaults to SimilarityCosine.
+func WithSimilarityFunction(similarity SimilarityFunction) Option {
+	return func(s *Store) {
+		s.similarity = similarity
+	}
+}
+
+// WithDefaultPartition is an option for specifying the partition used when
+// no name space is given.
+func WithDefaultPartition(partition string) Option {
+	return func(s *Store) {
+		s.defaultPartition = partition
+	}
+}
+
+// WithSkipProvisioning is an option for not creating the table and its
+// indexes, e.g. when they are managed by migrations.
+func WithSkipProvisioning() Option {
+	return func(s *Store) {
+		s.skipProvisioning = true
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		tableName:        DefaultTableName,
+		similarity:       SimilarityCosine,
+		defaultPartition: DefaultPartition,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.session == nil {
+		return nil, fmt.Errorf("%w: missing cassandra session", ErrInvalidOptions)
+	}
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.keyspace == "" {
+		return nil, fmt.Errorf("%w: missing keyspace", ErrInvalidOptions)
+	}
+	switch s.similarity {
+	case SimilarityCosine, SimilarityDotProduct, SimilarityEuclidean:
+	default:
+		return nil, fmt.Errorf("%w: unsupported similarity function %q", ErrInvalidOptions, s.similarity)
+	}
+	return s, nil
+}
@@ -21,6 +21,7 @@ var (
 	ErrNewClient                = errors.New("error creating collection")
 	ErrAddDocument              = errors.New("error adding document")
 	ErrRemoveCollection         = errors.New("error resetting collection")
+	ErrDeleteDocuments          = errors.New("error deleting documents")
 	ErrUnsupportedOptions       = errors.New("unsupported options")
 )
 
@@ -39,7 +40,17 @@ type Store struct {
 	includes     []chromatypes.QueryEnum
 }
 
-var _ vectorstores.VectorStore = Store{}
+// _defaultBatchSize is the number of documents added at a time, which the
+// collection embeds with its embedding function.
+const _defaultBatchSize = 500
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+
+	_ vectorstores.CollectionManager = Store{}
+)
 
 // New creates an active client connection to the (specified, or default) collection in the Chroma server
 // and returns the `Store` object needed by the other accessors.
@@ -88,6 +99,9 @@ func New(opts ...Option) (Store, error) {
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
 // and returns the ids of the added documents.
+// The documents are embedded by the Chroma client, so vectors of the wrong
+// dimension are rejected by Chroma rather than with
+// vectorstores.ErrDimensionMismatch.
 func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
@@ -103,10 +117,91 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	ids := make([]string, len(docs))
+	for docIdx := range docs {
+		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
+	}
+	texts, metadatas := s.documents(nameSpace, docs)
+
+	col := s.collection
+	err := vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		if _, addErr := col.Add(ctx, nil, metadatas[start:end], texts[start:end], ids[start:end]); addErr != nil {
+			return fmt.Errorf("%w: %w", ErrAddDocument, addErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds the text and metadata from the documents with the ids
+// to the Chroma collection associated with 'Store', replacing the documents
+// with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	opts := s.getOptions(options...)
+	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	if nameSpace != "" && s.nameSpaceKey == "" {
+		return fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
+	}
+
+	texts, metadatas := s.documents(nameSpace, docs)
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		_, upsertErr := s.collection.Upsert(ctx, nil, metadatas[start:end], texts[start:end], ids[start:end])
+		if upsertErr != nil {
+			return fmt.Errorf("%w: %w", ErrAddDocument, upsertErr)
+		}
+		return nil
+	})
+}
+
+// DeleteByIDs deletes the documents with the ids from the Chroma collection
+// associated with 'Store'.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := s.collection.Delete(ctx, ids, nil, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocuments, err)
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents of the name space whose metadata is
+// matched by the filter, a Chroma where filter, from the Chroma collection
+// associated with 'Store'.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	if _, ok := filter.(map[string]any); !ok {
+		return fmt.Errorf("%w: filter must be a map", ErrUnsupportedOptions)
+	}
+	opts := s.getOptions(options...)
+	opts.Filters = filter
+	if _, err := s.collection.Delete(ctx, nil, s.getNamespacedFilter(opts), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocuments, err)
+	}
+	return nil
+}
+
+// documents returns the texts and the metadatas of the documents, with the
+// name space if any.
+func (s Store) documents(nameSpace string, docs []schema.Document) ([]string, []map[string]any) {
 	texts := make([]string, len(docs))
 	metadatas := make([]map[string]any, len(docs))
 	for docIdx, doc := range docs {
-		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
 		texts[docIdx] = doc.PageContent
 		mc := make(map[string]any, 0)
 		maps.Copy(mc, doc.Metadata)
@@ -115,18 +210,16 @@ func (s Store) AddDocuments(ctx context.Context,
 			metadatas[docIdx][s.nameSpaceKey] = nameSpace
 		}
 	}
-
-	col := s.collection
-	if _, addErr := col.Add(ctx, nil, metadatas, texts, ids); addErr != nil {
-		return nil, fmt.Errorf("%w: %w", ErrAddDocument, addErr)
-	}
-	return ids, nil
+	return texts, metadatas
 }
 
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by chroma", vectorstores.ErrInvalidHybridSearch)
+	}
 
 	if opts.Embedder != nil {
 		// embedder is not used by this method, so shouldn't ever be specified
@@ -151,7 +244,7 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	var sDocs []schema.Document
 	for docsI := range qr.Documents {
 		for docI := range qr.Documents[docsI] {
-			if score := 1.0 - qr.Distances[docsI][docI]; score >= scoreThreshold {
+			if score := s.score(qr.Distances[docsI][docI]); score >= scoreThreshold {
 				sDocs = append(sDocs, schema.Document{
 					Metadata:    qr.Metadatas[docsI][docI],
 					PageContent: qr.Documents[docsI][docI],
@@ -164,6 +257,15 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	return sDocs, nil
 }
 
+// score returns the score of a distance of the distance function: L2
+// distances are squared, and IP distances are 1 minus the inner product.
+func (s Store) score(distance float32) float32 {
+	if s.distanceFunction == chromatypes.L2 {
+		return vectorstores.SquaredEuclideanDistanceScore(float64(distance))
+	}
+	return vectorstores.CosineDistanceScore(float64(distance))
+}
+
 func (s Store) RemoveCollection() error {
 	if s.client == nil || s.collection == nil {
 		return fmt.Errorf("%w: no collection", ErrRemoveCollection)
@@ -175,6 +277,59 @@ func (s Store) RemoveCollection() error {
 	return nil
 }
 
+// _distanceFunctions maps distance metrics to the distance functions of
+// Chroma.
+var _distanceFunctions = map[vectorstores.DistanceMetric]chromatypes.DistanceFunction{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     chromatypes.COSINE,
+	vectorstores.DistanceEuclidean:  chromatypes.L2,
+	vectorstores.DistanceDotProduct: chromatypes.IP,
+}
+
+// CreateCollection creates a collection embedding documents like the
+// collection of the store. Chroma infers the vector dimensions from the first
+// documents added. The index params are added to the metadata of the
+// collection, e.g. {"hnsw:M": 32}.
+func (s Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts := vectorstores.CollectionOptions{Metric: vectorstores.DistanceCosine}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	distanceFunction, ok := _distanceFunctions[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	metadata := map[string]any{}
+	for key, value := range opts.IndexParams {
+		metadata[key] = value
+	}
+	_, err := s.client.CreateCollection(ctx, name, metadata, false, s.collection.EmbeddingFunction, distanceFunction)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNewClient, err)
+	}
+	return nil
+}
+
+// DropCollection deletes a collection and its documents.
+func (s Store) DropCollection(ctx context.Context, name string) error {
+	if _, err := s.client.DeleteCollection(ctx, name); err != nil {
+		return fmt.Errorf("%w(%s): %w", ErrRemoveCollection, name, err)
+	}
+	return nil
+}
+
+// ListCollections returns the names of the collections.
+func (s Store) ListCollections(ctx context.Context) ([]string, error) {
+	collections, err := s.client.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(collections))
+	for i, collection := range collections {
+		names[i] = collection.Name
+	}
+	return names, nil
+}
+
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	opts := vectorstores.Options{}
 	for _, opt := range options {
@@ -0,0 +1,94 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+var (
+	// ErrUnsupportedDistanceMetric is returned when a store doesn't support
+	// the distance metric of a collection.
+	ErrUnsupportedDistanceMetric = errors.New("unsupported distance metric")
+	// ErrMissingDimensions is returned when creating a collection whose
+	// vector dimensions are neither given nor known from the embedder.
+	ErrMissingDimensions = errors.New("vector dimensions of the collection are unknown")
+)
+
+// DistanceMetric is the metric comparing the vectors of a collection.
+type DistanceMetric string
+
+const (
+	DistanceCosine     DistanceMetric = "cosine"
+	DistanceEuclidean  DistanceMetric = "euclidean"
+	DistanceDotProduct DistanceMetric = "dot_product"
+)
+
+// CollectionManager is implemented by stores which can create, drop and list
+// their collections, which some stores call indexes, classes or namespaces.
+type CollectionManager interface {
+	// CreateCollection creates a collection. Stores which need the vector
+	// dimensions of a collection take them from their embedder if they
+	// aren't given.
+	CreateCollection(ctx context.Context, name string, options ...CollectionOption) error
+	// DropCollection drops a collection and its documents.
+	DropCollection(ctx context.Context, name string) error
+	// ListCollections returns the names of the collections.
+	ListCollections(ctx context.Context) ([]string, error)
+}
+
+// CollectionOption is a function that configures a CollectionOptions.
+type CollectionOption func(*CollectionOptions)
+
+// CollectionOptions is a set of options for creating a collection.
+type CollectionOptions struct {
+	Dimensions int
+	// Metric defaults to DistanceCosine.
+	Metric DistanceMetric
+	// IndexParams are parameters of the vector index in the syntax of the
+	// store, e.g. {"m": 16, "ef_construct": 100} for the HNSW index of
+	// Qdrant.
+	IndexParams map[string]any
+}
+
+// WithDimensions returns a CollectionOption for setting the dimensions of
+// the vectors of a collection.
+func WithDimensions(dimensions int) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.Dimensions = dimensions
+	}
+}
+
+// WithDistanceMetric returns a CollectionOption for setting the distance
+// metric of a collection.
+func WithDistanceMetric(metric DistanceMetric) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.Metric = metric
+	}
+}
+
+// WithIndexParams returns a CollectionOption for setting the parameters of
+// the vector index of a collection.
+func WithIndexParams(params map[string]any) CollectionOption {
+	return func(o *CollectionOptions) {
+		o.IndexParams = params
+	}
+}
+
+// NewCollectionOptions returns the CollectionOptions of the options, with
+// the default metric and, if they aren't given, the dimensions of the
+// embedder. It returns ErrMissingDimensions if the dimensions are unknown.
+func NewCollectionOptions(embedder embeddings.Embedder, options ...CollectionOption) (CollectionOptions, error) {
+	opts := CollectionOptions{Metric: DistanceCosine}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Dimensions == 0 && embedder != nil {
+		opts.Dimensions = embeddings.Dimensions(embedder)
+	}
+	if opts.Dimensions <= 0 {
+		return opts, ErrMissingDimensions
+	}
+	return opts, nil
+}
@@ -0,0 +1,52 @@
+package vectorstores
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDimensionMismatch is returned when vectors don't have the dimension of
+// the collection or index of a vector store, usually because the embedder
+// doesn't match the one the collection was created with.
+//
+// The stores check the vectors in AddDocuments and UpsertDocuments, except
+// chroma, whose client embeds the documents itself: Chroma rejects the
+// vectors with its own error. Stores that can't learn the dimension of their
+// index, e.g. an empty Weaviate class, only check that the vectors of a
+// batch have the same length.
+var ErrDimensionMismatch = errors.New("vector dimension mismatch")
+
+// DimensionMismatchError reports a vector whose length doesn't match the
+// dimension expected by a vector store. It wraps ErrDimensionMismatch.
+type DimensionMismatchError struct {
+	// Expected is the dimension of the collection or index.
+	Expected int
+	// Actual is the length of the vector.
+	Actual int
+	// Index is the index of the vector, and of its document, in the batch.
+	Index int
+}
+
+func (e *DimensionMismatchError) Error() string {
+	return fmt.Sprintf("%s: vector %d has %d dimensions, expected %d",
+		ErrDimensionMismatch, e.Index, e.Actual, e.Expected)
+}
+
+func (e *DimensionMismatchError) Unwrap() error {
+	return ErrDimensionMismatch
+}
+
+// CheckDimensions returns a *DimensionMismatchError for the first vector
+// whose length isn't dimensions. If dimensions isn't positive, i.e. unknown,
+// the vectors must all have the length of the first one.
+func CheckDimensions(vectors [][]float32, dimensions int) error {
+	if dimensions <= 0 && len(vectors) > 0 {
+		dimensions = len(vectors[0])
+	}
+	for i, vector := range vectors {
+		if len(vector) != dimensions {
+			return &DimensionMismatchError{Expected: dimensions, Actual: len(vector), Index: i}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDimensions(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, CheckDimensions(nil, 3))
+	require.NoError(t, CheckDimensions([][]float32{{1, 2, 3}, {4, 5, 6}}, 3))
+	require.NoError(t, CheckDimensions([][]float32{{1, 2}, {4, 5}}, 0))
+
+	err := CheckDimensions([][]float32{{1, 2, 3}, {4, 5}}, 3)
+	require.ErrorIs(t, err, ErrDimensionMismatch)
+	var mismatch *DimensionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, DimensionMismatchError{Expected: 3, Actual: 2, Index: 1}, *mismatch)
+
+	err = CheckDimensions([][]float32{{1, 2}, {4, 5, 6}}, 0)
+	require.ErrorIs(t, err, ErrDimensionMismatch)
+}
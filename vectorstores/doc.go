@@ -5,7 +5,10 @@ and querying documents as vector embeddings.
 The main components of this package are:
 
 - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
+- Deleter and Upserter interfaces: optional interfaces of the stores that can delete and replace documents.
 - Options: a set of options for similarity search and document addition.
+- RunBatches and AddDocumentsInBatches: batched, concurrent and retried document addition with progress reporting.
+- CollectionManager interface: optional interface of the stores that can create, drop and list collections.
 - Retriever: a retriever for vector stores that implements the schema.Retriever interface.
 
 The package provides a flexible way to handle different types of vector stores
@@ -0,0 +1,9 @@
+// Package faiss contains an implementation of the VectorStore interface
+// using FAISS, for fast approximate searches of millions of vectors without
+// an external service.
+//
+// The package uses the FAISS C API through cgo and is only built with the
+// faiss build tag, e.g. go build -tags faiss. The faiss_c library and its
+// headers must be installed, see
+// https://github.com/facebookresearch/faiss/blob/main/c_api/INSTALL.md.
+package faiss
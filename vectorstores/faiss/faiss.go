@@ -0,0 +1,505 @@
+//go:build faiss
+
+package faiss
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	_indexFile     = "index.faiss"
+	_documentsFile = "documents.gob"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+	// ErrNoIndex is returned when saving a store whose index hasn't been
+	// created yet because its vector dimensions are unknown.
+	ErrNoIndex = errors.New("index not created")
+	// ErrInvalidID is returned when upserting documents with ids that aren't
+	// integers, as FAISS ids are.
+	ErrInvalidID = errors.New("invalid id")
+)
+
+func init() { //nolint:gochecknoinits
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// Filter selects documents by their metadata.
+type Filter func(metadata map[string]any) bool
+
+// document is a document of the store. Its vector is in the index.
+type document struct {
+	Namespace string
+	Content   string
+	Metadata  map[string]any
+}
+
+// snapshot is the gob encoded content of a store saved alongside its index.
+type snapshot struct {
+	Metric    DistanceMetric
+	NextID    int64
+	Documents map[int64]document
+}
+
+// Store is a vector store keeping vectors in a FAISS index and documents in
+// memory. It is safe for concurrent use, and must be closed to free the
+// index.
+type Store struct {
+	mu          sync.RWMutex
+	embedder    embeddings.Embedder
+	description string
+	dimensions  int
+	metric      DistanceMetric
+	index       *index
+	docs        map[int64]document
+	nextID      int64
+}
+
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
+
+// New creates a new empty Store with options. The index is created when its
+// vector dimensions are known.
+func New(opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.dimensions > 0 {
+		if s.index, err = newIndex(s.dimensions, "IDMap,"+s.description, s.metric); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Load loads a store saved in dir by Save. The index description, vector
+// dimensions and distance metric options are ignored.
+func Load(dir string, opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, _documentsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decode documents: %w", err)
+	}
+
+	if s.index, err = readIndex(filepath.Join(dir, _indexFile)); err != nil {
+		return nil, err
+	}
+	s.dimensions = s.index.dimensions()
+	s.metric = snap.Metric
+	s.nextID = snap.NextID
+	if snap.Documents != nil {
+		s.docs = snap.Documents
+	}
+	return s, nil
+}
+
+// Save writes the index and the documents of the store to dir, creating it
+// if needed.
+func (s *Store) Save(dir string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.index == nil {
+		return ErrNoIndex
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec
+		return err
+	}
+	if err := s.index.write(filepath.Join(dir, _indexFile)); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, _documentsFile))
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(f).Encode(snapshot{Metric: s.metric, NextID: s.nextID, Documents: s.docs})
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close frees the index of the store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil {
+		s.index.free()
+		s.index = nil
+	}
+	return nil
+}
+
+// Len returns the number of documents in the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.docs)
+}
+
+// AddDocuments adds documents to the store, in the namespace of the options
+// if any, and returns their ids. Indexes that need training, e.g. IVF
+// indexes, are trained with the first documents added, which must be
+// numerous enough.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	return s.addDocuments(ctx, nil, docs, opts)
+}
+
+// UpsertDocuments adds documents with the ids, which must be integers, to the
+// store, in the namespace of the options if any, replacing the documents with
+// the same ids. Indexes that don't support removals, e.g. HNSW indexes, return
+// an error when replacing documents.
+func (s *Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	keys := make([]int64, len(ids))
+	for i, id := range ids {
+		var err error
+		if keys[i], err = strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("%w: %q is not an integer", ErrInvalidID, id)
+		}
+	}
+	_, err := s.addDocuments(ctx, keys, docs, opts)
+	return err
+}
+
+// addDocuments embeds the documents and adds them with the ids, replacing
+// the documents with the same ids, or with new ids if ids is nil.
+func (s *Store) addDocuments(ctx context.Context, ids []int64, docs []schema.Document, opts vectorstores.Options) ([]string, error) { //nolint:lll
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := vectorstores.CheckDimensions(vectors, s.dimensions); err != nil {
+		return nil, err
+	}
+	if s.index == nil {
+		s.dimensions = len(vectors[0])
+		if s.index, err = newIndex(s.dimensions, "IDMap,"+s.description, s.metric); err != nil {
+			return nil, err
+		}
+	}
+
+	if ids == nil {
+		ids = make([]int64, len(vectors))
+		for i := range ids {
+			ids[i] = s.nextID + int64(i)
+		}
+	} else if err := s.delete(ids); err != nil {
+		return nil, err
+	}
+
+	x := make([]float32, 0, len(vectors)*s.dimensions)
+	for _, vector := range vectors {
+		x = append(x, s.prepare(vector)...)
+	}
+	if err := s.index.add(x, ids); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		s.nextID = max(s.nextID, id+1)
+	}
+
+	result := make([]string, len(docs))
+	for i, doc := range docs {
+		s.docs[ids[i]] = document{
+			Namespace: opts.NameSpace,
+			Content:   doc.PageContent,
+			Metadata:  doc.Metadata,
+		}
+		result[i] = strconv.FormatInt(ids[i], 10)
+	}
+	return result, nil
+}
+
+// Delete removes the documents with the given ids from the store. Unknown
+// ids are ignored. Indexes that don't support removals, e.g. HNSW indexes,
+// return an error.
+func (s *Store) Delete(ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+			keys = append(keys, n)
+		}
+	}
+	return s.delete(keys)
+}
+
+// DeleteByIDs removes the documents with the given ids from the store, as
+// Delete does.
+func (s *Store) DeleteByIDs(_ context.Context, ids []string, _ ...vectorstores.Option) error {
+	return s.Delete(ids...)
+}
+
+// DeleteByFilter removes the documents of the namespace of the options whose
+// metadata is matched by the filter, which is either a vectorstores.Filter,
+// a map of metadata keys to values, or a Filter.
+func (s *Store) DeleteByFilter(_ context.Context, filters any, options ...vectorstores.Option) error {
+	if filters == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	match, err := filter(filters)
+	if err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int64
+	for id, doc := range s.docs {
+		if doc.Namespace == opts.NameSpace && match(doc.Metadata) {
+			ids = append(ids, id)
+		}
+	}
+	return s.delete(ids)
+}
+
+// delete removes the documents with the ids from the index, ignoring unknown
+// ids. s.mu must be held.
+func (s *Store) delete(ids []int64) error {
+	known := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := s.docs[id]; ok {
+			known = append(known, id)
+		}
+	}
+	if len(known) == 0 {
+		return nil
+	}
+	if err := s.index.remove(known); err != nil {
+		return err
+	}
+	for _, id := range known {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+// SimilaritySearch returns the documents of the namespace of the options
+// nearest to the query. Filters are either a vectorstores.Filter, a map of
+// metadata keys to the values the documents must have, or a Filter. Filtered searches fetch more
+// neighbors until enough of them match.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by faiss", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	match, err := filter(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.index == nil || numDocuments <= 0 {
+		return nil, nil
+	}
+	if err := vectorstores.CheckDimensions([][]float32{vector}, s.dimensions); err != nil {
+		return nil, err
+	}
+	vector = s.prepare(vector)
+
+	total := s.index.size()
+	for k := int64(numDocuments); ; k *= 4 {
+		k = min(k, total)
+		if k == 0 {
+			return nil, nil
+		}
+		ids, distances, err := s.index.search(vector, int(k))
+		if err != nil {
+			return nil, err
+		}
+		docs, complete := s.collect(ids, distances, numDocuments, opts, match)
+		if complete || k == total {
+			return docs, nil
+		}
+	}
+}
+
+// collect returns up to numDocuments documents of search results matching
+// the options, and whether no more results are needed.
+func (s *Store) collect(ids []int64, distances []float32, numDocuments int, opts vectorstores.Options, match Filter) ([]schema.Document, bool) { //nolint:lll
+	docs := make([]schema.Document, 0, numDocuments)
+	for i, id := range ids {
+		doc, ok := s.docs[id]
+		if id < 0 || !ok || doc.Namespace != opts.NameSpace || !match(doc.Metadata) {
+			continue
+		}
+		score := s.score(distances[i])
+		if opts.ScoreThreshold != 0 && score < opts.ScoreThreshold {
+			return docs, true
+		}
+		docs = append(docs, schema.Document{
+			PageContent: doc.Content,
+			Metadata:    maps.Clone(doc.Metadata),
+			Score:       score,
+		})
+		if len(docs) == numDocuments {
+			return docs, true
+		}
+	}
+	return docs, false
+}
+
+// prepare returns the vector to add to or search the index, normalized for
+// the cosine distance.
+func (s *Store) prepare(vector []float32) []float32 {
+	if s.metric != DistanceCosine {
+		return vector
+	}
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vector
+	}
+	norm := float32(math.Sqrt(sum))
+	normalized := make([]float32, len(vector))
+	for i, v := range vector {
+		normalized[i] = v / norm
+	}
+	return normalized
+}
+
+// score returns the score of a distance returned by the index, which is the
+// inner product for the cosine distance and the squared distance for L2.
+// score returns the score of a squared L2 distance, or of the inner product
+// of normalized vectors.
+func (s *Store) score(distance float32) float32 {
+	if s.metric == DistanceL2 {
+		return vectorstores.SquaredEuclideanDistanceScore(float64(distance))
+	}
+	return vectorstores.DotProductScore(float64(distance))
+}
+
+// filter returns the Filter of the filters of the options.
+func filter(filters any) (Filter, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case Filter:
+		return filters, nil
+	case vectorstores.Filter:
+		if err := filters.Validate(); err != nil {
+			return nil, err
+		}
+		return filters.Match, nil
+	case func(map[string]any) bool:
+		return filters, nil
+	case map[string]any:
+		return func(metadata map[string]any) bool {
+			for key, value := range filters {
+				if !reflect.DeepEqual(metadata[key], value) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, ErrInvalidFilters
+	}
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s *Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,158 @@
+//go:build faiss
+
+package faiss
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// newEmbedder returns an embedder mapping the texts of vectors to their
+// vector.
+func newEmbedder(t *testing.T) embeddings.Embedder {
+	t.Helper()
+	vectors := map[string][]float32{
+		"cat":    {1, 0, 0},
+		"kitten": {0.9, 0.1, 0},
+		"dog":    {0, 1, 0},
+		"puppy":  {0.1, 0.9, 0},
+		"car":    {0, 0, 1},
+	}
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			result := make([][]float32, len(texts))
+			for i, text := range texts {
+				result[i] = vectors[text]
+			}
+			return result, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+func contents(docs []schema.Document) []string {
+	result := make([]string, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.PageContent
+	}
+	return result
+}
+
+func TestFaissStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := New(WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	defer store.Close()
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "cat", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "dog", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "car", Metadata: map[string]any{"kind": "vehicle"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1", "2"}, ids)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "puppy"}}, vectorstores.WithNameSpace("young"))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "kitten", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat", "dog"}, contents(docs))
+	assert.InDelta(t, 0.9939, docs[0].Score, 1e-4)
+
+	docs, err = store.SimilaritySearch(ctx, "kitten", 1,
+		vectorstores.WithFilters(map[string]any{"kind": "vehicle"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"car"}, contents(docs))
+
+	docs, err = store.SimilaritySearch(ctx, "kitten", 2, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat"}, contents(docs))
+
+	docs, err = store.SimilaritySearch(ctx, "kitten", 2, vectorstores.WithNameSpace("young"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"puppy"}, contents(docs))
+
+	require.NoError(t, store.Delete(ids[0], "unknown"))
+	require.Equal(t, 3, store.Len())
+	docs, err = store.SimilaritySearch(ctx, "kitten", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dog"}, contents(docs))
+
+	dir := filepath.Join(t.TempDir(), "store")
+	require.NoError(t, store.Save(dir))
+	loaded, err := Load(dir, WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	defer loaded.Close()
+	require.Equal(t, 3, loaded.Len())
+	docs, err = loaded.SimilaritySearch(ctx, "kitten", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dog"}, contents(docs))
+	ids, err = loaded.AddDocuments(ctx, []schema.Document{{PageContent: "kitten"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids)
+}
+
+func TestFaissStoreUpsertAndDeleteByFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, err := New(WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.UpsertDocuments(ctx, []string{"7", "8"}, []schema.Document{
+		{PageContent: "cat", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "car", Metadata: map[string]any{"kind": "vehicle"}},
+	}))
+	require.NoError(t, store.UpsertDocuments(ctx, []string{"7"}, []schema.Document{
+		{PageContent: "dog", Metadata: map[string]any{"kind": "animal"}},
+	}))
+	require.Equal(t, 2, store.Len())
+	docs, err := store.SimilaritySearch(ctx, "kitten", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dog", "car"}, contents(docs))
+	ids, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "puppy"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9"}, ids)
+
+	require.ErrorIs(t, store.UpsertDocuments(ctx, []string{"a"}, []schema.Document{{PageContent: "cat"}}), ErrInvalidID)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Eq("kind", "animal")))
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"9"}))
+	docs, err = store.SimilaritySearch(ctx, "kitten", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"car"}, contents(docs))
+}
+
+func TestFaissStoreL2(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t)), WithDistanceMetric(DistanceL2),
+		WithIndexDescription("HNSW16"), WithVectorDimensions(3))
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "cat"}, {PageContent: "dog"}})
+	require.NoError(t, err)
+	docs, err := store.SimilaritySearch(context.Background(), "kitten", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat"}, contents(docs))
+	assert.InDelta(t, 1-0.1414*0.1414/2, docs[0].Score, 1e-4)
+}
+
+func TestFaissStoreNoIndex(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	require.ErrorIs(t, store.Save(t.TempDir()), ErrNoIndex)
+	docs, err := store.SimilaritySearch(context.Background(), "kitten", 1)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
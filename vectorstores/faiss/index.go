@@ -0,0 +1,125 @@
+//go:build faiss
+
+package faiss
+
+/*
+#cgo LDFLAGS: -lfaiss_c
+#include <stdlib.h>
+#include <faiss/c_api/Index_c.h>
+#include <faiss/c_api/error_c.h>
+#include <faiss/c_api/impl/AuxIndexStructures_c.h>
+#include <faiss/c_api/index_factory_c.h>
+#include <faiss/c_api/index_io_c.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrFaiss is returned when a FAISS call fails.
+var ErrFaiss = errors.New("faiss error")
+
+func lastError(op string) error {
+	return fmt.Errorf("%w: %s: %s", ErrFaiss, op, C.GoString(C.faiss_get_last_error()))
+}
+
+// index is a FAISS index, which must be freed with free.
+type index struct {
+	ptr *C.FaissIndex
+}
+
+// newIndex creates an index with the index factory.
+func newIndex(dimensions int, description string, metric DistanceMetric) (*index, error) {
+	cdescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cdescription))
+
+	faissMetric := C.METRIC_INNER_PRODUCT
+	if metric == DistanceL2 {
+		faissMetric = C.METRIC_L2
+	}
+	idx := &index{}
+	if C.faiss_index_factory(&idx.ptr, C.int(dimensions), cdescription, C.FaissMetricType(faissMetric)) != 0 {
+		return nil, lastError("create index")
+	}
+	return idx, nil
+}
+
+// readIndex reads an index written by write.
+func readIndex(path string) (*index, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	idx := &index{}
+	if C.faiss_read_index_fname(cpath, 0, &idx.ptr) != 0 {
+		return nil, lastError("read index")
+	}
+	return idx, nil
+}
+
+func (idx *index) write(path string) error {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	if C.faiss_write_index_fname(idx.ptr, cpath) != 0 {
+		return lastError("write index")
+	}
+	return nil
+}
+
+func (idx *index) dimensions() int {
+	return int(C.faiss_Index_d(idx.ptr))
+}
+
+func (idx *index) size() int64 {
+	return int64(C.faiss_Index_ntotal(idx.ptr))
+}
+
+// add adds the vectors, concatenated in x, with their ids. Indexes that
+// aren't trained yet are trained with the vectors first.
+func (idx *index) add(x []float32, ids []int64) error {
+	n := C.idx_t(len(ids))
+	if C.faiss_Index_is_trained(idx.ptr) == 0 {
+		if C.faiss_Index_train(idx.ptr, n, (*C.float)(&x[0])) != 0 {
+			return lastError("train index")
+		}
+	}
+	if C.faiss_Index_add_with_ids(idx.ptr, n, (*C.float)(&x[0]), (*C.idx_t)(&ids[0])) != 0 {
+		return lastError("add vectors")
+	}
+	return nil
+}
+
+// search returns the ids and distances of the k vectors nearest to query.
+// Ids are -1 when there are fewer than k vectors.
+func (idx *index) search(query []float32, k int) ([]int64, []float32, error) {
+	ids := make([]int64, k)
+	distances := make([]float32, k)
+	if C.faiss_Index_search(idx.ptr, 1, (*C.float)(&query[0]), C.idx_t(k),
+		(*C.float)(&distances[0]), (*C.idx_t)(&ids[0])) != 0 {
+		return nil, nil, lastError("search")
+	}
+	return ids, distances, nil
+}
+
+// remove removes the vectors with the given ids.
+func (idx *index) remove(ids []int64) error {
+	var selector *C.FaissIDSelectorBatch
+	if C.faiss_IDSelectorBatch_new(&selector, C.size_t(len(ids)), (*C.idx_t)(&ids[0])) != 0 {
+		return lastError("create id selector")
+	}
+	defer C.faiss_IDSelector_free((*C.FaissIDSelector)(unsafe.Pointer(selector)))
+
+	var removed C.size_t
+	if C.faiss_Index_remove_ids(idx.ptr, (*C.FaissIDSelector)(unsafe.Pointer(selector)), &removed) != 0 {
+		return lastError("remove vectors")
+	}
+	return nil
+}
+
+func (idx *index) free() {
+	C.faiss_Index_free(idx.ptr)
+	idx.ptr = nil
+}
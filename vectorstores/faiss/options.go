@@ -0,0 +1,90 @@
+//go:build faiss
+
+package faiss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultIndexDescription is the default index factory description, an
+	// exact search index.
+	DefaultIndexDescription = "Flat"
+)
+
+// DistanceMetric is the distance used to compare vectors.
+type DistanceMetric string
+
+const (
+	// DistanceCosine is the cosine distance, computed as the inner product
+	// of normalized vectors. Document scores are the cosine similarity.
+	DistanceCosine DistanceMetric = "cosine"
+	// DistanceL2 is the euclidean distance. Document scores are
+	// 1 / (1 + distance).
+	DistanceL2 DistanceMetric = "l2"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithIndexDescription is an option for specifying the index factory
+// description of the index, e.g. "HNSW32" or "IVF4096,PQ32", see
+// https://github.com/facebookresearch/faiss/wiki/The-index-factory. The
+// index is wrapped in an IDMap. Defaults to DefaultIndexDescription.
+func WithIndexDescription(description string) Option {
+	return func(s *Store) {
+		s.description = description
+	}
+}
+
+// WithVectorDimensions is an option for specifying the vector size of the
+// index. Defaults to the dimensions of the embedder, and then to the size of
+// the first vectors added.
+func WithVectorDimensions(size int) Option {
+	return func(s *Store) {
+		s.dimensions = size
+	}
+}
+
+// WithDistanceMetric is an option for specifying the distance metric.
+// Defaults to DistanceCosine.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		description: DefaultIndexDescription,
+		metric:      DistanceCosine,
+		docs:        map[int64]document{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.metric != DistanceCosine && s.metric != DistanceL2 {
+		return nil, fmt.Errorf("%w: unsupported distance metric %q", ErrInvalidOptions, s.metric)
+	}
+	if s.dimensions == 0 {
+		s.dimensions = embeddings.Dimensions(s.embedder)
+	}
+	return s, nil
+}
@@ -0,0 +1,225 @@
+package vectorstores
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidFilter is returned when a Filter is malformed or can't be
+// translated to the filter syntax of a vector store.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterOp is the operator of a Filter.
+type FilterOp string
+
+const (
+	FilterEq     FilterOp = "eq"
+	FilterNe     FilterOp = "ne"
+	FilterGt     FilterOp = "gt"
+	FilterGte    FilterOp = "gte"
+	FilterLt     FilterOp = "lt"
+	FilterLte    FilterOp = "lte"
+	FilterIn     FilterOp = "in"
+	FilterExists FilterOp = "exists"
+	FilterAnd    FilterOp = "and"
+	FilterOr     FilterOp = "or"
+)
+
+// Filter is a metadata filter expression that vector stores translate to
+// their native filter syntax, so that filtered searches are portable across
+// stores. Filters are built with Eq, Ne, Gt, Gte, Lt, Lte, In, Exists, And
+// and Or, and passed to searches with WithFilter.
+type Filter struct {
+	Op FilterOp
+	// Key is the metadata key of comparison, In and Exists filters.
+	Key string
+	// Value is the value compared by comparison filters.
+	Value any
+	// Values are the values of In filters.
+	Values []any
+	// Filters are the operands of And and Or filters.
+	Filters []Filter
+}
+
+// Eq returns a filter matching documents whose metadata value for key
+// equals value.
+func Eq(key string, value any) Filter {
+	return Filter{Op: FilterEq, Key: key, Value: value}
+}
+
+// Ne returns a filter matching documents whose metadata value for key
+// doesn't equal value, including documents without the key.
+func Ne(key string, value any) Filter {
+	return Filter{Op: FilterNe, Key: key, Value: value}
+}
+
+// Gt returns a filter matching documents whose metadata value for key is
+// greater than value.
+func Gt(key string, value any) Filter {
+	return Filter{Op: FilterGt, Key: key, Value: value}
+}
+
+// Gte returns a filter matching documents whose metadata value for key is
+// greater than or equal to value.
+func Gte(key string, value any) Filter {
+	return Filter{Op: FilterGte, Key: key, Value: value}
+}
+
+// Lt returns a filter matching documents whose metadata value for key is
+// less than value.
+func Lt(key string, value any) Filter {
+	return Filter{Op: FilterLt, Key: key, Value: value}
+}
+
+// Lte returns a filter matching documents whose metadata value for key is
+// less than or equal to value.
+func Lte(key string, value any) Filter {
+	return Filter{Op: FilterLte, Key: key, Value: value}
+}
+
+// In returns a filter matching documents whose metadata value for key
+// equals one of values.
+func In(key string, values ...any) Filter {
+	return Filter{Op: FilterIn, Key: key, Values: values}
+}
+
+// Exists returns a filter matching documents having a metadata value for
+// key.
+func Exists(key string) Filter {
+	return Filter{Op: FilterExists, Key: key}
+}
+
+// And returns a filter matching documents matched by all the filters.
+func And(filters ...Filter) Filter {
+	return Filter{Op: FilterAnd, Filters: filters}
+}
+
+// Or returns a filter matching documents matched by any of the filters.
+func Or(filters ...Filter) Filter {
+	return Filter{Op: FilterOr, Filters: filters}
+}
+
+// IsComparison reports whether the filter compares a metadata value to its
+// Value, i.e. is an Eq, Ne, Gt, Gte, Lt or Lte filter.
+func (f Filter) IsComparison() bool {
+	switch f.Op { //nolint:exhaustive
+	case FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate returns an error wrapping ErrInvalidFilter if the filter or one
+// of its operands is malformed.
+func (f Filter) Validate() error {
+	switch {
+	case f.Op == FilterAnd || f.Op == FilterOr:
+		for _, operand := range f.Filters {
+			if err := operand.Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case !f.IsComparison() && f.Op != FilterIn && f.Op != FilterExists:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Op)
+	case f.Key == "":
+		return fmt.Errorf("%w: %s filter without key", ErrInvalidFilter, f.Op)
+	case f.IsComparison() && f.Value == nil:
+		return fmt.Errorf("%w: %s filter on %q without value", ErrInvalidFilter, f.Op, f.Key)
+	case f.Op == FilterIn && len(f.Values) == 0:
+		return fmt.Errorf("%w: in filter on %q without values", ErrInvalidFilter, f.Key)
+	}
+	return nil
+}
+
+// Match reports whether metadata is matched by the filter, for stores
+// filtering documents themselves. Numbers are compared by value whatever
+// their type, and strings are ordered lexically.
+func (f Filter) Match(metadata map[string]any) bool {
+	value, ok := metadata[f.Key]
+	switch f.Op {
+	case FilterAnd:
+		for _, operand := range f.Filters {
+			if !operand.Match(metadata) {
+				return false
+			}
+		}
+		return true
+	case FilterOr:
+		for _, operand := range f.Filters {
+			if operand.Match(metadata) {
+				return true
+			}
+		}
+		return false
+	case FilterExists:
+		return ok && value != nil
+	case FilterIn:
+		for _, v := range f.Values {
+			if ok && equalValues(value, v) {
+				return true
+			}
+		}
+		return false
+	case FilterEq:
+		return ok && equalValues(value, f.Value)
+	case FilterNe:
+		return !ok || !equalValues(value, f.Value)
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		c, comparable := compareValues(value, f.Value)
+		if !ok || !comparable {
+			return false
+		}
+		switch f.Op { //nolint:exhaustive
+		case FilterGt:
+			return c > 0
+		case FilterGte:
+			return c >= 0
+		case FilterLt:
+			return c < 0
+		default:
+			return c <= 0
+		}
+	default:
+		return false
+	}
+}
+
+func equalValues(a, b any) bool {
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues compares two numbers or two strings.
+func compareValues(a, b any) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return cmp.Compare(x, y), ok
+	}
+	x, ok := a.(string)
+	y, ok2 := b.(string)
+	if !ok || !ok2 {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
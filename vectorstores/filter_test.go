@@ -0,0 +1,50 @@
+package vectorstores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+	metadata := map[string]any{"year": 2024, "lang": "en", "score": 0.5, "draft": false}
+
+	tests := []struct {
+		filter vectorstores.Filter
+		match  bool
+	}{
+		{vectorstores.Eq("year", 2024.0), true},
+		{vectorstores.Eq("lang", "fr"), false},
+		{vectorstores.Eq("draft", false), true},
+		{vectorstores.Ne("lang", "fr"), true},
+		{vectorstores.Ne("missing", "fr"), true},
+		{vectorstores.Gt("year", 2020), true},
+		{vectorstores.Gte("score", 0.5), true},
+		{vectorstores.Lt("score", 0.5), false},
+		{vectorstores.Lte("lang", "en"), true},
+		{vectorstores.Gt("lang", 1), false},
+		{vectorstores.In("lang", "de", "en"), true},
+		{vectorstores.In("missing", "en"), false},
+		{vectorstores.Exists("draft"), true},
+		{vectorstores.Exists("missing"), false},
+		{vectorstores.And(vectorstores.Eq("lang", "en"), vectorstores.Gt("year", 2025)), false},
+		{vectorstores.Or(vectorstores.Eq("lang", "fr"), vectorstores.Lt("year", 2025)), true},
+		{vectorstores.And(), true},
+		{vectorstores.Or(), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, tt.filter.Match(metadata), "%+v", tt.filter)
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, vectorstores.And(vectorstores.Eq("a", 1), vectorstores.Exists("b")).Validate())
+	require.ErrorIs(t, vectorstores.Eq("", 1).Validate(), vectorstores.ErrInvalidFilter)
+	require.ErrorIs(t, vectorstores.Gt("a", nil).Validate(), vectorstores.ErrInvalidFilter)
+	require.ErrorIs(t, vectorstores.Or(vectorstores.In("a")).Validate(), vectorstores.ErrInvalidFilter)
+	require.ErrorIs(t, vectorstores.Filter{Op: "like", Key: "a"}.Validate(), vectorstores.ErrInvalidFilter)
+}
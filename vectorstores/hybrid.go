@@ -0,0 +1,149 @@
+package vectorstores
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// FusionStrategy is how the results of the dense and sparse searches of a
+// hybrid search are combined.
+type FusionStrategy string
+
+const (
+	// FusionRRF is Reciprocal Rank Fusion: documents are scored with the sum
+	// of 1 / (RRFK + rank) over the searches returning them.
+	FusionRRF FusionStrategy = "rrf"
+	// FusionWeighted scores documents with the sum of their dense and sparse
+	// scores, normalized to [0, 1], weighted by Alpha and 1 - Alpha.
+	FusionWeighted FusionStrategy = "weighted"
+)
+
+// RRFK is the rank constant of Reciprocal Rank Fusion.
+const RRFK = 60
+
+// ErrInvalidHybridSearch is returned when the options of a hybrid search are
+// invalid or not supported by a store.
+var ErrInvalidHybridSearch = errors.New("invalid hybrid search")
+
+// HybridSearch configures a search combining the similarity of dense
+// vectors with the matching of sparse vectors or terms, e.g. BM25. Stores
+// supporting hybrid searches return documents with their fused scores, to
+// which score thresholds apply.
+type HybridSearch struct {
+	// Vector is the dense query vector. Defaults to the embedding of the
+	// query.
+	Vector []float32
+	// Sparse is the sparse query vector of stores searching sparse vectors.
+	// Defaults to the embedding of the query by the sparse embedder of the
+	// store.
+	Sparse embeddings.SparseVector
+	// Text is the full-text query of stores matching terms themselves.
+	// Defaults to the query.
+	Text string
+	// Fusion is the fusion strategy. Defaults to FusionRRF.
+	Fusion FusionStrategy
+	// Alpha is the weight of the dense scores with FusionWeighted, from 0
+	// for sparse scores only to 1 for dense scores only.
+	Alpha float32
+}
+
+// WithHybridSearch returns an Option for searching both dense and sparse
+// vectors and fusing their results. Stores not supporting hybrid searches
+// return an error wrapping ErrInvalidHybridSearch.
+func WithHybridSearch(hybrid HybridSearch) Option {
+	return func(o *Options) {
+		o.HybridSearch = &hybrid
+	}
+}
+
+// Validate returns an error wrapping ErrInvalidHybridSearch if the fusion
+// strategy is unknown or Alpha isn't between 0 and 1.
+func (h HybridSearch) Validate() error {
+	if h.Fusion != "" && h.Fusion != FusionRRF && h.Fusion != FusionWeighted {
+		return fmt.Errorf("%w: unknown fusion strategy %q", ErrInvalidHybridSearch, h.Fusion)
+	}
+	if h.Alpha < 0 || h.Alpha > 1 {
+		return fmt.Errorf("%w: alpha must be between 0 and 1", ErrInvalidHybridSearch)
+	}
+	return nil
+}
+
+// Fuse fuses the results of the dense and sparse searches of a hybrid
+// search, sorted by decreasing score, with the fusion strategy. It returns
+// up to numDocuments documents whose fused score is at least scoreThreshold.
+// Documents are identified by key, or by their content if key is nil, and
+// documents returned by both searches keep the fields of their dense result.
+func (h HybridSearch) Fuse(dense, sparse []schema.Document, numDocuments int, scoreThreshold float32, key func(schema.Document) string) []schema.Document { //nolint:lll
+	if key == nil {
+		key = func(doc schema.Document) string { return doc.PageContent }
+	}
+
+	var keys []string
+	docs := map[string]schema.Document{}
+	scores := map[string]float64{}
+	add := func(results []schema.Document, score func(rank int) float64) {
+		for rank, doc := range results {
+			k := key(doc)
+			if _, ok := docs[k]; !ok {
+				keys = append(keys, k)
+				docs[k] = doc
+			}
+			scores[k] += score(rank)
+		}
+	}
+
+	if h.Fusion == FusionWeighted {
+		denseScores, sparseScores := normalizeScores(dense), normalizeScores(sparse)
+		add(dense, func(rank int) float64 { return float64(h.Alpha) * denseScores[rank] })
+		add(sparse, func(rank int) float64 { return float64(1-h.Alpha) * sparseScores[rank] })
+	} else {
+		rrf := func(rank int) float64 { return 1 / float64(RRFK+rank+1) }
+		add(dense, rrf)
+		add(sparse, rrf)
+	}
+
+	// keys are in order of first appearance, which breaks ties.
+	slices.SortStableFunc(keys, func(a, b string) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return 0
+	})
+	fused := make([]schema.Document, 0, min(numDocuments, len(keys)))
+	for _, k := range keys {
+		doc := docs[k]
+		doc.Score = float32(scores[k])
+		if len(fused) == numDocuments || doc.Score < scoreThreshold {
+			break
+		}
+		fused = append(fused, doc)
+	}
+	return fused
+}
+
+// normalizeScores returns the scores of the documents scaled to [0, 1].
+func normalizeScores(docs []schema.Document) []float64 {
+	if len(docs) == 0 {
+		return nil
+	}
+	lowest, highest := docs[0].Score, docs[0].Score
+	for _, doc := range docs {
+		lowest, highest = min(lowest, doc.Score), max(highest, doc.Score)
+	}
+	scores := make([]float64, len(docs))
+	for i, doc := range docs {
+		if highest == lowest {
+			scores[i] = 1
+			continue
+		}
+		scores[i] = float64(doc.Score-lowest) / float64(highest-lowest)
+	}
+	return scores
+}
@@ -0,0 +1,58 @@
+package vectorstores_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestHybridSearchFuse(t *testing.T) {
+	t.Parallel()
+	dense := []schema.Document{
+		{PageContent: "a", Score: 0.9, Metadata: map[string]any{"from": "dense"}},
+		{PageContent: "b", Score: 0.8},
+		{PageContent: "c", Score: 0.5},
+	}
+	sparse := []schema.Document{
+		{PageContent: "c", Score: 12, Metadata: map[string]any{"from": "sparse"}},
+		{PageContent: "a", Score: 6},
+		{PageContent: "d", Score: 2},
+	}
+	contents := func(docs []schema.Document) []string {
+		var s []string
+		for _, doc := range docs {
+			s = append(s, doc.PageContent)
+		}
+		return s
+	}
+
+	rrf := vectorstores.HybridSearch{}.Fuse(dense, sparse, 10, 0, nil)
+	assert.Equal(t, []string{"a", "c", "b", "d"}, contents(rrf))
+	assert.InDelta(t, 1.0/61+1.0/62, rrf[0].Score, 1e-6)
+	assert.Equal(t, map[string]any{"from": "dense"}, rrf[0].Metadata)
+
+	weighted := vectorstores.HybridSearch{Fusion: vectorstores.FusionWeighted, Alpha: 0.25}.Fuse(dense, sparse, 2, 0, nil)
+	assert.Equal(t, []string{"c", "a"}, contents(weighted))
+	assert.InDelta(t, 0.75, weighted[0].Score, 1e-6)
+	assert.InDelta(t, 0.25+0.75*0.4, weighted[1].Score, 1e-6)
+
+	thresholded := vectorstores.HybridSearch{Fusion: vectorstores.FusionWeighted, Alpha: 1}.Fuse(dense, sparse, 10, 0.5, nil)
+	assert.Equal(t, []string{"a", "b"}, contents(thresholded))
+
+	byID := vectorstores.HybridSearch{}.Fuse(dense, sparse, 10, 0, func(doc schema.Document) string {
+		from, _ := doc.Metadata["from"].(string)
+		return from
+	})
+	assert.Len(t, byID, 3)
+}
+
+func TestHybridSearchValidate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, vectorstores.HybridSearch{}.Validate())
+	require.NoError(t, vectorstores.HybridSearch{Fusion: vectorstores.FusionWeighted, Alpha: 1}.Validate())
+	require.ErrorIs(t, vectorstores.HybridSearch{Fusion: "max"}.Validate(), vectorstores.ErrInvalidHybridSearch)
+	require.ErrorIs(t, vectorstores.HybridSearch{Alpha: 1.5}.Validate(), vectorstores.ErrInvalidHybridSearch)
+}
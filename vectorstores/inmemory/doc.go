@@ -0,0 +1,8 @@
+// Package inmemory contains an implementation of the VectorStore interface
+// keeping documents in memory, for tests, demos and small corpora that don't
+// need an external service.
+//
+// Searches are exact by default; WithHNSW builds an HNSW graph for
+// approximate searches of larger corpora. Stores can be saved to and loaded
+// from disk in the gob or parquet format.
+package inmemory
@@ -0,0 +1,213 @@
+package inmemory
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// candidate is a node and its distance to the query.
+type candidate struct {
+	id   int
+	dist float32
+}
+
+// candidateHeap is a min-heap of candidates ordered by distance.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h candidateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x any)        { *h = append(*h, x.(candidate)) } //nolint:forcetypeassert
+func (h *candidateHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// insertResult inserts c into results, sorted by increasing distance, keeping
+// at most size results.
+func insertResult(results []candidate, c candidate, size int) []candidate {
+	i, _ := slices.BinarySearchFunc(results, c, func(a, b candidate) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	results = slices.Insert(results, i, c)
+	if len(results) > size {
+		results = results[:size]
+	}
+	return results
+}
+
+// hnsw is a hierarchical navigable small world graph, see
+// https://arxiv.org/abs/1603.09320. Nodes are the indexes of the documents
+// of the store, which are added in order.
+type hnsw struct {
+	m              int
+	efConstruction int
+	efSearch       int
+	rng            *rand.Rand
+	entry          int
+	maxLevel       int
+	// neighbors are the neighbors of each node at each of its levels.
+	neighbors [][][]int
+}
+
+func newHNSW(m, efConstruction, efSearch int) *hnsw {
+	h := &hnsw{
+		m:              m,
+		efConstruction: efConstruction,
+		efSearch:       efSearch,
+	}
+	h.reset()
+	return h
+}
+
+// reset removes all the nodes of the graph.
+func (h *hnsw) reset() {
+	h.rng = rand.New(rand.NewPCG(1, 2)) //nolint:gosec
+	h.entry = -1
+	h.maxLevel = 0
+	h.neighbors = nil
+}
+
+func (h *hnsw) randomLevel() int {
+	return int(-math.Log(1-h.rng.Float64()) / math.Log(float64(h.m)))
+}
+
+func (h *hnsw) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * h.m
+	}
+	return h.m
+}
+
+// add adds the node id, which must be the next one, to the graph. distance
+// returns the distance between two nodes.
+func (h *hnsw) add(id int, distance func(a, b int) float32) {
+	level := h.randomLevel()
+	h.neighbors = append(h.neighbors, make([][]int, level+1))
+	if h.entry < 0 {
+		h.entry, h.maxLevel = id, level
+		return
+	}
+
+	toNode := func(n int) float32 { return distance(id, n) }
+	entry := candidate{id: h.entry, dist: toNode(h.entry)}
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.greedy(entry, l, toNode)
+	}
+	entries := []candidate{entry}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		found := h.searchLayer(entries, l, h.efConstruction, toNode, nil)
+		closest := found[:min(len(found), h.m)]
+		h.neighbors[id][l] = make([]int, len(closest))
+		for i, c := range closest {
+			h.neighbors[id][l][i] = c.id
+			h.connect(c.id, id, l, distance)
+		}
+		entries = found
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = id, level
+	}
+}
+
+// connect adds id to the neighbors of node at level, dropping the farthest
+// neighbor when node has too many.
+func (h *hnsw) connect(node, id, level int, distance func(a, b int) float32) {
+	neighbors := append(h.neighbors[node][level], id)
+	if maxNeighbors := h.maxNeighbors(level); len(neighbors) > maxNeighbors {
+		slices.SortFunc(neighbors, func(a, b int) int {
+			return cmp.Compare(distance(node, a), distance(node, b))
+		})
+		neighbors = neighbors[:maxNeighbors]
+	}
+	h.neighbors[node][level] = neighbors
+}
+
+// greedy returns the node closest to the query reachable from entry at
+// level by always moving to a closer neighbor.
+func (h *hnsw) greedy(entry candidate, level int, distance func(int) float32) candidate {
+	for changed := true; changed; {
+		changed = false
+		for _, n := range h.neighbors[entry.id][level] {
+			if d := distance(n); d < entry.dist {
+				entry = candidate{id: n, dist: d}
+				changed = true
+			}
+		}
+	}
+	return entry
+}
+
+// searchLayer returns up to ef nodes closest to the query at level, sorted
+// by increasing distance. If accept isn't nil, only the nodes it accepts are
+// returned, while the others are still traversed.
+func (h *hnsw) searchLayer(entries []candidate, level, ef int, distance func(int) float32, accept func(int) bool) []candidate { //nolint:lll
+	visited := make(map[int]bool, ef)
+	candidates := &candidateHeap{}
+	var results []candidate
+	for _, e := range entries {
+		visited[e.id] = true
+		heap.Push(candidates, e)
+		if accept == nil || accept(e.id) {
+			results = insertResult(results, e, ef)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(candidate) //nolint:forcetypeassert
+		if len(results) == ef && c.dist > results[len(results)-1].dist {
+			break
+		}
+		for _, n := range h.neighbors[c.id][level] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			d := distance(n)
+			if len(results) == ef && d >= results[len(results)-1].dist {
+				continue
+			}
+			heap.Push(candidates, candidate{id: n, dist: d})
+			if accept == nil || accept(n) {
+				results = insertResult(results, candidate{id: n, dist: d}, ef)
+			}
+		}
+	}
+	return results
+}
+
+// search returns the k accepted nodes closest to the query, sorted by
+// increasing distance. When few nodes are accepted, most of the graph is
+// traversed.
+func (h *hnsw) search(k int, distance func(int) float32, accept func(int) bool) []candidate {
+	if h.entry < 0 {
+		return nil
+	}
+	entry := candidate{id: h.entry, dist: distance(h.entry)}
+	for l := h.maxLevel; l > 0; l-- {
+		entry = h.greedy(entry, l, distance)
+	}
+	results := h.searchLayer([]candidate{entry}, 0, max(h.efSearch, k), distance, accept)
+	return results[:min(k, len(results))]
+}
+
+// exactSearch returns the k accepted nodes among n closest to the query,
+// sorted by increasing distance.
+func exactSearch(n, k int, distance func(int) float32, accept func(int) bool) []candidate {
+	var results []candidate
+	for id := range n {
+		if !accept(id) {
+			continue
+		}
+		d := distance(id)
+		if len(results) < k || d < results[len(results)-1].dist {
+			results = insertResult(results, candidate{id: id, dist: d}, k)
+		}
+	}
+	return results
+}
@@ -0,0 +1,419 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+)
+
+// Filter selects documents by their metadata.
+type Filter func(metadata map[string]any) bool
+
+// document is a document of the store.
+type document struct {
+	ID        string
+	Namespace string
+	Content   string
+	Metadata  map[string]any
+	Vector    []float32
+	deleted   bool
+}
+
+// Store is an in-memory vector store. It is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	embedder embeddings.Embedder
+	metric   DistanceMetric
+	// graph is the HNSW graph of the documents, or nil for exact searches.
+	graph *hnsw
+	docs  []document
+	norms []float32
+	ids   map[string]int
+	// tombstones is the number of deleted documents still in docs.
+	tombstones int
+}
+
+// _compactionRatio is the ratio of deleted documents to all documents past
+// which the store is compacted.
+const _compactionRatio = 0.5
+
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
+
+// New creates a new empty Store with options.
+func New(opts ...Option) (*Store, error) {
+	return applyClientOptions(opts...)
+}
+
+// Len returns the number of documents in the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.ids)
+}
+
+// AddDocuments adds documents to the store, in the namespace of the options
+// if any, and returns their ids.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.addDocuments(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids to the store, in the namespace
+// of the options if any, replacing the documents with the same ids.
+func (s *Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.addDocuments(ctx, ids, docs, opts)
+}
+
+// addDocuments embeds the documents and adds them with the ids, replacing
+// the documents with the same ids.
+func (s *Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := vectorstores.CheckDimensions(vectors, s.dimensions()); err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		s.delete(ids[i])
+		s.add(document{
+			ID:        ids[i],
+			Namespace: opts.NameSpace,
+			Content:   doc.PageContent,
+			Metadata:  doc.Metadata,
+			Vector:    vectors[i],
+		})
+	}
+	s.maybeCompact()
+	return nil
+}
+
+// Delete removes the documents with the given ids from the store. Unknown
+// ids are ignored.
+func (s *Store) Delete(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.delete(id)
+	}
+	s.maybeCompact()
+}
+
+// DeleteByIDs removes the documents with the given ids from the store.
+// Unknown ids are ignored.
+func (s *Store) DeleteByIDs(_ context.Context, ids []string, _ ...vectorstores.Option) error {
+	s.Delete(ids...)
+	return nil
+}
+
+// DeleteByFilter removes the documents of the namespace of the options whose
+// metadata is matched by the filter, which is either a vectorstores.Filter,
+// a map of metadata keys to values, or a Filter.
+func (s *Store) DeleteByFilter(_ context.Context, filters any, options ...vectorstores.Option) error {
+	if filters == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	match, err := filter(filters)
+	if err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range s.docs {
+		if !doc.deleted && doc.Namespace == opts.NameSpace && match(doc.Metadata) {
+			s.delete(doc.ID)
+		}
+	}
+	s.maybeCompact()
+	return nil
+}
+
+// SimilaritySearch returns the documents of the namespace of the options
+// nearest to the query. Filters are either a vectorstores.Filter, a map of
+// metadata keys to the values the documents must have, or a Filter.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by inmemory", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	match, err := filter(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := vectorstores.CheckDimensions([][]float32{vector}, s.dimensions()); err != nil {
+		return nil, err
+	}
+	queryNorm := norm(vector)
+	distance := func(id int) float32 {
+		return s.distance(vector, queryNorm, s.docs[id].Vector, s.norms[id])
+	}
+	accept := func(id int) bool {
+		doc := s.docs[id]
+		return !doc.deleted && doc.Namespace == opts.NameSpace && match(doc.Metadata)
+	}
+
+	var found []candidate
+	if s.graph != nil {
+		found = s.graph.search(numDocuments, distance, accept)
+	} else {
+		found = exactSearch(len(s.docs), numDocuments, distance, accept)
+	}
+
+	docs := make([]schema.Document, 0, len(found))
+	for _, c := range found {
+		score := s.score(c.dist)
+		if opts.ScoreThreshold != 0 && score < opts.ScoreThreshold {
+			break
+		}
+		docs = append(docs, schema.Document{
+			PageContent: s.docs[c.id].Content,
+			Metadata:    maps.Clone(s.docs[c.id].Metadata),
+			Score:       score,
+		})
+	}
+	return docs, nil
+}
+
+// add adds a document to the store. s.mu must be held.
+func (s *Store) add(doc document) {
+	id := len(s.docs)
+	s.docs = append(s.docs, doc)
+	s.norms = append(s.norms, norm(doc.Vector))
+	s.ids[doc.ID] = id
+	if s.graph != nil {
+		s.graph.add(id, func(a, b int) float32 {
+			return s.distance(s.docs[a].Vector, s.norms[a], s.docs[b].Vector, s.norms[b])
+		})
+	}
+}
+
+// delete marks the document with the id as deleted. s.mu must be held.
+func (s *Store) delete(id string) {
+	if i, ok := s.ids[id]; ok {
+		// the document stays in the HNSW graph to keep it connected.
+		s.docs[i].deleted = true
+		delete(s.ids, id)
+		s.tombstones++
+	}
+}
+
+// maybeCompact compacts the store once the ratio of deleted documents passes
+// _compactionRatio. s.mu must be held.
+func (s *Store) maybeCompact() {
+	if s.tombstones > 0 && float64(s.tombstones) > _compactionRatio*float64(len(s.docs)) {
+		s.compact()
+	}
+}
+
+// compact removes the deleted documents from the store and rebuilds the HNSW
+// graph without them. s.mu must be held.
+func (s *Store) compact() {
+	docs := s.docs
+	s.docs = make([]document, 0, len(s.ids))
+	s.norms = make([]float32, 0, len(s.ids))
+	s.ids = make(map[string]int, len(s.ids))
+	s.tombstones = 0
+	if s.graph != nil {
+		s.graph.reset()
+	}
+	for _, doc := range docs {
+		if !doc.deleted {
+			s.add(doc)
+		}
+	}
+}
+
+// dimensions returns the dimensions of the vectors of the store, or 0 if it
+// is empty. s.mu must be held.
+func (s *Store) dimensions() int {
+	if len(s.docs) == 0 {
+		return 0
+	}
+	return len(s.docs[0].Vector)
+}
+
+func (s *Store) distance(a []float32, normA float32, b []float32, normB float32) float32 {
+	if s.metric == DistanceL2 {
+		var sum float32
+		for i := range a {
+			d := a[i] - b[i]
+			sum += d * d
+		}
+		return float32(math.Sqrt(float64(sum)))
+	}
+	if normA == 0 || normB == 0 {
+		return 1
+	}
+	return 1 - dot(a, b)/(normA*normB)
+}
+
+func (s *Store) score(distance float32) float32 {
+	if s.metric == DistanceL2 {
+		return vectorstores.EuclideanDistanceScore(float64(distance))
+	}
+	return vectorstores.CosineDistanceScore(float64(distance))
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func norm(v []float32) float32 {
+	return float32(math.Sqrt(float64(dot(v, v))))
+}
+
+// filter returns the Filter of the filters of the options.
+func filter(filters any) (Filter, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case Filter:
+		return filters, nil
+	case vectorstores.Filter:
+		if err := filters.Validate(); err != nil {
+			return nil, err
+		}
+		return filters.Match, nil
+	case func(map[string]any) bool:
+		return filters, nil
+	case map[string]any:
+		return func(metadata map[string]any) bool {
+			for key, value := range filters {
+				if !equal(metadata[key], value) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, ErrInvalidFilters
+	}
+}
+
+// equal reports whether two metadata values are equal. Numbers are compared
+// by value, so that e.g. an int matches the float64 loaded from a parquet
+// snapshot.
+func equal(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s *Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
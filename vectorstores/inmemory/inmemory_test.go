@@ -0,0 +1,253 @@
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// newEmbedder returns an embedder mapping the texts of vectors to their
+// vector.
+func newEmbedder(t *testing.T, vectors map[string][]float32) embeddings.Embedder {
+	t.Helper()
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			result := make([][]float32, len(texts))
+			for i, text := range texts {
+				result[i] = vectors[text]
+			}
+			return result, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+var _vectors = map[string][]float32{ //nolint:gochecknoglobals
+	"cat":    {1, 0, 0},
+	"kitten": {0.9, 0.1, 0},
+	"dog":    {0, 1, 0},
+	"puppy":  {0.1, 0.9, 0},
+	"car":    {0, 0, 1},
+}
+
+func addDocuments(t *testing.T, store *Store) {
+	t.Helper()
+	_, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "cat", Metadata: map[string]any{"kind": "animal", "legs": 4}},
+		{PageContent: "dog", Metadata: map[string]any{"kind": "animal", "legs": 4}},
+		{PageContent: "car", Metadata: map[string]any{"kind": "vehicle", "wheels": 4}},
+	})
+	require.NoError(t, err)
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "puppy", Metadata: map[string]any{"kind": "animal"}},
+	}, vectorstores.WithNameSpace("young"))
+	require.NoError(t, err)
+}
+
+func contents(docs []schema.Document) []string {
+	result := make([]string, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.PageContent
+	}
+	return result
+}
+
+func TestInMemoryStore(t *testing.T) {
+	t.Parallel()
+	for name, opts := range map[string][]Option{
+		"exact": nil,
+		"hnsw":  {WithHNSW(0, 0, 0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store, err := New(append(opts, WithEmbedder(newEmbedder(t, _vectors)))...)
+			require.NoError(t, err)
+			addDocuments(t, store)
+			require.Equal(t, 4, store.Len())
+
+			docs, err := store.SimilaritySearch(context.Background(), "kitten", 2)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"cat", "dog"}, contents(docs))
+			assert.InDelta(t, 0.9939, docs[0].Score, 1e-4)
+			assert.Equal(t, map[string]any{"kind": "animal", "legs": 4}, docs[0].Metadata)
+
+			docs, err = store.SimilaritySearch(context.Background(), "kitten", 2,
+				vectorstores.WithFilters(map[string]any{"wheels": 4.0}))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"car"}, contents(docs))
+
+			docs, err = store.SimilaritySearch(context.Background(), "kitten", 2,
+				vectorstores.WithFilters(Filter(func(metadata map[string]any) bool {
+					return metadata["kind"] == "animal"
+				})), vectorstores.WithScoreThreshold(0.5))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"cat"}, contents(docs))
+
+			docs, err = store.SimilaritySearch(context.Background(), "kitten", 2,
+				vectorstores.WithFilter(vectorstores.Or(vectorstores.Gt("wheels", 2), vectorstores.Eq("kind", "plant"))))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"car"}, contents(docs))
+
+			docs, err = store.SimilaritySearch(context.Background(), "kitten", 2, vectorstores.WithNameSpace("young"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"puppy"}, contents(docs))
+
+			_, err = store.SimilaritySearch(context.Background(), "kitten", 2, vectorstores.WithFilters("kind"))
+			require.ErrorIs(t, err, ErrInvalidFilters)
+		})
+	}
+}
+
+func TestInMemoryStoreDelete(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)), WithDistanceMetric(DistanceL2))
+	require.NoError(t, err)
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{{PageContent: "cat"}, {PageContent: "dog"}})
+	require.NoError(t, err)
+
+	store.Delete(ids[0], "unknown")
+	require.Equal(t, 1, store.Len())
+	docs, err := store.SimilaritySearch(context.Background(), "kitten", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dog"}, contents(docs))
+	assert.InDelta(t, 1-1.2728*1.2728/2, docs[0].Score, 1e-4)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "dog"}},
+		vectorstores.WithEmbedder(newEmbedder(t, map[string][]float32{"dog": {1, 0}})))
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+}
+
+func TestInMemoryStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)))
+	require.NoError(t, err)
+	addDocuments(t, store)
+
+	_, err = store.SimilaritySearch(context.Background(), "kitten", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+}
+
+func TestInMemoryStoreCompaction(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)), WithHNSW(4, 16, 16))
+	require.NoError(t, err)
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "cat"}, {PageContent: "kitten"}, {PageContent: "dog"}, {PageContent: "puppy"},
+	})
+	require.NoError(t, err)
+
+	store.Delete(ids[0], ids[1])
+	assert.Len(t, store.docs, 4, "tombstones at the threshold are kept")
+	store.Delete(ids[2])
+	assert.Len(t, store.docs, 1)
+	assert.Equal(t, 0, store.tombstones)
+	assert.Len(t, store.graph.neighbors, 1)
+	assert.Equal(t, map[string]int{ids[3]: 0}, store.ids)
+
+	docs, err := store.SimilaritySearch(context.Background(), "cat", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"puppy"}, contents(docs))
+}
+
+func TestInMemoryStoreUpsertAndDeleteByFilter(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)), WithHNSW(4, 16, 8))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.UpsertDocuments(ctx, []string{"1", "2"}, []schema.Document{
+		{PageContent: "cat", Metadata: map[string]any{"kind": "animal"}},
+		{PageContent: "car", Metadata: map[string]any{"kind": "vehicle"}},
+	}))
+	require.NoError(t, store.UpsertDocuments(ctx, []string{"1"}, []schema.Document{
+		{PageContent: "dog", Metadata: map[string]any{"kind": "animal"}},
+	}))
+	require.Equal(t, 2, store.Len())
+	docs, err := store.SimilaritySearch(ctx, "cat", 3)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"dog", "car"}, contents(docs))
+
+	require.ErrorIs(t, store.UpsertDocuments(ctx, []string{"1"}, nil), vectorstores.ErrIDsMismatch)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Eq("kind", "animal")))
+	docs, err = store.SimilaritySearch(ctx, "cat", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"car"}, contents(docs))
+
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"2"}))
+	require.Equal(t, 0, store.Len())
+}
+
+func TestInMemoryStoreGob(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)))
+	require.NoError(t, err)
+	addDocuments(t, store)
+
+	var buf bytes.Buffer
+	require.NoError(t, store.Save(&buf, FormatGob))
+
+	loaded, err := New(WithEmbedder(newEmbedder(t, _vectors)), WithHNSW(4, 10, 10))
+	require.NoError(t, err)
+	require.NoError(t, loaded.Load(&buf, FormatGob))
+	require.Equal(t, 4, loaded.Len())
+
+	docs, err := loaded.SimilaritySearch(context.Background(), "kitten", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat"}, contents(docs))
+	// gob keeps the types of metadata values.
+	assert.Equal(t, map[string]any{"kind": "animal", "legs": 4}, docs[0].Metadata)
+
+	require.ErrorIs(t, loaded.Load(strings.NewReader(""), Format(-1)), ErrUnknownFormat)
+}
+
+func TestHNSWRecall(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewPCG(3, 4)) //nolint:gosec
+	randomVector := func() []float32 {
+		v := make([]float32, 16)
+		for i := range v {
+			v[i] = rng.Float32()*2 - 1
+		}
+		return v
+	}
+
+	store, err := New(WithEmbedder(newEmbedder(t, nil)), WithHNSW(8, 64, 32))
+	require.NoError(t, err)
+	for i := range 1000 {
+		store.add(document{ID: strconv.Itoa(i), Vector: randomVector()})
+	}
+
+	const k = 10
+	hits := 0
+	for range 50 {
+		query := randomVector()
+		queryNorm := norm(query)
+		distance := func(id int) float32 {
+			return store.distance(query, queryNorm, store.docs[id].Vector, store.norms[id])
+		}
+		accept := func(int) bool { return true }
+		exact := exactSearch(len(store.docs), k, distance, accept)
+		approximate := store.graph.search(k, distance, accept)
+		require.Len(t, approximate, k)
+		for _, c := range approximate {
+			for _, e := range exact {
+				if c.id == e.id {
+					hits++
+				}
+			}
+		}
+	}
+	assert.Greater(t, float64(hits)/(50*k), 0.9)
+}
@@ -0,0 +1,90 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// DistanceMetric is the distance used to compare vectors.
+type DistanceMetric string
+
+const (
+	// DistanceCosine is the cosine distance. Document scores are the cosine
+	// similarity.
+	DistanceCosine DistanceMetric = "cosine"
+	// DistanceL2 is the euclidean distance. Document scores are
+	// 1 / (1 + distance).
+	DistanceL2 DistanceMetric = "l2"
+)
+
+const (
+	_defaultM              = 16
+	_defaultEfConstruction = 200
+	_defaultEfSearch       = 50
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithDistanceMetric is an option for specifying the distance metric.
+// Defaults to DistanceCosine.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+// WithHNSW is an option for searching an HNSW graph instead of comparing the
+// query to every document. m is the number of neighbors of each node,
+// efConstruction and efSearch the number of candidates considered when
+// adding documents and searching. Zero values use the defaults of 16, 200
+// and 50.
+func WithHNSW(m, efConstruction, efSearch int) Option {
+	return func(s *Store) {
+		s.graph = newHNSW(
+			valueOr(m, _defaultM),
+			valueOr(efConstruction, _defaultEfConstruction),
+			valueOr(efSearch, _defaultEfSearch),
+		)
+	}
+}
+
+func valueOr(value, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		metric: DistanceCosine,
+		ids:    map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.metric != DistanceCosine && s.metric != DistanceL2 {
+		return nil, fmt.Errorf("%w: unsupported distance metric %q", ErrInvalidOptions, s.metric)
+	}
+	if s.graph != nil && (s.graph.m < 2 || s.graph.efConstruction < 1 || s.graph.efSearch < 1) {
+		return nil, fmt.Errorf("%w: invalid HNSW parameters", ErrInvalidOptions)
+	}
+	return s, nil
+}
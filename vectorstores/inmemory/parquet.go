@@ -0,0 +1,61 @@
+package inmemory
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRow is a document in a parquet snapshot.
+type parquetRow struct {
+	ID        string    `parquet:"id"`
+	Namespace string    `parquet:"namespace"`
+	Content   string    `parquet:"content"`
+	Metadata  string    `parquet:"metadata"`
+	Vector    []float32 `parquet:"vector"`
+}
+
+func writeParquet(w io.Writer, docs []document) error {
+	rows := make([]parquetRow, len(docs))
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		rows[i] = parquetRow{
+			ID:        doc.ID,
+			Namespace: doc.Namespace,
+			Content:   doc.Content,
+			Metadata:  string(metadata),
+			Vector:    doc.Vector,
+		}
+	}
+	return parquet.Write(w, rows)
+}
+
+func readParquet(r io.Reader) ([]document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parquet.Read[parquetRow](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]document, len(rows))
+	for i, row := range rows {
+		docs[i] = document{
+			ID:        row.ID,
+			Namespace: row.Namespace,
+			Content:   row.Content,
+			Vector:    row.Vector,
+		}
+		if err := json.Unmarshal([]byte(row.Metadata), &docs[i].Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
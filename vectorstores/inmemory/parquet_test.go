@@ -0,0 +1,31 @@
+package inmemory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStoreParquet(t *testing.T) {
+	t.Parallel()
+	store, err := New(WithEmbedder(newEmbedder(t, _vectors)))
+	require.NoError(t, err)
+	addDocuments(t, store)
+
+	path := filepath.Join(t.TempDir(), "store.parquet")
+	require.NoError(t, store.SaveFile(path))
+
+	loaded, err := New(WithEmbedder(newEmbedder(t, _vectors)))
+	require.NoError(t, err)
+	require.NoError(t, loaded.LoadFile(path))
+	require.Equal(t, 4, loaded.Len())
+
+	docs, err := loaded.SimilaritySearch(context.Background(), "kitten", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat"}, contents(docs))
+	// metadata is stored as JSON.
+	assert.Equal(t, map[string]any{"kind": "animal", "legs": float64(4)}, docs[0].Metadata)
+}
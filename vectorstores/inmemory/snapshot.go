@@ -0,0 +1,133 @@
+package inmemory
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrUnknownFormat is returned when saving or loading a snapshot in an
+// unknown format.
+var ErrUnknownFormat = errors.New("unknown snapshot format")
+
+// Format is the file format of a snapshot of a store.
+type Format int
+
+const (
+	// FormatGob is the encoding/gob format, which keeps the Go types of
+	// metadata values. Values other than basic types, []any and
+	// map[string]any must be registered with gob.Register.
+	FormatGob Format = iota
+	// FormatParquet is the parquet format, readable by other tools. Metadata
+	// is stored as JSON, so numbers are loaded as float64.
+	FormatParquet
+)
+
+func init() { //nolint:gochecknoinits
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// snapshot is the gob encoded content of a store.
+type snapshot struct {
+	Documents []document
+}
+
+// Save writes the documents of the store to w in the given format. HNSW
+// graphs aren't saved, they are rebuilt on Load.
+func (s *Store) Save(w io.Writer, format Format) error {
+	s.mu.RLock()
+	docs := make([]document, 0, len(s.ids))
+	for _, doc := range s.docs {
+		if !doc.deleted {
+			docs = append(docs, doc)
+		}
+	}
+	s.mu.RUnlock()
+
+	switch format {
+	case FormatGob:
+		return gob.NewEncoder(w).Encode(snapshot{Documents: docs})
+	case FormatParquet:
+		return writeParquet(w, docs)
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+	}
+}
+
+// Load replaces the documents of the store with those read from r in the
+// given format.
+func (s *Store) Load(r io.Reader, format Format) error {
+	var docs []document
+	switch format {
+	case FormatGob:
+		var snap snapshot
+		if err := gob.NewDecoder(r).Decode(&snap); err != nil {
+			return fmt.Errorf("decode snapshot: %w", err)
+		}
+		docs = snap.Documents
+	case FormatParquet:
+		var err error
+		if docs, err = readParquet(r); err != nil {
+			return fmt.Errorf("read parquet snapshot: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownFormat, format)
+	}
+
+	vectors := make([][]float32, len(docs))
+	for i, doc := range docs {
+		vectors[i] = doc.Vector
+	}
+	if err := vectorstores.CheckDimensions(vectors, 0); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs, s.norms, s.ids = nil, nil, make(map[string]int, len(docs))
+	s.tombstones = 0
+	if s.graph != nil {
+		s.graph.reset()
+	}
+	for _, doc := range docs {
+		s.add(doc)
+	}
+	return nil
+}
+
+// SaveFile saves the store to a file, in the parquet format if its name ends
+// with ".parquet" and in the gob format otherwise.
+func (s *Store) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Save(f, formatOf(path)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile loads the store from a file saved by SaveFile.
+func (s *Store) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.Load(f, formatOf(path))
+}
+
+func formatOf(path string) Format {
+	if filepath.Ext(path) == ".parquet" {
+		return FormatParquet
+	}
+	return FormatGob
+}
@@ -0,0 +1,76 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _exprOperators are the operators of comparison filters in boolean
+// expressions.
+var _exprOperators = map[vectorstores.FilterOp]string{ //nolint:gochecknoglobals
+	vectorstores.FilterEq:  "==",
+	vectorstores.FilterNe:  "!=",
+	vectorstores.FilterGt:  ">",
+	vectorstores.FilterGte: ">=",
+	vectorstores.FilterLt:  "<",
+	vectorstores.FilterLte: "<=",
+}
+
+// filterExpr translates a filter to a Milvus boolean expression on the
+// scalar fields of the collection, see
+// https://milvus.io/docs/boolean.md. Filter keys are field names.
+func filterExpr(f vectorstores.Filter) (string, error) {
+	if err := f.Validate(); err != nil {
+		return "", err
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		if len(f.Filters) == 0 {
+			return fmt.Sprint(f.Op == vectorstores.FilterAnd), nil
+		}
+		exprs := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			expr, err := filterExpr(operand)
+			if err != nil {
+				return "", err
+			}
+			exprs = append(exprs, expr)
+		}
+		return "(" + strings.Join(exprs, " "+string(f.Op)+" ") + ")", nil
+	case vectorstores.FilterIn:
+		values := make([]string, 0, len(f.Values))
+		for _, value := range f.Values {
+			literal, err := exprLiteral(value)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, literal)
+		}
+		return fmt.Sprintf("(%s in [%s])", f.Key, strings.Join(values, ", ")), nil
+	}
+
+	op, ok := _exprOperators[f.Op]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	literal, err := exprLiteral(f.Value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s %s %s)", f.Key, op, literal), nil
+}
+
+// exprLiteral returns the literal of a value in a boolean expression.
+func exprLiteral(value any) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(value), nil
+	}
+	return "", fmt.Errorf("%w: unsupported value %v", vectorstores.ErrInvalidFilter, value)
+}
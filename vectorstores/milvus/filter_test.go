@@ -0,0 +1,25 @@
+package milvus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestFilterExpr(t *testing.T) {
+	t.Parallel()
+	expr, err := filterExpr(vectorstores.And(
+		vectorstores.Eq("lang", `e"n`),
+		vectorstores.Or(vectorstores.Gte("year", 2020), vectorstores.In("tag", "a", "b")),
+		vectorstores.Ne("draft", true),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, `((lang == "e\"n") and ((year >= 2020) or (tag in ["a", "b"])) and (draft != true))`, expr)
+
+	_, err = filterExpr(vectorstores.Exists("author"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+	_, err = filterExpr(vectorstores.Eq("lang", []string{"en"}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
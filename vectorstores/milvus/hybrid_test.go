@@ -0,0 +1,136 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeClient is a milvus client recording the collection, indexes and
+// columns created and the hybrid searches made. Other methods panic.
+type fakeClient struct {
+	client.Client
+
+	schema    *entity.Schema
+	indexes   map[string]entity.Index
+	columns   []entity.Column
+	rerankers []client.Reranker
+	requests  [][]*client.ANNSearchRequest
+	results   []client.SearchResult
+}
+
+func (c *fakeClient) CreateCollection(_ context.Context, schema *entity.Schema, _ int32,
+	_ ...client.CreateCollectionOption,
+) error {
+	c.schema = schema
+	return nil
+}
+
+func (c *fakeClient) CreateIndex(_ context.Context, _ string, field string, idx entity.Index, _ bool,
+	_ ...client.IndexOption,
+) error {
+	c.indexes[field] = idx
+	return nil
+}
+
+func (c *fakeClient) LoadCollection(context.Context, string, bool, ...client.LoadCollectionOption) error {
+	return nil
+}
+
+func (c *fakeClient) Insert(_ context.Context, _ string, _ string, columns ...entity.Column) (entity.Column, error) {
+	c.columns = columns
+	return entity.NewColumnInt64(_defaultPrimaryField, []int64{1}), nil
+}
+
+func (c *fakeClient) HybridSearch(_ context.Context, _ string, _ []string, _ int, _ []string,
+	reranker client.Reranker, requests []*client.ANNSearchRequest, _ ...client.SearchQueryOptionFunc,
+) ([]client.SearchResult, error) {
+	c.rerankers = append(c.rerankers, reranker)
+	c.requests = append(c.requests, requests)
+	return c.results, nil
+}
+
+func newFakeStore(t *testing.T, opts ...Option) (Store, *fakeClient) {
+	t.Helper()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i := range vectors {
+				vectors[i] = []float32{1, 0}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	idx, err := entity.NewIndexHNSW(entity.L2, 8, 64)
+	require.NoError(t, err)
+
+	s, err := applyClientOptions(append([]Option{WithEmbedder(e), WithIndex(idx)}, opts...)...)
+	require.NoError(t, err)
+	fake := &fakeClient{indexes: map[string]entity.Index{}}
+	s.client = fake
+	return s, fake
+}
+
+func TestHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	s, fake := newFakeStore(t, WithSparseEmbedder(embeddings.NewBM25(), "sparse"))
+	fake.results = []client.SearchResult{{
+		ResultCount: 2,
+		Fields: client.ResultSet{
+			entity.NewColumnVarChar(_defaultTextField, []string{"tokyo", "paris"}),
+			entity.NewColumnVarChar(_defaultMetaField, []string{`{"year":2020}`, `{}`}),
+		},
+		Scores: []float32{0.9, 0.4},
+	}}
+
+	ctx := context.Background()
+	_, err := s.AddDocuments(ctx, []schema.Document{{PageContent: "tokyo"}})
+	require.NoError(t, err)
+	require.Equal(t, entity.FieldTypeSparseVector, fake.schema.Fields[4].DataType)
+	require.Equal(t, string(entity.SparseInverted), fake.indexes["sparse"].Params()["index_type"])
+	sparseCol, ok := fake.columns[3].(*entity.ColumnSparseFloatVector)
+	require.True(t, ok)
+	require.Equal(t, "sparse", sparseCol.Name())
+	require.Equal(t, 1, sparseCol.Len())
+
+	// the store searches both vectors by default, ignoring the threshold.
+	docs, err := s.SimilaritySearch(ctx, "tokyo", 2, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"year": float64(2020)}, Score: 0.9},
+		{PageContent: "paris", Metadata: map[string]any{}, Score: 0.4},
+	}, docs)
+	require.Len(t, fake.requests[0], 2)
+	require.Equal(t, client.NewRRFReranker().GetParams(), fake.rerankers[0].GetParams())
+
+	docs, err = s.SimilaritySearch(ctx, "tokyo", 2, vectorstores.WithScoreThreshold(0.5),
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{Fusion: vectorstores.FusionWeighted, Alpha: 0.75}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, client.NewWeightedReranker([]float64{0.75, 0.25}).GetParams(), fake.rerankers[1].GetParams())
+
+	_, err = s.SimilaritySearch(ctx, "tokyo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{Alpha: 2}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+}
+
+func TestHybridSearchErrors(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newFakeStore(t)
+	_, err := s.SimilaritySearch(context.Background(), "tokyo", 1,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+
+	_, err = applyClientOptions(WithEmbedder(s.embedder), WithIndex(s.index),
+		WithSparseEmbedder(embeddings.NewBM25(), ""))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
@@ -32,19 +35,35 @@ type Store struct {
 	consistencyLevel entity.ConsistencyLevel
 	index            entity.Index
 	embedder         embeddings.Embedder
+	sparseEmbedder   embeddings.SparseEmbedder
+	sparseField      string
+	sparseIndex      entity.Index
 	client           client.Client
 	metricType       entity.MetricType
 	searchParameters entity.SearchParam
 	schema           *entity.Schema
+	scalarFields     []*entity.Field
+	partitionNum     int64
 }
 
 var (
 	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
 
 	ErrEmbedderWrongNumberVectors = errors.New(
 		"number of vectors from embedder does not match number of documents",
 	)
 	ErrColumnNotFound = errors.New("invalid field")
+	// ErrInvalidID is returned when deleting documents with ids that aren't
+	// integers, as the primary keys of the collection are.
+	ErrInvalidID = errors.New("invalid id")
+	// ErrUnsupportedFilter is returned when searching or deleting documents
+	// with a filter that is neither a boolean expression nor a
+	// vectorstores.Filter.
+	ErrUnsupportedFilter = errors.New("unsupported filter")
+	// ErrInvalidMetadata is returned when adding documents whose metadata
+	// values don't match the type of their scalar fields.
+	ErrInvalidMetadata = errors.New("invalid metadata")
 )
 
 // New creates an active client connection to the (specified, or default) collection in the Milvus server
@@ -144,8 +163,19 @@ func (s *Store) createCollection(ctx context.Context, dim int) error {
 			},
 		},
 	}
+	if s.sparseField != "" {
+		s.schema.Fields = append(s.schema.Fields, &entity.Field{
+			Name:     s.sparseField,
+			DataType: entity.FieldTypeSparseVector,
+		})
+	}
+	s.schema.Fields = append(s.schema.Fields, s.scalarFields...)
 
-	err := s.client.CreateCollection(ctx, s.schema, s.shardNum, client.WithMetricsType(s.metricType))
+	options := []client.CreateCollectionOption{client.WithMetricsType(s.metricType)}
+	if s.partitionNum > 0 {
+		options = append(options, client.WithPartitionNum(s.partitionNum))
+	}
+	err := s.client.CreateCollection(ctx, s.schema, s.shardNum, options...)
 	if err != nil {
 		return err
 	}
@@ -153,12 +183,33 @@ func (s *Store) createCollection(ctx context.Context, dim int) error {
 	return nil
 }
 
+// vectorDimensions returns the dimension of the vector field of the
+// collection, or 0 if it is unknown.
+func (s *Store) vectorDimensions() int {
+	if s.schema == nil {
+		return 0
+	}
+	for _, field := range s.schema.Fields {
+		if field.Name == s.vectorField {
+			dim, _ := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+			return dim
+		}
+	}
+	return 0
+}
+
 func (s *Store) createIndex(ctx context.Context) error {
 	if !s.collectionExists {
 		return nil
 	}
 
-	return s.client.CreateIndex(ctx, s.collectionName, s.vectorField, s.index, s.async)
+	if err := s.client.CreateIndex(ctx, s.collectionName, s.vectorField, s.index, s.async); err != nil {
+		return err
+	}
+	if s.sparseField == "" {
+		return nil
+	}
+	return s.client.CreateIndex(ctx, s.collectionName, s.sparseField, s.sparseIndex, s.async)
 }
 
 func (s *Store) createSearchParams(ctx context.Context) error {
@@ -190,10 +241,39 @@ func (s *Store) load(ctx context.Context) error {
 }
 
 // AddDocuments adds the text and metadata from the documents to the Milvus collection associated with 'Store'.
-// and returns the ids of the added documents.
+// and returns the ids of the added documents. Documents are embedded and inserted
+// in batches of 1000 by default, in the partition named by the name space of
+// the options if it is given.
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
-	_ ...vectorstores.Option,
+	options ...vectorstores.Option,
 ) ([]string, error) {
+	opts := s.getOptions(options...)
+	partition := s.getPartition(opts)
+	batchIDs := make([][]string, len(docs))
+	// the collection is initialized by one batch at a time.
+	var mu sync.Mutex
+	err := vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		ids, err := s.insert(ctx, &mu, partition, docs[start:end])
+		if err != nil {
+			return err
+		}
+		batchIDs[start] = ids
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, batch := range batchIDs {
+		ids = append(ids, batch...)
+	}
+	return ids, nil
+}
+
+// insert embeds the documents and inserts them in the partition,
+// initializing the collection if needed, and returns their ids.
+func (s *Store) insert(ctx context.Context, mu *sync.Mutex, partition string, docs []schema.Document) ([]string, error) {
 	texts := make([]string, 0, len(docs))
 	metadatas := make([]string, 0, len(docs))
 	for _, doc := range docs {
@@ -212,25 +292,243 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 	if len(vectors) != len(docs) {
 		return nil, ErrEmbedderWrongNumberVectors
 	}
-	if err := s.init(ctx, len(vectors[0])); err != nil {
+	mu.Lock()
+	err = s.init(ctx, len(vectors[0]))
+	dimensions := s.vectorDimensions()
+	mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
 		return nil, err
 	}
 
 	textCol := entity.NewColumnVarChar(s.textField, texts)
 	metaCol := entity.NewColumnVarChar(s.metaField, metadatas)
 	vectorCol := entity.NewColumnFloatVector(s.vectorField, len(vectors[0]), vectors)
-	_, err = s.client.Insert(ctx, s.collectionName, s.partitionName, vectorCol, metaCol, textCol)
+	columns := []entity.Column{vectorCol, metaCol, textCol}
+	if s.sparseEmbedder != nil {
+		sparseCol, err := s.sparseColumn(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, sparseCol)
+	}
+	for _, field := range s.scalarFields {
+		column, err := scalarColumn(field, docs)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	idCol, err := s.client.Insert(ctx, s.collectionName, partition, columns...)
 	if err != nil {
 		return nil, err
 	}
 
+	switch idCol := idCol.(type) {
+	case *entity.ColumnInt64:
+		ids := make([]string, 0, idCol.Len())
+		for _, id := range idCol.Data() {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		return ids, nil
+	case *entity.ColumnVarChar:
+		return idCol.Data(), nil
+	}
 	return nil, nil
 }
 
+// sparseColumn returns the column of the sparse vector field with the
+// sparse embeddings of the texts.
+func (s *Store) sparseColumn(ctx context.Context, texts []string) (entity.Column, error) {
+	vectors, err := s.sparseEmbedder.EmbedDocumentsSparse(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+	sparse := make([]entity.SparseEmbedding, len(vectors))
+	for i, vector := range vectors {
+		if sparse[i], err = sparseEmbedding(vector); err != nil {
+			return nil, err
+		}
+	}
+	return entity.NewColumnSparseVectors(s.sparseField, sparse), nil
+}
+
+// sparseEmbedding converts a sparse vector to a milvus sparse embedding.
+func sparseEmbedding(vector embeddings.SparseVector) (entity.SparseEmbedding, error) {
+	return entity.NewSliceSparseEmbedding(vector.Indices(), vector.Values())
+}
+
+// DeleteByIDs deletes the entities with the primary keys ids, as returned by
+// AddDocuments, from the collection, or from the partition named by the name
+// space of the options.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		if s.primaryFieldType() == entity.FieldTypeVarChar {
+			keys[i] = strconv.Quote(id)
+			continue
+		}
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("%w: %q is not an integer", ErrInvalidID, id)
+		}
+		keys[i] = id
+	}
+	expr := fmt.Sprintf("%s in [%s]", s.primaryField, strings.Join(keys, ","))
+	return s.client.Delete(ctx, s.collectionName, s.getPartition(s.getOptions(options...)), expr)
+}
+
+// DeleteByFilter deletes the entities matched by the filter, a Milvus
+// boolean expression or a vectorstores.Filter on the scalar fields of the
+// collection, from the collection, or from the partition named by the name
+// space of the options. Metadata is stored as a string and can't be
+// filtered on.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	expr, err := s.getExpr(filter)
+	if err != nil {
+		return err
+	}
+	if expr == "" {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.client.Delete(ctx, s.collectionName, s.getPartition(s.getOptions(options...)), expr)
+}
+
+// getExpr returns the boolean expression of a filter, given as an
+// expression or as a vectorstores.Filter.
+func (s Store) getExpr(filter any) (string, error) {
+	switch filter := filter.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return filter, nil
+	case vectorstores.Filter:
+		return filterExpr(filter)
+	}
+	return "", fmt.Errorf("%w: filter must be a boolean expression", ErrUnsupportedFilter)
+}
+
+// getPartition returns the partition named by the name space of the
+// options, or the partition of the store.
+func (s Store) getPartition(opts vectorstores.Options) string {
+	if opts.NameSpace != "" {
+		return opts.NameSpace
+	}
+	return s.partitionName
+}
+
+// _scalarFieldTypes are the supported types of scalar fields.
+var _scalarFieldTypes = map[entity.FieldType]bool{ //nolint:gochecknoglobals
+	entity.FieldTypeBool:    true,
+	entity.FieldTypeInt64:   true,
+	entity.FieldTypeFloat:   true,
+	entity.FieldTypeDouble:  true,
+	entity.FieldTypeVarChar: true,
+}
+
+// scalarColumn returns the column of a scalar field with the metadata values
+// of its name, or zero values for documents without it.
+func scalarColumn(field *entity.Field, docs []schema.Document) (entity.Column, error) {
+	values := make([]reflect.Value, len(docs))
+	for i, doc := range docs {
+		values[i] = reflect.ValueOf(doc.Metadata[field.Name])
+	}
+	invalid := func(i int) error {
+		return fmt.Errorf("%w: metadata %q of type %T", ErrInvalidMetadata, field.Name, docs[i].Metadata[field.Name])
+	}
+
+	switch field.DataType { //nolint:exhaustive
+	case entity.FieldTypeBool:
+		data := make([]bool, len(docs))
+		for i, v := range values {
+			switch {
+			case !v.IsValid():
+			case v.Kind() == reflect.Bool:
+				data[i] = v.Bool()
+			default:
+				return nil, invalid(i)
+			}
+		}
+		return entity.NewColumnBool(field.Name, data), nil
+	case entity.FieldTypeInt64:
+		data := make([]int64, len(docs))
+		for i, v := range values {
+			switch {
+			case !v.IsValid():
+			case v.CanInt():
+				data[i] = v.Int()
+			case v.CanUint():
+				data[i] = int64(v.Uint()) //nolint:gosec
+			case v.CanFloat():
+				data[i] = int64(v.Float())
+			default:
+				return nil, invalid(i)
+			}
+		}
+		return entity.NewColumnInt64(field.Name, data), nil
+	case entity.FieldTypeFloat, entity.FieldTypeDouble:
+		data := make([]float64, len(docs))
+		for i, v := range values {
+			switch {
+			case !v.IsValid():
+			case v.CanInt():
+				data[i] = float64(v.Int())
+			case v.CanUint():
+				data[i] = float64(v.Uint())
+			case v.CanFloat():
+				data[i] = v.Float()
+			default:
+				return nil, invalid(i)
+			}
+		}
+		if field.DataType == entity.FieldTypeDouble {
+			return entity.NewColumnDouble(field.Name, data), nil
+		}
+		floats := make([]float32, len(data))
+		for i, f := range data {
+			floats[i] = float32(f)
+		}
+		return entity.NewColumnFloat(field.Name, floats), nil
+	case entity.FieldTypeVarChar:
+		data := make([]string, len(docs))
+		for i, v := range values {
+			if v.IsValid() {
+				data[i] = fmt.Sprint(v.Interface())
+			}
+		}
+		return entity.NewColumnVarChar(field.Name, data), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type of scalar field %q", ErrInvalidOptions, field.Name)
+}
+
+// primaryFieldType returns the data type of the primary key of the
+// collection, which is an int64 unless the collection was created otherwise.
+func (s Store) primaryFieldType() entity.FieldType {
+	if s.schema != nil {
+		for _, field := range s.schema.Fields {
+			if field.Name == s.primaryField {
+				return field.DataType
+			}
+		}
+	}
+	return entity.FieldTypeInt64
+}
+
 func (s *Store) getSearchFields() []string {
 	fields := []string{}
 	for _, f := range s.schema.Fields {
-		if f.DataType == entity.FieldTypeBinaryVector || f.DataType == entity.FieldTypeFloatVector {
+		switch f.DataType { //nolint:exhaustive
+		case entity.FieldTypeBinaryVector, entity.FieldTypeFloatVector, entity.FieldTypeSparseVector:
 			continue
 		}
 		fields = append(fields, f.Name)
@@ -246,7 +544,21 @@ func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	return opts
 }
 
-func (s Store) convertResultToDocument(searchResult []client.SearchResult) ([]schema.Document, error) {
+// getPartitions returns the partitions to search, the partition named by
+// the name space of the options or the partition of the store, if any.
+func (s Store) getPartitions(opts vectorstores.Options) []string {
+	partitions := []string{}
+	if partition := s.getPartition(opts); partition != "" {
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+// convertResultToDocument converts the search results to documents, whose
+// scores are converted by score.
+func (s Store) convertResultToDocument(searchResult []client.SearchResult,
+	score func(float32) float32,
+) ([]schema.Document, error) {
 	docs := []schema.Document{}
 	var err error
 
@@ -277,40 +589,56 @@ func (s Store) convertResultToDocument(searchResult []client.SearchResult) ([]sc
 			if err := json.Unmarshal([]byte(metaStr), &doc.Metadata); err != nil {
 				return nil, err
 			}
-			doc.Score = res.Scores[i]
+			doc.Score = score(res.Scores[i])
 			docs = append(docs, doc)
 		}
 	}
 	return docs, nil
 }
 
+// SimilaritySearch searches the collection for the documents most similar
+// to the query. If the store has a sparse embedder or the options have a
+// hybrid search, both the dense and the sparse vectors are searched and the
+// results are fused by Milvus.
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return s.hybridSearch(ctx, query, *opts.HybridSearch, numDocuments, opts)
+	}
 
 	vector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	if s.sparseEmbedder != nil {
+		sparse, err := s.sparseEmbedder.EmbedQuerySparse(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		opts.ScoreThreshold = 0
+		return s.searchHybrid(ctx, vector, sparse, vectorstores.HybridSearch{}, numDocuments, opts)
+	}
+
 	if err := s.init(ctx, len(vector)); err != nil {
 		return nil, err
 	}
+	expr, err := s.getExpr(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
 	vectors := []entity.Vector{
 		entity.FloatVector(vector),
 	}
-	partitions := []string{}
-	if s.partitionName != "" {
-		partitions = append(partitions, s.partitionName)
-	}
 	sp := s.searchParameters
 	if opts.ScoreThreshold > 0 {
-		sp.AddRadius(float64(opts.ScoreThreshold))
+		sp.AddRadius(s.radius(opts.ScoreThreshold))
 	}
 
 	searchResult, err := s.client.Search(ctx, s.collectionName,
-		partitions,
-		"",
+		s.getPartitions(opts),
+		expr,
 		s.getSearchFields(),
 		vectors,
 		s.vectorField,
@@ -323,5 +651,121 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	return s.convertResultToDocument(searchResult)
+	return s.convertResultToDocument(searchResult, s.score)
+}
+
+// hybridSearch searches both the dense and the sparse vectors of the
+// collection, embedding the query unless the vectors are given.
+func (s Store) hybridSearch(ctx context.Context,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	opts vectorstores.Options,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+	if s.sparseField == "" {
+		return nil, fmt.Errorf("%w: missing sparse embedder", vectorstores.ErrInvalidHybridSearch)
+	}
+
+	vector := hybrid.Vector
+	if vector == nil {
+		var err error
+		if vector, err = s.embedder.EmbedQuery(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	sparse := hybrid.Sparse
+	if sparse == nil {
+		var err error
+		if sparse, err = s.sparseEmbedder.EmbedQuerySparse(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.searchHybrid(ctx, vector, sparse, hybrid, numDocuments, opts)
+}
+
+// searchHybrid searches the dense and the sparse vector fields of the
+// collection with the vectors and has Milvus rerank the results with the
+// fusion strategy of the hybrid search. Documents are returned with their
+// fused scores, to which the score threshold of the options applies.
+func (s Store) searchHybrid(ctx context.Context,
+	vector []float32,
+	sparse embeddings.SparseVector,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	opts vectorstores.Options,
+) ([]schema.Document, error) {
+	if err := s.init(ctx, len(vector)); err != nil {
+		return nil, err
+	}
+	expr, err := s.getExpr(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	sparseVector, err := sparseEmbedding(sparse)
+	if err != nil {
+		return nil, err
+	}
+	sparseParameters, err := entity.NewIndexSparseInvertedSearchParam(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var reranker client.Reranker = client.NewRRFReranker()
+	if hybrid.Fusion == vectorstores.FusionWeighted {
+		reranker = client.NewWeightedReranker([]float64{float64(hybrid.Alpha), float64(1 - hybrid.Alpha)})
+	}
+	requests := []*client.ANNSearchRequest{
+		client.NewANNSearchRequest(s.vectorField, s.metricType, expr,
+			[]entity.Vector{entity.FloatVector(vector)}, s.searchParameters, numDocuments),
+		client.NewANNSearchRequest(s.sparseField, entity.IP, expr,
+			[]entity.Vector{sparseVector}, sparseParameters, numDocuments),
+	}
+	searchResult, err := s.client.HybridSearch(ctx, s.collectionName,
+		s.getPartitions(opts),
+		numDocuments,
+		s.getSearchFields(),
+		reranker,
+		requests,
+		client.WithSearchQueryConsistencyLevel(s.consistencyLevel),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.convertResultToDocument(searchResult, func(score float32) float32 { return score })
+	if err != nil {
+		return nil, err
+	}
+	for i, doc := range docs {
+		if doc.Score < opts.ScoreThreshold {
+			return docs[:i], nil
+		}
+	}
+	return docs, nil
+}
+
+// score converts the score of a document found, a squared distance for the
+// L2 metric, to a relevance score.
+func (s Store) score(score float32) float32 {
+	switch s.metricType { //nolint:exhaustive
+	case entity.L2:
+		return vectorstores.SquaredEuclideanDistanceScore(float64(score))
+	case entity.IP:
+		return vectorstores.DotProductScore(float64(score))
+	}
+	return vectorstores.CosineSimilarityScore(float64(score))
+}
+
+// radius converts a score threshold to the radius of range searches, the
+// maximum squared distance for the L2 metric and the minimum similarity
+// otherwise.
+func (s Store) radius(scoreThreshold float32) float64 {
+	if s.metricType == entity.L2 {
+		return vectorstores.SquaredEuclideanDistanceThreshold(scoreThreshold)
+	}
+	return float64(scoreThreshold)
 }
@@ -93,17 +93,17 @@ func TestMilvusConnection(t *testing.T) {
 		{PageContent: "New York"},
 	})
 	require.NoError(t, err)
-	// test with a score threshold of 0.8, expected 6 documents
+	// test with a score threshold of 0.85, expected 6 documents
 	japanRes, err := storer.SimilaritySearch(context.Background(),
 		"Which of these are cities in Japan", 10,
-		vectorstores.WithScoreThreshold(0.3))
+		vectorstores.WithScoreThreshold(0.85))
 	require.NoError(t, err)
 	require.Len(t, japanRes, 6)
 
-	// test with a score threshold of 0, expected all 10 documents
+	// test with a score threshold of 0.5, expected all 10 documents
 	euRes, err := storer.SimilaritySearch(context.Background(),
 		"Which of these are cities are located in Europe?", 10,
-		vectorstores.WithScoreThreshold(1),
+		vectorstores.WithScoreThreshold(0.5),
 	)
 	require.NoError(t, err)
 	require.Len(t, euRes, 10)
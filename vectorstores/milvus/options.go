@@ -17,6 +17,7 @@ const (
 	_defaultVectorField      = "vector"
 	_defaultMaxLength        = 65535
 	_defaultEF               = 10
+	_defaultBatchSize        = 1000
 )
 
 // ErrInvalidOptions is returned when the options given are invalid.
@@ -130,6 +131,45 @@ func WithMetricType(metricType entity.MetricType) Option {
 	}
 }
 
+// WithScalarFields adds scalar fields to the schema of the collection,
+// filled with the metadata values of their names, to filter searches and
+// deletions with boolean expressions on them. A VarChar or Int64 field with
+// IsPartitionKey set is the partition key of the collection.
+func WithScalarFields(fields ...*entity.Field) Option {
+	return func(s *Store) {
+		s.scalarFields = append(s.scalarFields, fields...)
+	}
+}
+
+// WithSparseEmbedder sets a sparse embedder, e.g. embeddings.NewBM25(),
+// enabling hybrid search. Its vectors are stored in the sparse vector field
+// named sparseField of the collection, and searches fuse the dense and
+// sparse results with Reciprocal Rank Fusion, ignoring the score threshold
+// unless vectorstores.WithHybridSearch is given to configure the fusion.
+// Requires Milvus 2.4 or later.
+func WithSparseEmbedder(embedder embeddings.SparseEmbedder, sparseField string) Option {
+	return func(s *Store) {
+		s.sparseEmbedder = embedder
+		s.sparseField = sparseField
+	}
+}
+
+// WithSparseIndex sets the index of the sparse vector field, an inverted
+// index with the IP metric by default, the only metric of sparse vectors.
+func WithSparseIndex(idx entity.Index) Option {
+	return func(s *Store) {
+		s.sparseIndex = idx
+	}
+}
+
+// WithPartitionNum sets the number of partitions of a collection with a
+// partition key.
+func WithPartitionNum(num int64) Option {
+	return func(s *Store) {
+		s.partitionNum = num
+	}
+}
+
 func applyClientOptions(opts ...Option) (Store, error) {
 	s := Store{
 		metricType:       entity.L2,
@@ -155,6 +195,23 @@ func applyClientOptions(opts ...Option) (Store, error) {
 	if s.index == nil {
 		return s, fmt.Errorf("%w: missing index function", ErrInvalidOptions)
 	}
+
+	if (s.sparseEmbedder == nil) != (s.sparseField == "") {
+		return s, fmt.Errorf("%w: sparse embedder and sparse field must be given together", ErrInvalidOptions)
+	}
+	if s.sparseEmbedder != nil && s.sparseIndex == nil {
+		idx, err := entity.NewIndexSparseInverted(entity.IP, 0)
+		if err != nil {
+			return s, err
+		}
+		s.sparseIndex = idx
+	}
+
+	for _, field := range s.scalarFields {
+		if _, ok := _scalarFieldTypes[field.DataType]; !ok {
+			return s, fmt.Errorf("%w: unsupported type of scalar field %q", ErrInvalidOptions, field.Name)
+		}
+	}
 	if s.searchParameters == nil {
 		idx, err := entity.NewIndexHNSWSearchParam(s.ef)
 		if err != nil {
@@ -0,0 +1,4 @@
+// Package mongovector contains an implementation of the VectorStore
+// interface using MongoDB Atlas Vector Search, whose searches are pre-filtered
+// on the indexed metadata fields of the documents.
+package mongovector
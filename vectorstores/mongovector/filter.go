@@ -0,0 +1,54 @@
+package mongovector
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _mqlOperators are the MQL operators of comparison filters.
+var _mqlOperators = map[vectorstores.FilterOp]string{ //nolint:gochecknoglobals
+	vectorstores.FilterEq:  "$eq",
+	vectorstores.FilterNe:  "$ne",
+	vectorstores.FilterGt:  "$gt",
+	vectorstores.FilterGte: "$gte",
+	vectorstores.FilterLt:  "$lt",
+	vectorstores.FilterLte: "$lte",
+}
+
+// filterMQL translates a filter to the MQL pre-filter of $vectorSearch, see
+// https://www.mongodb.com/docs/atlas/atlas-vector-search/vector-search-stage/#atlas-vector-search-pre-filter.
+// Filter keys are metadata fields, which must be indexed as filter fields.
+// Exists filters aren't supported by $vectorSearch.
+func filterMQL(f vectorstores.Filter) (map[string]any, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		if len(f.Filters) == 0 {
+			if f.Op == vectorstores.FilterAnd {
+				return map[string]any{}, nil
+			}
+			return nil, fmt.Errorf("%w: or filter without operands", vectorstores.ErrInvalidFilter)
+		}
+		operands := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			mql, err := filterMQL(operand)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, mql)
+		}
+		return map[string]any{"$" + string(f.Op): operands}, nil
+	case vectorstores.FilterIn:
+		return map[string]any{f.Key: map[string]any{"$in": f.Values}}, nil
+	}
+
+	op, ok := _mqlOperators[f.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	return map[string]any{f.Key: map[string]any{op: f.Value}}, nil
+}
@@ -0,0 +1,34 @@
+package mongovector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestFilterMQL(t *testing.T) {
+	t.Parallel()
+	mql, err := filterMQL(vectorstores.And(
+		vectorstores.Eq("lang", "en"),
+		vectorstores.Or(vectorstores.Gte("year", 2020), vectorstores.In("tag", "a", "b")),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$and": []any{
+		map[string]any{"lang": map[string]any{"$eq": "en"}},
+		map[string]any{"$or": []any{
+			map[string]any{"year": map[string]any{"$gte": 2020}},
+			map[string]any{"tag": map[string]any{"$in": []any{"a", "b"}}},
+		}},
+	}}, mql)
+
+	mql, err = filterMQL(vectorstores.And())
+	require.NoError(t, err)
+	assert.Empty(t, mql)
+
+	_, err = filterMQL(vectorstores.Exists("author"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+	_, err = filterMQL(vectorstores.Or())
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
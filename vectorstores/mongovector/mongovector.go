@@ -0,0 +1,371 @@
+package mongovector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	// ErrMissingTextKey is returned when a document found has no page
+	// content.
+	ErrMissingTextKey = errors.New("missing text key in document")
+)
+
+// _similarities are the vector similarities of the search index for the
+// distance metrics.
+var _similarities = map[vectorstores.DistanceMetric]string{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     "cosine",
+	vectorstores.DistanceEuclidean:  "euclidean",
+	vectorstores.DistanceDotProduct: "dotProduct",
+}
+
+const (
+	// _defaultBatchSize is the number of documents embedded and written at a
+	// time.
+	_defaultBatchSize = 100
+	// _defaultNumCandidatesMultiplier is the number of candidates of searches
+	// per document searched, see
+	// https://www.mongodb.com/docs/atlas/atlas-vector-search/vector-search-stage/#fields.
+	_defaultNumCandidatesMultiplier = 10
+	// _maxNumCandidates is the maximum number of candidates of searches.
+	_maxNumCandidates = 10000
+	// _scoreKey is the field the scores of the documents found are set to.
+	_scoreKey = "_vectorSearchScore"
+)
+
+// Store is a wrapper around a MongoDB Atlas collection and its vector search
+// index.
+type Store struct {
+	coll          *mongo.Collection
+	embedder      embeddings.Embedder
+	index         string
+	path          string
+	textKey       string
+	numCandidates int
+	metric        vectorstores.DistanceMetric
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
+
+// New creates a new Store of the documents of the collection with options.
+// Documents are stored with their metadata at the root, so that metadata
+// fields can be indexed as filter fields with CreateSearchIndex.
+func New(coll *mongo.Collection, embedder embeddings.Embedder, opts ...Option) (Store, error) {
+	s := Store{
+		coll:     coll,
+		embedder: embedder,
+	}
+	if err := applyClientOptions(&s, opts...); err != nil {
+		return Store{}, err
+	}
+	return s, nil
+}
+
+// AddDocuments adds the text and metadata from the documents to the
+// collection and returns the ids of the added documents.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.UpsertDocuments(ctx, ids, docs, options...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds the text and metadata from the documents with the ids
+// to the collection, replacing the documents with the same ids. Documents
+// are embedded and written in batches of 100 by default.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.upsert(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) upsert(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+	dimensions, err := s.searchIndexDimensions(ctx)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+		return err
+	}
+
+	models := make([]mongo.WriteModel, len(docs))
+	for i, doc := range docs {
+		fields := make(bson.M, len(doc.Metadata)+3) //nolint:mnd
+		for key, value := range doc.Metadata {
+			fields[key] = value
+		}
+		fields["_id"] = ids[i]
+		fields[s.textKey] = doc.PageContent
+		fields[s.path] = vectors[i]
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: ids[i]}}).
+			SetReplacement(fields).
+			SetUpsert(true)
+	}
+	_, err = s.coll.BulkWrite(ctx, models)
+	return err
+}
+
+// DeleteByIDs deletes the documents with the ids from the collection.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.coll.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	return err
+}
+
+// DeleteByFilter deletes the documents of the collection matched by the
+// filter, a vectorstores.Filter or an MQL query.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	query, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if query == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	_, err = s.coll.DeleteMany(ctx, query)
+	return err
+}
+
+// SimilaritySearch searches the documents most similar to the query with
+// $vectorSearch. The filters of the options, a vectorstores.Filter or an
+// MQL query, pre-filter the documents on metadata fields indexed as filter
+// fields of the search index.
+func (s Store) SimilaritySearch(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by mongovector", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filter, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.coll.Aggregate(ctx, s.searchPipeline(vector, numDocuments, filter, opts.ScoreThreshold))
+	if err != nil {
+		return nil, err
+	}
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	for _, result := range results {
+		pageContent, ok := result[s.textKey].(string)
+		if !ok {
+			return nil, ErrMissingTextKey
+		}
+		score, _ := result[_scoreKey].(float64)
+		delete(result, s.textKey)
+		delete(result, _scoreKey)
+		delete(result, "_id")
+		docs = append(docs, schema.Document{
+			PageContent: pageContent,
+			Metadata:    result,
+			Score:       s.score(score),
+		})
+	}
+	return docs, nil
+}
+
+// searchPipeline returns the aggregation pipeline searching the documents
+// nearest to the vector.
+func (s Store) searchPipeline(vector []float32, numDocuments int, filter any, scoreThreshold float32) mongo.Pipeline {
+	numCandidates := s.numCandidates
+	if numCandidates == 0 {
+		numCandidates = min(numDocuments*_defaultNumCandidatesMultiplier, _maxNumCandidates)
+	}
+	vectorSearch := bson.D{
+		{Key: "index", Value: s.index},
+		{Key: "path", Value: s.path},
+		{Key: "queryVector", Value: vector},
+		{Key: "numCandidates", Value: max(numCandidates, numDocuments)},
+		{Key: "limit", Value: numDocuments},
+	}
+	if filter != nil {
+		vectorSearch = append(vectorSearch, bson.E{Key: "filter", Value: filter})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$vectorSearch", Value: vectorSearch}},
+		{{Key: "$addFields", Value: bson.D{{Key: _scoreKey, Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}}}}},
+		{{Key: "$project", Value: bson.D{{Key: s.path, Value: 0}}}},
+	}
+	if scoreThreshold > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{
+			{Key: _scoreKey, Value: bson.D{{Key: "$gte", Value: s.vectorSearchScore(scoreThreshold)}}},
+		}}})
+	}
+	return pipeline
+}
+
+// score converts a vector search score, (1 + similarity) / 2 for the cosine
+// and dotProduct similarities and 1 / (1 + distance) for euclidean, to a
+// relevance score.
+func (s Store) score(score float64) float32 {
+	if s.metric == vectorstores.DistanceEuclidean {
+		if score <= 0 {
+			return 0
+		}
+		return vectorstores.EuclideanDistanceScore(1/score - 1)
+	}
+	return vectorstores.CosineSimilarityScore(2*score - 1) //nolint:mnd
+}
+
+// vectorSearchScore converts a score threshold to a vector search score.
+func (s Store) vectorSearchScore(scoreThreshold float32) float64 {
+	if s.metric == vectorstores.DistanceEuclidean {
+		return 1 / (1 + vectorstores.EuclideanDistanceThreshold(scoreThreshold))
+	}
+	return (1 + float64(scoreThreshold)) / 2 //nolint:mnd
+}
+
+// CreateSearchIndex creates the vector search index of the store, with the
+// vector dimensions and metric of the options and the metadata fields
+// documents can be pre-filtered on. The index is built asynchronously:
+// searches find no documents until it is ready.
+func (s Store) CreateSearchIndex(ctx context.Context, filterFields []string, options ...vectorstores.CollectionOption) error { //nolint:lll
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	similarity, ok := _similarities[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+
+	fields := bson.A{bson.D{
+		{Key: "type", Value: "vector"},
+		{Key: "path", Value: s.path},
+		{Key: "numDimensions", Value: opts.Dimensions},
+		{Key: "similarity", Value: similarity},
+	}}
+	for _, field := range filterFields {
+		fields = append(fields, bson.D{
+			{Key: "type", Value: "filter"},
+			{Key: "path", Value: field},
+		})
+	}
+	// the driver can't create indexes of type vectorSearch, so the command
+	// is run directly.
+	return s.coll.Database().RunCommand(ctx, bson.D{
+		{Key: "createSearchIndexes", Value: s.coll.Name()},
+		{Key: "indexes", Value: bson.A{bson.D{
+			{Key: "name", Value: s.index},
+			{Key: "type", Value: "vectorSearch"},
+			{Key: "definition", Value: bson.D{{Key: "fields", Value: fields}}},
+		}}},
+	}).Err()
+}
+
+// searchIndexDimensions returns the vector dimensions of the search index of
+// the store, or 0 if the index doesn't exist or the deployment doesn't
+// support search indexes.
+func (s Store) searchIndexDimensions(ctx context.Context) (int, error) {
+	cursor, err := s.coll.SearchIndexes().List(ctx, options.SearchIndexes().SetName(s.index))
+	if err != nil {
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var indexes []struct {
+		LatestDefinition struct {
+			Fields []struct {
+				Type          string `bson:"type"`
+				Path          string `bson:"path"`
+				NumDimensions int    `bson:"numDimensions"`
+			} `bson:"fields"`
+		} `bson:"latestDefinition"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return 0, err
+	}
+	for _, index := range indexes {
+		for _, field := range index.LatestDefinition.Fields {
+			if field.Type == "vector" && field.Path == s.path {
+				return field.NumDimensions, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// DropSearchIndex drops the vector search index of the store, keeping the
+// documents.
+func (s Store) DropSearchIndex(ctx context.Context) error {
+	return s.coll.SearchIndexes().DropOne(ctx, s.index)
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getFilters returns the MQL filter of the options.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if filter, ok := opts.Filters.(vectorstores.Filter); ok {
+		return filterMQL(filter)
+	}
+	return opts.Filters, nil
+}
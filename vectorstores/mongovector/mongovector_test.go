@@ -0,0 +1,75 @@
+package mongovector
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/internal/mongodb"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// TestMongoVectorStore needs an Atlas cluster, whose search indexes aren't
+// available in local MongoDB deployments.
+func TestMongoVectorStore(t *testing.T) {
+	t.Parallel()
+	uri := os.Getenv("MONGODB_ATLAS_URI")
+	if uri == "" {
+		t.Skip("Must set MONGODB_ATLAS_URI to run test")
+	}
+
+	ctx := context.Background()
+	client, err := mongodb.NewClient(ctx, uri)
+	require.NoError(t, err)
+	coll := client.Database("langchaingo_test").Collection(uuid.NewString())
+	t.Cleanup(func() {
+		require.NoError(t, coll.Drop(context.Background()))
+	})
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i, text := range texts {
+				vectors[i] = []float32{float32(len(text)), 1}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	store, err := New(coll, e, WithNumCandidates(50))
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan", "year": 2024}},
+		{PageContent: "osaka", Metadata: map[string]any{"country": "japan", "year": 2020}},
+		{PageContent: "paris", Metadata: map[string]any{"country": "france", "year": 2024}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateSearchIndex(ctx, []string{"country", "year"},
+		vectorstores.WithDimensions(2), vectorstores.WithDistanceMetric(vectorstores.DistanceEuclidean)))
+
+	// the search index is built asynchronously.
+	var docs []schema.Document
+	require.Eventually(t, func() bool {
+		docs, err = store.SimilaritySearch(ctx, "kyoto", 3, vectorstores.WithFilter(vectorstores.And(
+			vectorstores.Eq("country", "japan"),
+			vectorstores.Gte("year", 2022),
+		)))
+		return err == nil && len(docs) > 0
+	}, 2*time.Minute, 5*time.Second)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+
+	wide, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, _ []string) ([][]float32, error) {
+			return [][]float32{{1, 2, 3}}, nil
+		}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "kyoto"}}, vectorstores.WithEmbedder(wide))
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+}
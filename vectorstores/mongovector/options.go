@@ -0,0 +1,90 @@
+package mongovector
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	_defaultIndex   = "vector_index"
+	_defaultPath    = "embedding"
+	_defaultTextKey = "text"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithIndex is an option for specifying the name of the vector search index.
+// Defaults to "vector_index".
+func WithIndex(index string) Option {
+	return func(s *Store) {
+		s.index = index
+	}
+}
+
+// WithPath is an option for specifying the field storing the vectors of the
+// documents. Defaults to "embedding".
+func WithPath(path string) Option {
+	return func(s *Store) {
+		s.path = path
+	}
+}
+
+// WithTextKey is an option for specifying the field storing the page
+// content of the documents. Defaults to "text".
+func WithTextKey(textKey string) Option {
+	return func(s *Store) {
+		s.textKey = textKey
+	}
+}
+
+// WithNumCandidates is an option for specifying the number of nearest
+// neighbors considered by searches, at most 10000. More candidates improve
+// the recall of searches at the cost of their latency. Defaults to 10 times
+// the number of documents searched.
+func WithNumCandidates(numCandidates int) Option {
+	return func(s *Store) {
+		s.numCandidates = numCandidates
+	}
+}
+
+// WithDistanceMetric is an option for specifying the similarity of the
+// vector search index, converting the scores of the documents found to
+// relevance scores. Defaults to vectorstores.DistanceCosine.
+func WithDistanceMetric(metric vectorstores.DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+func applyClientOptions(s *Store, opts ...Option) error {
+	s.index = _defaultIndex
+	s.path = _defaultPath
+	s.textKey = _defaultTextKey
+	s.metric = vectorstores.DistanceCosine
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.coll == nil {
+		return fmt.Errorf("%w: missing collection", ErrInvalidOptions)
+	}
+	if s.embedder == nil {
+		return fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.index == "" || s.path == "" || s.textKey == "" {
+		return fmt.Errorf("%w: empty index, path or text key", ErrInvalidOptions)
+	}
+	if _, ok := _similarities[s.metric]; !ok {
+		return fmt.Errorf("%w: %w: %s", ErrInvalidOptions, vectorstores.ErrUnsupportedDistanceMetric, s.metric)
+	}
+	if s.numCandidates < 0 || s.numCandidates > _maxNumCandidates {
+		return fmt.Errorf("%w: number of candidates must be between 1 and %d", ErrInvalidOptions, _maxNumCandidates)
+	}
+	return nil
+}
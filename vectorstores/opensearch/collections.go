@@ -0,0 +1,135 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrIndexRequest is returned when Opensearch fails to create, delete or
+// list indexes.
+var ErrIndexRequest = errors.New("error requesting indexes")
+
+var _ vectorstores.CollectionManager = Store{}
+
+// _spaceTypes maps distance metrics to the space types of the knn_vector field.
+var _spaceTypes = map[vectorstores.DistanceMetric]string{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     "cosinesimil",
+	vectorstores.DistanceEuclidean:  "l2",
+	vectorstores.DistanceDotProduct: "innerproduct",
+}
+
+// CreateCollection creates an index like CreateIndex with the vector
+// dimensions and metric of the options. The index params override the HNSW
+// parameters of the index, e.g. {"m": 8}.
+func (s Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	spaceType, ok := _spaceTypes[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	res, err := s.CreateIndex(ctx, name, func(indexMap *map[string]interface{}) {
+		mappings, _ := (*indexMap)["mappings"].(map[string]interface{})
+		properties, _ := mappings["properties"].(map[string]interface{})
+		field, _ := properties[vectorField].(map[string]interface{})
+		if field == nil {
+			return
+		}
+		field["dimension"] = opts.Dimensions
+		method, _ := field["method"].(map[string]interface{})
+		if method == nil {
+			return
+		}
+		method["space_type"] = spaceType
+		params, _ := method["parameters"].(map[string]interface{})
+		for key, value := range opts.IndexParams {
+			params[key] = value
+		}
+	})
+	return checkIndexResponse(res, err)
+}
+
+// DropCollection deletes an index and its documents.
+func (s Store) DropCollection(ctx context.Context, name string) error {
+	return checkIndexResponse(s.DeleteIndex(ctx, name))
+}
+
+// ListCollections returns the names of the indexes.
+func (s Store) ListCollections(ctx context.Context) ([]string, error) {
+	catIndices := opensearchapi.CatIndicesRequest{
+		Format: "json",
+		H:      []string{"index"},
+	}
+	res, err := catIndices.Do(ctx, s.client)
+	if err != nil {
+		return nil, fmt.Errorf("catIndices.Do err: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("%w: %s", ErrIndexRequest, res.String())
+	}
+
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("error decoding cat indices response: %w", err)
+	}
+	names := make([]string, len(indices))
+	for i, index := range indices {
+		names[i] = index.Index
+	}
+	return names, nil
+}
+
+// indexDimensions returns the dimension of the vector field of the index, or
+// 0 if the index doesn't exist or has no vector field yet.
+func (s Store) indexDimensions(ctx context.Context, index string) (int, error) {
+	getMapping := opensearchapi.IndicesGetMappingRequest{Index: []string{index}}
+	res, err := getMapping.Do(ctx, s.client)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if res.IsError() {
+		return 0, fmt.Errorf("%w: %s", ErrIndexRequest, res.String())
+	}
+
+	var mappings map[string]struct {
+		Mappings struct {
+			Properties map[string]struct {
+				Dimension int `json:"dimension"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&mappings); err != nil {
+		return 0, fmt.Errorf("error decoding index mapping response: %w", err)
+	}
+	for _, mapping := range mappings {
+		return mapping.Mappings.Properties[vectorField].Dimension, nil
+	}
+	return 0, nil
+}
+
+// checkIndexResponse returns an error if the index request failed.
+func checkIndexResponse(res *opensearchapi.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("%w: %s", ErrIndexRequest, res.String())
+	}
+	return nil
+}
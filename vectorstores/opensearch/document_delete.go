@@ -0,0 +1,62 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrDeleteDocuments is returned when Opensearch fails to delete documents.
+var ErrDeleteDocuments = errors.New("error deleting documents")
+
+// DeleteByIDs deletes the documents with the ids from the index named by the
+// name space of the options.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	return s.deleteByQuery(ctx, opts.NameSpace, map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": ids,
+		},
+	})
+}
+
+// DeleteByFilter deletes the documents matched by the filter, an Opensearch
+// query, e.g. a term query on a "metadata." field, from the index named by
+// the name space of the options.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	return s.deleteByQuery(ctx, opts.NameSpace, filter)
+}
+
+// deleteByQuery deletes the documents matched by the query from the index.
+func (s Store) deleteByQuery(ctx context.Context, indexName string, query any) error {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{"query": query}); err != nil {
+		return fmt.Errorf("error encoding query to json buffer %w", err)
+	}
+
+	deleteByQuery := opensearchapi.DeleteByQueryRequest{
+		Index: []string{indexName},
+		Body:  buf,
+	}
+	res, err := deleteByQuery.Do(ctx, s.client)
+	if err != nil {
+		return fmt.Errorf("deleteByQuery.Do err: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("%w: %s", ErrDeleteDocuments, res.String())
+	}
+	return nil
+}
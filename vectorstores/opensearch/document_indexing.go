@@ -11,7 +11,7 @@ import (
 
 type document struct {
 	FieldsContent       string                 `json:"content"`
-	FieldsContentVector []float32              `json:"contentVector"`
+	FieldsContentVector []float32              `json:"contentVector,omitempty"`
 	FieldsMetadata      map[string]interface{} `json:"metadata"`
 }
 
@@ -39,6 +39,7 @@ func (s *Store) documentIndexing(
 		Index:      indexName,
 		DocumentID: id,
 		Body:       buf,
+		Pipeline:   s.ingestPipeline,
 	}
 
 	return indice.Do(ctx, s.client)
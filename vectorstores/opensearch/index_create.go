@@ -28,6 +28,23 @@ func (s *Store) CreateIndex(
 	indexName string,
 	opts ...IndexOption,
 ) (*opensearchapi.Response, error) {
+	buf := new(bytes.Buffer)
+
+	if err := json.NewEncoder(buf).Encode(newIndexSchema(opts...)); err != nil {
+		return nil, fmt.Errorf("error encoding index schema to json buffer %w", err)
+	}
+
+	indice := opensearchapi.IndicesCreateRequest{
+		Index: indexName,
+		Body:  buf,
+	}
+
+	return indice.Do(ctx, s.client)
+}
+
+// newIndexSchema returns the settings and mappings of indexes, modified by
+// the options.
+func newIndexSchema(opts ...IndexOption) map[string]interface{} {
 	indexSchema := map[string]interface{}{
 		"settings": map[string]interface{}{
 			"index": map[string]interface{}{
@@ -58,16 +75,5 @@ func (s *Store) CreateIndex(
 		indexOption(&indexSchema)
 	}
 
-	buf := new(bytes.Buffer)
-
-	if err := json.NewEncoder(buf).Encode(indexSchema); err != nil {
-		return nil, fmt.Errorf("error encoding index schema to json buffer %w", err)
-	}
-
-	indice := opensearchapi.IndicesCreateRequest{
-		Index: indexName,
-		Body:  buf,
-	}
-
-	return indice.Do(ctx, s.client)
+	return indexSchema
 }
@@ -0,0 +1,52 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+)
+
+// PutIndexTemplate creates or updates the index template with the name,
+// whose settings and mappings, those of CreateIndex modified by the options,
+// apply to the indexes created with a name matching the patterns, e.g.
+// "docs-*". Indexes are then created when documents are first added to them.
+func (s *Store) PutIndexTemplate(
+	ctx context.Context,
+	name string,
+	indexPatterns []string,
+	opts ...IndexOption,
+) (*opensearchapi.Response, error) {
+	template := map[string]interface{}{
+		"index_patterns": indexPatterns,
+		"template":       newIndexSchema(opts...),
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err := json.NewEncoder(buf).Encode(template); err != nil {
+		return nil, fmt.Errorf("error encoding index template to json buffer %w", err)
+	}
+
+	putTemplate := opensearchapi.IndicesPutIndexTemplateRequest{
+		Name: name,
+		Body: buf,
+	}
+
+	return putTemplate.Do(ctx, s.client)
+}
+
+// DeleteIndexTemplate deletes the index template with the name, keeping
+// the indexes created with it.
+func (s *Store) DeleteIndexTemplate(
+	ctx context.Context,
+	name string,
+) (*opensearchapi.Response, error) {
+	deleteTemplate := opensearchapi.IndicesDeleteIndexTemplateRequest{
+		Name: name,
+	}
+
+	return deleteTemplate.Do(ctx, s.client)
+}
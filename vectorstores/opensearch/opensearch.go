@@ -18,8 +18,12 @@ import (
 
 // Store is a wrapper around the chromaGo API and client.
 type Store struct {
-	embedder embeddings.Embedder
-	client   *opensearchgo.Client
+	embedder       embeddings.Embedder
+	client         *opensearchgo.Client
+	neuralModelID  string
+	ingestPipeline string
+	filterMode     FilterMode
+	metric         vectorstores.DistanceMetric
 }
 
 var (
@@ -48,7 +52,14 @@ func New(client *opensearchgo.Client, opts ...Option) (Store, error) {
 	return s, nil
 }
 
-var _ vectorstores.VectorStore = Store{}
+// _defaultBatchSize is the number of documents embedded and indexed at a time.
+const _defaultBatchSize = 100
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
 // and returns the ids of the added documents.
@@ -57,37 +68,75 @@ func (s Store) AddDocuments(
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
-	opts := s.getOptions(options...)
-	ids := []string{}
-	texts := []string{}
-
-	for _, doc := range docs {
-		texts = append(texts, doc.PageContent)
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
 	}
-
-	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
-	if err != nil {
-		return ids, err
+	if err := s.UpsertDocuments(ctx, ids, docs, options...); err != nil {
+		return nil, err
 	}
+	return ids, nil
+}
 
-	if len(vectors) != len(docs) {
-		return ids, ErrNumberOfVectorDoesNotMatch
+// UpsertDocuments adds the text and metadata from the documents with the ids
+// to the index named by the name space of the options, replacing the
+// documents with the same ids. Documents are embedded and indexed in batches
+// of 100 by default.
+func (s Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
 	}
+	opts := s.getOptions(options...)
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.indexDocuments(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
 
-	for i, doc := range docs {
-		id := uuid.NewString()
-		_, err := s.documentIndexing(ctx, id, opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata)
+func (s Store) indexDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	// without embedder, documents are embedded by the ingest pipeline.
+	vectors := make([][]float32, len(docs))
+	if s.embedder != nil {
+		texts := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			texts = append(texts, doc.PageContent)
+		}
+
+		var err error
+		vectors, err = s.embedder.EmbedDocuments(ctx, texts)
 		if err != nil {
-			return ids, err
+			return err
+		}
+
+		if len(vectors) != len(docs) {
+			return ErrNumberOfVectorDoesNotMatch
+		}
+		dimensions, err := s.indexDimensions(ctx, opts.NameSpace)
+		if err != nil {
+			return err
+		}
+		if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+			return err
 		}
-		ids = append(ids, id)
 	}
 
-	return ids, nil
+	for i, doc := range docs {
+		if _, err := s.documentIndexing(ctx, ids[i], opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents. With a neural model, the
+// query is embedded by Opensearch instead.
+// The filters of the options are Opensearch queries, applied as set by
+// WithFilterMode.
 func (s Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -96,58 +145,196 @@ func (s Store) SimilaritySearch(
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
 
-	queryVector, err := s.embedder.EmbedQuery(ctx, query)
+	if opts.HybridSearch != nil {
+		return s.hybridSearch(ctx, query, *opts.HybridSearch, numDocuments, opts)
+	}
+
+	searchPayload, err := s.vectorQuery(ctx, query, nil, numDocuments, opts.Filters)
 	if err != nil {
 		return nil, err
 	}
 
+	hits, err := s.search(ctx, opts.NameSpace, searchPayload)
+	if err != nil {
+		return []schema.Document{}, err
+	}
+
+	output := []schema.Document{}
+	for _, hit := range hits {
+		hit.Score = s.score(hit.Score)
+		if opts.ScoreThreshold > 0 && opts.ScoreThreshold > hit.Score {
+			continue
+		}
+		output = append(output, hit)
+	}
+
+	return output, nil
+}
+
+// score converts the score of a k-NN search, computed by the nmslib and faiss
+// engines from the distance of the space type of the metric, to a relevance
+// score.
+func (s Store) score(score float32) float32 {
+	if score <= 0 {
+		return 0
+	}
+	d := 1/float64(score) - 1
+	switch s.metric { //nolint:exhaustive
+	case vectorstores.DistanceCosine:
+		return vectorstores.CosineDistanceScore(d)
+	case vectorstores.DistanceDotProduct:
+		// scores of positive inner products are the products plus 1.
+		if score >= 1 {
+			return vectorstores.DotProductScore(float64(score) - 1)
+		}
+		return vectorstores.DotProductScore(-d)
+	}
+	return vectorstores.SquaredEuclideanDistanceScore(d)
+}
+
+// hybridSearch searches both the content vectors and, with BM25, the content
+// matching the text, which defaults to the query, fusing the results. Sparse
+// vectors are ignored.
+func (s Store) hybridSearch(
+	ctx context.Context,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	opts vectorstores.Options,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+
+	text := hybrid.Text
+	if text == "" {
+		text = query
+	}
+
+	denseQuery, err := s.vectorQuery(ctx, query, hybrid.Vector, numDocuments, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	dense, err := s.search(ctx, opts.NameSpace, denseQuery)
+	if err != nil {
+		return nil, err
+	}
+	var matchQuery interface{} = map[string]interface{}{
+		"match": map[string]interface{}{
+			"content": text,
+		},
+	}
+	if opts.Filters != nil {
+		matchQuery = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   matchQuery,
+				"filter": opts.Filters,
+			},
+		}
+	}
+	matches, err := s.search(ctx, opts.NameSpace, map[string]interface{}{
+		"size":  numDocuments,
+		"query": matchQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hybrid.Fuse(dense, matches, numDocuments, opts.ScoreThreshold, nil), nil
+}
+
+// vectorQuery returns the search of the documents nearest to the vector,
+// or to the query embedded by the neural model or the embedder if the
+// vector is nil, matching the filter.
+func (s Store) vectorQuery(
+	ctx context.Context,
+	query string,
+	vector []float32,
+	numDocuments int,
+	filter any,
+) (map[string]interface{}, error) {
+	if vector == nil && s.neuralModelID != "" {
+		return s.knnQuery("neural", map[string]interface{}{
+			"query_text": query,
+			"model_id":   s.neuralModelID,
+			"k":          numDocuments,
+		}, numDocuments, filter), nil
+	}
+	if vector == nil {
+		if s.embedder == nil {
+			return nil, ErrMissingEmbedded
+		}
+		var err error
+		vector, err = s.embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.knnQuery("knn", map[string]interface{}{
+		"vector": vector,
+		"k":      numDocuments,
+	}, numDocuments, filter), nil
+}
+
+// knnQuery returns the search with the k-NN query of the content vectors,
+// a knn or neural query with the params, filtered as set by the filter mode.
+func (s Store) knnQuery(
+	queryType string,
+	params map[string]interface{},
+	numDocuments int,
+	filter any,
+) map[string]interface{} {
 	searchPayload := map[string]interface{}{
 		"size": numDocuments,
-		"query": map[string]interface{}{
-			"knn": map[string]interface{}{
-				"contentVector": map[string]interface{}{
-					"vector": queryVector,
-					"k":      numDocuments,
-				},
-			},
+	}
+	if filter != nil {
+		if s.filterMode == EfficientFilter {
+			params["filter"] = filter
+		} else {
+			searchPayload["post_filter"] = filter
+		}
+	}
+	searchPayload["query"] = map[string]interface{}{
+		queryType: map[string]interface{}{
+			vectorField: params,
 		},
 	}
+	return searchPayload
+}
 
+// search runs the search in the index, returning the hits as documents.
+func (s Store) search(ctx context.Context, index string, searchPayload map[string]interface{}) ([]schema.Document, error) {
 	buf := new(bytes.Buffer)
 	if err := json.NewEncoder(buf).Encode(searchPayload); err != nil {
 		return nil, fmt.Errorf("error encoding index schema to json buffer %w", err)
 	}
 
 	search := opensearchapi.SearchRequest{
-		Index: []string{opts.NameSpace},
+		Index: []string{index},
 		Body:  buf,
 	}
-	output := []schema.Document{}
 	searchResponse, err := search.Do(ctx, s.client)
 	if err != nil {
-		return output, fmt.Errorf("search.Do err: %w", err)
+		return nil, fmt.Errorf("search.Do err: %w", err)
 	}
+	defer searchResponse.Body.Close()
 
 	body, err := io.ReadAll(searchResponse.Body)
 	if err != nil {
-		return output, fmt.Errorf("error reading search response body: %w", err)
+		return nil, fmt.Errorf("error reading search response body: %w", err)
 	}
 	searchResults := searchResults{}
 	if err := json.Unmarshal(body, &searchResults); err != nil {
-		return output, fmt.Errorf("error unmarshalling search response body: %w %s", err, body)
+		return nil, fmt.Errorf("error unmarshalling search response body: %w %s", err, body)
 	}
 
+	docs := make([]schema.Document, 0, len(searchResults.Hits.Hits))
 	for _, hit := range searchResults.Hits.Hits {
-		if opts.ScoreThreshold > 0 && opts.ScoreThreshold > hit.Score {
-			continue
-		}
-
-		output = append(output, schema.Document{
+		docs = append(docs, schema.Document{
 			PageContent: hit.Source.FieldsContent,
 			Metadata:    hit.Source.FieldsMetadata,
 			Score:       hit.Score,
 		})
 	}
-
-	return output, nil
+	return docs, nil
 }
@@ -2,6 +2,8 @@ package opensearch_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -187,15 +189,67 @@ func TestOpensearchStoreRestWithScoreThreshold(t *testing.T) {
 	}, vectorstores.WithNameSpace(indexName))
 	require.NoError(t, err)
 	time.Sleep(time.Second)
-	// test with a score threshold of 0.72, expected 6 documents
+	// test with a score threshold of 0.8, expected 6 documents
 	docs, err := storer.SimilaritySearch(context.Background(),
 		"Which of these are cities in Japan", 10,
-		vectorstores.WithScoreThreshold(0.72),
+		vectorstores.WithScoreThreshold(0.8),
 		vectorstores.WithNameSpace(indexName))
 	require.NoError(t, err)
 	require.Len(t, docs, 6)
 }
 
+func TestOpensearchStoreRestWithIndexTemplateAndFilter(t *testing.T) {
+	t.Parallel()
+	opensearchEndpoint, opensearchUser, opensearchPassword := getEnvVariables(t)
+	templateName := uuid.New().String()
+	indexName := templateName + "-docs"
+
+	llm := setLLM(t)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	storer, err := opensearch.New(
+		setOpensearchClient(t, opensearchEndpoint, opensearchUser, opensearchPassword),
+		opensearch.WithEmbedder(e),
+		opensearch.WithFilterMode(opensearch.EfficientFilter),
+	)
+	require.NoError(t, err)
+
+	// efficient filtering requires the lucene engine.
+	res, err := storer.PutIndexTemplate(context.Background(), templateName, []string{templateName + "-*"},
+		func(indexMap *map[string]interface{}) {
+			mappings, _ := (*indexMap)["mappings"].(map[string]interface{})
+			properties, _ := mappings["properties"].(map[string]interface{})
+			field, _ := properties["contentVector"].(map[string]interface{})
+			method, _ := field["method"].(map[string]interface{})
+			method["engine"] = "lucene"
+		})
+	require.NoError(t, err)
+	require.False(t, res.IsError(), res.String())
+	defer func() {
+		_, err := storer.DeleteIndexTemplate(context.Background(), templateName)
+		require.NoError(t, err)
+	}()
+	defer removeIndex(t, storer, indexName)
+
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Paris", Metadata: map[string]any{"country": "france"}},
+	}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	time.Sleep(time.Second)
+
+	docs, err := storer.SimilaritySearch(context.Background(), "Which of these are cities in Japan", 2,
+		vectorstores.WithFilters(map[string]interface{}{
+			"term": map[string]interface{}{"metadata.country.keyword": "france"},
+		}),
+		vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "Paris", docs[0].PageContent)
+}
+
 func TestOpensearchAsRetriever(t *testing.T) {
 	t.Parallel()
 	opensearchEndpoint, opensearchUser, opensearchPassword := getEnvVariables(t)
@@ -276,7 +330,7 @@ func TestOpensearchAsRetrieverWithScoreThreshold(t *testing.T) {
 			llm,
 			vectorstores.ToRetriever(storer, 5,
 				vectorstores.WithNameSpace(indexName),
-				vectorstores.WithScoreThreshold(0.8)),
+				vectorstores.WithScoreThreshold(0.875)),
 		),
 		"What colors is each piece of furniture next to the desk?",
 	)
@@ -285,3 +339,43 @@ func TestOpensearchAsRetrieverWithScoreThreshold(t *testing.T) {
 	require.Contains(t, result, "black", "expected black in result")
 	require.Contains(t, result, "beige", "expected beige in result")
 }
+
+func TestOpensearchDimensionMismatch(t *testing.T) {
+	t.Parallel()
+	var indexed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`{"version":{"number":"2.11.0","distribution":"opensearch"}}`))
+			return
+		case r.Method == http.MethodGet:
+			require.Equal(t, "/docs/_mapping", r.URL.Path)
+			_, _ = w.Write([]byte(`{"docs":{"mappings":{"properties":{"contentVector":{"type":"knn_vector","dimension":3}}}}}`))
+			return
+		}
+		indexed++
+		_, _ = w.Write([]byte(`{"result":"created"}`))
+	}))
+	defer server.Close()
+
+	client, err := opensearchgo.NewClient(opensearchgo.Config{Addresses: []string{server.URL}})
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i, text := range texts {
+				vectors[i] = make([]float32, len(text))
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	store, err := opensearch.New(client, opensearch.WithEmbedder(e))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "abc"}}, vectorstores.WithNameSpace("docs"))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "ab"}}, vectorstores.WithNameSpace("docs"))
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+	require.Equal(t, 1, indexed)
+}
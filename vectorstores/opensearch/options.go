@@ -2,6 +2,7 @@ package opensearch
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/vectorstores"
@@ -16,6 +17,10 @@ var (
 	ErrMissingOpensearchClient = errors.New(
 		"missing opensearch client",
 	)
+	// ErrInvalidFilterMode the filter mode must be PostFilter or EfficientFilter.
+	ErrInvalidFilterMode = errors.New(
+		"invalid filter mode",
+	)
 )
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
@@ -38,15 +43,83 @@ func WithEmbedder(e embeddings.Embedder) Option {
 	}
 }
 
+// FilterMode is how the filters of the options are applied to k-NN
+// searches.
+type FilterMode string
+
+const (
+	// PostFilter filters the nearest documents after the search, which may
+	// return less documents than requested. It is the default.
+	PostFilter FilterMode = "post_filter"
+	// EfficientFilter filters the documents during the search, which returns
+	// the requested number of documents if enough match the filters. It
+	// requires the lucene or faiss engine.
+	EfficientFilter FilterMode = "efficient"
+)
+
+// WithNeuralModel returns an Option for searching with neural queries, whose
+// text is embedded by the model deployed in Opensearch with the id, instead
+// of the embedder. The documents must be embedded in the contentVector field
+// with the same model, e.g. by the ingest pipeline of WithIngestPipeline.
+// The embedder is optional with a neural model: documents are then indexed
+// without vectors.
+func WithNeuralModel(modelID string) Option {
+	return func(p *Store) {
+		p.neuralModelID = modelID
+	}
+}
+
+// WithIngestPipeline returns an Option for setting the ingest pipeline the
+// documents are indexed with, e.g. a text_embedding pipeline embedding their
+// content in the contentVector field.
+func WithIngestPipeline(pipeline string) Option {
+	return func(p *Store) {
+		p.ingestPipeline = pipeline
+	}
+}
+
+// WithFilterMode returns an Option for setting how the filters of searches,
+// Opensearch queries, are applied to k-NN searches. Defaults to PostFilter.
+func WithFilterMode(mode FilterMode) Option {
+	return func(p *Store) {
+		p.filterMode = mode
+	}
+}
+
+// WithDistanceMetric returns an Option for setting the distance metric of
+// the space type of the indexes, converting the scores of the documents found
+// to relevance scores. Defaults to vectorstores.DistanceEuclidean, the l2
+// space type of CreateIndex.
+func WithDistanceMetric(metric vectorstores.DistanceMetric) Option {
+	return func(p *Store) {
+		p.metric = metric
+	}
+}
+
 func applyClientOptions(s *Store, opts ...Option) error {
 	for _, opt := range opts {
 		opt(s)
 	}
 
-	if s.embedder == nil {
+	if s.embedder == nil && s.neuralModelID == "" {
 		return ErrMissingEmbedded
 	}
 
+	switch s.filterMode {
+	case "":
+		s.filterMode = PostFilter
+	case PostFilter, EfficientFilter:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidFilterMode, s.filterMode)
+	}
+
+	if s.metric == "" {
+		s.metric = vectorstores.DistanceEuclidean
+	}
+	if _, ok := _spaceTypes[s.metric]; !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, s.metric)
+	}
+
 	if s.client == nil {
 		return ErrMissingOpensearchClient
 	}
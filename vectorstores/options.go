@@ -2,6 +2,7 @@ package vectorstores
 
 import (
 	"context"
+	"time"
 
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
@@ -17,6 +18,25 @@ type Options struct {
 	Filters        any
 	Embedder       embeddings.Embedder
 	Deduplicater   func(context.Context, schema.Document) bool
+	HybridSearch   *HybridSearch
+	Tenant         string
+
+	// BatchSize, Concurrency, MaxRetries, RetryBackoff and Progress
+	// configure how documents are added in batches.
+	BatchSize    int
+	Concurrency  int
+	MaxRetries   int
+	RetryBackoff time.Duration
+	Progress     ProgressFunc
+}
+
+// WithTenant returns an Option for setting the tenant of the documents added,
+// searched or deleted, in stores with native multi-tenancy such as
+// Weaviate.
+func WithTenant(tenant string) Option {
+	return func(o *Options) {
+		o.Tenant = tenant
+	}
 }
 
 // WithNameSpace returns an Option for setting the name space.
@@ -26,6 +46,8 @@ func WithNameSpace(nameSpace string) Option {
 	}
 }
 
+// WithScoreThreshold returns an Option for setting the minimum relevance
+// score, between 0 and 1, of the documents found by similarity searches.
 func WithScoreThreshold(scoreThreshold float32) Option {
 	return func(o *Options) {
 		o.ScoreThreshold = scoreThreshold
@@ -36,12 +58,24 @@ func WithScoreThreshold(scoreThreshold float32) Option {
 // filters retrieve exactly the number of nearest-neighbors results that match the filters. In
 // most cases the search latency will be lower than unfiltered searches
 // See https://docs.pinecone.io/docs/metadata-filtering
+//
+// The filters are in the native syntax of the store; use WithFilter for
+// filters portable across stores.
 func WithFilters(filters any) Option {
 	return func(o *Options) {
 		o.Filters = filters
 	}
 }
 
+// WithFilter returns an Option for limiting searches to the documents whose
+// metadata is matched by filter. Stores supporting Filter translate it to
+// their native filter syntax.
+func WithFilter(filter Filter) Option {
+	return func(o *Options) {
+		o.Filters = filter
+	}
+}
+
 // WithEmbedder returns an Option for setting the embedder that could be used when
 // adding documents or doing similarity search (instead the embedder from the Store context)
 // this is useful when we are using multiple LLMs with single vectorstore.
@@ -59,3 +93,37 @@ func WithDeduplicater(fn func(ctx context.Context, doc schema.Document) bool) Op
 		o.Deduplicater = fn
 	}
 }
+
+// WithBatchSize returns an Option for setting the number of documents added
+// at a time. Stores default to a batch size suited to their API.
+func WithBatchSize(batchSize int) Option {
+	return func(o *Options) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithConcurrency returns an Option for setting the number of batches of
+// documents added concurrently.
+func WithConcurrency(concurrency int) Option {
+	return func(o *Options) {
+		o.Concurrency = concurrency
+	}
+}
+
+// WithRetries returns an Option for retrying failed batches of documents up
+// to maxRetries times, waiting backoff before the first retry and doubling it
+// afterwards.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.MaxRetries = maxRetries
+		o.RetryBackoff = backoff
+	}
+}
+
+// WithProgress returns an Option for setting a function called after each
+// batch of documents added.
+func WithProgress(progress ProgressFunc) Option {
+	return func(o *Options) {
+		o.Progress = progress
+	}
+}
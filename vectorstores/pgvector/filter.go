@@ -0,0 +1,128 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _sqlOperators are the jsonb operators of comparison filters. Values of
+// different JSON types are ordered by type, see
+// https://www.postgresql.org/docs/current/datatype-json.html#JSON-INDEXING.
+var _sqlOperators = map[vectorstores.FilterOp]string{ //nolint:gochecknoglobals
+	vectorstores.FilterEq:  "=",
+	vectorstores.FilterNe:  "IS DISTINCT FROM",
+	vectorstores.FilterGt:  ">",
+	vectorstores.FilterGte: ">=",
+	vectorstores.FilterLt:  "<",
+	vectorstores.FilterLte: "<=",
+}
+
+// sqlFilter builds the SQL condition of a filter on a jsonb column.
+type sqlFilter struct {
+	column string
+	// columns are the columns of the metadata keys stored in extra columns.
+	columns    map[string]string
+	firstParam int
+	args       []any
+}
+
+// filterSQL returns the SQL condition of a filter on the jsonb column, or on
+// the columns of the keys stored in extra columns, and the arguments of its
+// parameters, numbered from firstParam.
+func filterSQL(f vectorstores.Filter, column string, columns map[string]string, firstParam int) (string, []any, error) {
+	if err := f.Validate(); err != nil {
+		return "", nil, err
+	}
+	b := &sqlFilter{column: column, columns: columns, firstParam: firstParam}
+	condition, err := b.condition(f)
+	if err != nil {
+		return "", nil, err
+	}
+	return condition, b.args, nil
+}
+
+// param adds an argument and returns its parameter.
+func (b *sqlFilter) param(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d::text", b.firstParam+len(b.args)-1)
+}
+
+// columnParam adds an argument and returns its parameter, typed by the
+// column it is compared to.
+func (b *sqlFilter) columnParam(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", b.firstParam+len(b.args)-1)
+}
+
+// jsonParam adds a value encoded as JSON and returns its jsonb parameter.
+func (b *sqlFilter) jsonParam(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", vectorstores.ErrInvalidFilter, err)
+	}
+	return b.param(string(data)) + "::jsonb", nil
+}
+
+func (b *sqlFilter) condition(f vectorstores.Filter) (string, error) {
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		if len(f.Filters) == 0 {
+			return fmt.Sprint(f.Op == vectorstores.FilterAnd), nil
+		}
+		conditions := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			condition, err := b.condition(operand)
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, condition)
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(string(f.Op))+" ") + ")", nil
+	}
+
+	if column, ok := b.columns[f.Key]; ok {
+		return b.columnCondition(f, column)
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterExists:
+		return fmt.Sprintf("(%s -> %s) IS NOT NULL", b.column, b.param(f.Key)), nil
+	case vectorstores.FilterIn:
+		// a JSON array contains the primitive values it has.
+		values, err := b.jsonParam(f.Values)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s @> (%s -> %s))", values, b.column, b.param(f.Key)), nil
+	}
+
+	op, ok := _sqlOperators[f.Op]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	key := b.param(f.Key)
+	value, err := b.jsonParam(f.Value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("((%s -> %s) %s %s)", b.column, key, op, value), nil
+}
+
+// columnCondition returns the condition of a filter on an extra column.
+func (b *sqlFilter) columnCondition(f vectorstores.Filter, column string) (string, error) {
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterExists:
+		return fmt.Sprintf("(%s IS NOT NULL)", column), nil
+	case vectorstores.FilterIn:
+		return fmt.Sprintf("(%s = ANY(%s))", column, b.columnParam(f.Values)), nil
+	}
+
+	op, ok := _sqlOperators[f.Op]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	return fmt.Sprintf("(%s %s %s)", column, op, b.columnParam(f.Value)), nil
+}
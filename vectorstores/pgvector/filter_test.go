@@ -0,0 +1,63 @@
+package pgvector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestFilterSQL(t *testing.T) {
+	t.Parallel()
+	condition, args, err := filterSQL(vectorstores.And(
+		vectorstores.Eq("lang", "en"),
+		vectorstores.Or(vectorstores.Gt("year", 2020), vectorstores.In("tag", "a", "b")),
+		vectorstores.Exists("author"),
+	), "cmetadata", nil, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "(((cmetadata -> $4::text) = $5::text::jsonb) AND "+
+		"(((cmetadata -> $6::text) > $7::text::jsonb) OR ($8::text::jsonb @> (cmetadata -> $9::text))) AND "+
+		"(cmetadata -> $10::text) IS NOT NULL)", condition)
+	assert.Equal(t, []any{"lang", `"en"`, "year", "2020", `["a","b"]`, "tag", "author"}, args)
+
+	condition, args, err = filterSQL(vectorstores.Or(), "cmetadata", nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "false", condition)
+	assert.Empty(t, args)
+
+	_, _, err = filterSQL(vectorstores.Ne("lang", nil), "cmetadata", nil, 2)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
+
+func TestFilterSQLColumns(t *testing.T) {
+	t.Parallel()
+	condition, args, err := filterSQL(vectorstores.And(
+		vectorstores.Eq("tenant", "a"),
+		vectorstores.In("year", 2020, 2021),
+		vectorstores.Exists("tenant"),
+		vectorstores.Eq("lang", "en"),
+	), "data.cmetadata", map[string]string{"tenant": "data.tenant", "year": "data.year"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "((data.tenant = $2) AND (data.year = ANY($3)) AND (data.tenant IS NOT NULL) AND "+
+		"((data.cmetadata -> $4::text) = $5::text::jsonb))", condition)
+	assert.Equal(t, []any{"a", []any{2020, 2021}, "lang", `"en"`}, args)
+}
+
+func TestInsertSQL(t *testing.T) {
+	t.Parallel()
+	s := Store{
+		embeddingTableName: "embeddings",
+		vectorType:         VectorTypeHalfVec,
+		extraColumns:       []Column{{Name: "tenant", Type: "text"}},
+	}
+	assert.Equal(t, `INSERT INTO embeddings (uuid, document, embedding, cmetadata, collection_id, tenant)
+		VALUES($1, $2, $3::halfvec, $4, $5, $6) ON CONFLICT (uuid) DO
+		UPDATE SET document = $2, embedding = $3::halfvec, cmetadata = $4, collection_id = $5, tenant = $6`,
+		s.insertSQL(true))
+	assert.Equal(t, "embedding <=> $2::halfvec", s.distanceSQL("$2"))
+
+	s.vectorType = VectorTypeBit
+	assert.Equal(t, "(embedding <~> binary_quantize($2::vector)) / bit_length(embedding)", s.distanceSQL("$2"))
+	assert.Equal(t, "bit_length(embedding)", s.dimensionsSQL())
+}
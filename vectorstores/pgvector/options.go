@@ -94,12 +94,46 @@ func WithHNSWIndex(m int, efConstruction int, distanceFunction string) Option {
 	}
 }
 
+// WithIVFFlatIndex is an option for specifying the IVFFlat index parameters.
+// See here for more details: https://github.com/pgvector/pgvector#ivfflat
+//
+// lists: the number of inverted lists (rows / 1000 is a good start up to 1M rows)
+// distanceFunction: the operator class of the distance function to use, e.g. vector_cosine_ops.
+func WithIVFFlatIndex(lists int, distanceFunction string) Option {
+	return func(p *Store) {
+		p.ivfflatIndex = &IVFFlatIndex{
+			lists:            lists,
+			distanceFunction: distanceFunction,
+		}
+	}
+}
+
+// WithVectorType is an option for specifying the type of the embedding
+// column: VectorTypeHalfVec stores half precision vectors, and VectorTypeBit
+// stores binary quantized vectors searched by hamming distance. Both require
+// pgvector 0.7 or later.
+func WithVectorType(vectorType VectorType) Option {
+	return func(p *Store) {
+		p.vectorType = vectorType
+	}
+}
+
+// WithExtraColumns is an option for adding indexed columns to the embedding
+// table, filled with the metadata values of their names. Filters on these
+// metadata keys use the columns instead of the metadata.
+func WithExtraColumns(columns ...Column) Option {
+	return func(p *Store) {
+		p.extraColumns = append(p.extraColumns, columns...)
+	}
+}
+
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		collectionName:      DefaultCollectionName,
 		preDeleteCollection: DefaultPreDeleteCollection,
 		embeddingTableName:  DefaultEmbeddingStoreTableName,
 		collectionTableName: DefaultCollectionStoreTableName,
+		vectorType:          VectorTypeVector,
 	}
 
 	for _, opt := range opts {
@@ -114,5 +148,17 @@ func applyClientOptions(opts ...Option) (Store, error) {
 		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
 	}
 
+	switch o.vectorType {
+	case VectorTypeVector, VectorTypeHalfVec, VectorTypeBit:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown vector type %q", ErrInvalidOptions, o.vectorType)
+	}
+
+	for _, column := range o.extraColumns {
+		if column.Name == "" || column.Type == "" {
+			return Store{}, fmt.Errorf("%w: extra columns must have a name and a type", ErrInvalidOptions)
+		}
+	}
+
 	return *o, nil
 }
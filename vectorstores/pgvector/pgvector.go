@@ -26,6 +26,8 @@ const (
 	// of the vector extension. The value is deliberately set to the same as python langchain
 	// https://github.com/langchain-ai/langchain/blob/v0.0.340/libs/langchain/langchain/vectorstores/pgvector.py#L167
 	pgLockIDExtension = 1573678846307946496
+	// _defaultBatchSize is the number of documents inserted at a time.
+	_defaultBatchSize = 500
 )
 
 var (
@@ -57,6 +59,9 @@ type Store struct {
 	preDeleteCollection bool
 	vectorDimensions    int
 	hnswIndex           *HNSWIndex
+	ivfflatIndex        *IVFFlatIndex
+	vectorType          VectorType
+	extraColumns        []Column
 }
 
 type HNSWIndex struct {
@@ -65,7 +70,34 @@ type HNSWIndex struct {
 	distanceFunction string
 }
 
-var _ vectorstores.VectorStore = Store{}
+type IVFFlatIndex struct {
+	lists            int
+	distanceFunction string
+}
+
+// VectorType is the type of the embedding column.
+type VectorType string
+
+const (
+	VectorTypeVector  VectorType = "vector"
+	VectorTypeHalfVec VectorType = "halfvec"
+	VectorTypeBit     VectorType = "bit"
+)
+
+// Column is an extra column of the embedding table, e.g.
+// Column{Name: "tenant_id", Type: "uuid"}.
+type Column struct {
+	// Name is the name of the column and of the metadata key filling it.
+	Name string
+	// Type is the SQL type of the column.
+	Type string
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (Store, error) {
@@ -168,13 +200,13 @@ func (s Store) createEmbeddingTableIfNotExists(ctx context.Context, tx pgx.Tx) e
 
 	sql := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
 	collection_id uuid,
-	embedding vector%s,
+	embedding %s%s,
 	document varchar,
 	cmetadata json,
 	"uuid" uuid NOT NULL,
 	CONSTRAINT langchain_pg_embedding_collection_id_fkey
 	FOREIGN KEY (collection_id) REFERENCES %s (uuid) ON DELETE CASCADE,
-	PRIMARY KEY (uuid))`, s.embeddingTableName, vectorDimensions, s.collectionTableName)
+	PRIMARY KEY (uuid))`, s.embeddingTableName, s.vectorType, vectorDimensions, s.collectionTableName)
 	if _, err := tx.Exec(ctx, sql); err != nil {
 		return err
 	}
@@ -197,11 +229,91 @@ func (s Store) createEmbeddingTableIfNotExists(ctx context.Context, tx pgx.Tx) e
 		}
 	}
 
+	// See this for more details on IVFFlat indexes: https://github.com/pgvector/pgvector#ivfflat
+	if s.ivfflatIndex != nil {
+		sql = fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS %s_embedding_ivfflat ON %s USING ivfflat (embedding %s)`,
+			s.embeddingTableName, s.embeddingTableName, s.ivfflatIndex.distanceFunction,
+		)
+		if s.ivfflatIndex.lists > 0 {
+			sql = fmt.Sprintf("%s WITH (lists = %d)", sql, s.ivfflatIndex.lists)
+		}
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+
+	for _, column := range s.extraColumns {
+		sql = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, s.embeddingTableName, column.Name, column.Type)
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		sql = fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_%s ON %s (%s)`,
+			s.embeddingTableName, column.Name, s.embeddingTableName, column.Name)
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// insertSQL returns the statement inserting a document, replacing the
+// document with the same id if upsert is set.
+func (s Store) insertSQL(upsert bool) string {
+	columns := []string{"uuid", "document", "embedding", "cmetadata", "collection_id"}
+	values := []string{"$1", "$2", s.vectorParam("$3"), "$4", "$5"}
+	for i, column := range s.extraColumns {
+		columns = append(columns, column.Name)
+		values = append(values, fmt.Sprintf("$%d", i+6)) //nolint:mnd
+	}
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)
+		VALUES(%s)`, s.embeddingTableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+	if !upsert {
+		return sql
+	}
+	updates := make([]string, 0, len(columns)-1)
+	for i, column := range columns[1:] {
+		updates = append(updates, fmt.Sprintf("%s = %s", column, values[i+1]))
+	}
+	return fmt.Sprintf(`%s ON CONFLICT (uuid) DO
+		UPDATE SET %s`, sql, strings.Join(updates, ", "))
+}
+
+// vectorParam returns the expression converting the vector parameter to the
+// type of the embedding column.
+func (s Store) vectorParam(param string) string {
+	switch s.vectorType {
+	case VectorTypeHalfVec:
+		return param + "::halfvec"
+	case VectorTypeBit:
+		return "binary_quantize(" + param + "::vector)"
+	case VectorTypeVector:
+	}
+	return param
+}
+
+// distanceSQL returns the expression of the distance between the embedding
+// column and the vector parameter: the cosine distance, or the hamming
+// distance divided by the number of bits for bit vectors.
+func (s Store) distanceSQL(param string) string {
+	if s.vectorType == VectorTypeBit {
+		return fmt.Sprintf("(embedding <~> %s) / bit_length(embedding)", s.vectorParam(param))
+	}
+	return "embedding <=> " + s.vectorParam(param)
+}
+
+// dimensionsSQL returns the expression of the dimensions of the embedding column.
+func (s Store) dimensionsSQL() string {
+	if s.vectorType == VectorTypeBit {
+		return "bit_length(embedding)"
+	}
+	return "vector_dims(embedding)"
+}
+
 // AddDocuments adds documents to the Postgres collection associated with 'Store'.
-// and returns the ids of the added documents.
+// and returns the ids of the added documents. Documents are inserted in batches
+// of 500 by default; adding batches concurrently requires a pgxpool.Pool.
 func (s Store) AddDocuments(
 	ctx context.Context,
 	docs []schema.Document,
@@ -214,6 +326,54 @@ func (s Store) AddDocuments(
 
 	docs = s.deduplicate(ctx, opts, docs)
 
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.New().String()
+	}
+	return ids, s.addDocuments(ctx, s.insertSQL(false), ids, docs, opts)
+}
+
+// UpsertDocuments adds documents with the ids, which must be UUIDs, to the
+// Postgres collection associated with 'Store', replacing the documents with
+// the same ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+
+	return s.addDocuments(ctx, s.insertSQL(true), ids, docs, opts)
+}
+
+// addDocuments embeds the documents and inserts them with the ids using the
+// insert statement sql, in batches.
+func (s Store) addDocuments(
+	ctx context.Context,
+	sql string,
+	ids []string,
+	docs []schema.Document,
+	opts vectorstores.Options,
+) error {
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.insertDocuments(ctx, sql, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) insertDocuments(
+	ctx context.Context,
+	sql string,
+	ids []string,
+	docs []schema.Document,
+	opts vectorstores.Options,
+) error {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -225,24 +385,63 @@ func (s Store) AddDocuments(
 	}
 	vectors, err := embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, s.vectorDimensions); err != nil {
+		return err
 	}
 
 	b := &pgx.Batch{}
-	sql := fmt.Sprintf(`INSERT INTO %s (uuid, document, embedding, cmetadata, collection_id)
-		VALUES($1, $2, $3, $4, $5)`, s.embeddingTableName)
-
-	ids := make([]string, len(docs))
 	for docIdx, doc := range docs {
-		id := uuid.New().String()
-		ids[docIdx] = id
-		b.Queue(sql, id, doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, s.collectionUUID)
+		args := []any{ids[docIdx], doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, s.collectionUUID}
+		for _, column := range s.extraColumns {
+			args = append(args, doc.Metadata[column.Name])
+		}
+		b.Queue(sql, args...)
+	}
+	return s.conn.SendBatch(ctx, b).Close()
+}
+
+// DeleteByIDs deletes the documents with the ids from the collection, or
+// from the collection named by the name space of the options.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1) AND uuid = ANY($2)`,
+		s.embeddingTableName, s.collectionTableName)
+	_, err := s.conn.Exec(ctx, sql, s.getNameSpace(opts), ids)
+	return err
+}
+
+// DeleteByFilter deletes the documents whose metadata is matched by the
+// filter, either a map of metadata keys to values or a vectorstores.Filter,
+// from the collection, or from the collection named by the name space of the
+// options.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	opts.Filters = filter
+	conditions, filterArgs, err := s.getFilterConditions(opts, "", 2) //nolint:mnd
+	if err != nil {
+		return err
 	}
-	return ids, s.conn.SendBatch(ctx, b).Close()
+	if len(conditions) == 0 {
+		return vectorstores.ErrMissingFilter
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1) AND %s`,
+		s.embeddingTableName, s.collectionTableName, strings.Join(conditions, " AND "))
+	_, err = s.conn.Exec(ctx, sql, append([]any{s.getNameSpace(opts)}, filterArgs...)...)
+	return err
 }
 
 //nolint:cyclop
@@ -253,12 +452,15 @@ func (s Store) SimilaritySearch(
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by pgvector", vectorstores.ErrInvalidHybridSearch)
+	}
 	collectionName := s.getNameSpace(opts)
 	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
 		return nil, err
 	}
-	filter, err := s.getFilters(opts)
+	filterConditions, filterArgs, err := s.getFilterConditions(opts, "data", 4) //nolint:mnd
 	if err != nil {
 		return nil, err
 	}
@@ -272,11 +474,9 @@ func (s Store) SimilaritySearch(
 	}
 	whereQuerys := make([]string, 0)
 	if scoreThreshold != 0 {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("data.distance < %f", 1-scoreThreshold))
-	}
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(data.cmetadata ->> '%s') = '%s'", k, v))
+		whereQuerys = append(whereQuerys, fmt.Sprintf("data.distance <= %f", vectorstores.CosineDistanceThreshold(scoreThreshold)))
 	}
+	whereQuerys = append(whereQuerys, filterConditions...)
 	whereQuery := strings.Join(whereQuerys, " AND ")
 	if len(whereQuery) == 0 {
 		whereQuery = "TRUE"
@@ -288,9 +488,7 @@ func (s Store) SimilaritySearch(
     FROM
         %s
     WHERE
-        vector_dims (
-                embedding
-        ) = $1
+        %s = $1
 )
 SELECT
 	data.document,
@@ -299,17 +497,18 @@ SELECT
 FROM (
 	SELECT
 		filtered_embedding_dims.*,
-		embedding <=> $2 AS distance
+		%s AS distance
 	FROM
 		filtered_embedding_dims
 		JOIN %s ON filtered_embedding_dims.collection_id=%s.uuid WHERE %s.name='%s') AS data
 WHERE %s
 ORDER BY
 	data.distance
-LIMIT $3`, s.embeddingTableName,
+LIMIT $3`, s.embeddingTableName, s.dimensionsSQL(), s.distanceSQL("$2"),
 		s.collectionTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, dims, pgvector.NewVector(embedderData), numDocuments)
+	args := append([]any{dims, pgvector.NewVector(embedderData), numDocuments}, filterArgs...)
+	rows, err := s.conn.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -318,9 +517,12 @@ LIMIT $3`, s.embeddingTableName,
 	docs := make([]schema.Document, 0)
 	for rows.Next() {
 		doc := schema.Document{}
-		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &doc.Score); err != nil {
+		var distance float64
+		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &distance); err != nil {
 			return nil, err
 		}
+		// the distance of bit vectors is their normalized Hamming distance.
+		doc.Score = vectorstores.CosineDistanceScore(distance)
 		docs = append(docs, doc)
 	}
 	return docs, rows.Err()
@@ -334,14 +536,10 @@ func (s Store) Search(
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
 	collectionName := s.getNameSpace(opts)
-	filter, err := s.getFilters(opts)
+	whereQuerys, filterArgs, err := s.getFilterConditions(opts, s.embeddingTableName, 2) //nolint:mnd
 	if err != nil {
 		return nil, err
 	}
-	whereQuerys := make([]string, 0)
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(%s.cmetadata ->> '%s') = '%s'", s.embeddingTableName, k, v))
-	}
 	whereQuery := strings.Join(whereQuerys, " AND ")
 	if len(whereQuery) == 0 {
 		whereQuery = "TRUE"
@@ -355,7 +553,7 @@ WHERE %s.name='%s' AND %s
 LIMIT $1`, s.embeddingTableName, s.embeddingTableName, s.embeddingTableName,
 		s.collectionTableName, s.embeddingTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, numDocuments)
+	rows, err := s.conn.Query(ctx, sql, append([]any{numDocuments}, filterArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -434,6 +632,47 @@ func (s Store) getFilters(opts vectorstores.Options) (map[string]any, error) {
 	return map[string]any{}, nil
 }
 
+// getFilterConditions returns the SQL conditions of the filters of the
+// options on the metadata and extra columns of the table, which may be empty
+// for unqualified columns, and the arguments of their parameters, numbered
+// from firstParam.
+func (s Store) getFilterConditions(opts vectorstores.Options, table string, firstParam int) ([]string, []any, error) {
+	qualify := func(column string) string {
+		if table == "" {
+			return column
+		}
+		return table + "." + column
+	}
+	columns := make(map[string]string, len(s.extraColumns))
+	for _, column := range s.extraColumns {
+		columns[column.Name] = qualify(column.Name)
+	}
+
+	if filter, ok := opts.Filters.(vectorstores.Filter); ok {
+		condition, args, err := filterSQL(filter, qualify("cmetadata"), columns, firstParam)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []string{condition}, args, nil
+	}
+
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	conditions := make([]string, 0, len(filters))
+	var args []any
+	for k, v := range filters {
+		if column, ok := columns[k]; ok {
+			args = append(args, v)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", column, firstParam+len(args)-1))
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s ->> '%s') = '%s'", qualify("cmetadata"), k, v))
+	}
+	return conditions, args, nil
+}
+
 func (s Store) deduplicate(
 	ctx context.Context,
 	opts vectorstores.Options,
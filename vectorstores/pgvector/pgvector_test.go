@@ -119,6 +119,59 @@ func TestPgvectorStoreRest(t *testing.T) {
 	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
 
+func TestPgvectorUpsertAndDelete(t *testing.T) {
+	t.Parallel()
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := context.Background()
+
+	llm, err := openai.New(
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(e),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	ids := []string{uuid.New().String(), uuid.New().String()}
+	err = store.UpsertDocuments(ctx, ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "paris", Metadata: map[string]any{"country": "france"}},
+	})
+	require.NoError(t, err)
+	err = store.UpsertDocuments(ctx, ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Eq("country", "france")))
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, store.DeleteByIDs(ctx, ids))
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestPgvectorStoreRestWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 	pgvectorURL := preCheckEnvSetting(t)
@@ -0,0 +1,88 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var _ vectorstores.CollectionManager = Store{}
+
+// _metrics maps distance metrics to the metrics of Pinecone indexes.
+var _metrics = map[vectorstores.DistanceMetric]pinecone.IndexMetric{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     pinecone.Cosine,
+	vectorstores.DistanceEuclidean:  pinecone.Euclidean,
+	vectorstores.DistanceDotProduct: pinecone.Dotproduct,
+}
+
+// CreateCollection creates a serverless index in the cloud and region of the
+// store, set with WithServerlessSpec. Use the host of the index, as returned
+// by the Pinecone console or API, to connect a store to it.
+func (s Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	metric, ok := _metrics[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	_, err = s.client.CreateServerlessIndex(ctx, &pinecone.CreateServerlessIndexRequest{
+		Name:      name,
+		Dimension: int32(opts.Dimensions), //nolint:gosec
+		Metric:    metric,
+		Cloud:     pinecone.Cloud(s.cloud),
+		Region:    s.region,
+	})
+	return err
+}
+
+// DropCollection deletes an index and its vectors.
+func (s Store) DropCollection(ctx context.Context, name string) error {
+	return s.client.DeleteIndex(ctx, name)
+}
+
+// ListCollections returns the names of the indexes.
+func (s Store) ListCollections(ctx context.Context) ([]string, error) {
+	indexes, err := s.client.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index.Name
+	}
+	return names, nil
+}
+
+// ListNameSpaces returns the name spaces of the index having vectors.
+func (s Store) ListNameSpaces(ctx context.Context) ([]string, error) {
+	indexConn, err := s.client.Index(s.host)
+	if err != nil {
+		return nil, err
+	}
+	defer indexConn.Close()
+
+	stats, err := indexConn.DescribeIndexStats(&ctx)
+	if err != nil {
+		return nil, err
+	}
+	nameSpaces := make([]string, 0, len(stats.Namespaces))
+	for nameSpace := range stats.Namespaces {
+		nameSpaces = append(nameSpaces, nameSpace)
+	}
+	return nameSpaces, nil
+}
+
+// DeleteNameSpace deletes all the vectors of a name space of the index.
+func (s Store) DeleteNameSpace(ctx context.Context, nameSpace string) error {
+	indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteAllVectorsInNamespace(&ctx)
+}
@@ -0,0 +1,48 @@
+package pinecone
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _filterOperators are the Pinecone operators of comparison filters.
+var _filterOperators = map[vectorstores.FilterOp]string{ //nolint:gochecknoglobals
+	vectorstores.FilterEq:  "$eq",
+	vectorstores.FilterNe:  "$ne",
+	vectorstores.FilterGt:  "$gt",
+	vectorstores.FilterGte: "$gte",
+	vectorstores.FilterLt:  "$lt",
+	vectorstores.FilterLte: "$lte",
+}
+
+// metadataFilter translates a filter to a Pinecone metadata filter, see
+// https://docs.pinecone.io/guides/data/filter-with-metadata.
+func metadataFilter(f vectorstores.Filter) (map[string]any, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		operands := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			filter, err := metadataFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, filter)
+		}
+		return map[string]any{"$" + string(f.Op): operands}, nil
+	case vectorstores.FilterIn:
+		return map[string]any{f.Key: map[string]any{"$in": f.Values}}, nil
+	case vectorstores.FilterExists:
+		return map[string]any{f.Key: map[string]any{"$exists": true}}, nil
+	}
+
+	op, ok := _filterOperators[f.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	return map[string]any{f.Key: map[string]any{op: f.Value}}, nil
+}
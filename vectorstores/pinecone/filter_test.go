@@ -0,0 +1,30 @@
+package pinecone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestMetadataFilter(t *testing.T) {
+	t.Parallel()
+	filter, err := metadataFilter(vectorstores.Or(
+		vectorstores.And(vectorstores.Eq("lang", "en"), vectorstores.Lt("year", 2020)),
+		vectorstores.In("tag", "a", "b"),
+		vectorstores.Exists("author"),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$or": []any{
+		map[string]any{"$and": []any{
+			map[string]any{"lang": map[string]any{"$eq": "en"}},
+			map[string]any{"year": map[string]any{"$lt": 2020}},
+		}},
+		map[string]any{"tag": map[string]any{"$in": []any{"a", "b"}}},
+		map[string]any{"author": map[string]any{"$exists": true}},
+	}}, filter)
+
+	_, err = metadataFilter(vectorstores.In("tag"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
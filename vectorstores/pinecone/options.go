@@ -7,11 +7,16 @@ import (
 	"strings"
 
 	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores"
 )
 
 const (
 	_pineconeEnvVrName = "PINECONE_API_KEY"
 	_defaultTextKey    = "text"
+	// _defaultBatchSize is the number of vectors upserted at a time.
+	_defaultBatchSize = 100
+	_defaultCloud     = "aws"
+	_defaultRegion    = "us-east-1"
 )
 
 // ErrInvalidOptions is returned when the options given are invalid.
@@ -59,9 +64,53 @@ func WithNameSpace(nameSpace string) Option {
 	}
 }
 
+// WithSparseEmbedder is an option for setting a sparse embedder, e.g.
+// embeddings.NewBM25(), upserting and querying sparse-dense vectors for
+// hybrid search. The index must use the dotproduct metric. Searches sum the
+// dense and sparse scores unless vectorstores.WithHybridSearch is given to
+// configure the fusion.
+func WithSparseEmbedder(e embeddings.SparseEmbedder) Option {
+	return func(p *Store) {
+		p.sparseEmbedder = e
+	}
+}
+
+// WithServerlessSpec is an option for setting the cloud, e.g. "aws", "gcp"
+// or "azure", and the region of the serverless indexes created with
+// CreateCollection. Defaults to "aws" and "us-east-1".
+func WithServerlessSpec(cloud, region string) Option {
+	return func(p *Store) {
+		p.cloud = cloud
+		p.region = region
+	}
+}
+
+// WithIntegratedInference is an option for using an index with integrated
+// embedding, which embeds the text of records and queries with its model
+// instead of the embedder, which is then optional. The text is stored in the
+// field of the text key, which must be the field mapped to the text of the
+// index. Hybrid searches aren't supported.
+func WithIntegratedInference() Option {
+	return func(p *Store) {
+		p.integratedInference = true
+	}
+}
+
+// WithDistanceMetric is an option for setting the distance metric of the
+// index, converting the scores of the matches found to relevance scores.
+// Defaults to vectorstores.DistanceCosine.
+func WithDistanceMetric(metric vectorstores.DistanceMetric) Option {
+	return func(p *Store) {
+		p.metric = metric
+	}
+}
+
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		textKey: _defaultTextKey,
+		cloud:   _defaultCloud,
+		region:  _defaultRegion,
+		metric:  vectorstores.DistanceCosine,
 	}
 
 	for _, opt := range opts {
@@ -72,7 +121,11 @@ func applyClientOptions(opts ...Option) (Store, error) {
 		return Store{}, fmt.Errorf("%w: missing host", ErrInvalidOptions)
 	}
 
-	if o.embedder == nil {
+	if _, ok := _metrics[o.metric]; !ok {
+		return Store{}, fmt.Errorf("%w: %w: %s", ErrInvalidOptions, vectorstores.ErrUnsupportedDistanceMetric, o.metric)
+	}
+
+	if o.embedder == nil && !o.integratedInference {
 		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/pinecone"
@@ -26,19 +27,34 @@ var (
 	ErrEmptyResponse         = errors.New("empty response")
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
+	// ErrRecordsRequest is returned when a request to the records API of an
+	// index with integrated embedding fails.
+	ErrRecordsRequest = errors.New("records request failed")
 )
 
 // Store is a wrapper around the pinecone rest API and grpc client.
 type Store struct {
-	embedder embeddings.Embedder
-	client   *pinecone.Client
+	embedder       embeddings.Embedder
+	sparseEmbedder embeddings.SparseEmbedder
+	client         *pinecone.Client
 
 	host      string
 	apiKey    string
 	textKey   string
 	nameSpace string
+	cloud     string
+	region    string
+
+	integratedInference bool
+	metric              vectorstores.DistanceMetric
 }
 
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
+
 // New creates a new Store with options. Options for WithAPIKey, WithHost and WithEmbedder must be set.
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -56,17 +72,93 @@ func New(opts ...Option) (Store, error) {
 
 // AddDocuments creates vector embeddings from the documents using the embedder
 // and upsert the vectors to the pinecone index and returns the ids of the added documents.
+// Vectors are upserted in batches of 100 by default, as recommended by pinecone.
 func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.New().String()
+	}
+	if err := s.UpsertDocuments(ctx, ids, docs, options...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the
+// embedder and upserts the vectors with the ids to the pinecone index,
+// replacing the vectors with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
 	opts := s.getOptions(options...)
+	if s.integratedInference {
+		nameSpace := s.getNameSpace(opts)
+		return vectorstores.RunBatches(ctx, opts, len(docs), _defaultRecordsBatchSize, func(ctx context.Context, start, end int) error { //nolint:lll
+			return s.upsertRecords(ctx, nameSpace, ids[start:end], docs[start:end])
+		})
+	}
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.addDocuments(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+// DeleteByIDs deletes the vectors with the ids from the pinecone index.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	indexConn, err := s.client.IndexWithNamespace(s.host, s.getNameSpace(s.getOptions(options...)))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsById(&ctx, ids)
+}
+
+// DeleteByFilter deletes the vectors whose metadata is matched by the filter,
+// given in the pinecone filter syntax or as a vectorstores.Filter, from the
+// pinecone index. Serverless indexes don't support deleting by filter.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	opts.Filters = filter
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+	protoFilterStruct, err := s.createProtoStructFilter(filters)
+	if err != nil {
+		return err
+	}
+
+	indexConn, err := s.client.IndexWithNamespace(s.host, s.getNameSpace(opts))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsByFilter(&ctx, protoFilterStruct)
+}
 
+// addDocuments creates vector embeddings from the documents using the embedder
+// and upserts the vectors with the ids to the pinecone index.
+func (s Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
 	nameSpace := s.getNameSpace(opts)
 
 	indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer indexConn.Close()
 
@@ -77,11 +169,29 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
+	}
+	stats, err := indexConn.DescribeIndexStats(&ctx)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, int(stats.Dimension)); err != nil {
+		return err
+	}
+
+	var sparseVectors []embeddings.SparseVector
+	if s.sparseEmbedder != nil {
+		sparseVectors, err = s.sparseEmbedder.EmbedDocumentsSparse(ctx, texts)
+		if err != nil {
+			return err
+		}
+		if len(sparseVectors) != len(docs) {
+			return ErrEmbedderWrongNumberVectors
+		}
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -97,31 +207,25 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	pineconeVectors := make([]*pinecone.Vector, 0, len(vectors))
 
-	ids := make([]string, len(vectors))
 	for i := 0; i < len(vectors); i++ {
 		metadataStruct, err := structpb.NewStruct(metadatas[i])
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		id := uuid.New().String()
-		ids[i] = id
-		pineconeVectors = append(
-			pineconeVectors,
-			&pinecone.Vector{
-				Id:       id,
-				Values:   vectors[i],
-				Metadata: metadataStruct,
-			},
-		)
+		vector := &pinecone.Vector{
+			Id:       ids[i],
+			Values:   vectors[i],
+			Metadata: metadataStruct,
+		}
+		if sparseVectors != nil {
+			vector.SparseValues = newSparseValues(sparseVectors[i])
+		}
+		pineconeVectors = append(pineconeVectors, vector)
 	}
 
 	_, err = indexConn.UpsertVectors(&ctx, pineconeVectors)
-	if err != nil {
-		return nil, err
-	}
-
-	return ids, nil
+	return err
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
@@ -130,6 +234,14 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	opts := s.getOptions(options...)
 
 	nameSpace := s.getNameSpace(opts)
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	if s.integratedInference {
+		return s.searchIntegrated(ctx, nameSpace, query, numDocuments, filters, opts)
+	}
+
 	indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
 	if err != nil {
 		return nil, err
@@ -137,7 +249,6 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	defer indexConn.Close()
 
 	var protoFilterStruct *structpb.Struct
-	filters := s.getFilters(opts)
 	if filters != nil {
 		protoFilterStruct, err = s.createProtoStructFilter(filters)
 		if err != nil {
@@ -150,21 +261,31 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
+	if opts.HybridSearch != nil {
+		return s.hybridSearch(ctx, indexConn, query, *opts.HybridSearch, numDocuments, scoreThreshold, protoFilterStruct)
+	}
+
 	vector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	queryResult, err := indexConn.QueryByVectorValues(
-		&ctx,
-		&pinecone.QueryByVectorValuesRequest{
-			Vector:          vector,
-			TopK:            uint32(numDocuments),
-			Filter:          protoFilterStruct,
-			IncludeMetadata: true,
-			IncludeValues:   true,
-		},
-	)
+	queryRequest := &pinecone.QueryByVectorValuesRequest{
+		Vector:          vector,
+		TopK:            uint32(numDocuments),
+		Filter:          protoFilterStruct,
+		IncludeMetadata: true,
+		IncludeValues:   true,
+	}
+	if s.sparseEmbedder != nil {
+		sparse, err := s.sparseEmbedder.EmbedQuerySparse(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		queryRequest.SparseValues = newSparseValues(sparse)
+	}
+
+	queryResult, err := indexConn.QueryByVectorValues(&ctx, queryRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -173,10 +294,123 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, ErrEmptyResponse
 	}
 
-	return s.getDocumentsFromMatches(queryResult, scoreThreshold)
+	score := s.score
+	if s.sparseEmbedder != nil {
+		// the scores of sparse-dense vectors are sums of the dense and sparse
+		// dot products.
+		score = rawScore
+	}
+	return s.getDocumentsFromMatches(queryResult, scoreThreshold, score)
+}
+
+// searchIntegrated searches the records of an index with integrated
+// embedding.
+func (s Store) searchIntegrated(ctx context.Context,
+	nameSpace string,
+	query string,
+	numDocuments int,
+	filters any,
+	opts vectorstores.Options,
+) ([]schema.Document, error) {
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: hybrid search is not supported with integrated inference",
+			vectorstores.ErrInvalidHybridSearch)
+	}
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchRecords(ctx, nameSpace, query, numDocuments, filters, scoreThreshold)
 }
 
-func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsResponse, scoreThreshold float32) ([]schema.Document, error) {
+// hybridSearch queries the index with both the dense and the sparse vectors.
+// The weighted fusion is a single query with the dense values scaled by Alpha
+// and the sparse values by 1 - Alpha, while Reciprocal Rank Fusion queries the
+// dense and sparse vectors separately and fuses the results.
+func (s Store) hybridSearch(ctx context.Context,
+	indexConn *pinecone.IndexConnection,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	scoreThreshold float32,
+	filter *structpb.Struct,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+
+	vector := hybrid.Vector
+	if vector == nil {
+		var err error
+		vector, err = s.embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sparse := hybrid.Sparse
+	if sparse == nil {
+		if s.sparseEmbedder == nil {
+			return nil, fmt.Errorf("%w: missing sparse vector", vectorstores.ErrInvalidHybridSearch)
+		}
+		var err error
+		sparse, err = s.sparseEmbedder.EmbedQuerySparse(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	queryRequest := &pinecone.QueryByVectorValuesRequest{
+		Vector:          vector,
+		TopK:            uint32(numDocuments),
+		Filter:          filter,
+		IncludeMetadata: true,
+	}
+
+	if hybrid.Fusion == vectorstores.FusionWeighted {
+		queryRequest.Vector = scale(vector, hybrid.Alpha)
+		queryRequest.SparseValues = newSparseValues(sparse)
+		queryRequest.SparseValues.Values = scale(queryRequest.SparseValues.Values, 1-hybrid.Alpha)
+		queryResult, err := indexConn.QueryByVectorValues(&ctx, queryRequest)
+		if err != nil {
+			return nil, err
+		}
+		if len(queryResult.Matches) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		return s.getDocumentsFromMatches(queryResult, scoreThreshold, rawScore)
+	}
+
+	denseResult, err := indexConn.QueryByVectorValues(&ctx, queryRequest)
+	if err != nil {
+		return nil, err
+	}
+	// the dense values are required, zeroing them leaves the sparse score.
+	queryRequest.Vector = make([]float32, len(vector))
+	queryRequest.SparseValues = newSparseValues(sparse)
+	sparseResult, err := indexConn.QueryByVectorValues(&ctx, queryRequest)
+	if err != nil {
+		return nil, err
+	}
+	if len(denseResult.Matches) == 0 && len(sparseResult.Matches) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	dense, err := s.getDocumentsFromMatches(denseResult, 0, rawScore)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.getDocumentsFromMatches(sparseResult, 0, rawScore)
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.Fuse(dense, matches, numDocuments, scoreThreshold, nil), nil
+}
+
+func (s Store) getDocumentsFromMatches(
+	queryResult *pinecone.QueryVectorsResponse,
+	scoreThreshold float32,
+	score func(float32) float32,
+) ([]schema.Document, error) {
 	resultDocuments := make([]schema.Document, 0)
 	for _, match := range queryResult.Matches {
 		metadata := match.Vector.Metadata.AsMap()
@@ -189,11 +423,11 @@ func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsRespons
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    metadata,
-			Score:       match.Score,
+			Score:       score(match.Score),
 		}
 
 		// If scoreThreshold is not 0, we only return matches with a score above the threshold.
-		if scoreThreshold != 0 && match.Score >= scoreThreshold {
+		if scoreThreshold != 0 && doc.Score >= scoreThreshold {
 			resultDocuments = append(resultDocuments, doc)
 		} else if scoreThreshold == 0 { // If scoreThreshold is 0, we return all matches.
 			resultDocuments = append(resultDocuments, doc)
@@ -202,6 +436,23 @@ func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsRespons
 	return resultDocuments, nil
 }
 
+// score converts the score of a match, a squared distance for euclidean
+// indexes, to a relevance score.
+func (s Store) score(score float32) float32 {
+	switch s.metric { //nolint:exhaustive
+	case vectorstores.DistanceEuclidean:
+		return vectorstores.SquaredEuclideanDistanceScore(float64(score))
+	case vectorstores.DistanceDotProduct:
+		return vectorstores.DotProductScore(float64(score))
+	}
+	return vectorstores.CosineSimilarityScore(float64(score))
+}
+
+// rawScore keeps the scores of hybrid searches, which aren't similarities.
+func rawScore(score float32) float32 {
+	return score
+}
+
 func (s Store) getNameSpace(opts vectorstores.Options) string {
 	if opts.NameSpace != "" {
 		return opts.NameSpace
@@ -216,11 +467,11 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-func (s Store) getFilters(opts vectorstores.Options) any {
-	if opts.Filters != nil {
-		return opts.Filters
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if filter, ok := opts.Filters.(vectorstores.Filter); ok {
+		return metadataFilter(filter)
 	}
-	return nil
+	return opts.Filters, nil
 }
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
@@ -245,3 +496,15 @@ func (s Store) createProtoStructFilter(filter any) (*structpb.Struct, error) {
 
 	return &filterStruct, nil
 }
+
+func newSparseValues(v embeddings.SparseVector) *pinecone.SparseValues {
+	return &pinecone.SparseValues{Indices: v.Indices(), Values: v.Values()}
+}
+
+func scale(values []float32, weight float32) []float32 {
+	scaled := make([]float32, len(values))
+	for i, v := range values {
+		scaled[i] = v * weight
+	}
+	return scaled
+}
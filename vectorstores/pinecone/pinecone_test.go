@@ -1,7 +1,11 @@
 package pinecone_test
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -551,3 +555,61 @@ func TestPineconeAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	require.Contains(t, result, "purple", "expected black in purple")
 }
+
+func TestPineconeIntegratedInference(t *testing.T) {
+	t.Parallel()
+
+	var records []map[string]any
+	var search map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get("Api-Key"))
+		switch r.URL.Path {
+		case "/records/namespaces/ns/upsert":
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var record map[string]any
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+				records = append(records, record)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "/records/namespaces/__default__/search":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&search))
+			_, _ = w.Write([]byte(`{"result":{"hits":[
+				{"_id":"1","_score":0.9,"fields":{"text":"tokyo","country":"japan"}},
+				{"_id":"2","_score":0.1,"fields":{"text":"potato"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store, err := pinecone.New(
+		pinecone.WithAPIKey("key"),
+		pinecone.WithHost(server.URL),
+		pinecone.WithIntegratedInference(),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = store.UpsertDocuments(ctx, []string{"1", "2"}, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato"},
+	}, vectorstores.WithNameSpace("ns"))
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"_id": "1", "text": "tokyo", "country": "japan"},
+		{"_id": "2", "text": "potato"},
+	}, records)
+
+	docs, err := store.SimilaritySearch(ctx, "japan", 2,
+		vectorstores.WithScoreThreshold(0.5), vectorstores.WithFilter(vectorstores.Eq("country", "japan")))
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}, Score: 0.9},
+	}, docs)
+	require.Equal(t, map[string]any{"query": map[string]any{
+		"inputs": map[string]any{"text": "japan"},
+		"top_k":  float64(2),
+		"filter": map[string]any{"country": map[string]any{"$eq": "japan"}},
+	}}, search)
+}
@@ -0,0 +1,158 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// _recordsAPIVersion is the version of the API of records, which embeds
+	// them with the model integrated with the index.
+	_recordsAPIVersion = "2025-01"
+	// _defaultRecordsBatchSize is the number of records upserted at a time,
+	// the maximum of indexes with integrated embedding.
+	_defaultRecordsBatchSize = 96
+	// _defaultNameSpace is the name space of records without name space.
+	_defaultNameSpace = "__default__"
+)
+
+type searchRecordsQuery struct {
+	Inputs map[string]string `json:"inputs"`
+	TopK   int               `json:"top_k"`
+	Filter any               `json:"filter,omitempty"`
+}
+
+type searchRecordsBody struct {
+	Query searchRecordsQuery `json:"query"`
+}
+
+type searchRecordsResponse struct {
+	Result struct {
+		Hits []struct {
+			ID     string         `json:"_id"`
+			Score  float32        `json:"_score"`
+			Fields map[string]any `json:"fields"`
+		} `json:"hits"`
+	} `json:"result"`
+}
+
+// upsertRecords upserts the documents with the ids as records, whose text
+// is embedded by the model integrated with the index.
+func (s Store) upsertRecords(ctx context.Context, nameSpace string, ids []string, docs []schema.Document) error {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	for i, doc := range docs {
+		record := make(map[string]any, len(doc.Metadata)+2) //nolint:mnd
+		for key, value := range doc.Metadata {
+			record[key] = value
+		}
+		record["_id"] = ids[i]
+		record[s.textKey] = doc.PageContent
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	body, err := s.doRecordsRequest(ctx, nameSpace, "upsert", "application/x-ndjson", buf)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// searchRecords searches the records whose text is the most similar to the
+// query, embedded by the model integrated with the index.
+func (s Store) searchRecords(
+	ctx context.Context,
+	nameSpace string,
+	query string,
+	numDocuments int,
+	filter any,
+	scoreThreshold float32,
+) ([]schema.Document, error) {
+	payload, err := json.Marshal(searchRecordsBody{Query: searchRecordsQuery{
+		Inputs: map[string]string{"text": query},
+		TopK:   numDocuments,
+		Filter: filter,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.doRecordsRequest(ctx, nameSpace, "search", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var response searchRecordsResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	docs := make([]schema.Document, 0, len(response.Result.Hits))
+	for _, hit := range response.Result.Hits {
+		score := s.score(hit.Score)
+		if scoreThreshold != 0 && score < scoreThreshold {
+			continue
+		}
+		pageContent, ok := hit.Fields[s.textKey].(string)
+		if !ok {
+			return nil, ErrMissingTextKey
+		}
+		delete(hit.Fields, s.textKey)
+		docs = append(docs, schema.Document{
+			PageContent: pageContent,
+			Metadata:    hit.Fields,
+			Score:       score,
+		})
+	}
+	return docs, nil
+}
+
+// doRecordsRequest performs a request to the records API of the index, returning
+// the body of a successful response.
+func (s Store) doRecordsRequest(
+	ctx context.Context,
+	nameSpace string,
+	action string,
+	contentType string,
+	payload io.Reader,
+) (io.ReadCloser, error) {
+	if nameSpace == "" {
+		nameSpace = _defaultNameSpace
+	}
+	baseURL := s.host
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	u, err := url.JoinPath(baseURL, "records", "namespaces", nameSpace, action)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Api-Key", s.apiKey)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Pinecone-API-Version", _recordsAPIVersion)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s records: %s %s", ErrRecordsRequest, action, resp.Status, msg)
+	}
+	return resp.Body, nil
+}
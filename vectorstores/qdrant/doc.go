@@ -1,3 +1,4 @@
 // Package qdrant contains an implementation of the VectorStore
-// interface using Qdrant.
+// interface using Qdrant, over its REST API or, given a connection with
+// WithGRPCConn, its gRPC API.
 package qdrant
@@ -0,0 +1,58 @@
+package qdrant
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _rangeOperators are the Qdrant range operators of comparison filters.
+var _rangeOperators = map[vectorstores.FilterOp]string{ //nolint:gochecknoglobals
+	vectorstores.FilterGt:  "gt",
+	vectorstores.FilterGte: "gte",
+	vectorstores.FilterLt:  "lt",
+	vectorstores.FilterLte: "lte",
+}
+
+// payloadFilter translates a filter to a Qdrant filter on the payload, see
+// https://qdrant.tech/documentation/concepts/filtering.
+func payloadFilter(f vectorstores.Filter) (map[string]any, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		conditions := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			condition, err := payloadFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		if f.Op == vectorstores.FilterAnd {
+			return map[string]any{"must": conditions}, nil
+		}
+		return map[string]any{"should": conditions}, nil
+	case vectorstores.FilterEq:
+		return map[string]any{"must": []any{matchCondition(f.Key, "value", f.Value)}}, nil
+	case vectorstores.FilterNe:
+		return map[string]any{"must_not": []any{matchCondition(f.Key, "value", f.Value)}}, nil
+	case vectorstores.FilterIn:
+		return map[string]any{"must": []any{matchCondition(f.Key, "any", f.Values)}}, nil
+	case vectorstores.FilterExists:
+		return map[string]any{"must_not": []any{map[string]any{"is_empty": map[string]any{"key": f.Key}}}}, nil
+	}
+
+	op, ok := _rangeOperators[f.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	condition := map[string]any{"key": f.Key, "range": map[string]any{op: f.Value}}
+	return map[string]any{"must": []any{condition}}, nil
+}
+
+func matchCondition(key, match string, value any) map[string]any {
+	return map[string]any{"key": key, "match": map[string]any{match: value}}
+}
@@ -0,0 +1,32 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestPayloadFilter(t *testing.T) {
+	t.Parallel()
+	filter, err := payloadFilter(vectorstores.And(
+		vectorstores.Eq("lang", "en"),
+		vectorstores.Or(vectorstores.Gte("year", 2020), vectorstores.In("tag", "a", "b")),
+		vectorstores.Ne("draft", true),
+		vectorstores.Exists("author"),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"must": []any{
+		map[string]any{"must": []any{map[string]any{"key": "lang", "match": map[string]any{"value": "en"}}}},
+		map[string]any{"should": []any{
+			map[string]any{"must": []any{map[string]any{"key": "year", "range": map[string]any{"gte": 2020}}}},
+			map[string]any{"must": []any{map[string]any{"key": "tag", "match": map[string]any{"any": []any{"a", "b"}}}}},
+		}},
+		map[string]any{"must_not": []any{map[string]any{"key": "draft", "match": map[string]any{"value": true}}}},
+		map[string]any{"must_not": []any{map[string]any{"is_empty": map[string]any{"key": "author"}}}},
+	}}, filter)
+
+	_, err = payloadFilter(vectorstores.Eq("", "en"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
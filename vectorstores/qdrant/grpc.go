@@ -0,0 +1,446 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	qdrantpb "github.com/qdrant/go-client/qdrant"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpcContext returns the context of the calls to the gRPC API, with the API
+// key of the store in the metadata.
+func (s Store) grpcContext(ctx context.Context) context.Context {
+	if s.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "api-key", s.apiKey)
+	}
+	return ctx
+}
+
+// grpcUpsertPoints updates or inserts points into the Qdrant collection.
+func (s Store) grpcUpsertPoints(
+	ctx context.Context,
+	ids []string,
+	vectors [][]float32,
+	sparseVectors []embeddings.SparseVector,
+	payloads []map[string]interface{},
+) error {
+	points := make([]*qdrantpb.PointStruct, len(ids))
+	for i, id := range ids {
+		payload, err := grpcPayload(payloads[i])
+		if err != nil {
+			return fmt.Errorf("upserting vectors: %w", err)
+		}
+		var sparse *embeddings.SparseVector
+		if sparseVectors != nil {
+			sparse = &sparseVectors[i]
+		}
+		points[i] = &qdrantpb.PointStruct{
+			Id:      grpcPointID(id),
+			Payload: payload,
+			Vectors: s.grpcVectors(vectors[i], sparse),
+		}
+	}
+
+	_, err := s.points.Upsert(s.grpcContext(ctx), &qdrantpb.UpsertPoints{
+		CollectionName: s.collectionName,
+		Wait:           proto.Bool(true),
+		Points:         points,
+	})
+	if err != nil {
+		return fmt.Errorf("upserting vectors: %w", err)
+	}
+	return nil
+}
+
+// grpcVectors returns the vectors of a point, the dense vector, unnamed
+// unless the store has a vector name, and the sparse vector if any.
+func (s Store) grpcVectors(vector []float32, sparse *embeddings.SparseVector) *qdrantpb.Vectors {
+	dense := &qdrantpb.Vector{Data: vector}
+	if s.vectorName == "" && sparse == nil {
+		return &qdrantpb.Vectors{VectorsOptions: &qdrantpb.Vectors_Vector{Vector: dense}}
+	}
+	named := map[string]*qdrantpb.Vector{s.vectorName: dense}
+	if sparse != nil {
+		named[s.sparseVectorName] = &qdrantpb.Vector{
+			Data:    sparse.Values(),
+			Indices: &qdrantpb.SparseIndices{Data: sparse.Indices()},
+		}
+	}
+	return &qdrantpb.Vectors{VectorsOptions: &qdrantpb.Vectors_Vectors{Vectors: &qdrantpb.NamedVectors{Vectors: named}}}
+}
+
+// grpcDeletePoints deletes the points with the ids or matched by the filter
+// of the payload from the Qdrant collection.
+func (s Store) grpcDeletePoints(ctx context.Context, payload deleteBody) error {
+	selector := &qdrantpb.PointsSelector{}
+	if payload.Points != nil {
+		ids := make([]*qdrantpb.PointId, len(payload.Points))
+		for i, id := range payload.Points {
+			ids[i] = grpcPointID(id)
+		}
+		selector.PointsSelectorOneOf = &qdrantpb.PointsSelector_Points{Points: &qdrantpb.PointsIdsList{Ids: ids}}
+	} else {
+		filter, err := grpcFilter(payload.Filter)
+		if err != nil {
+			return err
+		}
+		selector.PointsSelectorOneOf = &qdrantpb.PointsSelector_Filter{Filter: filter}
+	}
+
+	_, err := s.points.Delete(s.grpcContext(ctx), &qdrantpb.DeletePoints{
+		CollectionName: s.collectionName,
+		Wait:           proto.Bool(true),
+		Points:         selector,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting points: %w", err)
+	}
+	return nil
+}
+
+// grpcSearchPoints queries the Qdrant collection for points based on the
+// provided parameters.
+func (s Store) grpcSearchPoints(
+	ctx context.Context,
+	vector []float32,
+	numVectors int,
+	scoreThreshold float32,
+	filter any,
+) ([]schema.Document, error) {
+	request := &qdrantpb.SearchPoints{
+		CollectionName: s.collectionName,
+		Vector:         vector,
+		Limit:          uint64(numVectors),
+		WithPayload:    grpcWithPayload(true),
+	}
+	var err error
+	if filter != nil {
+		if request.Filter, err = grpcFilter(filter); err != nil {
+			return nil, err
+		}
+	}
+	if request.Params, err = s.grpcSearchParams(); err != nil {
+		return nil, err
+	}
+	if scoreThreshold != 0 {
+		request.ScoreThreshold = proto.Float32(s.scoreThreshold(scoreThreshold))
+	}
+	if s.vectorName != "" {
+		request.VectorName = proto.String(s.vectorName)
+	}
+
+	response, err := s.points.Search(s.grpcContext(ctx), request)
+	if err != nil {
+		return nil, fmt.Errorf("querying collection: %w", err)
+	}
+	docs, err := s.grpcDocuments(response.GetResult())
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].Score = s.score(docs[i].Score)
+	}
+	return docs, nil
+}
+
+// grpcQueryPoints queries the Qdrant collection with the universal query API.
+func (s Store) grpcQueryPoints(ctx context.Context, payload queryBody) ([]schema.Document, error) {
+	request := &qdrantpb.QueryPoints{
+		CollectionName: s.collectionName,
+		Limit:          proto.Uint64(uint64(payload.Limit)),
+		WithPayload:    grpcWithPayload(payload.WithPayload),
+	}
+	for _, p := range payload.Prefetch {
+		query, err := grpcQuery(p.Query)
+		if err != nil {
+			return nil, err
+		}
+		prefetch := &qdrantpb.PrefetchQuery{Query: query, Limit: proto.Uint64(uint64(p.Limit))}
+		if p.Using != "" {
+			prefetch.Using = proto.String(p.Using)
+		}
+		if p.Filter != nil {
+			if prefetch.Filter, err = grpcFilter(p.Filter); err != nil {
+				return nil, err
+			}
+		}
+		request.Prefetch = append(request.Prefetch, prefetch)
+	}
+
+	var err error
+	if request.Query, err = grpcQuery(payload.Query); err != nil {
+		return nil, err
+	}
+	if payload.Using != "" {
+		request.Using = proto.String(payload.Using)
+	}
+	if payload.Filter != nil {
+		if request.Filter, err = grpcFilter(payload.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if request.Params, err = s.grpcSearchParams(); err != nil {
+		return nil, err
+	}
+	if payload.ScoreThreshold != 0 {
+		request.ScoreThreshold = proto.Float32(payload.ScoreThreshold)
+	}
+
+	response, err := s.points.Query(s.grpcContext(ctx), request)
+	if err != nil {
+		return nil, fmt.Errorf("querying collection: %w", err)
+	}
+	return s.grpcDocuments(response.GetResult())
+}
+
+// grpcQuery converts the query of a queryBody, a dense vector, a sparse
+// vector or a fusion, to a Query.
+func grpcQuery(query any) (*qdrantpb.Query, error) {
+	switch query := query.(type) {
+	case []float32:
+		return grpcNearest(&qdrantpb.VectorInput{
+			Variant: &qdrantpb.VectorInput_Dense{Dense: &qdrantpb.DenseVector{Data: query}},
+		}), nil
+	case sparseVector:
+		return grpcNearest(&qdrantpb.VectorInput{
+			Variant: &qdrantpb.VectorInput_Sparse{Sparse: &qdrantpb.SparseVector{
+				Values:  query.Values,
+				Indices: query.Indices,
+			}},
+		}), nil
+	case fusionQuery:
+		fusion, ok := map[string]qdrantpb.Fusion{"rrf": qdrantpb.Fusion_RRF, "dbsf": qdrantpb.Fusion_DBSF}[query.Fusion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported fusion %q", query.Fusion)
+		}
+		return &qdrantpb.Query{Variant: &qdrantpb.Query_Fusion{Fusion: fusion}}, nil
+	}
+	return nil, fmt.Errorf("unsupported query %T", query)
+}
+
+func grpcNearest(input *qdrantpb.VectorInput) *qdrantpb.Query {
+	return &qdrantpb.Query{Variant: &qdrantpb.Query_Nearest{Nearest: input}}
+}
+
+func grpcWithPayload(enable bool) *qdrantpb.WithPayloadSelector {
+	return &qdrantpb.WithPayloadSelector{SelectorOptions: &qdrantpb.WithPayloadSelector_Enable{Enable: enable}}
+}
+
+// grpcSearchParams returns the search params of the store, or nil if it has
+// none.
+func (s Store) grpcSearchParams() (*qdrantpb.SearchParams, error) {
+	if s.searchParams == nil {
+		return nil, nil //nolint:nilnil
+	}
+	params := &qdrantpb.SearchParams{}
+	if err := decodeParams("search params", s.searchParams, params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// grpcDocuments converts the scored points of a search or query response to
+// documents.
+func (s Store) grpcDocuments(points []*qdrantpb.ScoredPoint) ([]schema.Document, error) {
+	results := make([]result, len(points))
+	for i, point := range points {
+		results[i] = result{Score: point.GetScore(), Payload: decodeValueMap(point.GetPayload())}
+	}
+	return s.documents(results)
+}
+
+// grpcVectorDimensions returns the size of the dense vectors of the
+// collection, or 0 if the collection doesn't exist.
+func (s Store) grpcVectorDimensions(ctx context.Context) (int, error) {
+	response, err := s.collections.Get(s.grpcContext(ctx), &qdrantpb.GetCollectionInfoRequest{
+		CollectionName: s.collectionName,
+	})
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getting collection: %w", err)
+	}
+
+	// the vectors config is either a single unnamed vector or a map of
+	// named vectors.
+	config := response.GetResult().GetConfig().GetParams().GetVectorsConfig()
+	if params := config.GetParams(); params != nil {
+		return int(params.GetSize()), nil
+	}
+	return int(config.GetParamsMap().GetMap()[s.vectorName].GetSize()), nil
+}
+
+// _fieldTypes maps payload schema types to the field types of Qdrant.
+var _fieldTypes = map[PayloadSchemaType]qdrantpb.FieldType{ //nolint:gochecknoglobals
+	PayloadSchemaKeyword:  qdrantpb.FieldType_FieldTypeKeyword,
+	PayloadSchemaInteger:  qdrantpb.FieldType_FieldTypeInteger,
+	PayloadSchemaFloat:    qdrantpb.FieldType_FieldTypeFloat,
+	PayloadSchemaGeo:      qdrantpb.FieldType_FieldTypeGeo,
+	PayloadSchemaText:     qdrantpb.FieldType_FieldTypeText,
+	PayloadSchemaBool:     qdrantpb.FieldType_FieldTypeBool,
+	PayloadSchemaDatetime: qdrantpb.FieldType_FieldTypeDatetime,
+	PayloadSchemaUUID:     qdrantpb.FieldType_FieldTypeUuid,
+}
+
+// grpcCreateCollection creates a collection with the vectors of the store.
+func (s Store) grpcCreateCollection(ctx context.Context, name string, opts vectorstores.CollectionOptions) error {
+	distance, ok := _distances[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	params := map[string]any{"size": opts.Dimensions, "distance": distance}
+	for key, value := range s.vectorParams {
+		params[key] = value
+	}
+	vectorParams := &qdrantpb.VectorParams{}
+	if err := decodeParams("vector params", params, vectorParams); err != nil {
+		return err
+	}
+
+	request := &qdrantpb.CreateCollection{CollectionName: name}
+	if s.vectorName != "" {
+		request.VectorsConfig = &qdrantpb.VectorsConfig{Config: &qdrantpb.VectorsConfig_ParamsMap{
+			ParamsMap: &qdrantpb.VectorParamsMap{Map: map[string]*qdrantpb.VectorParams{s.vectorName: vectorParams}},
+		}}
+	} else {
+		request.VectorsConfig = &qdrantpb.VectorsConfig{Config: &qdrantpb.VectorsConfig_Params{Params: vectorParams}}
+	}
+	if opts.IndexParams != nil {
+		request.HnswConfig = &qdrantpb.HnswConfigDiff{}
+		if err := decodeParams("index params", opts.IndexParams, request.HnswConfig); err != nil {
+			return err
+		}
+	}
+	if s.quantization != nil {
+		request.QuantizationConfig = &qdrantpb.QuantizationConfig{}
+		if err := decodeParams("quantization", s.quantization, request.QuantizationConfig); err != nil {
+			return err
+		}
+	}
+	if s.sparseEmbedder != nil {
+		request.SparseVectorsConfig = &qdrantpb.SparseVectorConfig{
+			Map: map[string]*qdrantpb.SparseVectorParams{s.sparseVectorName: {}},
+		}
+	}
+
+	if _, err := s.collections.Create(s.grpcContext(ctx), request); err != nil {
+		return fmt.Errorf("creating collection: %w", err)
+	}
+	return nil
+}
+
+// grpcCreatePayloadIndex indexes a payload field of a collection.
+func (s Store) grpcCreatePayloadIndex(
+	ctx context.Context,
+	collectionName string,
+	field string,
+	schemaType PayloadSchemaType,
+) error {
+	fieldType, ok := _fieldTypes[schemaType]
+	if !ok {
+		return fmt.Errorf("%w: unsupported payload schema type %q", ErrInvalidOptions, schemaType)
+	}
+	_, err := s.points.CreateFieldIndex(s.grpcContext(ctx), &qdrantpb.CreateFieldIndexCollection{
+		CollectionName: collectionName,
+		Wait:           proto.Bool(true),
+		FieldName:      field,
+		FieldType:      fieldType.Enum(),
+	})
+	if err != nil {
+		return fmt.Errorf("creating payload index: %w", err)
+	}
+	return nil
+}
+
+// grpcDropCollection deletes a collection.
+func (s Store) grpcDropCollection(ctx context.Context, name string) error {
+	_, err := s.collections.Delete(s.grpcContext(ctx), &qdrantpb.DeleteCollection{CollectionName: name})
+	if err != nil {
+		return fmt.Errorf("deleting collection: %w", err)
+	}
+	return nil
+}
+
+// grpcListCollections returns the names of the collections.
+func (s Store) grpcListCollections(ctx context.Context) ([]string, error) {
+	response, err := s.collections.List(s.grpcContext(ctx), &qdrantpb.ListCollectionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	names := make([]string, 0, len(response.GetCollections()))
+	for _, collection := range response.GetCollections() {
+		names = append(names, collection.GetName())
+	}
+	return names, nil
+}
+
+// normalize converts a value to its JSON representation, with numbers as
+// json.Number, as the REST API would receive it.
+func normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var normalized any
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// _enumValues maps the enum values of the REST params to the names of the
+// gRPC enums, by field, where they differ.
+var _enumValues = map[string]map[string]string{ //nolint:gochecknoglobals
+	"type":       {"int8": "Int8"},
+	"datatype":   {"float32": "Float32", "uint8": "Uint8", "float16": "Float16"},
+	"comparator": {"max_sim": "MaxSim"},
+}
+
+// decodeParams decodes REST params, e.g. the search params of the store,
+// into the message of the gRPC API with the same fields.
+func decodeParams(name string, params any, m proto.Message) error {
+	normalized, err := normalize(params)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(renameEnums(normalized))
+	if err != nil {
+		return err
+	}
+	if err := protojson.Unmarshal(b, m); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidOptions, name, err)
+	}
+	return nil
+}
+
+// renameEnums renames the enum values of REST params to the names of the
+// gRPC enums.
+func renameEnums(v any) any {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for key, value := range object {
+		if s, ok := value.(string); ok {
+			if renamed, ok := _enumValues[key][s]; ok {
+				object[key] = renamed
+			}
+			continue
+		}
+		object[key] = renameEnums(value)
+	}
+	return object
+}
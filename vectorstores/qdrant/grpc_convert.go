@@ -0,0 +1,439 @@
+package qdrant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	qdrantpb "github.com/qdrant/go-client/qdrant"
+	"github.com/tmc/langchaingo/vectorstores"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// This file converts payloads and filters, given in their REST
+// representation, to the messages of the Qdrant gRPC API, so that both
+// transports accept the same filters.
+
+// grpcPayload converts a payload to a map of values. Integers are converted
+// to integer values, other numbers to double values.
+func grpcPayload(payload map[string]any) (map[string]*qdrantpb.Value, error) {
+	normalized, err := normalize(payload)
+	if err != nil {
+		return nil, err
+	}
+	object, _ := normalized.(map[string]any)
+	return grpcValueMap(object)
+}
+
+func grpcValueMap(object map[string]any) (map[string]*qdrantpb.Value, error) {
+	values := make(map[string]*qdrantpb.Value, len(object))
+	for key, v := range object {
+		value, err := grpcValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// grpcValue converts a JSON value to a Value.
+func grpcValue(v any) (*qdrantpb.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_NullValue{}}, nil
+	case bool:
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_BoolValue{BoolValue: v}}, nil
+	case string:
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_StringValue{StringValue: v}}, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &qdrantpb.Value{Kind: &qdrantpb.Value_IntegerValue{IntegerValue: i}}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_DoubleValue{DoubleValue: f}}, nil
+	case map[string]any:
+		fields, err := grpcValueMap(v)
+		if err != nil {
+			return nil, err
+		}
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_StructValue{StructValue: &qdrantpb.Struct{Fields: fields}}}, nil
+	case []any:
+		values := make([]*qdrantpb.Value, len(v))
+		for i, item := range v {
+			value, err := grpcValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return &qdrantpb.Value{Kind: &qdrantpb.Value_ListValue{ListValue: &qdrantpb.ListValue{Values: values}}}, nil
+	}
+	return nil, fmt.Errorf("unsupported payload value %T", v)
+}
+
+// decodeValueMap converts a map of values to a JSON object.
+func decodeValueMap(values map[string]*qdrantpb.Value) map[string]any {
+	object := make(map[string]any, len(values))
+	for key, value := range values {
+		object[key] = decodeValue(value)
+	}
+	return object
+}
+
+// decodeValue converts a Value to a JSON value. Numbers are converted to
+// float64, as the REST API returns them.
+func decodeValue(value *qdrantpb.Value) any {
+	switch kind := value.GetKind().(type) {
+	case *qdrantpb.Value_DoubleValue:
+		return kind.DoubleValue
+	case *qdrantpb.Value_IntegerValue:
+		return float64(kind.IntegerValue)
+	case *qdrantpb.Value_StringValue:
+		return kind.StringValue
+	case *qdrantpb.Value_BoolValue:
+		return kind.BoolValue
+	case *qdrantpb.Value_StructValue:
+		return decodeValueMap(kind.StructValue.GetFields())
+	case *qdrantpb.Value_ListValue:
+		list := make([]any, len(kind.ListValue.GetValues()))
+		for i, item := range kind.ListValue.GetValues() {
+			list[i] = decodeValue(item)
+		}
+		return list
+	}
+	return nil
+}
+
+// grpcPointID converts a point id, an unsigned integer or a UUID, to a
+// PointId.
+func grpcPointID(id string) *qdrantpb.PointId {
+	if num, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return &qdrantpb.PointId{PointIdOptions: &qdrantpb.PointId_Num{Num: num}}
+	}
+	return &qdrantpb.PointId{PointIdOptions: &qdrantpb.PointId_Uuid{Uuid: id}}
+}
+
+// grpcFilter converts a filter of the payload, given in the Qdrant REST
+// syntax, to a Filter.
+func grpcFilter(filter any) (*qdrantpb.Filter, error) {
+	normalized, err := normalize(filter)
+	if err != nil {
+		return nil, err
+	}
+	return convertFilter(normalized)
+}
+
+func convertFilter(filter any) (*qdrantpb.Filter, error) {
+	object, ok := filter.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: filter must be an object, got %T", vectorstores.ErrInvalidFilter, filter)
+	}
+
+	f := &qdrantpb.Filter{}
+	for key, value := range object {
+		if value == nil {
+			continue
+		}
+		var err error
+		switch key {
+		case "should":
+			f.Should, err = convertConditions(value)
+		case "must":
+			f.Must, err = convertConditions(value)
+		case "must_not":
+			f.MustNot, err = convertConditions(value)
+		case "min_should":
+			f.MinShould, err = convertMinShould(value)
+		default:
+			return nil, fmt.Errorf("%w: unsupported filter clause %q", vectorstores.ErrInvalidFilter, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func convertMinShould(value any) (*qdrantpb.MinShould, error) {
+	minShould, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: min_should must be an object", vectorstores.ErrInvalidFilter)
+	}
+	conditions, err := convertConditions(minShould["conditions"])
+	if err != nil {
+		return nil, err
+	}
+	count, err := parseUint(minShould["min_count"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: min_count: %w", vectorstores.ErrInvalidFilter, err)
+	}
+	return &qdrantpb.MinShould{Conditions: conditions, MinCount: count}, nil
+}
+
+// convertConditions converts a condition or a list of conditions.
+func convertConditions(value any) ([]*qdrantpb.Condition, error) {
+	list, ok := value.([]any)
+	if !ok {
+		list = []any{value}
+	}
+	conditions := make([]*qdrantpb.Condition, 0, len(list))
+	for _, item := range list {
+		condition, err := convertCondition(item)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// convertCondition converts a condition, a field condition, a special
+// condition or a nested filter.
+func convertCondition(value any) (*qdrantpb.Condition, error) { //nolint:cyclop
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: condition must be an object, got %T", vectorstores.ErrInvalidFilter, value)
+	}
+
+	switch {
+	case object["key"] != nil:
+		field, err := convertFieldCondition(object)
+		if err != nil {
+			return nil, err
+		}
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_Field{Field: field}}, nil
+	case object["is_empty"] != nil:
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_IsEmpty{
+			IsEmpty: &qdrantpb.IsEmptyCondition{Key: conditionKey(object["is_empty"])},
+		}}, nil
+	case object["is_null"] != nil:
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_IsNull{
+			IsNull: &qdrantpb.IsNullCondition{Key: conditionKey(object["is_null"])},
+		}}, nil
+	case object["has_id"] != nil:
+		list, _ := object["has_id"].([]any)
+		ids := make([]*qdrantpb.PointId, len(list))
+		for i, id := range list {
+			ids[i] = grpcPointID(fmt.Sprint(id))
+		}
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_HasId{
+			HasId: &qdrantpb.HasIdCondition{HasId: ids},
+		}}, nil
+	case object["nested"] != nil:
+		nested, _ := object["nested"].(map[string]any)
+		filter, err := convertFilter(nested["filter"])
+		if err != nil {
+			return nil, err
+		}
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_Nested{
+			Nested: &qdrantpb.NestedCondition{Key: conditionKey(nested), Filter: filter},
+		}}, nil
+	}
+
+	filter, err := convertFilter(object)
+	if err != nil {
+		return nil, err
+	}
+	return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_Filter{Filter: filter}}, nil
+}
+
+func conditionKey(value any) string {
+	object, _ := value.(map[string]any)
+	key, _ := object["key"].(string)
+	return key
+}
+
+// convertFieldCondition converts a condition on a payload field.
+func convertFieldCondition(object map[string]any) (*qdrantpb.FieldCondition, error) { //nolint:cyclop
+	key, ok := object["key"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: condition key must be a string", vectorstores.ErrInvalidFilter)
+	}
+	field := &qdrantpb.FieldCondition{Key: key}
+	for name, value := range object {
+		var err error
+		switch name {
+		case "key":
+			continue
+		case "match":
+			field.Match, err = convertMatch(value)
+		case "range":
+			err = convertRange(field, value)
+		case "geo_bounding_box":
+			field.GeoBoundingBox = &qdrantpb.GeoBoundingBox{}
+			err = decodeCondition(value, field.GeoBoundingBox)
+		case "geo_radius":
+			field.GeoRadius = &qdrantpb.GeoRadius{}
+			err = decodeCondition(value, field.GeoRadius)
+		case "geo_polygon":
+			field.GeoPolygon = &qdrantpb.GeoPolygon{}
+			err = decodeCondition(value, field.GeoPolygon)
+		case "values_count":
+			field.ValuesCount = &qdrantpb.ValuesCount{}
+			err = decodeCondition(value, field.ValuesCount)
+		default:
+			return nil, fmt.Errorf("%w: unsupported condition %q", vectorstores.ErrInvalidFilter, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", vectorstores.ErrInvalidFilter, key, err)
+		}
+	}
+	return field, nil
+}
+
+// decodeCondition decodes a condition whose REST representation has the
+// fields of its message, e.g. geo conditions.
+func decodeCondition(value any, m proto.Message) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(b, m)
+}
+
+// convertRange converts a range, or a datetime range if its bounds are
+// strings, of a field condition.
+func convertRange(field *qdrantpb.FieldCondition, value any) error {
+	bounds, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("range must be an object, got %T", value)
+	}
+	numeric, datetime := &qdrantpb.Range{}, &qdrantpb.DatetimeRange{}
+	numericBounds := map[string]**float64{"lt": &numeric.Lt, "gt": &numeric.Gt, "gte": &numeric.Gte, "lte": &numeric.Lte}
+	datetimeBounds := map[string]**timestamppb.Timestamp{
+		"lt": &datetime.Lt, "gt": &datetime.Gt, "gte": &datetime.Gte, "lte": &datetime.Lte,
+	}
+	for name, bound := range bounds {
+		if _, ok := numericBounds[name]; !ok {
+			return fmt.Errorf("unsupported range bound %q", name)
+		}
+		switch bound := bound.(type) {
+		case json.Number:
+			f, err := bound.Float64()
+			if err != nil {
+				return err
+			}
+			*numericBounds[name] = proto.Float64(f)
+			field.Range = numeric
+		case string:
+			t, err := parseDatetime(bound)
+			if err != nil {
+				return err
+			}
+			*datetimeBounds[name] = timestamppb.New(t)
+			field.DatetimeRange = datetime
+		default:
+			return fmt.Errorf("unsupported range bound %v", bound)
+		}
+	}
+	if field.Range != nil && field.DatetimeRange != nil {
+		return errors.New("range must not mix numbers and datetimes")
+	}
+	return nil
+}
+
+// parseDatetime parses a datetime in one of the formats accepted by Qdrant.
+func parseDatetime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999", time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid datetime %q", s)
+}
+
+// convertMatch converts a match condition.
+func convertMatch(value any) (*qdrantpb.Match, error) { //nolint:cyclop
+	match, ok := value.(map[string]any)
+	if !ok || len(match) != 1 {
+		return nil, fmt.Errorf("match must be an object with a single key, got %v", value)
+	}
+	var name string
+	for name = range match {
+	}
+	v := match[name]
+
+	switch name {
+	case "value":
+		switch v := v.(type) {
+		case string:
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Keyword{Keyword: v}}, nil
+		case bool:
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Boolean{Boolean: v}}, nil
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Integer{Integer: i}}, nil
+			}
+		}
+		return nil, fmt.Errorf("match value must be a keyword, an integer or a boolean, got %v", v)
+	case "any", "except":
+		keywords, integers, err := matchList(v)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case name == "any" && integers != nil:
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Integers{Integers: integers}}, nil
+		case name == "any":
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Keywords{Keywords: keywords}}, nil
+		case integers != nil:
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_ExceptIntegers{ExceptIntegers: integers}}, nil
+		default:
+			return &qdrantpb.Match{MatchValue: &qdrantpb.Match_ExceptKeywords{ExceptKeywords: keywords}}, nil
+		}
+	case "text":
+		text, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("match text must be a string, got %v", v)
+		}
+		return &qdrantpb.Match{MatchValue: &qdrantpb.Match_Text{Text: text}}, nil
+	}
+	return nil, fmt.Errorf("unsupported match %q", name)
+}
+
+// matchList converts a list of keywords or integers of a match condition.
+// The integers are nil for lists of keywords.
+func matchList(value any) (*qdrantpb.RepeatedStrings, *qdrantpb.RepeatedIntegers, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("match list must be an array, got %v", value)
+	}
+	keywords, integers := &qdrantpb.RepeatedStrings{}, &qdrantpb.RepeatedIntegers{}
+	for _, item := range list {
+		switch item := item.(type) {
+		case string:
+			keywords.Strings = append(keywords.Strings, item)
+		case json.Number:
+			i, err := item.Int64()
+			if err != nil {
+				return nil, nil, fmt.Errorf("match list must contain keywords or integers, got %v", item)
+			}
+			integers.Integers = append(integers.Integers, i)
+		default:
+			return nil, nil, fmt.Errorf("match list must contain keywords or integers, got %v", item)
+		}
+	}
+	if len(keywords.Strings) > 0 && len(integers.Integers) > 0 {
+		return nil, nil, errors.New("match list must not mix keywords and integers")
+	}
+	if len(integers.Integers) == 0 {
+		integers = nil
+	}
+	return keywords, integers, nil
+}
+
+func parseUint(value any) (uint64, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("expected an unsigned integer, got %v", value)
+	}
+	return strconv.ParseUint(string(n), 10, 64)
+}
@@ -0,0 +1,196 @@
+package qdrant
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	qdrantpb "github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcqdrant "github.com/testcontainers/testcontainers-go/modules/qdrant"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGRPCConn returns a connection to the gRPC API of the Qdrant instance at
+// QDRANT_GRPC_ENDPOINT, or of a Qdrant container.
+func newGRPCConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	endpoint := os.Getenv("QDRANT_GRPC_ENDPOINT")
+	if endpoint == "" {
+		ctx := context.Background()
+		container, err := tcqdrant.RunContainer(ctx, testcontainers.WithImage("qdrant/qdrant:v1.11.0"))
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, container.Terminate(context.Background()))
+		})
+		endpoint, err = container.GRPCEndpoint(ctx)
+		require.NoError(t, err)
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// newVectorsEmbedder returns an embedder mapping the texts of vectors to
+// their vector.
+func newVectorsEmbedder(t *testing.T, vectors map[string][]float32) embeddings.Embedder {
+	t.Helper()
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			result := make([][]float32, len(texts))
+			for i, text := range texts {
+				result[i] = vectors[text]
+			}
+			return result, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+func TestGRPCStore(t *testing.T) {
+	t.Parallel()
+	conn := newGRPCConn(t)
+
+	store, err := New(
+		WithGRPCConn(conn),
+		WithCollectionName("docs"),
+		WithEmbedder(newVectorsEmbedder(t, map[string][]float32{
+			"tokyo": {1, 0}, "kyoto": {0.8, 0.6}, "paris": {0, 1},
+		})),
+		WithVectorName("dense"),
+		WithSparseEmbedder(embeddings.NewBM25(), "text"),
+		WithPayloadIndexes(map[string]PayloadSchemaType{"year": PayloadSchemaInteger}),
+		WithQuantization(map[string]any{"scalar": map[string]any{"type": "int8", "always_ram": true}}),
+		WithSearchParams(map[string]any{"hnsw_ef": 128, "exact": true}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(2),
+		vectorstores.WithIndexParams(map[string]any{"m": 32})))
+	names, err := store.ListCollections(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docs"}, names)
+
+	ids := []string{
+		"5c56c793-69f3-4fbf-87e6-c4bf54c28c26",
+		"42",
+		"0b6c3a8e-3d4e-4d4b-8a53-8f8b0f2c1e7a",
+	}
+	require.NoError(t, store.UpsertDocuments(ctx, ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"year": 2020, "tags": []string{"capital"}}},
+		{PageContent: "kyoto", Metadata: map[string]any{"year": 1994, "rating": 4.5}},
+		{PageContent: "paris", Metadata: map[string]any{"year": 2024}},
+	}))
+
+	docs, err := store.SimilaritySearch(ctx, "tokyo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{}),
+		vectorstores.WithFilters(vectorstores.Lt("year", 2022)))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "tokyo", docs[0].PageContent)
+	require.Equal(t, map[string]any{"year": float64(2020), "tags": []any{"capital"}}, docs[0].Metadata)
+	require.Equal(t, "kyoto", docs[1].PageContent)
+	require.Equal(t, map[string]any{"year": float64(1994), "rating": 4.5}, docs[1].Metadata)
+
+	dense, err := New(WithGRPCConn(conn), WithCollectionName("docs"), WithVectorName("dense"),
+		WithEmbedder(newVectorsEmbedder(t, map[string][]float32{"tokyo": {1, 0}, "city": {1, 2, 3}})))
+	require.NoError(t, err)
+	docs, err = dense.SimilaritySearch(ctx, "tokyo", 3, vectorstores.WithScoreThreshold(0.85),
+		vectorstores.WithFilters(map[string]any{"must_not": map[string]any{"has_id": []any{42}}}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].PageContent)
+	require.InDelta(t, 1, docs[0].Score, 1e-4)
+	_, err = dense.AddDocuments(ctx, []schema.Document{{PageContent: "city"}})
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+
+	require.NoError(t, store.DeleteByIDs(ctx, ids[:1]))
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Gte("year", 2024)))
+	docs, err = dense.SimilaritySearch(ctx, "tokyo", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, store.DropCollection(ctx, "docs"))
+	names, err = store.ListCollections(ctx)
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
+
+func TestGRPCFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := payloadFilter(vectorstores.And(
+		vectorstores.Eq("lang", "en"),
+		vectorstores.In("year", 2020, 2021),
+		vectorstores.Exists("author"),
+	))
+	require.NoError(t, err)
+	got, err := grpcFilter(filter)
+	require.NoError(t, err)
+	field := func(field *qdrantpb.FieldCondition) *qdrantpb.Condition {
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_Field{Field: field}}
+	}
+	nested := func(f *qdrantpb.Filter) *qdrantpb.Condition {
+		return &qdrantpb.Condition{ConditionOneOf: &qdrantpb.Condition_Filter{Filter: f}}
+	}
+	want := &qdrantpb.Filter{Must: []*qdrantpb.Condition{
+		nested(&qdrantpb.Filter{Must: []*qdrantpb.Condition{field(&qdrantpb.FieldCondition{
+			Key: "lang", Match: &qdrantpb.Match{MatchValue: &qdrantpb.Match_Keyword{Keyword: "en"}},
+		})}}),
+		nested(&qdrantpb.Filter{Must: []*qdrantpb.Condition{field(&qdrantpb.FieldCondition{
+			Key: "year", Match: &qdrantpb.Match{MatchValue: &qdrantpb.Match_Integers{
+				Integers: &qdrantpb.RepeatedIntegers{Integers: []int64{2020, 2021}},
+			}},
+		})}}),
+		nested(&qdrantpb.Filter{MustNot: []*qdrantpb.Condition{{ConditionOneOf: &qdrantpb.Condition_IsEmpty{
+			IsEmpty: &qdrantpb.IsEmptyCondition{Key: "author"},
+		}}}}),
+	}}
+	require.True(t, proto.Equal(want, got), "got %v", got)
+
+	got, err = grpcFilter(map[string]any{"must": map[string]any{
+		"key":   "published",
+		"range": map[string]any{"gte": "2024-01-02T03:04:05Z"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1704164645), got.GetMust()[0].GetField().GetDatetimeRange().GetGte().GetSeconds())
+
+	_, err = grpcFilter(map[string]any{"must": map[string]any{"unknown": 1}})
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
+
+func TestDecodeParams(t *testing.T) {
+	t.Parallel()
+
+	params := &qdrantpb.VectorParams{}
+	require.NoError(t, decodeParams("vector params", map[string]any{
+		"size": 2, "distance": "Dot", "datatype": "float16", "on_disk": true,
+	}, params))
+	require.True(t, proto.Equal(&qdrantpb.VectorParams{
+		Size: 2, Distance: qdrantpb.Distance_Dot, Datatype: qdrantpb.Datatype_Float16.Enum(), OnDisk: proto.Bool(true),
+	}, params), "got %v", params)
+
+	quantization := &qdrantpb.QuantizationConfig{}
+	require.NoError(t, decodeParams("quantization", map[string]any{
+		"scalar": map[string]any{"type": "int8", "quantile": 0.99},
+	}, quantization))
+	require.Equal(t, qdrantpb.QuantizationType_Int8, quantization.GetScalar().GetType())
+
+	require.ErrorIs(t, decodeParams("search params", map[string]any{"unknown": 1}, &qdrantpb.SearchParams{}),
+		ErrInvalidOptions)
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/url"
 
+	qdrantpb "github.com/qdrant/go-client/qdrant"
 	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -26,13 +29,26 @@ func WithCollectionName(name string) Option {
 }
 
 // WithURL returns an Option for setting the Qdrant instance URL.
-// Example: 'http://localhost:63333'. Required.
+// Example: 'http://localhost:63333'. Required unless WithGRPCConn is given.
 func WithURL(qdrantURL url.URL) Option {
 	return func(p *Store) {
 		p.qdrantURL = qdrantURL
 	}
 }
 
+// WithGRPCConn returns an Option for setting a connection to the gRPC API of
+// the Qdrant instance, e.g. created with grpc.NewClient("localhost:6334", ...),
+// used instead of the REST API through the clients of github.com/qdrant/go-client.
+// The API key, if any, is sent in the metadata of the calls. Either the URL or
+// the connection is required.
+func WithGRPCConn(conn grpc.ClientConnInterface) Option {
+	return func(p *Store) {
+		p.conn = conn
+		p.points = qdrantpb.NewPointsClient(conn)
+		p.collections = qdrantpb.NewCollectionsClient(conn)
+	}
+}
+
 // WithEmbedder returns an Option for setting the embedder to be used when
 // adding documents or doing similarity search. Required.
 func WithEmbedder(embedder embeddings.Embedder) Option {
@@ -56,9 +72,78 @@ func WithContentKey(contentKey string) Option {
 	}
 }
 
+// WithVectorName returns an Option for setting the name of the dense vector
+// in collections with named vectors. Optional.
+func WithVectorName(name string) Option {
+	return func(p *Store) {
+		p.vectorName = name
+	}
+}
+
+// WithSparseEmbedder returns an Option for setting a sparse embedder, e.g.
+// embeddings.NewBM25(), enabling hybrid search. Its vectors are stored in
+// the sparse vector named sparseVectorName of the collection, and searches
+// fuse the dense and sparse results with Reciprocal Rank Fusion, ignoring
+// the score threshold unless vectorstores.WithHybridSearch is given to
+// configure the fusion. Requires Qdrant 1.10 or later. Optional.
+func WithSparseEmbedder(embedder embeddings.SparseEmbedder, sparseVectorName string) Option {
+	return func(p *Store) {
+		p.sparseEmbedder = embedder
+		p.sparseVectorName = sparseVectorName
+	}
+}
+
+// WithVectorParams returns an Option for setting extra parameters of the
+// dense vector of collections created with CreateCollection, e.g.
+// {"on_disk": true, "datatype": "float16"}. Optional.
+func WithVectorParams(params map[string]any) Option {
+	return func(p *Store) {
+		p.vectorParams = params
+	}
+}
+
+// WithQuantization returns an Option for setting the quantization config of
+// collections created with CreateCollection, e.g.
+// {"scalar": {"type": "int8", "always_ram": true}}. Optional.
+func WithQuantization(config map[string]any) Option {
+	return func(p *Store) {
+		p.quantization = config
+	}
+}
+
+// WithPayloadIndexes returns an Option for setting the payload fields
+// indexed in collections created with CreateCollection, to filter them
+// efficiently. Metadata fields are indexed by their key. Optional.
+func WithPayloadIndexes(indexes map[string]PayloadSchemaType) Option {
+	return func(p *Store) {
+		p.payloadIndexes = indexes
+	}
+}
+
+// WithSearchParams returns an Option for setting the search params of
+// similarity searches, e.g. {"hnsw_ef": 128} or
+// {"quantization": {"rescore": true, "oversampling": 2}}. Optional.
+func WithSearchParams(params map[string]any) Option {
+	return func(p *Store) {
+		p.searchParams = params
+	}
+}
+
+// WithDistanceMetric returns an Option for setting the distance metric of
+// the vectors of the collection, converting the scores of the points found
+// and the score thresholds to relevance scores. Defaults to
+// vectorstores.DistanceCosine.
+func WithDistanceMetric(metric vectorstores.DistanceMetric) Option {
+	return func(p *Store) {
+		p.metric = metric
+	}
+}
+
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		contentKey: defaultContentKey,
+		metric:     vectorstores.DistanceCosine,
+		dimensions: &dimensionsCache{},
 	}
 
 	for _, opt := range opts {
@@ -69,7 +154,7 @@ func applyClientOptions(opts ...Option) (Store, error) {
 		return Store{}, fmt.Errorf("%w: missing collection name", ErrInvalidOptions)
 	}
 
-	if o.qdrantURL == (url.URL{}) {
+	if o.qdrantURL == (url.URL{}) && o.conn == nil {
 		return Store{}, fmt.Errorf("%w: missing Qdrant URL", ErrInvalidOptions)
 	}
 
@@ -77,5 +162,13 @@ func applyClientOptions(opts ...Option) (Store, error) {
 		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
 	}
 
+	if _, ok := _distances[o.metric]; !ok {
+		return Store{}, fmt.Errorf("%w: %w: %s", ErrInvalidOptions, vectorstores.ErrUnsupportedDistanceMetric, o.metric)
+	}
+
+	if o.sparseEmbedder != nil && o.sparseVectorName == "" {
+		return Store{}, fmt.Errorf("%w: missing sparse vector name", ErrInvalidOptions)
+	}
+
 	return *o, nil
 }
@@ -3,22 +3,71 @@ package qdrant
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
+	"sync"
 
+	"github.com/google/uuid"
+	qdrantpb "github.com/qdrant/go-client/qdrant"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"google.golang.org/grpc"
 )
 
 type Store struct {
-	embedder       embeddings.Embedder
-	collectionName string
-	qdrantURL      url.URL
-	apiKey         string
-	contentKey     string
+	embedder         embeddings.Embedder
+	sparseEmbedder   embeddings.SparseEmbedder
+	collectionName   string
+	qdrantURL        url.URL
+	conn             grpc.ClientConnInterface
+	points           qdrantpb.PointsClient
+	collections      qdrantpb.CollectionsClient
+	apiKey           string
+	contentKey       string
+	vectorName       string
+	sparseVectorName string
+	vectorParams     map[string]any
+	quantization     map[string]any
+	payloadIndexes   map[string]PayloadSchemaType
+	searchParams     map[string]any
+	metric           vectorstores.DistanceMetric
+	// dimensions caches the vector size of the collection, shared by the
+	// copies of the store.
+	dimensions *dimensionsCache
 }
 
-var _ vectorstores.VectorStore = Store{}
+// dimensionsCache holds the vector size of the collection of a store once it
+// is known.
+type dimensionsCache struct {
+	mu         sync.Mutex
+	dimensions int
+}
+
+// PayloadSchemaType is the type of an indexed payload field.
+type PayloadSchemaType string
+
+const (
+	PayloadSchemaKeyword  PayloadSchemaType = "keyword"
+	PayloadSchemaInteger  PayloadSchemaType = "integer"
+	PayloadSchemaFloat    PayloadSchemaType = "float"
+	PayloadSchemaBool     PayloadSchemaType = "bool"
+	PayloadSchemaGeo      PayloadSchemaType = "geo"
+	PayloadSchemaDatetime PayloadSchemaType = "datetime"
+	PayloadSchemaText     PayloadSchemaType = "text"
+	PayloadSchemaUUID     PayloadSchemaType = "uuid"
+)
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+
+	_ vectorstores.CollectionManager = Store{}
+)
+
+// _defaultBatchSize is the number of points upserted at a time.
+const _defaultBatchSize = 256
 
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -28,10 +77,125 @@ func New(opts ...Option) (Store, error) {
 	return s, nil
 }
 
+// AddDocuments adds documents in batches of 256 points by default, and
+// returns their ids.
 func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
-	_ ...vectorstores.Option,
+	options ...vectorstores.Option,
 ) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.UpsertDocuments(ctx, ids, docs, options...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids, which must be UUIDs or
+// unsigned integers, replacing the points with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.addDocuments(ctx, ids[start:end], docs[start:end])
+	})
+}
+
+// DeleteByIDs deletes the points with the ids.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if s.conn != nil {
+		return s.grpcDeletePoints(ctx, deleteBody{Points: ids})
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Points: ids})
+}
+
+// DeleteByFilter deletes the points whose payload is matched by the filter,
+// given in the Qdrant filter syntax or as a vectorstores.Filter.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	filters, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if s.conn != nil {
+		return s.grpcDeletePoints(ctx, deleteBody{Filter: filters})
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Filter: filters})
+}
+
+// CreateCollection creates a collection with the named or unnamed vectors of
+// the store, and a sparse vector if the store has a sparse embedder, then
+// creates the payload indexes of the store. The index params are the HNSW
+// config of the collection.
+func (s Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	s.resetDimensions(name)
+	if s.conn != nil {
+		err = s.grpcCreateCollection(ctx, name, opts)
+	} else {
+		err = s.createCollection(ctx, &s.qdrantURL, name, opts)
+	}
+	if err != nil {
+		return err
+	}
+	for field, schemaType := range s.payloadIndexes {
+		if err := s.createIndex(ctx, name, field, schemaType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePayloadIndex indexes a payload field of the collection of the store,
+// e.g. a metadata key, to filter it efficiently.
+func (s Store) CreatePayloadIndex(ctx context.Context, field string, schemaType PayloadSchemaType) error {
+	return s.createIndex(ctx, s.collectionName, field, schemaType)
+}
+
+// createIndex indexes a payload field of a collection, over gRPC if the store
+// has a connection.
+func (s Store) createIndex(ctx context.Context, collectionName, field string, schemaType PayloadSchemaType) error {
+	if s.conn != nil {
+		return s.grpcCreatePayloadIndex(ctx, collectionName, field, schemaType)
+	}
+	return s.createPayloadIndex(ctx, &s.qdrantURL, collectionName, field, schemaType)
+}
+
+// DropCollection deletes a collection and its points.
+func (s Store) DropCollection(ctx context.Context, name string) error {
+	s.resetDimensions(name)
+	if s.conn != nil {
+		return s.grpcDropCollection(ctx, name)
+	}
+	return s.dropCollection(ctx, &s.qdrantURL, name)
+}
+
+// ListCollections returns the names of the collections.
+func (s Store) ListCollections(ctx context.Context) ([]string, error) {
+	if s.conn != nil {
+		return s.grpcListCollections(ctx)
+	}
+	return s.listCollections(ctx, &s.qdrantURL)
+}
+
+// addDocuments embeds the documents and upserts them as points with the ids.
+func (s Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document) error {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -40,11 +204,29 @@ func (s Store) AddDocuments(ctx context.Context,
 	vectors,
 		err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, errors.New("number of vectors from embedder does not match number of documents")
+		return errors.New("number of vectors from embedder does not match number of documents")
+	}
+	dimensions, err := s.collectionDimensions(ctx)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+		return err
+	}
+
+	var sparseVectors []embeddings.SparseVector
+	if s.sparseEmbedder != nil {
+		sparseVectors, err = s.sparseEmbedder.EmbedDocumentsSparse(ctx, texts)
+		if err != nil {
+			return err
+		}
+		if len(sparseVectors) != len(docs) {
+			return errors.New("number of sparse vectors from embedder does not match number of documents")
+		}
 	}
 
 	metadatas := make([]map[string]interface{}, 0, len(docs))
@@ -58,7 +240,48 @@ func (s Store) AddDocuments(ctx context.Context,
 		metadatas = append(metadatas, metadata)
 	}
 
-	return s.upsertPoints(ctx, &s.qdrantURL, vectors, metadatas)
+	if s.conn != nil {
+		return s.grpcUpsertPoints(ctx, ids, vectors, sparseVectors, metadatas)
+	}
+	return s.upsertPoints(ctx, &s.qdrantURL, ids, vectors, sparseVectors, metadatas)
+}
+
+// collectionDimensions returns the size of the dense vectors of the
+// collection of the store, or 0 if it isn't known yet. It is fetched once and
+// cached.
+func (s Store) collectionDimensions(ctx context.Context) (int, error) {
+	if s.dimensions == nil {
+		return s.fetchDimensions(ctx)
+	}
+	s.dimensions.mu.Lock()
+	defer s.dimensions.mu.Unlock()
+	if s.dimensions.dimensions > 0 {
+		return s.dimensions.dimensions, nil
+	}
+	dimensions, err := s.fetchDimensions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.dimensions.dimensions = dimensions
+	return dimensions, nil
+}
+
+// resetDimensions forgets the cached vector size if the collection of the
+// store is created or dropped.
+func (s Store) resetDimensions(name string) {
+	if s.dimensions == nil || name != s.collectionName {
+		return
+	}
+	s.dimensions.mu.Lock()
+	s.dimensions.dimensions = 0
+	s.dimensions.mu.Unlock()
+}
+
+func (s Store) fetchDimensions(ctx context.Context) (int, error) {
+	if s.conn != nil {
+		return s.grpcVectorDimensions(ctx)
+	}
+	return s.vectorDimensions(ctx, &s.qdrantURL)
 }
 
 func (s Store) SimilaritySearch(ctx context.Context,
@@ -67,7 +290,10 @@ func (s Store) SimilaritySearch(ctx context.Context,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
 
-	filters := s.getFilters(opts)
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	scoreThreshold,
 		err := s.getScoreThreshold(opts)
@@ -75,15 +301,69 @@ func (s Store) SimilaritySearch(ctx context.Context,
 		return nil, err
 	}
 
+	if opts.HybridSearch != nil {
+		return s.hybridSearch(ctx, query, *opts.HybridSearch, numDocuments, scoreThreshold, filters)
+	}
+
 	vector,
 		err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.sparseEmbedder != nil {
+		sparse, err := s.sparseEmbedder.EmbedQuerySparse(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return s.hybridSearchPoints(ctx, vector, sparse, vectorstores.HybridSearch{}, numDocuments, 0, filters)
+	}
+
+	if s.conn != nil {
+		return s.grpcSearchPoints(ctx, vector, numDocuments, scoreThreshold, filters)
+	}
 	return s.searchPoints(ctx, &s.qdrantURL, vector, numDocuments, scoreThreshold, filters)
 }
 
+// hybridSearch searches both the dense and the sparse vectors of the
+// collection, embedding the query unless the vectors are given.
+func (s Store) hybridSearch(ctx context.Context,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	numDocuments int,
+	scoreThreshold float32,
+	filters any,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+	if s.sparseVectorName == "" {
+		return nil, fmt.Errorf("%w: missing sparse vector name", vectorstores.ErrInvalidHybridSearch)
+	}
+
+	vector := hybrid.Vector
+	if vector == nil {
+		var err error
+		vector, err = s.embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sparse := hybrid.Sparse
+	if sparse == nil {
+		if s.sparseEmbedder == nil {
+			return nil, fmt.Errorf("%w: missing sparse vector", vectorstores.ErrInvalidHybridSearch)
+		}
+		var err error
+		sparse, err = s.sparseEmbedder.EmbedQuerySparse(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.hybridSearchPoints(ctx, vector, sparse, hybrid, numDocuments, scoreThreshold, filters)
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, errors.New("score threshold must be between 0 and 1")
@@ -91,12 +371,11 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-func (s Store) getFilters(opts vectorstores.Options) any {
-	if opts.Filters != nil {
-		return opts.Filters
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if filter, ok := opts.Filters.(vectorstores.Filter); ok {
+		return payloadFilter(filter)
 	}
-
-	return nil
+	return opts.Filters, nil
 }
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
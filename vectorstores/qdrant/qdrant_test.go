@@ -2,7 +2,9 @@ package qdrant_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strconv"
@@ -423,3 +425,358 @@ func setupCollection(t *testing.T, qdrantURL, apiKey string, dimension int, dist
 	})
 	return collectionName
 }
+
+func TestQdrantHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"result":{"config":{"params":{"vectors":{"dense":{"size":2}}}}}}`))
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		body["path"] = r.URL.Path
+		requests = append(requests, body)
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			_, _ = w.Write([]byte(`{"result":{"points":[{"score":0.5,"payload":{"content":"tokyo"}}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{}}`))
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i := range texts {
+				vectors[i] = []float32{1, 0}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+		qdrant.WithVectorName("dense"),
+		qdrant.WithSparseEmbedder(embeddings.NewBM25(), "text"),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "tokyo"}})
+	require.NoError(t, err)
+	docs, err := store.SimilaritySearch(context.Background(), "tokyo", 1)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{{PageContent: "tokyo", Metadata: map[string]any{}, Score: 0.5}}, docs)
+
+	require.Len(t, requests, 2)
+	points, ok := requests[0]["points"].([]any)
+	require.True(t, ok)
+	vector, ok := points[0].(map[string]any)["vector"].(map[string]any)
+	require.True(t, ok)
+	require.Contains(t, vector, "dense")
+	require.Contains(t, vector, "text")
+
+	require.Equal(t, "/collections/test/points/query", requests[1]["path"])
+	require.Len(t, requests[1]["prefetch"], 2)
+	require.Equal(t, map[string]any{"fusion": "rrf"}, requests[1]["query"])
+}
+
+func TestQdrantWeightedHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		if body["using"] == "text" {
+			_, _ = w.Write([]byte(`{"result":{"points":[{"score":7,"payload":{"content":"kyoto"}},{"score":1,"payload":{"content":"tokyo"}}]}}`)) //nolint:lll
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":{"points":[{"score":0.9,"payload":{"content":"tokyo"}},{"score":0.3,"payload":{"content":"osaka"}}]}}`)) //nolint:lll
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+		qdrant.WithSparseEmbedder(embeddings.NewBM25(), "text"),
+	)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "tokyo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{
+			Fusion: vectorstores.FusionWeighted,
+			Alpha:  0.7,
+		}),
+		vectorstores.WithScoreThreshold(0.5),
+	)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].PageContent)
+	require.InDelta(t, 0.7, docs[0].Score, 1e-6)
+
+	require.Len(t, requests, 2)
+	require.NotContains(t, requests[0], "using")
+	require.NotContains(t, requests[0], "prefetch")
+
+	_, err = store.SimilaritySearch(context.Background(), "tokyo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{Fusion: "max"}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+}
+
+func TestQdrantEuclideanScores(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"result":[{"score":0.6,"payload":{"content":"tokyo"}}]}`))
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+		qdrant.WithDistanceMetric(vectorstores.DistanceEuclidean),
+	)
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "tokyo", 1, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.InDelta(t, 0.82, docs[0].Score, 1e-6)
+	require.InDelta(t, 1, body["score_threshold"], 1e-6)
+
+	_, err = qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+		qdrant.WithDistanceMetric("manhattan"),
+	)
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedDistanceMetric)
+}
+
+func TestQdrantUpsertAndDelete(t *testing.T) {
+	t.Parallel()
+
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		body["path"] = r.URL.Path
+		requests = append(requests, body)
+		_, _ = w.Write([]byte(`{"result":{}}`))
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	id := "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"
+	require.NoError(t, store.UpsertDocuments(ctx, []string{id}, []schema.Document{{PageContent: "tokyo"}}))
+	require.ErrorIs(t, store.UpsertDocuments(ctx, nil, []schema.Document{{PageContent: "tokyo"}}), vectorstores.ErrIDsMismatch)
+	require.NoError(t, store.DeleteByIDs(ctx, []string{id}))
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Eq("city", "tokyo")))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+
+	require.Len(t, requests, 3)
+	require.Equal(t, []any{id}, requests[0]["batch"].(map[string]any)["ids"])
+	require.Equal(t, "/collections/test/points/delete", requests[1]["path"])
+	require.Equal(t, []any{id}, requests[1]["points"])
+	require.Equal(t, map[string]any{
+		"must": []any{map[string]any{"key": "city", "match": map[string]any{"value": "tokyo"}}},
+	}, requests[2]["filter"])
+}
+
+func TestQdrantDimensionMismatch(t *testing.T) {
+	t.Parallel()
+
+	infos, upserts := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			infos++
+			_, _ = w.Write([]byte(`{"result":{"config":{"params":{"vectors":{"size":3,"distance":"Cosine"}}}}}`))
+			return
+		}
+		upserts++
+		_, _ = w.Write([]byte(`{"result":{}}`))
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "tokyo"}})
+		require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+	}
+	require.Zero(t, upserts)
+	// the collection info is fetched once.
+	require.Equal(t, 1, infos)
+}
+
+func TestQdrantCollections(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"result":true}`))
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"result":{"collections":[{"name":"a"},{"name":"b"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"result":true}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(context.Context, []string) ([][]float32, error) {
+			return nil, nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("test"),
+		qdrant.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.ErrorIs(t, store.CreateCollection(ctx, "docs"), vectorstores.ErrMissingDimensions)
+	require.NoError(t, store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(3),
+		vectorstores.WithDistanceMetric(vectorstores.DistanceDotProduct),
+		vectorstores.WithIndexParams(map[string]any{"m": 32})))
+	require.Equal(t, map[string]any{
+		"vectors":     map[string]any{"size": float64(3), "distance": "Dot"},
+		"hnsw_config": map[string]any{"m": float64(32)},
+	}, created)
+	err = store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(3), vectorstores.WithDistanceMetric("hamming"))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedDistanceMetric)
+
+	names, err := store.ListCollections(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+	require.NoError(t, store.DropCollection(ctx, "docs"))
+	require.Equal(t, []string{"PUT /collections/docs", "GET /collections", "DELETE /collections/docs"}, requests)
+}
+
+func TestQdrantPayloadIndexesAndQuantization(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		if strings.HasSuffix(r.URL.Path, "/search") {
+			_, _ = w.Write([]byte(`{"result":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*serverURL),
+		qdrant.WithCollectionName("docs"),
+		qdrant.WithEmbedder(e),
+		qdrant.WithVectorName("dense"),
+		qdrant.WithVectorParams(map[string]any{"on_disk": true}),
+		qdrant.WithQuantization(map[string]any{"scalar": map[string]any{"type": "int8"}}),
+		qdrant.WithPayloadIndexes(map[string]qdrant.PayloadSchemaType{"lang": qdrant.PayloadSchemaKeyword}),
+		qdrant.WithSearchParams(map[string]any{"hnsw_ef": 128}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(3)))
+	require.NoError(t, store.CreatePayloadIndex(ctx, "year", qdrant.PayloadSchemaInteger))
+	_, err = store.SimilaritySearch(ctx, "q", 1)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"PUT /collections/docs", "PUT /collections/docs/index", "PUT /collections/docs/index",
+		"POST /collections/docs/points/search",
+	}, requests)
+	require.Equal(t, map[string]any{
+		"vectors": map[string]any{
+			"dense": map[string]any{"size": float64(3), "distance": "Cosine", "on_disk": true},
+		},
+		"quantization_config": map[string]any{"scalar": map[string]any{"type": "int8"}},
+	}, bodies[0])
+	require.Equal(t, map[string]any{"field_name": "lang", "field_schema": "keyword"}, bodies[1])
+	require.Equal(t, map[string]any{"field_name": "year", "field_schema": "integer"}, bodies[2])
+	require.Equal(t, map[string]any{"hnsw_ef": float64(128)}, bodies[3]["params"])
+}
@@ -9,29 +9,42 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
 )
 
 // upsertPoints updates or inserts points into the Qdrant collection.
 func (s Store) upsertPoints(
 	ctx context.Context,
 	baseURL *url.URL,
+	ids []string,
 	vectors [][]float32,
+	sparseVectors []embeddings.SparseVector,
 	payloads []map[string]interface{},
-) ([]string, error) {
-	ids := make([]string, len(vectors))
-	for i := range ids {
-		ids[i] = uuid.NewString()
-	}
-
-	payload := upsertBody{
+) error {
+	var payload any = upsertBody{
 		Batch: upsertBatch{
 			IDs:      ids,
 			Vectors:  vectors,
 			Payloads: payloads,
 		},
 	}
+	if s.vectorName != "" || sparseVectors != nil {
+		// named vectors are only supported in the list of points format.
+		points := make([]point, len(ids))
+		for i, id := range ids {
+			points[i] = point{
+				ID:      id,
+				Vector:  map[string]any{s.vectorName: vectors[i]},
+				Payload: payloads[i],
+			}
+			if sparseVectors != nil {
+				points[i].Vector[s.sparseVectorName] = newSparseVector(sparseVectors[i])
+			}
+		}
+		payload = upsertPointsBody{Points: points}
+	}
 
 	url := baseURL.JoinPath("collections", s.collectionName, "points")
 	body,
@@ -43,16 +56,31 @@ func (s Store) upsertPoints(
 		payload,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer body.Close()
 
 	if status == http.StatusOK {
-		return ids, nil
+		return nil
+	}
+
+	return newAPIError("upserting vectors", body)
+}
+
+// deletePoints deletes the points with the ids or matched by the filter of
+// the payload from the Qdrant collection.
+func (s Store) deletePoints(ctx context.Context, baseURL *url.URL, payload deleteBody) error {
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "delete")
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodPost, payload)
+	if err != nil {
+		return err
 	}
+	defer body.Close()
 
-	return nil,
-		newAPIError("upserting vectors", body)
+	if status != http.StatusOK {
+		return newAPIError("deleting points", body)
+	}
+	return nil
 }
 
 // searchPoints queries the Qdrant collection for points based on the provided parameters.
@@ -71,9 +99,15 @@ func (s Store) searchPoints(
 		Limit:       numVectors,
 		Filter:      filter,
 	}
+	if s.searchParams != nil {
+		payload.Params = s.searchParams
+	}
+	if s.vectorName != "" {
+		payload.Vector = namedVector{Name: s.vectorName, Vector: vector}
+	}
 
 	if scoreThreshold != 0 {
-		payload.ScoreThreshold = scoreThreshold
+		payload.ScoreThreshold = s.scoreThreshold(scoreThreshold)
 	}
 
 	url := baseURL.JoinPath("collections", s.collectionName, "points", "search")
@@ -101,8 +135,124 @@ func (s Store) searchPoints(
 	if err != nil {
 		return nil, err
 	}
-	docs := make([]schema.Document, len(response.Result))
-	for i, match := range response.Result {
+	docs, err := s.documents(response.Result)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].Score = s.score(docs[i].Score)
+	}
+	return docs, nil
+}
+
+// score converts the score of a point found, a distance for Euclidean
+// collections, to a relevance score.
+func (s Store) score(score float32) float32 {
+	switch s.metric { //nolint:exhaustive
+	case vectorstores.DistanceEuclidean:
+		return vectorstores.EuclideanDistanceScore(float64(score))
+	case vectorstores.DistanceDotProduct:
+		return vectorstores.DotProductScore(float64(score))
+	}
+	return vectorstores.CosineSimilarityScore(float64(score))
+}
+
+// scoreThreshold converts a relevance score threshold to the score
+// threshold of Qdrant, the maximum distance for Euclidean collections.
+func (s Store) scoreThreshold(scoreThreshold float32) float32 {
+	if s.metric == vectorstores.DistanceEuclidean {
+		return float32(vectorstores.EuclideanDistanceThreshold(scoreThreshold))
+	}
+	return scoreThreshold
+}
+
+// hybridSearchPoints queries the Qdrant collection with both the dense and
+// the sparse vectors. Results are fused by Qdrant with Reciprocal Rank Fusion,
+// or by weighting the dense and sparse scores.
+func (s Store) hybridSearchPoints(
+	ctx context.Context,
+	vector []float32,
+	sparse embeddings.SparseVector,
+	hybrid vectorstores.HybridSearch,
+	numVectors int,
+	scoreThreshold float32,
+	filter any,
+) ([]schema.Document, error) {
+	if hybrid.Fusion == vectorstores.FusionWeighted {
+		dense, err := s.query(ctx, queryBody{
+			Query: vector, Using: s.vectorName, Limit: numVectors, Filter: filter, WithPayload: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches, err := s.query(ctx, queryBody{
+			Query: newSparseVector(sparse), Using: s.sparseVectorName, Limit: numVectors, Filter: filter, WithPayload: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return hybrid.Fuse(dense, matches, numVectors, scoreThreshold, nil), nil
+	}
+
+	return s.query(ctx, queryBody{
+		Prefetch: []prefetch{
+			{Query: vector, Using: s.vectorName, Limit: numVectors, Filter: filter},
+			{Query: newSparseVector(sparse), Using: s.sparseVectorName, Limit: numVectors, Filter: filter},
+		},
+		Query:          fusionQuery{Fusion: "rrf"},
+		Limit:          numVectors,
+		ScoreThreshold: scoreThreshold,
+		WithPayload:    true,
+	})
+}
+
+// query queries the Qdrant collection with the universal query API, over
+// gRPC if the store has a connection.
+func (s Store) query(ctx context.Context, payload queryBody) ([]schema.Document, error) {
+	if s.conn != nil {
+		return s.grpcQueryPoints(ctx, payload)
+	}
+	return s.queryPoints(ctx, &s.qdrantURL, payload)
+}
+
+// queryPoints queries the Qdrant collection with the universal query API.
+func (s Store) queryPoints(ctx context.Context, baseURL *url.URL, payload queryBody) ([]schema.Document, error) {
+	if s.searchParams != nil {
+		payload.Params = s.searchParams
+	}
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "query")
+	body,
+		statusCode,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode != http.StatusOK {
+		return nil, newAPIError("querying collection", body)
+	}
+
+	var response queryResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return s.documents(response.Result.Points)
+}
+
+func newSparseVector(v embeddings.SparseVector) sparseVector {
+	return sparseVector{Indices: v.Indices(), Values: v.Values()}
+}
+
+// documents converts the points of a search result to documents.
+func (s Store) documents(results []result) ([]schema.Document, error) {
+	docs := make([]schema.Document, len(results))
+	for i, match := range results {
 		pageContent, ok := match.Payload[s.contentKey].(string)
 		if !ok {
 			return nil, fmt.Errorf("payload does not contain content key '%s'", s.contentKey)
@@ -121,6 +271,38 @@ func (s Store) searchPoints(
 	return docs, nil
 }
 
+// vectorDimensions returns the size of the dense vectors of the collection,
+// or 0 if the collection info isn't available, e.g. because the collection
+// doesn't exist.
+func (s Store) vectorDimensions(ctx context.Context, baseURL *url.URL) (int, error) {
+	url := baseURL.JoinPath("collections", s.collectionName)
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodGet, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		return 0, nil
+	}
+
+	var info collectionInfo
+	if err := json.NewDecoder(body).Decode(&info); err != nil {
+		return 0, err
+	}
+	vectors := info.Result.Config.Params.Vectors
+	// the vectors config is either a single unnamed vector or a map of
+	// named vectors.
+	var unnamed vectorParams
+	if err := json.Unmarshal(vectors, &unnamed); err == nil && unnamed.Size > 0 {
+		return unnamed.Size, nil
+	}
+	var named map[string]vectorParams
+	if err := json.Unmarshal(vectors, &named); err != nil {
+		return 0, nil //nolint:nilerr
+	}
+	return named[s.vectorName].Size, nil
+}
+
 // doRequest performs an HTTP request to the Qdrant API.
 func DoRequest(ctx context.Context,
 	url url.URL,
@@ -128,11 +310,14 @@ func DoRequest(ctx context.Context,
 	method string,
 	payload interface{},
 ) (io.ReadCloser, int, error) {
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, 0, err
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, 0, err
+		}
+		body = bytes.NewReader(payloadBytes)
 	}
-	body := bytes.NewReader(payloadBytes)
 
 	req, err := http.NewRequestWithContext(ctx, method, url.String()+"?wait=true", body)
 	if err != nil {
@@ -160,3 +345,107 @@ func newAPIError(task string, body io.ReadCloser) error {
 
 	return fmt.Errorf("%s: %s", task, buf.String())
 }
+
+// _distances maps distance metrics to the distances of Qdrant.
+var _distances = map[vectorstores.DistanceMetric]string{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     "Cosine",
+	vectorstores.DistanceEuclidean:  "Euclid",
+	vectorstores.DistanceDotProduct: "Dot",
+}
+
+// createCollection creates a collection with the vectors of the store.
+func (s Store) createCollection(
+	ctx context.Context,
+	baseURL *url.URL,
+	name string,
+	opts vectorstores.CollectionOptions,
+) error {
+	distance, ok := _distances[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	params := map[string]any{"size": opts.Dimensions, "distance": distance}
+	for key, value := range s.vectorParams {
+		params[key] = value
+	}
+	payload := createCollectionBody{
+		Vectors:            params,
+		HNSWConfig:         opts.IndexParams,
+		QuantizationConfig: s.quantization,
+	}
+	if s.vectorName != "" {
+		payload.Vectors = map[string]any{s.vectorName: params}
+	}
+	if s.sparseEmbedder != nil {
+		payload.SparseVectors = map[string]any{s.sparseVectorName: map[string]any{}}
+	}
+
+	url := baseURL.JoinPath("collections", name)
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodPut, payload)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		return newAPIError("creating collection", body)
+	}
+	return nil
+}
+
+// createPayloadIndex indexes a payload field of a collection.
+func (s Store) createPayloadIndex(
+	ctx context.Context,
+	baseURL *url.URL,
+	collectionName string,
+	field string,
+	schemaType PayloadSchemaType,
+) error {
+	url := baseURL.JoinPath("collections", collectionName, "index")
+	payload := createPayloadIndexBody{FieldName: field, FieldSchema: schemaType}
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodPut, payload)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		return newAPIError("creating payload index", body)
+	}
+	return nil
+}
+
+// dropCollection deletes a collection.
+func (s Store) dropCollection(ctx context.Context, baseURL *url.URL, name string) error {
+	url := baseURL.JoinPath("collections", name)
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		return newAPIError("deleting collection", body)
+	}
+	return nil
+}
+
+// listCollections returns the names of the collections.
+func (s Store) listCollections(ctx context.Context, baseURL *url.URL) ([]string, error) {
+	url := baseURL.JoinPath("collections")
+	body, status, err := DoRequest(ctx, *url, s.apiKey, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		return nil, newAPIError("listing collections", body)
+	}
+
+	var response listCollectionsResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(response.Result.Collections))
+	for i, collection := range response.Result.Collections {
+		names[i] = collection.Name
+	}
+	return names, nil
+}
@@ -5,6 +5,8 @@
 
 package qdrant
 
+import "encoding/json"
+
 type upsertBatch struct {
 	IDs      []string                 `json:"ids"`
 	Payloads []map[string]interface{} `json:"payloads"`
@@ -25,10 +27,98 @@ type searchResponse struct {
 }
 
 type searchBody struct {
-	Vector         []float32 `json:"vector"`
-	Filter         any       `json:"filter"`
-	Limit          int       `json:"limit"`
-	ScoreThreshold float32   `json:"score_threshold"`
-	WithVector     bool      `json:"with_vector"`
-	WithPayload    bool      `json:"with_payload"`
+	Vector         any     `json:"vector"`
+	Filter         any     `json:"filter"`
+	Limit          int     `json:"limit"`
+	ScoreThreshold float32 `json:"score_threshold"`
+	WithVector     bool    `json:"with_vector"`
+	WithPayload    bool    `json:"with_payload"`
+	Params         any     `json:"params,omitempty"`
+}
+
+type namedVector struct {
+	Name   string    `json:"name"`
+	Vector []float32 `json:"vector"`
+}
+
+type sparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+type point struct {
+	ID      string                 `json:"id"`
+	Vector  map[string]any         `json:"vector"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+type deleteBody struct {
+	Points []string `json:"points,omitempty"`
+	Filter any      `json:"filter,omitempty"`
+}
+
+type upsertPointsBody struct {
+	Points []point `json:"points"`
+}
+
+type prefetch struct {
+	Query  any    `json:"query"`
+	Using  string `json:"using,omitempty"`
+	Limit  int    `json:"limit"`
+	Filter any    `json:"filter,omitempty"`
+}
+
+type fusionQuery struct {
+	Fusion string `json:"fusion"`
+}
+
+type queryBody struct {
+	Prefetch       []prefetch `json:"prefetch,omitempty"`
+	Query          any        `json:"query"`
+	Using          string     `json:"using,omitempty"`
+	Filter         any        `json:"filter,omitempty"`
+	Limit          int        `json:"limit"`
+	ScoreThreshold float32    `json:"score_threshold,omitempty"`
+	WithPayload    bool       `json:"with_payload"`
+	Params         any        `json:"params,omitempty"`
+}
+
+type queryResponse struct {
+	Result struct {
+		Points []result `json:"points"`
+	} `json:"result"`
+}
+
+type vectorParams struct {
+	Size int `json:"size"`
+}
+
+type collectionInfo struct {
+	Result struct {
+		Config struct {
+			Params struct {
+				Vectors json.RawMessage `json:"vectors"`
+			} `json:"params"`
+		} `json:"config"`
+	} `json:"result"`
+}
+
+type createCollectionBody struct {
+	Vectors            any            `json:"vectors"`
+	SparseVectors      map[string]any `json:"sparse_vectors,omitempty"`
+	HNSWConfig         map[string]any `json:"hnsw_config,omitempty"`
+	QuantizationConfig map[string]any `json:"quantization_config,omitempty"`
+}
+
+type createPayloadIndexBody struct {
+	FieldName   string            `json:"field_name"`
+	FieldSchema PayloadSchemaType `json:"field_schema"`
+}
+
+type listCollectionsResponse struct {
+	Result struct {
+		Collections []struct {
+			Name string `json:"name"`
+		} `json:"collections"`
+	} `json:"result"`
 }
@@ -9,6 +9,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -49,6 +50,9 @@ const (
 	PhoneticDoubleMetaphoneFrench     PhoneticMatcherType = "dm:fr"
 	PhoneticDoubleMetaphonePortuguese PhoneticMatcherType = "dm:pt"
 	PhoneticDoubleMetaphoneSpanish    PhoneticMatcherType = "dm:es"
+
+	// _jsonRootPath is the JSON path of the root of JSON documents.
+	_jsonRootPath = "$"
 )
 
 type RedisIndexSchemaField interface {
@@ -279,6 +283,47 @@ func (s *IndexSchema) AsCommand() []string {
 	return argsOut
 }
 
+// jsonPaths returns the schema of JSON documents whose fields are at the
+// root of the documents, with the names of the fields as attributes.
+// Fields whose names are already JSON paths are left unchanged.
+func (s *IndexSchema) jsonPaths() *IndexSchema {
+	out := &IndexSchema{
+		Tag:     make([]TagField, len(s.Tag)),
+		Text:    make([]TextField, len(s.Text)),
+		Numeric: make([]NumericField, len(s.Numeric)),
+		Vector:  make([]VectorField, len(s.Vector)),
+	}
+	for i, field := range s.Tag {
+		field.Name, field.As = jsonPath(field.Name, field.As)
+		out.Tag[i] = field
+	}
+	for i, field := range s.Text {
+		field.Name, field.As = jsonPath(field.Name, field.As)
+		out.Text[i] = field
+	}
+	for i, field := range s.Numeric {
+		field.Name, field.As = jsonPath(field.Name, field.As)
+		out.Numeric[i] = field
+	}
+	for i, field := range s.Vector {
+		field.Name, field.As = jsonPath(field.Name, field.As)
+		out.Vector[i] = field
+	}
+	return out
+}
+
+// jsonPath returns the JSON path of the field at the root of the documents
+// and its attribute.
+func jsonPath(name, as string) (string, string) {
+	if strings.HasPrefix(name, _jsonRootPath) {
+		return name, as
+	}
+	if as == "" {
+		as = name
+	}
+	return _jsonRootPath + "." + name, as
+}
+
 type schemaGenerator struct {
 	format   SchemaFormat
 	filePath string
@@ -406,6 +451,10 @@ func (i *RedisIndex) AsCommand() ([]string, error) {
 		cmd = append(cmd, i.prefix...)
 	}
 	cmd = append(cmd, "SCORE", "1.0", "SCHEMA")
-	cmd = append(cmd, i.schema.AsCommand()...)
+	if i.indexType == JSONIndexType {
+		cmd = append(cmd, i.schema.jsonPaths().AsCommand()...)
+	} else {
+		cmd = append(cmd, i.schema.AsCommand()...)
+	}
 	return cmd, nil
 }
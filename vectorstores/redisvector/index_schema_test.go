@@ -179,6 +179,11 @@ func TestIndexSearchAsCommand(t *testing.T) {
 			Args{"demo", []float32{0.111}, []SearchOption{WithScoreThreshold(0.5), WithPreFilters("@job{engineer}")}},
 			"FT.SEARCH demo (@job{engineer}) @content_vector:[VECTOR_RANGE $distance_threshold $vector]=>{$yield_distance_as: distance} SORTBY distance ASC DIALECT 2 LIMIT 0 1 PARAMS 4 vector \xf8S\xe3= distance_threshold 0.5",
 		},
+		{
+			"search with distance threshold",
+			Args{"demo", []float32{0.111}, []SearchOption{WithScoreThreshold(0.5), WithDistanceThreshold(1.5), WithOffsetLimit(0, 10)}},
+			"FT.SEARCH demo @content_vector:[VECTOR_RANGE $distance_threshold $vector]=>{$yield_distance_as: distance} SORTBY distance ASC DIALECT 2 LIMIT 0 10 PARAMS 4 vector \xf8S\xe3= distance_threshold 1.5",
+		},
 	}
 
 	for _, tt := range tests {
@@ -190,3 +195,30 @@ func TestIndexSearchAsCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexAsCommand(t *testing.T) {
+	t.Parallel()
+
+	schema := IndexSchema{
+		Tag:     []TagField{{Name: "$.author.tags", As: "tags"}},
+		Text:    []TextField{{Name: "content"}},
+		Numeric: []NumericField{{Name: "year", As: "published"}},
+		Vector:  []VectorField{{Name: "content_vector", Dims: 2}},
+	}
+
+	cmd, err := NewIndex("demo", []string{"doc:demo"}, HASHIndexType, schema).AsCommand()
+	require.NoError(t, err)
+	assert.Equal(t, "FT.CREATE demo ON HASH PREFIX 1 doc:demo SCORE 1.0 SCHEMA "+
+		"$.author.tags AS tags TAG , content TEXT year AS published NUMERIC "+
+		"content_vector VECTOR FLAT 6 TYPE FLOAT32 DIM 2 DISTANCE_METRIC COSINE", strings.Join(cmd, " "))
+
+	cmd, err = NewIndex("demo", []string{"doc:demo"}, JSONIndexType, schema).AsCommand()
+	require.NoError(t, err)
+	assert.Equal(t, "FT.CREATE demo ON JSON PREFIX 1 doc:demo SCORE 1.0 SCHEMA "+
+		"$.author.tags AS tags TAG , $.content AS content TEXT $.year AS published NUMERIC "+
+		"$.content_vector AS content_vector VECTOR FLAT 6 TYPE FLOAT32 DIM 2 DISTANCE_METRIC COSINE", strings.Join(cmd, " "))
+	assert.Equal(t, "content", schema.Text[0].Name)
+
+	_, err = NewIndex("demo", nil, "XML", schema).AsCommand()
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ type IndexVectorSearch struct {
 	index          string
 	vector         []float32
 	scoreThreshold float32
+	radius         float32
 	preFilters     string
 	returns        []string
 	offset         int
@@ -48,6 +49,17 @@ func WithScoreThreshold(scoreThreshold float32) SearchOption {
 	}
 }
 
+// WithDistanceThreshold sets the radius of a range search, which returns the
+// documents whose vectors are within the distance of the vector, instead of
+// the nearest neighbors. It takes precedence over the score threshold.
+func WithDistanceThreshold(radius float32) SearchOption {
+	return func(s *IndexVectorSearch) {
+		if radius > 0 {
+			s.radius = radius
+		}
+	}
+}
+
 func WithPreFilters(preFilters string) SearchOption {
 	return func(s *IndexVectorSearch) {
 		if len(preFilters) != 0 {
@@ -102,7 +114,12 @@ func (s IndexVectorSearch) AsCommand() []string {
 	const vectorKey = defaultContentVectorFieldKey
 	params := []string{vectorField, VectorString32(s.vector)}
 
-	if s.scoreThreshold > 0 && s.scoreThreshold < 1 {
+	radius := s.radius
+	if radius == 0 && s.scoreThreshold > 0 && s.scoreThreshold < 1 {
+		radius = s.scoreThreshold
+	}
+
+	if radius > 0 {
 		// Range search
 		// "@content_vector:[VECTOR_RANGE $distance_threshold $vector]=>{$yield_distance_as: distance}"
 		filter := fmt.Sprintf("@%s:[VECTOR_RANGE $%s $%s]=>{$yield_distance_as: %s}", vectorKey, disThresholdFiled, vectorField, vectorFieldAs)
@@ -110,7 +127,7 @@ func (s IndexVectorSearch) AsCommand() []string {
 			filter = fmt.Sprintf("(%s) %s", s.preFilters, filter)
 		}
 		cmd = append(cmd, filter)
-		params = append(params, disThresholdFiled, strconv.FormatFloat(float64(s.scoreThreshold), 'f', -1, 32))
+		params = append(params, disThresholdFiled, strconv.FormatFloat(float64(radius), 'f', -1, 32))
 	} else {
 		// KNN search
 		// "(*)=>[KNN n @content_vector $vector AS distance]"
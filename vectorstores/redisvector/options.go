@@ -51,6 +51,18 @@ func WithIndexName(name string, createIndexIfNotExists bool) Option {
 	}
 }
 
+// WithIndexType is an option for specifying how the documents are stored,
+// as hashes with HASHIndexType, the default, or as JSON documents with
+// JSONIndexType, which keeps the types of the metadata values.
+// Fields of the schema of JSON indexes are JSON paths from the root of the
+// documents, with their name as attribute, unless they are already paths
+// (eg: $.author.name).
+func WithIndexType(indexType IndexType) Option {
+	return func(s *Store) {
+		s.indexType = indexType
+	}
+}
+
 // SchemaFormat JSONSchemaFormat or YAMLSchemaFormat.
 type SchemaFormat string
 
@@ -95,6 +107,14 @@ func applyClientOptions(opts ...Option) (*Store, error) {
 		return nil, fmt.Errorf("%w: missing index name", ErrInvalidOptions)
 	}
 
+	switch s.indexType {
+	case "":
+		s.indexType = HASHIndexType
+	case HASHIndexType, JSONIndexType:
+	default:
+		return nil, fmt.Errorf("%w: invalid index type %q", ErrInvalidOptions, s.indexType)
+	}
+
 	if s.schemaGenerator != nil {
 		schema, err := s.schemaGenerator.generate()
 		if err != nil {
@@ -2,11 +2,13 @@ package redisvector
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/redis/rueidis"
@@ -19,13 +21,25 @@ import (
 type RedisClient interface {
 	DropIndex(ctx context.Context, index string, deleteDocuments bool) error
 	CheckIndexExists(ctx context.Context, index string) bool
+	IndexDimensions(ctx context.Context, index string, field string) (int, error)
 	CreateIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error
+	CreateJSONIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error
 	AddDocWithHash(ctx context.Context, prefix string, doc schema.Document) (string, error)
 	AddDocsWithHash(ctx context.Context, prefix string, docs []schema.Document) ([]string, error)
-	// TODO AddDocsWithJSON
+	AddDocsWithJSON(ctx context.Context, prefix string, docs []schema.Document) ([]string, error)
+	SetDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error
+	SetDocsWithJSON(ctx context.Context, docIDs []string, docs []schema.Document) error
+	UpdateAlias(ctx context.Context, alias string, index string) error
+	DeleteAlias(ctx context.Context, alias string) error
+	DeleteDocs(ctx context.Context, docIDs []string) error
+	DeleteDocsByQuery(ctx context.Context, index string, query string) error
 	Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error)
 }
 
+// _deleteBatchSize is the number of documents searched at once when deleting
+// the documents matched by a query.
+const _deleteBatchSize = 1000
+
 type RueidisClient struct {
 	client rueidis.Client
 }
@@ -59,7 +73,80 @@ func (c RueidisClient) CheckIndexExists(ctx context.Context, index string) bool
 	return c.client.Do(ctx, c.client.B().FtInfo().Index(index).Build()).Error() == nil
 }
 
+// IndexDimensions returns the dimension of the vector field of the index, or
+// 0 if the index doesn't exist or has no such field.
+func (c RueidisClient) IndexDimensions(ctx context.Context, index string, field string) (int, error) {
+	if index == "" {
+		return 0, nil
+	}
+	info, err := c.client.Do(ctx, c.client.B().FtInfo().Index(index).Build()).ToMessage()
+	if err != nil {
+		if _, ok := rueidis.IsRedisErr(err); ok {
+			return 0, nil
+		}
+		return 0, err
+	}
+	attributes := messagePairs(info)["attributes"]
+	if !attributes.IsArray() {
+		return 0, nil
+	}
+	values, _ := attributes.ToArray()
+	for _, attribute := range values {
+		pairs := messagePairs(attribute)
+		name, dim := pairs["attribute"], pairs["dim"]
+		if !name.IsString() || !(dim.IsInt64() || dim.IsString()) {
+			continue
+		}
+		if name, _ := name.ToString(); name != field {
+			continue
+		}
+		dimensions, err := dim.AsInt64()
+		if err != nil {
+			return 0, err
+		}
+		return int(dimensions), nil
+	}
+	return 0, nil
+}
+
+// messagePairs returns the values of a map, or of an array of alternating
+// keys and values, by lowercased key. FT.INFO replies with maps to RESP3
+// clients and with arrays to RESP2 clients.
+func messagePairs(m rueidis.RedisMessage) map[string]rueidis.RedisMessage {
+	pairs := map[string]rueidis.RedisMessage{}
+	if m.IsMap() {
+		values, _ := m.AsMap()
+		for key, value := range values {
+			pairs[strings.ToLower(key)] = value
+		}
+		return pairs
+	}
+	if !m.IsArray() {
+		return pairs
+	}
+	values, _ := m.ToArray()
+	for i := 0; i+1 < len(values); i += 2 {
+		if values[i].IsString() {
+			key, _ := values[i].ToString()
+			pairs[strings.ToLower(key)] = values[i+1]
+		}
+	}
+	return pairs
+}
+
+// CreateIndexIfNotExists creates an index of hashes with the schema if it
+// doesn't exist.
 func (c RueidisClient) CreateIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error {
+	return c.createIndexIfNotExists(ctx, index, HASHIndexType, schema)
+}
+
+// CreateJSONIndexIfNotExists creates an index of JSON documents with the
+// schema if it doesn't exist.
+func (c RueidisClient) CreateJSONIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error {
+	return c.createIndexIfNotExists(ctx, index, JSONIndexType, schema)
+}
+
+func (c RueidisClient) createIndexIfNotExists(ctx context.Context, index string, indexType IndexType, schema *IndexSchema) error { //nolint:lll
 	if index == "" {
 		return ErrEmptyIndexName
 	}
@@ -68,7 +155,7 @@ func (c RueidisClient) CreateIndexIfNotExists(ctx context.Context, index string,
 		return nil
 	}
 
-	redisIndex := NewIndex(index, []string{getPrefix(index)}, HASHIndexType, *schema)
+	redisIndex := NewIndex(index, []string{getPrefix(index)}, indexType, *schema)
 	createIndexCmd, err := redisIndex.AsCommand()
 	if err != nil {
 		return err
@@ -99,6 +186,104 @@ func (c RueidisClient) AddDocsWithHash(ctx context.Context, prefix string, docs
 	return docIDs, errors.Join(errs...)
 }
 
+// AddDocsWithJSON adds the documents as JSON documents, returning their ids.
+func (c RueidisClient) AddDocsWithJSON(ctx context.Context, prefix string, docs []schema.Document) ([]string, error) {
+	cmds := make([]rueidis.Completed, 0, len(docs))
+	docIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		docID := getDocIDWithMetaData(prefix, doc.Metadata)
+		cmd, err := c.jsonSetCMD(docID, doc)
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, cmd)
+		docIDs = append(docIDs, docID)
+	}
+	return docIDs, c.doMulti(ctx, cmds)
+}
+
+// SetDocsWithJSON replaces the JSON documents with the ids by the documents.
+func (c RueidisClient) SetDocsWithJSON(ctx context.Context, docIDs []string, docs []schema.Document) error {
+	cmds := make([]rueidis.Completed, 0, len(docs))
+	for i, doc := range docs {
+		cmd, err := c.jsonSetCMD(docIDs[i], doc)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, cmd)
+	}
+	return c.doMulti(ctx, cmds)
+}
+
+// UpdateAlias points the alias to the index, creating the alias if it
+// doesn't exist.
+func (c RueidisClient) UpdateAlias(ctx context.Context, alias string, index string) error {
+	return c.client.Do(ctx, c.client.B().FtAliasupdate().Alias(alias).Index(index).Build()).Error()
+}
+
+// DeleteAlias deletes the alias, keeping the index it points to.
+func (c RueidisClient) DeleteAlias(ctx context.Context, alias string) error {
+	return c.client.Do(ctx, c.client.B().FtAliasdel().Alias(alias).Build()).Error()
+}
+
+// SetDocsWithHash replaces the hashes with the ids by the documents.
+func (c RueidisClient) SetDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error {
+	cmds := make([]rueidis.Completed, 0, 2*len(docs))
+	for i, doc := range docs {
+		cmds = append(cmds, c.client.B().Arbitrary("DEL").Keys(docIDs[i]).Build(), c.hsetCMD(docIDs[i], doc))
+	}
+	return c.doMulti(ctx, cmds)
+}
+
+// DeleteDocs deletes the hashes with the ids.
+func (c RueidisClient) DeleteDocs(ctx context.Context, docIDs []string) error {
+	cmds := make([]rueidis.Completed, 0, len(docIDs))
+	for _, docID := range docIDs {
+		cmds = append(cmds, c.client.B().Arbitrary("DEL").Keys(docID).Build())
+	}
+	return c.doMulti(ctx, cmds)
+}
+
+// DeleteDocsByQuery deletes the hashes of the index matched by the query.
+func (c RueidisClient) DeleteDocsByQuery(ctx context.Context, index string, query string) error {
+	for {
+		res, err := c.client.Do(ctx, c.client.B().Arbitrary("FT.SEARCH").Keys(index).
+			Args(query, "NOCONTENT", "DIALECT", "2", "LIMIT", "0", strconv.Itoa(_deleteBatchSize)).Build()).ToArray()
+		if err != nil {
+			return err
+		}
+		// the reply is the total number of matches followed by their keys.
+		docIDs := make([]string, 0, len(res))
+		for _, msg := range res[1:] {
+			docID, err := msg.ToString()
+			if err != nil {
+				return err
+			}
+			docIDs = append(docIDs, docID)
+		}
+		if len(docIDs) == 0 {
+			return nil
+		}
+		if err := c.DeleteDocs(ctx, docIDs); err != nil {
+			return err
+		}
+	}
+}
+
+// doMulti runs the commands, joining their errors.
+func (c RueidisClient) doMulti(ctx context.Context, cmds []rueidis.Completed) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(cmds))
+	for _, res := range c.client.DoMulti(ctx, cmds...) {
+		if res.Error() != nil {
+			errs = append(errs, res.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error) {
 	cmds := search.AsCommand()
 	// fmt.Println(strings.Join(cmds, " "))
@@ -111,6 +296,11 @@ func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (in
 }
 
 func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (string, rueidis.Completed) {
+	docID := getDocIDWithMetaData(prefix, doc.Metadata)
+	return docID, c.hsetCMD(docID, doc)
+}
+
+func (c RueidisClient) hsetCMD(docID string, doc schema.Document) rueidis.Completed {
 	kvs := make([]string, 0, len(maps.Keys(doc.Metadata))*2)
 	for k, v := range doc.Metadata {
 		kvs = append(kvs, k)
@@ -127,8 +317,17 @@ func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (stri
 			kvs = append(kvs, fmt.Sprintf("%v", v))
 		}
 	}
-	docID := getDocIDWithMetaData(prefix, doc.Metadata)
-	return docID, c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
+	return c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
+}
+
+// jsonSetCMD returns the command setting the JSON document with the id to the
+// metadata of the document, which holds its content and vector.
+func (c RueidisClient) jsonSetCMD(docID string, doc schema.Document) (rueidis.Completed, error) {
+	value, err := json.Marshal(doc.Metadata)
+	if err != nil {
+		return rueidis.Completed{}, err
+	}
+	return c.client.B().Arbitrary("JSON.SET").Keys(docID).Args("$", string(value)).Build(), nil
 }
 
 // getPrefix get prefix with index name.
@@ -158,7 +357,11 @@ func convertFTSearchResIntoDocSchema(docs []rueidis.FtSearchDoc) []schema.Docume
 		metadata := make(map[string]any, len(doc.Doc))
 		//nolint: gocritic
 		for k, v := range doc.Doc {
-			if k == defaultContentFieldKey {
+			if k == _jsonRootPath {
+				// JSON documents searched without returned fields are
+				// returned whole.
+				convertJSONDocIntoDocSchema(v, &_doc, metadata)
+			} else if k == defaultContentFieldKey {
 				_doc.PageContent = v
 			} else if k == defaultDistanceFieldKey {
 				score, _ := strconv.ParseFloat(v, 32)
@@ -175,3 +378,22 @@ func convertFTSearchResIntoDocSchema(docs []rueidis.FtSearchDoc) []schema.Docume
 	}
 	return res
 }
+
+// convertJSONDocIntoDocSchema sets the content of the document and the
+// metadata from the JSON document.
+func convertJSONDocIntoDocSchema(value string, doc *schema.Document, metadata map[string]any) {
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		slog.Warn("ignore invalid JSON document", "error", err)
+		return
+	}
+	for k, v := range fields {
+		switch k {
+		case defaultContentFieldKey:
+			doc.PageContent, _ = v.(string)
+		case defaultContentVectorFieldKey:
+		default:
+			metadata[k] = v
+		}
+	}
+}
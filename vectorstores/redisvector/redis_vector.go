@@ -3,6 +3,9 @@ package redisvector
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
@@ -15,6 +18,10 @@ const (
 	defaultContentFieldKey       = "content"        // page_content
 	defaultContentVectorFieldKey = "content_vector" // vector
 	defaultDistanceFieldKey      = "distance"       // distance
+
+	// _defaultBatchSize is the number of documents embedded and saved at a
+	// time.
+	_defaultBatchSize = 500
 )
 
 var (
@@ -22,6 +29,7 @@ var (
 	ErrNotExistedIndex        = errors.New("redis index name does not exist")
 	ErrInvalidEmbeddingVector = errors.New("embedding vector error")
 	ErrInvalidScoreThreshold  = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidRadius          = errors.New("radius must be positive")
 	ErrInvalidFilters         = errors.New("invalid filters")
 )
 
@@ -31,12 +39,17 @@ type Store struct {
 	client                 RedisClient
 	redisURL               string
 	indexName              string
+	indexType              IndexType
 	createIndexIfNotExists bool
 	indexSchema            *IndexSchema
 	schemaGenerator        *schemaGenerator
 }
 
-var _ vectorstores.VectorStore = &Store{}
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (*Store, error) {
@@ -60,7 +73,7 @@ func New(ctx context.Context, opts ...Option) (*Store, error) {
 			return nil, ErrNotExistedIndex
 		} else if s.indexSchema != nil {
 			// create index with input schema
-			if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, s.indexSchema); err != nil {
+			if err := s.createIndex(ctx, s.indexSchema); err != nil {
 				return nil, err
 			}
 		}
@@ -71,38 +84,123 @@ func New(ctx context.Context, opts ...Option) (*Store, error) {
 
 // AddDocuments adds the text and metadata from the documents to the redis associated with 'Store'.
 // and returns the ids of the added documents.
-// Note: currently save documents with Hset command
+// Note: documents are saved with Hset command, or as JSON documents with
+// JSONIndexType
 // return `docIDs` that prefix with `doc:{index_name}`
 //
 //	if doc.metadata has `keys` or `ids` field, the docId will use `keys` or `ids` value
 //	if not, the docId is uuid string
-func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
-	err := s.appendDocumentsWithVectors(ctx, docs)
+//
+// Documents are embedded and saved in batches of 500 by default.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	ids := make([]string, len(docs))
+	var mu sync.Mutex
+	err := vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		batch := docs[start:end]
+		if err := s.prepareDocuments(ctx, &mu, batch); err != nil {
+			return err
+		}
+		addDocs := s.client.AddDocsWithHash
+		if s.indexType == JSONIndexType {
+			addDocs = s.client.AddDocsWithJSON
+		}
+		docIDs, err := addDocs(ctx, getPrefix(s.indexName), batch)
+		if err != nil {
+			return err
+		}
+		copy(ids[start:end], docIDs)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return ids, nil
+}
+
+// UpsertDocuments adds the text and metadata from the documents with the ids
+// to the redis associated with 'Store', replacing the documents with the same
+// ids. The ids are the `docIDs` returned by AddDocuments, or ids that are
+// prefixed with `doc:{index_name}:`.
+func (s *Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	opts := s.getOptions(options...)
+	docIDs := s.getDocIDs(ids)
+	var mu sync.Mutex
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		if err := s.prepareDocuments(ctx, &mu, docs[start:end]); err != nil {
+			return err
+		}
+		if s.indexType == JSONIndexType {
+			return s.client.SetDocsWithJSON(ctx, docIDs[start:end], docs[start:end])
+		}
+		return s.client.SetDocsWithHash(ctx, docIDs[start:end], docs[start:end])
+	})
+}
+
+// prepareDocuments appends the content and vectors of the documents to their
+// metadata, and creates the index from the metadata of the first documents
+// if needed. The index is created by one batch at a time.
+func (s *Store) prepareDocuments(ctx context.Context, mu *sync.Mutex, docs []schema.Document) error {
+	vectors, err := s.appendDocumentsWithVectors(ctx, docs)
+	if err != nil {
+		return err
+	}
 
 	indexSchema, err := generateSchemaWithMetadata(docs[0].Metadata)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	mu.Lock()
+	defer mu.Unlock()
 	if s.indexSchema == nil {
 		s.indexSchema = indexSchema
 	}
-
 	if s.createIndexIfNotExists && !s.client.CheckIndexExists(ctx, s.indexName) {
-		if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema); err != nil {
-			return nil, err
+		if err := s.createIndex(ctx, indexSchema); err != nil {
+			return err
 		}
 	}
 
-	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
+	dimensions, err := s.client.IndexDimensions(ctx, s.indexName, defaultContentVectorFieldKey)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	return vectorstores.CheckDimensions(vectors, dimensions)
+}
+
+// createIndex creates the index of the store with the schema, of the type of
+// the store, if it doesn't exist.
+func (s *Store) createIndex(ctx context.Context, indexSchema *IndexSchema) error {
+	if s.indexType == JSONIndexType {
+		return s.client.CreateJSONIndexIfNotExists(ctx, s.indexName, indexSchema)
 	}
+	return s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema)
+}
 
-	return docIDs, nil
+// DeleteByIDs deletes the documents with the ids, as for UpsertDocuments,
+// from the redis associated with 'Store'.
+func (s *Store) DeleteByIDs(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	return s.client.DeleteDocs(ctx, s.getDocIDs(ids))
+}
+
+// DeleteByFilter deletes the documents matched by the filter, a redis search
+// query (eg: @title:Dune), from the redis associated with 'Store'.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	query, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if query == "" || query == "*" {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.client.DeleteDocsByQuery(ctx, s.indexName, query)
 }
 
 // SimilaritySearch similarity search docs with `ScoreThreshold` `Filters` `Embedder`
@@ -120,6 +218,43 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	if err != nil {
 		return nil, err
 	}
+	if scoreThreshold == 0 {
+		return s.search(ctx, query, numDocuments, opts)
+	}
+	return s.search(ctx, query, numDocuments, opts, WithDistanceThreshold(s.distanceThreshold(scoreThreshold)))
+}
+
+// RangeSearch searches the documents whose vectors are within the radius of
+// the vector of the query, in the distance metric of the index, up to
+// numDocuments documents ordered by distance. It supports the same options
+// as SimilaritySearch, except WithScoreThreshold.
+//
+// ref: https://redis.io/docs/latest/develop/interact/search-and-query/advanced-concepts/vectors/#range-queries
+func (s *Store) RangeSearch(
+	ctx context.Context,
+	query string,
+	radius float32,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	if radius <= 0 {
+		return nil, ErrInvalidRadius
+	}
+	return s.search(ctx, query, numDocuments, s.getOptions(options...), WithDistanceThreshold(radius))
+}
+
+// search searches the documents nearest to the vector of the query with the
+// options and the search options.
+func (s *Store) search(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	opts vectorstores.Options,
+	searchOpts ...SearchOption,
+) ([]schema.Document, error) {
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by redisvector", vectorstores.ErrInvalidHybridSearch)
+	}
 	filter, err := s.getFilters(opts)
 	if err != nil {
 		return nil, err
@@ -133,7 +268,7 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	searchOpts := []SearchOption{WithScoreThreshold(scoreThreshold), WithOffsetLimit(0, numDocuments), WithPreFilters(filter)}
+	searchOpts = append(searchOpts, WithOffsetLimit(0, numDocuments), WithPreFilters(filter))
 	if s.indexSchema != nil {
 		searchOpts = append(searchOpts, WithReturns(maps.Keys(s.indexSchema.MetadataKeys())))
 	}
@@ -151,9 +286,46 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	if err != nil {
 		return nil, err
 	}
+	for i := range docs {
+		docs[i].Score = s.score(docs[i].Score)
+	}
 	return docs, nil
 }
 
+// distanceMetric returns the distance metric of the content vectors of the
+// index schema, COSINE by default.
+func (s *Store) distanceMetric() DistanceMetric {
+	if s.indexSchema != nil {
+		for _, f := range s.indexSchema.Vector {
+			if f.Name == defaultContentVectorFieldKey && f.DistanceMetric != "" {
+				return f.DistanceMetric
+			}
+		}
+	}
+	return CosineDistanceMetric
+}
+
+// score converts the distance of a document found, 1 minus the inner product
+// for the IP metric and the squared distance for L2, to a relevance score.
+func (s *Store) score(distance float32) float32 {
+	switch s.distanceMetric() { //nolint:exhaustive
+	case L2DistanceMetric:
+		return vectorstores.SquaredEuclideanDistanceScore(float64(distance))
+	case IPDistanceMetric:
+		return vectorstores.DotProductScore(1 - float64(distance))
+	}
+	return vectorstores.CosineDistanceScore(float64(distance))
+}
+
+// distanceThreshold converts a score threshold to the radius of range
+// searches.
+func (s *Store) distanceThreshold(scoreThreshold float32) float32 {
+	if s.distanceMetric() == L2DistanceMetric {
+		return float32(vectorstores.SquaredEuclideanDistanceThreshold(scoreThreshold))
+	}
+	return float32(vectorstores.CosineDistanceThreshold(scoreThreshold))
+}
+
 func (s *Store) DropIndex(ctx context.Context, index string, deleteDocuments bool) error {
 	if !s.client.CheckIndexExists(ctx, index) {
 		return ErrNotExistedIndex
@@ -161,6 +333,21 @@ func (s *Store) DropIndex(ctx context.Context, index string, deleteDocuments boo
 	return s.client.DropIndex(ctx, index, deleteDocuments)
 }
 
+// UpdateAlias points the alias to the index of the store, creating the alias
+// if it doesn't exist. Stores whose index name is the alias search the index
+// it points to, which allows reindexing without downtime: fill a new index,
+// then move the alias to it and drop the old index.
+// Documents should be added, upserted and deleted with the store of the
+// index, since their ids are prefixed with the name of the index.
+func (s *Store) UpdateAlias(ctx context.Context, alias string) error {
+	return s.client.UpdateAlias(ctx, alias, s.indexName)
+}
+
+// DeleteAlias deletes the alias, keeping the index it points to.
+func (s *Store) DeleteAlias(ctx context.Context, alias string) error {
+	return s.client.DeleteAlias(ctx, alias)
+}
+
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	opts := vectorstores.Options{}
 	for _, opt := range options {
@@ -187,10 +374,23 @@ func (s Store) getFilters(opts vectorstores.Options) (string, error) {
 	return "", nil
 }
 
-// append content & content_vector into doc.Metadata.
-func (s Store) appendDocumentsWithVectors(ctx context.Context, docs []schema.Document) error {
+// getDocIDs returns the ids with the prefix of the index.
+func (s Store) getDocIDs(ids []string) []string {
+	prefix := getPrefix(s.indexName) + ":"
+	docIDs := make([]string, len(ids))
+	for i, id := range ids {
+		docIDs[i] = id
+		if !strings.HasPrefix(id, prefix) {
+			docIDs[i] = prefix + id
+		}
+	}
+	return docIDs
+}
+
+// append content & content_vector into doc.Metadata, and return the vectors.
+func (s Store) appendDocumentsWithVectors(ctx context.Context, docs []schema.Document) ([][]float32, error) {
 	if len(docs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	texts := make([]string, 0, len(docs))
@@ -200,10 +400,10 @@ func (s Store) appendDocumentsWithVectors(ctx context.Context, docs []schema.Doc
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(vectors) != len(docs) {
-		return ErrInvalidEmbeddingVector
+		return nil, ErrInvalidEmbeddingVector
 	}
 
 	// append content & content_vector info metadata
@@ -214,7 +414,5 @@ func (s Store) appendDocumentsWithVectors(ctx context.Context, docs []schema.Doc
 		docs[i].Metadata[defaultContentFieldKey] = docs[i].PageContent
 		docs[i].Metadata[defaultContentVectorFieldKey] = vectors[i]
 	}
-
-	// vectorDimension := len(vectors[0])
-	return nil
+	return vectors, nil
 }
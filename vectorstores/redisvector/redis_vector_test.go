@@ -214,6 +214,21 @@ func TestAddDocuments(t *testing.T) {
 	assert.Equal(t, len(data), len(docIDs))
 	assert.True(t, strings.HasPrefix(docIDs[0], prefix+index))
 
+	// vectors of another dimension than the index are rejected
+	short, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+	shortVector, err := redisvector.New(ctx,
+		redisvector.WithConnectionURL(redisURL),
+		redisvector.WithIndexName(index, false),
+		redisvector.WithEmbedder(short),
+	)
+	require.NoError(t, err)
+	_, err = shortVector.AddDocuments(ctx, data[:1])
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+
 	// create data with ids or keys
 	dataWithIDOrKeys := []schema.Document{
 		{PageContent: "Tokyo", Metadata: map[string]any{"ids": "id1", "population": 9.7, "area": 622}},
@@ -252,6 +267,51 @@ func TestAddDocuments(t *testing.T) {
 	})
 }
 
+func TestUpsertAndDeleteDocuments(t *testing.T) {
+	t.Parallel()
+
+	redisURL, ollamaURL := getValues(t)
+	_, e := getEmbedding(ollamaModel, ollamaURL)
+
+	ctx := context.Background()
+	index := "test_upsert_delete_document"
+
+	vector, err := redisvector.New(ctx,
+		redisvector.WithConnectionURL(redisURL),
+		redisvector.WithIndexName(index, true),
+		redisvector.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vector.DropIndex(ctx, index, true))
+	})
+
+	err = vector.UpsertDocuments(ctx, []string{"tokyo", "paris"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"population": 9.7}},
+		{PageContent: "Paris", Metadata: map[string]any{"population": 11}},
+	})
+	require.NoError(t, err)
+	err = vector.UpsertDocuments(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"population": 14}},
+	})
+	require.NoError(t, err)
+
+	docs, err := vector.SimilaritySearch(ctx, "Tokyo", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, vector.DeleteByFilter(ctx, "@population:[12 +inf]"))
+	docs, err = vector.SimilaritySearch(ctx, "Tokyo", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Paris", docs[0].PageContent)
+
+	require.NoError(t, vector.DeleteByIDs(ctx, []string{"doc:" + index + ":paris"}))
+	docs, err = vector.SimilaritySearch(ctx, "Tokyo", 5)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
+
 func TestSimilaritySearch(t *testing.T) {
 	t.Parallel()
 
@@ -383,7 +443,7 @@ func TestRedisVectorAsRetriever(t *testing.T) {
 		ctx,
 		chains.NewRetrievalQAFromLLM(
 			llm,
-			vectorstores.ToRetriever(store, 5, vectorstores.WithScoreThreshold(0.8)),
+			vectorstores.ToRetriever(store, 5, vectorstores.WithScoreThreshold(0.2)),
 		),
 		"What colors is each piece of furniture next to the desk?",
 	)
@@ -0,0 +1,60 @@
+package vectorstores
+
+import "math"
+
+// The scores of the documents found by similarity searches, and the score
+// thresholds of WithScoreThreshold, are relevance scores between 0 and 1,
+// higher for more similar documents. Stores convert the distances or
+// similarities of their backends so that, for the normalized vectors of
+// most embedding models, the score of a document is the cosine similarity
+// of its vector and the query vector, 0 if it is negative, whatever the
+// distance metric. Scores of hybrid searches are fused scores instead.
+
+// CosineSimilarityScore returns the score of a cosine similarity, which is
+// the similarity clamped to [0, 1].
+func CosineSimilarityScore(similarity float64) float32 {
+	return float32(max(0, min(1, similarity)))
+}
+
+// CosineDistanceScore returns the score of a cosine distance, 1 minus the
+// cosine similarity.
+func CosineDistanceScore(distance float64) float32 {
+	return CosineSimilarityScore(1 - distance)
+}
+
+// DotProductScore returns the score of the dot product of normalized
+// vectors, which is their cosine similarity.
+func DotProductScore(product float64) float32 {
+	return CosineSimilarityScore(product)
+}
+
+// EuclideanDistanceScore returns the score of the Euclidean distance of
+// normalized vectors, whose cosine similarity is 1 - distance²/2.
+func EuclideanDistanceScore(distance float64) float32 {
+	return SquaredEuclideanDistanceScore(distance * distance)
+}
+
+// SquaredEuclideanDistanceScore returns the score of the squared Euclidean
+// distance of normalized vectors.
+func SquaredEuclideanDistanceScore(distance float64) float32 {
+	return CosineSimilarityScore(1 - distance/2) //nolint:mnd
+}
+
+// CosineDistanceThreshold returns the cosine distance of the documents with
+// the score threshold, for stores filtering documents by distance.
+func CosineDistanceThreshold(scoreThreshold float32) float64 {
+	return 1 - float64(scoreThreshold)
+}
+
+// EuclideanDistanceThreshold returns the Euclidean distance of the documents
+// with the score threshold, for stores filtering documents by distance.
+func EuclideanDistanceThreshold(scoreThreshold float32) float64 {
+	return math.Sqrt(SquaredEuclideanDistanceThreshold(scoreThreshold))
+}
+
+// SquaredEuclideanDistanceThreshold returns the squared Euclidean distance
+// of the documents with the score threshold, for stores filtering documents
+// by distance.
+func SquaredEuclideanDistanceThreshold(scoreThreshold float32) float64 {
+	return 2 * (1 - float64(scoreThreshold)) //nolint:mnd
+}
@@ -0,0 +1,29 @@
+package vectorstores
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScores(t *testing.T) {
+	t.Parallel()
+	// the normalized vectors (1, 0) and (0.6, 0.8) have a cosine similarity
+	// of 0.6.
+	const similarity = 0.6
+	euclidean := math.Sqrt(0.4*0.4 + 0.8*0.8)
+	assert.InDelta(t, similarity, CosineSimilarityScore(similarity), 1e-6)
+	assert.InDelta(t, similarity, CosineDistanceScore(1-similarity), 1e-6)
+	assert.InDelta(t, similarity, DotProductScore(similarity), 1e-6)
+	assert.InDelta(t, similarity, EuclideanDistanceScore(euclidean), 1e-6)
+	assert.InDelta(t, similarity, SquaredEuclideanDistanceScore(euclidean*euclidean), 1e-6)
+
+	assert.Zero(t, CosineDistanceScore(1.5))
+	assert.Zero(t, EuclideanDistanceScore(2))
+	assert.Equal(t, float32(1), CosineSimilarityScore(1.2))
+
+	assert.InDelta(t, 1-similarity, CosineDistanceThreshold(similarity), 1e-6)
+	assert.InDelta(t, euclidean, EuclideanDistanceThreshold(similarity), 1e-6)
+	assert.InDelta(t, euclidean*euclidean, SquaredEuclideanDistanceThreshold(similarity), 1e-6)
+}
@@ -0,0 +1,9 @@
+// Package sqlitevec contains an implementation of the VectorStore
+// interface using sqlite-vec, storing documents and their vectors in a
+// single SQLite database file.
+//
+// The sqlite-vec extension must be loaded in the connections of the
+// database, e.g. by calling sqlite_vec.Auto() from
+// github.com/asg017/sqlite-vec-go-bindings/cgo before opening it with the
+// github.com/mattn/go-sqlite3 driver.
+package sqlitevec
@@ -0,0 +1,83 @@
+package sqlitevec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultTableName is the default name of the documents table. The
+	// vectors are stored in a vec0 virtual table of the same name suffixed
+	// with "_vec".
+	DefaultTableName = "langchaingo_documents"
+)
+
+// DistanceMetric is the distance used to compare vectors.
+type DistanceMetric string
+
+const (
+	// DistanceCosine is the cosine distance. Document scores are the cosine
+	// similarity.
+	DistanceCosine DistanceMetric = "cosine"
+	// DistanceL2 is the euclidean distance. Document scores are
+	// 1 / (1 + distance).
+	DistanceL2 DistanceMetric = "l2"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithTableName is an option for specifying the documents table name.
+func WithTableName(name string) Option {
+	return func(s *Store) {
+		s.tableName = name
+	}
+}
+
+// WithVectorDimensions is an option for specifying the vector size used to
+// create the vector table. Defaults to the dimensions of an existing table,
+// then to those of the embedder, and finally to the size of the first
+// vectors added.
+func WithVectorDimensions(size int) Option {
+	return func(s *Store) {
+		s.dimensions = size
+	}
+}
+
+// WithDistanceMetric is an option for specifying the distance metric of a
+// new vector table. Defaults to DistanceCosine.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		tableName: DefaultTableName,
+		metric:    DistanceCosine,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.metric != DistanceCosine && s.metric != DistanceL2 {
+		return nil, fmt.Errorf("%w: unsupported distance metric %q", ErrInvalidOptions, s.metric)
+	}
+	return s, nil
+}
@@ -0,0 +1,475 @@
+package sqlitevec
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+)
+
+// Store is a wrapper around a SQLite database with the sqlite-vec
+// extension. Documents are stored in a regular table and their vectors in a
+// vec0 virtual table sharing its rowids.
+type Store struct {
+	db         *sql.DB
+	embedder   embeddings.Embedder
+	tableName  string
+	dimensions int
+	metric     DistanceMetric
+	migrated   bool
+}
+
+var (
+	_ vectorstores.VectorStore = &Store{}
+	_ vectorstores.Deleter     = &Store{}
+	_ vectorstores.Upserter    = &Store{}
+)
+
+// New creates a new Store with options, creating its tables if their vector
+// dimensions are known.
+func New(ctx context.Context, db *sql.DB, opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.db = db
+
+	existing, err := s.existingDimensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case existing > 0:
+		s.dimensions = existing
+		s.migrated = true
+	case s.dimensions == 0:
+		s.dimensions = embeddings.Dimensions(s.embedder)
+	}
+	if s.dimensions > 0 && !s.migrated {
+		if err := s.Migrate(ctx, s.dimensions); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *Store) vectorTableName() string {
+	return s.tableName + "_vec"
+}
+
+// Migrate creates the documents table and the vec0 virtual table for
+// vectors of the given dimensions, if they don't exist yet.
+func (s *Store) Migrate(ctx context.Context, dimensions int) error {
+	if dimensions <= 0 {
+		return fmt.Errorf("%w: vector dimensions must be positive", ErrInvalidOptions)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q (
+		id TEXT NOT NULL UNIQUE,
+		content TEXT NOT NULL,
+		metadata TEXT NOT NULL
+	)`, s.tableName))
+	if err != nil {
+		return fmt.Errorf("create documents table: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		`CREATE VIRTUAL TABLE IF NOT EXISTS %q USING vec0(embedding float[%d] distance_metric=%s)`,
+		s.vectorTableName(), dimensions, s.metric))
+	if err != nil {
+		return fmt.Errorf("create vector table: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.dimensions = dimensions
+	s.migrated = true
+	return nil
+}
+
+// DropTables drops the documents and vector tables.
+func (s *Store) DropTables(ctx context.Context) error {
+	for _, table := range []string{s.vectorTableName(), s.tableName} {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %q", table)); err != nil {
+			return err
+		}
+	}
+	s.migrated = false
+	return nil
+}
+
+var _vectorColumn = regexp.MustCompile(`float\[(\d+)\]`)
+
+// existingDimensions returns the dimensions of the existing vector table,
+// or 0 if it doesn't exist.
+func (s *Store) existingDimensions(ctx context.Context) (int, error) {
+	var ddl string
+	err := s.db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE name = ?", s.vectorTableName()).Scan(&ddl)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	match := _vectorColumn.FindStringSubmatch(ddl)
+	if match == nil {
+		return 0, nil
+	}
+	return strconv.Atoi(match[1])
+}
+
+// AddDocuments adds documents to the SQLite database associated with
+// 'Store', and returns their ids.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	vectors, err := s.embed(ctx, opts, docs)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.insert(ctx, ids, docs, vectors, false); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids to the SQLite database
+// associated with 'Store', replacing the documents with the same ids.
+func (s *Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	vectors, err := s.embed(ctx, opts, docs)
+	if err != nil {
+		return err
+	}
+	return s.insert(ctx, ids, docs, vectors, true)
+}
+
+// DeleteByIDs deletes the documents with the ids.
+func (s *Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	if len(ids) == 0 || !s.migrated {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return s.deleteWhere(ctx, fmt.Sprintf("id IN (%s)", placeholders), args)
+}
+
+// DeleteByFilter deletes the documents with the metadata values of the
+// filter, a map of metadata keys to values.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	filters, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if len(filters) == 0 {
+		return vectorstores.ErrMissingFilter
+	}
+	if !s.migrated {
+		return nil
+	}
+	where, args := filterConditions("", filters)
+	return s.deleteWhere(ctx, strings.Join(where, " AND "), args)
+}
+
+// deleteWhere deletes the documents matching the condition on the documents
+// table, and their vectors.
+func (s *Store) deleteWhere(ctx context.Context, where string, args []any) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := s.deleteRows(ctx, tx, where, args); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) deleteRows(ctx context.Context, tx *sql.Tx, where string, args []any) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %q WHERE rowid IN (SELECT rowid FROM %q WHERE %s)",
+		s.vectorTableName(), s.tableName, where), args...)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %q WHERE %s", s.tableName, where), args...)
+	return err
+}
+
+// embed embeds the documents, creating the tables for their dimensions if
+// they don't exist yet.
+func (s *Store) embed(ctx context.Context, opts vectorstores.Options, docs []schema.Document) ([][]float32, error) {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, s.dimensions); err != nil {
+		return nil, err
+	}
+	if !s.migrated {
+		if err := s.Migrate(ctx, len(vectors[0])); err != nil {
+			return nil, err
+		}
+	}
+	return vectors, nil
+}
+
+// insert inserts the documents with the ids, first deleting the documents
+// with the same ids if replace is set.
+func (s *Store) insert(ctx context.Context, ids []string, docs []schema.Document, vectors [][]float32, replace bool) error { //nolint:lll
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	insertDoc, err := tx.PrepareContext(ctx,
+		fmt.Sprintf("INSERT INTO %q (id, content, metadata) VALUES (?, ?, ?)", s.tableName))
+	if err != nil {
+		return err
+	}
+	defer insertDoc.Close()
+	insertVector, err := tx.PrepareContext(ctx,
+		fmt.Sprintf("INSERT INTO %q (rowid, embedding) VALUES (?, ?)", s.vectorTableName()))
+	if err != nil {
+		return err
+	}
+	defer insertVector.Close()
+
+	for i, doc := range docs {
+		if replace {
+			if err := s.deleteRows(ctx, tx, "id = ?", []any{ids[i]}); err != nil {
+				return err
+			}
+		}
+		metadata := doc.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		buf, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		res, err := insertDoc.ExecContext(ctx, ids[i], doc.PageContent, string(buf))
+		if err != nil {
+			return err
+		}
+		rowid, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if _, err := insertVector.ExecContext(ctx, rowid, embeddings.EncodeVector(vectors[i])); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SimilaritySearch returns the documents nearest to the query. Filters are
+// a map of metadata keys to the values the documents must have; filtered
+// searches compare the query with every matching vector instead of using
+// the KNN query of sqlite-vec.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by sqlitevec", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	if !s.migrated {
+		return []schema.Document{}, nil
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := vectorstores.CheckDimensions([][]float32{vector}, s.dimensions); err != nil {
+		return nil, err
+	}
+
+	sqlQuery, args := s.searchQuery(embeddings.EncodeVector(vector), numDocuments, filters)
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, numDocuments)
+	for rows.Next() {
+		var doc schema.Document
+		var metadata string
+		var distance float64
+		if err := rows.Scan(&doc.PageContent, &metadata, &distance); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return nil, err
+		}
+		doc.Score = s.score(distance)
+		if opts.ScoreThreshold != 0 && doc.Score < opts.ScoreThreshold {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (s *Store) searchQuery(vector []byte, numDocuments int, filters map[string]any) (string, []any) {
+	if len(filters) == 0 {
+		query := fmt.Sprintf(`WITH knn AS (
+	SELECT rowid, distance FROM %q WHERE embedding MATCH ? AND k = ?
+)
+SELECT d.content, d.metadata, knn.distance
+FROM knn JOIN %q AS d ON d.rowid = knn.rowid
+ORDER BY knn.distance`, s.vectorTableName(), s.tableName)
+		return query, []any{vector, numDocuments}
+	}
+
+	where, filterArgs := filterConditions("d.", filters)
+	args := append([]any{vector}, filterArgs...)
+	args = append(args, numDocuments)
+	query := fmt.Sprintf(`SELECT d.content, d.metadata, vec_distance_%s(v.embedding, ?) AS distance
+FROM %q AS v JOIN %q AS d ON d.rowid = v.rowid
+WHERE %s
+ORDER BY distance
+LIMIT ?`, s.metric, s.vectorTableName(), s.tableName, strings.Join(where, " AND "))
+	return query, args
+}
+
+// filterConditions returns the conditions on the metadata column, with the
+// prefix, of the filters, sorted by key, and their arguments.
+func filterConditions(prefix string, filters map[string]any) ([]string, []any) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	where := make([]string, len(keys))
+	args := make([]any, 0, 2*len(keys)) //nolint:mnd
+	for i, key := range keys {
+		where[i] = fmt.Sprintf("json_extract(%smetadata, ?) = ?", prefix)
+		args = append(args, "$."+strconv.Quote(key), filters[key])
+	}
+	return where, args
+}
+
+func (s *Store) score(distance float64) float32 {
+	if s.metric == DistanceL2 {
+		return vectorstores.EuclideanDistanceScore(distance)
+	}
+	return vectorstores.CosineDistanceScore(distance)
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s *Store) getFilters(opts vectorstores.Options) (map[string]any, error) {
+	if opts.Filters == nil {
+		return nil, nil
+	}
+	filters, ok := opts.Filters.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidFilters
+	}
+	return filters, nil
+}
+
+func (s *Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,156 @@
+package sqlitevec
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeEmbedder embeds texts by the counts of the letters a, b and c.
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = embed(text)
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return embed(text), nil
+}
+
+func embed(text string) []float32 {
+	vector := make([]float32, 3)
+	for _, r := range text {
+		if r >= 'a' && r <= 'c' {
+			vector[r-'a']++
+		}
+	}
+	return vector
+}
+
+var _ embeddings.Embedder = fakeEmbedder{}
+
+func init() {
+	sqlite_vec.Auto()
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func TestSqliteVecStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openDB(t)
+
+	store, err := New(ctx, db, WithEmbedder(fakeEmbedder{}))
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "aaa", Metadata: map[string]any{"kind": "a"}},
+		{PageContent: "bbb", Metadata: map[string]any{"kind": "b"}},
+		{PageContent: "abc", Metadata: map[string]any{"kind": "mixed"}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, ids, 3)
+
+	docs, err := store.SimilaritySearch(ctx, "a", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "aaa", docs[0].PageContent)
+	assert.InDelta(t, 1, docs[0].Score, 1e-5)
+	assert.Equal(t, "abc", docs[1].PageContent)
+
+	docs, err = store.SimilaritySearch(ctx, "a", 2, vectorstores.WithFilters(map[string]any{"kind": "b"}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "bbb", docs[0].PageContent)
+
+	docs, err = store.SimilaritySearch(ctx, "a", 3, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	// a new store on the same tables picks up their dimensions.
+	store, err = New(ctx, db, WithEmbedder(fakeEmbedder{}), WithVectorDimensions(4))
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.dimensions)
+}
+
+func TestSqliteVecUpsertAndDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openDB(t)
+
+	store, err := New(ctx, db, WithEmbedder(fakeEmbedder{}))
+	require.NoError(t, err)
+
+	err = store.UpsertDocuments(ctx, []string{"1", "2", "3"}, []schema.Document{
+		{PageContent: "aaa", Metadata: map[string]any{"kind": "a"}},
+		{PageContent: "bbb", Metadata: map[string]any{"kind": "b"}},
+		{PageContent: "ccc", Metadata: map[string]any{"kind": "c"}},
+	})
+	require.NoError(t, err)
+	err = store.UpsertDocuments(ctx, []string{"1"}, []schema.Document{
+		{PageContent: "abc", Metadata: map[string]any{"kind": "mixed"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "a", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "abc", docs[0].PageContent)
+
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"2"}))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"kind": "c"}))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+
+	docs, err = store.SimilaritySearch(ctx, "a", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "abc", docs[0].PageContent)
+}
+
+func TestSqliteVecDimensionMismatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openDB(t)
+
+	store, err := New(ctx, db, WithEmbedder(fakeEmbedder{}), WithVectorDimensions(4))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "a"}})
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+}
+
+func TestSqliteVecOptions(t *testing.T) {
+	t.Parallel()
+	_, err := New(context.Background(), nil)
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(context.Background(), nil, WithEmbedder(fakeEmbedder{}), WithDistanceMetric("dot"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestSqliteVecSearchQuery(t *testing.T) {
+	t.Parallel()
+	store := &Store{tableName: "docs", metric: DistanceL2}
+	query, args := store.searchQuery([]byte{1}, 3, map[string]any{"b": 2, "a": "x"})
+	assert.Contains(t, query, "vec_distance_l2(v.embedding, ?)")
+	assert.Contains(t, query, `FROM "docs_vec" AS v JOIN "docs" AS d`)
+	assert.Equal(t, []any{[]byte{1}, `$."a"`, "x", `$."b"`, 2, 3}, args)
+	assert.InDelta(t, 0.5, store.score(1), 1e-6)
+}
@@ -0,0 +1,4 @@
+// Package turbopuffer contains an implementation of the VectorStore
+// interface using turbopuffer, a serverless vector and full-text search
+// engine whose namespaces are cheap enough to hold one tenant each.
+package turbopuffer
@@ -0,0 +1,126 @@
+package turbopuffer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultBaseURL is the default URL of the turbopuffer API.
+	DefaultBaseURL = "https://api.turbopuffer.com"
+	// APIKeyEnvVarName is the environment variable read for the API key.
+	APIKeyEnvVarName = "TURBOPUFFER_API_KEY"
+
+	_defaultContentKey = "text"
+)
+
+// DistanceMetric is the distance used to compare vectors.
+type DistanceMetric string
+
+const (
+	DistanceCosine           DistanceMetric = "cosine_distance"
+	DistanceEuclideanSquared DistanceMetric = "euclidean_squared"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithAPIKey is an option for specifying the API key. Defaults to the
+// TURBOPUFFER_API_KEY environment variable.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Store) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithBaseURL is an option for specifying the API URL, e.g. the URL of a
+// region.
+func WithBaseURL(baseURL string) Option {
+	return func(s *Store) {
+		s.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient is an option for providing a custom http client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// WithNamespace is an option for specifying the namespace used when no name
+// space is given to AddDocuments or SimilaritySearch. Must be set.
+func WithNamespace(namespace string) Option {
+	return func(s *Store) {
+		s.namespace = namespace
+	}
+}
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithContentKey is an option for specifying the attribute storing the
+// page content of the documents.
+func WithContentKey(contentKey string) Option {
+	return func(s *Store) {
+		s.contentKey = contentKey
+	}
+}
+
+// WithDistanceMetric is an option for specifying the distance metric.
+// Defaults to DistanceCosine.
+func WithDistanceMetric(metric DistanceMetric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+// WithHybridSearch is an option for indexing the page content for BM25
+// full-text search, and fusing the BM25 and vector results of searches
+// with reciprocal rank fusion unless vectorstores.WithHybridSearch sets
+// another strategy.
+func WithHybridSearch(hybrid bool) Option {
+	return func(s *Store) {
+		s.hybrid = hybrid
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		baseURL:    DefaultBaseURL,
+		contentKey: _defaultContentKey,
+		metric:     DistanceCosine,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.apiKey == "" {
+		s.apiKey = os.Getenv(APIKeyEnvVarName)
+	}
+	if s.apiKey == "" {
+		return Store{}, fmt.Errorf("%w: missing API key, set it with %s", ErrInvalidOptions, APIKeyEnvVarName)
+	}
+	if s.namespace == "" {
+		return Store{}, fmt.Errorf("%w: missing namespace", ErrInvalidOptions)
+	}
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	return s, nil
+}
@@ -0,0 +1,409 @@
+package turbopuffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+	// ErrAPI is returned when the turbopuffer API returns an error.
+	ErrAPI = errors.New("turbopuffer API error")
+)
+
+// _defaultBatchSize is the number of documents upserted at a time.
+const _defaultBatchSize = 1000
+
+// Store is a wrapper around the turbopuffer API.
+type Store struct {
+	baseURL    string
+	apiKey     string
+	client     *http.Client
+	namespace  string
+	embedder   embeddings.Embedder
+	contentKey string
+	metric     DistanceMetric
+	hybrid     bool
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
+
+// New creates a new Store with options.
+func New(opts ...Option) (Store, error) {
+	return applyClientOptions(opts...)
+}
+
+type row struct {
+	ID         string         `json:"id"`
+	Vector     []float32      `json:"vector,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Dist       *float64       `json:"dist,omitempty"`
+}
+
+type attributeSchema struct {
+	Type           string `json:"type"`
+	FullTextSearch bool   `json:"full_text_search,omitempty"`
+}
+
+type upsertBody struct {
+	Upserts        []row                      `json:"upserts"`
+	DistanceMetric DistanceMetric             `json:"distance_metric"`
+	Schema         map[string]attributeSchema `json:"schema,omitempty"`
+}
+
+type deleteBody struct {
+	Deletes        []string `json:"deletes,omitempty"`
+	DeleteByFilter any      `json:"delete_by_filter,omitempty"`
+}
+
+type queryBody struct {
+	Vector            []float32      `json:"vector,omitempty"`
+	RankBy            []any          `json:"rank_by,omitempty"`
+	DistanceMetric    DistanceMetric `json:"distance_metric,omitempty"`
+	TopK              int            `json:"top_k"`
+	Filters           any            `json:"filters,omitempty"`
+	IncludeAttributes bool           `json:"include_attributes"`
+}
+
+// AddDocuments adds documents to the namespace given with
+// vectorstores.WithNameSpace, or to the default one, and returns their ids.
+// Metadata values are stored as attributes and must be of types supported
+// by turbopuffer.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.upsert(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids to the namespace given with
+// vectorstores.WithNameSpace, or to the default one, replacing the documents
+// with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.upsert(ctx, ids, docs, opts)
+}
+
+// DeleteByIDs deletes the documents with the ids from the namespace given
+// with vectorstores.WithNameSpace, or from the default one.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	if err := s.do(ctx, s.namespacePath(opts), deleteBody{Deletes: ids}, nil); err != nil {
+		return fmt.Errorf("delete documents: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents of the namespace given with
+// vectorstores.WithNameSpace, or of the default one, matching the filter,
+// given like the filters of SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	filters, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if filters == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	if err := s.do(ctx, s.namespacePath(opts), deleteBody{DeleteByFilter: filters}, nil); err != nil {
+		return fmt.Errorf("delete documents: %w", err)
+	}
+	return nil
+}
+
+// upsert embeds the documents and upserts them with the ids, in batches of
+// 1000 by default.
+func (s Store) upsert(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.upsertRows(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) upsertRows(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, 0); err != nil {
+		return err
+	}
+
+	body := upsertBody{
+		Upserts:        make([]row, len(docs)),
+		DistanceMetric: s.metric,
+	}
+	if s.hybrid {
+		body.Schema = map[string]attributeSchema{
+			s.contentKey: {Type: "string", FullTextSearch: true},
+		}
+	}
+	for i, doc := range docs {
+		attributes := make(map[string]any, len(doc.Metadata)+1)
+		for key, value := range doc.Metadata {
+			attributes[key] = value
+		}
+		attributes[s.contentKey] = doc.PageContent
+		body.Upserts[i] = row{ID: ids[i], Vector: vectors[i], Attributes: attributes}
+	}
+
+	if err := s.do(ctx, s.namespacePath(opts), body, nil); err != nil {
+		return fmt.Errorf("upsert documents: %w", err)
+	}
+	return nil
+}
+
+// SimilaritySearch returns the documents of the namespace nearest to the
+// query. Filters are either a map of attribute names to the values the
+// documents must have, or a filter expression of the turbopuffer API such
+// as []any{"year", "Gte", 2020}. With vectorstores.WithHybridSearch, or by
+// default in stores created with WithHybridSearch, the vector and BM25
+// results are fused with the fusion strategy of the hybrid search.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+	hybrid := opts.HybridSearch
+	if hybrid == nil && s.hybrid {
+		hybrid = &vectorstores.HybridSearch{}
+	}
+	if hybrid != nil {
+		if err := hybrid.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var vector []float32
+	if hybrid != nil {
+		vector = hybrid.Vector
+	}
+	if vector == nil {
+		embedder := s.embedder
+		if opts.Embedder != nil {
+			embedder = opts.Embedder
+		}
+		vector, err = embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	path := s.namespacePath(opts).JoinPath("query")
+	var rows []row
+	err = s.do(ctx, path, queryBody{
+		Vector:            vector,
+		DistanceMetric:    s.metric,
+		TopK:              numDocuments,
+		Filters:           filters,
+		IncludeAttributes: true,
+	}, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("query vectors: %w", err)
+	}
+	if hybrid == nil {
+		return s.documents(rows, opts.ScoreThreshold, s.score), nil
+	}
+
+	text := hybrid.Text
+	if text == "" {
+		text = query
+	}
+	var textRows []row
+	err = s.do(ctx, path, queryBody{
+		RankBy:            []any{s.contentKey, "BM25", text},
+		TopK:              numDocuments,
+		Filters:           filters,
+		IncludeAttributes: true,
+	}, &textRows)
+	if err != nil {
+		return nil, fmt.Errorf("query text: %w", err)
+	}
+	return hybrid.Fuse(s.documents(rows, 0, s.score), s.documents(textRows, 0, bm25Score),
+		numDocuments, opts.ScoreThreshold, documentID), nil
+}
+
+// documents converts rows to documents, scored by the score of their
+// distance.
+func (s Store) documents(rows []row, scoreThreshold float32, score func(float64) float32) []schema.Document {
+	docs := make([]schema.Document, 0, len(rows))
+	for _, r := range rows {
+		doc := schema.Document{Metadata: map[string]any{"id": r.ID}}
+		for key, value := range r.Attributes {
+			if key == s.contentKey {
+				doc.PageContent, _ = value.(string)
+				continue
+			}
+			doc.Metadata[key] = value
+		}
+		if r.Dist != nil {
+			doc.Score = score(*r.Dist)
+		}
+		if scoreThreshold != 0 && doc.Score < scoreThreshold {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func (s Store) score(dist float64) float32 {
+	if s.metric == DistanceEuclideanSquared {
+		return vectorstores.SquaredEuclideanDistanceScore(dist)
+	}
+	return vectorstores.CosineDistanceScore(dist)
+}
+
+// bm25Score returns the BM25 score of full-text results, which turbopuffer
+// returns as their distance.
+func bm25Score(dist float64) float32 {
+	return float32(dist)
+}
+
+// documentID identifies the documents of fused results.
+func documentID(doc schema.Document) string {
+	return fmt.Sprint(doc.Metadata["id"])
+}
+
+func (s Store) namespacePath(opts vectorstores.Options) *url.URL {
+	namespace := s.namespace
+	if opts.NameSpace != "" {
+		namespace = opts.NameSpace
+	}
+	u, _ := url.Parse(s.baseURL)
+	return u.JoinPath("v1", "namespaces", namespace)
+}
+
+func (s Store) do(ctx context.Context, u *url.URL, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: status code %d: %s", ErrAPI, resp.StatusCode, msg)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+// getFilters converts map filters to an And of Eq filters, and passes
+// filter expressions through.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	switch filters := opts.Filters.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		keys := make([]string, 0, len(filters))
+		for key := range filters {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		conditions := make([]any, len(keys))
+		for i, key := range keys {
+			conditions[i] = []any{key, "Eq", filters[key]}
+		}
+		return []any{"And", conditions}, nil
+	case []any:
+		return filters, nil
+	default:
+		return nil, ErrInvalidFilters
+	}
+}
+
+func (s Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
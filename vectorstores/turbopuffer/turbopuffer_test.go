@@ -0,0 +1,213 @@
+package turbopuffer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func newEmbedder(t *testing.T) embeddings.Embedder {
+	t.Helper()
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i := range texts {
+				vectors[i] = []float32{1, 0}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+func TestTurbopufferStore(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		body["path"] = r.URL.Path
+		requests = append(requests, body)
+		switch {
+		case !strings.HasSuffix(r.URL.Path, "/query"):
+			_, _ = w.Write([]byte(`{"status":"OK"}`))
+		case body["rank_by"] != nil:
+			_, _ = w.Write([]byte(`[{"id":"b","attributes":{"text":"bar"}},{"id":"a","attributes":{"text":"foo"}}]`))
+		default:
+			_, _ = w.Write([]byte(`[{"id":"a","dist":0.1,"attributes":{"text":"foo","year":2024}},` +
+				`{"id":"c","dist":0.4,"attributes":{"text":"baz"}}]`))
+		}
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)), WithHybridSearch(true))
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "foo", Metadata: map[string]any{"year": 2024}},
+	}, vectorstores.WithNameSpace("tenant"))
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	require.Equal(t, "/v1/namespaces/tenant", requests[0]["path"])
+	upserts, ok := requests[0]["upserts"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"text": "foo", "year": float64(2024)}, upserts[0].(map[string]any)["attributes"])
+	assert.Equal(t, map[string]any{"text": map[string]any{"type": "string", "full_text_search": true}},
+		requests[0]["schema"])
+
+	docs, err := store.SimilaritySearch(context.Background(), "foo", 2,
+		vectorstores.WithFilters(map[string]any{"year": 2024}))
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/v1/namespaces/default/query", requests[1]["path"])
+	assert.Equal(t, []any{"And", []any{[]any{"year", "Eq", float64(2024)}}}, requests[1]["filters"])
+	assert.Equal(t, []any{"text", "BM25", "foo"}, requests[2]["rank_by"])
+
+	// "a" is ranked by both queries.
+	require.Len(t, docs, 2)
+	assert.Equal(t, "foo", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"id": "a", "year": float64(2024)}, docs[0].Metadata)
+	assert.InDelta(t, 1.0/61+1.0/62, docs[0].Score, 1e-6)
+	assert.Equal(t, "bar", docs[1].PageContent)
+}
+
+func TestTurbopufferWeightedHybridSearch(t *testing.T) {
+	t.Parallel()
+	var rankBy []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["rank_by"] != nil {
+			rankBy, _ = body["rank_by"].([]any)
+			_, _ = w.Write([]byte(`[{"id":"b","dist":2,"attributes":{"text":"bar"}},{"id":"a","dist":1,"attributes":{"text":"foo"}}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"a","dist":0.1,"attributes":{"text":"foo"}},{"id":"c","dist":0.4,"attributes":{"text":"baz"}}]`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "foo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{
+			Text: "bar", Fusion: vectorstores.FusionWeighted, Alpha: 0.5,
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, []any{"text", "BM25", "bar"}, rankBy)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "foo", docs[0].PageContent)
+	assert.InDelta(t, 0.5, docs[0].Score, 1e-6)
+	assert.Equal(t, "bar", docs[1].PageContent)
+	assert.InDelta(t, 0.5, docs[1].Score, 1e-6)
+
+	_, err = store.SimilaritySearch(context.Background(), "foo", 2,
+		vectorstores.WithHybridSearch(vectorstores.HybridSearch{Alpha: 2}))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridSearch)
+}
+
+func TestTurbopufferVectorSearch(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"a","dist":0.1,"attributes":{"text":"foo"}},{"id":"c","dist":0.6,"attributes":{"text":"baz"}}]`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "foo", 2, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.InDelta(t, 0.9, docs[0].Score, 1e-6)
+}
+
+func TestTurbopufferUpsertAndDelete(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		body["path"] = r.URL.Path
+		requests = append(requests, body)
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	err = store.UpsertDocuments(ctx, []string{"a"}, []schema.Document{{PageContent: "foo"}})
+	require.NoError(t, err)
+	err = store.UpsertDocuments(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "foo"}})
+	require.ErrorIs(t, err, vectorstores.ErrIDsMismatch)
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"a"}, vectorstores.WithNameSpace("tenant")))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"year": 2024}))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+
+	require.Len(t, requests, 3)
+	upserts, ok := requests[0]["upserts"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, "a", upserts[0].(map[string]any)["id"])
+	assert.Equal(t, "/v1/namespaces/tenant", requests[1]["path"])
+	assert.Equal(t, []any{"a"}, requests[1]["deletes"])
+	assert.Equal(t, []any{"And", []any{[]any{"year", "Eq", float64(2024)}}}, requests[2]["delete_by_filter"])
+}
+
+func TestTurbopufferAddDocumentsInBatches(t *testing.T) {
+	t.Parallel()
+	var upserts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body upsertBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		upserts = append(upserts, len(body.Upserts))
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	var progress []int
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "a"}, {PageContent: "b"}, {PageContent: "c"},
+	}, vectorstores.WithBatchSize(2), vectorstores.WithProgress(func(done, _ int) { progress = append(progress, done) }))
+	require.NoError(t, err)
+	assert.Len(t, ids, 3)
+	assert.Equal(t, []int{2, 1}, upserts)
+	assert.Equal(t, []int{2, 3}, progress)
+}
+
+func TestTurbopufferError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"bad request"}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithBaseURL(server.URL), WithAPIKey("key"), WithNamespace("default"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	_, err = store.SimilaritySearch(context.Background(), "foo", 2)
+	require.ErrorIs(t, err, ErrAPI)
+
+	_, err = New(WithAPIKey("key"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -0,0 +1,8 @@
+// Package typesense contains an implementation of the VectorStore
+// interface using the vector search of Typesense.
+//
+// The collection is created on the first AddDocuments call, with fields
+// inferred from the metadata of the documents, and fields are added for new
+// metadata keys afterwards, so that they can be used in filter_by
+// expressions.
+package typesense
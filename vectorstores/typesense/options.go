@@ -0,0 +1,100 @@
+package typesense
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultURL is the default URL of the Typesense server.
+	DefaultURL = "http://localhost:8108"
+	// DefaultCollectionName is the default name of the collection.
+	DefaultCollectionName = "langchaingo"
+	// APIKeyEnvVarName is the environment variable read for the API key.
+	APIKeyEnvVarName = "TYPESENSE_API_KEY"
+
+	_defaultContentField   = "text"
+	_defaultEmbeddingField = "embedding"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithURL is an option for specifying the URL of the Typesense server.
+func WithURL(url string) Option {
+	return func(s *Store) {
+		s.url = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithAPIKey is an option for specifying the API key. Defaults to the
+// TYPESENSE_API_KEY environment variable.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Store) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient is an option for providing a custom http client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// WithCollectionName is an option for specifying the collection name.
+func WithCollectionName(name string) Option {
+	return func(s *Store) {
+		s.collectionName = name
+	}
+}
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithFields is an option for specifying the names of the fields storing
+// the page content and the embedding.
+func WithFields(content, embedding string) Option {
+	return func(s *Store) {
+		s.contentField = content
+		s.embeddingField = embedding
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		url:            DefaultURL,
+		collectionName: DefaultCollectionName,
+		contentField:   _defaultContentField,
+		embeddingField: _defaultEmbeddingField,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.apiKey == "" {
+		s.apiKey = os.Getenv(APIKeyEnvVarName)
+	}
+	if s.apiKey == "" {
+		return Store{}, fmt.Errorf("%w: missing API key, set it with %s", ErrInvalidOptions, APIKeyEnvVarName)
+	}
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	return s, nil
+}
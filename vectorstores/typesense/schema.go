@@ -0,0 +1,82 @@
+package typesense
+
+import (
+	"slices"
+	"strings"
+)
+
+// field is a field of a Typesense collection schema.
+type field struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	NumDim     int            `json:"num_dim,omitempty"`
+	VecDist    string         `json:"vec_dist,omitempty"`
+	HNSWParams map[string]any `json:"hnsw_params,omitempty"`
+	Facet      bool           `json:"facet,omitempty"`
+	Optional   bool           `json:"optional,omitempty"`
+}
+
+type collectionSchema struct {
+	Name               string  `json:"name"`
+	Fields             []field `json:"fields"`
+	EnableNestedFields bool    `json:"enable_nested_fields,omitempty"`
+}
+
+// fieldType returns the Typesense type of a metadata value.
+func fieldType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return "int64"
+	case float32, float64:
+		return "float"
+	case bool:
+		return "bool"
+	case []string:
+		return "string[]"
+	case []int, []int32, []int64:
+		return "int64[]"
+	case []float32, []float64:
+		return "float[]"
+	case []bool:
+		return "bool[]"
+	case map[string]any:
+		return "object"
+	case []map[string]any:
+		return "object[]"
+	default:
+		return "auto"
+	}
+}
+
+// metadataFields returns the fields of the metadata keys of docs missing in
+// existing, sorted by name. Metadata fields are optional, and strings are
+// faceted.
+func metadataFields(metadatas []map[string]any, existing []field) []field {
+	known := make(map[string]bool, len(existing))
+	for _, f := range existing {
+		known[f.Name] = true
+	}
+
+	var fields []field
+	for _, metadata := range metadatas {
+		for key, value := range metadata {
+			if known[key] || value == nil {
+				continue
+			}
+			known[key] = true
+			typ := fieldType(value)
+			fields = append(fields, field{
+				Name:     key,
+				Type:     typ,
+				Facet:    typ == "string" || typ == "string[]",
+				Optional: true,
+			})
+		}
+	}
+	slices.SortFunc(fields, func(a, b field) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fields
+}
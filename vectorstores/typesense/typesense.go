@@ -0,0 +1,547 @@
+package typesense
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+	// ErrAPI is returned when Typesense returns an error.
+	ErrAPI = errors.New("typesense API error")
+)
+
+// _defaultBatchSize is the number of documents imported at a time.
+const _defaultBatchSize = 500
+
+// Store is a wrapper around a Typesense collection.
+type Store struct {
+	url            string
+	apiKey         string
+	client         *http.Client
+	collectionName string
+	embedder       embeddings.Embedder
+	contentField   string
+	embeddingField string
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+
+	_ vectorstores.CollectionManager = Store{}
+)
+
+// New creates a new Store with options.
+func New(opts ...Option) (Store, error) {
+	return applyClientOptions(opts...)
+}
+
+// AddDocuments adds documents to the collection, creating it or adding
+// fields for new metadata keys as needed, and returns their ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.importDocuments(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds documents with the ids to the collection, replacing
+// the documents with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.importDocuments(ctx, ids, docs, opts)
+}
+
+// DeleteByIDs deletes the documents with the ids.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = "`" + strings.ReplaceAll(id, "`", "") + "`"
+	}
+	return s.deleteDocuments(ctx, "id:["+strings.Join(values, ",")+"]")
+}
+
+// DeleteByFilter deletes the documents matching the filter, given like the
+// filters of SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	filterBy, err := filterBy(filter)
+	if err != nil {
+		return err
+	}
+	if filterBy == "" {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.deleteDocuments(ctx, filterBy)
+}
+
+// deleteDocuments deletes the documents matching the filter_by expression.
+// Deleting from a collection that doesn't exist yet is a no-op.
+func (s Store) deleteDocuments(ctx context.Context, filterBy string) error {
+	path := s.collectionPath().JoinPath("documents")
+	path.RawQuery = url.Values{"filter_by": {filterBy}}.Encode()
+	resp, err := s.do(ctx, http.MethodDelete, path, nil)
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete documents: %w", err)
+	}
+	return resp.Close()
+}
+
+// importDocuments embeds the documents and imports them with the ids, in
+// batches of 500 by default, creating the collection or adding fields for new
+// metadata keys as needed.
+func (s Store) importDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	// the collection is updated by one batch at a time.
+	var mu sync.Mutex
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.importBatch(ctx, &mu, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) importBatch(
+	ctx context.Context,
+	mu *sync.Mutex,
+	ids []string,
+	docs []schema.Document,
+	opts vectorstores.Options,
+) error {
+	texts := make([]string, 0, len(docs))
+	metadatas := make([]map[string]any, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+		metadatas = append(metadatas, doc.Metadata)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, 0); err != nil {
+		return err
+	}
+	mu.Lock()
+	dimensions, err := s.ensureCollection(ctx, len(vectors[0]), metadatas)
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for i, doc := range docs {
+		document := make(map[string]any, len(doc.Metadata)+3) //nolint:mnd
+		for key, value := range doc.Metadata {
+			document[key] = value
+		}
+		document["id"] = ids[i]
+		document[s.contentField] = doc.PageContent
+		document[s.embeddingField] = vectors[i]
+		if err := enc.Encode(document); err != nil {
+			return err
+		}
+	}
+
+	path := s.collectionPath().JoinPath("documents", "import")
+	path.RawQuery = "action=upsert"
+	resp, err := s.do(ctx, http.MethodPost, path, &body)
+	if err != nil {
+		return fmt.Errorf("import documents: %w", err)
+	}
+	defer resp.Close()
+	return importError(resp)
+}
+
+// importError returns the first error of the JSON lines of an import
+// response.
+func importError(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var result struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return err
+		}
+		if !result.Success {
+			return fmt.Errorf("%w: import document: %s", ErrAPI, result.Error)
+		}
+	}
+	return scanner.Err()
+}
+
+// ensureCollection creates the collection if it doesn't exist, or adds the
+// fields of new metadata keys, and returns its vector dimensions.
+func (s Store) ensureCollection(ctx context.Context, dimensions int, metadatas []map[string]any) (int, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.collectionPath(), nil)
+	if errors.Is(err, errNotFound) {
+		return dimensions, s.createCollection(ctx, dimensions, metadatas)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get collection: %w", err)
+	}
+	defer resp.Close()
+
+	var collection collectionSchema
+	if err := json.NewDecoder(resp).Decode(&collection); err != nil {
+		return 0, err
+	}
+	for _, f := range collection.Fields {
+		if f.Name == s.embeddingField && f.NumDim > 0 {
+			dimensions = f.NumDim
+		}
+	}
+
+	missing := metadataFields(metadatas, collection.Fields)
+	if len(missing) == 0 {
+		return dimensions, nil
+	}
+	body, err := json.Marshal(map[string]any{"fields": missing})
+	if err != nil {
+		return 0, err
+	}
+	resp, err = s.do(ctx, http.MethodPatch, s.collectionPath(), bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("add fields: %w", err)
+	}
+	resp.Close()
+	return dimensions, nil
+}
+
+func (s Store) createCollection(ctx context.Context, dimensions int, metadatas []map[string]any) error {
+	collection := collectionSchema{
+		Name: s.collectionName,
+		Fields: []field{
+			{Name: s.contentField, Type: "string"},
+			{Name: s.embeddingField, Type: "float[]", NumDim: dimensions},
+		},
+	}
+	collection.Fields = append(collection.Fields, metadataFields(metadatas, collection.Fields)...)
+	collection.EnableNestedFields = slices.ContainsFunc(collection.Fields, func(f field) bool {
+		return strings.HasPrefix(f.Type, "object")
+	})
+
+	return s.postCollection(ctx, collection)
+}
+
+func (s Store) postCollection(ctx context.Context, collection collectionSchema) error {
+	body, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+	u, _ := url.Parse(s.url)
+	resp, err := s.do(ctx, http.MethodPost, u.JoinPath("collections"), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create collection: %w", err)
+	}
+	return resp.Close()
+}
+
+// _vectorDistances maps distance metrics to the vector distances of
+// Typesense.
+var _vectorDistances = map[vectorstores.DistanceMetric]string{ //nolint:gochecknoglobals
+	vectorstores.DistanceCosine:     "cosine",
+	vectorstores.DistanceDotProduct: "ip",
+}
+
+// CreateCollection creates a collection with the content and embedding
+// fields of the store. Fields of metadata keys are added with the documents.
+// The index params are the HNSW params of the embedding field, e.g.
+// {"M": 16, "ef_construction": 200}.
+func (s Store) CreateCollection(ctx context.Context, name string, options ...vectorstores.CollectionOption) error {
+	opts, err := vectorstores.NewCollectionOptions(s.embedder, options...)
+	if err != nil {
+		return err
+	}
+	vecDist, ok := _vectorDistances[opts.Metric]
+	if !ok {
+		return fmt.Errorf("%w: %s", vectorstores.ErrUnsupportedDistanceMetric, opts.Metric)
+	}
+	return s.postCollection(ctx, collectionSchema{
+		Name: name,
+		Fields: []field{
+			{Name: s.contentField, Type: "string"},
+			{
+				Name:       s.embeddingField,
+				Type:       "float[]",
+				NumDim:     opts.Dimensions,
+				VecDist:    vecDist,
+				HNSWParams: opts.IndexParams,
+			},
+		},
+	})
+}
+
+// DropCollection deletes a collection and its documents.
+func (s Store) DropCollection(ctx context.Context, name string) error {
+	u, _ := url.Parse(s.url)
+	resp, err := s.do(ctx, http.MethodDelete, u.JoinPath("collections", name), nil)
+	if err != nil {
+		return fmt.Errorf("delete collection: %w", err)
+	}
+	return resp.Close()
+}
+
+// ListCollections returns the names of the collections.
+func (s Store) ListCollections(ctx context.Context) ([]string, error) {
+	u, _ := url.Parse(s.url)
+	resp, err := s.do(ctx, http.MethodGet, u.JoinPath("collections"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+	defer resp.Close()
+
+	var collections []collectionSchema
+	if err := json.NewDecoder(resp).Decode(&collections); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(collections))
+	for i, collection := range collections {
+		names[i] = collection.Name
+	}
+	return names, nil
+}
+
+type searchResult struct {
+	Hits []struct {
+		Document       map[string]any `json:"document"`
+		VectorDistance float64        `json:"vector_distance"`
+	} `json:"hits"`
+	Error string `json:"error"`
+}
+
+// SimilaritySearch returns the documents nearest to the query. Filters are
+// either a filter_by expression, e.g. "year:>2020 && lang:=en", or a map of
+// field names to the values the documents must have.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by typesense", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filterBy, err := filterBy(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]any{"searches": []any{s.search(vector, numDocuments, filterBy)}})
+	if err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(s.url)
+	resp, err := s.do(ctx, http.MethodPost, u.JoinPath("multi_search"), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer resp.Close()
+
+	var results struct {
+		Results []searchResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp).Decode(&results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) == 0 {
+		return nil, fmt.Errorf("%w: no search result", ErrAPI)
+	}
+	if msg := results.Results[0].Error; msg != "" {
+		return nil, fmt.Errorf("%w: search: %s", ErrAPI, msg)
+	}
+
+	docs := make([]schema.Document, 0, len(results.Results[0].Hits))
+	for _, hit := range results.Results[0].Hits {
+		doc := schema.Document{Metadata: map[string]any{}, Score: vectorstores.CosineDistanceScore(hit.VectorDistance)}
+		if opts.ScoreThreshold != 0 && doc.Score < opts.ScoreThreshold {
+			continue
+		}
+		for key, value := range hit.Document {
+			switch key {
+			case s.contentField:
+				doc.PageContent, _ = value.(string)
+			case "id", s.embeddingField:
+			default:
+				doc.Metadata[key] = value
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// search returns a search of multi_search finding the numDocuments nearest
+// neighbors of vector.
+func (s Store) search(vector []float32, numDocuments int, filterBy string) map[string]any {
+	values := make([]string, len(vector))
+	for i, v := range vector {
+		values[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	search := map[string]any{
+		"collection":     s.collectionName,
+		"q":              "*",
+		"vector_query":   fmt.Sprintf("%s:([%s], k:%d)", s.embeddingField, strings.Join(values, ","), numDocuments),
+		"per_page":       numDocuments,
+		"exclude_fields": s.embeddingField,
+	}
+	if filterBy != "" {
+		search["filter_by"] = filterBy
+	}
+	return search
+}
+
+// filterBy converts filters to a filter_by expression.
+func filterBy(filters any) (string, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return filters, nil
+	case map[string]any:
+		keys := make([]string, 0, len(filters))
+		for key := range filters {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		conditions := make([]string, len(keys))
+		for i, key := range keys {
+			value := fmt.Sprint(filters[key])
+			if _, ok := filters[key].(string); ok {
+				value = "`" + strings.ReplaceAll(value, "`", "") + "`"
+			}
+			conditions[i] = key + ":=" + value
+		}
+		return strings.Join(conditions, " && "), nil
+	default:
+		return "", ErrInvalidFilters
+	}
+}
+
+func (s Store) collectionPath() *url.URL {
+	u, _ := url.Parse(s.url)
+	return u.JoinPath("collections", s.collectionName)
+}
+
+var errNotFound = errors.New("not found")
+
+// do sends a request and returns the response body, which must be closed.
+func (s Store) do(ctx context.Context, method string, u *url.URL, body io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-TYPESENSE-API-KEY", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %w: %s", ErrAPI, errNotFound, msg)
+	}
+	return nil, fmt.Errorf("%w: status code %d: %s", ErrAPI, resp.StatusCode, msg)
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
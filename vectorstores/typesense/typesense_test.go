@@ -0,0 +1,223 @@
+package typesense
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func newEmbedder(t *testing.T) embeddings.Embedder {
+	t.Helper()
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i := range texts {
+				vectors[i] = []float32{1, 0.5}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+func TestTypesenseStore(t *testing.T) {
+	t.Parallel()
+	var (
+		created  collectionSchema
+		imported []map[string]any
+		search   map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("X-TYPESENSE-API-KEY"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /collections/docs":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "POST /collections":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case "POST /collections/docs/documents/import":
+			assert.Equal(t, "upsert", r.URL.Query().Get("action"))
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var doc map[string]any
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &doc))
+				imported = append(imported, doc)
+				_, _ = w.Write([]byte("{\"success\":true}\n"))
+			}
+		case "POST /multi_search":
+			var body struct {
+				Searches []map[string]any `json:"searches"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			search = body.Searches[0]
+			_, _ = w.Write([]byte(`{"results":[{"hits":[` +
+				`{"document":{"id":"1","text":"foo","year":2024,"lang":"en"},"vector_distance":0.25}]}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithAPIKey("key"), WithCollectionName("docs"),
+		WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "foo", Metadata: map[string]any{"year": 2024, "lang": "en"}},
+		{PageContent: "bar", Metadata: map[string]any{"tags": []string{"a"}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	assert.Equal(t, collectionSchema{
+		Name: "docs",
+		Fields: []field{
+			{Name: "text", Type: "string"},
+			{Name: "embedding", Type: "float[]", NumDim: 2},
+			{Name: "lang", Type: "string", Facet: true, Optional: true},
+			{Name: "tags", Type: "string[]", Facet: true, Optional: true},
+			{Name: "year", Type: "int64", Optional: true},
+		},
+	}, created)
+	require.Len(t, imported, 2)
+	assert.Equal(t, ids[0], imported[0]["id"])
+	assert.Equal(t, "foo", imported[0]["text"])
+	assert.Equal(t, "en", imported[0]["lang"])
+	assert.Equal(t, []any{float64(1), 0.5}, imported[0]["embedding"])
+
+	docs, err := store.SimilaritySearch(context.Background(), "foo", 3,
+		vectorstores.WithFilters(map[string]any{"year": 2024, "lang": "en"}))
+	require.NoError(t, err)
+	assert.Equal(t, "embedding:([1,0.5], k:3)", search["vector_query"])
+	assert.Equal(t, "lang:=`en` && year:=2024", search["filter_by"])
+	assert.Equal(t, "embedding", search["exclude_fields"])
+	require.Len(t, docs, 1)
+	assert.Equal(t, "foo", docs[0].PageContent)
+	assert.InDelta(t, 0.75, docs[0].Score, 1e-6)
+	assert.Equal(t, map[string]any{"year": float64(2024), "lang": "en"}, docs[0].Metadata)
+
+	docs, err = store.SimilaritySearch(context.Background(), "foo", 3,
+		vectorstores.WithFilters("year:>2020"), vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	assert.Equal(t, "year:>2020", search["filter_by"])
+	assert.Empty(t, docs)
+
+	_, err = store.SimilaritySearch(context.Background(), "foo", 3, vectorstores.WithFilters(1))
+	require.ErrorIs(t, err, ErrInvalidFilters)
+}
+
+func TestTypesenseAddFields(t *testing.T) {
+	t.Parallel()
+	var patched map[string][]field
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"name":"langchaingo","fields":[` +
+				`{"name":"text","type":"string"},{"name":"embedding","type":"float[]","num_dim":3},` +
+				`{"name":"year","type":"int64","optional":true}]}`))
+		case http.MethodPatch:
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithAPIKey("key"), WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	dimensions, err := store.ensureCollection(context.Background(), 2, []map[string]any{
+		{"year": 2024, "score": 0.5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, dimensions)
+	assert.Equal(t, map[string][]field{"fields": {{Name: "score", Type: "float", Optional: true}}}, patched)
+
+	// the collection has 3 dimensions, the embedder returns 2.
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "foo"}})
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+}
+
+func TestTypesenseDelete(t *testing.T) {
+	t.Parallel()
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE /collections/langchaingo/documents", r.Method+" "+r.URL.Path)
+		filters = append(filters, r.URL.Query().Get("filter_by"))
+		_, _ = w.Write([]byte(`{"num_deleted":1}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithAPIKey("key"), WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"a", "b"}))
+	require.NoError(t, store.DeleteByIDs(ctx, nil))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"lang": "en"}))
+	require.NoError(t, store.DeleteByFilter(ctx, "year:<2020"))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, 1), ErrInvalidFilters)
+	assert.Equal(t, []string{"id:[`a`,`b`]", "lang:=`en`", "year:<2020"}, filters)
+
+	err = store.UpsertDocuments(ctx, []string{"a"}, nil)
+	require.ErrorIs(t, err, vectorstores.ErrIDsMismatch)
+}
+
+func TestTypesenseCollections(t *testing.T) {
+	t.Parallel()
+	var (
+		requests []string
+		created  collectionSchema
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodPost:
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"name":"a","fields":[]},{"name":"b","fields":[]}]`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithAPIKey("key"), WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.ErrorIs(t, store.CreateCollection(ctx, "docs"), vectorstores.ErrMissingDimensions)
+	err = store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(2),
+		vectorstores.WithDistanceMetric(vectorstores.DistanceEuclidean))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedDistanceMetric)
+	require.NoError(t, store.CreateCollection(ctx, "docs", vectorstores.WithDimensions(2),
+		vectorstores.WithIndexParams(map[string]any{"M": 32})))
+	assert.Equal(t, collectionSchema{
+		Name: "docs",
+		Fields: []field{
+			{Name: "text", Type: "string"},
+			{Name: "embedding", Type: "float[]", NumDim: 2, VecDist: "cosine", HNSWParams: map[string]any{"M": float64(32)}},
+		},
+	}, created)
+
+	names, err := store.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+	require.NoError(t, store.DropCollection(ctx, "docs"))
+	assert.Equal(t, []string{"POST /collections", "GET /collections", "DELETE /collections/docs"}, requests)
+}
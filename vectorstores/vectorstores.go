@@ -2,6 +2,8 @@ package vectorstores
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/schema"
@@ -14,6 +16,44 @@ type VectorStore interface {
 	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
 }
 
+// Deleter is implemented by vector stores that can delete documents, e.g. to
+// re-index documents that changed without dropping the collection.
+type Deleter interface {
+	// DeleteByIDs deletes the documents with the ids, as returned by
+	// AddDocuments. Ids of missing documents are ignored.
+	DeleteByIDs(ctx context.Context, ids []string, options ...Option) error
+	// DeleteByFilter deletes the documents whose metadata is matched by the
+	// filter, given in the native syntax of the store as with WithFilters or
+	// as a Filter.
+	DeleteByFilter(ctx context.Context, filter any, options ...Option) error
+}
+
+// Upserter is implemented by vector stores that can add documents with given
+// ids, replacing the documents with the same ids.
+type Upserter interface {
+	// UpsertDocuments adds or replaces the documents with the ids, which must
+	// be as many as the documents.
+	UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...Option) error
+}
+
+var (
+	// ErrMissingFilter is returned by DeleteByFilter if the filter is nil,
+	// which would delete all the documents.
+	ErrMissingFilter = errors.New("missing filter")
+	// ErrIDsMismatch is returned by UpsertDocuments if the number of ids
+	// doesn't match the number of documents.
+	ErrIDsMismatch = errors.New("number of ids does not match number of documents")
+)
+
+// CheckIDs returns an error wrapping ErrIDsMismatch if there aren't as many
+// ids as documents.
+func CheckIDs(ids []string, docs []schema.Document) error {
+	if len(ids) != len(docs) {
+		return fmt.Errorf("%w: %d ids for %d documents", ErrIDsMismatch, len(ids), len(docs))
+	}
+	return nil
+}
+
 // Retriever is a retriever for vector stores.
 type Retriever struct {
 	CallbacksHandler callbacks.Handler
@@ -24,7 +64,8 @@ type Retriever struct {
 
 var _ schema.Retriever = Retriever{}
 
-// GetRelevantDocuments returns documents using the vector store.
+// GetRelevantDocuments returns documents using the vector store, with the
+// "vectorstore" retriever name in their metadata.
 func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
 	if r.CallbacksHandler != nil {
 		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
@@ -34,6 +75,7 @@ func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]sc
 	if err != nil {
 		return nil, err
 	}
+	schema.SetRetriever(docs, "vectorstore")
 
 	if r.CallbacksHandler != nil {
 		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
@@ -42,6 +84,18 @@ func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]sc
 	return docs, nil
 }
 
+// NumDocuments returns the number of documents retrieved.
+func (r Retriever) NumDocuments() int {
+	return r.numDocs
+}
+
+// WithNumDocuments returns a copy of the retriever retrieving numDocuments
+// documents, e.g. for over-fetching documents to rerank.
+func (r Retriever) WithNumDocuments(numDocuments int) schema.Retriever {
+	r.numDocs = numDocuments
+	return r
+}
+
 // ToRetriever takes a vector store and returns a retriever using the
 // vector store to retrieve documents.
 func ToRetriever(vectorStore VectorStore, numDocuments int, options ...Option) Retriever {
@@ -0,0 +1,130 @@
+package vespa
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ApplicationPackage returns the files of a Vespa application package with
+// a content cluster holding documentType, with the fields and the rank
+// profile the store uses by default and an HNSW index on embeddings of the
+// given dimensions.
+func ApplicationPackage(documentType string, dimensions int) map[string][]byte {
+	services := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
+<services version="1.0">
+  <container id="default" version="1.0">
+    <document-api/>
+    <search/>
+  </container>
+  <content id="content" version="1.0">
+    <redundancy>1</redundancy>
+    <documents>
+      <document type="%s" mode="index"/>
+    </documents>
+    <nodes>
+      <node hostalias="node1" distribution-key="0"/>
+    </nodes>
+  </content>
+</services>
+`, documentType)
+
+	schema := fmt.Sprintf(`schema %[1]s {
+    document %[1]s {
+        field %[2]s type string {
+            indexing: index | summary
+            index: enable-bm25
+        }
+        field %[3]s type string {
+            indexing: summary
+        }
+        field %[4]s type tensor<float>(x[%[5]d]) {
+            indexing: attribute | index
+            attribute {
+                distance-metric: angular
+            }
+            index {
+                hnsw {
+                    max-links-per-node: 16
+                    neighbors-to-explore-at-insert: 200
+                }
+            }
+        }
+    }
+    rank-profile %[6]s {
+        inputs {
+            query(q) tensor<float>(x[%[5]d])
+        }
+        first-phase {
+            expression: closeness(field, %[4]s)
+        }
+    }
+}
+`, documentType, _defaultContentField, _defaultMetadataField, _defaultEmbeddingField, dimensions,
+		DefaultRankProfile)
+
+	return map[string][]byte{
+		"services.xml":                    []byte(services),
+		"schemas/" + documentType + ".sd": []byte(schema),
+	}
+}
+
+// DeployApplicationPackage zips the files of an application package and
+// deploys it with the config server at configServerURL, e.g.
+// "http://localhost:19071".
+func DeployApplicationPackage(ctx context.Context, client *http.Client, configServerURL string, files map[string][]byte) error { //nolint:lll
+	if client == nil {
+		client = http.DefaultClient
+	}
+	archive, err := zipFiles(files)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(configServerURL, "/") + "/application/v2/tenant/default/prepareandactivate"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/zip")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: deploy: status code %d: %s", ErrAPI, resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func zipFiles(files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,8 @@
+// Package vespa contains an implementation of the VectorStore interface
+// using Vespa, with nearestNeighbor queries combined with YQL filter
+// expressions.
+//
+// ApplicationPackage and DeployApplicationPackage bootstrap an application
+// with a document type matching the store defaults, for teams not already
+// running a Vespa application with a suitable schema.
+package vespa
@@ -0,0 +1,112 @@
+package vespa
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	// DefaultURL is the default URL of the Vespa container endpoint.
+	DefaultURL = "http://localhost:8080"
+	// DefaultDocumentType is the default document type, also used as the
+	// document id namespace.
+	DefaultDocumentType = "langchaingo"
+	// DefaultRankProfile is the default rank profile of the queries.
+	DefaultRankProfile = "semantic"
+
+	_defaultContentField   = "text"
+	_defaultMetadataField  = "metadata"
+	_defaultEmbeddingField = "embedding"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithURL is an option for specifying the URL of the Vespa container
+// endpoint serving the document and search APIs.
+func WithURL(url string) Option {
+	return func(s *Store) {
+		s.url = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithHTTPClient is an option for providing a custom http client, e.g. one
+// with the mTLS certificate of a Vespa Cloud application.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithDocumentType is an option for specifying the document type, i.e. the
+// schema, of the documents.
+func WithDocumentType(documentType string) Option {
+	return func(s *Store) {
+		s.documentType = documentType
+	}
+}
+
+// WithNamespace is an option for specifying the namespace of the document
+// ids. Defaults to the document type.
+func WithNamespace(namespace string) Option {
+	return func(s *Store) {
+		s.namespace = namespace
+	}
+}
+
+// WithFields is an option for specifying the names of the fields storing
+// the page content, the JSON encoded metadata and the embedding.
+func WithFields(content, metadata, embedding string) Option {
+	return func(s *Store) {
+		s.contentField = content
+		s.metadataField = metadata
+		s.embeddingField = embedding
+	}
+}
+
+// WithRankProfile is an option for specifying the rank profile of the
+// queries. Its first phase should rank by closeness to the query vector.
+func WithRankProfile(rankProfile string) Option {
+	return func(s *Store) {
+		s.rankProfile = rankProfile
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		url:            DefaultURL,
+		documentType:   DefaultDocumentType,
+		rankProfile:    DefaultRankProfile,
+		contentField:   _defaultContentField,
+		metadataField:  _defaultMetadataField,
+		embeddingField: _defaultEmbeddingField,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.namespace == "" {
+		s.namespace = s.documentType
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	return s, nil
+}
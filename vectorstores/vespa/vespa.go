@@ -0,0 +1,352 @@
+package vespa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
+	ErrInvalidFilters             = errors.New("invalid filters, expected a YQL expression")
+	ErrUnsupportedOptions         = errors.New("unsupported options")
+	// ErrAPI is returned when Vespa returns an error.
+	ErrAPI = errors.New("vespa API error")
+)
+
+// Store is a wrapper around the document and search APIs of a Vespa
+// application.
+type Store struct {
+	url            string
+	client         *http.Client
+	embedder       embeddings.Embedder
+	documentType   string
+	namespace      string
+	rankProfile    string
+	contentField   string
+	metadataField  string
+	embeddingField string
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
+
+const (
+	// _defaultBatchSize is the number of documents embedded and fed at a time.
+	_defaultBatchSize = 100
+	// _deleteBatchSize is the number of documents matching a filter deleted
+	// at a time.
+	_deleteBatchSize = 100
+)
+
+// New creates a new Store with options.
+func New(opts ...Option) (Store, error) {
+	return applyClientOptions(opts...)
+}
+
+type tensor struct {
+	Values []float32 `json:"values"`
+}
+
+// AddDocuments feeds documents to Vespa and returns their ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.feed(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments feeds documents with the ids to Vespa, replacing the
+// documents with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" || opts.Deduplicater != nil {
+		return ErrUnsupportedOptions
+	}
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.feed(ctx, ids, docs, opts)
+}
+
+// feed embeds the documents and feeds them with the ids, embedding 100
+// documents at a time by default.
+func (s Store) feed(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.feedBatch(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) feedBatch(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+	if err := vectorstores.CheckDimensions(vectors, 0); err != nil {
+		return err
+	}
+
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		body := map[string]any{"fields": map[string]any{
+			s.contentField:   doc.PageContent,
+			s.metadataField:  string(metadata),
+			s.embeddingField: tensor{Values: vectors[i]},
+		}}
+		if err := s.do(ctx, http.MethodPost, s.documentPath(ids[i]), body, nil); err != nil {
+			return fmt.Errorf("feed document: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteByIDs deletes the documents with the ids.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	for _, id := range ids {
+		if err := s.do(ctx, http.MethodDelete, s.documentPath(id), nil, nil); err != nil {
+			return fmt.Errorf("delete document: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents matching the filter, a YQL
+// expression like the filters of SimilaritySearch. The matching documents
+// are searched and deleted in batches until none are left.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	yql, ok := filter.(string)
+	if !ok {
+		return ErrInvalidFilters
+	}
+	if yql == "" {
+		return vectorstores.ErrMissingFilter
+	}
+
+	body := map[string]any{
+		"yql":  fmt.Sprintf("select documentid from sources %s where %s", s.documentType, yql),
+		"hits": _deleteBatchSize,
+	}
+	for {
+		var resp searchResponse
+		if err := s.do(ctx, http.MethodPost, "/search/", body, &resp); err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+		if len(resp.Root.Children) == 0 {
+			return nil
+		}
+		ids := make([]string, len(resp.Root.Children))
+		for i, hit := range resp.Root.Children {
+			// document ids have the form id:<namespace>:<type>::<id>.
+			_, id, found := strings.Cut(hit.ID, "::")
+			if !found {
+				return fmt.Errorf("%w: unexpected document id %q", ErrAPI, hit.ID)
+			}
+			ids[i] = id
+		}
+		if err := s.DeleteByIDs(ctx, ids); err != nil {
+			return err
+		}
+	}
+}
+
+func (s Store) documentPath(id string) string {
+	return fmt.Sprintf("/document/v1/%s/%s/docid/%s",
+		url.PathEscape(s.namespace), url.PathEscape(s.documentType), url.PathEscape(id))
+}
+
+type searchResponse struct {
+	Root struct {
+		Children []struct {
+			ID        string         `json:"id"`
+			Relevance float64        `json:"relevance"`
+			Fields    map[string]any `json:"fields"`
+		} `json:"children"`
+	} `json:"root"`
+}
+
+// SimilaritySearch returns the documents nearest to the query. Filters are
+// YQL expressions, e.g. `year > 2020 and lang contains "en"`, combined with
+// the nearestNeighbor operator.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+	if opts.HybridSearch != nil {
+		return nil, fmt.Errorf("%w: not supported by vespa", vectorstores.ErrInvalidHybridSearch)
+	}
+	if opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filter, ok := opts.Filters.(string)
+	if opts.Filters != nil && !ok {
+		return nil, ErrInvalidFilters
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp searchResponse
+	if err := s.do(ctx, http.MethodPost, "/search/", s.searchBody(vector, numDocuments, filter), &resp); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	docs := make([]schema.Document, 0, len(resp.Root.Children))
+	for _, hit := range resp.Root.Children {
+		doc := schema.Document{Score: s.score(hit.Relevance)}
+		if opts.ScoreThreshold != 0 && doc.Score < opts.ScoreThreshold {
+			continue
+		}
+		doc.PageContent, _ = hit.Fields[s.contentField].(string)
+		if metadata, _ := hit.Fields[s.metadataField].(string); metadata != "" {
+			if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+				return nil, err
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// score returns the score of the relevance of a hit. The default rank
+// profile ranks documents by their closeness with the angular distance,
+// 1 / (1 + angle), whose cosine is their cosine similarity. Relevances of
+// other rank profiles are returned as is.
+func (s Store) score(relevance float64) float32 {
+	if s.rankProfile != DefaultRankProfile {
+		return float32(relevance)
+	}
+	return vectorstores.CosineSimilarityScore(math.Cos(1/relevance - 1))
+}
+
+// searchBody returns the query of the search API finding the numDocuments
+// nearest neighbors of vector matching the YQL filter.
+func (s Store) searchBody(vector []float32, numDocuments int, filter string) map[string]any {
+	where := fmt.Sprintf("{targetHits:%d}nearestNeighbor(%s, q)", numDocuments, s.embeddingField)
+	if filter != "" {
+		where += " and (" + filter + ")"
+	}
+	return map[string]any{
+		"yql":            fmt.Sprintf("select * from sources %s where %s", s.documentType, where),
+		"hits":           numDocuments,
+		"ranking":        s.rankProfile,
+		"input.query(q)": vector,
+	}
+}
+
+func (s Store) do(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: status code %d: %s", ErrAPI, resp.StatusCode, msg)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,157 @@
+package vespa
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func newEmbedder(t *testing.T) embeddings.Embedder {
+	t.Helper()
+	e, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			vectors := make([][]float32, len(texts))
+			for i := range texts {
+				vectors[i] = []float32{1, 0}
+			}
+			return vectors, nil
+		}))
+	require.NoError(t, err)
+	return e
+}
+
+func TestVespaStore(t *testing.T) {
+	t.Parallel()
+	var paths []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/search/" {
+			_, _ = w.Write([]byte(`{"root":{"children":[
+				{"id":"1","relevance":0.9,"fields":{"text":"foo","metadata":"{\"year\":2024}"}},
+				{"id":"2","relevance":0.2,"fields":{"text":"bar","metadata":"null"}}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "foo", Metadata: map[string]any{"year": 2024}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	assert.Equal(t, "/document/v1/langchaingo/langchaingo/docid/"+ids[0], paths[0])
+	assert.Equal(t, map[string]any{
+		"text":      "foo",
+		"metadata":  `{"year":2024}`,
+		"embedding": map[string]any{"values": []any{1.0, 0.0}},
+	}, bodies[0]["fields"])
+
+	docs, err := store.SimilaritySearch(context.Background(), "foo", 2,
+		vectorstores.WithFilters("year > 2020"), vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "select * from sources langchaingo where "+
+		"{targetHits:2}nearestNeighbor(embedding, q) and (year > 2020)", bodies[1]["yql"])
+	assert.Equal(t, []any{1.0, 0.0}, bodies[1]["input.query(q)"])
+	assert.Equal(t, DefaultRankProfile, bodies[1]["ranking"])
+	require.Len(t, docs, 1)
+	assert.Equal(t, "foo", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"year": 2024.0}, docs[0].Metadata)
+	assert.InDelta(t, math.Cos(1/0.9-1), docs[0].Score, 1e-6)
+
+	_, err = store.SimilaritySearch(context.Background(), "foo", 2, vectorstores.WithFilters(map[string]any{}))
+	require.ErrorIs(t, err, ErrInvalidFilters)
+}
+
+func TestVespaUpsertAndDelete(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	searches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path != "/search/" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "select documentid from sources langchaingo where year < 2020", body["yql"])
+		searches++
+		if searches > 1 {
+			_, _ = w.Write([]byte(`{"root":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"root":{"children":[{"id":"id:langchaingo:langchaingo::b"}]}}`))
+	}))
+	defer server.Close()
+
+	store, err := New(WithURL(server.URL), WithEmbedder(newEmbedder(t)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.UpsertDocuments(ctx, []string{"a"}, []schema.Document{{PageContent: "foo"}}))
+	require.NoError(t, store.DeleteByIDs(ctx, []string{"a"}))
+	require.NoError(t, store.DeleteByFilter(ctx, "year < 2020"))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrMissingFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, map[string]any{}), ErrInvalidFilters)
+
+	assert.Equal(t, []string{
+		"POST /document/v1/langchaingo/langchaingo/docid/a",
+		"DELETE /document/v1/langchaingo/langchaingo/docid/a",
+		"POST /search/",
+		"DELETE /document/v1/langchaingo/langchaingo/docid/b",
+		"POST /search/",
+	}, requests)
+}
+
+func TestDeployApplicationPackage(t *testing.T) {
+	t.Parallel()
+	var files map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/application/v2/tenant/default/prepareandactivate", r.URL.Path)
+		assert.Equal(t, "application/zip", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		assert.NoError(t, err)
+		files = map[string]string{}
+		for _, f := range archive.File {
+			rc, err := f.Open()
+			assert.NoError(t, err)
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(content)
+		}
+	}))
+	defer server.Close()
+
+	pkg := ApplicationPackage("docs", 384)
+	require.NoError(t, DeployApplicationPackage(context.Background(), nil, server.URL+"/", pkg))
+	require.Len(t, files, 2)
+	assert.Contains(t, files["services.xml"], `<document type="docs" mode="index"/>`)
+	sd := files["schemas/docs.sd"]
+	assert.True(t, strings.HasPrefix(sd, "schema docs {"))
+	assert.Contains(t, sd, "field embedding type tensor<float>(x[384])")
+	assert.Contains(t, sd, "rank-profile semantic {")
+	assert.Contains(t, sd, "closeness(field, embedding)")
+}
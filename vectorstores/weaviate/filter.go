@@ -0,0 +1,88 @@
+package weaviate
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+// _whereOperators are the Weaviate operators of comparison filters.
+var _whereOperators = map[vectorstores.FilterOp]filters.WhereOperator{ //nolint:gochecknoglobals
+	vectorstores.FilterEq:  filters.Equal,
+	vectorstores.FilterNe:  filters.NotEqual,
+	vectorstores.FilterGt:  filters.GreaterThan,
+	vectorstores.FilterGte: filters.GreaterThanEqual,
+	vectorstores.FilterLt:  filters.LessThan,
+	vectorstores.FilterLte: filters.LessThanEqual,
+}
+
+// whereFilter translates a filter to a Weaviate where filter on the
+// properties of objects. Exists filters need the null state of the property
+// to be indexed.
+func whereFilter(f vectorstores.Filter) (*filters.WhereBuilder, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch f.Op { //nolint:exhaustive
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		operands := make([]*filters.WhereBuilder, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			where, err := whereFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, where)
+		}
+		op := filters.And
+		if f.Op == vectorstores.FilterOr {
+			op = filters.Or
+		}
+		return filters.Where().WithOperator(op).WithOperands(operands), nil
+	case vectorstores.FilterExists:
+		return filters.Where().WithPath([]string{f.Key}).WithOperator(filters.IsNull).WithValueBoolean(false), nil
+	case vectorstores.FilterIn:
+		operands := make([]*filters.WhereBuilder, 0, len(f.Values))
+		for _, value := range f.Values {
+			where, err := whereFilter(vectorstores.Eq(f.Key, value))
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, where)
+		}
+		return filters.Where().WithOperator(filters.Or).WithOperands(operands), nil
+	}
+
+	op, ok := _whereOperators[f.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported operator %q", vectorstores.ErrInvalidFilter, f.Op)
+	}
+	return withValue(filters.Where().WithPath([]string{f.Key}).WithOperator(op), f.Value)
+}
+
+// withValue sets the value of a where filter according to its type.
+func withValue(where *filters.WhereBuilder, value any) (*filters.WhereBuilder, error) {
+	switch v := value.(type) {
+	case string:
+		return where.WithValueText(v), nil
+	case bool:
+		return where.WithValueBoolean(v), nil
+	case time.Time:
+		return where.WithValueDate(v), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return where.WithValueInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return where.WithValueInt(int64(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return where.WithValueNumber(rv.Float()), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported value %v of type %T", vectorstores.ErrInvalidFilter, value, value)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	_defaultNameSpaceKey = "nameSpace"
 	_defaultTextKey      = "text"
 	_defaultNameSpace    = "default"
+	_defaultBatchSize    = 100
 )
 
 // ErrInvalidOptions is returned when the options given are invalid.
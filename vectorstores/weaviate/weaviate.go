@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-openapi/strfmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/tmc/langchaingo/vectorstores"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/auth"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
@@ -61,7 +63,11 @@ type Store struct {
 	additionalFields []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+	_ vectorstores.Upserter    = Store{}
+)
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -94,7 +100,6 @@ func (s Store) AddDocuments(ctx context.Context,
 	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
-	nameSpace := s.getNameSpace(opts)
 
 	docs = s.deduplicate(ctx, opts, docs)
 
@@ -104,6 +109,85 @@ func (s Store) AddDocuments(ctx context.Context,
 		return nil, nil
 	}
 
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.New().String()
+	}
+	if err := s.addDocuments(ctx, ids, docs, opts); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the
+// embedder and upserts the objects with the ids, which must be UUIDs, to the
+// weaviate index, replacing the objects with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.addDocuments(ctx, ids, docs, s.getOptions(options...))
+}
+
+// DeleteByIDs deletes the objects of the name space with the ids from the
+// weaviate index.
+func (s Store) DeleteByIDs(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	operands := make([]*filters.WhereBuilder, 0, len(ids))
+	for _, id := range ids {
+		operands = append(operands, filters.Where().WithPath([]string{"id"}).WithOperator(filters.Equal).WithValueText(id))
+	}
+	return s.deleteObjects(ctx, filters.Where().WithOperator(filters.Or).WithOperands(operands), options...)
+}
+
+// DeleteByFilter deletes the objects of the name space matched by the
+// filter, a *filters.WhereBuilder or a vectorstores.Filter, from the weaviate
+// index.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.deleteObjects(ctx, filter, options...)
+}
+
+// deleteObjects deletes the objects of the name space matched by the filter.
+func (s Store) deleteObjects(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), filter)
+	if err != nil {
+		return err
+	}
+	deleter := s.client.Batch().ObjectsBatchDeleter().
+		WithClassName(s.indexName).
+		WithWhere(whereBuilder)
+	if opts.Tenant != "" {
+		deleter = deleter.WithTenant(opts.Tenant)
+	}
+	_, err = deleter.Do(ctx)
+	return err
+}
+
+// addDocuments creates vector embeddings from the documents using the
+// embedder and upserts the objects with the ids to the weaviate index, in
+// batches of 100 objects by default.
+func (s Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	return vectorstores.RunBatches(ctx, opts, len(docs), _defaultBatchSize, func(ctx context.Context, start, end int) error {
+		return s.upsertObjects(ctx, ids[start:end], docs[start:end], opts)
+	})
+}
+
+func (s Store) upsertObjects(ctx context.Context, ids []string, docs []schema.Document, opts vectorstores.Options) error { //nolint:lll
+	nameSpace := s.getNameSpace(opts)
+
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -111,11 +195,18 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	vectors, err := opts.Embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
+	}
+	dimensions, err := s.classDimensions(ctx)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDimensions(vectors, dimensions); err != nil {
+		return err
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -131,21 +222,43 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	objects := make([]*models.Object, 0, len(docs))
-	ids := make([]string, len(docs))
 	for i := range docs {
-		id := strfmt.UUID(uuid.New().String())
-		ids[i] = id.String()
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         id,
+			ID:         strfmt.UUID(ids[i]),
 			Vector:     vectors[i],
 			Properties: metadatas[i],
+			Tenant:     opts.Tenant,
 		})
 	}
-	if _, err := s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx); err != nil {
-		return nil, err
+	_, err = s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx)
+	return err
+}
+
+// classDimensions returns the length of the vectors of the class of the
+// store, read from one of its objects since Weaviate doesn't store it in the
+// schema, or 0 if the class has no objects yet. The objects of multi-tenant
+// classes can't be listed without a tenant, which the client doesn't
+// support, so their dimensions are unknown too.
+func (s Store) classDimensions(ctx context.Context) (int, error) {
+	objects, err := s.client.Data().ObjectsGetter().
+		WithClassName(s.indexName).
+		WithVector().
+		WithLimit(1).
+		Do(ctx)
+	if err != nil {
+		var clientErr *fault.WeaviateClientError
+		if errors.As(err, &clientErr) && clientErr.IsUnexpectedStatusCode &&
+			clientErr.StatusCode < http.StatusInternalServerError {
+			// the class doesn't exist yet or is multi-tenant.
+			return 0, nil
+		}
+		return 0, err
 	}
-	return ids, nil
+	if len(objects) == 0 {
+		return 0, nil
+	}
+	return len(objects[0].Vector), nil
 }
 
 func (s Store) SimilaritySearch(
@@ -166,21 +279,22 @@ func (s Store) SimilaritySearch(
 		return nil, err
 	}
 
+	if opts.HybridSearch != nil {
+		get := s.get(opts, whereBuilder, numDocuments)
+		return s.hybridSearch(ctx, get, query, *opts.HybridSearch, opts.Embedder, scoreThreshold)
+	}
+
 	vector, err := opts.Embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := s.client.GraphQL().
-		Get().
+	res, err := s.get(opts, whereBuilder, numDocuments).
 		WithNearVector(s.client.GraphQL().
 			NearVectorArgBuilder().
 			WithVector(vector).
-			WithCertainty(scoreThreshold),
+			WithCertainty(certainty(scoreThreshold)),
 		).
-		WithWhere(whereBuilder).
-		WithClassName(s.indexName).
-		WithLimit(numDocuments).
 		WithFields(s.createFields()...).Do(ctx)
 	if err != nil {
 		return nil, err
@@ -188,8 +302,67 @@ func (s Store) SimilaritySearch(
 	return s.parseDocumentsByGraphQLResponse(res)
 }
 
+// hybridSearch searches weaviate with both the vector and BM25 scoring of the
+// text, which defaults to the query. Weaviate fuses the results with ranked
+// fusion, or relative score fusion weighted by Alpha for the weighted fusion.
+// Sparse vectors are ignored.
+func (s Store) hybridSearch(
+	ctx context.Context,
+	get *graphql.GetBuilder,
+	query string,
+	hybrid vectorstores.HybridSearch,
+	embedder embeddings.Embedder,
+	scoreThreshold float32,
+) ([]schema.Document, error) {
+	if err := hybrid.Validate(); err != nil {
+		return nil, err
+	}
+
+	vector := hybrid.Vector
+	if vector == nil {
+		var err error
+		vector, err = embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	text := hybrid.Text
+	if text == "" {
+		text = query
+	}
+
+	arguments := s.client.GraphQL().HybridArgumentBuilder().
+		WithQuery(text).
+		WithVector(vector).
+		WithFusionType(graphql.Ranked).
+		WithAlpha(0.5) //nolint:mnd
+	if hybrid.Fusion == vectorstores.FusionWeighted {
+		arguments = arguments.WithFusionType(graphql.RelativeScore).WithAlpha(hybrid.Alpha)
+	}
+
+	res, err := get.
+		WithHybrid(arguments).
+		WithFields(s.createHybridFields()...).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.parseDocumentsByGraphQLResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Score >= scoreThreshold {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered, nil
+}
+
 // MetadataSearch searches weaviate based on metadata rather than based on similarity.
-// Use `vectorstores.WithFilter(*filters.WhereBuilder)` to provide a where condition
+// Use `vectorstores.WithFilters(*filters.WhereBuilder)` or
+// `vectorstores.WithFilter(vectorstores.Filter)` to provide a where condition
 // as an option.
 func (s Store) MetadataSearch(
 	ctx context.Context,
@@ -203,11 +376,7 @@ func (s Store) MetadataSearch(
 	if err != nil {
 		return nil, err
 	}
-	res, err := s.client.GraphQL().
-		Get().
-		WithWhere(whereBuilder).
-		WithClassName(s.indexName).
-		WithLimit(numDocuments).
+	res, err := s.get(opts, whereBuilder, numDocuments).
 		WithFields(s.createFields()...).
 		Do(ctx)
 	if err != nil {
@@ -217,6 +386,80 @@ func (s Store) MetadataSearch(
 	return s.parseDocumentsByGraphQLResponse(res)
 }
 
+// BM25Search searches weaviate with the BM25 keyword scoring of the text of
+// the documents, without vectors. The score of the documents is their BM25
+// score, which isn't normalized, so the score threshold is ignored.
+func (s Store) BM25Search(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), s.getFilters(opts))
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.get(opts, whereBuilder, numDocuments).
+		WithBM25(s.client.GraphQL().
+			Bm25ArgBuilder().
+			WithQuery(query).
+			WithProperties(s.textKey),
+		).
+		WithFields(s.createHybridFields()...).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseDocumentsByGraphQLResponse(res)
+}
+
+// NearTextSearch searches weaviate for the documents most similar to the
+// query, vectorized by the vectorizer module of the class, e.g.
+// text2vec-openai, instead of the embedder of the store.
+func (s Store) NearTextSearch(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	scoreThreshold, err := s.getScoreThreshold(opts)
+	if err != nil {
+		return nil, err
+	}
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), s.getFilters(opts))
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.get(opts, whereBuilder, numDocuments).
+		WithNearText(s.client.GraphQL().
+			NearTextArgBuilder().
+			WithConcepts([]string{query}).
+			WithCertainty(certainty(scoreThreshold)),
+		).
+		WithFields(s.createFields()...).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseDocumentsByGraphQLResponse(res)
+}
+
+// get returns a query of the objects of the class and tenant matched by
+// the where filter.
+func (s Store) get(opts vectorstores.Options, whereBuilder *filters.WhereBuilder, numDocuments int) *graphql.GetBuilder {
+	get := s.client.GraphQL().
+		Get().
+		WithWhere(whereBuilder).
+		WithClassName(s.indexName).
+		WithLimit(numDocuments)
+	if opts.Tenant != "" {
+		get = get.WithTenant(opts.Tenant)
+	}
+	return get
+}
+
 //nolint:cyclop
 func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]schema.Document, error) {
 	if len(res.Errors) > 0 {
@@ -247,7 +490,13 @@ func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]s
 		}
 		var score float64
 		if additional, ok := itemMap["_additional"].(map[string]any); ok {
-			score, _ = additional["certainty"].(float64)
+			if certainty, ok := additional["certainty"].(float64); ok {
+				score = float64(vectorstores.CosineSimilarityScore(2*certainty - 1)) //nolint:mnd
+			}
+			// the score of hybrid searches is a string.
+			if fused, ok := additional["score"].(string); ok {
+				score, _ = strconv.ParseFloat(fused, 64)
+			}
 		}
 		delete(itemMap, s.textKey)
 		doc := schema.Document{
@@ -285,6 +534,15 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.nameSpace
 }
 
+// certainty converts a score threshold to the certainty of near vector and
+// near text searches, (1 + cosine similarity) / 2.
+func certainty(scoreThreshold float32) float32 {
+	if scoreThreshold == 0 {
+		return 0
+	}
+	return (1 + scoreThreshold) / 2 //nolint:mnd
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, ErrInvalidScoreThreshold
@@ -316,13 +574,21 @@ func (s Store) createWhereBuilder(namespace string, filter any) (*filters.WhereB
 		return filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(namespace), nil
 	}
 
-	whereFilter, ok := filter.(*filters.WhereBuilder)
-	if !ok {
+	var where *filters.WhereBuilder
+	switch filter := filter.(type) {
+	case *filters.WhereBuilder:
+		where = filter
+	case vectorstores.Filter:
+		var err error
+		if where, err = whereFilter(filter); err != nil {
+			return nil, err
+		}
+	default:
 		return nil, ErrInvalidFilter
 	}
 	return filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
 		filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(namespace),
-		whereFilter,
+		where,
 	}), nil
 }
 
@@ -348,3 +614,18 @@ func (s Store) createFields() []graphql.Field {
 
 	return fields
 }
+
+// createHybridFields returns the fields of hybrid searches, which have a
+// score instead of a certainty.
+func (s Store) createHybridFields() []graphql.Field {
+	fields := s.createFields()
+	additional := &fields[len(fields)-1]
+	additionalFields := make([]graphql.Field, 0, len(additional.Fields))
+	for _, field := range additional.Fields {
+		if field.Name != "certainty" && field.Name != "score" {
+			additionalFields = append(additionalFields, field)
+		}
+	}
+	additional.Fields = append(additionalFields, graphql.Field{Name: "score"})
+	return fields
+}
@@ -108,6 +108,53 @@ func TestWeaviateStoreRest(t *testing.T) {
 	require.Len(t, docs, 1)
 	require.Equal(t, "tokyo", docs[0].PageContent)
 	require.Equal(t, "japan", docs[0].Metadata["country"])
+
+	short, err := embeddings.NewEmbedder(embeddings.EmbedderClientFunc(
+		func(_ context.Context, texts []string) ([][]float32, error) {
+			return make([][]float32, len(texts)), nil
+		}))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(context.Background(), []schema.Document{{PageContent: "kyoto"}},
+		vectorstores.WithEmbedder(short))
+	require.ErrorIs(t, err, vectorstores.ErrDimensionMismatch)
+}
+
+func TestWeaviateStoreBM25Search(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+
+	llm, err := openai.New()
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+		WithQueryAttrs([]string{"country"}),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(context.Background(), store)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo is the capital of japan", Metadata: map[string]any{
+			"country": "japan",
+		}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.BM25Search(context.Background(), "capital", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo is the capital of japan", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
 
 func TestWeaviateStoreRestWithScoreThreshold(t *testing.T) {
@@ -149,7 +196,7 @@ func TestWeaviateStoreRestWithScoreThreshold(t *testing.T) {
 	// test with a score threshold of 0.8, expected 6 documents
 	docs, err := store.SimilaritySearch(context.Background(),
 		"Which of these are cities in Japan", 10,
-		vectorstores.WithScoreThreshold(0.9))
+		vectorstores.WithScoreThreshold(0.8))
 	require.NoError(t, err)
 	require.Len(t, docs, 6)
 
@@ -383,7 +430,7 @@ func TestWeaviateAsRetrieverWithScoreThreshold(t *testing.T) {
 		chains.NewRetrievalQAFromLLM(
 			llm,
 			vectorstores.ToRetriever(store, 5, vectorstores.WithNameSpace(
-				nameSpace), vectorstores.WithScoreThreshold(0.8)),
+				nameSpace), vectorstores.WithScoreThreshold(0.6)),
 		),
 		"What colors is each piece of furniture next to the desk?",
 	)
@@ -682,7 +729,7 @@ func TestWeaviateStoreAdditionalFieldsDefaults(t *testing.T) {
 	require.Len(t, additional, 1)
 
 	certainty, _ := additional["certainty"].(float64)
-	require.InDelta(t, docs[0].Score, float32(certainty), 0, "expect score to be equal to the certainty")
+	require.InDelta(t, 2*certainty-1, docs[0].Score, 1e-6, "expect score to be the cosine similarity of the certainty")
 }
 
 func TestWeaviateStoreAdditionalFieldsAdded(t *testing.T) {